@@ -101,7 +101,21 @@ CREATE OR REPLACE PACKAGE OMNI_TRACER_API AS
     -- Core Methods
     PROCEDURE Initialize;
     PROCEDURE Trace_Message(message_ IN CLOB, log_level_ IN VARCHAR2 DEFAULT 'INFO');
-    PROCEDURE Trace_Message_To_Webhook(message_ IN CLOB, log_level_ IN VARCHAR2 DEFAULT 'INFO');
+    PROCEDURE Trace_Message_To_Webhook(message_ IN CLOB, log_level_ IN VARCHAR2 DEFAULT 'INFO', target_ IN VARCHAR2 DEFAULT NULL);
+    PROCEDURE Trace_Message_With_Props(message_ IN CLOB, props_ IN CLOB, log_level_ IN VARCHAR2 DEFAULT 'INFO');
+
+    -- Timed Spans
+    FUNCTION Trace_Begin(
+        span_name_      IN VARCHAR2,
+        parent_span_id_ IN VARCHAR2 DEFAULT NULL,
+        log_level_      IN VARCHAR2 DEFAULT 'INFO',
+        process_name_   IN VARCHAR2 DEFAULT NULL
+    ) RETURN VARCHAR2;
+    PROCEDURE Trace_End(
+        span_id_   IN VARCHAR2,
+        message_   IN CLOB DEFAULT NULL,
+        log_level_ IN VARCHAR2 DEFAULT 'INFO'
+    );
     PROCEDURE Dequeue_Array_Events(
         subscriber_name_ IN  VARCHAR2,
         batch_size_      IN  INTEGER,
@@ -127,6 +141,14 @@ CREATE OR REPLACE PACKAGE BODY OMNI_TRACER_API AS
     -- Forward declarations for private functions
     FUNCTION Clob_To_Blob___(input_ IN CLOB) RETURN BLOB;
 
+    -- Spans opened by Trace_Begin in this session and not yet closed by Trace_End
+    TYPE Open_Span_Rec IS RECORD (
+        span_name_     VARCHAR2(200),
+        process_name_  VARCHAR2(100)
+    );
+    TYPE Open_Span_Tab IS TABLE OF Open_Span_Rec INDEX BY VARCHAR2(32);
+    open_spans_ Open_Span_Tab;
+
     PROCEDURE Initialize IS
         PRAGMA AUTONOMOUS_TRANSACTION;
         queue_exists_ NUMBER;
@@ -343,22 +365,170 @@ CREATE OR REPLACE PACKAGE BODY OMNI_TRACER_API AS
 
 
     -- @DOC: Trace_Message_To_Webhook
-    -- Traces a message and signals the Go client to forward it to the configured webhook URL.
-    -- The webhook URL is stored in BoltDB on the Go client side.
+    -- Traces a message and signals the Go client to forward it to a webhook. target_ names
+    -- the webhook, e.g. target_ => 'OPS'; when it is NULL the default webhook is used.
+    -- The webhook URLs are stored in BoltDB on the Go client side.
     PROCEDURE Trace_Message_To_Webhook (
         message_    IN CLOB,
-        log_level_  IN VARCHAR2 DEFAULT 'INFO')
+        log_level_  IN VARCHAR2 DEFAULT 'INFO',
+        target_     IN VARCHAR2 DEFAULT NULL)
     IS
-        calling_process_ VARCHAR2(100);
+        calling_process_  VARCHAR2(100);
+        props_            JSON_OBJECT_T;
+        additional_props_ CLOB;
     BEGIN
         calling_process_ := 'OMNI_TRACER_API';
+
+        props_ := JSON_OBJECT_T();
+        props_.PUT('SEND_TO_WEBHOOK', 'TRUE');
+        IF target_ IS NOT NULL THEN
+            props_.PUT('WEBHOOK_TARGET', SUBSTR(target_, 1, 64));
+        END IF;
+        additional_props_ := props_.TO_CLOB();
+
         Enqueue_Event___(
             process_name_       => calling_process_,
             log_level_          => log_level_,
             payload_            => message_,
-            additional_props_   => '{"SEND_TO_WEBHOOK":"TRUE"}'
+            additional_props_   => additional_props_
         );
+
+        IF additional_props_ IS NOT NULL AND DBMS_LOB.ISTEMPORARY(additional_props_) = 1 THEN
+            DBMS_LOB.FREETEMPORARY(additional_props_);
+        END IF;
     END Trace_Message_To_Webhook;
+
+
+    -- @DOC: Trace_Message_With_Props
+    -- Traces a message with structured attributes, e.g. '{"order_id": 4711, "tenant": "ACME"}'.
+    -- props_ must be a JSON object. It is sent as the ATTRIBUTES member, so attribute
+    -- names can never overwrite the standard message fields.
+    PROCEDURE Trace_Message_With_Props (
+        message_    IN CLOB,
+        props_      IN CLOB,
+        log_level_  IN VARCHAR2 DEFAULT 'INFO')
+    IS
+        calling_process_  VARCHAR2(100);
+        wrapper_          JSON_OBJECT_T;
+        additional_props_ CLOB;
+    BEGIN
+        calling_process_ := 'OMNI_TRACER_API';
+
+        IF props_ IS NOT NULL AND DBMS_LOB.GETLENGTH(props_) > 0 THEN
+            wrapper_ := JSON_OBJECT_T();
+            wrapper_.PUT('ATTRIBUTES', JSON_OBJECT_T.parse(props_));
+            additional_props_ := wrapper_.TO_CLOB();
+        END IF;
+
+        Enqueue_Event___(
+            process_name_       => calling_process_,
+            log_level_          => log_level_,
+            payload_            => message_,
+            additional_props_   => additional_props_
+        );
+
+        IF additional_props_ IS NOT NULL AND DBMS_LOB.ISTEMPORARY(additional_props_) = 1 THEN
+            DBMS_LOB.FREETEMPORARY(additional_props_);
+        END IF;
+    END Trace_Message_With_Props;
+
+
+    -- @DOC: Trace_Begin
+    -- Opens a named span and returns its span ID. Pass the ID to Trace_End to close the
+    -- span, and as parent_span_id_ of nested spans. OmniView pairs the begin and end
+    -- messages by span ID to show durations and a waterfall per process.
+    FUNCTION Trace_Begin (
+        span_name_      IN VARCHAR2,
+        parent_span_id_ IN VARCHAR2 DEFAULT NULL,
+        log_level_      IN VARCHAR2 DEFAULT 'INFO',
+        process_name_   IN VARCHAR2 DEFAULT NULL) RETURN VARCHAR2
+    IS
+        span_id_          VARCHAR2(32);
+        span_             JSON_OBJECT_T;
+        wrapper_          JSON_OBJECT_T;
+        additional_props_ CLOB;
+        open_span_        Open_Span_Rec;
+    BEGIN
+        IF span_name_ IS NULL THEN
+            RAISE_APPLICATION_ERROR(-20003, 'Span name cannot be NULL or empty');
+        END IF;
+
+        span_id_ := RAWTOHEX(SYS_GUID());
+        open_span_.span_name_    := SUBSTR(span_name_, 1, 200);
+        open_span_.process_name_ := process_name_;
+
+        span_ := JSON_OBJECT_T();
+        span_.PUT('ID', span_id_);
+        span_.PUT('NAME', open_span_.span_name_);
+        span_.PUT('EVENT', 'BEGIN');
+        IF parent_span_id_ IS NOT NULL THEN
+            span_.PUT('PARENT_ID', parent_span_id_);
+        END IF;
+        wrapper_ := JSON_OBJECT_T();
+        wrapper_.PUT('SPAN', span_);
+        additional_props_ := wrapper_.TO_CLOB();
+
+        Enqueue_Event___(
+            process_name_       => process_name_,
+            log_level_          => log_level_,
+            payload_            => 'Begin ' || open_span_.span_name_,
+            additional_props_   => additional_props_
+        );
+        open_spans_(span_id_) := open_span_;
+
+        IF additional_props_ IS NOT NULL AND DBMS_LOB.ISTEMPORARY(additional_props_) = 1 THEN
+            DBMS_LOB.FREETEMPORARY(additional_props_);
+        END IF;
+        RETURN span_id_;
+    END Trace_Begin;
+
+
+    -- @DOC: Trace_End
+    -- Closes a span opened by Trace_Begin. message_ defaults to 'End <span name>'.
+    PROCEDURE Trace_End (
+        span_id_   IN VARCHAR2,
+        message_   IN CLOB DEFAULT NULL,
+        log_level_ IN VARCHAR2 DEFAULT 'INFO')
+    IS
+        span_             JSON_OBJECT_T;
+        wrapper_          JSON_OBJECT_T;
+        additional_props_ CLOB;
+        open_span_        Open_Span_Rec;
+    BEGIN
+        IF span_id_ IS NULL THEN
+            RAISE_APPLICATION_ERROR(-20004, 'Span ID cannot be NULL or empty');
+        END IF;
+
+        IF open_spans_.EXISTS(span_id_) THEN
+            open_span_ := open_spans_(span_id_);
+        END IF;
+
+        span_ := JSON_OBJECT_T();
+        span_.PUT('ID', span_id_);
+        span_.PUT('EVENT', 'END');
+        IF open_span_.span_name_ IS NOT NULL THEN
+            span_.PUT('NAME', open_span_.span_name_);
+        END IF;
+        wrapper_ := JSON_OBJECT_T();
+        wrapper_.PUT('SPAN', span_);
+        additional_props_ := wrapper_.TO_CLOB();
+
+        Enqueue_Event___(
+            process_name_       => open_span_.process_name_,
+            log_level_          => log_level_,
+            payload_            => NVL(message_, 'End ' || NVL(open_span_.span_name_, span_id_)),
+            additional_props_   => additional_props_
+        );
+        IF open_spans_.EXISTS(span_id_) THEN
+            open_spans_.DELETE(span_id_);
+        END IF;
+
+        IF additional_props_ IS NOT NULL AND DBMS_LOB.ISTEMPORARY(additional_props_) = 1 THEN
+            DBMS_LOB.FREETEMPORARY(additional_props_);
+        END IF;
+    END Trace_End;
+
+
     PROCEDURE Dequeue_Array_Events(
         subscriber_name_ IN  VARCHAR2,
         batch_size_      IN  INTEGER,
@@ -1,25 +1,42 @@
 package main
 
 import (
+	"OmniView/internal/adapter/cli"
+	"OmniView/internal/adapter/control"
 	"OmniView/internal/adapter/logger"
+	"OmniView/internal/adapter/metrics"
 	"OmniView/internal/adapter/security/credcipher"
+	"OmniView/internal/adapter/sink"
 	"OmniView/internal/adapter/storage/boltdb"
 	"OmniView/internal/adapter/storage/oracle"
+	"OmniView/internal/adapter/stream"
 	"OmniView/internal/adapter/ui"
 	"OmniView/internal/app"
 	"OmniView/internal/core/domain"
 	"OmniView/internal/core/ports"
+	"OmniView/internal/service/sinks"
 	"OmniView/internal/service/tracer"
 	updaterSvc "OmniView/internal/service/updater"
 	"OmniView/internal/updater"
+	"context"
 	"errors"
 	"fmt"
 	"os"
+	"os/signal"
 	"runtime"
+	"syscall"
+)
+
+const (
+	boltDBPath = "omniview.bolt"
+	keyPath    = "omniview.key"
 )
 
 func main() {
 	omniApp := app.New()
+	if len(os.Args) > 1 && cli.IsCommand(os.Args[1]) {
+		os.Exit(runCommand(omniApp, os.Args[1:]))
+	}
 	if err := run(omniApp); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
@@ -41,55 +58,55 @@ func run(omniApp *app.App) error {
 
 	updater.CleanupOldBinary()
 
-	// Enable at-rest encryption for credentials persisted in BoltDB. The master key
-	// is stored in a 0600 file alongside the database and generated on first run.
-	const boltDBPath = "omniview.bolt"
-	const keyPath = "omniview.key"
-	if _, err := os.Stat(boltDBPath); err == nil {
-		if _, keyErr := os.Stat(keyPath); keyErr != nil {
-			if errors.Is(keyErr, os.ErrNotExist) {
-				// To provide zero-friction upgrades for existing customers, we only block
-				// startup if the database actively contains encrypted credentials that need
-				// a missing key. If no credentials are encrypted yet (i.e. legacy plaintext),
-				// we let database initialization automatically generate a new key file.
-				tempBA, err := boltdb.NewBoltAdapter(boltDBPath)
-				if err != nil {
-					return fmt.Errorf("could not open BoltDB at %q to scan for encrypted credentials (key file: %q): %w", boltDBPath, keyPath, err)
-				}
-				hasEncrypted, scanErr := tempBA.HasEncryptedCredentials()
-				if scanErr != nil {
-					return fmt.Errorf("BoltDB encrypted-credentials scan failed on file %q (key file: %q): %w", boltDBPath, keyPath, scanErr)
-				}
-				if hasEncrypted {
-					return fmt.Errorf("credential key %q is missing while %q already exists and contains encrypted credentials; restore the original key file or remove %q and reconfigure, otherwise stored credentials cannot be decrypted", keyPath, boltDBPath, boltDBPath)
-				}
-			} else {
-				return fmt.Errorf("failed to stat credential key %s: %w", keyPath, keyErr)
-			}
-		}
-	} else if !errors.Is(err, os.ErrNotExist) {
-		return fmt.Errorf("failed to stat BoltDB file %s: %w", boltDBPath, err)
+	boltAdapter, err := openBoltDB()
+	if err != nil {
+		return err
 	}
+	defer boltAdapter.Close()
 
-	credCipher, err := credcipher.New(credcipher.NewFileKeyProvider(keyPath))
-	if err != nil {
-		return fmt.Errorf("failed to initialise credential cipher: %w", err)
+	// ==========================================
+	// Phase 2: Initialize Services
+	// ==========================================
+
+	// Opt-in Prometheus endpoint; the TUI has no flags, so it follows the environment
+	if addr := os.Getenv(cli.MetricsAddrEnv); addr != "" {
+		metricsServer, err := metrics.Listen(addr, metrics.Default())
+		if err != nil {
+			return err
+		}
+		defer metricsServer.Close()
 	}
-	domain.SetCredentialCipher(credCipher)
 
-	// Initialize BoltDB
-	boltAdapter, err := boltdb.NewBoltAdapter(boltDBPath)
+	// Output sinks, webhooks and OpenTelemetry export included, and their
+	// routing rules are edited in the settings panel, which reloads the
+	// router after every change
+	sinkRegistry := sinks.NewRegistry()
+	sink.Register(sinkRegistry, boltAdapter)
+	sinkRouter, err := sinks.NewRouter(sinkRegistry, boltdb.NewSinkRepository(boltAdapter))
 	if err != nil {
-		return fmt.Errorf("failed to create BoltDB adapter: %w", err)
+		return err
 	}
-	if err := boltAdapter.Initialize(); err != nil {
-		return fmt.Errorf("failed to initialize BoltDB: %w", err)
+	if err := sinkRouter.Reload(context.Background()); err != nil {
+		logger.Warn("failed to load output sinks, routing only webhook messages", "error", err)
+		sinkRouter.ApplyDefaultRoute()
 	}
-	defer boltAdapter.Close()
+	defer sinkRouter.Close()
 
-	// ==========================================
-	// Phase 2: Initialize Services
-	// ==========================================
+	// Opt-in SSE and WebSocket re-broadcast beside the TUI
+	if addr := os.Getenv(cli.StreamAddrEnv); addr != "" {
+		hub := stream.NewHub()
+		streamServer, err := stream.Listen(addr, hub)
+		if err != nil {
+			return err
+		}
+		defer streamServer.Close()
+		sinkRouter.Tap(hub)
+	}
+
+	// Webhook deliveries that fail are kept in BoltDB; those interrupted by
+	// the last shutdown are sent now
+	deadLetters := boltdb.NewDeadLetterRepository(boltAdapter)
+	resumeWebhookDeliveries(deadLetters, boltAdapter)
 
 	eventCh := make(chan *domain.QueueMessage, 100)
 	updaterService := updaterSvc.NewUpdaterService(omniApp.GetVersion())
@@ -98,17 +115,23 @@ func run(omniApp *app.App) error {
 
 	dbSettingsRepo := boltdb.NewDatabaseSettingsRepository(boltAdapter)
 
+	historyRetention, err := boltAdapter.GetHistoryRetention()
+	if err != nil {
+		logger.Warn("failed to load history retention, using defaults", "error", err)
+	}
+	historyRepo := boltdb.NewTraceHistoryRepository(boltAdapter, historyRetention)
+	if err := historyRepo.Prune(context.Background()); err != nil {
+		logger.Warn("failed to prune trace history", "error", err)
+	}
+
 	model, err := ui.NewModel(ui.ModelOpts{
-		App:         omniApp,
-		BoltAdapter: boltAdapter,
-		DBFactory: func(settings *domain.DatabaseSettings) (ports.DatabaseRepository, error) {
-			adapter := oracle.NewOracleAdapter(settings)
-			if adapter == nil {
-				return nil, fmt.Errorf("failed to create oracle adapter: nil settings")
-			}
-			return adapter, nil
-		},
+		App:            omniApp,
+		BoltAdapter:    boltAdapter,
+		DBFactory:      newOracleAdapter,
 		DBSettingsRepo: dbSettingsRepo,
+		HistoryRepo:    historyRepo,
+		SinkRouter:     sinkRouter,
+		DeadLetters:    deadLetters,
 		EventChannel:   eventCh,
 		UpdaterService: updaterService,
 	})
@@ -129,11 +152,129 @@ func run(omniApp *app.App) error {
 	}
 
 	p := ui.NewProgram(model)
+
+	// Opt-in JSON-RPC control socket for scripts and editor integrations
+	if path := os.Getenv(cli.ControlSocketEnv); path != "" {
+		controlServer, err := control.Listen(path, ui.NewControlHandler(p))
+		if err != nil {
+			return err
+		}
+		defer controlServer.Close()
+	}
+
+	// The router closes first so webhook sinks hand the dispatcher what they hold
 	if _, err := p.Run(); err != nil {
+		sinkRouter.Close()
 		tracer.StopWebhookDispatcher()
 		return fmt.Errorf("TUI error: %w", err)
 	}
 
+	sinkRouter.Close()
 	tracer.StopWebhookDispatcher()
 	return nil
 }
+
+// openBoltDB opens the local configuration store with credential encryption enabled.
+func openBoltDB() (*boltdb.BoltAdapter, error) {
+	// Enable at-rest encryption for credentials persisted in BoltDB. The master key
+	// is stored in a 0600 file alongside the database and generated on first run.
+	if _, err := os.Stat(boltDBPath); err == nil {
+		if _, keyErr := os.Stat(keyPath); keyErr != nil {
+			if errors.Is(keyErr, os.ErrNotExist) {
+				// To provide zero-friction upgrades for existing customers, we only block
+				// startup if the database actively contains encrypted credentials that need
+				// a missing key. If no credentials are encrypted yet (i.e. legacy plaintext),
+				// we let database initialization automatically generate a new key file.
+				tempBA, err := boltdb.NewBoltAdapter(boltDBPath)
+				if err != nil {
+					return nil, fmt.Errorf("could not open BoltDB at %q to scan for encrypted credentials (key file: %q): %w", boltDBPath, keyPath, err)
+				}
+				hasEncrypted, scanErr := tempBA.HasEncryptedCredentials()
+				if scanErr != nil {
+					return nil, fmt.Errorf("BoltDB encrypted-credentials scan failed on file %q (key file: %q): %w", boltDBPath, keyPath, scanErr)
+				}
+				if hasEncrypted {
+					return nil, fmt.Errorf("credential key %q is missing while %q already exists and contains encrypted credentials; restore the original key file or remove %q and reconfigure, otherwise stored credentials cannot be decrypted", keyPath, boltDBPath, boltDBPath)
+				}
+			} else {
+				return nil, fmt.Errorf("failed to stat credential key %s: %w", keyPath, keyErr)
+			}
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to stat BoltDB file %s: %w", boltDBPath, err)
+	}
+
+	credCipher, err := credcipher.New(credcipher.NewFileKeyProvider(keyPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialise credential cipher: %w", err)
+	}
+	domain.SetCredentialCipher(credCipher)
+
+	// Initialize BoltDB
+	boltAdapter, err := boltdb.NewBoltAdapter(boltDBPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create BoltDB adapter: %w", err)
+	}
+	if err := boltAdapter.Initialize(); err != nil {
+		return nil, fmt.Errorf("failed to initialize BoltDB: %w", err)
+	}
+	return boltAdapter, nil
+}
+
+// resumeWebhookDeliveries has the webhook dispatcher save failed deliveries
+// to deadLetters and queues those left over from the last shutdown.
+func resumeWebhookDeliveries(deadLetters *boltdb.DeadLetterRepository, configs ports.ConfigRepository) {
+	tracer.SetWebhookDeadLetterRepository(deadLetters)
+	resumed, err := tracer.ResumeWebhookDeliveries(context.Background(), configs)
+	if err != nil {
+		logger.Warn("failed to resume interrupted webhook deliveries", "error", err)
+	}
+	if resumed > 0 {
+		logger.Info("resumed interrupted webhook deliveries", "count", resumed)
+	}
+}
+
+// runCommand runs a headless subcommand and returns the process exit code.
+func runCommand(omniApp *app.App, args []string) int {
+	closeLog, err := logger.Init("omniview.log")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to initialise logger: %v\n", err)
+		return cli.ExitFailure
+	}
+	defer closeLog()
+	logger.Info("OmniInspect command starting", "version", omniApp.GetVersion(), "command", args[0])
+
+	boltAdapter, err := openBoltDB()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return cli.ExitFailure
+	}
+	defer boltAdapter.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err = cli.Run(ctx, cli.Deps{
+		Bolt:      boltAdapter,
+		Settings:  boltdb.NewDatabaseSettingsRepository(boltAdapter),
+		DBFactory: newOracleAdapter,
+		Stdin:     os.Stdin,
+		Stdout:    os.Stdout,
+		Stderr:    os.Stderr,
+		Getenv:    os.Getenv,
+	}, args)
+	if err != nil {
+		logger.Error("command failed", "command", args[0], "error", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+	return cli.ExitCode(err)
+}
+
+// newOracleAdapter creates the database adapter for a stored profile.
+func newOracleAdapter(settings *domain.DatabaseSettings) (ports.DatabaseRepository, error) {
+	adapter := oracle.NewOracleAdapter(settings)
+	if adapter == nil {
+		return nil, fmt.Errorf("failed to create oracle adapter: nil settings")
+	}
+	return adapter, nil
+}
@@ -0,0 +1,196 @@
+package cli
+
+// ==========================================
+// Command Line Adapter
+// ==========================================
+// Headless subcommands of the omniview binary. They share the BoltDB
+// profiles and the tracer services with the TUI but never start Bubble Tea,
+// so their output can be piped into other tools and their exit status
+// checked by scripts and CI jobs.
+
+import (
+	"OmniView/internal/adapter/storage/boltdb"
+	"OmniView/internal/core/domain"
+	"OmniView/internal/core/ports"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ==========================================
+// Exit Codes
+// ==========================================
+
+const (
+	ExitOK         = 0 // Finished, or stopped by an interrupt
+	ExitFailure    = 1 // Any error without a more specific code
+	ExitUsage      = 2 // Unknown command or invalid flags
+	ExitConfig     = 3 // The database profile is missing or already exists, or no default is set
+	ExitConnection = 4 // The database could not be reached or the session was lost
+	ExitSetup      = 5 // Permission check, tracer deployment or subscriber registration failed
+)
+
+// ExitError attaches a process exit code to an error.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string { return e.Err.Error() }
+
+func (e *ExitError) Unwrap() error { return e.Err }
+
+// exitError wraps err with code, leaving nil untouched.
+func exitError(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ExitError{Code: code, Err: err}
+}
+
+// ExitCode returns the exit code for an error returned by Run.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	if domain.IsConnectionLost(err) {
+		return ExitConnection
+	}
+	return ExitFailure
+}
+
+// MetricsAddrEnv names the environment variable that enables the Prometheus
+// endpoint when no --metrics-addr flag is given.
+const MetricsAddrEnv = "OMNIVIEW_METRICS_ADDR"
+
+// StreamAddrEnv names the environment variable that enables the SSE and
+// WebSocket trace stream when no --stream-addr flag is given.
+const StreamAddrEnv = "OMNIVIEW_STREAM_ADDR"
+
+// ControlSocketEnv names the environment variable holding the Unix socket
+// path on which the TUI accepts JSON-RPC control calls.
+const ControlSocketEnv = "OMNIVIEW_CONTROL_SOCKET"
+
+// ==========================================
+// Dispatch
+// ==========================================
+
+// Deps are the shared stores and factories the subcommands run against.
+type Deps struct {
+	Bolt      *boltdb.BoltAdapter
+	Settings  ports.DatabaseSettingsRepository
+	DBFactory func(settings *domain.DatabaseSettings) (ports.DatabaseRepository, error)
+	Stdin     io.Reader
+	Stdout    io.Writer
+	Stderr    io.Writer
+	Getenv    func(key string) string
+}
+
+// command is one subcommand of the omniview binary.
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, deps Deps, args []string) error
+}
+
+// commands lists the subcommands in the order the usage text shows them.
+func commands() []command {
+	return []command{
+		{name: "tail", summary: "Stream trace messages to stdout", run: runTail},
+		{name: "db", summary: "List, add, edit, remove and test database profiles", run: runDB},
+	}
+}
+
+// IsCommand reports whether name is a subcommand, so the caller can tell a
+// headless invocation from a TUI launch.
+func IsCommand(name string) bool {
+	if name == "help" || name == "-h" || name == "--help" {
+		return true
+	}
+	for _, cmd := range commands() {
+		if cmd.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Run executes the subcommand named by args[0]. Use ExitCode to turn the
+// returned error into the process exit status.
+func Run(ctx context.Context, deps Deps, args []string) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(deps.Stdout)
+		return nil
+	}
+	for _, cmd := range commands() {
+		if cmd.name == args[0] {
+			return cmd.run(ctx, deps, args[1:])
+		}
+	}
+	printUsage(deps.Stderr)
+	return exitError(ExitUsage, fmt.Errorf("unknown command %q", args[0]))
+}
+
+// printUsage lists the subcommands.
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: omniview [command] [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Without a command, omniview starts the terminal UI.")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands() {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "omniview <command> -h" for the flags of a command.`)
+}
+
+// parseFlags parses args into fs, mapping flag errors to ExitUsage. It
+// reports done when -h was requested and the usage has been printed.
+func parseFlags(fs *flag.FlagSet, args []string) (done bool, err error) {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return true, nil
+		}
+		return true, exitError(ExitUsage, err)
+	}
+	if fs.NArg() > 0 {
+		return true, exitError(ExitUsage, fmt.Errorf("unexpected argument %q", fs.Arg(0)))
+	}
+	return false, nil
+}
+
+// parseFlagsWithID parses a command that takes one database ID argument,
+// accepted before or after the flags. required reports a missing ID as a
+// usage error.
+func parseFlagsWithID(fs *flag.FlagSet, args []string, required bool) (id string, done bool, err error) {
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		id, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return "", true, nil
+		}
+		return "", true, exitError(ExitUsage, err)
+	}
+	if id == "" && fs.NArg() > 0 {
+		id = fs.Arg(0)
+		if fs.NArg() > 1 {
+			return "", true, exitError(ExitUsage, fmt.Errorf("unexpected argument %q", fs.Arg(1)))
+		}
+	} else if fs.NArg() > 0 {
+		return "", true, exitError(ExitUsage, fmt.Errorf("unexpected argument %q", fs.Arg(0)))
+	}
+	if required && strings.TrimSpace(id) == "" {
+		fs.Usage()
+		return "", true, exitError(ExitUsage, fmt.Errorf("a database ID is required"))
+	}
+	return strings.TrimSpace(id), false, nil
+}
@@ -0,0 +1,438 @@
+package cli
+
+import (
+	"OmniView/internal/adapter/logger"
+	"OmniView/internal/adapter/storage/boltdb"
+	"OmniView/internal/core/domain"
+	"OmniView/internal/service/permissions"
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// defaultOraclePort is used by "db add" when --port is omitted.
+const defaultOraclePort = 1521
+
+// ==========================================
+// Dispatch
+// ==========================================
+
+// dbCommands lists the "omniview db" subcommands in usage order.
+func dbCommands() []command {
+	return []command{
+		{name: "list", summary: "List the stored database profiles", run: runDBList},
+		{name: "add", summary: "Add a database profile", run: runDBAdd},
+		{name: "edit", summary: "Change fields of a database profile", run: runDBEdit},
+		{name: "remove", summary: "Remove a database profile", run: runDBRemove},
+		{name: "set-default", summary: "Make a profile the default database", run: runDBSetDefault},
+		{name: "test", summary: "Connect to a profile and check its permissions", run: runDBTest},
+	}
+}
+
+// runDB implements "omniview db".
+func runDB(ctx context.Context, deps Deps, args []string) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printDBUsage(deps.Stdout)
+		return nil
+	}
+	for _, cmd := range dbCommands() {
+		if cmd.name == args[0] {
+			return cmd.run(ctx, deps, args[1:])
+		}
+	}
+	printDBUsage(deps.Stderr)
+	return exitError(ExitUsage, fmt.Errorf("unknown db command %q", args[0]))
+}
+
+// printDBUsage lists the db subcommands.
+func printDBUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: omniview db <command> [ID] [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range dbCommands() {
+		fmt.Fprintf(w, "  %-12s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Passwords are never accepted as arguments. Pass --password-stdin to read")
+	fmt.Fprintln(w, "the first line of stdin, or --password-env NAME to read an environment variable.")
+}
+
+// newDBFlagSet creates the flag set of a db subcommand.
+func newDBFlagSet(deps Deps, name, usage, description string) *flag.FlagSet {
+	fs := flag.NewFlagSet("db "+name, flag.ContinueOnError)
+	fs.SetOutput(deps.Stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(deps.Stderr, "Usage: omniview db "+name+" "+usage)
+		fmt.Fprintln(deps.Stderr)
+		fmt.Fprintln(deps.Stderr, description)
+		fmt.Fprintln(deps.Stderr)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// ==========================================
+// Passwords
+// ==========================================
+
+// passwordSource is where a db subcommand reads the password from.
+type passwordSource struct {
+	stdin bool
+	env   string
+}
+
+// register adds the password flags to fs.
+func (p *passwordSource) register(fs *flag.FlagSet) {
+	fs.BoolVar(&p.stdin, "password-stdin", false, "read the password from the first line of stdin")
+	fs.StringVar(&p.env, "password-env", "", "read the password from the named environment variable")
+}
+
+// set reports whether a password source was given.
+func (p passwordSource) set() bool { return p.stdin || p.env != "" }
+
+// read returns the password from the chosen source.
+func (p passwordSource) read(deps Deps) (string, error) {
+	switch {
+	case p.stdin && p.env != "":
+		return "", exitError(ExitUsage, fmt.Errorf("use either --password-stdin or --password-env, not both"))
+	case p.stdin:
+		line, err := bufio.NewReader(deps.Stdin).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", fmt.Errorf("failed to read the password from stdin: %w", err)
+		}
+		password := strings.TrimRight(line, "\r\n")
+		if password == "" {
+			return "", exitError(ExitUsage, fmt.Errorf("no password on stdin"))
+		}
+		return password, nil
+	case p.env != "":
+		password := deps.Getenv(p.env)
+		if password == "" {
+			return "", exitError(ExitUsage, fmt.Errorf("environment variable %s is empty or not set", p.env))
+		}
+		return password, nil
+	default:
+		return "", exitError(ExitUsage, fmt.Errorf("a password is required; pass --password-stdin or --password-env NAME"))
+	}
+}
+
+// ==========================================
+// list
+// ==========================================
+
+// dbProfileJSON is the "db list --format json" shape. It never carries the password.
+type dbProfileJSON struct {
+	ID                   string `json:"id"`
+	Database             string `json:"database"`
+	Host                 string `json:"host"`
+	Port                 int    `json:"port"`
+	Username             string `json:"username"`
+	Default              bool   `json:"default"`
+	PermissionsValidated bool   `json:"permissions_validated"`
+}
+
+// runDBList implements "omniview db list".
+func runDBList(ctx context.Context, deps Deps, args []string) error {
+	fs := newDBFlagSet(deps, "list", "[--format text|json]", "Lists the stored database profiles. Passwords are never printed.")
+	format := fs.String("format", "text", "output format: text or json")
+	if done, err := parseFlags(fs, args); done {
+		return err
+	}
+	if *format != "text" && *format != "json" {
+		return exitError(ExitUsage, fmt.Errorf("unknown format %q; use text or json", *format))
+	}
+
+	profiles, err := deps.Settings.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load database profiles: %w", err)
+	}
+	defaultKey := ""
+	if def, err := deps.Settings.GetDefault(ctx); err == nil && def != nil {
+		defaultKey = def.StorageKey()
+	}
+
+	if *format == "json" {
+		out := make([]dbProfileJSON, 0, len(profiles))
+		for _, p := range profiles {
+			out = append(out, dbProfileJSON{
+				ID:                   p.DatabaseID(),
+				Database:             p.Database(),
+				Host:                 p.Host(),
+				Port:                 p.Port().Int(),
+				Username:             p.Username(),
+				Default:              p.StorageKey() == defaultKey,
+				PermissionsValidated: p.PermissionsValidated(),
+			})
+		}
+		enc := json.NewEncoder(deps.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	if len(profiles) == 0 {
+		fmt.Fprintln(deps.Stderr, `No database profiles. Add one with "omniview db add".`)
+		return nil
+	}
+	tw := tabwriter.NewWriter(deps.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tCONNECTION\tDEFAULT\tPERMISSIONS")
+	for _, p := range profiles {
+		def, perms := "", "unchecked"
+		if p.StorageKey() == defaultKey {
+			def = "*"
+		}
+		if p.PermissionsValidated() {
+			perms = "verified"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", p.DatabaseID(), p.GetConnectionDetails(), def, perms)
+	}
+	return tw.Flush()
+}
+
+// ==========================================
+// add / edit
+// ==========================================
+
+// runDBAdd implements "omniview db add".
+func runDBAdd(ctx context.Context, deps Deps, args []string) error {
+	fs := newDBFlagSet(deps, "add", "ID --host HOST --service NAME --user USER (--password-stdin | --password-env NAME) [flags]",
+		"Adds a database profile. The first profile becomes the default.")
+	var host, service, user string
+	var password passwordSource
+	var makeDefault bool
+	fs.StringVar(&host, "host", "", "database host")
+	port := fs.Int("port", defaultOraclePort, "listener port")
+	fs.StringVar(&service, "service", "", "service name or SID")
+	fs.StringVar(&user, "user", "", "database user (the traced schema)")
+	password.register(fs)
+	fs.BoolVar(&makeDefault, "default", false, "make this the default database")
+	id, done, err := parseFlagsWithID(fs, args, true)
+	if done {
+		return err
+	}
+
+	if _, err := deps.Settings.GetByID(ctx, id); err == nil {
+		return exitError(ExitConfig, fmt.Errorf("database %q: %w; use \"omniview db edit\" to change it", id, domain.ErrKeyCollision))
+	}
+	dbPort, err := domain.NewPort(*port)
+	if err != nil {
+		return exitError(ExitUsage, err)
+	}
+	secret, err := password.read(deps)
+	if err != nil {
+		return err
+	}
+	settings, err := domain.NewDatabaseSettings(id, service, host, dbPort, user, secret)
+	if err != nil {
+		return exitError(ExitUsage, err)
+	}
+
+	if !makeDefault {
+		if _, err := deps.Settings.GetDefault(ctx); errors.Is(err, domain.ErrDefaultSettingsNotFound) {
+			makeDefault = true
+		}
+	}
+	if makeDefault {
+		settings.SetAsDefault()
+		if err := switchDefault(ctx, deps, *settings); err != nil {
+			return err
+		}
+	} else if err := deps.Settings.Save(ctx, *settings); err != nil {
+		return fmt.Errorf("failed to save database %q: %w", id, err)
+	}
+
+	fmt.Fprintf(deps.Stdout, "Added %s (%s)", settings.DatabaseID(), settings.GetConnectionDetails())
+	if makeDefault {
+		fmt.Fprint(deps.Stdout, " as the default database")
+	}
+	fmt.Fprintln(deps.Stdout)
+	return nil
+}
+
+// runDBEdit implements "omniview db edit". Only the given fields change.
+func runDBEdit(ctx context.Context, deps Deps, args []string) error {
+	fs := newDBFlagSet(deps, "edit", "ID [--rename NEW_ID] [--host HOST] [--port PORT] [--service NAME] [--user USER] [--password-stdin | --password-env NAME]",
+		"Changes the given fields of a database profile and keeps the others.")
+	var rename, host, service, user string
+	var password passwordSource
+	fs.StringVar(&rename, "rename", "", "new database ID")
+	fs.StringVar(&host, "host", "", "database host")
+	port := fs.Int("port", 0, "listener port")
+	fs.StringVar(&service, "service", "", "service name or SID")
+	fs.StringVar(&user, "user", "", "database user (the traced schema)")
+	password.register(fs)
+	id, done, err := parseFlagsWithID(fs, args, true)
+	if done {
+		return err
+	}
+
+	existing, err := resolveSettings(ctx, deps, id)
+	if err != nil {
+		return err
+	}
+	newID, newHost, newService, newUser, newPassword := existing.DatabaseID(), existing.Host(), existing.Database(), existing.Username(), existing.Password()
+	newPort := existing.Port()
+	if rename = strings.TrimSpace(rename); rename != "" && rename != newID {
+		if _, err := deps.Settings.GetByID(ctx, rename); err == nil {
+			return exitError(ExitConfig, fmt.Errorf("database %q: %w", rename, domain.ErrKeyCollision))
+		}
+		newID = rename
+	}
+	if host != "" {
+		newHost = host
+	}
+	if service != "" {
+		newService = service
+	}
+	if user != "" {
+		newUser = user
+	}
+	if *port != 0 {
+		if newPort, err = domain.NewPort(*port); err != nil {
+			return exitError(ExitUsage, err)
+		}
+	}
+	if password.set() {
+		if newPassword, err = password.read(deps); err != nil {
+			return err
+		}
+	}
+
+	if err := existing.Update(newID, newService, newHost, newPort, newUser, newPassword); err != nil {
+		return exitError(ExitUsage, err)
+	}
+	if err := deps.Settings.Replace(ctx, id, *existing); err != nil {
+		return fmt.Errorf("failed to save database %q: %w", newID, err)
+	}
+	fmt.Fprintf(deps.Stdout, "Updated %s (%s)\n", existing.DatabaseID(), existing.GetConnectionDetails())
+	return nil
+}
+
+// ==========================================
+// remove / set-default
+// ==========================================
+
+// runDBRemove implements "omniview db remove".
+func runDBRemove(ctx context.Context, deps Deps, args []string) error {
+	fs := newDBFlagSet(deps, "remove", "ID", "Removes a database profile.")
+	id, done, err := parseFlagsWithID(fs, args, true)
+	if done {
+		return err
+	}
+	settings, err := resolveSettings(ctx, deps, id)
+	if err != nil {
+		return err
+	}
+	wasDefault := false
+	if def, err := deps.Settings.GetDefault(ctx); err == nil && def != nil {
+		wasDefault = def.StorageKey() == settings.StorageKey()
+	}
+
+	if err := deps.Settings.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to remove database %q: %w", id, err)
+	}
+	fmt.Fprintf(deps.Stdout, "Removed %s\n", settings.DatabaseID())
+	if wasDefault {
+		fmt.Fprintln(deps.Stderr, `No default database is set now; choose one with "omniview db set-default".`)
+	}
+	return nil
+}
+
+// runDBSetDefault implements "omniview db set-default".
+func runDBSetDefault(ctx context.Context, deps Deps, args []string) error {
+	fs := newDBFlagSet(deps, "set-default", "ID", "Makes a profile the database the UI and tail connect to by default.")
+	id, done, err := parseFlagsWithID(fs, args, true)
+	if done {
+		return err
+	}
+	settings, err := resolveSettings(ctx, deps, id)
+	if err != nil {
+		return err
+	}
+	settings.SetAsDefault()
+	if err := switchDefault(ctx, deps, *settings); err != nil {
+		return err
+	}
+	fmt.Fprintf(deps.Stdout, "%s is now the default database\n", settings.DatabaseID())
+	return nil
+}
+
+// switchDefault stores newDefault and clears the flag on the previous default
+// in one transaction.
+func switchDefault(ctx context.Context, deps Deps, newDefault domain.DatabaseSettings) error {
+	var previous *domain.DatabaseSettings
+	current, err := deps.Settings.GetDefault(ctx)
+	switch {
+	case err == nil && current != nil && current.StorageKey() != newDefault.StorageKey():
+		current.ClearDefault()
+		previous = current
+	case err != nil && !errors.Is(err, domain.ErrDefaultSettingsNotFound):
+		return fmt.Errorf("failed to load the current default database: %w", err)
+	}
+	if err := deps.Settings.SwitchDefault(ctx, previous, newDefault); err != nil {
+		return fmt.Errorf("failed to set %q as the default database: %w", newDefault.DatabaseID(), err)
+	}
+	return nil
+}
+
+// ==========================================
+// test
+// ==========================================
+
+// runDBTest implements "omniview db test". It reports each stage on stdout
+// and skips the stages after the first failure.
+func runDBTest(ctx context.Context, deps Deps, args []string) error {
+	fs := newDBFlagSet(deps, "test", "[ID]", "Connects to a profile (default: the default database) and checks the permissions OmniView needs.")
+	id, done, err := parseFlagsWithID(fs, args, false)
+	if done {
+		return err
+	}
+
+	w := deps.Stdout
+	stage := func(mark, name, detail string) {
+		fmt.Fprintln(w, strings.TrimRight(fmt.Sprintf("  %s %-18s %s", mark, name, detail), " "))
+	}
+	settings, err := resolveSettings(ctx, deps, id)
+	if err != nil {
+		stage("✗", "Load profile", "failed")
+		return err
+	}
+	fmt.Fprintf(w, "Testing %s (%s)\n", settings.DatabaseID(), settings.GetConnectionDetails())
+	stage("✓", "Load profile", "")
+
+	start := time.Now()
+	db, err := connectDatabase(ctx, deps, settings)
+	if err != nil {
+		stage("✗", "Connect", "failed")
+		stage("-", "Check permissions", "skipped")
+		return err
+	}
+	defer func() {
+		if err := db.Close(context.Background()); err != nil {
+			logger.Warn("failed to close database connection", "error", err)
+		}
+	}()
+	stage("✓", "Connect", time.Since(start).Round(time.Millisecond).String())
+
+	permissionService := permissions.NewPermissionService(db, boltdb.NewPermissionsRepository(deps.Bolt), deps.Bolt)
+	if _, err := permissionService.Verify(ctx, settings.Username()); err != nil {
+		stage("✗", "Check permissions", "failed")
+		return setupError("permission check failed", err)
+	}
+	stage("✓", "Check permissions", "all required grants present")
+
+	if !settings.PermissionsValidated() {
+		settings.MarkPermissionsValidated()
+		if err := deps.Settings.Save(ctx, *settings); err != nil {
+			logger.Warn("failed to persist permission validation", "database", settings.DatabaseID(), "error", err)
+		}
+	}
+	fmt.Fprintf(w, "%s is ready for tracing\n", settings.DatabaseID())
+	return nil
+}
@@ -0,0 +1,164 @@
+package cli
+
+import (
+	"OmniView/internal/adapter/storage/boltdb"
+	"OmniView/internal/core/domain"
+	"OmniView/internal/core/ports"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+)
+
+// grantingDatabase connects and answers the permission check with every grant present.
+type grantingDatabase struct{ ports.DatabaseRepository }
+
+func (grantingDatabase) Connect(context.Context) error                       { return nil }
+func (grantingDatabase) Close(context.Context) error                         { return nil }
+func (grantingDatabase) PackageExists(context.Context, string) (bool, error) { return true, nil }
+func (grantingDatabase) ExecuteStatement(context.Context, string) error      { return nil }
+func (grantingDatabase) FetchWithParams(context.Context, string, map[string]interface{}) ([]string, error) {
+	granted, _ := json.Marshal(domain.PermissionStatus{
+		CreateSequence: true, CreateProcedure: true, CreateType: true, AQAdministratorRole: true, AQUserRole: true,
+		DBMSAQADMExecute: true, DBMSAQExecute: true, AQRecipientListT: true, AQAgentType: true,
+	})
+	return []string{string(granted)}, nil
+}
+
+// newDBTestDeps returns deps over a fresh BoltDB, with stdout captured and
+// the given stdin and environment.
+func newDBTestDeps(t *testing.T, db ports.DatabaseRepository, stdin string, env map[string]string) (Deps, *bytes.Buffer) {
+	t.Helper()
+
+	bolt := newTestBoltAdapter(t)
+	var stdout bytes.Buffer
+	return Deps{
+		Bolt:      bolt,
+		Settings:  boltdb.NewDatabaseSettingsRepository(bolt),
+		DBFactory: func(*domain.DatabaseSettings) (ports.DatabaseRepository, error) { return db, nil },
+		Stdin:     strings.NewReader(stdin),
+		Stdout:    &stdout,
+		Stderr:    io.Discard,
+		Getenv:    func(key string) string { return env[key] },
+	}, &stdout
+}
+
+func TestDBCommands_ManageProfilesWithoutPasswordsInArgs(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	deps, stdout := newDBTestDeps(t, nil, "s3cret\n", map[string]string{"PROD_PW": "prod-pass"})
+
+	if err := Run(ctx, deps, []string{"db", "add", "DEV", "--host", "dev-db", "--service", "DEVPDB", "--user", "tracer", "--password-stdin"}); err != nil {
+		t.Fatalf("db add DEV: %v", err)
+	}
+	if err := Run(ctx, deps, []string{"db", "add", "PROD", "--host", "prod-db", "--port", "1522", "--service", "PRODPDB", "--user", "tracer", "--password-env", "PROD_PW"}); err != nil {
+		t.Fatalf("db add PROD: %v", err)
+	}
+
+	dev, err := deps.Settings.GetDefault(ctx)
+	if err != nil || dev.DatabaseID() != "DEV" || dev.Password() != "s3cret" {
+		t.Fatalf("expected the first profile to become the default with the stdin password, got %v (err %v)", dev, err)
+	}
+	prod, err := deps.Settings.GetByID(ctx, "PROD")
+	if err != nil || prod.Password() != "prod-pass" || prod.Port().Int() != 1522 {
+		t.Fatalf("PROD = %v (err %v)", prod, err)
+	}
+
+	for name, args := range map[string][]string{
+		"no password":     {"db", "add", "QA", "--host", "qa", "--service", "QA", "--user", "u"},
+		"empty env":       {"db", "add", "QA", "--host", "qa", "--service", "QA", "--user", "u", "--password-env", "MISSING"},
+		"password flag":   {"db", "add", "QA", "--host", "qa", "--service", "QA", "--user", "u", "--password", "oops"},
+		"missing ID":      {"db", "remove"},
+		"unknown command": {"db", "rename"},
+	} {
+		if err := Run(ctx, deps, args); ExitCode(err) != ExitUsage {
+			t.Errorf("%s: exit code %d, want %d (err %v)", name, ExitCode(err), ExitUsage, err)
+		}
+	}
+	if err := Run(ctx, deps, []string{"db", "add", "PROD", "--host", "h", "--service", "s", "--user", "u", "--password-env", "PROD_PW"}); ExitCode(err) != ExitConfig {
+		t.Fatalf("duplicate add: exit code %d, want %d (err %v)", ExitCode(err), ExitConfig, err)
+	}
+
+	if err := Run(ctx, deps, []string{"db", "edit", "PROD", "--host", "prod-db-2", "--rename", "LIVE"}); err != nil {
+		t.Fatalf("db edit: %v", err)
+	}
+	live, err := deps.Settings.GetByID(ctx, "LIVE")
+	if err != nil || live.Host() != "prod-db-2" || live.Database() != "PRODPDB" || live.Password() != "prod-pass" {
+		t.Fatalf("expected edit to change only the given fields, got %v (err %v)", live, err)
+	}
+	if _, err := deps.Settings.GetByID(ctx, "PROD"); err == nil {
+		t.Fatal("expected the renamed profile to leave no record under the old ID")
+	}
+
+	if err := Run(ctx, deps, []string{"db", "set-default", "LIVE"}); err != nil {
+		t.Fatalf("db set-default: %v", err)
+	}
+	if def, err := deps.Settings.GetDefault(ctx); err != nil || def.DatabaseID() != "LIVE" {
+		t.Fatalf("default = %v (err %v), want LIVE", def, err)
+	}
+	if dev, _ := deps.Settings.GetByID(ctx, "DEV"); dev.IsDefault() {
+		t.Fatal("expected the previous default to lose its flag")
+	}
+
+	stdout.Reset()
+	if err := Run(ctx, deps, []string{"db", "list"}); err != nil {
+		t.Fatalf("db list: %v", err)
+	}
+	list := stdout.String()
+	if !strings.Contains(list, "tracer@prod-db-2:1522/PRODPDB  *") || strings.Contains(list, "prod-pass") || strings.Contains(list, "s3cret") {
+		t.Fatalf("unexpected list output:\n%s", list)
+	}
+
+	stdout.Reset()
+	if err := Run(ctx, deps, []string{"db", "list", "--format", "json"}); err != nil {
+		t.Fatalf("db list --format json: %v", err)
+	}
+	var profiles []dbProfileJSON
+	if err := json.Unmarshal(stdout.Bytes(), &profiles); err != nil || len(profiles) != 2 {
+		t.Fatalf("json list = %s (err %v)", stdout.String(), err)
+	}
+
+	if err := Run(ctx, deps, []string{"db", "remove", "LIVE"}); err != nil {
+		t.Fatalf("db remove: %v", err)
+	}
+	if err := Run(ctx, deps, []string{"db", "remove", "LIVE"}); ExitCode(err) != ExitConfig {
+		t.Fatalf("removing a missing profile: exit code %d, want %d", ExitCode(err), ExitConfig)
+	}
+}
+
+func TestDBTest_ReportsEachStage(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	deps, stdout := newDBTestDeps(t, grantingDatabase{}, "pw\n", nil)
+	if err := Run(ctx, deps, []string{"db", "add", "DEV", "--host", "dev-db", "--service", "DEVPDB", "--user", "tracer", "--password-stdin"}); err != nil {
+		t.Fatalf("db add: %v", err)
+	}
+
+	stdout.Reset()
+	if err := Run(ctx, deps, []string{"db", "test"}); err != nil {
+		t.Fatalf("db test: %v", err)
+	}
+	out := stdout.String()
+	for _, want := range []string{"Testing DEV (tracer@dev-db:1521/DEVPDB)", "✓ Load profile", "✓ Connect", "✓ Check permissions", "DEV is ready for tracing"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("output is missing %q:\n%s", want, out)
+		}
+	}
+	if dev, _ := deps.Settings.GetByID(ctx, "DEV"); !dev.PermissionsValidated() {
+		t.Fatal("expected a passing test to mark the permissions as verified")
+	}
+
+	deps.DBFactory = func(*domain.DatabaseSettings) (ports.DatabaseRepository, error) { return unreachableDatabase{}, nil }
+	stdout.Reset()
+	err := Run(ctx, deps, []string{"db", "test", "DEV"})
+	if ExitCode(err) != ExitConnection {
+		t.Fatalf("exit code %d, want %d (err %v)", ExitCode(err), ExitConnection, err)
+	}
+	if out := stdout.String(); !strings.Contains(out, "✗ Connect") || !strings.Contains(out, "- Check permissions") {
+		t.Fatalf("expected the failed and skipped stages:\n%s", out)
+	}
+}
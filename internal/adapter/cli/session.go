@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"OmniView/internal/adapter/logger"
+	"OmniView/internal/adapter/storage/boltdb"
+	"OmniView/internal/core/domain"
+	"OmniView/internal/core/ports"
+	"OmniView/internal/service/permissions"
+	"OmniView/internal/service/subscribers"
+	"OmniView/internal/service/tracer"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// eventBufferSize matches the event channel the TUI listens on. The
+// session's tracer waits when it is full rather than dropping messages, so
+// a slow stdout holds up dequeuing instead.
+const eventBufferSize = 100
+
+// ==========================================
+// Trace Session
+// ==========================================
+
+// session is a connected database with the tracer deployed and a subscriber
+// registered, the same state the TUI loading screen reaches.
+type session struct {
+	settings   *domain.DatabaseSettings
+	db         ports.DatabaseRepository
+	tracer     *tracer.TracerService
+	subscriber *domain.Subscriber
+	events     chan *domain.QueueMessage
+}
+
+// resolveSettings loads the profile with databaseID, or the default profile
+// when databaseID is empty.
+func resolveSettings(ctx context.Context, deps Deps, databaseID string) (*domain.DatabaseSettings, error) {
+	if databaseID == "" {
+		settings, err := deps.Settings.GetDefault(ctx)
+		if err != nil {
+			if errors.Is(err, domain.ErrDefaultSettingsNotFound) {
+				return nil, exitError(ExitConfig, fmt.Errorf("no default database configured; pass --db or add one in the settings screen"))
+			}
+			return nil, exitError(ExitConfig, fmt.Errorf("failed to load the default database: %w", err))
+		}
+		return settings, nil
+	}
+	settings, err := deps.Settings.GetByID(ctx, databaseID)
+	if err != nil {
+		return nil, exitError(ExitConfig, fmt.Errorf("database %q: %w", databaseID, err))
+	}
+	return settings, nil
+}
+
+// connectDatabase creates the adapter for settings and connects it.
+func connectDatabase(ctx context.Context, deps Deps, settings *domain.DatabaseSettings) (ports.DatabaseRepository, error) {
+	db, err := deps.DBFactory(settings)
+	if err != nil {
+		return nil, exitError(ExitFailure, fmt.Errorf("failed to create database adapter: %w", err))
+	}
+	if err := db.Connect(ctx); err != nil {
+		return nil, exitError(ExitConnection, fmt.Errorf("failed to connect to %s: %w", settings.DisplayTarget(), err))
+	}
+	return db, nil
+}
+
+// setupError classifies a failure after the connection was made: a dropped
+// session is a connection failure, anything else a setup failure.
+func setupError(stage string, err error) error {
+	err = fmt.Errorf("%s: %w", stage, err)
+	if domain.IsConnectionLost(err) {
+		return exitError(ExitConnection, err)
+	}
+	return exitError(ExitSetup, err)
+}
+
+// openSession connects to the database described by settings, checks
+// permissions, deploys the tracer package and registers the subscriber.
+// The returned session must be closed.
+func openSession(ctx context.Context, deps Deps, settings *domain.DatabaseSettings) (*session, error) {
+	db, err := connectDatabase(ctx, deps, settings)
+	if err != nil {
+		return nil, err
+	}
+	s := &session{settings: settings, db: db, events: make(chan *domain.QueueMessage, eventBufferSize)}
+
+	permissionService := permissions.NewPermissionService(db, boltdb.NewPermissionsRepository(deps.Bolt), deps.Bolt)
+	if _, err := permissionService.DeployAndCheck(ctx, settings.Username()); err != nil {
+		s.close()
+		return nil, setupError("permission check failed", err)
+	}
+	if !settings.PermissionsValidated() {
+		settings.MarkPermissionsValidated()
+		if err := deps.Settings.Save(ctx, *settings); err != nil {
+			logger.Warn("failed to persist permission validation", "database", settings.DatabaseID(), "error", err)
+		}
+	}
+
+	s.tracer, err = tracer.NewTracerService(db, deps.Bolt, s.events)
+	if err != nil {
+		s.close()
+		return nil, exitError(ExitFailure, err)
+	}
+	s.tracer.SetBlockingDelivery(true)
+	if err := s.tracer.DeployAndCheck(ctx); err != nil {
+		s.close()
+		return nil, setupError("tracer deployment failed", err)
+	}
+
+	procGen, err := subscribers.NewProcedureGenerator(db)
+	if err != nil {
+		s.close()
+		return nil, exitError(ExitFailure, err)
+	}
+	subscriberService := subscribers.NewSubscriberService(db, boltdb.NewSubscriberRepository(deps.Bolt), procGen)
+	if s.subscriber, err = subscriberService.RegisterSubscriber(ctx); err != nil {
+		s.close()
+		return nil, setupError("subscriber registration failed", err)
+	}
+	return s, nil
+}
+
+// listen starts the tracer listener; messages arrive on s.events.
+func (s *session) listen(ctx context.Context) error {
+	if err := s.tracer.StartEventListener(ctx, s.subscriber, s.settings.Username()); err != nil {
+		return setupError("failed to start the trace listener", err)
+	}
+	return nil
+}
+
+// close stops the listener, which unregisters the subscriber, and closes the
+// connection. It runs after the command context is cancelled, so it uses a
+// fresh one.
+func (s *session) close() {
+	if s.tracer != nil {
+		s.tracer.StopConnectionListener()
+	}
+	if err := s.db.Close(context.Background()); err != nil {
+		logger.Warn("failed to close database connection", "error", err)
+	}
+}
@@ -0,0 +1,300 @@
+package cli
+
+import (
+	"OmniView/internal/adapter/logger"
+	"OmniView/internal/adapter/metrics"
+	"OmniView/internal/adapter/sink"
+	"OmniView/internal/adapter/storage/boltdb"
+	"OmniView/internal/adapter/stream"
+	"OmniView/internal/core/domain"
+	"OmniView/internal/service/sinks"
+	"OmniView/internal/service/tracer"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// tailHealthCheckInterval is how often tail checks whether the listener is
+// still reconnecting.
+const tailHealthCheckInterval = time.Second
+
+// ==========================================
+// Tail Options
+// ==========================================
+
+// tailFormat selects how tail prints each message.
+type tailFormat string
+
+const (
+	tailFormatText tailFormat = "text" // One human-readable line per message
+	tailFormatJSON tailFormat = "json" // One JSON object per line (NDJSON)
+)
+
+// tailOptions are the parsed flags of the tail command.
+type tailOptions struct {
+	databaseID       string
+	format           tailFormat
+	filter           *domain.TraceFilter
+	reconnectTimeout time.Duration // 0 keeps reconnecting for as long as it takes
+	metricsAddr      string        // host:port of the Prometheus endpoint; empty disables it
+	streamAddr       string        // host:port of the SSE and WebSocket stream; empty disables it
+}
+
+// parseTailArgs parses the tail flags. The level, process and filter flags
+// are combined into one trace filter expression, so they behave exactly like
+// the F filter in the TUI.
+func parseTailArgs(args []string, stderr io.Writer) (opts tailOptions, done bool, err error) {
+	fs := flag.NewFlagSet("tail", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var format, level, process, expr string
+	fs.StringVar(&opts.databaseID, "db", "", "database ID of the profile to trace (default: the default database)")
+	fs.StringVar(&format, "format", string(tailFormatText), "output format: text or json")
+	fs.StringVar(&level, "level", "", "only print messages at or above this level, e.g. WARNING")
+	fs.StringVar(&process, "process", "", "only print messages whose process name matches this regular expression")
+	fs.StringVar(&expr, "filter", "", `trace filter expression, e.g. 'payload contains "timeout"'`)
+	fs.DurationVar(&opts.reconnectTimeout, "reconnect-timeout", 0, "exit when the session cannot be re-established within this duration (default: keep retrying)")
+	fs.StringVar(&opts.metricsAddr, "metrics-addr", "", "serve Prometheus metrics on host:port, e.g. 127.0.0.1:9464 (default: $"+MetricsAddrEnv+", or off)")
+	fs.StringVar(&opts.streamAddr, "stream-addr", "", "re-broadcast messages over SSE and WebSocket on host:port (default: $"+StreamAddrEnv+", or off)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: omniview tail [--db ID] [--format text|json] [--level LEVEL] [--process PATTERN] [--filter EXPR] [--metrics-addr HOST:PORT] [--stream-addr HOST:PORT]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Streams trace messages to stdout until interrupted.")
+		fmt.Fprintln(stderr)
+		fs.PrintDefaults()
+	}
+	if done, err := parseFlags(fs, args); done {
+		return opts, true, err
+	}
+
+	switch tailFormat(strings.ToLower(format)) {
+	case tailFormatText:
+		opts.format = tailFormatText
+	case tailFormatJSON:
+		opts.format = tailFormatJSON
+	default:
+		return opts, true, exitError(ExitUsage, fmt.Errorf("unknown format %q; use text or json", format))
+	}
+	if opts.reconnectTimeout < 0 {
+		return opts, true, exitError(ExitUsage, fmt.Errorf("--reconnect-timeout cannot be negative"))
+	}
+
+	var clauses []string
+	if level != "" {
+		lvl, err := domain.NewLogLevel(level)
+		if err != nil {
+			return opts, true, exitError(ExitUsage, err)
+		}
+		clauses = append(clauses, "level>="+lvl.String())
+	}
+	if process != "" {
+		clauses = append(clauses, "process~"+quoteFilterValue(process))
+	}
+	if expr = strings.TrimSpace(expr); expr != "" {
+		clauses = append(clauses, "("+expr+")")
+	}
+	if opts.filter, err = domain.ParseTraceFilter(strings.Join(clauses, " and ")); err != nil {
+		return opts, true, exitError(ExitUsage, fmt.Errorf("invalid filter: %w", err))
+	}
+	return opts, false, nil
+}
+
+// quoteFilterValue quotes s as a trace filter string literal.
+func quoteFilterValue(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// ==========================================
+// Output
+// ==========================================
+
+// formatTailLine renders msg as a single line without the trailing newline.
+// Text lines keep the QueueMessage.Format layout with line breaks in the
+// payload escaped and the attributes appended as key=value pairs.
+func formatTailLine(msg *domain.QueueMessage, format tailFormat) (string, error) {
+	if format == tailFormatJSON {
+		data, err := json.Marshal(msg)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode message %s: %w", msg.MessageID(), err)
+		}
+		return string(data), nil
+	}
+
+	var b strings.Builder
+	b.WriteString(escapeLineBreaks(msg.Format()))
+	for _, key := range msg.AttributeKeys() {
+		value, _ := msg.Attribute(key)
+		fmt.Fprintf(&b, " %s=%s", key, escapeLineBreaks(value))
+	}
+	return b.String(), nil
+}
+
+// escapeLineBreaks keeps a message on one line for grep and log files.
+func escapeLineBreaks(s string) string {
+	return strings.NewReplacer("\r\n", `\n`, "\n", `\n`, "\r", `\r`).Replace(s)
+}
+
+// ==========================================
+// Command
+// ==========================================
+
+// runTail implements "omniview tail".
+func runTail(ctx context.Context, deps Deps, args []string) error {
+	opts, done, err := parseTailArgs(args, deps.Stderr)
+	if done {
+		return err
+	}
+
+	if deps.Getenv != nil {
+		if opts.metricsAddr == "" {
+			opts.metricsAddr = deps.Getenv(MetricsAddrEnv)
+		}
+		if opts.streamAddr == "" {
+			opts.streamAddr = deps.Getenv(StreamAddrEnv)
+		}
+	}
+	if opts.metricsAddr != "" {
+		server, err := metrics.Listen(opts.metricsAddr, metrics.Default())
+		if err != nil {
+			return err
+		}
+		defer server.Close()
+		fmt.Fprintf(deps.Stderr, "Serving metrics on %s\n", server.URL())
+	}
+	var hub *stream.Hub
+	if opts.streamAddr != "" {
+		hub = stream.NewHub()
+		server, err := stream.Listen(opts.streamAddr, hub)
+		if err != nil {
+			return err
+		}
+		defer server.Close()
+		fmt.Fprintf(deps.Stderr, "Streaming messages on %s (SSE: /events, WebSocket: /ws)\n", server.URL())
+	}
+
+	settings, err := resolveSettings(ctx, deps, opts.databaseID)
+	if err != nil {
+		return err
+	}
+	s, err := openSession(ctx, deps, settings)
+	if err != nil {
+		return err
+	}
+	defer s.close()
+
+	// Deferred before the router, so its webhook sink hands over what it holds first
+	defer startWebhookDeliveries(ctx, deps)()
+	if router, running := newSinkRouter(ctx, deps); router != nil {
+		defer router.Close()
+		if running > 0 {
+			fmt.Fprintf(deps.Stderr, "Routing messages to %d output sinks\n", running)
+		}
+		if hub != nil {
+			router.Tap(hub)
+		}
+		s.tracer.SetMessagePublisher(router)
+	} else if hub != nil {
+		s.tracer.SetMessagePublisher(hub)
+	}
+	if err := s.listen(ctx); err != nil {
+		return err
+	}
+	fmt.Fprintf(deps.Stderr, "Tracing %s as %s. Press Ctrl+C to stop.\n", settings.DisplayTarget(), s.subscriber.Name())
+	return streamMessages(ctx, s, opts, deps.Stdout)
+}
+
+// startWebhookDeliveries has the webhook dispatcher save failed deliveries
+// to the settings store and queues again those a previous run left pending.
+// The returned func waits for the dispatcher to drain.
+func startWebhookDeliveries(ctx context.Context, deps Deps) func() {
+	if deps.Bolt == nil {
+		return func() {}
+	}
+	tracer.SetWebhookDeadLetterRepository(boltdb.NewDeadLetterRepository(deps.Bolt))
+	resumed, err := tracer.ResumeWebhookDeliveries(ctx, deps.Bolt)
+	if err != nil {
+		logger.Warn("failed to resume interrupted webhook deliveries", "error", err)
+	}
+	if resumed > 0 {
+		logger.Info("resumed interrupted webhook deliveries", "count", resumed)
+	}
+	return tracer.StopWebhookDispatcher
+}
+
+// newSinkRouter starts routing to the output sinks configured in the TUI,
+// webhooks included, and reports how many are running. When they cannot be
+// loaded it still sends Trace_Message_To_Webhook messages to their webhooks.
+func newSinkRouter(ctx context.Context, deps Deps) (*sinks.Router, int) {
+	if deps.Bolt == nil {
+		return nil, 0
+	}
+	registry := sinks.NewRegistry()
+	sink.Register(registry, deps.Bolt)
+	router, err := sinks.NewRouter(registry, boltdb.NewSinkRepository(deps.Bolt))
+	if err != nil {
+		fmt.Fprintf(deps.Stderr, "Skipping output sinks: %v\n", err)
+		return nil, 0
+	}
+	if err := router.Reload(ctx); err != nil {
+		fmt.Fprintf(deps.Stderr, "Skipping output sinks, routing only webhook messages: %v\n", err)
+		router.ApplyDefaultRoute()
+	}
+	running := 0
+	for _, status := range router.Status() {
+		if status.Running {
+			running++
+		} else if status.Err != nil {
+			fmt.Fprintf(deps.Stderr, "Skipping output sink %s: %v\n", status.Config.ID, status.Err)
+		}
+	}
+	return router, running
+}
+
+// streamMessages prints the messages that pass the filter until ctx is
+// cancelled, the event channel closes or the session stays down longer than
+// the reconnect timeout.
+func streamMessages(ctx context.Context, s *session, opts tailOptions, w io.Writer) error {
+	ticker := time.NewTicker(tailHealthCheckInterval)
+	defer ticker.Stop()
+	var reconnectingSince time.Time
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-s.events:
+			if !ok {
+				return nil
+			}
+			if !opts.filter.Match(msg) {
+				continue
+			}
+			line, err := formatTailLine(msg, opts.format)
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return fmt.Errorf("failed to write message: %w", err)
+			}
+		case now := <-ticker.C:
+			if opts.reconnectTimeout <= 0 {
+				continue
+			}
+			health := s.tracer.ConnectionHealth()
+			if !health.Reconnecting() {
+				reconnectingSince = time.Time{}
+				continue
+			}
+			if reconnectingSince.IsZero() {
+				reconnectingSince = now
+			}
+			if now.Sub(reconnectingSince) >= opts.reconnectTimeout {
+				return exitError(ExitConnection, fmt.Errorf("connection lost and not re-established within %s (attempt %d): %s",
+					opts.reconnectTimeout, health.ReconnectAttempt, health.ReconnectErr))
+			}
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package cli
+
+import (
+	"OmniView/internal/adapter/storage/boltdb"
+	"OmniView/internal/core/domain"
+	"OmniView/internal/core/ports"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestBoltAdapter(t *testing.T) *boltdb.BoltAdapter {
+	t.Helper()
+
+	adapter, err := boltdb.NewBoltAdapter(t.TempDir() + "/test.bolt")
+	if err != nil {
+		t.Fatalf("NewBoltAdapter: %v", err)
+	}
+	if err := adapter.Initialize(); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	t.Cleanup(func() { _ = adapter.Close() })
+	return adapter
+}
+
+func newTestDatabaseSettings(t *testing.T, id string) *domain.DatabaseSettings {
+	t.Helper()
+
+	port, err := domain.NewPort(1521)
+	if err != nil {
+		t.Fatalf("NewPort: %v", err)
+	}
+	settings, err := domain.NewDatabaseSettings(id, "FREEDB", "localhost", port, "testuser", "testpass")
+	if err != nil {
+		t.Fatalf("NewDatabaseSettings: %v", err)
+	}
+	return settings
+}
+
+func newTestMessage(t *testing.T, level domain.LogLevel, process, payload string) *domain.QueueMessage {
+	t.Helper()
+
+	msg, err := domain.NewQueueMessage("MSG-1", process, level, payload, time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("NewQueueMessage: %v", err)
+	}
+	return msg
+}
+
+// unreachableDatabase fails to connect; no other method may be called.
+type unreachableDatabase struct{ ports.DatabaseRepository }
+
+func (unreachableDatabase) Connect(context.Context) error {
+	return errors.New("ORA-12541: TNS:no listener")
+}
+
+func TestRun_UnknownCommandIsAUsageError(t *testing.T) {
+	t.Parallel()
+
+	var stderr bytes.Buffer
+	err := Run(context.Background(), Deps{Stdout: io.Discard, Stderr: &stderr}, []string{"follow"})
+	if ExitCode(err) != ExitUsage {
+		t.Fatalf("ExitCode = %d, want %d (err %v)", ExitCode(err), ExitUsage, err)
+	}
+	if !strings.Contains(stderr.String(), "tail") {
+		t.Fatalf("expected the usage to list the commands, got:\n%s", stderr.String())
+	}
+	if !IsCommand("tail") || IsCommand("--debug") {
+		t.Fatal("IsCommand should only accept known subcommands")
+	}
+}
+
+func TestParseTailArgs_CombinesFiltersAndRejectsBadFlags(t *testing.T) {
+	t.Parallel()
+
+	opts, done, err := parseTailArgs([]string{"--db", "PROD", "--format", "JSON", "--level", "warning", "--process", `^ORDER\.`, "--filter", `payload contains "late"`}, io.Discard)
+	if err != nil || done {
+		t.Fatalf("parseTailArgs: done=%v err=%v", done, err)
+	}
+	if opts.databaseID != "PROD" || opts.format != tailFormatJSON {
+		t.Fatalf("opts = %+v", opts)
+	}
+
+	cases := []struct {
+		level   domain.LogLevel
+		process string
+		payload string
+		want    bool
+	}{
+		{domain.LogLevelError, "ORDER.SUBMIT", "shipment late", true},
+		{domain.LogLevelInfo, "ORDER.SUBMIT", "shipment late", false},
+		{domain.LogLevelError, "BILLING", "shipment late", false},
+		{domain.LogLevelError, "ORDERXSUBMIT", "shipment late", false},
+		{domain.LogLevelCritical, "ORDER.SUBMIT", "on time", false},
+	}
+	for _, tc := range cases {
+		if got := opts.filter.Match(newTestMessage(t, tc.level, tc.process, tc.payload)); got != tc.want {
+			t.Errorf("Match(%s %s %q) = %v, want %v", tc.level, tc.process, tc.payload, got, tc.want)
+		}
+	}
+
+	for _, args := range [][]string{{"--format", "xml"}, {"--level", "LOUD"}, {"--filter", "level >>"}, {"extra"}} {
+		if _, _, err := parseTailArgs(args, io.Discard); ExitCode(err) != ExitUsage {
+			t.Errorf("parseTailArgs(%q) exit code = %d, want %d", args, ExitCode(err), ExitUsage)
+		}
+	}
+}
+
+func TestFormatTailLine_KeepsEachMessageOnOneLine(t *testing.T) {
+	t.Parallel()
+
+	msg := newTestMessage(t, domain.LogLevelWarning, "ORDER_API", "first\nsecond")
+	msg.SetAttributes(map[string]string{"tenant": "acme"})
+
+	line, err := formatTailLine(msg, tailFormatText)
+	if err != nil {
+		t.Fatalf("formatTailLine: %v", err)
+	}
+	if want := `[2026-03-01 09:30:00] [WARNING] ORDER_API: first\nsecond tenant=acme`; line != want {
+		t.Fatalf("text line = %q, want %q", line, want)
+	}
+
+	line, err = formatTailLine(msg, tailFormatJSON)
+	if err != nil {
+		t.Fatalf("formatTailLine: %v", err)
+	}
+	if strings.Contains(line, "\n") || !strings.Contains(line, `"process_name":"ORDER_API"`) {
+		t.Fatalf("json line = %q", line)
+	}
+}
+
+func TestStreamMessages_PrintsMatchingMessagesUntilCancelled(t *testing.T) {
+	t.Parallel()
+
+	opts, _, err := parseTailArgs([]string{"--level", "ERROR"}, io.Discard)
+	if err != nil {
+		t.Fatalf("parseTailArgs: %v", err)
+	}
+	s := &session{events: make(chan *domain.QueueMessage, 3)}
+	s.events <- newTestMessage(t, domain.LogLevelInfo, "P", "skipped")
+	s.events <- newTestMessage(t, domain.LogLevelError, "P", "printed")
+	close(s.events)
+
+	var out bytes.Buffer
+	if err := streamMessages(context.Background(), s, opts, &out); err != nil {
+		t.Fatalf("streamMessages: %v", err)
+	}
+	if got := out.String(); got != "[2026-03-01 09:30:00] [ERROR] P: printed\n" {
+		t.Fatalf("output = %q", got)
+	}
+}
+
+func TestRunTail_ExitCodesReflectConfigAndConnectionFailures(t *testing.T) {
+	t.Parallel()
+
+	bolt := newTestBoltAdapter(t)
+	settingsRepo := boltdb.NewDatabaseSettingsRepository(bolt)
+	deps := Deps{
+		Bolt:     bolt,
+		Settings: settingsRepo,
+		DBFactory: func(*domain.DatabaseSettings) (ports.DatabaseRepository, error) {
+			return unreachableDatabase{}, nil
+		},
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+
+	err := Run(context.Background(), deps, []string{"tail"})
+	if ExitCode(err) != ExitConfig {
+		t.Fatalf("without a default database: exit code %d, want %d (err %v)", ExitCode(err), ExitConfig, err)
+	}
+	err = Run(context.Background(), deps, []string{"tail", "--db", "MISSING"})
+	if ExitCode(err) != ExitConfig {
+		t.Fatalf("unknown database: exit code %d, want %d (err %v)", ExitCode(err), ExitConfig, err)
+	}
+
+	if err := settingsRepo.Save(context.Background(), *newTestDatabaseSettings(t, "DEV")); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	err = Run(context.Background(), deps, []string{"tail", "--db", "DEV"})
+	if ExitCode(err) != ExitConnection || !strings.Contains(err.Error(), "ORA-12541") {
+		t.Fatalf("unreachable database: exit code %d, want %d (err %v)", ExitCode(err), ExitConnection, err)
+	}
+}
+
+func TestNewSinkRouter_CountsRunningSinks(t *testing.T) {
+	t.Parallel()
+
+	bolt := newTestBoltAdapter(t)
+	var stderr bytes.Buffer
+	deps := Deps{Bolt: bolt, Stderr: &stderr}
+	if router, _ := newSinkRouter(context.Background(), Deps{Stderr: &stderr}); router != nil {
+		t.Fatal("expected no router without a settings store")
+	}
+
+	// A new store routes flagged messages to the webhook sink
+	router, running := newSinkRouter(context.Background(), deps)
+	if router == nil || running != 1 {
+		t.Fatalf("expected a router running the seeded webhook sink, got %v and %d", router, running)
+	}
+	router.Close()
+
+	repo := boltdb.NewSinkRepository(bolt)
+	broken, err := domain.NewSinkConfig("broken", "file", nil, true)
+	if err != nil {
+		t.Fatalf("NewSinkConfig: %v", err)
+	}
+	if err := repo.SaveSink(context.Background(), *broken); err != nil {
+		t.Fatalf("SaveSink: %v", err)
+	}
+	router, running = newSinkRouter(context.Background(), deps)
+	if router == nil || running != 1 {
+		t.Fatalf("expected the broken sink to be skipped, got %v and %d", router, running)
+	}
+	router.Close()
+	if !strings.Contains(stderr.String(), "Skipping output sink broken") {
+		t.Fatalf("expected the open error on stderr, got %q", stderr.String())
+	}
+
+	file, err := domain.NewSinkConfig("audit", "file", map[string]string{"path": t.TempDir() + "/audit.log"}, true)
+	if err != nil {
+		t.Fatalf("NewSinkConfig: %v", err)
+	}
+	if err := repo.SaveSink(context.Background(), *file); err != nil {
+		t.Fatalf("SaveSink: %v", err)
+	}
+	router, running = newSinkRouter(context.Background(), deps)
+	if router == nil || running != 2 {
+		t.Fatalf("expected a router with two running sinks, got %v and %d", router, running)
+	}
+	router.Close()
+}
@@ -0,0 +1,160 @@
+package control
+
+// ==========================================
+// Control API Adapter
+// ==========================================
+// JSON-RPC 2.0 over a Unix domain socket, so scripts and editor integrations
+// can drive a running OmniView instance. Requests and responses are single
+// JSON objects, one per line; a connection may send any number of them.
+// Requests without an id are notifications and get no response.
+//
+// The adapter only speaks the protocol. What a method does is up to the
+// Handler, which the UI implements by forwarding each call to the Bubble Tea
+// program so that state only changes on the UI goroutine.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Standard JSON-RPC 2.0 error codes.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+// CodeUnavailable is returned when a method cannot run in the current state,
+// e.g. a database switch before the UI has finished its welcome screen.
+const CodeUnavailable = -32000
+
+// requestTimeout bounds how long one call may take, including the wait for
+// the UI to pick it up.
+const requestTimeout = 30 * time.Second
+
+// ==========================================
+// Errors
+// ==========================================
+
+// Error is a JSON-RPC error object. Handlers return it to pick the code;
+// any other error is reported as an internal error.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string { return e.Message }
+
+// MethodNotFound reports an unknown method.
+func MethodNotFound(method string) *Error {
+	return &Error{Code: CodeMethodNotFound, Message: fmt.Sprintf("method %q not found", method)}
+}
+
+// InvalidParams reports parameters that are missing or malformed.
+func InvalidParams(err error) *Error {
+	return &Error{Code: CodeInvalidParams, Message: err.Error()}
+}
+
+// Unavailable reports a method that cannot run right now.
+func Unavailable(err error) *Error {
+	return &Error{Code: CodeUnavailable, Message: err.Error()}
+}
+
+// toError maps a handler error onto a JSON-RPC error object.
+func toError(err error) *Error {
+	var rpcErr *Error
+	if errors.As(err, &rpcErr) {
+		return rpcErr
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &Error{Code: CodeInternalError, Message: "timed out waiting for OmniView to handle the request"}
+	}
+	return &Error{Code: CodeInternalError, Message: err.Error()}
+}
+
+// ==========================================
+// Handler
+// ==========================================
+
+// Handler runs one method call. The result is encoded as the JSON-RPC result.
+type Handler interface {
+	HandleControl(ctx context.Context, method string, params json.RawMessage) (any, error)
+}
+
+// HandlerFunc adapts a function to Handler.
+type HandlerFunc func(ctx context.Context, method string, params json.RawMessage) (any, error)
+
+func (f HandlerFunc) HandleControl(ctx context.Context, method string, params json.RawMessage) (any, error) {
+	return f(ctx, method, params)
+}
+
+// DecodeParams unmarshals params into v, rejecting unknown fields so typos in
+// scripts fail loudly. Missing params decode as an empty object.
+func DecodeParams(params json.RawMessage, v any) error {
+	if len(params) == 0 || string(params) == "null" {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(params))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return InvalidParams(fmt.Errorf("invalid params: %w", err))
+	}
+	return nil
+}
+
+// ==========================================
+// Messages
+// ==========================================
+
+// request is one JSON-RPC request or notification.
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// response is one JSON-RPC response; exactly one of Result and Error is set.
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// handleLine decodes and runs one request line. It returns nil for
+// notifications, which get no response.
+func handleLine(ctx context.Context, handler Handler, line []byte) *response {
+	var req request
+	if err := json.Unmarshal(line, &req); err != nil {
+		return &response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &Error{Code: CodeParseError, Message: "parse error: " + err.Error()}}
+	}
+	id := req.ID
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return &response{JSONRPC: "2.0", ID: id, Error: &Error{Code: CodeInvalidRequest, Message: `invalid request: "jsonrpc" must be "2.0" and "method" is required`}}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	result, err := handler.HandleControl(ctx, req.Method, req.Params)
+	if len(req.ID) == 0 {
+		return nil
+	}
+	if err != nil {
+		return &response{JSONRPC: "2.0", ID: id, Error: toError(err)}
+	}
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return &response{JSONRPC: "2.0", ID: id, Error: &Error{Code: CodeInternalError, Message: "failed to encode result: " + err.Error()}}
+	}
+	return &response{JSONRPC: "2.0", ID: id, Result: encoded}
+}
@@ -0,0 +1,182 @@
+package control
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// echoHandler returns its params for "echo", fails for "fail" and records
+// every call, including notifications.
+type echoHandler struct {
+	calls chan string
+}
+
+func (h *echoHandler) HandleControl(_ context.Context, method string, params json.RawMessage) (any, error) {
+	h.calls <- method
+	switch method {
+	case "echo":
+		var p struct {
+			Text string `json:"text"`
+		}
+		if err := DecodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		return map[string]string{"text": p.Text}, nil
+	case "fail":
+		return nil, errors.New("boom")
+	default:
+		return nil, MethodNotFound(method)
+	}
+}
+
+func newEchoHandler() *echoHandler {
+	return &echoHandler{calls: make(chan string, 16)}
+}
+
+// socketPath returns a short socket path; Unix socket paths are limited to
+// about 100 bytes, which t.TempDir can exceed on some platforms.
+func socketPath(t *testing.T) string {
+	t.Helper()
+
+	dir, err := os.MkdirTemp("", "ovctl")
+	if err != nil {
+		t.Fatalf("MkdirTemp: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return filepath.Join(dir, "s")
+}
+
+func TestHandleLine_Responses(t *testing.T) {
+	t.Parallel()
+
+	h := newEchoHandler()
+	cases := []struct {
+		name string
+		line string
+		want string
+	}{
+		{"result", `{"jsonrpc":"2.0","id":1,"method":"echo","params":{"text":"hi"}}`, `{"jsonrpc":"2.0","id":1,"result":{"text":"hi"}}`},
+		{"string id", `{"jsonrpc":"2.0","id":"a","method":"echo"}`, `{"jsonrpc":"2.0","id":"a","result":{"text":""}}`},
+		{"parse error", `{"jsonrpc":`, `"code":-32700`},
+		{"invalid request", `{"jsonrpc":"1.0","id":2,"method":"echo"}`, `{"jsonrpc":"2.0","id":2,"error":{"code":-32600`},
+		{"unknown method", `{"jsonrpc":"2.0","id":3,"method":"nope"}`, `"code":-32601`},
+		{"unknown param", `{"jsonrpc":"2.0","id":4,"method":"echo","params":{"txt":"hi"}}`, `"code":-32602`},
+		{"handler error", `{"jsonrpc":"2.0","id":5,"method":"fail"}`, `{"code":-32603,"message":"boom"}`},
+	}
+	for _, tc := range cases {
+		resp := handleLine(context.Background(), h, []byte(tc.line))
+		if resp == nil {
+			t.Errorf("%s: expected a response", tc.name)
+			continue
+		}
+		data, _ := json.Marshal(resp)
+		if !strings.Contains(string(data), tc.want) {
+			t.Errorf("%s: response %s, want it to contain %s", tc.name, data, tc.want)
+		}
+	}
+
+	if resp := handleLine(context.Background(), h, []byte(`{"jsonrpc":"2.0","method":"echo"}`)); resp != nil {
+		t.Errorf("expected no response to a notification, got %+v", resp)
+	}
+}
+
+func TestServer_ServesRequestsInOrder(t *testing.T) {
+	t.Parallel()
+
+	h := newEchoHandler()
+	path := socketPath(t)
+	s, err := Listen(path, h)
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != socketPerm {
+		t.Fatalf("socket permissions %o, want %o", perm, socketPerm)
+	}
+	// The private directory the socket was created in is gone
+	if entries, err := os.ReadDir(filepath.Dir(path)); err != nil || len(entries) != 1 {
+		t.Fatalf("socket directory holds %v (err %v), want only the socket", entries, err)
+	}
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	requests := `{"jsonrpc":"2.0","method":"echo"}` + "\n" +
+		`{"jsonrpc":"2.0","id":1,"method":"echo","params":{"text":"one"}}` + "\n" +
+		"\n" +
+		`{"jsonrpc":"2.0","id":2,"method":"echo","params":{"text":"two"}}` + "\n"
+	if _, err := conn.Write([]byte(requests)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	for _, want := range []string{`"id":1,"result":{"text":"one"}`, `"id":2,"result":{"text":"two"}`} {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("ReadString: %v", err)
+		}
+		if !strings.Contains(line, want) {
+			t.Fatalf("response %q, want it to contain %s", line, want)
+		}
+	}
+	if len(h.calls) != 3 {
+		t.Fatalf("handler ran %d times, want 3 including the notification", len(h.calls))
+	}
+}
+
+func TestListen_ReplacesStaleSocketOnly(t *testing.T) {
+	t.Parallel()
+
+	path := socketPath(t)
+	first, err := Listen(path, newEchoHandler())
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	if _, err := Listen(path, newEchoHandler()); err == nil || !strings.Contains(err.Error(), "in use") {
+		t.Fatalf("expected a live socket to be refused, got %v", err)
+	}
+	if err := first.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected Close to remove the socket, stat err %v", err)
+	}
+
+	// A socket file nobody listens on, as left by a crash
+	stale, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("net.Listen: %v", err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	second, err := Listen(path, newEchoHandler())
+	if err != nil {
+		t.Fatalf("expected a stale socket to be replaced, got %v", err)
+	}
+	second.Close()
+
+	if err := os.WriteFile(path, []byte("not a socket"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if _, err := Listen(path, newEchoHandler()); err == nil {
+		t.Fatal("expected a regular file at the socket path to be refused")
+	}
+}
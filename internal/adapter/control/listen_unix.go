@@ -0,0 +1,39 @@
+//go:build !windows
+
+package control
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+)
+
+// listenPrivate creates the socket inside a directory only the current user
+// can enter, restricts it to socketPerm and then moves it to path, so it is
+// never reachable with the mode the umask would give it.
+func listenPrivate(path string) (net.Listener, error) {
+	// MkdirTemp creates the directory 0700, beside path so the rename stays on one filesystem
+	dir, err := os.MkdirTemp(filepath.Dir(path), ".omniview-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create private socket directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	tmp := filepath.Join(dir, "s")
+	listener, err := net.Listen("unix", tmp)
+	if err != nil {
+		return nil, err
+	}
+	// The socket is renamed below; Server.Close removes it from path
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+	if err := os.Chmod(tmp, socketPerm); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to restrict socket: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to move socket into place: %w", err)
+	}
+	return listener, nil
+}
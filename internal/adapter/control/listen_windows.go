@@ -0,0 +1,23 @@
+//go:build windows
+
+package control
+
+import (
+	"fmt"
+	"net"
+	"os"
+)
+
+// listenPrivate creates the socket at path. Windows has no umask; the socket
+// takes the access rules of its directory.
+func listenPrivate(path string) (net.Listener, error) {
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, socketPerm); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to restrict socket: %w", err)
+	}
+	return listener, nil
+}
@@ -0,0 +1,11 @@
+//go:build !linux && !darwin
+
+package control
+
+import "net"
+
+// peerUID reports errPeerCredUnsupported; the socket's permissions are the
+// only check on these platforms.
+func peerUID(*net.UnixConn) (int, error) {
+	return 0, errPeerCredUnsupported
+}
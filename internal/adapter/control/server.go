@@ -0,0 +1,210 @@
+package control
+
+import (
+	"OmniView/internal/adapter/logger"
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// maxRequestSize caps one request line.
+	maxRequestSize = 1 << 20
+	// socketPerm keeps other local users from driving the instance.
+	socketPerm = 0o600
+)
+
+// errPeerCredUnsupported is returned by peerUID where the platform cannot
+// tell who is connecting.
+var errPeerCredUnsupported = errors.New("peer credentials not supported")
+
+// Server accepts control connections on a Unix domain socket.
+type Server struct {
+	path     string
+	listener net.Listener
+	handler  Handler
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	conns    map[net.Conn]struct{}
+
+	closeOnce sync.Once
+}
+
+// Listen creates the socket at path and serves handler in the background.
+// A socket left behind by an instance that exited uncleanly is replaced; one
+// that still accepts connections belongs to a running instance and is an error.
+// Connections from other users are refused where the platform reports who
+// is connecting.
+func Listen(path string, handler Handler) (*Server, error) {
+	if err := removeStaleSocket(path); err != nil {
+		return nil, err
+	}
+	listener, err := listenPrivate(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on control socket %s: %w", path, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Server{
+		path:     path,
+		listener: listener,
+		handler:  handler,
+		ctx:      ctx,
+		cancel:   cancel,
+		conns:    make(map[net.Conn]struct{}),
+	}
+	s.wg.Add(1)
+	go s.acceptLoop()
+	logger.Info("control socket listening", "path", path)
+	return s, nil
+}
+
+// removeStaleSocket deletes a socket at path that nothing is listening on.
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to inspect control socket %s: %w", path, err)
+	}
+	if info.Mode()&fs.ModeSocket == 0 {
+		return fmt.Errorf("control socket path %s exists and is not a socket", path)
+	}
+	if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		conn.Close()
+		return fmt.Errorf("control socket %s is in use by another OmniView instance", path)
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove stale control socket %s: %w", path, err)
+	}
+	return nil
+}
+
+// Path returns the socket path.
+func (s *Server) Path() string { return s.path }
+
+// Close stops accepting connections, disconnects clients, cancels calls in
+// progress and removes the socket file.
+func (s *Server) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		s.cancel()
+		err = s.listener.Close()
+
+		s.mu.Lock()
+		for conn := range s.conns {
+			conn.Close()
+		}
+		s.mu.Unlock()
+		s.wg.Wait()
+
+		// The listener unlinks the socket on close on most platforms; this covers the rest
+		if removeErr := os.Remove(s.path); removeErr != nil && !errors.Is(removeErr, fs.ErrNotExist) && err == nil {
+			err = removeErr
+		}
+	})
+	return err
+}
+
+func (s *Server) acceptLoop() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if s.ctx.Err() == nil {
+				logger.Error("control socket stopped accepting connections", "path", s.path, "error", err)
+			}
+			return
+		}
+		if !authorizePeer(conn) {
+			conn.Close()
+			continue
+		}
+		s.mu.Lock()
+		if s.ctx.Err() != nil {
+			// Close already disconnected the others
+			s.mu.Unlock()
+			conn.Close()
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go s.serveConn(conn)
+	}
+}
+
+// authorizePeer reports whether conn comes from a process of the user running
+// OmniView. Where the platform cannot tell, the socket's permissions decide.
+func authorizePeer(conn net.Conn) bool {
+	unixConn, ok := conn.(*net.UnixConn)
+	if !ok {
+		return false
+	}
+	uid, err := peerUID(unixConn)
+	if errors.Is(err, errPeerCredUnsupported) {
+		return true
+	}
+	if err != nil {
+		logger.Warn("refused control connection: failed to read peer credentials", "error", err)
+		return false
+	}
+	if uid != os.Getuid() {
+		logger.Warn("refused control connection from another user", "uid", uid)
+		return false
+	}
+	return true
+}
+
+// serveConn answers request lines until the client disconnects. Requests on
+// one connection run in order, so a script can rely on a switch finishing
+// before its next call.
+func (s *Server) serveConn(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 4096), maxRequestSize)
+	writer := bufio.NewWriter(conn)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		resp := handleLine(s.ctx, s.handler, line)
+		if resp == nil {
+			continue
+		}
+		data, err := json.Marshal(resp)
+		if err != nil {
+			logger.Error("failed to encode control response", "error", err)
+			return
+		}
+		data = append(data, '\n')
+		if _, err := writer.Write(data); err != nil {
+			return
+		}
+		if err := writer.Flush(); err != nil {
+			return
+		}
+	}
+	if err := scanner.Err(); err != nil && s.ctx.Err() == nil {
+		logger.Warn("control connection closed", "error", err)
+	}
+}
@@ -0,0 +1,216 @@
+package export
+
+// ==========================================
+// Trace Export Adapter
+// ==========================================
+// Writes a snapshot of trace messages to a shareable file. Three formats are
+// supported:
+//
+//	NDJSON — one QueueMessage.MarshalJSON object per line
+//	CSV    — fixed column layout with a header row
+//	HTML   — standalone report with inline CSS and level colouring
+//
+// Files are written to a temporary sibling first and renamed into place, so
+// readers never observe a half-written export.
+
+import (
+	"OmniView/internal/core/domain"
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ==========================================
+// Format
+// ==========================================
+
+// Format identifies the on-disk layout of an export.
+type Format int
+
+const (
+	FormatNDJSON Format = iota
+	FormatCSV
+	FormatHTML
+)
+
+// ErrUnsupportedFormat is returned when a path or name does not map to a known format.
+var ErrUnsupportedFormat = errors.New("unsupported export format")
+
+// String returns the short name of the format.
+func (f Format) String() string {
+	switch f {
+	case FormatNDJSON:
+		return "ndjson"
+	case FormatCSV:
+		return "csv"
+	case FormatHTML:
+		return "html"
+	default:
+		return "unknown"
+	}
+}
+
+// Extension returns the canonical file extension for the format, including the dot.
+func (f Format) Extension() string {
+	switch f {
+	case FormatCSV:
+		return ".csv"
+	case FormatHTML:
+		return ".html"
+	default:
+		return ".ndjson"
+	}
+}
+
+// Next returns the next format in the cycle: NDJSON -> CSV -> HTML -> NDJSON.
+func (f Format) Next() Format {
+	switch f {
+	case FormatNDJSON:
+		return FormatCSV
+	case FormatCSV:
+		return FormatHTML
+	default:
+		return FormatNDJSON
+	}
+}
+
+// FormatForPath infers the export format from the file extension of path.
+func FormatForPath(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ndjson", ".jsonl", ".json":
+		return FormatNDJSON, nil
+	case ".csv":
+		return FormatCSV, nil
+	case ".html", ".htm":
+		return FormatHTML, nil
+	default:
+		return 0, fmt.Errorf("%w: %q (use .ndjson, .csv or .html)", ErrUnsupportedFormat, filepath.Ext(path))
+	}
+}
+
+// ParseFormat resolves a format by name ("ndjson", "csv" or "html"),
+// ignoring case. "json" and "jsonl" are accepted for NDJSON.
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "ndjson", "jsonl", "json":
+		return FormatNDJSON, nil
+	case "csv":
+		return FormatCSV, nil
+	case "html":
+		return FormatHTML, nil
+	default:
+		return 0, fmt.Errorf("%w: %q (use ndjson, csv or html)", ErrUnsupportedFormat, name)
+	}
+}
+
+// DefaultFileName returns a timestamped file name for an export taken at now.
+func DefaultFileName(format Format, now time.Time) string {
+	return "omniview-trace-" + now.Format("20060102-150405") + format.Extension()
+}
+
+// ==========================================
+// Report Metadata
+// ==========================================
+
+// Report describes the context of an export. It is rendered into the HTML
+// header; the NDJSON and CSV formats carry messages only.
+type Report struct {
+	Title       string
+	Database    string
+	Mode        domain.BroadcastMode
+	Filter      string // Active text filter, empty when none
+	GeneratedAt time.Time
+	Palette     Palette
+}
+
+// Palette holds the CSS colours used by the HTML report. Empty fields fall
+// back to DefaultPalette.
+type Palette struct {
+	Background string
+	Surface    string
+	Text       string
+	Muted      string
+	Levels     map[domain.LogLevel]string
+}
+
+// DefaultPalette returns the colours of the default terminal theme.
+func DefaultPalette() Palette {
+	return Palette{
+		Background: "#0B1118",
+		Surface:    "#0F1720",
+		Text:       "#E6EDF3",
+		Muted:      "#5B636D",
+		Levels: map[domain.LogLevel]string{
+			domain.LogLevelDebug:    "#7D93AA",
+			domain.LogLevelInfo:     "#4FD1C5",
+			domain.LogLevelWarning:  "#F59E0B",
+			domain.LogLevelError:    "#B50000",
+			domain.LogLevelCritical: "#FF8A8A",
+		},
+	}
+}
+
+// ==========================================
+// Writers
+// ==========================================
+
+// Write encodes msgs in the given format to w.
+func Write(w io.Writer, format Format, msgs []*domain.QueueMessage, report Report) error {
+	switch format {
+	case FormatNDJSON:
+		return writeNDJSON(w, msgs)
+	case FormatCSV:
+		return writeCSV(w, msgs)
+	case FormatHTML:
+		return writeHTML(w, msgs, report)
+	default:
+		return fmt.Errorf("%w: %d", ErrUnsupportedFormat, format)
+	}
+}
+
+// WriteFile atomically writes msgs to path in the given format. The parent
+// directory must already exist; an existing file at path is replaced.
+func WriteFile(path string, format Format, msgs []*domain.QueueMessage, report Report) error {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return fmt.Errorf("export: empty file path")
+	}
+
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("export: create temp file in %s: %w", dir, err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	buffered := bufio.NewWriter(tmp)
+	if err := Write(buffered, format, msgs, report); err != nil {
+		tmp.Close()
+		return fmt.Errorf("export: encode %s: %w", format, err)
+	}
+	if err := buffered.Flush(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("export: write %s: %w", path, err)
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return fmt.Errorf("export: chmod temp file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("export: sync temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("export: close temp file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("export: rename into %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package export
+
+import (
+	"OmniView/internal/core/domain"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func mustNewExportMessage(t *testing.T, id string, level domain.LogLevel, payload string) *domain.QueueMessage {
+	t.Helper()
+
+	msg, err := domain.NewQueueMessage(id, "ORDER_API", level, payload, time.Unix(1700000000, 0), true)
+	if err != nil {
+		t.Fatalf("NewQueueMessage: %v", err)
+	}
+	return msg
+}
+
+func TestFormatForPath(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]Format{
+		"trace.ndjson":      FormatNDJSON,
+		"trace.JSONL":       FormatNDJSON,
+		"/tmp/trace.csv":    FormatCSV,
+		"report.htm":        FormatHTML,
+		"./a.b/report.html": FormatHTML,
+	}
+	for path, want := range cases {
+		got, err := FormatForPath(path)
+		if err != nil || got != want {
+			t.Fatalf("FormatForPath(%q) = %v, %v; want %v", path, got, err, want)
+		}
+	}
+
+	if _, err := FormatForPath("trace.txt"); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Fatalf("expected ErrUnsupportedFormat for .txt, got %v", err)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range []Format{FormatNDJSON, FormatCSV, FormatHTML} {
+		got, err := ParseFormat(strings.ToUpper(format.String()))
+		if err != nil || got != format {
+			t.Fatalf("ParseFormat(%q) = %v, %v; want %v", format, got, err, format)
+		}
+	}
+	if got, err := ParseFormat(" jsonl "); err != nil || got != FormatNDJSON {
+		t.Fatalf("ParseFormat(jsonl) = %v, %v", got, err)
+	}
+	if _, err := ParseFormat("xml"); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Fatalf("expected ErrUnsupportedFormat for xml, got %v", err)
+	}
+}
+
+func TestWrite_NDJSONRoundTripsQueueMessages(t *testing.T) {
+	t.Parallel()
+
+	msgs := []*domain.QueueMessage{
+		mustNewExportMessage(t, "m1", domain.LogLevelInfo, "first"),
+		mustNewExportMessage(t, "m2", domain.LogLevelError, "second\nline"),
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatNDJSON, msgs, Report{}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	scanner := bufio.NewScanner(&buf)
+	var got []*domain.QueueMessage
+	for scanner.Scan() {
+		var msg domain.QueueMessage
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			t.Fatalf("line %d: %v", len(got)+1, err)
+		}
+		got = append(got, &msg)
+	}
+	if len(got) != 2 {
+		t.Fatalf("decoded %d lines, want 2", len(got))
+	}
+	if got[1].MessageID() != "m2" || got[1].Payload() != "second\nline" || !got[1].SendToWebhook() {
+		t.Fatalf("unexpected round-trip result: %+v", got[1])
+	}
+}
+
+func TestWrite_CSVUsesColumnLayoutAndNeutralisesFormulas(t *testing.T) {
+	t.Parallel()
+
+	sent := time.Unix(1700000000, 123456789)
+	msg, err := domain.NewQueueMessage("m1", "ORDER_API", domain.LogLevelWarning, `=HYPERLINK("x")`, sent, true)
+	if err != nil {
+		t.Fatalf("NewQueueMessage: %v", err)
+	}
+	msg.SetAttributes(map[string]string{"tenant": "ACME"})
+	msgs := []*domain.QueueMessage{msg}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatCSV, msgs, Report{}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want header + 1", len(records))
+	}
+	if strings.Join(records[0], ",") != strings.Join(csvHeader, ",") {
+		t.Fatalf("header = %v", records[0])
+	}
+	row := records[1]
+	if ts, err := time.Parse(time.RFC3339Nano, row[0]); err != nil || !ts.Equal(sent) {
+		t.Fatalf("timestamp = %q, want %s with nanoseconds", row[0], sent.Format(time.RFC3339Nano))
+	}
+	if row[1] != "WARNING" || row[2] != "ORDER_API" || row[3] != "m1" || row[5] != "true" {
+		t.Fatalf("unexpected row: %v", row)
+	}
+	if row[6] != `'=HYPERLINK("x")` {
+		t.Fatalf("expected formula payload to be neutralised, got %q", row[6])
+	}
+	if row[7] != `{"tenant":"ACME"}` {
+		t.Fatalf("attributes = %q, want a JSON object", row[7])
+	}
+}
+
+func TestWrite_HTMLEscapesPayloadAndColoursLevels(t *testing.T) {
+	t.Parallel()
+
+	msgs := []*domain.QueueMessage{
+		mustNewExportMessage(t, "m1", domain.LogLevelCritical, "<script>alert(1)</script>"),
+	}
+	palette := Palette{Levels: map[domain.LogLevel]string{domain.LogLevelCritical: "#123456"}}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatHTML, msgs, Report{Database: "DEV", Mode: domain.BroadcastModeBroadcast, Palette: palette}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	out := buf.String()
+
+	if strings.Contains(out, "<script>") {
+		t.Fatal("payload must be HTML-escaped")
+	}
+	for _, want := range []string{".lvl-CRITICAL { color: #123456; }", "Database DEV", "Mode Only Broadcast", "1 messages", "#7D93AA"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("HTML report missing %q", want)
+		}
+	}
+}
+
+func TestWriteFile_ReplacesAtomically(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "trace.ndjson")
+	if err := os.WriteFile(path, []byte("stale"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	msgs := []*domain.QueueMessage{mustNewExportMessage(t, "m1", domain.LogLevelInfo, "fresh")}
+	if err := WriteFile(path, FormatNDJSON, msgs, Report{}); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if !strings.Contains(string(data), `"payload":"fresh"`) {
+		t.Fatalf("unexpected file contents: %s", data)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected no temp files left behind, got %d entries", len(entries))
+	}
+
+	if err := WriteFile(filepath.Join(dir, "missing", "trace.csv"), FormatCSV, msgs, Report{}); err == nil {
+		t.Fatal("expected an error when the parent directory does not exist")
+	}
+}
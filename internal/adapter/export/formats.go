@@ -0,0 +1,246 @@
+package export
+
+import (
+	"OmniView/internal/core/domain"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"time"
+)
+
+// ==========================================
+// NDJSON
+// ==========================================
+
+// writeNDJSON writes one QueueMessage JSON object per line.
+func writeNDJSON(w io.Writer, msgs []*domain.QueueMessage) error {
+	for _, msg := range msgs {
+		data, err := msg.MarshalJSON()
+		if err != nil {
+			return fmt.Errorf("marshal message %s: %w", msg.MessageID(), err)
+		}
+		data = append(data, '\n')
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ==========================================
+// CSV
+// ==========================================
+
+// csvHeader is the fixed column layout of CSV exports.
+var csvHeader = []string{"timestamp", "level", "process", "message_id", "mode", "send_to_webhook", "payload", "attributes"}
+
+// writeCSV writes a header row followed by one row per message.
+func writeCSV(w io.Writer, msgs []*domain.QueueMessage) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, msg := range msgs {
+		record := []string{
+			msg.Timestamp().Format(time.RFC3339Nano),
+			msg.LogLevel().String(),
+			csvSafe(msg.ProcessName()),
+			csvSafe(msg.MessageID()),
+			msg.Mode(),
+			strconv.FormatBool(msg.SendToWebhook()),
+			csvSafe(msg.Payload()),
+			"",
+		}
+		if attrs := msg.Attributes(); attrs != nil {
+			data, err := json.Marshal(attrs)
+			if err != nil {
+				return fmt.Errorf("marshal attributes of %s: %w", msg.MessageID(), err)
+			}
+			record[7] = string(data)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvSafe prefixes values that spreadsheet applications would evaluate as a
+// formula, so an exported payload can never execute when the file is opened.
+func csvSafe(value string) string {
+	if value == "" {
+		return value
+	}
+	switch value[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + value
+	}
+	return value
+}
+
+// ==========================================
+// HTML
+// ==========================================
+
+type htmlRow struct {
+	Timestamp string
+	Level     string
+	Process   string
+	Mode      string
+	Payload   string
+}
+
+type htmlLevelCount struct {
+	Level string
+	Count int
+}
+
+type htmlReport struct {
+	Title       string
+	Database    string
+	Mode        string
+	Filter      string
+	GeneratedAt string
+	Total       int
+	Counts      []htmlLevelCount
+	Rows        []htmlRow
+	Palette     Palette
+	Levels      []htmlLevelColour
+}
+
+type htmlLevelColour struct {
+	Class  string
+	Colour string
+}
+
+// reportLevels is the display order of levels in the HTML summary.
+var reportLevels = []domain.LogLevel{
+	domain.LogLevelDebug,
+	domain.LogLevelInfo,
+	domain.LogLevelWarning,
+	domain.LogLevelError,
+	domain.LogLevelCritical,
+}
+
+// writeHTML renders a standalone HTML report. All message content is escaped
+// by html/template.
+func writeHTML(w io.Writer, msgs []*domain.QueueMessage, report Report) error {
+	palette := report.Palette.withDefaults()
+
+	title := report.Title
+	if title == "" {
+		title = "OmniView Trace Export"
+	}
+	generatedAt := report.GeneratedAt
+	if generatedAt.IsZero() {
+		generatedAt = time.Now()
+	}
+
+	counts := make(map[domain.LogLevel]int, len(reportLevels))
+	rows := make([]htmlRow, 0, len(msgs))
+	for _, msg := range msgs {
+		counts[msg.LogLevel()]++
+		rows = append(rows, htmlRow{
+			Timestamp: msg.Timestamp().Format("2006-01-02 15:04:05"),
+			Level:     msg.LogLevel().String(),
+			Process:   msg.ProcessName(),
+			Mode:      msg.Mode(),
+			Payload:   msg.Payload(),
+		})
+	}
+
+	data := htmlReport{
+		Title:       title,
+		Database:    report.Database,
+		Mode:        report.Mode.String(),
+		Filter:      report.Filter,
+		GeneratedAt: generatedAt.Format("2006-01-02 15:04:05 MST"),
+		Total:       len(msgs),
+		Rows:        rows,
+		Palette:     palette,
+	}
+	for _, level := range reportLevels {
+		data.Counts = append(data.Counts, htmlLevelCount{Level: level.String(), Count: counts[level]})
+		data.Levels = append(data.Levels, htmlLevelColour{Class: level.String(), Colour: palette.Levels[level]})
+	}
+
+	return htmlReportTemplate.Execute(w, data)
+}
+
+// withDefaults fills unset palette entries from DefaultPalette.
+func (p Palette) withDefaults() Palette {
+	def := DefaultPalette()
+	if p.Background == "" {
+		p.Background = def.Background
+	}
+	if p.Surface == "" {
+		p.Surface = def.Surface
+	}
+	if p.Text == "" {
+		p.Text = def.Text
+	}
+	if p.Muted == "" {
+		p.Muted = def.Muted
+	}
+	levels := make(map[domain.LogLevel]string, len(def.Levels))
+	for level, colour := range def.Levels {
+		levels[level] = colour
+	}
+	for level, colour := range p.Levels {
+		if colour != "" {
+			levels[level] = colour
+		}
+	}
+	p.Levels = levels
+	return p
+}
+
+// htmlReportTemplate is the standalone report layout. Colours are passed
+// through template.CSS only after they come from the palette, never from
+// message content.
+var htmlReportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"css": func(s string) template.CSS { return template.CSS(s) },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { margin: 0; padding: 24px; background: {{css .Palette.Background}}; color: {{css .Palette.Text}}; font: 13px/1.45 ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; }
+h1 { margin: 0 0 4px; font-size: 18px; }
+.meta { color: {{css .Palette.Muted}}; margin-bottom: 16px; }
+.meta span { margin-right: 16px; }
+.counts { margin-bottom: 16px; }
+.counts span { margin-right: 16px; font-weight: bold; }
+table { width: 100%; border-collapse: collapse; background: {{css .Palette.Surface}}; }
+th, td { padding: 4px 8px; text-align: left; vertical-align: top; border-bottom: 1px solid {{css .Palette.Background}}; }
+th { color: {{css .Palette.Muted}}; font-weight: normal; }
+td.ts, td.proc { white-space: nowrap; color: {{css .Palette.Muted}}; }
+td.lvl { white-space: nowrap; font-weight: bold; }
+td.payload { white-space: pre-wrap; word-break: break-word; }
+{{range .Levels}}.lvl-{{.Class}} { color: {{css .Colour}}; }
+{{end}}</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<div class="meta">
+<span>Generated {{.GeneratedAt}}</span>
+{{if .Database}}<span>Database {{.Database}}</span>{{end}}
+<span>Mode {{.Mode}}</span>
+{{if .Filter}}<span>Filter {{.Filter}}</span>{{end}}
+<span>{{.Total}} messages</span>
+</div>
+<div class="counts">{{range .Counts}}<span class="lvl-{{.Level}}">{{.Level}} {{.Count}}</span>{{end}}</div>
+<table>
+<thead><tr><th>Timestamp</th><th>Level</th><th>Process</th><th>Mode</th><th>Payload</th></tr></thead>
+<tbody>
+{{range .Rows}}<tr><td class="ts">{{.Timestamp}}</td><td class="lvl lvl-{{.Level}}">[{{.Level}}]</td><td class="proc">{{.Process}}</td><td>{{.Mode}}</td><td class="payload">{{.Payload}}</td></tr>
+{{end}}</tbody>
+</table>
+</body>
+</html>
+`))
@@ -0,0 +1,306 @@
+package metrics
+
+// ==========================================
+// Prometheus Metrics Adapter
+// ==========================================
+// A small stdlib-only implementation of the Prometheus text exposition
+// format (version 0.0.4). Packages declare their instruments as package-level
+// variables with the New* constructors, which register them with the default
+// registry; the composition root opts in to exposing them with Listen.
+//
+// Instruments are safe for concurrent use and cheap enough to update on the
+// message hot path. Recording is always on; only the endpoint is optional.
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// ─────────────────────────
+// Registry
+// ─────────────────────────
+
+// metric is one registered metric family.
+type metric interface {
+	name() string
+	write(w io.Writer)
+}
+
+// Registry holds metric families and renders them in the text format.
+type Registry struct {
+	mu      sync.Mutex
+	metrics map[string]metric
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{metrics: make(map[string]metric)}
+}
+
+// defaultRegistry collects the instruments created by the package-level constructors.
+var defaultRegistry = NewRegistry()
+
+// Default returns the registry the package-level constructors register with.
+func Default() *Registry { return defaultRegistry }
+
+// register adds m, panicking on a duplicate name the same way two package-level
+// declarations of one metric would be a programming error.
+func (r *Registry) register(m metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.metrics[m.name()]; exists {
+		panic(fmt.Sprintf("metrics: %q registered twice", m.name()))
+	}
+	r.metrics[m.name()] = m
+}
+
+// WriteText renders every family, sorted by name, in the Prometheus text format.
+func (r *Registry) WriteText(w io.Writer) {
+	r.mu.Lock()
+	families := make([]metric, 0, len(r.metrics))
+	for _, m := range r.metrics {
+		families = append(families, m)
+	}
+	r.mu.Unlock()
+
+	sort.Slice(families, func(i, j int) bool { return families[i].name() < families[j].name() })
+	for _, m := range families {
+		m.write(w)
+	}
+}
+
+// desc is the name and help text shared by every metric type.
+type desc struct {
+	metricName string
+	help       string
+}
+
+func (d desc) name() string { return d.metricName }
+
+// writeHeader writes the HELP and TYPE lines of a family.
+func (d desc) writeHeader(w io.Writer, typ string) {
+	help := strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(d.help)
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", d.metricName, help, d.metricName, typ)
+}
+
+// ─────────────────────────
+// Counter
+// ─────────────────────────
+
+// Counter is a monotonically increasing count.
+type Counter struct {
+	desc
+	value atomic.Uint64
+}
+
+// NewCounter creates a counter registered with the default registry.
+func NewCounter(name, help string) *Counter {
+	return defaultRegistry.NewCounter(name, help)
+}
+
+// NewCounter creates a counter registered with r.
+func (r *Registry) NewCounter(name, help string) *Counter {
+	c := &Counter{desc: desc{metricName: name, help: help}}
+	r.register(c)
+	return c
+}
+
+// Inc adds one.
+func (c *Counter) Inc() { c.value.Add(1) }
+
+// Add adds n.
+func (c *Counter) Add(n uint64) { c.value.Add(n) }
+
+// Value returns the current count.
+func (c *Counter) Value() uint64 { return c.value.Load() }
+
+func (c *Counter) write(w io.Writer) {
+	c.writeHeader(w, "counter")
+	fmt.Fprintf(w, "%s %d\n", c.metricName, c.Value())
+}
+
+// ─────────────────────────
+// Counter Vector
+// ─────────────────────────
+
+// CounterVec is a family of counters partitioned by one label.
+type CounterVec struct {
+	desc
+	label    string
+	mu       sync.RWMutex
+	counters map[string]*Counter
+}
+
+// NewCounterVec creates a labelled counter family registered with the default
+// registry. The given values are exported at zero before their first increment,
+// so rate() and absent() behave from the first scrape.
+func NewCounterVec(name, help, label string, values ...string) *CounterVec {
+	return defaultRegistry.NewCounterVec(name, help, label, values...)
+}
+
+// NewCounterVec creates a labelled counter family registered with r.
+func (r *Registry) NewCounterVec(name, help, label string, values ...string) *CounterVec {
+	v := &CounterVec{desc: desc{metricName: name, help: help}, label: label, counters: make(map[string]*Counter)}
+	for _, value := range values {
+		v.counters[value] = &Counter{}
+	}
+	r.register(v)
+	return v
+}
+
+// With returns the counter for a label value, creating it on first use.
+func (v *CounterVec) With(value string) *Counter {
+	v.mu.RLock()
+	c, ok := v.counters[value]
+	v.mu.RUnlock()
+	if ok {
+		return c
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if c, ok = v.counters[value]; !ok {
+		c = &Counter{}
+		v.counters[value] = c
+	}
+	return c
+}
+
+func (v *CounterVec) write(w io.Writer) {
+	v.writeHeader(w, "counter")
+
+	v.mu.RLock()
+	values := make([]string, 0, len(v.counters))
+	for value := range v.counters {
+		values = append(values, value)
+	}
+	slices.Sort(values)
+	for _, value := range values {
+		fmt.Fprintf(w, "%s{%s=\"%s\"} %d\n", v.metricName, v.label, escapeLabelValue(value), v.counters[value].Value())
+	}
+	v.mu.RUnlock()
+}
+
+// ─────────────────────────
+// Gauge
+// ─────────────────────────
+
+// GaugeFunc is a gauge whose value is read at scrape time.
+type GaugeFunc struct {
+	desc
+	fn func() float64
+}
+
+// NewGaugeFunc creates a gauge registered with the default registry that
+// reports fn on every scrape. fn must be safe to call from any goroutine.
+func NewGaugeFunc(name, help string, fn func() float64) *GaugeFunc {
+	return defaultRegistry.NewGaugeFunc(name, help, fn)
+}
+
+// NewGaugeFunc creates a gauge registered with r.
+func (r *Registry) NewGaugeFunc(name, help string, fn func() float64) *GaugeFunc {
+	g := &GaugeFunc{desc: desc{metricName: name, help: help}, fn: fn}
+	r.register(g)
+	return g
+}
+
+func (g *GaugeFunc) write(w io.Writer) {
+	g.writeHeader(w, "gauge")
+	fmt.Fprintf(w, "%s %s\n", g.metricName, formatFloat(g.fn()))
+}
+
+// ─────────────────────────
+// Histogram
+// ─────────────────────────
+
+// Histogram counts observations into cumulative buckets.
+type Histogram struct {
+	desc
+	mu      sync.Mutex
+	bounds  []float64 // Upper bounds, ascending; +Inf is implied
+	buckets []uint64  // Observations per bound, not cumulative
+	sum     float64
+	count   uint64
+}
+
+// NewHistogram creates a histogram registered with the default registry.
+// bounds are the bucket upper bounds in ascending order.
+func NewHistogram(name, help string, bounds []float64) *Histogram {
+	return defaultRegistry.NewHistogram(name, help, bounds)
+}
+
+// NewHistogram creates a histogram registered with r.
+func (r *Registry) NewHistogram(name, help string, bounds []float64) *Histogram {
+	if !slices.IsSorted(bounds) {
+		panic(fmt.Sprintf("metrics: bucket bounds of %q are not sorted", name))
+	}
+	h := &Histogram{
+		desc:    desc{metricName: name, help: help},
+		bounds:  slices.Clone(bounds),
+		buckets: make([]uint64, len(bounds)),
+	}
+	r.register(h)
+	return h
+}
+
+// Observe records one value.
+func (h *Histogram) Observe(v float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if i, _ := slices.BinarySearch(h.bounds, v); i < len(h.bounds) {
+		h.buckets[i]++
+	}
+	h.sum += v
+	h.count++
+}
+
+// Count returns the number of observations.
+func (h *Histogram) Count() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.count
+}
+
+func (h *Histogram) write(w io.Writer) {
+	h.writeHeader(w, "histogram")
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var cumulative uint64
+	for i, bound := range h.bounds {
+		cumulative += h.buckets[i]
+		fmt.Fprintf(w, "%s_bucket{le=\"%s\"} %d\n", h.metricName, formatFloat(bound), cumulative)
+	}
+	fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n", h.metricName, h.count)
+	fmt.Fprintf(w, "%s_sum %s\n", h.metricName, formatFloat(h.sum))
+	fmt.Fprintf(w, "%s_count %d\n", h.metricName, h.count)
+}
+
+// ─────────────────────────
+// Formatting
+// ─────────────────────────
+
+// formatFloat renders v the way Prometheus parses it.
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// escapeLabelValue escapes a label value for the text format.
+func escapeLabelValue(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
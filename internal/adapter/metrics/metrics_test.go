@@ -0,0 +1,103 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRegistry_WritesTextFormat(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	dequeued := r.NewCounterVec("test_dequeued_total", "Messages dequeued.", "level", "INFO", "ERROR")
+	drops := r.NewCounter("test_drops_total", "Dropped messages.\nSecond line.")
+	batch := r.NewHistogram("test_batch_size", "Batch sizes.", []float64{1, 10, 100})
+	r.NewGaugeFunc("test_queue_depth", "Queue depth.", func() float64 { return 7 })
+
+	dequeued.With("ERROR").Add(3)
+	dequeued.With(`odd"level`).Inc()
+	drops.Inc()
+	for _, v := range []float64{0, 1, 5, 10, 500} {
+		batch.Observe(v)
+	}
+
+	var b strings.Builder
+	r.WriteText(&b)
+	want := `# HELP test_batch_size Batch sizes.
+# TYPE test_batch_size histogram
+test_batch_size_bucket{le="1"} 2
+test_batch_size_bucket{le="10"} 4
+test_batch_size_bucket{le="100"} 4
+test_batch_size_bucket{le="+Inf"} 5
+test_batch_size_sum 516
+test_batch_size_count 5
+# HELP test_dequeued_total Messages dequeued.
+# TYPE test_dequeued_total counter
+test_dequeued_total{level="ERROR"} 3
+test_dequeued_total{level="INFO"} 0
+test_dequeued_total{level="odd\"level"} 1
+# HELP test_drops_total Dropped messages.\nSecond line.
+# TYPE test_drops_total counter
+test_drops_total 1
+# HELP test_queue_depth Queue depth.
+# TYPE test_queue_depth gauge
+test_queue_depth 7
+`
+	if got := b.String(); got != want {
+		t.Fatalf("text output mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRegistry_RejectsDuplicateNames(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	r.NewCounter("test_total", "First.")
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a duplicate registration to panic")
+		}
+	}()
+	r.NewCounter("test_total", "Second.")
+}
+
+func TestListen_ServesMetricsPath(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	r.NewCounter("test_scrapes_total", "Scrapes.").Inc()
+
+	s, err := Listen("127.0.0.1:0", r)
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	defer s.Close()
+
+	resp, err := http.Get(s.URL())
+	if err != nil {
+		t.Fatalf("GET %s: %v", s.URL(), err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain; version=0.0.4") {
+		t.Fatalf("status %d, content type %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	if !strings.Contains(string(body), "test_scrapes_total 1\n") {
+		t.Fatalf("unexpected body:\n%s", body)
+	}
+
+	resp, err = http.Post(s.URL(), "text/plain", nil)
+	if err != nil {
+		t.Fatalf("POST %s: %v", s.URL(), err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Fatalf("POST status %d, want %d", resp.StatusCode, http.StatusMethodNotAllowed)
+	}
+
+	if _, err := Listen(s.Addr(), r); err == nil {
+		t.Fatal("expected binding an address in use to fail")
+	}
+}
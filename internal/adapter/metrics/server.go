@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"OmniView/internal/adapter/logger"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// MetricsPath is where the endpoint serves the registry.
+const MetricsPath = "/metrics"
+
+// textContentType is the content type of the Prometheus text format.
+const textContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Handler serves r in the text format on GET and HEAD.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", textContentType)
+		r.WriteText(w)
+	})
+}
+
+// Server exposes a registry over HTTP.
+type Server struct {
+	listener net.Listener
+	server   *http.Server
+}
+
+// Listen binds addr (host:port) and serves r on MetricsPath in the background.
+// Binding happens before Listen returns, so a port already in use is reported
+// here rather than lost in the serving goroutine.
+func Listen(addr string, r *Registry) (*Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for metrics on %s: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(MetricsPath, r.Handler())
+	s := &Server{
+		listener: listener,
+		server:   &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second},
+	}
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("metrics endpoint stopped", "addr", listener.Addr().String(), "error", err)
+		}
+	}()
+	logger.Info("metrics endpoint listening", "addr", listener.Addr().String())
+	return s, nil
+}
+
+// Addr returns the bound address, which resolves a ":0" port.
+func (s *Server) Addr() string { return s.listener.Addr().String() }
+
+// URL returns the address scrapers should use.
+func (s *Server) URL() string { return "http://" + s.Addr() + MetricsPath }
+
+// Close stops the endpoint, letting in-flight scrapes finish for up to five seconds.
+func (s *Server) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return s.server.Shutdown(ctx)
+}
@@ -0,0 +1,129 @@
+package otlp
+
+import (
+	"OmniView/internal/core/domain"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// ==========================================
+// OTLP/JSON Encoding
+// ==========================================
+// The subset of the ExportLogsServiceRequest message OmniView sends, in the
+// OTLP/JSON encoding: lowerCamelCase field names, 64-bit integers as decimal
+// strings and enums as numbers.
+
+type exportLogsRequest struct {
+	ResourceLogs []resourceLogs `json:"resourceLogs"`
+}
+
+type resourceLogs struct {
+	Resource  resource    `json:"resource"`
+	ScopeLogs []scopeLogs `json:"scopeLogs"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+type scopeLogs struct {
+	Scope      scope       `json:"scope"`
+	LogRecords []logRecord `json:"logRecords"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type logRecord struct {
+	TimeUnixNano         string     `json:"timeUnixNano"`
+	ObservedTimeUnixNano string     `json:"observedTimeUnixNano"`
+	SeverityNumber       int        `json:"severityNumber"`
+	SeverityText         string     `json:"severityText"`
+	Body                 anyValue   `json:"body"`
+	Attributes           []keyValue `json:"attributes,omitempty"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type anyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+// unknownService names the resource of messages without a process name, as
+// the OpenTelemetry SDKs do when service.name is not set.
+const unknownService = "unknown_service:omniview"
+
+// encodeBatch builds one export request for msgs. Messages are grouped into
+// one resource per process name, in the order the processes first appear,
+// so a collector sees each PL/SQL process as its own service.
+func encodeBatch(msgs []*domain.QueueMessage, observed time.Time) ([]byte, error) {
+	var req exportLogsRequest
+	index := make(map[string]int)
+	for _, msg := range msgs {
+		service := msg.ProcessName()
+		if service == "" {
+			service = unknownService
+		}
+		i, ok := index[service]
+		if !ok {
+			i = len(req.ResourceLogs)
+			index[service] = i
+			req.ResourceLogs = append(req.ResourceLogs, resourceLogs{
+				Resource:  resource{Attributes: []keyValue{stringAttr("service.name", service)}},
+				ScopeLogs: []scopeLogs{{Scope: scope{Name: service}}},
+			})
+		}
+		records := &req.ResourceLogs[i].ScopeLogs[0].LogRecords
+		*records = append(*records, newLogRecord(msg, observed))
+	}
+	return json.Marshal(req)
+}
+
+// newLogRecord maps one trace message onto a log record. The message time is
+// kept as the event time; the export time is the observed time.
+func newLogRecord(msg *domain.QueueMessage, observed time.Time) logRecord {
+	severity, severityText := domain.OTLPSeverity(msg.LogLevel())
+	attrs := []keyValue{
+		stringAttr("omniview.message_id", msg.MessageID()),
+		stringAttr("omniview.mode", msg.Mode()),
+		stringAttr("omniview.log_level", msg.LogLevel().String()),
+	}
+	if span, ok := msg.Span(); ok {
+		attrs = append(attrs,
+			stringAttr("omniview.span.id", span.ID),
+			stringAttr("omniview.span.event", string(span.Event)),
+		)
+		if span.ParentID != "" {
+			attrs = append(attrs, stringAttr("omniview.span.parent_id", span.ParentID))
+		}
+		if span.Name != "" {
+			attrs = append(attrs, stringAttr("omniview.span.name", span.Name))
+		}
+	}
+	for _, key := range msg.AttributeKeys() {
+		value, _ := msg.Attribute(key)
+		attrs = append(attrs, stringAttr(key, value))
+	}
+
+	return logRecord{
+		TimeUnixNano:         unixNano(msg.Timestamp()),
+		ObservedTimeUnixNano: unixNano(observed),
+		SeverityNumber:       severity,
+		SeverityText:         severityText,
+		Body:                 anyValue{StringValue: msg.Payload()},
+		Attributes:           attrs,
+	}
+}
+
+func stringAttr(key, value string) keyValue {
+	return keyValue{Key: key, Value: anyValue{StringValue: value}}
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
@@ -0,0 +1,353 @@
+package otlp
+
+// ==========================================
+// OTLP Log Exporter
+// ==========================================
+// Forwards delivered trace messages to an OpenTelemetry collector as log
+// records over OTLP/HTTP with the JSON encoding. The Exporter backs the
+// "otlp" output sink: Publish only queues the message, and a single
+// goroutine batches the queue and posts it, retrying throttled or
+// unavailable collectors with backoff, so a slow collector never holds up
+// the sink router.
+
+import (
+	"OmniView/internal/adapter/logger"
+	"OmniView/internal/adapter/metrics"
+	"OmniView/internal/core/domain"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// queueSize is how many messages may wait for export before new ones are
+	// dropped.
+	queueSize = 4096
+
+	// requestTimeout bounds one POST to the collector.
+	requestTimeout = 10 * time.Second
+
+	// shutdownTimeout bounds the final flush in Close.
+	shutdownTimeout = 5 * time.Second
+
+	// maxResponseBody is how much of a response is read for partial success
+	// details or an error message.
+	maxResponseBody = 64 << 10
+)
+
+var (
+	exportedRecords = metrics.NewCounter("omniview_otlp_exported_records_total",
+		"Log records accepted by the OTLP collector.")
+	failedRecords = metrics.NewCounter("omniview_otlp_failed_records_total",
+		"Log records the OTLP collector rejected or that failed after every retry.")
+	droppedRecords = metrics.NewCounter("omniview_otlp_dropped_records_total",
+		"Log records dropped before export because the queue was full or export was disabled.")
+	exportRetries = metrics.NewCounter("omniview_otlp_retries_total",
+		"OTLP export requests retried after a network error or a retryable status.")
+	exportLatency = metrics.NewHistogram("omniview_otlp_export_seconds",
+		"Time taken by one OTLP export, including retries.",
+		[]float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60})
+)
+
+// retryPolicy controls how failed exports are retried.
+type retryPolicy struct {
+	attempts int           // Total tries per batch, including the first
+	initial  time.Duration // Backoff before the second try
+	max      time.Duration // Upper bound for backoff and Retry-After
+}
+
+var defaultRetryPolicy = retryPolicy{attempts: domain.DefaultOTLPRetryAttempts, initial: time.Second, max: 30 * time.Second}
+
+// Exporter batches trace messages and posts them to an OTLP/HTTP collector.
+type Exporter struct {
+	client   *http.Client
+	config   *domain.OTLPConfig
+	retry    retryPolicy
+	queue    chan *domain.QueueMessage
+	dropping atomic.Bool // Set while messages are being dropped, so the warning is logged once per streak
+
+	ctx       context.Context
+	cancel    context.CancelFunc
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewExporter starts an exporter for cfg. A nil or disabled cfg leaves it
+// idle.
+func NewExporter(cfg *domain.OTLPConfig) *Exporter {
+	return newExporter(cfg, defaultRetryPolicy)
+}
+
+func newExporter(cfg *domain.OTLPConfig, retry retryPolicy) *Exporter {
+	ctx, cancel := context.WithCancel(context.Background())
+	e := &Exporter{
+		client: &http.Client{Timeout: requestTimeout},
+		config: cfg,
+		retry:  retry,
+		queue:  make(chan *domain.QueueMessage, queueSize),
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go e.run()
+	return e
+}
+
+// Publish queues msg for export. It never blocks: while export is disabled
+// the message is ignored, and when the queue is full it is dropped.
+func (e *Exporter) Publish(msg *domain.QueueMessage) {
+	if msg == nil || !e.config.IsConfigured() || e.ctx.Err() != nil {
+		return
+	}
+	select {
+	case e.queue <- msg:
+		if e.dropping.Load() {
+			e.dropping.Store(false)
+		}
+	default:
+		droppedRecords.Inc()
+		if !e.dropping.Swap(true) {
+			logger.Warn("OTLP export queue is full, dropping trace messages", "capacity", queueSize)
+		}
+	}
+}
+
+// Close stops the exporter after a last attempt, bounded by a short timeout,
+// to export the messages still queued. It is safe to call more than once.
+func (e *Exporter) Close() {
+	e.closeOnce.Do(func() {
+		e.cancel()
+		<-e.done
+	})
+}
+
+// ==========================================
+// Batching
+// ==========================================
+
+func (e *Exporter) run() {
+	defer close(e.done)
+
+	var batch []*domain.QueueMessage
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+
+	flush := func() {
+		timer.Stop()
+		batch = e.flush(e.ctx, batch)
+	}
+
+	for {
+		select {
+		case <-e.ctx.Done():
+			timer.Stop()
+			e.shutdown(batch)
+			return
+		case msg := <-e.queue:
+			batch = append(batch, msg)
+			cfg := e.config
+			if !cfg.IsConfigured() || len(batch) >= cfg.BatchSize {
+				flush()
+			} else if len(batch) == 1 {
+				timer.Reset(cfg.FlushInterval)
+			}
+		case <-timer.C:
+			batch = e.flush(e.ctx, batch)
+		}
+	}
+}
+
+// shutdown drains the queue and exports what is left with a fresh deadline,
+// since the exporter's own context is already cancelled.
+func (e *Exporter) shutdown(batch []*domain.QueueMessage) {
+drain:
+	for {
+		select {
+		case msg := <-e.queue:
+			batch = append(batch, msg)
+		default:
+			break drain
+		}
+	}
+	if len(batch) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if rest := e.flush(ctx, batch); len(rest) > 0 {
+		droppedRecords.Add(uint64(len(rest)))
+		logger.Warn("OTLP export did not finish before shutdown", "dropped", len(rest))
+	}
+}
+
+// flush exports batch in chunks of the configured batch size. It returns the
+// messages it did not get to because ctx ended, so the caller can keep them
+// for the final flush; every other outcome consumes the batch.
+func (e *Exporter) flush(ctx context.Context, batch []*domain.QueueMessage) []*domain.QueueMessage {
+	if len(batch) == 0 {
+		return nil
+	}
+	cfg := e.config
+	if !cfg.IsConfigured() {
+		droppedRecords.Add(uint64(len(batch)))
+		return nil
+	}
+	for len(batch) > 0 {
+		n := min(len(batch), cfg.BatchSize)
+		if err := e.export(ctx, cfg, batch[:n]); err != nil {
+			if ctx.Err() != nil {
+				return batch
+			}
+			failedRecords.Add(uint64(n))
+			logger.Warn("OTLP export failed", "endpoint", cfg.LogsURL(), "records", n, "error", err)
+		}
+		batch = batch[n:]
+	}
+	return nil
+}
+
+// ==========================================
+// Export
+// ==========================================
+
+// retryableError marks a failure worth another try, with the delay the
+// collector asked for, if any.
+type retryableError struct {
+	err        error
+	retryAfter time.Duration
+}
+
+func (r *retryableError) Error() string { return r.err.Error() }
+func (r *retryableError) Unwrap() error { return r.err }
+
+// export posts one batch, retrying network errors and throttling responses.
+func (e *Exporter) export(ctx context.Context, cfg *domain.OTLPConfig, batch []*domain.QueueMessage) error {
+	body, err := encodeBatch(batch, time.Now())
+	if err != nil {
+		return fmt.Errorf("encode batch: %w", err)
+	}
+
+	start := time.Now()
+	defer func() { exportLatency.Observe(time.Since(start).Seconds()) }()
+
+	backoff := e.retry.initial
+	for attempt := 1; ; attempt++ {
+		err = e.post(ctx, cfg, body, len(batch))
+		var retryable *retryableError
+		if err == nil || !errors.As(err, &retryable) || attempt >= e.retry.attempts {
+			return err
+		}
+
+		delay := retryable.retryAfter
+		if delay <= 0 {
+			delay = jitter(backoff)
+			backoff = min(backoff*2, e.retry.max)
+		}
+		delay = min(delay, e.retry.max)
+		logger.Debug("retrying OTLP export", "attempt", attempt, "delay", delay, "error", err)
+		exportRetries.Inc()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// post sends body once and records the outcome of a successful request.
+func (e *Exporter) post(ctx context.Context, cfg *domain.OTLPConfig, body []byte, records int) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.LogsURL(), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range cfg.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return &retryableError{err: err}
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		rejected := partialSuccess(respBody)
+		exportedRecords.Add(uint64(records - rejected))
+		failedRecords.Add(uint64(rejected))
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests,
+		resp.StatusCode == http.StatusBadGateway,
+		resp.StatusCode == http.StatusServiceUnavailable,
+		resp.StatusCode == http.StatusGatewayTimeout:
+		return &retryableError{
+			err:        fmt.Errorf("collector returned %s", resp.Status),
+			retryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+	default:
+		return fmt.Errorf("collector returned %s: %s", resp.Status, bytes.TrimSpace(respBody))
+	}
+}
+
+// partialSuccess reads the rejected record count from an export response
+// and logs the collector's explanation. Malformed or empty bodies count as
+// full success, since the status code already said so.
+func partialSuccess(body []byte) int {
+	var resp struct {
+		PartialSuccess struct {
+			RejectedLogRecords json.Number `json:"rejectedLogRecords"`
+			ErrorMessage       string      `json:"errorMessage"`
+		} `json:"partialSuccess"`
+	}
+	if len(body) == 0 || json.Unmarshal(body, &resp) != nil {
+		return 0
+	}
+	rejected, _ := strconv.Atoi(resp.PartialSuccess.RejectedLogRecords.String())
+	if rejected > 0 || resp.PartialSuccess.ErrorMessage != "" {
+		logger.Warn("OTLP collector partially accepted a batch",
+			"rejected", rejected, "message", resp.PartialSuccess.ErrorMessage)
+	}
+	return max(rejected, 0)
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an
+// HTTP date. It returns zero when the header is absent or unusable.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0)
+	}
+	return 0
+}
+
+// jitter spreads d over [d/2, d) so exporters that failed together do not
+// retry together.
+func jitter(d time.Duration) time.Duration {
+	half := d / 2
+	if half <= 0 {
+		return d
+	}
+	return half + rand.N(half)
+}
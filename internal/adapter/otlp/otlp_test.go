@@ -0,0 +1,319 @@
+package otlp
+
+import (
+	"OmniView/internal/core/domain"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+var testTime = time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+
+func newTestMessage(t *testing.T, id string, level domain.LogLevel, process string) *domain.QueueMessage {
+	t.Helper()
+
+	msg, err := domain.NewQueueMessage(id, process, level, "payload "+id, testTime)
+	if err != nil {
+		t.Fatalf("NewQueueMessage: %v", err)
+	}
+	return msg
+}
+
+func newTestConfig(t *testing.T, endpoint string, batchSize int, flushInterval time.Duration) *domain.OTLPConfig {
+	t.Helper()
+
+	cfg, err := domain.NewOTLPConfig(endpoint, map[string]string{"api-key": "secret"}, true, batchSize, flushInterval)
+	if err != nil {
+		t.Fatalf("NewOTLPConfig: %v", err)
+	}
+	return cfg
+}
+
+// collector records the export requests it receives and answers each with
+// the next queued status, then 200 once the queue is empty.
+type collector struct {
+	mu       sync.Mutex
+	requests []exportLogsRequest
+	headers  []http.Header
+	statuses []int
+	received chan struct{}
+}
+
+func newCollector(t *testing.T, statuses ...int) (*collector, *httptest.Server) {
+	t.Helper()
+
+	c := &collector{statuses: statuses, received: make(chan struct{}, 64)}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/logs" || r.Header.Get("Content-Type") != "application/json" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		var req exportLogsRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		c.mu.Lock()
+		status := http.StatusOK
+		if len(c.statuses) > 0 {
+			status, c.statuses = c.statuses[0], c.statuses[1:]
+		}
+		if status == http.StatusOK {
+			c.requests = append(c.requests, req)
+			c.headers = append(c.headers, r.Header.Clone())
+		}
+		c.mu.Unlock()
+
+		if status == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", "0")
+		}
+		w.WriteHeader(status)
+		c.received <- struct{}{}
+	}))
+	t.Cleanup(srv.Close)
+	return c, srv
+}
+
+// records returns the number of log records accepted so far.
+func (c *collector) records() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for _, req := range c.requests {
+		for _, rl := range req.ResourceLogs {
+			for _, sl := range rl.ScopeLogs {
+				n += len(sl.LogRecords)
+			}
+		}
+	}
+	return n
+}
+
+// waitForRecords blocks until the collector has accepted n records.
+func (c *collector) waitForRecords(t *testing.T, n int) {
+	t.Helper()
+
+	deadline := time.After(5 * time.Second)
+	for c.records() < n {
+		select {
+		case <-c.received:
+		case <-deadline:
+			t.Fatalf("expected %d records, collector has %d", n, c.records())
+		}
+	}
+}
+
+func TestEncodeBatch_MapsMessagesToLogRecords(t *testing.T) {
+	t.Parallel()
+
+	order := newTestMessage(t, "m1", domain.LogLevelCritical, "ORDER_PKG")
+	order.SetAttributes(map[string]string{"order_id": "42"})
+	span, err := domain.NewSpanMarker("s1", "", "load", domain.SpanEventBegin)
+	if err != nil {
+		t.Fatalf("NewSpanMarker: %v", err)
+	}
+	order.SetSpan(span)
+	anonymous := newTestMessage(t, "m2", domain.LogLevelInfo, "")
+	again := newTestMessage(t, "m3", domain.LogLevelDebug, "ORDER_PKG")
+
+	data, err := encodeBatch([]*domain.QueueMessage{order, anonymous, again}, testTime.Add(time.Second))
+	if err != nil {
+		t.Fatalf("encodeBatch: %v", err)
+	}
+	var req exportLogsRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if len(req.ResourceLogs) != 2 {
+		t.Fatalf("expected one resource per process, got %d", len(req.ResourceLogs))
+	}
+	first := req.ResourceLogs[0]
+	if first.Resource.Attributes[0] != stringAttr("service.name", "ORDER_PKG") || first.ScopeLogs[0].Scope.Name != "ORDER_PKG" {
+		t.Fatalf("first resource = %+v", first)
+	}
+	if got := req.ResourceLogs[1].Resource.Attributes[0].Value.StringValue; got != unknownService {
+		t.Fatalf("expected a message without a process to use %q, got %q", unknownService, got)
+	}
+	if len(first.ScopeLogs[0].LogRecords) != 2 {
+		t.Fatalf("expected both ORDER_PKG messages under one scope, got %d", len(first.ScopeLogs[0].LogRecords))
+	}
+
+	record := first.ScopeLogs[0].LogRecords[0]
+	if record.SeverityNumber != 21 || record.SeverityText != "FATAL" || record.Body.StringValue != "payload m1" {
+		t.Fatalf("record = %+v", record)
+	}
+	if record.TimeUnixNano != "1772357400000000000" || record.ObservedTimeUnixNano != "1772357401000000000" {
+		t.Fatalf("expected the message time to be kept, got %s observed %s", record.TimeUnixNano, record.ObservedTimeUnixNano)
+	}
+	attrs := make(map[string]string)
+	for _, kv := range record.Attributes {
+		attrs[kv.Key] = kv.Value.StringValue
+	}
+	want := map[string]string{
+		"omniview.message_id": "m1",
+		"omniview.log_level":  "CRITICAL",
+		"omniview.span.id":    "s1",
+		"omniview.span.event": "BEGIN",
+		"omniview.span.name":  "load",
+		"order_id":            "42",
+	}
+	for key, value := range want {
+		if attrs[key] != value {
+			t.Errorf("attribute %s = %q, want %q", key, attrs[key], value)
+		}
+	}
+	if _, ok := attrs["omniview.mode"]; !ok {
+		t.Error("expected the delivery mode as an attribute")
+	}
+}
+
+func TestExporter_BatchesBySizeAndInterval(t *testing.T) {
+	t.Parallel()
+
+	c, srv := newCollector(t)
+	e := NewExporter(newTestConfig(t, srv.URL, 3, 100*time.Millisecond))
+	defer e.Close()
+
+	for _, id := range []string{"a", "b", "c", "d"} {
+		e.Publish(newTestMessage(t, id, domain.LogLevelInfo, "P"))
+	}
+	c.waitForRecords(t, 4)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.requests) != 2 {
+		t.Fatalf("expected a full batch of 3 and a timed batch of 1, got %d requests", len(c.requests))
+	}
+	if got := c.headers[0].Get("api-key"); got != "secret" {
+		t.Fatalf("expected the configured headers on every request, got api-key %q", got)
+	}
+}
+
+func TestExporter_RetriesThrottledExports(t *testing.T) {
+	t.Parallel()
+
+	c, srv := newCollector(t, http.StatusTooManyRequests, http.StatusServiceUnavailable)
+	e := newExporter(newTestConfig(t, srv.URL, 1, time.Second), retryPolicy{attempts: 3, initial: time.Millisecond, max: 10 * time.Millisecond})
+	defer e.Close()
+
+	e.Publish(newTestMessage(t, "a", domain.LogLevelError, "P"))
+	c.waitForRecords(t, 1)
+}
+
+func TestExporter_DoesNotRetryRejectedExports(t *testing.T) {
+	t.Parallel()
+
+	c, srv := newCollector(t, http.StatusBadRequest)
+	e := newExporter(newTestConfig(t, srv.URL, 1, time.Second), retryPolicy{attempts: 3, initial: time.Millisecond, max: 10 * time.Millisecond})
+
+	e.Publish(newTestMessage(t, "a", domain.LogLevelError, "P"))
+	<-c.received
+	e.Publish(newTestMessage(t, "b", domain.LogLevelError, "P"))
+	c.waitForRecords(t, 1)
+	e.Close()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if got := c.requests[0].ResourceLogs[0].ScopeLogs[0].LogRecords[0].Body.StringValue; got != "payload b" {
+		t.Fatalf("expected the rejected batch to be dropped, collector got %q", got)
+	}
+}
+
+func TestExporter_CloseFlushesPendingMessages(t *testing.T) {
+	t.Parallel()
+
+	c, srv := newCollector(t)
+	e := NewExporter(newTestConfig(t, srv.URL, 100, domain.MaxOTLPFlushInterval))
+	for _, id := range []string{"a", "b"} {
+		e.Publish(newTestMessage(t, id, domain.LogLevelInfo, "P"))
+	}
+	e.Close()
+	e.Close()
+
+	if got := c.records(); got != 2 {
+		t.Fatalf("expected Close to export the pending batch, collector has %d records", got)
+	}
+	e.Publish(newTestMessage(t, "c", domain.LogLevelInfo, "P"))
+}
+
+func TestExporter_IgnoresMessagesWhileDisabled(t *testing.T) {
+	t.Parallel()
+
+	_, srv := newCollector(t)
+	cfg := newTestConfig(t, srv.URL, 1, time.Second)
+	cfg.Enabled = false
+	e := NewExporter(cfg)
+	defer e.Close()
+
+	e.Publish(newTestMessage(t, "a", domain.LogLevelInfo, "P"))
+	if len(e.queue) != 0 {
+		t.Fatal("expected a disabled exporter not to queue messages")
+	}
+}
+
+func TestNewSink_ExportsWithHeaderSettings(t *testing.T) {
+	t.Parallel()
+
+	c, srv := newCollector(t)
+	cfg, err := domain.NewSinkConfig("otel", "otlp", map[string]string{
+		"endpoint": srv.URL, "batch_size": "1", "header.x-team": "ops",
+	}, true)
+	if err != nil {
+		t.Fatalf("NewSinkConfig: %v", err)
+	}
+	sink, err := NewSink(*cfg)
+	if err != nil {
+		t.Fatalf("NewSink: %v", err)
+	}
+	if err := sink.Write(context.Background(), newTestMessage(t, "a", domain.LogLevelInfo, "P")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	c.waitForRecords(t, 1)
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if got := c.headers[0].Get("X-Team"); got != "ops" {
+		t.Fatalf("X-Team header = %q, want ops", got)
+	}
+
+	cfg.Settings["retry_attempts"] = "0"
+	if _, err := NewSink(*cfg); !errors.Is(err, domain.ErrInvalidSink) {
+		t.Fatalf("NewSink with zero retry attempts = %v, want ErrInvalidSink", err)
+	}
+	cfg.Settings["retry_attempts"] = "2"
+	cfg.Settings["flush_interval"] = "soon"
+	if _, err := NewSink(*cfg); !errors.Is(err, domain.ErrInvalidSink) {
+		t.Fatalf("NewSink with a bad flush interval = %v, want ErrInvalidSink", err)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	cases := map[string]time.Duration{
+		"":                              0,
+		"7":                             7 * time.Second,
+		"-3":                            0,
+		"soon":                          0,
+		"Sun, 01 Mar 2026 09:30:20 GMT": 20 * time.Second,
+		"Sun, 01 Mar 2026 09:29:00 GMT": 0,
+	}
+	for value, want := range cases {
+		if got := parseRetryAfter(value, now); got != want {
+			t.Errorf("parseRetryAfter(%q) = %s, want %s", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package otlp
+
+import (
+	"OmniView/internal/core/domain"
+	"OmniView/internal/core/ports"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// headerSettingPrefix marks sink settings that become request headers, e.g.
+// header.api-key=secret.
+const headerSettingPrefix = "header."
+
+// Sink sends routed messages to a collector through its own Exporter, so a
+// routing rule can pick which messages reach which collector.
+type Sink struct {
+	exporter *Exporter
+}
+
+// NewSink is the ports.SinkFactory for "otlp" sinks. Settings: endpoint
+// (required), batch_size, flush_interval as a Go duration, retry_attempts
+// (tries per batch, the first included) and a header.<name> entry per
+// request header.
+func NewSink(cfg domain.SinkConfig) (ports.MessageSink, error) {
+	headers := make(map[string]string)
+	for key, value := range cfg.Settings {
+		if name, ok := strings.CutPrefix(key, headerSettingPrefix); ok {
+			headers[name] = value
+		}
+	}
+	var batchSize int
+	if s := cfg.Setting("batch_size"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, fmt.Errorf("%w: batch_size must be a number", domain.ErrInvalidSink)
+		}
+		batchSize = n
+	}
+	var flushInterval time.Duration
+	if s := cfg.Setting("flush_interval"); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("%w: flush_interval must be a duration such as 5s", domain.ErrInvalidSink)
+		}
+		flushInterval = d
+	}
+
+	retry := defaultRetryPolicy
+	if s := cfg.Setting("retry_attempts"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > domain.MaxOTLPRetryAttempts {
+			return nil, fmt.Errorf("%w: retry_attempts must be between 1 and %d", domain.ErrInvalidSink, domain.MaxOTLPRetryAttempts)
+		}
+		retry.attempts = n
+	}
+
+	otlpConfig, err := domain.NewOTLPConfig(cfg.Setting("endpoint"), headers, true, batchSize, flushInterval)
+	if err != nil {
+		return nil, err
+	}
+	return &Sink{exporter: newExporter(otlpConfig, retry)}, nil
+}
+
+// Write queues msg on the exporter. Delivery failures are counted by the
+// exporter's own metrics rather than returned here.
+func (s *Sink) Write(_ context.Context, msg *domain.QueueMessage) error {
+	s.exporter.Publish(msg)
+	return nil
+}
+
+// Close flushes and stops the exporter.
+func (s *Sink) Close() error {
+	s.exporter.Close()
+	return nil
+}
@@ -0,0 +1,113 @@
+package sink
+
+import (
+	"OmniView/internal/core/domain"
+	"OmniView/internal/core/ports"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ==========================================
+// File Sink
+// ==========================================
+
+const (
+	fileFormatJSON = "json" // One QueueMessage JSON object per line (NDJSON)
+	fileFormatText = "text" // One human-readable line per message
+)
+
+// FileSink appends each message to a file as one line. The file is opened
+// on the first write and created with owner-only permissions, since trace
+// payloads can hold application data.
+type FileSink struct {
+	path   string
+	format string
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileSink is the ports.SinkFactory for "file" sinks. Settings:
+// path (required) and format, json (default) or text.
+func NewFileSink(cfg domain.SinkConfig) (ports.MessageSink, error) {
+	path := cfg.Setting("path")
+	if path == "" {
+		return nil, fmt.Errorf("%w: file sink needs a path", domain.ErrInvalidSink)
+	}
+	format := strings.ToLower(cfg.Setting("format"))
+	switch format {
+	case "":
+		format = fileFormatJSON
+	case fileFormatJSON, fileFormatText:
+	default:
+		return nil, fmt.Errorf("%w: file format must be json or text, got %q", domain.ErrInvalidSink, format)
+	}
+	return &FileSink{path: filepath.Clean(path), format: format}, nil
+}
+
+// Write appends msg to the file.
+func (s *FileSink) Write(ctx context.Context, msg *domain.QueueMessage) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	line, err := s.formatLine(msg)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			return fmt.Errorf("open %s: %w", s.path, err)
+		}
+		s.file = file
+	}
+	if _, err := s.file.Write(line); err != nil {
+		return fmt.Errorf("write %s: %w", s.path, err)
+	}
+	return nil
+}
+
+// Close closes the file if it was opened.
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// formatLine renders msg with its trailing newline. Text lines escape line
+// breaks in the payload so every message stays on one line.
+func (s *FileSink) formatLine(msg *domain.QueueMessage) ([]byte, error) {
+	if s.format == fileFormatJSON {
+		data, err := json.Marshal(msg)
+		if err != nil {
+			return nil, fmt.Errorf("marshal message %s: %w", msg.MessageID(), err)
+		}
+		return append(data, '\n'), nil
+	}
+
+	var b strings.Builder
+	b.WriteString(escapeLineBreaks(msg.Format()))
+	for _, key := range msg.AttributeKeys() {
+		value, _ := msg.Attribute(key)
+		fmt.Fprintf(&b, " %s=%s", key, escapeLineBreaks(value))
+	}
+	b.WriteByte('\n')
+	return []byte(b.String()), nil
+}
+
+func escapeLineBreaks(s string) string {
+	return strings.NewReplacer("\r\n", `\n`, "\n", `\n`, "\r", `\r`).Replace(s)
+}
@@ -0,0 +1,37 @@
+// Package sink implements the built-in output sink plug-ins: append-only
+// files and syslog servers. The OTLP plug-in lives with the OTLP exporter and
+// the webhook plug-in with the webhook dispatcher in the tracer service.
+package sink
+
+import (
+	"OmniView/internal/adapter/otlp"
+	"OmniView/internal/core/domain"
+	"OmniView/internal/core/ports"
+	"OmniView/internal/service/sinks"
+	"OmniView/internal/service/tracer"
+)
+
+// Register adds the built-in plug-ins to r. Webhook sinks look webhooks up
+// in configs.
+func Register(r *sinks.Registry, configs ports.ConfigRepository) {
+	r.Register(sinks.Plugin{
+		Type:    "file",
+		Example: "path=/var/log/omniview.ndjson,format=json",
+		Open:    NewFileSink,
+	})
+	r.Register(sinks.Plugin{
+		Type:    "syslog",
+		Example: "address=localhost:514,network=udp,facility=local0,tag=omniview",
+		Open:    NewSyslogSink,
+	})
+	r.Register(sinks.Plugin{
+		Type:    domain.OTLPSinkType,
+		Example: "endpoint=http://localhost:4318,header.api-key=secret",
+		Open:    otlp.NewSink,
+	})
+	r.Register(sinks.Plugin{
+		Type:    domain.WebhookSinkType,
+		Example: "webhook=ops",
+		Open:    tracer.NewWebhookSinkFactory(configs),
+	})
+}
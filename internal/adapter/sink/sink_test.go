@@ -0,0 +1,197 @@
+package sink
+
+import (
+	"OmniView/internal/core/domain"
+	"OmniView/internal/service/sinks"
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func mustNewSinkMessage(t *testing.T, level domain.LogLevel, payload string) *domain.QueueMessage {
+	t.Helper()
+	msg, err := domain.NewQueueMessage("42", "ORDER_API", level, payload, time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("NewQueueMessage: %v", err)
+	}
+	return msg
+}
+
+func mustNewSinkConfig(t *testing.T, sinkType string, settings map[string]string) domain.SinkConfig {
+	t.Helper()
+	cfg, err := domain.NewSinkConfig("test", sinkType, settings, true)
+	if err != nil {
+		t.Fatalf("NewSinkConfig: %v", err)
+	}
+	return *cfg
+}
+
+func TestFileSink_AppendsJSONAndTextLines(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for _, format := range []string{"json", "text"} {
+		path := filepath.Join(dir, format+".log")
+		sink, err := NewFileSink(mustNewSinkConfig(t, "file", map[string]string{"path": path, "format": format}))
+		if err != nil {
+			t.Fatalf("NewFileSink(%s): %v", format, err)
+		}
+		if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("expected the %s file to be created on first write, stat = %v", format, err)
+		}
+		for _, payload := range []string{"first", "second\nline"} {
+			if err := sink.Write(context.Background(), mustNewSinkMessage(t, domain.LogLevelError, payload)); err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+		}
+		if err := sink.Close(); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("ReadFile: %v", err)
+		}
+		lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("%s file has %d lines, want 2:\n%s", format, len(lines), data)
+		}
+		if format == "json" {
+			var decoded domain.QueueMessage
+			if err := json.Unmarshal([]byte(lines[1]), &decoded); err != nil || decoded.Payload() != "second\nline" {
+				t.Fatalf("decoded %q: payload %q, err %v", lines[1], decoded.Payload(), err)
+			}
+		} else if !strings.Contains(lines[1], `second\nline`) {
+			t.Fatalf("text line %q, want the line break escaped", lines[1])
+		}
+	}
+}
+
+func TestSinkFactories_RejectInvalidSettings(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		sinkType string
+		settings map[string]string
+	}{
+		{"file", nil},
+		{"file", map[string]string{"path": "/tmp/x", "format": "xml"}},
+		{"syslog", map[string]string{"address": "no-port"}},
+		{"syslog", map[string]string{"address": "h:514", "network": "unix"}},
+		{"syslog", map[string]string{"address": "h:514", "facility": "kern2"}},
+		{"syslog", map[string]string{"address": "h:514", "tag": "has space"}},
+	}
+	registry := sinks.NewRegistry()
+	Register(registry, nil)
+	for _, tc := range cases {
+		if err := registry.Validate(mustNewSinkConfig(t, tc.sinkType, tc.settings)); !errors.Is(err, domain.ErrInvalidSink) {
+			t.Errorf("Validate(%s %v) = %v, want ErrInvalidSink", tc.sinkType, tc.settings, err)
+		}
+	}
+}
+
+func TestSyslogSink_SendsRFC5424OverUDP(t *testing.T) {
+	t.Parallel()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenPacket: %v", err)
+	}
+	defer conn.Close()
+
+	sink, err := NewSyslogSink(mustNewSinkConfig(t, "syslog", map[string]string{
+		"address": conn.LocalAddr().String(), "facility": "local0", "tag": "trace",
+	}))
+	if err != nil {
+		t.Fatalf("NewSyslogSink: %v", err)
+	}
+	defer sink.Close()
+	if err := sink.Write(context.Background(), mustNewSinkMessage(t, domain.LogLevelError, `bad "quote"]`)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	buf := make([]byte, 2048)
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("ReadFrom: %v", err)
+	}
+	got := string(buf[:n])
+	// local0 (16) * 8 + error (3) = 131
+	if !strings.HasPrefix(got, "<131>1 2026-03-04T05:06:07.000000Z ") {
+		t.Fatalf("message %q, want the local0.err priority and an RFC 3339 timestamp", got)
+	}
+	if !strings.Contains(got, ` trace `+strconv.Itoa(os.Getpid())+` - [omniview@32473 level="ERROR" process="ORDER_API" message_id="42"`) {
+		t.Fatalf("message %q, want the tag, PID and structured data", got)
+	}
+	if !strings.HasSuffix(got, `] bad "quote"]`) {
+		t.Fatalf("message %q, want the payload after the structured data", got)
+	}
+}
+
+func TestSyslogSink_FramesTCPAndRedials(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	defer ln.Close()
+
+	frames := make(chan string, 4)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			// Read one frame per connection, then hang up so the sink has
+			// to reconnect for the next message.
+			r := bufio.NewReader(conn)
+			length, err := r.ReadString(' ')
+			if err == nil {
+				n, _ := strconv.Atoi(strings.TrimSpace(length))
+				buf := make([]byte, n)
+				if _, err := io.ReadFull(r, buf); err == nil {
+					frames <- string(buf)
+				}
+			}
+			conn.Close()
+		}
+	}()
+
+	sink, err := NewSyslogSink(mustNewSinkConfig(t, "syslog", map[string]string{"address": ln.Addr().String(), "network": "tcp"}))
+	if err != nil {
+		t.Fatalf("NewSyslogSink: %v", err)
+	}
+	defer sink.Close()
+
+	for _, payload := range []string{"one\ntwo", "three"} {
+		// The first write after the server hangs up can still succeed
+		// locally, so allow a few attempts for the broken pipe to surface.
+		var got string
+		deadline := time.Now().Add(2 * time.Second)
+		for got == "" && time.Now().Before(deadline) {
+			if err := sink.Write(context.Background(), mustNewSinkMessage(t, domain.LogLevelInfo, payload)); err != nil {
+				t.Fatalf("Write(%q): %v", payload, err)
+			}
+			select {
+			case frame := <-frames:
+				got = frame
+			case <-time.After(200 * time.Millisecond):
+			}
+		}
+		if !strings.HasSuffix(got, "] "+payload) {
+			t.Fatalf("frame %q, want the payload %q intact", got, payload)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package sink
+
+import (
+	"OmniView/internal/core/domain"
+	"OmniView/internal/core/ports"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ==========================================
+// Syslog Sink
+// ==========================================
+// Writes RFC 5424 messages over UDP or TCP. The standard library's log/syslog
+// only speaks the older BSD format and does not build on Windows, so the
+// message is formatted here.
+
+const (
+	// syslogDialTimeout bounds connecting to the server and each write.
+	syslogDialTimeout = 5 * time.Second
+
+	// syslogSDID is the structured data element carrying the trace fields.
+	// 32473 is the enterprise number reserved for documentation (RFC 5612).
+	syslogSDID = "omniview@32473"
+
+	syslogTimeLayout = "2006-01-02T15:04:05.000000Z07:00"
+	maxSyslogTag     = 48 // APP-NAME limit in RFC 5424
+)
+
+// syslogFacilities maps facility names to their codes.
+var syslogFacilities = map[string]int{
+	"user": 1, "daemon": 3,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// syslogSeverity maps a log level to its RFC 5424 severity.
+func syslogSeverity(level domain.LogLevel) int {
+	switch level {
+	case domain.LogLevelDebug:
+		return 7
+	case domain.LogLevelInfo:
+		return 6
+	case domain.LogLevelWarning:
+		return 4
+	case domain.LogLevelError:
+		return 3
+	case domain.LogLevelCritical:
+		return 2
+	default:
+		return 5
+	}
+}
+
+// SyslogSink sends each message to a syslog server.
+type SyslogSink struct {
+	network  string
+	address  string
+	facility int
+	tag      string
+	hostname string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewSyslogSink is the ports.SinkFactory for "syslog" sinks. Settings:
+// address host:port (required), network udp (default) or tcp, facility
+// (user by default, or daemon or local0-local7) and tag (default omniview).
+func NewSyslogSink(cfg domain.SinkConfig) (ports.MessageSink, error) {
+	address := cfg.Setting("address")
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		return nil, fmt.Errorf("%w: syslog address must be host:port: %v", domain.ErrInvalidSink, err)
+	}
+	network := strings.ToLower(cfg.Setting("network"))
+	switch network {
+	case "":
+		network = "udp"
+	case "udp", "tcp":
+	default:
+		return nil, fmt.Errorf("%w: syslog network must be udp or tcp, got %q", domain.ErrInvalidSink, network)
+	}
+	facilityName := strings.ToLower(cfg.Setting("facility"))
+	if facilityName == "" {
+		facilityName = "user"
+	}
+	facility, ok := syslogFacilities[facilityName]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown syslog facility %q", domain.ErrInvalidSink, facilityName)
+	}
+	tag := cfg.Setting("tag")
+	if tag == "" {
+		tag = "omniview"
+	}
+	if !isPrintableASCII(tag) || len(tag) > maxSyslogTag {
+		return nil, fmt.Errorf("%w: syslog tag must be 1-%d printable characters without spaces", domain.ErrInvalidSink, maxSyslogTag)
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || !isPrintableASCII(hostname) {
+		hostname = "-"
+	}
+	return &SyslogSink{
+		network:  network,
+		address:  address,
+		facility: facility,
+		tag:      tag,
+		hostname: hostname,
+	}, nil
+}
+
+// Write sends msg, connecting on first use. A failed write on an existing
+// connection is retried once on a new one, since the server may have
+// restarted.
+func (s *SyslogSink) Write(ctx context.Context, msg *domain.QueueMessage) error {
+	frame := s.frame(s.format(msg))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	reused := s.conn != nil
+	err := s.send(ctx, frame)
+	if err != nil && reused {
+		err = s.send(ctx, frame)
+	}
+	return err
+}
+
+// Close closes the connection if one is open.
+func (s *SyslogSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// send writes frame on the connection, dialling first if needed. The
+// connection is dropped after a failure.
+func (s *SyslogSink) send(ctx context.Context, frame []byte) error {
+	if s.conn == nil {
+		dialer := net.Dialer{Timeout: syslogDialTimeout}
+		conn, err := dialer.DialContext(ctx, s.network, s.address)
+		if err != nil {
+			return fmt.Errorf("dial syslog %s/%s: %w", s.network, s.address, err)
+		}
+		s.conn = conn
+	}
+	err := s.conn.SetWriteDeadline(time.Now().Add(syslogDialTimeout))
+	if err == nil {
+		_, err = s.conn.Write(frame)
+	}
+	if err != nil {
+		s.conn.Close()
+		s.conn = nil
+		return fmt.Errorf("write syslog %s/%s: %w", s.network, s.address, err)
+	}
+	return nil
+}
+
+// format renders msg as an RFC 5424 message:
+// <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID [SD] MSG
+func (s *SyslogSink) format(msg *domain.QueueMessage) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<%d>1 %s %s %s %d - [%s", s.facility*8+syslogSeverity(msg.LogLevel()),
+		msg.Timestamp().Format(syslogTimeLayout), s.hostname, s.tag, os.Getpid(), syslogSDID)
+	writeSDParam(&b, "level", string(msg.LogLevel()))
+	writeSDParam(&b, "process", msg.ProcessName())
+	writeSDParam(&b, "message_id", msg.MessageID())
+	writeSDParam(&b, "mode", msg.Mode())
+	b.WriteString("] ")
+	b.WriteString(msg.Payload())
+	return b.String()
+}
+
+// frame prepares a formatted message for the transport. TCP uses octet
+// counting (RFC 6587) so payloads may contain line breaks; UDP sends one
+// message per datagram.
+func (s *SyslogSink) frame(message string) []byte {
+	if s.network == "tcp" {
+		return []byte(strconv.Itoa(len(message)) + " " + message)
+	}
+	return []byte(message)
+}
+
+// writeSDParam appends a structured data parameter, escaping '"', '\' and
+// ']' as RFC 5424 requires. Empty values are left out.
+func writeSDParam(b *strings.Builder, name, value string) {
+	if value == "" {
+		return
+	}
+	fmt.Fprintf(b, ` %s="%s"`, name, strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(value))
+}
+
+// isPrintableASCII reports whether s is a non-empty run of the characters
+// RFC 5424 allows in header fields.
+func isPrintableASCII(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < 33 || s[i] > 126 {
+			return false
+		}
+	}
+	return true
+}
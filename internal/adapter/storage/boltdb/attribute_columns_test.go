@@ -0,0 +1,52 @@
+package boltdb
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// TestBoltAdapter_AttributeColumns_PerDatabaseRoundTrip verifies that attribute
+// columns keep their order per database ID and that an empty list removes them.
+func TestBoltAdapter_AttributeColumns_PerDatabaseRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	adapter := newTestBoltAdapter(t)
+
+	if got, err := adapter.GetAttributeColumns("DEV"); err != nil || got != nil {
+		t.Fatalf("GetAttributeColumns on empty store = %v, %v; want nil", got, err)
+	}
+
+	if err := adapter.SetAttributeColumns("DEV", []string{"tenant", "order_id"}); err != nil {
+		t.Fatalf("SetAttributeColumns(DEV): %v", err)
+	}
+	if err := adapter.SetAttributeColumns("PROD", []string{"region"}); err != nil {
+		t.Fatalf("SetAttributeColumns(PROD): %v", err)
+	}
+	if got, _ := adapter.GetAttributeColumns("DEV"); !slices.Equal(got, []string{"tenant", "order_id"}) {
+		t.Fatalf("GetAttributeColumns(DEV) = %v", got)
+	}
+	if got, _ := adapter.GetAttributeColumns("PROD"); !slices.Equal(got, []string{"region"}) {
+		t.Fatalf("GetAttributeColumns(PROD) = %v", got)
+	}
+
+	if err := adapter.SetAttributeColumns("DEV", nil); err != nil {
+		t.Fatalf("SetAttributeColumns(DEV, nil): %v", err)
+	}
+	if got, _ := adapter.GetAttributeColumns("DEV"); got != nil {
+		t.Fatalf("expected cleared columns, got %v", got)
+	}
+}
+
+// TestBoltAdapter_AttributeColumns_UninitializedDB verifies the sentinel error on an unopened adapter.
+func TestBoltAdapter_AttributeColumns_UninitializedDB(t *testing.T) {
+	t.Parallel()
+
+	adapter := &BoltAdapter{}
+	if _, err := adapter.GetAttributeColumns("DEV"); !errors.Is(err, ErrAdapterNotInitialized) {
+		t.Fatalf("GetAttributeColumns error = %v, want ErrAdapterNotInitialized", err)
+	}
+	if err := adapter.SetAttributeColumns("DEV", []string{"tenant"}); !errors.Is(err, ErrAdapterNotInitialized) {
+		t.Fatalf("SetAttributeColumns error = %v, want ErrAdapterNotInitialized", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"OmniView/internal/adapter/security/credcipher"
 	"OmniView/internal/core/domain"
 	"OmniView/internal/core/ports"
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -27,6 +28,9 @@ const (
 	DefaultWebhookKey          = "webhook:default"
 	TracerPackageVersionKey    = "tracer:package_version"
 	BroadcastModeKey           = "client:broadcast_mode"
+	HistoryRetentionKey        = "client:history_retention"
+	TraceFilterKeyPrefix       = "client:trace_filter:"
+	AttributeColumnsKeyPrefix  = "client:attribute_columns:"
 )
 
 // BoltAdapter implements the ports.ConfigRepository
@@ -57,6 +61,7 @@ func (ba *BoltAdapter) Initialize() error {
 
 	// Initialize buckets
 	if err := ba.db.Update(func(tx *bolt.Tx) error {
+		newSinkStore := tx.Bucket([]byte(SinkBucket)) == nil
 		if _, err := tx.CreateBucketIfNotExists([]byte(DatabaseConfigBucket)); err != nil {
 			return fmt.Errorf("failed to create bucket: %w", err)
 		}
@@ -72,6 +77,23 @@ func (ba *BoltAdapter) Initialize() error {
 		if _, err := tx.CreateBucketIfNotExists([]byte(WebhookConfigBucket)); err != nil {
 			return fmt.Errorf("failed to create bucket: %w", err)
 		}
+		if _, err := tx.CreateBucketIfNotExists([]byte(TraceHistoryBucket)); err != nil {
+			return fmt.Errorf("failed to create bucket: %w", err)
+		}
+		if _, err := tx.CreateBucketIfNotExists([]byte(SinkBucket)); err != nil {
+			return fmt.Errorf("failed to create bucket: %w", err)
+		}
+		if _, err := tx.CreateBucketIfNotExists([]byte(RoutingRuleBucket)); err != nil {
+			return fmt.Errorf("failed to create bucket: %w", err)
+		}
+		if _, err := tx.CreateBucketIfNotExists([]byte(WebhookDeadLetterBucket)); err != nil {
+			return fmt.Errorf("failed to create bucket: %w", err)
+		}
+		if newSinkStore {
+			if err := seedWebhookRoute(tx); err != nil {
+				return fmt.Errorf("failed to seed webhook route: %w", err)
+			}
+		}
 		return nil
 	}); err != nil {
 		_ = ba.db.Close()
@@ -350,8 +372,8 @@ func (ba *BoltAdapter) SaveWebhookConfig(config *domain.WebhookConfig) error {
 			return fmt.Errorf("failed to save webhook config: %v", err)
 		}
 
-		// If this is the default webhook, update default key
-		if config.ID == domain.DefaultWebhookID {
+		// The first webhook saved becomes the default until another is chosen
+		if b.Get([]byte(DefaultWebhookKey)) == nil {
 			if err := b.Put([]byte(DefaultWebhookKey), []byte(config.ID)); err != nil {
 				return fmt.Errorf("failed to set default webhook: %v", err)
 			}
@@ -401,6 +423,56 @@ func (ba *BoltAdapter) GetWebhookConfig() (*domain.WebhookConfig, error) {
 	return config, nil
 }
 
+// ListWebhookConfigs returns every stored webhook, ordered by ID
+func (ba *BoltAdapter) ListWebhookConfigs() ([]domain.WebhookConfig, error) {
+	if ba.db == nil {
+		return nil, fmt.Errorf("boltAdapter not initialized")
+	}
+
+	var configs []domain.WebhookConfig
+	err := ba.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(WebhookConfigBucket))
+		if b == nil {
+			return fmt.Errorf("bucket %s not found", WebhookConfigBucket)
+		}
+		return b.ForEach(func(k, v []byte) error {
+			// Pointer keys such as DefaultWebhookKey share the bucket; webhook IDs cannot contain ':'
+			if bytes.ContainsRune(k, ':') {
+				return nil
+			}
+			var config domain.WebhookConfig
+			if err := json.Unmarshal(v, &config); err != nil {
+				return fmt.Errorf("failed to unmarshal webhook config %s: %v", k, err)
+			}
+			configs = append(configs, config)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return configs, nil
+}
+
+// SetDefaultWebhook makes the webhook with the given ID the one used for
+// messages that do not name a target
+func (ba *BoltAdapter) SetDefaultWebhook(id string) error {
+	if ba.db == nil {
+		return fmt.Errorf("boltAdapter not initialized")
+	}
+
+	return ba.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(WebhookConfigBucket))
+		if b == nil {
+			return fmt.Errorf("bucket %s not found", WebhookConfigBucket)
+		}
+		if strings.ContainsRune(id, ':') || b.Get([]byte(id)) == nil {
+			return fmt.Errorf("SetDefaultWebhook %s: %w", id, domain.ErrWebhookConfigNotFound)
+		}
+		return b.Put([]byte(DefaultWebhookKey), []byte(id))
+	})
+}
+
 // GetTracerPackageVersion retrieves the stored package version hash.
 func (ba *BoltAdapter) GetTracerPackageVersion() (string, error) {
 	if ba.db == nil {
@@ -503,6 +575,157 @@ func (ba *BoltAdapter) SetBroadcastMode(mode domain.BroadcastMode) error {
 	})
 }
 
+// GetHistoryRetention retrieves the stored trace history retention.
+// Returns domain.DefaultHistoryRetention when no value has been stored yet.
+func (ba *BoltAdapter) GetHistoryRetention() (domain.HistoryRetention, error) {
+	if ba.db == nil {
+		return domain.DefaultHistoryRetention(), fmt.Errorf("GetHistoryRetention: %w", ErrAdapterNotInitialized)
+	}
+
+	retention := domain.DefaultHistoryRetention()
+	err := ba.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(ClientConfigBucket))
+		if b == nil {
+			return fmt.Errorf("bucket %s not found", ClientConfigBucket)
+		}
+		val := b.Get([]byte(HistoryRetentionKey))
+		if val == nil {
+			return nil
+		}
+		var stored domain.HistoryRetention
+		if err := json.Unmarshal(val, &stored); err != nil {
+			return fmt.Errorf("failed to unmarshal history retention: %w", err)
+		}
+		validated, err := domain.NewHistoryRetention(stored.MaxAge, stored.MaxBytes)
+		if err != nil {
+			return err
+		}
+		retention = validated
+		return nil
+	})
+	if err != nil {
+		return domain.DefaultHistoryRetention(), fmt.Errorf("GetHistoryRetention: %w", err)
+	}
+	return retention, nil
+}
+
+// SetHistoryRetention stores the trace history retention.
+func (ba *BoltAdapter) SetHistoryRetention(retention domain.HistoryRetention) error {
+	if ba.db == nil {
+		return fmt.Errorf("boltAdapter not initialized")
+	}
+
+	data, err := json.Marshal(retention)
+	if err != nil {
+		return fmt.Errorf("failed to marshal history retention: %w", err)
+	}
+
+	return ba.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(ClientConfigBucket))
+		if b == nil {
+			return fmt.Errorf("bucket %s not found", ClientConfigBucket)
+		}
+		return b.Put([]byte(HistoryRetentionKey), data)
+	})
+}
+
+// GetTraceFilter retrieves the main-screen filter expression stored for databaseID.
+// Returns an empty string when no filter has been stored.
+func (ba *BoltAdapter) GetTraceFilter(databaseID string) (string, error) {
+	if ba.db == nil {
+		return "", fmt.Errorf("GetTraceFilter: %w", ErrAdapterNotInitialized)
+	}
+
+	var expr string
+	err := ba.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(ClientConfigBucket))
+		if b == nil {
+			return fmt.Errorf("bucket %s not found", ClientConfigBucket)
+		}
+		expr = string(b.Get([]byte(TraceFilterKeyPrefix + databaseID)))
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("GetTraceFilter: %w", err)
+	}
+	return expr, nil
+}
+
+// SetTraceFilter stores the filter expression for databaseID. An empty
+// expression removes the stored filter.
+func (ba *BoltAdapter) SetTraceFilter(databaseID, expr string) error {
+	if ba.db == nil {
+		return fmt.Errorf("boltAdapter not initialized")
+	}
+	if strings.TrimSpace(databaseID) == "" {
+		return fmt.Errorf("SetTraceFilter: %w", domain.ErrEmptyDatabaseID)
+	}
+
+	return ba.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(ClientConfigBucket))
+		if b == nil {
+			return fmt.Errorf("bucket %s not found", ClientConfigBucket)
+		}
+		key := []byte(TraceFilterKeyPrefix + databaseID)
+		if strings.TrimSpace(expr) == "" {
+			return b.Delete(key)
+		}
+		return b.Put(key, []byte(expr))
+	})
+}
+
+// GetAttributeColumns retrieves the message attributes shown as trace columns
+// for databaseID. Returns nil when none have been stored.
+func (ba *BoltAdapter) GetAttributeColumns(databaseID string) ([]string, error) {
+	if ba.db == nil {
+		return nil, fmt.Errorf("GetAttributeColumns: %w", ErrAdapterNotInitialized)
+	}
+
+	var columns []string
+	err := ba.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(ClientConfigBucket))
+		if b == nil {
+			return fmt.Errorf("bucket %s not found", ClientConfigBucket)
+		}
+		data := b.Get([]byte(AttributeColumnsKeyPrefix + databaseID))
+		if data == nil {
+			return nil
+		}
+		return json.Unmarshal(data, &columns)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("GetAttributeColumns: %w", err)
+	}
+	return columns, nil
+}
+
+// SetAttributeColumns stores the attribute columns for databaseID in display
+// order. An empty list removes the stored columns.
+func (ba *BoltAdapter) SetAttributeColumns(databaseID string, columns []string) error {
+	if ba.db == nil {
+		return fmt.Errorf("SetAttributeColumns: %w", ErrAdapterNotInitialized)
+	}
+	if strings.TrimSpace(databaseID) == "" {
+		return fmt.Errorf("SetAttributeColumns: %w", domain.ErrEmptyDatabaseID)
+	}
+
+	return ba.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(ClientConfigBucket))
+		if b == nil {
+			return fmt.Errorf("bucket %s not found", ClientConfigBucket)
+		}
+		key := []byte(AttributeColumnsKeyPrefix + databaseID)
+		if len(columns) == 0 {
+			return b.Delete(key)
+		}
+		data, err := json.Marshal(columns)
+		if err != nil {
+			return fmt.Errorf("failed to marshal attribute columns: %w", err)
+		}
+		return b.Put(key, data)
+	})
+}
+
 // HasEncryptedCredentials checks if this BoltDB instance contains any credentials
 // encrypted via the current format. The detection delegates to
 // credcipher.ContainsEncryptedTokenInJSON so the wire-format marker stays
@@ -2,6 +2,7 @@ package boltdb
 
 import (
 	"OmniView/internal/adapter/security/credcipher"
+	"OmniView/internal/core/domain"
 	"errors"
 	"path/filepath"
 	"testing"
@@ -91,3 +92,48 @@ func TestBoltAdapter_HasEncryptedCredentials(t *testing.T) {
 		}
 	})
 }
+
+func TestBoltAdapter_NamedWebhooksAndDefault(t *testing.T) {
+	t.Parallel()
+
+	adapter := newTestBoltAdapter(t)
+	for _, id := range []string{"ops", domain.DefaultWebhookID, "audit"} {
+		config, err := domain.NewWebhookConfig(id, "https://example.com/"+id, true)
+		if err != nil {
+			t.Fatalf("NewWebhookConfig: %v", err)
+		}
+		if err := adapter.SaveWebhookConfig(config); err != nil {
+			t.Fatalf("SaveWebhookConfig(%s): %v", id, err)
+		}
+	}
+
+	webhooks, err := adapter.ListWebhookConfigs()
+	if err != nil {
+		t.Fatalf("ListWebhookConfigs: %v", err)
+	}
+	if len(webhooks) != 3 || webhooks[0].ID != "audit" || webhooks[1].ID != "default" || webhooks[2].ID != "ops" {
+		t.Fatalf("expected the three webhooks in ID order without the pointer key, got %+v", webhooks)
+	}
+
+	config, err := adapter.GetWebhookConfig()
+	if err != nil || config.ID != "ops" {
+		t.Fatalf("GetWebhookConfig() = %+v, %v; want the first saved webhook", config, err)
+	}
+
+	if err := adapter.SetDefaultWebhook("audit"); err != nil {
+		t.Fatalf("SetDefaultWebhook: %v", err)
+	}
+	if config, err := adapter.GetWebhookConfig(); err != nil || config.ID != "audit" {
+		t.Fatalf("GetWebhookConfig() = %+v, %v; want audit", config, err)
+	}
+	if err := adapter.SetDefaultWebhook("missing"); !errors.Is(err, domain.ErrWebhookConfigNotFound) {
+		t.Fatalf("SetDefaultWebhook(missing) = %v, want ErrWebhookConfigNotFound", err)
+	}
+
+	if err := adapter.DeleteWebhookConfig("audit"); err != nil {
+		t.Fatalf("DeleteWebhookConfig: %v", err)
+	}
+	if config, err := adapter.GetWebhookConfig(); err != nil || config.ID != domain.DefaultWebhookID {
+		t.Fatalf("GetWebhookConfig() after deleting the default = %+v, %v; want the fallback", config, err)
+	}
+}
@@ -0,0 +1,113 @@
+package boltdb
+
+import (
+	"OmniView/internal/core/domain"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const WebhookDeadLetterBucket = "WebhookDeadLetters"
+
+// DeadLetterRepository implements ports.WebhookDeadLetterRepository. Each
+// delivery is stored as JSON under a fixed-width hex sequence number, so
+// bolt's key order is the order the deliveries were saved in.
+type DeadLetterRepository struct {
+	adapter *BoltAdapter
+}
+
+// NewDeadLetterRepository creates a new DeadLetterRepository
+func NewDeadLetterRepository(adapter *BoltAdapter) *DeadLetterRepository {
+	return &DeadLetterRepository{
+		adapter: adapter,
+	}
+}
+
+// SaveDeadLetter stores a delivery under a new ID and sets SavedAt when unset
+func (r *DeadLetterRepository) SaveDeadLetter(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	if err := r.ready(ctx); err != nil {
+		return err
+	}
+	if delivery == nil {
+		return fmt.Errorf("webhook dead letter cannot be nil")
+	}
+
+	return r.adapter.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(WebhookDeadLetterBucket))
+		if b == nil {
+			return fmt.Errorf("bucket %s not found", WebhookDeadLetterBucket)
+		}
+		seq, err := b.NextSequence()
+		if err != nil {
+			return fmt.Errorf("failed to allocate dead letter ID: %w", err)
+		}
+		delivery.ID = fmt.Sprintf("%016x", seq)
+		if delivery.SavedAt.IsZero() {
+			delivery.SavedAt = time.Now()
+		}
+
+		data, err := json.Marshal(delivery)
+		if err != nil {
+			return fmt.Errorf("failed to marshal webhook dead letter: %w", err)
+		}
+		return b.Put([]byte(delivery.ID), data)
+	})
+}
+
+// ListDeadLetters returns every stored delivery, oldest first
+func (r *DeadLetterRepository) ListDeadLetters(ctx context.Context) ([]domain.WebhookDelivery, error) {
+	if err := r.ready(ctx); err != nil {
+		return nil, err
+	}
+
+	var deliveries []domain.WebhookDelivery
+	err := r.adapter.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(WebhookDeadLetterBucket))
+		if b == nil {
+			return fmt.Errorf("bucket %s not found", WebhookDeadLetterBucket)
+		}
+		return b.ForEach(func(k, v []byte) error {
+			var delivery domain.WebhookDelivery
+			if err := json.Unmarshal(v, &delivery); err != nil {
+				return fmt.Errorf("failed to unmarshal webhook dead letter %s: %w", k, err)
+			}
+			deliveries = append(deliveries, delivery)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// DeleteDeadLetter removes a delivery
+func (r *DeadLetterRepository) DeleteDeadLetter(ctx context.Context, id string) error {
+	if err := r.ready(ctx); err != nil {
+		return err
+	}
+
+	return r.adapter.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(WebhookDeadLetterBucket))
+		if b == nil {
+			return fmt.Errorf("bucket %s not found", WebhookDeadLetterBucket)
+		}
+		if b.Get([]byte(id)) == nil {
+			return fmt.Errorf("%w: %s", domain.ErrDeadLetterNotFound, id)
+		}
+		return b.Delete([]byte(id))
+	})
+}
+
+func (r *DeadLetterRepository) ready(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if r == nil || r.adapter == nil || r.adapter.db == nil {
+		return fmt.Errorf("boltAdapter not initialized")
+	}
+	return nil
+}
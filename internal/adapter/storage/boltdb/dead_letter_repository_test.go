@@ -0,0 +1,67 @@
+package boltdb
+
+import (
+	"OmniView/internal/core/domain"
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestDeadLetterRepository_SurvivesReopenInSaveOrder(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "test.bolt")
+	adapter := &BoltAdapter{dbPath: dbPath}
+	if err := adapter.Initialize(); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	repo := NewDeadLetterRepository(adapter)
+
+	var ids []string
+	for _, webhookID := range []string{"ops", "default", "alerts"} {
+		delivery := &domain.WebhookDelivery{
+			WebhookID: webhookID,
+			URL:       "https://example.com/" + webhookID,
+			Payload:   `{"message":"disk full"}`,
+			Attempts:  5,
+			Reason:    domain.DeadLetterRetriesExhausted,
+		}
+		if err := repo.SaveDeadLetter(ctx, delivery); err != nil {
+			t.Fatalf("SaveDeadLetter: %v", err)
+		}
+		if delivery.ID == "" || delivery.SavedAt.IsZero() {
+			t.Fatalf("SaveDeadLetter did not assign ID and SavedAt: %+v", delivery)
+		}
+		ids = append(ids, delivery.ID)
+	}
+	if err := adapter.db.Close(); err != nil {
+		t.Fatalf("db.Close: %v", err)
+	}
+
+	reopened := &BoltAdapter{dbPath: dbPath}
+	if err := reopened.Initialize(); err != nil {
+		t.Fatalf("Initialize after reopen: %v", err)
+	}
+	t.Cleanup(func() { _ = reopened.db.Close() })
+	repo = NewDeadLetterRepository(reopened)
+
+	if err := repo.DeleteDeadLetter(ctx, ids[1]); err != nil {
+		t.Fatalf("DeleteDeadLetter: %v", err)
+	}
+	if err := repo.DeleteDeadLetter(ctx, ids[1]); !errors.Is(err, domain.ErrDeadLetterNotFound) {
+		t.Fatalf("DeleteDeadLetter twice error = %v, want ErrDeadLetterNotFound", err)
+	}
+
+	deliveries, err := repo.ListDeadLetters(ctx)
+	if err != nil {
+		t.Fatalf("ListDeadLetters: %v", err)
+	}
+	if len(deliveries) != 2 || deliveries[0].WebhookID != "ops" || deliveries[1].WebhookID != "alerts" {
+		t.Fatalf("ListDeadLetters = %+v, want ops then alerts", deliveries)
+	}
+	if deliveries[0].Payload != `{"message":"disk full"}` || deliveries[0].Reason != domain.DeadLetterRetriesExhausted || deliveries[0].Attempts != 5 {
+		t.Fatalf("ListDeadLetters did not keep the delivery: %+v", deliveries[0])
+	}
+}
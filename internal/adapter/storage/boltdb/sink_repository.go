@@ -0,0 +1,161 @@
+package boltdb
+
+import (
+	"OmniView/internal/core/domain"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	SinkBucket        = "OutputSinks"
+	RoutingRuleBucket = "RoutingRules"
+)
+
+// SinkRepository implements ports.SinkRepository. Sinks and rules are stored
+// as JSON under their IDs, so bolt's key order gives the ID order.
+type SinkRepository struct {
+	adapter *BoltAdapter
+}
+
+// NewSinkRepository creates a new SinkRepository
+func NewSinkRepository(adapter *BoltAdapter) *SinkRepository {
+	return &SinkRepository{
+		adapter: adapter,
+	}
+}
+
+// ListSinks returns every stored sink, ordered by ID
+func (r *SinkRepository) ListSinks(ctx context.Context) ([]domain.SinkConfig, error) {
+	return listBucket[domain.SinkConfig](ctx, r, SinkBucket)
+}
+
+// SaveSink stores a sink, replacing one with the same ID
+func (r *SinkRepository) SaveSink(ctx context.Context, sink domain.SinkConfig) error {
+	return r.put(ctx, SinkBucket, sink.ID, sink)
+}
+
+// DeleteSink removes a sink. Rules that name it are kept; the router skips
+// sinks that do not exist.
+func (r *SinkRepository) DeleteSink(ctx context.Context, id string) error {
+	return r.delete(ctx, SinkBucket, id, domain.ErrSinkNotFound)
+}
+
+// ListRoutingRules returns every stored routing rule, ordered by ID
+func (r *SinkRepository) ListRoutingRules(ctx context.Context) ([]domain.RoutingRule, error) {
+	return listBucket[domain.RoutingRule](ctx, r, RoutingRuleBucket)
+}
+
+// SaveRoutingRule stores a rule, replacing one with the same ID
+func (r *SinkRepository) SaveRoutingRule(ctx context.Context, rule domain.RoutingRule) error {
+	return r.put(ctx, RoutingRuleBucket, rule.ID, rule)
+}
+
+// DeleteRoutingRule removes a rule
+func (r *SinkRepository) DeleteRoutingRule(ctx context.Context, id string) error {
+	return r.delete(ctx, RoutingRuleBucket, id, domain.ErrRoutingRuleNotFound)
+}
+
+// ==========================================
+// Helpers
+// ==========================================
+
+func (r *SinkRepository) ready(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if r == nil || r.adapter == nil || r.adapter.db == nil {
+		return fmt.Errorf("boltAdapter not initialized")
+	}
+	return nil
+}
+
+func listBucket[T any](ctx context.Context, r *SinkRepository, bucket string) ([]T, error) {
+	if err := r.ready(ctx); err != nil {
+		return nil, err
+	}
+
+	var items []T
+	err := r.adapter.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		if b == nil {
+			return fmt.Errorf("bucket %s not found", bucket)
+		}
+		return b.ForEach(func(k, v []byte) error {
+			var item T
+			if err := json.Unmarshal(v, &item); err != nil {
+				return fmt.Errorf("failed to unmarshal %s entry %s: %w", bucket, k, err)
+			}
+			items = append(items, item)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *SinkRepository) put(ctx context.Context, bucket, id string, value any) error {
+	if err := r.ready(ctx); err != nil {
+		return err
+	}
+	if id == "" {
+		return fmt.Errorf("%s entry ID cannot be empty", bucket)
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s entry: %w", bucket, err)
+	}
+	return r.adapter.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		if b == nil {
+			return fmt.Errorf("bucket %s not found", bucket)
+		}
+		return b.Put([]byte(id), data)
+	})
+}
+
+func (r *SinkRepository) delete(ctx context.Context, bucket, id string, notFound error) error {
+	if err := r.ready(ctx); err != nil {
+		return err
+	}
+
+	return r.adapter.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		if b == nil {
+			return fmt.Errorf("bucket %s not found", bucket)
+		}
+		if b.Get([]byte(id)) == nil {
+			return fmt.Errorf("%w: %s", notFound, id)
+		}
+		return b.Delete([]byte(id))
+	})
+}
+
+// seedWebhookRoute stores the webhook sink and the rule that sends it the
+// messages flagged by Trace_Message_To_Webhook. Initialize runs it only when
+// it creates the sink bucket, so a route the user removes stays removed.
+func seedWebhookRoute(tx *bolt.Tx) error {
+	sink, rule := domain.DefaultWebhookRoute()
+	return putRoute(tx, &sink, &rule)
+}
+
+// putRoute stores sink and a rule routing to it within tx.
+func putRoute(tx *bolt.Tx, sink *domain.SinkConfig, rule *domain.RoutingRule) error {
+	sinkData, err := json.Marshal(sink)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s entry: %w", SinkBucket, err)
+	}
+	ruleData, err := json.Marshal(rule)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s entry: %w", RoutingRuleBucket, err)
+	}
+	if err := tx.Bucket([]byte(SinkBucket)).Put([]byte(sink.ID), sinkData); err != nil {
+		return err
+	}
+	return tx.Bucket([]byte(RoutingRuleBucket)).Put([]byte(rule.ID), ruleData)
+}
@@ -0,0 +1,105 @@
+package boltdb
+
+import (
+	"OmniView/internal/core/domain"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSinkRepository_StoresSinksAndRulesByID(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := NewSinkRepository(newTestBoltAdapter(t))
+
+	for _, id := range []string{"syslog", "audit"} {
+		sink, err := domain.NewSinkConfig(id, "file", map[string]string{"path": "/tmp/" + id}, true)
+		if err != nil {
+			t.Fatalf("NewSinkConfig: %v", err)
+		}
+		if err := repo.SaveSink(ctx, *sink); err != nil {
+			t.Fatalf("SaveSink: %v", err)
+		}
+	}
+	rule, err := domain.NewRoutingRule("errors", []string{"audit"}, domain.LogLevelError, "^ORDER_", domain.BroadcastModeGlobal, "", true)
+	if err != nil {
+		t.Fatalf("NewRoutingRule: %v", err)
+	}
+	if err := repo.SaveRoutingRule(ctx, *rule); err != nil {
+		t.Fatalf("SaveRoutingRule: %v", err)
+	}
+
+	sinks, err := repo.ListSinks(ctx)
+	if err != nil {
+		t.Fatalf("ListSinks: %v", err)
+	}
+	if len(sinks) != 3 || sinks[0].ID != "audit" || sinks[0].Setting("path") != "/tmp/audit" || sinks[2].ID != domain.WebhookRouteID {
+		t.Fatalf("ListSinks = %+v, want audit, syslog then the seeded webhooks sink", sinks)
+	}
+	rules, err := repo.ListRoutingRules(ctx)
+	if err != nil {
+		t.Fatalf("ListRoutingRules: %v", err)
+	}
+	if len(rules) != 2 || rules[0].Process != "^ORDER_" || rules[0].MinLevel != domain.LogLevelError {
+		t.Fatalf("ListRoutingRules = %+v", rules)
+	}
+
+	if err := repo.DeleteSink(ctx, "audit"); err != nil {
+		t.Fatalf("DeleteSink: %v", err)
+	}
+	if err := repo.DeleteSink(ctx, "audit"); !errors.Is(err, domain.ErrSinkNotFound) {
+		t.Fatalf("DeleteSink twice = %v, want ErrSinkNotFound", err)
+	}
+	if err := repo.DeleteRoutingRule(ctx, "errors"); err != nil {
+		t.Fatalf("DeleteRoutingRule: %v", err)
+	}
+	if err := repo.DeleteRoutingRule(ctx, "errors"); !errors.Is(err, domain.ErrRoutingRuleNotFound) {
+		t.Fatalf("DeleteRoutingRule twice = %v, want ErrRoutingRuleNotFound", err)
+	}
+}
+
+// TestBoltAdapter_SeedsWebhookRouteOnce verifies that a new store routes
+// flagged messages to the webhook sink, and that removing the route sticks
+// across restarts.
+func TestBoltAdapter_SeedsWebhookRouteOnce(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	adapter := newTestBoltAdapter(t)
+	repo := NewSinkRepository(adapter)
+
+	rules, err := repo.ListRoutingRules(ctx)
+	if err != nil {
+		t.Fatalf("ListRoutingRules: %v", err)
+	}
+	if len(rules) != 1 || rules[0].ID != domain.WebhookRouteID || !rules[0].Flagged || !rules[0].Enabled {
+		t.Fatalf("ListRoutingRules = %+v, want the flagged webhooks rule", rules)
+	}
+	sinks, err := repo.ListSinks(ctx)
+	if err != nil {
+		t.Fatalf("ListSinks: %v", err)
+	}
+	if len(sinks) != 1 || sinks[0].Type != domain.WebhookSinkType {
+		t.Fatalf("ListSinks = %+v, want the webhook sink", sinks)
+	}
+
+	if err := repo.DeleteRoutingRule(ctx, domain.WebhookRouteID); err != nil {
+		t.Fatalf("DeleteRoutingRule: %v", err)
+	}
+	reopen(t, adapter)
+	if rules, err := repo.ListRoutingRules(ctx); err != nil || len(rules) != 0 {
+		t.Fatalf("ListRoutingRules after restart = %+v, %v, want none", rules, err)
+	}
+}
+
+// reopen closes adapter's database and initializes it again, as a restart does.
+func reopen(t *testing.T, adapter *BoltAdapter) {
+	t.Helper()
+	if err := adapter.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := adapter.Initialize(); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package boltdb
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestBoltAdapter_TraceFilter_PerDatabaseRoundTrip verifies that filter expressions
+// are stored per database ID and that saving an empty expression removes the entry.
+func TestBoltAdapter_TraceFilter_PerDatabaseRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	adapter := newTestBoltAdapter(t)
+
+	if got, err := adapter.GetTraceFilter("DEV"); err != nil || got != "" {
+		t.Fatalf("GetTraceFilter on empty store = %q, %v; want empty", got, err)
+	}
+
+	if err := adapter.SetTraceFilter("DEV", `level>=WARNING`); err != nil {
+		t.Fatalf("SetTraceFilter(DEV): %v", err)
+	}
+	if err := adapter.SetTraceFilter("PROD", `process~"ORDER_"`); err != nil {
+		t.Fatalf("SetTraceFilter(PROD): %v", err)
+	}
+
+	if got, _ := adapter.GetTraceFilter("DEV"); got != `level>=WARNING` {
+		t.Fatalf("GetTraceFilter(DEV) = %q", got)
+	}
+	if got, _ := adapter.GetTraceFilter("PROD"); got != `process~"ORDER_"` {
+		t.Fatalf("GetTraceFilter(PROD) = %q", got)
+	}
+
+	if err := adapter.SetTraceFilter("DEV", "  "); err != nil {
+		t.Fatalf("SetTraceFilter(DEV, blank): %v", err)
+	}
+	if got, _ := adapter.GetTraceFilter("DEV"); got != "" {
+		t.Fatalf("expected blank expression to clear the DEV filter, got %q", got)
+	}
+}
+
+// TestBoltAdapter_GetTraceFilter_UninitializedDB verifies the sentinel error on an unopened adapter.
+func TestBoltAdapter_GetTraceFilter_UninitializedDB(t *testing.T) {
+	t.Parallel()
+
+	adapter := &BoltAdapter{}
+	if _, err := adapter.GetTraceFilter("DEV"); !errors.Is(err, ErrAdapterNotInitialized) {
+		t.Fatalf("GetTraceFilter error = %v, want ErrAdapterNotInitialized", err)
+	}
+}
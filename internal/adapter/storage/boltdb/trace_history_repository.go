@@ -0,0 +1,446 @@
+package boltdb
+
+import (
+	"OmniView/internal/adapter/logger"
+	"OmniView/internal/core/domain"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Trace history layout:
+//
+//	TraceHistory/<databaseID>/sessions/<sessionID>       -> TraceSession JSON
+//	TraceHistory/<databaseID>/messages/<sessionID>/<key> -> QueueMessage JSON
+//
+// Message keys are the big-endian receive time in nanoseconds followed by a
+// big-endian sequence number, so a cursor walks them in arrival order.
+const (
+	TraceHistoryBucket      = "TraceHistory"
+	historySessionsBucket   = "sessions"
+	historyMessagesBucket   = "messages"
+	historyRecordKeyLength  = 16
+	historyRecordTimeLength = 8
+)
+
+// TraceHistoryRepository implements ports.TraceHistoryRepository
+type TraceHistoryRepository struct {
+	adapter   *BoltAdapter
+	mu        sync.RWMutex
+	retention domain.HistoryRetention
+	now       func() time.Time
+}
+
+// NewTraceHistoryRepository creates a new TraceHistoryRepository
+func NewTraceHistoryRepository(adapter *BoltAdapter, retention domain.HistoryRetention) *TraceHistoryRepository {
+	return &TraceHistoryRepository{
+		adapter:   adapter,
+		retention: retention,
+		now:       time.Now,
+	}
+}
+
+// Retention returns the active retention limits
+func (r *TraceHistoryRepository) Retention() domain.HistoryRetention {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.retention
+}
+
+// SetRetention replaces the active retention limits
+func (r *TraceHistoryRepository) SetRetention(retention domain.HistoryRetention) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.retention = retention
+}
+
+// BeginSession creates and stores a new history session for a database
+func (r *TraceHistoryRepository) BeginSession(ctx context.Context, databaseID string, subscriberName string) (*domain.TraceSession, error) {
+	if err := r.ready(ctx); err != nil {
+		return nil, err
+	}
+
+	session, err := domain.NewTraceSession(databaseID, subscriberName, r.now())
+	if err != nil {
+		return nil, fmt.Errorf("BeginSession: %w", err)
+	}
+
+	err = r.adapter.db.Update(func(tx *bolt.Tx) error {
+		dbBucket, err := historyDatabaseBucket(tx, session.DatabaseID, true)
+		if err != nil {
+			return err
+		}
+		if err := putTraceSession(dbBucket, session); err != nil {
+			return err
+		}
+		return pruneDatabaseHistory(dbBucket, r.Retention(), session.StartedAt)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("BeginSession: %w", err)
+	}
+	return session, nil
+}
+
+// Append stores messages under the session and updates its counters in place.
+// Counters continue from the stored session, so records a concurrent Prune
+// removed stay uncounted. Retention is left to BeginSession and Prune; pruning
+// here would walk the database's whole history on every batch.
+func (r *TraceHistoryRepository) Append(ctx context.Context, session *domain.TraceSession, messages []*domain.QueueMessage) error {
+	if err := r.ready(ctx); err != nil {
+		return err
+	}
+	if session == nil {
+		return fmt.Errorf("Append: %w", domain.ErrTraceSessionNotFound)
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+
+	receivedAt := r.now()
+	updated := *session
+	err := r.adapter.db.Update(func(tx *bolt.Tx) error {
+		dbBucket, err := historyDatabaseBucket(tx, updated.DatabaseID, true)
+		if err != nil {
+			return err
+		}
+		if stored, err := getTraceSession(dbBucket, updated.ID); err == nil {
+			updated.MessageCount, updated.Bytes = stored.MessageCount, stored.Bytes
+		} else {
+			// Pruned away entirely; its records start again from nothing.
+			updated.MessageCount, updated.Bytes = 0, 0
+		}
+		records, err := dbBucket.Bucket([]byte(historyMessagesBucket)).CreateBucketIfNotExists([]byte(updated.ID))
+		if err != nil {
+			return fmt.Errorf("failed to create session bucket: %w", err)
+		}
+
+		for _, msg := range messages {
+			if msg == nil {
+				continue
+			}
+			data, err := json.Marshal(msg)
+			if err != nil {
+				return fmt.Errorf("failed to marshal trace message: %w", err)
+			}
+			seq, err := records.NextSequence()
+			if err != nil {
+				return fmt.Errorf("failed to allocate history sequence: %w", err)
+			}
+			if err := records.Put(historyRecordKey(receivedAt, seq), data); err != nil {
+				return fmt.Errorf("failed to save trace message: %w", err)
+			}
+			updated.MessageCount++
+			updated.Bytes += int64(len(data))
+		}
+		updated.LastMessageAt = receivedAt
+		return putTraceSession(dbBucket, &updated)
+	})
+	if err != nil {
+		return fmt.Errorf("Append: %w", err)
+	}
+
+	*session = updated
+	return nil
+}
+
+// ListSessions returns the stored sessions for a database, newest first
+func (r *TraceHistoryRepository) ListSessions(ctx context.Context, databaseID string) ([]domain.TraceSession, error) {
+	if err := r.ready(ctx); err != nil {
+		return nil, err
+	}
+
+	var sessions []domain.TraceSession
+	err := r.adapter.db.View(func(tx *bolt.Tx) error {
+		dbBucket, err := historyDatabaseBucket(tx, databaseID, false)
+		if err != nil || dbBucket == nil {
+			return err
+		}
+		c := dbBucket.Bucket([]byte(historySessionsBucket)).Cursor()
+		for k, v := c.Last(); k != nil; k, v = c.Prev() {
+			var session domain.TraceSession
+			if err := json.Unmarshal(v, &session); err != nil {
+				logger.Warn("skipping unreadable trace session", "databaseID", databaseID, "session", string(k), "error", err)
+				continue
+			}
+			sessions = append(sessions, session)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ListSessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// ReadSession returns up to limit messages of a session that follow the
+// position after, oldest first, and the position of the last one read. A
+// position is the hex-encoded record key, so a page starts with a seek
+// rather than a walk over the records before it.
+func (r *TraceHistoryRepository) ReadSession(ctx context.Context, session domain.TraceSession, after string, limit int) ([]*domain.QueueMessage, string, error) {
+	if err := r.ready(ctx); err != nil {
+		return nil, "", err
+	}
+	var start []byte
+	if after != "" {
+		key, err := hex.DecodeString(after)
+		if err != nil || len(key) != historyRecordKeyLength {
+			return nil, "", fmt.Errorf("ReadSession: invalid position %q", after)
+		}
+		start = key
+	}
+	if limit <= 0 {
+		return nil, after, nil
+	}
+
+	messages := make([]*domain.QueueMessage, 0, limit)
+	last := after
+	err := r.adapter.db.View(func(tx *bolt.Tx) error {
+		dbBucket, err := historyDatabaseBucket(tx, session.DatabaseID, false)
+		if err != nil {
+			return err
+		}
+		if dbBucket == nil {
+			return domain.ErrTraceSessionNotFound
+		}
+		records := dbBucket.Bucket([]byte(historyMessagesBucket)).Bucket([]byte(session.ID))
+		if records == nil {
+			if dbBucket.Bucket([]byte(historySessionsBucket)).Get([]byte(session.ID)) == nil {
+				return domain.ErrTraceSessionNotFound
+			}
+			return nil
+		}
+
+		c := records.Cursor()
+		k, v := c.First()
+		if start != nil {
+			if k, v = c.Seek(start); bytes.Equal(k, start) {
+				k, v = c.Next()
+			}
+		}
+		for ; k != nil && len(messages) < limit; k, v = c.Next() {
+			last = hex.EncodeToString(k)
+			msg := &domain.QueueMessage{}
+			if err := json.Unmarshal(v, msg); err != nil {
+				logger.Warn("skipping unreadable trace history record", "session", session.ID, "error", err)
+				continue
+			}
+			messages = append(messages, msg)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("ReadSession: %w", err)
+	}
+	return messages, last, nil
+}
+
+// Prune removes history that falls outside the active retention limits
+func (r *TraceHistoryRepository) Prune(ctx context.Context) error {
+	if err := r.ready(ctx); err != nil {
+		return err
+	}
+
+	retention := r.Retention()
+	now := r.now()
+	err := r.adapter.db.Update(func(tx *bolt.Tx) error {
+		root := tx.Bucket([]byte(TraceHistoryBucket))
+		if root == nil {
+			return fmt.Errorf("bucket %s not found", TraceHistoryBucket)
+		}
+
+		var databaseIDs [][]byte
+		if err := root.ForEachBucket(func(k []byte) error {
+			databaseIDs = append(databaseIDs, append([]byte(nil), k...))
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		for _, id := range databaseIDs {
+			if err := pruneDatabaseHistory(root.Bucket(id), retention, now); err != nil {
+				return fmt.Errorf("database %s: %w", string(id), err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Prune: %w", err)
+	}
+	return nil
+}
+
+func (r *TraceHistoryRepository) ready(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if r == nil || r.adapter == nil || r.adapter.db == nil {
+		return ErrAdapterNotInitialized
+	}
+	return nil
+}
+
+// ==========================================
+// Bucket Helpers
+// ==========================================
+
+// historyDatabaseBucket returns the per-database history bucket. When create is
+// false and nothing has been recorded for the database yet, it returns nil.
+func historyDatabaseBucket(tx *bolt.Tx, databaseID string, create bool) (*bolt.Bucket, error) {
+	if databaseID == "" {
+		return nil, domain.ErrEmptyDatabaseID
+	}
+	root := tx.Bucket([]byte(TraceHistoryBucket))
+	if root == nil {
+		return nil, fmt.Errorf("bucket %s not found", TraceHistoryBucket)
+	}
+	if !create {
+		return root.Bucket([]byte(databaseID)), nil
+	}
+
+	dbBucket, err := root.CreateBucketIfNotExists([]byte(databaseID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create history bucket: %w", err)
+	}
+	if _, err := dbBucket.CreateBucketIfNotExists([]byte(historySessionsBucket)); err != nil {
+		return nil, fmt.Errorf("failed to create history bucket: %w", err)
+	}
+	if _, err := dbBucket.CreateBucketIfNotExists([]byte(historyMessagesBucket)); err != nil {
+		return nil, fmt.Errorf("failed to create history bucket: %w", err)
+	}
+	return dbBucket, nil
+}
+
+func putTraceSession(dbBucket *bolt.Bucket, session *domain.TraceSession) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to marshal trace session: %w", err)
+	}
+	if err := dbBucket.Bucket([]byte(historySessionsBucket)).Put([]byte(session.ID), data); err != nil {
+		return fmt.Errorf("failed to save trace session: %w", err)
+	}
+	return nil
+}
+
+func getTraceSession(dbBucket *bolt.Bucket, id string) (*domain.TraceSession, error) {
+	data := dbBucket.Bucket([]byte(historySessionsBucket)).Get([]byte(id))
+	if data == nil {
+		return nil, domain.ErrTraceSessionNotFound
+	}
+	var session domain.TraceSession
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal trace session: %w", err)
+	}
+	return &session, nil
+}
+
+func historyRecordKey(receivedAt time.Time, seq uint64) []byte {
+	key := make([]byte, historyRecordKeyLength)
+	binary.BigEndian.PutUint64(key[:historyRecordTimeLength], uint64(receivedAt.UnixNano()))
+	binary.BigEndian.PutUint64(key[historyRecordTimeLength:], seq)
+	return key
+}
+
+func historyRecordTime(key []byte) time.Time {
+	if len(key) < historyRecordTimeLength {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(binary.BigEndian.Uint64(key[:historyRecordTimeLength])))
+}
+
+// ==========================================
+// Retention
+// ==========================================
+
+// pruneDatabaseHistory applies age and size retention to a single database bucket.
+// Records are removed oldest first; sessions left without records are dropped.
+func pruneDatabaseHistory(dbBucket *bolt.Bucket, retention domain.HistoryRetention, now time.Time) error {
+	if dbBucket == nil {
+		return nil
+	}
+	sessionsBucket := dbBucket.Bucket([]byte(historySessionsBucket))
+	messagesBucket := dbBucket.Bucket([]byte(historyMessagesBucket))
+	if sessionsBucket == nil || messagesBucket == nil {
+		return nil
+	}
+
+	// Sessions are keyed by start time, so ForEach yields them oldest first.
+	var sessions []*domain.TraceSession
+	var totalBytes int64
+	if err := sessionsBucket.ForEach(func(k, v []byte) error {
+		var session domain.TraceSession
+		if err := json.Unmarshal(v, &session); err != nil {
+			logger.Warn("dropping unreadable trace session", "session", string(k), "error", err)
+			session = domain.TraceSession{ID: string(k)}
+		}
+		sessions = append(sessions, &session)
+		totalBytes += session.Bytes
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	cutoff := retention.Cutoff(now)
+	for _, session := range sessions {
+		ageExpired := !cutoff.IsZero() && session.StartedAt.Before(cutoff)
+		overSize := retention.MaxBytes > 0 && totalBytes > retention.MaxBytes
+		if !ageExpired && !overSize {
+			break
+		}
+
+		records := messagesBucket.Bucket([]byte(session.ID))
+		removed := 0
+		if records != nil {
+			var stale [][]byte
+			var freed int64
+			c := records.Cursor()
+			for k, v := c.First(); k != nil; k, v = c.Next() {
+				expired := !cutoff.IsZero() && historyRecordTime(k).Before(cutoff)
+				if !expired && !(retention.MaxBytes > 0 && totalBytes-freed > retention.MaxBytes) {
+					break
+				}
+				stale = append(stale, append([]byte(nil), k...))
+				freed += int64(len(v))
+			}
+			for _, k := range stale {
+				if err := records.Delete(k); err != nil {
+					return fmt.Errorf("failed to delete trace history record: %w", err)
+				}
+			}
+			removed = len(stale)
+			totalBytes -= freed
+			session.Bytes = max(session.Bytes-freed, 0)
+			session.MessageCount = max(session.MessageCount-removed, 0)
+		}
+
+		empty := records == nil
+		if records != nil {
+			first, _ := records.Cursor().First()
+			empty = first == nil
+		}
+		if empty && (ageExpired || removed > 0) {
+			if records != nil {
+				if err := messagesBucket.DeleteBucket([]byte(session.ID)); err != nil {
+					return fmt.Errorf("failed to delete trace session records: %w", err)
+				}
+			}
+			if err := sessionsBucket.Delete([]byte(session.ID)); err != nil {
+				return fmt.Errorf("failed to delete trace session: %w", err)
+			}
+			continue
+		}
+		if removed == 0 {
+			continue
+		}
+		if err := putTraceSession(dbBucket, session); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,254 @@
+package boltdb
+
+import (
+	"OmniView/internal/core/domain"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func newTestTraceHistoryRepository(t *testing.T, retention domain.HistoryRetention) (*TraceHistoryRepository, *time.Time) {
+	t.Helper()
+
+	clock := time.Date(2026, 3, 3, 12, 0, 0, 0, time.UTC)
+	repo := NewTraceHistoryRepository(newTestBoltAdapter(t), retention)
+	repo.now = func() time.Time { return clock }
+	return repo, &clock
+}
+
+func mustNewHistoryMessage(t *testing.T, id string, payload string) *domain.QueueMessage {
+	t.Helper()
+
+	msg, err := domain.NewQueueMessage(id, "ORDER_API", domain.LogLevelInfo, payload, time.Unix(1700000000, 0))
+	if err != nil {
+		t.Fatalf("NewQueueMessage: %v", err)
+	}
+	return msg
+}
+
+func TestTraceHistoryRepository_AppendAndReadSessionInOrder(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo, clock := newTestTraceHistoryRepository(t, domain.HistoryRetention{})
+
+	session, err := repo.BeginSession(ctx, "DEV", "SUB_A")
+	if err != nil {
+		t.Fatalf("BeginSession: %v", err)
+	}
+
+	for batch := 0; batch < 3; batch++ {
+		*clock = clock.Add(time.Second)
+		msgs := []*domain.QueueMessage{
+			mustNewHistoryMessage(t, fmt.Sprintf("%d-a", batch), "first"),
+			mustNewHistoryMessage(t, fmt.Sprintf("%d-b", batch), "second"),
+		}
+		if err := repo.Append(ctx, session, msgs); err != nil {
+			t.Fatalf("Append batch %d: %v", batch, err)
+		}
+	}
+
+	if session.MessageCount != 6 {
+		t.Fatalf("session.MessageCount = %d, want 6", session.MessageCount)
+	}
+	if session.Bytes <= 0 {
+		t.Fatalf("expected session.Bytes to grow, got %d", session.Bytes)
+	}
+
+	// Each page starts where the previous one stopped, mid-batch included.
+	after := ""
+	for _, page := range []struct {
+		limit int
+		want  []string
+	}{
+		{1, []string{"0-a"}},
+		{3, []string{"0-b", "1-a", "1-b"}},
+		{3, []string{"2-a", "2-b"}},
+		{3, nil},
+	} {
+		got, next, err := repo.ReadSession(ctx, *session, after, page.limit)
+		if err != nil {
+			t.Fatalf("ReadSession: %v", err)
+		}
+		if len(got) != len(page.want) {
+			t.Fatalf("ReadSession after %q returned %d messages, want %d", after, len(got), len(page.want))
+		}
+		for i, msg := range got {
+			if msg.MessageID() != page.want[i] {
+				t.Fatalf("page[%d].MessageID() = %q, want %q", i, msg.MessageID(), page.want[i])
+			}
+		}
+		if len(got) == 0 && next != after {
+			t.Fatalf("ReadSession past the end moved the position from %q to %q", after, next)
+		}
+		after = next
+	}
+
+	if _, _, err := repo.ReadSession(ctx, *session, "not-a-key", 3); err == nil {
+		t.Fatal("expected an invalid position to be rejected")
+	}
+}
+
+func TestTraceHistoryRepository_ListSessionsNewestFirstPerDatabase(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo, clock := newTestTraceHistoryRepository(t, domain.HistoryRetention{})
+
+	first, err := repo.BeginSession(ctx, "DEV", "SUB_A")
+	if err != nil {
+		t.Fatalf("BeginSession: %v", err)
+	}
+	*clock = clock.Add(time.Minute)
+	second, err := repo.BeginSession(ctx, "DEV", "SUB_A")
+	if err != nil {
+		t.Fatalf("BeginSession: %v", err)
+	}
+	if _, err := repo.BeginSession(ctx, "PROD", "SUB_B"); err != nil {
+		t.Fatalf("BeginSession: %v", err)
+	}
+
+	sessions, err := repo.ListSessions(ctx, "DEV")
+	if err != nil {
+		t.Fatalf("ListSessions: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("ListSessions returned %d sessions, want 2", len(sessions))
+	}
+	if sessions[0].ID != second.ID || sessions[1].ID != first.ID {
+		t.Fatalf("ListSessions order = [%s %s], want [%s %s]", sessions[0].ID, sessions[1].ID, second.ID, first.ID)
+	}
+
+	unknown, err := repo.ListSessions(ctx, "UNKNOWN")
+	if err != nil {
+		t.Fatalf("ListSessions(UNKNOWN): %v", err)
+	}
+	if len(unknown) != 0 {
+		t.Fatalf("expected no sessions for unknown database, got %d", len(unknown))
+	}
+}
+
+func TestTraceHistoryRepository_PruneDropsSessionsOlderThanMaxAge(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo, clock := newTestTraceHistoryRepository(t, domain.HistoryRetention{MaxAge: time.Hour})
+
+	old, err := repo.BeginSession(ctx, "DEV", "SUB_A")
+	if err != nil {
+		t.Fatalf("BeginSession: %v", err)
+	}
+	if err := repo.Append(ctx, old, []*domain.QueueMessage{mustNewHistoryMessage(t, "old", "stale")}); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+
+	*clock = clock.Add(2 * time.Hour)
+	current, err := repo.BeginSession(ctx, "DEV", "SUB_A")
+	if err != nil {
+		t.Fatalf("BeginSession: %v", err)
+	}
+
+	sessions, err := repo.ListSessions(ctx, "DEV")
+	if err != nil {
+		t.Fatalf("ListSessions: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].ID != current.ID {
+		t.Fatalf("expected only the current session to survive, got %+v", sessions)
+	}
+
+	if _, _, err := repo.ReadSession(ctx, *old, "", 10); !errors.Is(err, domain.ErrTraceSessionNotFound) {
+		t.Fatalf("ReadSession(old) error = %v, want ErrTraceSessionNotFound", err)
+	}
+}
+
+func TestTraceHistoryRepository_PruneEnforcesMaxBytesOldestFirst(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo, clock := newTestTraceHistoryRepository(t, domain.HistoryRetention{})
+
+	session, err := repo.BeginSession(ctx, "DEV", "SUB_A")
+	if err != nil {
+		t.Fatalf("BeginSession: %v", err)
+	}
+	if err := repo.Append(ctx, session, []*domain.QueueMessage{mustNewHistoryMessage(t, "p0", "x")}); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	recordSize := session.Bytes
+
+	// Allow three records; the probe plus four more must leave only the newest three.
+	repo.SetRetention(domain.HistoryRetention{MaxBytes: 3 * recordSize})
+	for i := 0; i < 4; i++ {
+		*clock = clock.Add(time.Second)
+		if err := repo.Append(ctx, session, []*domain.QueueMessage{mustNewHistoryMessage(t, fmt.Sprintf("m%d", i), "x")}); err != nil {
+			t.Fatalf("Append %d: %v", i, err)
+		}
+	}
+	if session.MessageCount != 5 {
+		t.Fatalf("Append pruned: session holds %d messages, want 5", session.MessageCount)
+	}
+	if err := repo.Prune(ctx); err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+
+	sessions, err := repo.ListSessions(ctx, "DEV")
+	if err != nil {
+		t.Fatalf("ListSessions: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].MessageCount != 3 || sessions[0].Bytes != 3*recordSize {
+		t.Fatalf("sessions after Prune = %+v, want one with 3 msgs / %d bytes", sessions, 3*recordSize)
+	}
+
+	// Appending after the prune continues from the stored counters.
+	if err := repo.Append(ctx, session, []*domain.QueueMessage{mustNewHistoryMessage(t, "m4", "x")}); err != nil {
+		t.Fatalf("Append after Prune: %v", err)
+	}
+	if session.MessageCount != 4 || session.Bytes != 4*recordSize {
+		t.Fatalf("session counters = %d msgs / %d bytes, want 4 / %d", session.MessageCount, session.Bytes, 4*recordSize)
+	}
+
+	page, _, err := repo.ReadSession(ctx, *session, "", 10)
+	if err != nil {
+		t.Fatalf("ReadSession: %v", err)
+	}
+	if len(page) != 4 || page[0].MessageID() != "m1" || page[3].MessageID() != "m4" {
+		t.Fatalf("unexpected retained messages: %v", page)
+	}
+}
+
+func TestTraceHistoryRepository_RejectsUninitializedAdapter(t *testing.T) {
+	t.Parallel()
+
+	repo := NewTraceHistoryRepository(&BoltAdapter{}, domain.DefaultHistoryRetention())
+	if _, err := repo.BeginSession(context.Background(), "DEV", "SUB_A"); !errors.Is(err, ErrAdapterNotInitialized) {
+		t.Fatalf("BeginSession error = %v, want ErrAdapterNotInitialized", err)
+	}
+}
+
+func TestBoltAdapter_HistoryRetentionRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	adapter := newTestBoltAdapter(t)
+
+	got, err := adapter.GetHistoryRetention()
+	if err != nil {
+		t.Fatalf("GetHistoryRetention: %v", err)
+	}
+	if got != domain.DefaultHistoryRetention() {
+		t.Fatalf("GetHistoryRetention() = %+v, want defaults", got)
+	}
+
+	want := domain.HistoryRetention{MaxAge: 24 * time.Hour, MaxBytes: 1 << 20}
+	if err := adapter.SetHistoryRetention(want); err != nil {
+		t.Fatalf("SetHistoryRetention: %v", err)
+	}
+	got, err = adapter.GetHistoryRetention()
+	if err != nil {
+		t.Fatalf("GetHistoryRetention: %v", err)
+	}
+	if got != want {
+		t.Fatalf("GetHistoryRetention() = %+v, want %+v", got, want)
+	}
+}
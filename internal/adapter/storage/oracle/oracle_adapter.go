@@ -17,6 +17,7 @@ import "C"
 import (
 	"OmniView/internal/core/domain"
 	"context"
+	"errors"
 	"fmt"
 	"unsafe"
 )
@@ -39,6 +40,17 @@ func NewOracleAdapter(cfg *domain.DatabaseSettings) *OracleAdapter {
 	}
 }
 
+// dpiError returns the error ODPI-C recorded for a failed call, prefixed with
+// what failed. Errors meaning the session dropped wrap domain.ErrConnectionLost,
+// so the tracer reconnects instead of retrying on a dead session.
+func dpiError(errInfo *C.dpiErrorInfo, what string) error {
+	msg := C.GoString(errInfo.message)
+	if domain.IsConnectionLostCode(int(errInfo.code)) || domain.IsConnectionLost(errors.New(msg)) {
+		return fmt.Errorf("%s: %w: %s (code: %d)", what, domain.ErrConnectionLost, msg, errInfo.code)
+	}
+	return fmt.Errorf("%s: %s (code: %d)", what, msg, errInfo.code)
+}
+
 // GetRawConnection returns the underlying DPI connection handle as unsafe.Pointer.
 // WARNING: this low-level API should be only used in tracer functionality.
 func (oa *OracleAdapter) GetRawConnection() unsafe.Pointer {
@@ -94,7 +106,7 @@ func (oa *OracleAdapter) ExecuteAndReturnStatement(query string) (*C.dpiStmt, er
 		var errInfo C.dpiErrorInfo
 		C.dpiContext_getError(oa.Context, &errInfo)
 		C.dpiStmt_release(stmt)
-		return nil, fmt.Errorf("failed to execute statement: %s", C.GoString(errInfo.message))
+		return nil, dpiError(&errInfo, "failed to execute statement")
 	}
 
 	return stmt, nil
@@ -111,7 +123,7 @@ func (oa *OracleAdapter) ExecuteWithStatement(stmt *C.dpiStmt) error {
 	if C.dpiStmt_execute(stmt, C.DPI_MODE_EXEC_DEFAULT, nil) != C.DPI_SUCCESS {
 		var errInfo C.dpiErrorInfo
 		C.dpiContext_getError(oa.Context, &errInfo)
-		return fmt.Errorf("failed to execute statement: %s", C.GoString(errInfo.message))
+		return dpiError(&errInfo, "failed to execute statement")
 	}
 
 	return nil
@@ -134,7 +146,7 @@ func (oa *OracleAdapter) FetchData(stmt *C.dpiStmt) ([]string, error) {
 		if fetch != C.DPI_SUCCESS {
 			var errInfo C.dpiErrorInfo
 			C.dpiContext_getError(oa.Context, &errInfo)
-			return nil, fmt.Errorf("failed to fetch data: %s", C.GoString(errInfo.message))
+			return nil, dpiError(&errInfo, "failed to fetch data")
 		}
 		if found == 0 {
 			break // No more rows
@@ -192,7 +204,7 @@ func (oa *OracleAdapter) PrepareStatement(query string) (*C.dpiStmt, error) {
 	if C.dpiConn_prepareStmt(oa.Connection, 0, cQuery, C.uint32_t(len(query)), nil, 0, &stmt) != C.DPI_SUCCESS {
 		var errInfo C.dpiErrorInfo
 		C.dpiContext_getError(oa.Context, &errInfo)
-		return nil, fmt.Errorf("failed to prepare statement: %s", C.GoString(errInfo.message))
+		return nil, dpiError(&errInfo, "failed to prepare statement")
 	}
 	return stmt, nil
 }
@@ -213,7 +225,7 @@ func (oa *OracleAdapter) ExecuteStatement(ctx context.Context, query string) err
 	if C.dpiStmt_execute(stmt, C.DPI_MODE_EXEC_DEFAULT, nil) != C.DPI_SUCCESS {
 		var errInfo C.dpiErrorInfo
 		C.dpiContext_getError(oa.Context, &errInfo)
-		return fmt.Errorf("failed to execute statement: %s", C.GoString(errInfo.message))
+		return dpiError(&errInfo, "failed to execute statement")
 	}
 
 	return nil
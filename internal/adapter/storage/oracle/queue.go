@@ -75,7 +75,7 @@ func (oa *OracleAdapter) BulkDequeueTracerMessages(ctx context.Context, subscrib
 			return []string{}, [][]byte{}, 0, nil
 		}
 
-		return nil, nil, 0, fmt.Errorf("failed to dequeue messages: %s (code: %d)", C.GoString(errInfo.message), errInfo.code)
+		return nil, nil, 0, dpiError(&errInfo, "failed to dequeue messages")
 	}
 	count := int(cCount)
 
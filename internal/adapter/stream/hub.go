@@ -0,0 +1,175 @@
+package stream
+
+// ==========================================
+// Trace Stream Adapter
+// ==========================================
+// Re-broadcasts delivered trace messages to browsers and scripts over
+// Server-Sent Events and WebSocket. The Hub is a ports.MessagePublisher set
+// on the tracer service beside the UI event channel; each connected client
+// gets its own buffer and filter, so a slow client drops its own messages
+// without holding up the dequeue loop or other clients.
+
+import (
+	"OmniView/internal/adapter/logger"
+	"OmniView/internal/adapter/metrics"
+	"OmniView/internal/core/domain"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// clientBufferSize is how many messages a client may fall behind before its
+// messages are dropped.
+const clientBufferSize = 256
+
+var (
+	streamDrops = metrics.NewCounter("omniview_stream_drops_total",
+		"Messages dropped for stream clients that could not keep up.")
+	_ = metrics.NewGaugeFunc("omniview_stream_clients",
+		"Connected SSE and WebSocket stream clients.", func() float64 { return float64(connectedClients.Load()) })
+)
+
+// connectedClients counts clients across hubs for the gauge above.
+var connectedClients atomic.Int64
+
+// ==========================================
+// Filter
+// ==========================================
+
+// Filter selects the messages a client receives. The zero value passes everything.
+type Filter struct {
+	MinLevel domain.LogLevel      // Lowest level delivered; empty for every level
+	Process  *regexp.Regexp       // Process names delivered; nil for every process
+	Mode     domain.BroadcastMode // Same meaning as the mode toggle in the UI
+}
+
+// ParseFilter reads the level, process and mode query parameters, e.g.
+// ?level=WARNING&process=^ORDER_&mode=subscriber.
+func ParseFilter(query url.Values) (Filter, error) {
+	var f Filter
+	if level := strings.TrimSpace(query.Get("level")); level != "" {
+		lvl, err := domain.NewLogLevel(level)
+		if err != nil {
+			return f, err
+		}
+		f.MinLevel = lvl
+	}
+	if process := query.Get("process"); process != "" {
+		re, err := regexp.Compile(process)
+		if err != nil {
+			return f, fmt.Errorf("invalid process pattern: %w", err)
+		}
+		f.Process = re
+	}
+	if mode := strings.TrimSpace(query.Get("mode")); mode != "" {
+		m, err := domain.ParseBroadcastMode(mode)
+		if err != nil {
+			return f, err
+		}
+		f.Mode = m
+	}
+	return f, nil
+}
+
+// Match reports whether msg passes the filter.
+func (f Filter) Match(msg *domain.QueueMessage) bool {
+	if f.MinLevel != "" && msg.LogLevel().Severity() < f.MinLevel.Severity() {
+		return false
+	}
+	if f.Process != nil && !f.Process.MatchString(msg.ProcessName()) {
+		return false
+	}
+	return f.Mode.Shows(msg)
+}
+
+// ==========================================
+// Hub
+// ==========================================
+
+// event is one encoded message queued for a client.
+type event struct {
+	id   string
+	data []byte
+}
+
+// client is one connected stream consumer.
+type client struct {
+	filter  Filter
+	send    chan event
+	dropped atomic.Uint64
+}
+
+// Hub fans published messages out to the connected clients.
+type Hub struct {
+	mu      sync.RWMutex
+	clients map[*client]struct{}
+}
+
+// NewHub creates a hub with no clients.
+func NewHub() *Hub {
+	return &Hub{clients: make(map[*client]struct{})}
+}
+
+// Publish queues msg for every client whose filter it passes. It never
+// blocks: a client with a full buffer loses the message.
+func (h *Hub) Publish(msg *domain.QueueMessage) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	var data []byte
+	for c := range h.clients {
+		if !c.filter.Match(msg) {
+			continue
+		}
+		// Encode once, and only when someone wants the message
+		if data == nil {
+			var err error
+			if data, err = json.Marshal(msg); err != nil {
+				logger.Warn("failed to encode message for stream clients", "msgID", msg.MessageID(), "error", err)
+				return
+			}
+		}
+		select {
+		case c.send <- event{id: msg.MessageID(), data: data}:
+		default:
+			c.dropped.Add(1)
+			streamDrops.Inc()
+		}
+	}
+}
+
+// Clients returns the number of connected clients.
+func (h *Hub) Clients() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.clients)
+}
+
+// subscribe registers a client with the given filter.
+func (h *Hub) subscribe(filter Filter) *client {
+	c := &client{filter: filter, send: make(chan event, clientBufferSize)}
+	h.mu.Lock()
+	h.clients[c] = struct{}{}
+	h.mu.Unlock()
+	connectedClients.Add(1)
+	return c
+}
+
+// unsubscribe removes c; messages already queued for it are discarded.
+func (h *Hub) unsubscribe(c *client) {
+	h.mu.Lock()
+	_, ok := h.clients[c]
+	delete(h.clients, c)
+	h.mu.Unlock()
+	if !ok {
+		return
+	}
+	connectedClients.Add(-1)
+	if dropped := c.dropped.Load(); dropped > 0 {
+		logger.Warn("stream client fell behind and lost messages", "dropped", dropped)
+	}
+}
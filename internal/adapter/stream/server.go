@@ -0,0 +1,190 @@
+package stream
+
+import (
+	"OmniView/internal/adapter/logger"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// keepaliveInterval keeps idle streams open through proxies and detects
+	// clients that went away without closing.
+	keepaliveInterval = 15 * time.Second
+	shutdownTimeout   = 5 * time.Second
+)
+
+// viewerPage is a single-page viewer for browsers without the TUI.
+//
+//go:embed viewer.html
+var viewerPage []byte
+
+// Server serves a Hub over HTTP:
+//
+//	/        a minimal browser viewer
+//	/events  Server-Sent Events, one "message" event per trace message
+//	/ws      WebSocket, one JSON text frame per trace message
+//
+// All three accept the level, process and mode query parameters of ParseFilter.
+type Server struct {
+	hub       *Hub
+	listener  net.Listener
+	server    *http.Server
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// Listen binds addr (host:port) and serves hub in the background. Binding
+// happens before Listen returns, so a port already in use is reported here.
+func Listen(addr string, hub *Hub) (*Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for stream clients on %s: %w", addr, err)
+	}
+
+	s := &Server{hub: hub, listener: listener, done: make(chan struct{})}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.serveViewer)
+	mux.HandleFunc("GET /events", s.serveEvents)
+	mux.HandleFunc("GET /ws", s.serveWebSocket)
+	s.server = &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("trace stream server stopped", "addr", listener.Addr().String(), "error", err)
+		}
+	}()
+	logger.Info("trace stream listening", "addr", listener.Addr().String())
+	return s, nil
+}
+
+// Addr returns the bound address, which resolves a ":0" port.
+func (s *Server) Addr() string { return s.listener.Addr().String() }
+
+// URL returns the address of the browser viewer.
+func (s *Server) URL() string { return "http://" + s.Addr() + "/" }
+
+// Close disconnects every client and stops the server.
+func (s *Server) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		// Streams never go idle on their own, so end them before Shutdown waits for idle connections
+		close(s.done)
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		err = s.server.Shutdown(ctx)
+	})
+	return err
+}
+
+// ==========================================
+// Handlers
+// ==========================================
+
+func (s *Server) serveViewer(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(viewerPage)
+}
+
+// serveEvents streams messages as Server-Sent Events until the client or the
+// server goes away.
+func (s *Server) serveEvents(w http.ResponseWriter, r *http.Request) {
+	filter, err := ParseFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // Stop reverse proxies from buffering the stream
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	c := s.hub.subscribe(filter)
+	defer s.hub.unsubscribe(c)
+	ticker := time.NewTicker(keepaliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-s.done:
+			return
+		case ev := <-c.send:
+			fmt.Fprintf(w, "id: %s\nevent: message\ndata: %s\n\n", sseField(ev.id), ev.data)
+		case <-ticker.C:
+			fmt.Fprint(w, ": keepalive\n\n")
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// sseField keeps a value on one SSE line.
+func sseField(s string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(s)
+}
+
+// serveWebSocket upgrades the request and streams messages as text frames
+// until the client or the server goes away.
+func (s *Server) serveWebSocket(w http.ResponseWriter, r *http.Request) {
+	filter, err := ParseFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !isWebSocketUpgrade(r) {
+		w.Header().Set("Upgrade", "websocket")
+		http.Error(w, "expected a WebSocket upgrade", http.StatusUpgradeRequired)
+		return
+	}
+	if !sameOrigin(r) {
+		http.Error(w, "cross-origin WebSocket connections are not allowed", http.StatusForbidden)
+		return
+	}
+
+	ws, err := upgradeWebSocket(w, r)
+	if err != nil {
+		logger.Warn("WebSocket upgrade failed", "remote", r.RemoteAddr, "error", err)
+		return
+	}
+	defer ws.Close()
+
+	c := s.hub.subscribe(filter)
+	defer s.hub.unsubscribe(c)
+	clientGone := make(chan struct{})
+	go ws.readLoop(clientGone)
+	ticker := time.NewTicker(keepaliveInterval)
+	defer ticker.Stop()
+
+	for {
+		var err error
+		select {
+		case <-clientGone:
+			return
+		case <-s.done:
+			_ = ws.writeClose(closeGoingAway)
+			return
+		case ev := <-c.send:
+			err = ws.writeFrame(opText, ev.data)
+		case <-ticker.C:
+			err = ws.writeFrame(opPing, nil)
+		}
+		if err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package stream
+
+import (
+	"OmniView/internal/core/domain"
+	"bufio"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestMessage(t *testing.T, id string, level domain.LogLevel, process string) *domain.QueueMessage {
+	t.Helper()
+
+	msg, err := domain.NewQueueMessage(id, process, level, "payload "+id, time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("NewQueueMessage: %v", err)
+	}
+	return msg
+}
+
+// newTestServer starts a server on a free loopback port.
+func newTestServer(t *testing.T) (*Hub, *Server) {
+	t.Helper()
+
+	hub := NewHub()
+	s, err := Listen("127.0.0.1:0", hub)
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	return hub, s
+}
+
+// waitForClients blocks until n clients are subscribed, so published messages
+// are not lost to a client that is still connecting.
+func waitForClients(t *testing.T, hub *Hub, n int) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for hub.Clients() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d clients, have %d", n, hub.Clients())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestParseFilter_MatchesLevelProcessAndMode(t *testing.T) {
+	t.Parallel()
+
+	f, err := ParseFilter(url.Values{"level": {"warning"}, "process": {`^ORDER_`}, "mode": {"broadcast"}})
+	if err != nil {
+		t.Fatalf("ParseFilter: %v", err)
+	}
+	cases := []struct {
+		level   domain.LogLevel
+		process string
+		want    bool
+	}{
+		{domain.LogLevelError, "ORDER_API", true},
+		{domain.LogLevelInfo, "ORDER_API", false},
+		{domain.LogLevelCritical, "BILLING", false},
+	}
+	for _, tc := range cases {
+		if got := f.Match(newTestMessage(t, "1", tc.level, tc.process)); got != tc.want {
+			t.Errorf("Match(%s %s) = %v, want %v", tc.level, tc.process, got, tc.want)
+		}
+	}
+
+	subscriberOnly, _ := ParseFilter(url.Values{"mode": {"subscriber"}})
+	if subscriberOnly.Match(newTestMessage(t, "1", domain.LogLevelInfo, "P")) {
+		t.Error("expected a global message to be hidden in subscriber mode")
+	}
+
+	for _, query := range []url.Values{{"level": {"LOUD"}}, {"process": {"("}}, {"mode": {"everyone"}}} {
+		if _, err := ParseFilter(query); err == nil {
+			t.Errorf("ParseFilter(%v) succeeded, want an error", query)
+		}
+	}
+}
+
+func TestHub_DropsForSlowClientsWithoutBlocking(t *testing.T) {
+	t.Parallel()
+
+	hub := NewHub()
+	c := hub.subscribe(Filter{})
+	defer hub.unsubscribe(c)
+
+	for i := 0; i < clientBufferSize+10; i++ {
+		hub.Publish(newTestMessage(t, fmt.Sprint(i), domain.LogLevelInfo, "P"))
+	}
+	if got := c.dropped.Load(); got != 10 {
+		t.Fatalf("dropped = %d, want 10", got)
+	}
+	if len(c.send) != clientBufferSize {
+		t.Fatalf("buffered = %d, want %d", len(c.send), clientBufferSize)
+	}
+}
+
+func TestServer_StreamsServerSentEvents(t *testing.T) {
+	t.Parallel()
+
+	hub, s := newTestServer(t)
+
+	resp, err := http.Get("http://" + s.Addr() + "/events?level=ERROR")
+	if err != nil {
+		t.Fatalf("GET /events: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	waitForClients(t, hub, 1)
+
+	hub.Publish(newTestMessage(t, "skipped", domain.LogLevelInfo, "P"))
+	hub.Publish(newTestMessage(t, "sent", domain.LogLevelError, "P"))
+
+	// Collect the lines of the first event, skipping comments
+	reader := bufio.NewReader(resp.Body)
+	var lines []string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading event: %v (so far %q)", err, lines)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" && len(lines) > 0 {
+			break
+		}
+		if line != "" && !strings.HasPrefix(line, ":") {
+			lines = append(lines, line)
+		}
+	}
+	if lines[0] != "id: sent" || lines[1] != "event: message" || !strings.HasPrefix(lines[2], "data: {") {
+		t.Fatalf("unexpected event %q", lines)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(lines[2], "data: ")), &decoded); err != nil || decoded["message_id"] != "sent" {
+		t.Fatalf("event data %q (err %v)", lines[2], err)
+	}
+
+	if resp, err := http.Get("http://" + s.Addr() + "/events?mode=everyone"); err != nil || resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected a bad filter to be rejected, got %v (err %v)", resp.Status, err)
+	}
+}
+
+// dialWebSocket performs the client side of the handshake.
+func dialWebSocket(t *testing.T, addr, query, origin string) (net.Conn, *bufio.Reader, *http.Response) {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	var nonce [16]byte
+	_, _ = rand.Read(nonce[:])
+	key := base64.StdEncoding.EncodeToString(nonce[:])
+	fmt.Fprintf(conn, "GET /ws%s HTTP/1.1\r\nHost: %s\r\nUpgrade: websocket\r\nConnection: keep-alive, Upgrade\r\nSec-WebSocket-Key: %s\r\nSec-WebSocket-Version: 13\r\n", query, addr, key)
+	if origin != "" {
+		fmt.Fprintf(conn, "Origin: %s\r\n", origin)
+	}
+	fmt.Fprint(conn, "\r\n")
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatalf("ReadResponse: %v", err)
+	}
+	if resp.StatusCode == http.StatusSwitchingProtocols && resp.Header.Get("Sec-WebSocket-Accept") != acceptKey(key) {
+		t.Fatalf("Sec-WebSocket-Accept = %q", resp.Header.Get("Sec-WebSocket-Accept"))
+	}
+	return conn, reader, resp
+}
+
+// writeMaskedFrame sends a client frame, which must be masked.
+func writeMaskedFrame(t *testing.T, conn net.Conn, opcode byte, payload []byte) {
+	t.Helper()
+
+	mask := [4]byte{1, 2, 3, 4}
+	frame := []byte{0x80 | opcode, 0x80 | byte(len(payload))}
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	if _, err := conn.Write(frame); err != nil {
+		t.Fatalf("write frame: %v", err)
+	}
+}
+
+// readServerFrame reads one unmasked server frame.
+func readServerFrame(t *testing.T, r *bufio.Reader) (byte, []byte) {
+	t.Helper()
+
+	var head [2]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		t.Fatalf("read frame header: %v", err)
+	}
+	length := int(head[1] & 0x7F)
+	if length == 126 {
+		var ext [2]byte
+		io.ReadFull(r, ext[:])
+		length = int(binary.BigEndian.Uint16(ext[:]))
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		t.Fatalf("read frame payload: %v", err)
+	}
+	return head[0] & 0x0F, payload
+}
+
+func TestServer_StreamsWebSocketFrames(t *testing.T) {
+	t.Parallel()
+
+	hub, s := newTestServer(t)
+
+	conn, reader, resp := dialWebSocket(t, s.Addr(), "?process=^ORDER_", "http://"+s.Addr())
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("handshake status %d", resp.StatusCode)
+	}
+	waitForClients(t, hub, 1)
+
+	hub.Publish(newTestMessage(t, "skipped", domain.LogLevelInfo, "BILLING"))
+	hub.Publish(newTestMessage(t, "sent", domain.LogLevelInfo, "ORDER_API"))
+	opcode, payload := readServerFrame(t, reader)
+	if opcode != opText || !strings.Contains(string(payload), `"message_id":"sent"`) {
+		t.Fatalf("frame %x %s", opcode, payload)
+	}
+
+	writeMaskedFrame(t, conn, opPing, []byte("hi"))
+	if opcode, payload := readServerFrame(t, reader); opcode != opPong || string(payload) != "hi" {
+		t.Fatalf("expected a pong echoing the ping, got %x %q", opcode, payload)
+	}
+
+	writeMaskedFrame(t, conn, opClose, binary.BigEndian.AppendUint16(nil, closeNormal))
+	if opcode, _ := readServerFrame(t, reader); opcode != opClose {
+		t.Fatalf("expected a close frame, got %x", opcode)
+	}
+	waitForClients(t, hub, 0)
+}
+
+func TestServer_RejectsCrossOriginWebSockets(t *testing.T) {
+	t.Parallel()
+
+	_, s := newTestServer(t)
+	if _, _, resp := dialWebSocket(t, s.Addr(), "", "https://evil.example"); resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("status %d, want %d", resp.StatusCode, http.StatusForbidden)
+	}
+}
+
+func TestServer_CloseEndsOpenStreams(t *testing.T) {
+	t.Parallel()
+
+	hub, s := newTestServer(t)
+	resp, err := http.Get("http://" + s.Addr() + "/events")
+	if err != nil {
+		t.Fatalf("GET /events: %v", err)
+	}
+	defer resp.Body.Close()
+	waitForClients(t, hub, 1)
+
+	closed := make(chan error, 1)
+	go func() { closed <- s.Close() }()
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+	case <-time.After(shutdownTimeout):
+		t.Fatal("Close waited for the open stream")
+	}
+	waitForClients(t, hub, 0)
+}
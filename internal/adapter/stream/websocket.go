@@ -0,0 +1,210 @@
+package stream
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ==========================================
+// WebSocket Protocol (RFC 6455)
+// ==========================================
+// Just enough of the protocol for a server that only sends: text frames out,
+// and ping and close handling for whatever the client sends back.
+
+// websocketGUID is appended to the client key to derive Sec-WebSocket-Accept.
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+const (
+	opText  byte = 0x1
+	opClose byte = 0x8
+	opPing  byte = 0x9
+	opPong  byte = 0xA
+)
+
+const (
+	maxClientFrameSize = 64 << 10 // Larger client frames end the connection
+	wsWriteTimeout     = 10 * time.Second
+
+	closeNormal    = 1000
+	closeGoingAway = 1001
+)
+
+var errWebSocketProtocol = errors.New("websocket protocol error")
+
+// isWebSocketUpgrade reports whether r asks for a version 13 WebSocket.
+func isWebSocketUpgrade(r *http.Request) bool {
+	return r.Method == http.MethodGet &&
+		headerContainsToken(r.Header, "Connection", "upgrade") &&
+		strings.EqualFold(r.Header.Get("Upgrade"), "websocket") &&
+		r.Header.Get("Sec-WebSocket-Version") == "13" &&
+		r.Header.Get("Sec-WebSocket-Key") != ""
+}
+
+// headerContainsToken reports whether a comma-separated header lists token.
+func headerContainsToken(h http.Header, name, token string) bool {
+	for _, value := range h.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sameOrigin rejects browser pages from other sites, which could otherwise
+// read a colleague's trace stream through their browser. Clients that send
+// no Origin (scripts, curl) are allowed.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+// acceptKey derives the Sec-WebSocket-Accept value for a client key.
+func acceptKey(key string) string {
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// wsConn is a hijacked connection speaking the WebSocket framing.
+type wsConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+	mu   sync.Mutex // Serialises writes from the stream loop and the read loop
+	w    *bufio.Writer
+}
+
+// upgradeWebSocket completes the handshake and takes over the connection.
+func upgradeWebSocket(w http.ResponseWriter, r *http.Request) (*wsConn, error) {
+	conn, brw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		return nil, fmt.Errorf("failed to take over the connection: %w", err)
+	}
+	ws := &wsConn{conn: conn, r: brw.Reader, w: brw.Writer}
+
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	_ = conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	fmt.Fprintf(ws.w, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n",
+		acceptKey(r.Header.Get("Sec-WebSocket-Key")))
+	if err := ws.w.Flush(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to complete the WebSocket handshake: %w", err)
+	}
+	return ws, nil
+}
+
+// writeFrame sends one unfragmented, unmasked frame.
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	header := []byte{0x80 | opcode, 0}
+	switch n := len(payload); {
+	case n < 126:
+		header[1] = byte(n)
+	case n <= 0xFFFF:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+
+	_ = c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	if _, err := c.w.Write(header); err != nil {
+		return err
+	}
+	if _, err := c.w.Write(payload); err != nil {
+		return err
+	}
+	return c.w.Flush()
+}
+
+// writeClose sends a close frame with the given status code.
+func (c *wsConn) writeClose(code uint16) error {
+	return c.writeFrame(opClose, binary.BigEndian.AppendUint16(nil, code))
+}
+
+// readFrame reads one client frame and unmasks its payload.
+func (c *wsConn) readFrame() (opcode byte, payload []byte, err error) {
+	var head [2]byte
+	if _, err := io.ReadFull(c.r, head[:]); err != nil {
+		return 0, nil, err
+	}
+	opcode = head[0] & 0x0F
+	if head[1]&0x80 == 0 {
+		return 0, nil, fmt.Errorf("%w: client frame is not masked", errWebSocketProtocol)
+	}
+
+	length := uint64(head[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > maxClientFrameSize {
+		return 0, nil, fmt.Errorf("%w: client frame of %d bytes", errWebSocketProtocol, length)
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(c.r, mask[:]); err != nil {
+		return 0, nil, err
+	}
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(c.r, payload); err != nil {
+		return 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return opcode, payload, nil
+}
+
+// readLoop answers pings and close frames until the client goes away, then
+// closes done. Data frames from the client are ignored.
+func (c *wsConn) readLoop(done chan<- struct{}) {
+	defer close(done)
+	for {
+		opcode, payload, err := c.readFrame()
+		if err != nil {
+			return
+		}
+		switch opcode {
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return
+			}
+		case opClose:
+			_ = c.writeClose(closeNormal)
+			return
+		}
+	}
+}
+
+// Close closes the underlying connection.
+func (c *wsConn) Close() error { return c.conn.Close() }
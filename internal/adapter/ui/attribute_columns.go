@@ -0,0 +1,234 @@
+package ui
+
+import (
+	"OmniView/internal/adapter/logger"
+	"OmniView/internal/adapter/ui/styles"
+	"OmniView/internal/core/domain"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+)
+
+const (
+	// maxAttributeColumns bounds how many attributes can be shown as columns.
+	maxAttributeColumns = 4
+	// Attribute column widths; values longer than the column are truncated.
+	colMinAttrWidth = 10
+	colMaxAttrWidth = 16
+)
+
+// ==========================================
+// Attribute Columns Sub-State
+// ==========================================
+
+// attributeColumnsState holds the message attributes shown as trace columns
+// and the "o" overlay that picks them.
+type attributeColumnsState struct {
+	columns []string // Attribute names shown as columns, in display order
+	visible bool     // Whether the "o" overlay is open
+	cursor  int      // Selected row in the overlay
+	keys    []string // Attribute names listed in the overlay
+}
+
+// attributeColumn is one attribute column in a traceColumnLayout.
+type attributeColumn struct {
+	key   string
+	width int
+}
+
+// ==========================================
+// Helpers
+// ==========================================
+
+// loadAttributeColumns restores the attribute columns saved for the active database.
+func (m *Model) loadAttributeColumns() {
+	m.attrColumns = attributeColumnsState{}
+	if m.boltAdapter == nil || m.appConfig == nil {
+		return
+	}
+	columns, err := m.boltAdapter.GetAttributeColumns(m.appConfig.DatabaseID())
+	if err != nil {
+		logger.Warn("failed to load attribute columns", "databaseID", m.appConfig.DatabaseID(), "error", err)
+		return
+	}
+	m.attrColumns.columns = columns
+}
+
+// openAttributeColumnsOverlay lists every attribute name in the buffer along
+// with the configured columns, so columns can be removed while no message
+// carrying them is buffered.
+func (m *Model) openAttributeColumnsOverlay() {
+	seen := make(map[string]bool)
+	for _, msg := range m.main.messages {
+		for _, key := range msg.AttributeKeys() {
+			seen[key] = true
+		}
+	}
+	for _, key := range m.attrColumns.columns {
+		seen[key] = true
+	}
+	m.attrColumns.keys = slices.Sorted(maps.Keys(seen))
+	m.attrColumns.visible = true
+	m.attrColumns.cursor = 0
+}
+
+// toggleAttributeColumn shows or hides key as a column. New columns are added
+// on the right; at most maxAttributeColumns are shown.
+func (m *Model) toggleAttributeColumn(key string) {
+	if i := slices.Index(m.attrColumns.columns, key); i >= 0 {
+		m.attrColumns.columns = slices.Delete(slices.Clone(m.attrColumns.columns), i, i+1)
+	} else if len(m.attrColumns.columns) < maxAttributeColumns {
+		m.attrColumns.columns = append(slices.Clone(m.attrColumns.columns), key)
+	} else {
+		return
+	}
+	m.applyAttributeColumns()
+}
+
+// applyAttributeColumns saves the columns for the current database and
+// re-renders the viewport.
+func (m *Model) applyAttributeColumns() {
+	if m.boltAdapter != nil && m.appConfig != nil {
+		if err := m.boltAdapter.SetAttributeColumns(m.appConfig.DatabaseID(), m.attrColumns.columns); err != nil {
+			logger.Error("failed to save attribute columns", "databaseID", m.appConfig.DatabaseID(), "error", err)
+		}
+	}
+	if m.main.ready {
+		m.rebuildRenderedContent(m.main.viewport.Width())
+		if m.main.autoScroll {
+			m.main.viewport.GotoBottom()
+		}
+	}
+}
+
+// withAttributeColumns carves the configured attribute columns out of the
+// payload column. Columns that would shrink the payload below
+// colMinPayloadWidth are left out.
+func (m *Model) withAttributeColumns(layout traceColumnLayout) traceColumnLayout {
+	for _, key := range m.attrColumns.columns {
+		width := min(max(lipgloss.Width(key), colMinAttrWidth), colMaxAttrWidth)
+		if layout.payloadWidth-width-len(colSeparator) < colMinPayloadWidth {
+			break
+		}
+		layout.attrColumns = append(layout.attrColumns, attributeColumn{key: key, width: width})
+		layout.payloadWidth -= width + len(colSeparator)
+	}
+	return layout
+}
+
+// attributeValues returns the sanitized values of msg for each attribute
+// column in layout; missing attributes are empty.
+func attributeValues(msg *domain.QueueMessage, layout traceColumnLayout) []string {
+	if len(layout.attrColumns) == 0 {
+		return nil
+	}
+	values := make([]string, len(layout.attrColumns))
+	for i, col := range layout.attrColumns {
+		if value, ok := msg.Attribute(col.key); ok {
+			values[i] = truncate(strings.Join(strings.Fields(sanitizeLogString(value)), " "), col.width)
+		}
+	}
+	return values
+}
+
+// ==========================================
+// Update
+// ==========================================
+
+// updateAttributeColumnsOverlay handles keyboard input while the overlay is open.
+func (m *Model) updateAttributeColumnsOverlay(msg tea.KeyPressMsg) (*Model, tea.Cmd) {
+	keys := m.attrColumns.keys
+	switch msg.String() {
+	case "ctrl+c":
+		m.cancel()
+		return m, tea.Quit
+	case "esc", "o", "q":
+		m.attrColumns.visible = false
+	case "up", "k":
+		if len(keys) > 0 {
+			m.attrColumns.cursor = (m.attrColumns.cursor - 1 + len(keys)) % len(keys)
+		}
+	case "down", "j":
+		if len(keys) > 0 {
+			m.attrColumns.cursor = (m.attrColumns.cursor + 1) % len(keys)
+		}
+	case "space", "enter":
+		if len(keys) > 0 {
+			m.toggleAttributeColumn(keys[m.attrColumns.cursor])
+		}
+	case "r":
+		m.attrColumns.columns = nil
+		m.applyAttributeColumns()
+	}
+	return m, nil
+}
+
+// ==========================================
+// View
+// ==========================================
+
+// viewAttributeColumnsOverlay renders the attribute names with their column state.
+func (m *Model) viewAttributeColumnsOverlay() string {
+	contentWidth, _ := screenContentSize(m.width, m.height)
+	panelWidth := max(min(contentWidth-4, levelOverlayMaxWidth), 1)
+	innerWidth := max(panelWidth-4, 1)
+
+	var body string
+	if len(m.attrColumns.keys) == 0 {
+		body = styles.BodyTextStyle.Width(innerWidth).Render("No buffered message has attributes yet. Send some with OMNI_TRACER_API.Trace_Message_With_Props.")
+	} else {
+		rows := make([]string, 0, len(m.attrColumns.keys))
+		for i, key := range m.attrColumns.keys {
+			marker := "  "
+			if i == m.attrColumns.cursor {
+				marker = formCursorStyle.Render("› ")
+			}
+			check := styles.SubtitleStyle.Render("[ ]")
+			position := ""
+			if pos := slices.Index(m.attrColumns.columns, key); pos >= 0 {
+				check = lipgloss.NewStyle().Foreground(styles.SuccessColor).Render("[x]")
+				position = styles.SubtitleStyle.Render(fmt.Sprintf("  column %d", pos+1))
+			}
+			rows = append(rows, truncateRendered(marker+check+" "+styles.SyntaxAttrStyle.Render(sanitizeLogString(key))+position, innerWidth))
+		}
+		body = strings.Join(rows, "\n")
+	}
+
+	parts := []string{
+		styles.SubtitleStyle.Width(innerWidth).Render(fmt.Sprintf("Show up to %d message attributes as columns before the payload.", maxAttributeColumns)),
+		"",
+		body,
+		"",
+		styles.OnboardingHintStyle.Width(innerWidth).Render("↑/↓ Select  •  Space Toggle  •  R Remove All  •  Esc Close"),
+	}
+
+	return renderFramedPanel("Attribute Columns", panelWidth, panelTypeInfo, lipgloss.JoinVertical(lipgloss.Left, parts...))
+}
+
+// mainColumnHeader labels the trace columns when attribute columns are shown,
+// since their values alone do not say which attribute they hold. It takes the
+// blank line under the feed subtitle, so it is empty otherwise.
+func (m *Model) mainColumnHeader(width int) string {
+	if len(m.attrColumns.columns) == 0 || width < colMinWidth {
+		return ""
+	}
+	layout := m.traceColumnLayout(width)
+	if len(layout.attrColumns) == 0 {
+		return ""
+	}
+	header := styles.SubtitleStyle
+	cells := []string{
+		header.Width(layout.timestampWidth).Render("Timestamp"),
+		header.Width(layout.levelWidth).Render("Level"),
+		header.Width(layout.apiWidth).Render("Process"),
+	}
+	for _, col := range layout.attrColumns {
+		cells = append(cells, styles.SyntaxAttrStyle.Bold(true).Width(col.width).Render(truncate(sanitizeLogString(col.key), col.width)))
+	}
+	cells = append(cells, header.Render("Payload"))
+	return truncateRendered(strings.Join(cells, colSeparator), width)
+}
@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"OmniView/internal/core/domain"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// newTestAttributeModel returns a ready main-screen model backed by BoltDB
+// holding count messages, every other one carrying tenant and order_id attributes.
+func newTestAttributeModel(t *testing.T, count int) *Model {
+	t.Helper()
+
+	m := newTestMainModel(t, 160, 30)
+	m.boltAdapter = newTestBoltAdapter(t)
+	m.appConfig = newTestDatabaseSettings(t, "DEV")
+	m.initViewport()
+	for i := 0; i < count; i++ {
+		msg, err := domain.NewQueueMessage(fmt.Sprintf("id-%d", i), "ORDER_API", domain.LogLevelInfo, fmt.Sprintf("order step %d", i), time.Unix(1700000000, 0))
+		if err != nil {
+			t.Fatalf("NewQueueMessage: %v", err)
+		}
+		if i%2 == 0 {
+			msg.SetAttributes(map[string]string{"tenant": "ACME", "order_id": fmt.Sprint(4700 + i)})
+		}
+		m.Update(queueMessageMsg{message: msg})
+	}
+	return m
+}
+
+func TestAttributeColumns_OverlayTogglesPersistedColumns(t *testing.T) {
+	t.Parallel()
+
+	m := newTestAttributeModel(t, 4)
+	m.Update(makeCharPress("o"))
+	if !m.attrColumns.visible || !slices.Equal(m.attrColumns.keys, []string{"order_id", "tenant"}) {
+		t.Fatalf("expected the overlay to list the buffered attribute names, got %v", m.attrColumns.keys)
+	}
+
+	m.Update(makeKeyPress(tea.KeyDown))
+	m.Update(makeKeyPress(tea.KeySpace))
+	m.Update(makeKeyPress(tea.KeyUp))
+	m.Update(makeKeyPress(tea.KeySpace))
+	if !slices.Equal(m.attrColumns.columns, []string{"tenant", "order_id"}) {
+		t.Fatalf("columns = %v, want them in the order they were picked", m.attrColumns.columns)
+	}
+	if got, _ := m.boltAdapter.GetAttributeColumns("DEV"); !slices.Equal(got, m.attrColumns.columns) {
+		t.Fatalf("saved columns = %v", got)
+	}
+
+	if _, cmd := m.Update(makeCharPress("q")); cmd != nil || m.attrColumns.visible {
+		t.Fatal("expected q to close the overlay without quitting")
+	}
+
+	first := ansi.Strip(m.main.renderedLines[0])
+	if !strings.Contains(first, "ACME") || !strings.Contains(first, "4700") || strings.Index(first, "ACME") > strings.Index(first, "order step") {
+		t.Fatalf("expected attribute values between process and payload, got %q", first)
+	}
+	if !strings.Contains(ansi.Strip(m.viewMain()), "tenant") {
+		t.Fatal("expected a column header naming the attribute columns")
+	}
+
+	// Columns are restored per database.
+	m.loadAttributeColumns()
+	if !slices.Equal(m.attrColumns.columns, []string{"tenant", "order_id"}) {
+		t.Fatalf("restored columns = %v", m.attrColumns.columns)
+	}
+
+	m.Update(makeCharPress("o"))
+	m.Update(makeCharPress("r"))
+	if m.attrColumns.columns != nil || strings.Contains(ansi.Strip(m.main.renderedLines[0]), "ACME") {
+		t.Fatal("expected R to remove every attribute column")
+	}
+}
+
+func TestAttributeColumns_NarrowTerminalDropsColumns(t *testing.T) {
+	t.Parallel()
+
+	m := newTestAttributeModel(t, 2)
+	m.attrColumns.columns = []string{"tenant", "order_id"}
+
+	layout := m.withAttributeColumns(newTraceColumnLayout(colMinWidth+colMinAttrWidth+1, colMinLevelWidth, colMinAPIWidth))
+	if len(layout.attrColumns) != 1 || layout.payloadWidth < colMinPayloadWidth {
+		t.Fatalf("expected one column to fit without shrinking the payload, got %d columns and payload width %d", len(layout.attrColumns), layout.payloadWidth)
+	}
+}
+
+func TestMessageDetail_ShowsAttributes(t *testing.T) {
+	t.Parallel()
+
+	m := newTestAttributeModel(t, 1)
+	body := ansi.Strip(m.messageDetailBody(m.main.messages[0], 80))
+	for _, want := range []string{"Attributes", "order_id         4700", "tenant           ACME"} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("detail body is missing %q:\n%s", want, body)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+package ui
+
+import (
+	"OmniView/internal/adapter/ui/styles"
+	"OmniView/internal/core/domain"
+	"fmt"
+	"image/color"
+	"math"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+)
+
+const (
+	// healthRefreshInterval is how often the header re-reads the probe results.
+	healthRefreshInterval = time.Second
+	// healthStaleGrace is added to the probe interval and the dequeue wait time
+	// before a missing probe counts as a stalled connection.
+	healthStaleGrace = 5 * time.Second
+	// healthOverlayMaxWidth caps the connection health overlay on wide terminals.
+	healthOverlayMaxWidth = 90
+)
+
+// sparkBlocks are the sparkline glyphs from lowest to highest.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// ==========================================
+// Connection Health Sub-State
+// ==========================================
+
+// connectionHealthState holds the latest probe results of the tracer service
+// and the "i" overlay that charts them.
+type connectionHealthState struct {
+	snapshot domain.ConnectionHealth
+	ticking  bool // Whether the refresh tick is scheduled
+	visible  bool // Whether the "i" overlay is open
+}
+
+// ==========================================
+// Helpers
+// ==========================================
+
+// healthTickCmd schedules the next health refresh.
+func healthTickCmd() tea.Cmd {
+	return tea.Tick(healthRefreshInterval, func(time.Time) tea.Msg { return healthTickMsg{} })
+}
+
+// startHealthTicker starts the refresh tick unless it is already running.
+func (m *Model) startHealthTicker() tea.Cmd {
+	if m.health.ticking {
+		return nil
+	}
+	m.health.ticking = true
+	return healthTickCmd()
+}
+
+// refreshConnectionHealth copies the latest probe results from the tracer service.
+func (m *Model) refreshConnectionHealth() {
+	if m.tracerService == nil {
+		m.health.snapshot = domain.ConnectionHealth{}
+		return
+	}
+	m.health.snapshot = m.tracerService.ConnectionHealth()
+}
+
+// healthStaleAfter returns how old the latest probe may get before the
+// connection counts as stalled. Probes wait for the blocking dequeue to
+// return, so the subscriber wait time is part of the allowance.
+func (m *Model) healthStaleAfter() time.Duration {
+	wait := time.Duration(domain.DefaultWaitTime) * time.Second
+	if m.subscriber != nil {
+		wait = time.Duration(m.subscriber.WaitTime().Int()) * time.Second
+	}
+	return m.health.snapshot.ProbeInterval + wait + healthStaleGrace
+}
+
+// connectionStatus returns the status shown for the connection, reporting a
+// probe that stopped completing as stalled. Probes pause while the listener
+// reconnects, so a reconnect is reported before the stale check.
+func (m *Model) connectionStatus(now time.Time) (text string, c color.Color) {
+	health := m.health.snapshot
+	if health.Reconnecting() {
+		return "RECONNECTING", styles.WarningColor
+	}
+	if health.Stale(now, m.healthStaleAfter()) {
+		return "STALLED", styles.ErrorColor
+	}
+	switch health.Status() {
+	case domain.ConnectionStatusHealthy:
+		return "HEALTHY", styles.SuccessColor
+	case domain.ConnectionStatusDegraded:
+		return "DEGRADED", styles.WarningColor
+	case domain.ConnectionStatusDown:
+		return "DOWN", styles.ErrorColor
+	default:
+		return "PROBING", styles.MutedColor
+	}
+}
+
+// formatLatency renders a probe round trip with millisecond precision.
+func formatLatency(d time.Duration) string {
+	if d < time.Millisecond {
+		return "<1ms"
+	}
+	if d < 10*time.Second {
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	}
+	return d.Round(time.Second).String()
+}
+
+// formatRate renders messages per second.
+func formatRate(rate float64) string {
+	if rate >= 10 || rate == 0 {
+		return fmt.Sprintf("%.0f msg/s", rate)
+	}
+	return fmt.Sprintf("%.1f msg/s", rate)
+}
+
+// sparkline draws the last width values as block glyphs scaled to the
+// largest of them. NaN values are gaps, drawn as "·".
+func sparkline(values []float64, width int) string {
+	if width <= 0 || len(values) == 0 {
+		return ""
+	}
+	values = values[max(len(values)-width, 0):]
+	peak := 0.0
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			peak = max(peak, v)
+		}
+	}
+	var b strings.Builder
+	for _, v := range values {
+		switch {
+		case math.IsNaN(v):
+			b.WriteRune('·')
+		case peak <= 0:
+			b.WriteRune(sparkBlocks[0])
+		default:
+			level := int(v / peak * float64(len(sparkBlocks)-1))
+			b.WriteRune(sparkBlocks[min(max(level, 0), len(sparkBlocks)-1)])
+		}
+	}
+	return b.String()
+}
+
+// ==========================================
+// Update
+// ==========================================
+
+// handleHealthTick refreshes the health snapshot and schedules the next tick.
+func (m *Model) handleHealthTick() tea.Cmd {
+	m.refreshConnectionHealth()
+	return healthTickCmd()
+}
+
+// updateConnectionHealthOverlay handles keyboard input while the overlay is open.
+func (m *Model) updateConnectionHealthOverlay(msg tea.KeyPressMsg) (*Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		m.cancel()
+		return m, tea.Quit
+	case "esc", "i", "q":
+		m.health.visible = false
+	}
+	return m, nil
+}
+
+// ==========================================
+// View
+// ==========================================
+
+// mainConnectionMeta: returns the connection health summary shown at the right of the header.
+func (m *Model) mainConnectionMeta() string {
+	if m.tracerService == nil {
+		return ""
+	}
+	now := time.Now()
+	status, statusColor := m.connectionStatus(now)
+	dot := lipgloss.NewStyle().Foreground(statusColor).Render("●")
+	label := lipgloss.NewStyle().Foreground(statusColor).Bold(true).Render(status)
+	sep := styles.SubtitleStyle.Render("  •  ")
+
+	latest, ok := m.health.snapshot.Latest()
+	switch {
+	case m.health.snapshot.Reconnecting():
+		return dot + " " + lipgloss.NewStyle().Foreground(statusColor).Bold(true).Render(fmt.Sprintf("reconnecting (attempt %d)", m.health.snapshot.ReconnectAttempt))
+	case !ok:
+		return dot + " " + label
+	case status == "STALLED":
+		return dot + " " + label + sep + styles.BodyTextStyle.Render("no probe for "+now.Sub(latest.At).Round(time.Second).String())
+	case latest.Err != "":
+		return dot + " " + label + sep + styles.BodyTextStyle.Render("I for details")
+	}
+
+	parts := []string{dot + " " + styles.BodyTextStyle.Render(formatLatency(latest.Latency)), styles.BodyTextStyle.Render(formatRate(latest.MessagesPerSecond))}
+	if latest.QueueDepth >= 0 {
+		parts = append(parts, styles.BodyTextStyle.Render(fmt.Sprintf("queue %d", latest.QueueDepth)))
+	}
+	return strings.Join(parts, sep)
+}
+
+// viewConnectionHealthOverlay renders the probe history with sparklines.
+func (m *Model) viewConnectionHealthOverlay() string {
+	contentWidth, _ := screenContentSize(m.width, m.height)
+	panelWidth := max(min(contentWidth-4, healthOverlayMaxWidth), 1)
+	innerWidth := max(panelWidth-4, 1)
+	now := time.Now()
+
+	label := func(name string) string {
+		return styles.SubtitleStyle.Render(fmt.Sprintf("%-14s", name))
+	}
+	value := styles.BodyTextStyle
+
+	health := m.health.snapshot
+	status, statusColor := m.connectionStatus(now)
+	lines := []string{label("Status") + lipgloss.NewStyle().Foreground(statusColor).Bold(true).Render(status)}
+	if health.Reconnecting() {
+		lines = append(lines, label("Reconnect")+value.Render(fmt.Sprintf("attempt %d, backing off up to a minute between attempts", health.ReconnectAttempt)))
+		if health.ReconnectErr != "" {
+			lines = append(lines, label("Cause")+styles.OnboardingErrorStyle.Render(sanitizeLogString(health.ReconnectErr)))
+		}
+	}
+
+	latest, ok := health.Latest()
+	if !ok && m.tracerService == nil {
+		lines = append(lines, "", value.Width(innerWidth).Render("Not connected. Probing starts once the trace listener runs."))
+	} else if !ok {
+		lines = append(lines, "", value.Width(innerWidth).Render(fmt.Sprintf("Waiting for the first probe. The connection is probed every %s while the trace listener runs.", health.ProbeInterval)))
+	} else {
+		lines = append(lines, label("Last probe")+value.Render(now.Sub(latest.At).Round(time.Second).String()+" ago"))
+		if latest.Err != "" {
+			lines = append(lines, label("Last error")+styles.OnboardingErrorStyle.Render(sanitizeLogString(latest.Err)))
+		}
+
+		sparkWidth := max(innerWidth-14-lipgloss.Width("  max 99999ms"), 8)
+		latency := make([]float64, len(health.Samples))
+		rate := make([]float64, len(health.Samples))
+		depth := make([]float64, len(health.Samples))
+		var maxLatency time.Duration
+		var maxRate float64
+		maxDepth := 0
+		for i, s := range health.Samples {
+			rate[i] = s.MessagesPerSecond
+			maxRate = max(maxRate, s.MessagesPerSecond)
+			if s.Err != "" {
+				latency[i], depth[i] = math.NaN(), math.NaN()
+				continue
+			}
+			latency[i] = float64(s.Latency)
+			maxLatency = max(maxLatency, s.Latency)
+			depth[i] = math.NaN()
+			if s.QueueDepth >= 0 {
+				depth[i] = float64(s.QueueDepth)
+				maxDepth = max(maxDepth, s.QueueDepth)
+			}
+		}
+
+		current := func(text string) string { return value.Width(14).Render(text) }
+		chart := func(values []float64, style lipgloss.Style, peak string) string {
+			return style.Render(sparkline(values, sparkWidth)) + styles.SubtitleStyle.Render("  max "+peak)
+		}
+		latencyNow, rateNow, depthNow := "—", formatRate(latest.MessagesPerSecond), "—"
+		if latest.Err == "" {
+			latencyNow = formatLatency(latest.Latency)
+			if latest.QueueDepth >= 0 {
+				depthNow = fmt.Sprint(latest.QueueDepth)
+			}
+		}
+		lines = append(lines,
+			"",
+			styles.SectionTitleStyle.Render("Latency"),
+			current(latencyNow)+chart(latency, lipgloss.NewStyle().Foreground(styles.AccentColor), formatLatency(maxLatency)),
+			"",
+			styles.SectionTitleStyle.Render("Throughput"),
+			current(rateNow)+chart(rate, lipgloss.NewStyle().Foreground(styles.SecondaryColor), formatRate(maxRate)),
+			"",
+			styles.SectionTitleStyle.Render("Queue Depth"),
+			current(depthNow)+chart(depth, lipgloss.NewStyle().Foreground(styles.WarningColor), fmt.Sprint(maxDepth)),
+			"",
+			styles.SubtitleStyle.Width(innerWidth).Render(fmt.Sprintf("%d samples, one every %s. Queue depth counts READY messages waiting for this subscriber; · marks a failed probe.", len(health.Samples), health.ProbeInterval)),
+		)
+	}
+
+	for i, line := range lines {
+		lines[i] = truncateRendered(line, innerWidth)
+	}
+	parts := []string{
+		strings.Join(lines, "\n"),
+		"",
+		styles.OnboardingHintStyle.Width(innerWidth).Render("Esc Close"),
+	}
+	return renderFramedPanel("Connection Health", panelWidth, panelTypeInfo, lipgloss.JoinVertical(lipgloss.Left, parts...))
+}
@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"OmniView/internal/core/domain"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestSparkline_ScalesToPeakAndMarksGaps(t *testing.T) {
+	t.Parallel()
+
+	if got := sparkline([]float64{0, 50, 100, math.NaN()}, 10); got != "▁▄█·" {
+		t.Fatalf("sparkline = %q", got)
+	}
+	if got := sparkline([]float64{5, 0, 0}, 2); got != "▁▁" {
+		t.Fatalf("expected only the last values at a flat scale, got %q", got)
+	}
+}
+
+func TestConnectionHealth_HeaderMetaAndOverlay(t *testing.T) {
+	t.Parallel()
+
+	m := newTestMainModel(t, 140, 40)
+	m.initViewport()
+	if m.mainConnectionMeta() != "" {
+		t.Fatal("expected no connection meta without a tracer service")
+	}
+
+	m.tracerService = mustNewTracerService(t, NewMockDatabaseRepository(), m.eventChannel)
+	m.Update(makeCharPress("i"))
+	if !m.health.visible {
+		t.Fatal("expected i to open the health overlay")
+	}
+	if overlay := ansi.Strip(m.viewConnectionHealthOverlay()); !strings.Contains(overlay, "Waiting for the first probe") {
+		t.Fatalf("expected the overlay to wait for a probe:\n%s", overlay)
+	}
+
+	now := time.Now()
+	m.health.snapshot = domain.ConnectionHealth{
+		ProbeInterval: 5 * time.Second,
+		Samples: []domain.HealthSample{
+			{At: now.Add(-10 * time.Second), Latency: 40 * time.Millisecond, QueueDepth: 3, MessagesPerSecond: 12},
+			{At: now.Add(-5 * time.Second), Err: "ORA-03113: end-of-file on communication channel", QueueDepth: -1},
+			{At: now, Latency: 12 * time.Millisecond, QueueDepth: 0, MessagesPerSecond: 2.5},
+		},
+	}
+	if meta := ansi.Strip(m.mainConnectionMeta()); meta != "● 12ms  •  2.5 msg/s  •  queue 0" {
+		t.Fatalf("header meta = %q", meta)
+	}
+
+	overlay := ansi.Strip(m.viewConnectionHealthOverlay())
+	for _, want := range []string{"HEALTHY", "Latency", "max 40ms", "Throughput", "max 12 msg/s", "Queue Depth", "·"} {
+		if !strings.Contains(overlay, want) {
+			t.Fatalf("overlay is missing %q:\n%s", want, overlay)
+		}
+	}
+	if _, cmd := m.Update(makeCharPress("q")); cmd != nil || m.health.visible {
+		t.Fatal("expected q to close the overlay without quitting")
+	}
+
+	// A probe stuck behind an unresponsive connection shows as stalled.
+	m.health.snapshot.Samples = m.health.snapshot.Samples[:1]
+	m.health.snapshot.Samples[0].At = now.Add(-time.Minute)
+	if meta := ansi.Strip(m.mainConnectionMeta()); !strings.HasPrefix(meta, "● STALLED  •  no probe for 1m") {
+		t.Fatalf("header meta = %q, want a stalled connection", meta)
+	}
+
+	// A dropped session shows the reconnect attempt instead of the stale probe.
+	m.health.snapshot.ReconnectAttempt = 3
+	m.health.snapshot.ReconnectErr = "ORA-03113: end-of-file on communication channel"
+	if meta := ansi.Strip(m.mainConnectionMeta()); meta != "● reconnecting (attempt 3)" {
+		t.Fatalf("header meta = %q, want the reconnect attempt", meta)
+	}
+	overlay = ansi.Strip(m.viewConnectionHealthOverlay())
+	for _, want := range []string{"RECONNECTING", "attempt 3", "ORA-03113"} {
+		if !strings.Contains(overlay, want) {
+			t.Fatalf("overlay is missing %q:\n%s", want, overlay)
+		}
+	}
+}
@@ -0,0 +1,374 @@
+package ui
+
+import (
+	"OmniView/internal/adapter/control"
+	"OmniView/internal/adapter/export"
+	"OmniView/internal/adapter/logger"
+	"OmniView/internal/core/domain"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// ==========================================
+// Control API
+// ==========================================
+// Calls arriving on the control socket are sent into the Bubble Tea program
+// as controlRequestMsg and handled in Update like any key press, so the
+// model is never touched from the socket goroutines.
+
+// ControlHandler implements control.Handler for a running program.
+type ControlHandler struct {
+	send func(tea.Msg)
+}
+
+// NewControlHandler returns a handler that drives program.
+func NewControlHandler(program *tea.Program) *ControlHandler {
+	return &ControlHandler{send: program.Send}
+}
+
+// HandleControl forwards the call to the UI and waits for its reply.
+func (h *ControlHandler) HandleControl(ctx context.Context, method string, params json.RawMessage) (any, error) {
+	reply := make(chan controlReply, 1)
+	// Send returns once the program has taken the message, or immediately after it exited
+	h.send(controlRequestMsg{method: method, params: params, reply: reply})
+	select {
+	case r := <-reply:
+		return r.result, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// ==========================================
+// Results
+// ==========================================
+
+type controlDatabase struct {
+	ID     string `json:"id"`
+	Target string `json:"target"`
+}
+
+type controlLevels struct {
+	Minimum string   `json:"minimum,omitempty"`
+	Hidden  []string `json:"hidden"`
+}
+
+type controlMessages struct {
+	Buffered int  `json:"buffered"`
+	Visible  int  `json:"visible"`
+	Paused   bool `json:"paused"`
+	Backlog  int  `json:"backlog"`
+}
+
+type controlConnection struct {
+	Status            string  `json:"status"`
+	LatencyMS         float64 `json:"latency_ms"`
+	QueueDepth        int     `json:"queue_depth"`
+	MessagesPerSecond float64 `json:"messages_per_second"`
+	ReconnectAttempt  int     `json:"reconnect_attempt,omitempty"`
+	Error             string  `json:"error,omitempty"`
+}
+
+type controlStatus struct {
+	Screen     string             `json:"screen"`
+	Database   *controlDatabase   `json:"database,omitempty"`
+	Subscriber *domain.Subscriber `json:"subscriber,omitempty"`
+	Mode       string             `json:"mode"`
+	Filter     string             `json:"filter"`
+	Levels     controlLevels      `json:"levels"`
+	Messages   controlMessages    `json:"messages"`
+	Connection controlConnection  `json:"connection"`
+}
+
+type controlExportResult struct {
+	Path   string `json:"path"`
+	Format string `json:"format"`
+	Count  int    `json:"count"`
+}
+
+// ==========================================
+// Dispatch
+// ==========================================
+
+// handleControlRequest runs one control call. Most methods reply before
+// returning; export replies from the command that writes the file.
+func (m *Model) handleControlRequest(req controlRequestMsg) tea.Cmd {
+	if req.method == "export" {
+		cmd, err := m.controlExport(req)
+		if err != nil {
+			req.reply <- controlReply{err: err}
+		}
+		return cmd
+	}
+
+	var (
+		result any
+		cmd    tea.Cmd
+		err    error
+	)
+	switch req.method {
+	case "status":
+		result = m.controlStatus()
+	case "switchDatabase":
+		result, cmd, err = m.controlSwitchDatabase(req.params)
+	case "setMode":
+		result, err = m.controlSetMode(req.params)
+	case "setFilter":
+		result, err = m.controlSetFilter(req.params)
+	case "setLevels":
+		result, err = m.controlSetLevels(req.params)
+	case "clear":
+		result, err = m.controlClear()
+	default:
+		err = control.MethodNotFound(req.method)
+	}
+	req.reply <- controlReply{result: result, err: err}
+	return cmd
+}
+
+// requireMainScreen rejects view changes until the trace view is up, since
+// entering it loads the saved mode and filter over anything set earlier.
+func (m *Model) requireMainScreen() error {
+	if m.screen != screenMain || !m.main.ready {
+		return control.Unavailable(fmt.Errorf("the trace view is not open (screen %q)", m.screen))
+	}
+	return nil
+}
+
+// ==========================================
+// Methods
+// ==========================================
+
+func (m *Model) controlStatus() controlStatus {
+	status := controlStatus{
+		Screen:     m.screen,
+		Subscriber: m.subscriber,
+		Mode:       m.broadcastMode.String(),
+		Filter:     m.traceFilter.active.String(),
+		Levels:     m.controlLevels(),
+		Messages: controlMessages{
+			Buffered: len(m.main.messages),
+			Visible:  len(m.filterMessages(m.main.messages)),
+			Paused:   m.pause.active,
+			Backlog:  len(m.pause.backlog),
+		},
+	}
+	if m.appConfig != nil {
+		status.Database = &controlDatabase{ID: m.appConfig.ID(), Target: m.appConfig.DisplayTarget()}
+	}
+
+	text, _ := m.connectionStatus(time.Now())
+	status.Connection = controlConnection{
+		Status:           text,
+		QueueDepth:       -1,
+		ReconnectAttempt: m.health.snapshot.ReconnectAttempt,
+		Error:            m.health.snapshot.ReconnectErr,
+	}
+	if latest, ok := m.health.snapshot.Latest(); ok {
+		status.Connection.LatencyMS = float64(latest.Latency) / float64(time.Millisecond)
+		status.Connection.QueueDepth = latest.QueueDepth
+		status.Connection.MessagesPerSecond = latest.MessagesPerSecond
+		if status.Connection.Error == "" {
+			status.Connection.Error = latest.Err
+		}
+	}
+	return status
+}
+
+func (m *Model) controlLevels() controlLevels {
+	levels := controlLevels{Hidden: []string{}}
+	for _, level := range domain.LogLevels() {
+		if m.levelFilter.minSeverity > 0 && level.Severity() == m.levelFilter.minSeverity {
+			levels.Minimum = level.String()
+		}
+		if m.levelFilter.hidden[level] {
+			levels.Hidden = append(levels.Hidden, level.String())
+		}
+	}
+	return levels
+}
+
+// controlSwitchDatabase makes the saved profile with the given id the active
+// database. It replies once the switch has started; the connection sequence
+// then runs as it does from the database settings panel, and status reports
+// when it reaches the trace view.
+func (m *Model) controlSwitchDatabase(params json.RawMessage) (any, tea.Cmd, error) {
+	var p struct {
+		ID string `json:"id"`
+	}
+	if err := control.DecodeParams(params, &p); err != nil {
+		return nil, nil, err
+	}
+	id := strings.TrimSpace(p.ID)
+	if id == "" {
+		return nil, nil, control.InvalidParams(errors.New(`"id" is required`))
+	}
+	if m.screen != screenMain && m.screen != screenLoading {
+		return nil, nil, control.Unavailable(fmt.Errorf("cannot switch databases from the %s screen", m.screen))
+	}
+	if m.appConfig != nil && m.appConfig.ID() == id && m.screen == screenMain {
+		return map[string]any{"id": id, "switched": false}, nil, nil
+	}
+
+	databases, err := m.dbSettingsRepo.GetAll(m.ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load database profiles: %w", err)
+	}
+	var known []string
+	for _, db := range databases {
+		if db.ID() == id {
+			cmd, err := m.switchDatabase(db)
+			if err != nil {
+				logger.Error("database switch over the control socket failed", "databaseID", id, "error", err)
+				return nil, nil, err
+			}
+			return map[string]any{"id": id, "switched": true}, cmd, nil
+		}
+		known = append(known, db.ID())
+	}
+	return nil, nil, control.InvalidParams(fmt.Errorf("no database profile %q (have %s)", id, strings.Join(known, ", ")))
+}
+
+func (m *Model) controlSetMode(params json.RawMessage) (any, error) {
+	var p struct {
+		Mode string `json:"mode"`
+	}
+	if err := control.DecodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	mode, err := domain.ParseBroadcastMode(p.Mode)
+	if err != nil {
+		return nil, control.InvalidParams(err)
+	}
+	if err := m.requireMainScreen(); err != nil {
+		return nil, err
+	}
+	if err := m.setBroadcastMode(mode); err != nil {
+		return nil, fmt.Errorf("failed to save broadcast mode: %w", err)
+	}
+	m.rebuildRenderedContent(m.main.viewport.Width())
+	return map[string]string{"mode": mode.String()}, nil
+}
+
+func (m *Model) controlSetFilter(params json.RawMessage) (any, error) {
+	var p struct {
+		Expression string `json:"expression"`
+	}
+	if err := control.DecodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	filter, err := domain.ParseTraceFilter(p.Expression)
+	if err != nil {
+		return nil, control.InvalidParams(err)
+	}
+	if err := m.requireMainScreen(); err != nil {
+		return nil, err
+	}
+	m.applyTraceFilter(filter)
+	return map[string]string{"filter": filter.String()}, nil
+}
+
+// controlSetLevels replaces the level visibility settings: an empty minimum
+// removes the threshold and hidden lists every level switched off.
+func (m *Model) controlSetLevels(params json.RawMessage) (any, error) {
+	var p struct {
+		Minimum string   `json:"minimum"`
+		Hidden  []string `json:"hidden"`
+	}
+	if err := control.DecodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	minSeverity := 0
+	if strings.TrimSpace(p.Minimum) != "" {
+		level, err := domain.NewLogLevel(p.Minimum)
+		if err != nil {
+			return nil, control.InvalidParams(err)
+		}
+		minSeverity = level.Severity()
+	}
+	var hidden map[domain.LogLevel]bool
+	for _, name := range p.Hidden {
+		level, err := domain.NewLogLevel(name)
+		if err != nil {
+			return nil, control.InvalidParams(err)
+		}
+		if hidden == nil {
+			hidden = make(map[domain.LogLevel]bool)
+		}
+		hidden[level] = true
+	}
+	if err := m.requireMainScreen(); err != nil {
+		return nil, err
+	}
+
+	m.levelFilter.minSeverity = minSeverity
+	m.levelFilter.hidden = hidden
+	m.applyLevelFilter()
+	return m.controlLevels(), nil
+}
+
+func (m *Model) controlClear() (any, error) {
+	if err := m.requireMainScreen(); err != nil {
+		return nil, err
+	}
+	cleared := len(m.main.messages)
+	m.resetMainLogState()
+	m.syncViewportContent()
+	m.main.viewport.GotoTop()
+	return map[string]int{"cleared": cleared}, nil
+}
+
+// controlExport writes the visible messages to an absolute path, like the
+// export dialog. The format follows the file extension; format picks one when
+// the path has none and must agree with it otherwise.
+func (m *Model) controlExport(req controlRequestMsg) (tea.Cmd, error) {
+	var p struct {
+		Path   string `json:"path"`
+		Format string `json:"format"`
+	}
+	if err := control.DecodeParams(req.params, &p); err != nil {
+		return nil, err
+	}
+	selected := export.FormatNDJSON
+	if p.Format != "" {
+		format, err := export.ParseFormat(p.Format)
+		if err != nil {
+			return nil, control.InvalidParams(err)
+		}
+		selected = format
+	}
+	path, format, err := resolveExportTarget(p.Path, selected)
+	if err != nil {
+		return nil, control.InvalidParams(err)
+	}
+	if p.Format != "" && format != selected {
+		return nil, control.InvalidParams(fmt.Errorf("format %s does not match the %s extension of %s", selected, filepath.Ext(path), path))
+	}
+	if !filepath.IsAbs(path) {
+		return nil, control.InvalidParams(fmt.Errorf("path must be absolute, got %s", path))
+	}
+	if err := m.requireMainScreen(); err != nil {
+		return nil, err
+	}
+
+	msgs := m.exportSnapshot()
+	if len(msgs) == 0 {
+		return nil, control.Unavailable(errors.New("nothing to export: no messages match the current view"))
+	}
+	report := m.exportReport()
+	return func() tea.Msg {
+		if err := export.WriteFile(path, format, msgs, report); err != nil {
+			req.reply <- controlReply{err: err}
+			return nil
+		}
+		req.reply <- controlReply{result: controlExportResult{Path: path, Format: format.String(), Count: len(msgs)}}
+		return nil
+	}, nil
+}
@@ -0,0 +1,208 @@
+package ui
+
+import (
+	"OmniView/internal/adapter/control"
+	"OmniView/internal/adapter/storage/boltdb"
+	"OmniView/internal/core/domain"
+	"OmniView/internal/core/ports"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// callControl sends one control call through Update and returns its reply,
+// running the returned command first when the method replies from it.
+func callControl(t *testing.T, m *Model, method, params string) (any, error) {
+	t.Helper()
+
+	req := controlRequestMsg{method: method, reply: make(chan controlReply, 1)}
+	if params != "" {
+		req.params = json.RawMessage(params)
+	}
+	_, cmd := m.Update(req)
+	select {
+	case r := <-req.reply:
+		return r.result, r.err
+	default:
+	}
+	if cmd == nil {
+		t.Fatalf("%s: no reply and no command", method)
+	}
+	cmd()
+	r := <-req.reply
+	return r.result, r.err
+}
+
+// controlCode returns the JSON-RPC error code err maps to.
+func controlCode(err error) int {
+	var rpcErr *control.Error
+	if errors.As(err, &rpcErr) {
+		return rpcErr.Code
+	}
+	return 0
+}
+
+func TestControl_ViewMethodsChangeTheTraceView(t *testing.T) {
+	t.Parallel()
+
+	m := newTestLevelModel(t)
+
+	if _, err := callControl(t, m, "setLevels", `{"minimum":"WARNING","hidden":["ERROR"]}`); err != nil {
+		t.Fatalf("setLevels: %v", err)
+	}
+	if got := len(m.main.renderedLines); got != 8 {
+		t.Fatalf("expected WARNING and CRITICAL lines only, got %d", got)
+	}
+
+	if _, err := callControl(t, m, "setFilter", `{"expression":"level = CRITICAL"}`); err != nil {
+		t.Fatalf("setFilter: %v", err)
+	}
+	if m.traceFilter.active == nil || len(m.main.renderedLines) != 5 {
+		t.Fatalf("expected the filter to leave the CRITICAL lines, got %d", len(m.main.renderedLines))
+	}
+
+	if _, err := callControl(t, m, "setMode", `{"mode":"broadcast"}`); err != nil {
+		t.Fatalf("setMode: %v", err)
+	}
+	if m.broadcastMode != domain.BroadcastModeBroadcast {
+		t.Fatalf("broadcast mode = %s", m.broadcastMode)
+	}
+
+	result, err := callControl(t, m, "status", "")
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	encoded, _ := json.Marshal(result)
+	for _, want := range []string{`"screen":"main"`, `"mode":"Only Broadcast"`, `"minimum":"WARNING"`, `"hidden":["ERROR"]`, `"buffered":15`} {
+		if !strings.Contains(string(encoded), want) {
+			t.Errorf("status %s does not contain %s", encoded, want)
+		}
+	}
+
+	result, err = callControl(t, m, "clear", "")
+	if err != nil || result.(map[string]int)["cleared"] != 15 || len(m.main.messages) != 0 {
+		t.Fatalf("clear = %v, %v with %d messages left", result, err, len(m.main.messages))
+	}
+}
+
+func TestControl_RejectsBadCalls(t *testing.T) {
+	t.Parallel()
+
+	m := newTestLevelModel(t)
+	cases := []struct {
+		method string
+		params string
+		code   int
+	}{
+		{"reboot", "", control.CodeMethodNotFound},
+		{"setMode", `{"mode":"everyone"}`, control.CodeInvalidParams},
+		{"setFilter", `{"expression":"level ="}`, control.CodeInvalidParams},
+		{"setLevels", `{"minimum":"LOUD"}`, control.CodeInvalidParams},
+		{"setLevels", `{"min":"INFO"}`, control.CodeInvalidParams},
+		{"switchDatabase", `{}`, control.CodeInvalidParams},
+		{"export", `{"path":"relative.csv"}`, control.CodeInvalidParams},
+		{"export", `{"path":"/tmp/trace.csv","format":"html"}`, control.CodeInvalidParams},
+	}
+	for _, tc := range cases {
+		if _, err := callControl(t, m, tc.method, tc.params); controlCode(err) != tc.code {
+			t.Errorf("%s %s: error %v, want code %d", tc.method, tc.params, err, tc.code)
+		}
+	}
+
+	m.screen = screenWelcome
+	if _, err := callControl(t, m, "clear", ""); controlCode(err) != control.CodeUnavailable {
+		t.Fatalf("expected clear to be unavailable before the trace view opens, got %v", err)
+	}
+	if _, err := callControl(t, m, "status", ""); err != nil {
+		t.Fatalf("expected status on every screen, got %v", err)
+	}
+}
+
+func TestControl_ExportWritesTheVisibleMessages(t *testing.T) {
+	t.Parallel()
+
+	m := newTestLevelModel(t)
+	m.levelFilter.minSeverity = domain.LogLevelError.Severity()
+	path := filepath.Join(t.TempDir(), "trace")
+
+	result, err := callControl(t, m, "export", `{"path":"`+path+`","format":"csv"}`)
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	got := result.(controlExportResult)
+	if got.Path != path+".csv" || got.Format != "csv" || got.Count != 9 {
+		t.Fatalf("export result %+v", got)
+	}
+	data, err := os.ReadFile(got.Path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if lines := strings.Count(strings.TrimSpace(string(data)), "\n"); lines != 9 {
+		t.Fatalf("expected a header and 9 rows, got %d newlines", lines)
+	}
+}
+
+func TestControl_SwitchDatabaseByProfileID(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModelForSettings(t)
+	m.boltAdapter = newTestBoltAdapter(t)
+	m.dbSettingsRepo = boltdb.NewDatabaseSettingsRepository(m.boltAdapter)
+	m.dbAdapter = NewMockDatabaseRepository()
+	m.appConfig = newTestDatabaseSettings(t, "OLD-DB")
+	for _, id := range []string{"OLD-DB", "NEW-DB"} {
+		if err := m.dbSettingsRepo.Save(context.Background(), *newTestDatabaseSettings(t, id)); err != nil {
+			t.Fatalf("Save(%s): %v", id, err)
+		}
+	}
+	m.dbFactory = func(*domain.DatabaseSettings) (ports.DatabaseRepository, error) {
+		return NewMockDatabaseRepository(), nil
+	}
+
+	result, err := callControl(t, m, "switchDatabase", `{"id":"OLD-DB"}`)
+	if err != nil || result.(map[string]any)["switched"] != false {
+		t.Fatalf("expected switching to the active database to be a no-op, got %v, %v", result, err)
+	}
+	if _, err := callControl(t, m, "switchDatabase", `{"id":"MISSING"}`); controlCode(err) != control.CodeInvalidParams || !strings.Contains(err.Error(), "NEW-DB") {
+		t.Fatalf("expected an unknown profile to list the known ones, got %v", err)
+	}
+
+	req := controlRequestMsg{method: "switchDatabase", params: json.RawMessage(`{"id":"NEW-DB"}`), reply: make(chan controlReply, 1)}
+	_, cmd := m.Update(req)
+	if r := <-req.reply; r.err != nil || r.result.(map[string]any)["switched"] != true {
+		t.Fatalf("switchDatabase = %v, %v", r.result, r.err)
+	}
+	if cmd == nil || m.screen != screenLoading || m.appConfig.ID() != "NEW-DB" {
+		t.Fatalf("expected the connection sequence to start against NEW-DB (screen %q)", m.screen)
+	}
+
+	m.screen = screenOnboarding
+	if _, err := callControl(t, m, "switchDatabase", `{"id":"OLD-DB"}`); controlCode(err) != control.CodeUnavailable {
+		t.Fatalf("expected switching during onboarding to be unavailable, got %v", err)
+	}
+}
+
+func TestControlHandler_TimesOutWhenTheUIDoesNotAnswer(t *testing.T) {
+	t.Parallel()
+
+	h := &ControlHandler{send: func(msg tea.Msg) {}}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := h.HandleControl(ctx, "status", nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the context error, got %v", err)
+	}
+
+	h = &ControlHandler{send: func(msg tea.Msg) {
+		req := msg.(controlRequestMsg)
+		req.reply <- controlReply{result: req.method}
+	}}
+	if result, err := h.HandleControl(context.Background(), "status", nil); err != nil || result != "status" {
+		t.Fatalf("HandleControl = %v, %v", result, err)
+	}
+}
@@ -641,19 +641,30 @@ func (m *Model) persistDefaultDatabaseSelection(previousDefault *domain.Database
 
 // handleSettingsSetAsMain: updates the active database configuration and reinitializes dependent services.
 func (m *Model) handleSettingsSetAsMain(selectedDb domain.DatabaseSettings) (*Model, tea.Cmd) {
+	cmd, err := m.switchDatabase(selectedDb)
+	if err != nil {
+		return m.showDatabaseSwitchError(err)
+	}
+	return m, cmd
+}
+
+// switchDatabase probes selectedDb, makes it the default and restarts the
+// connection sequence against it. Errors are returned before the active
+// connection is touched, so the caller decides how to report them.
+func (m *Model) switchDatabase(selectedDb domain.DatabaseSettings) (tea.Cmd, error) {
 	newAdapter, err := m.dbFactory(&selectedDb)
 	if err != nil {
-		return m.showDatabaseSwitchError(fmt.Errorf("failed to initialize database %q: %w", selectedDb.DatabaseID(), err))
+		return nil, fmt.Errorf("failed to initialize database %q: %w", selectedDb.DatabaseID(), err)
 	}
 	if newAdapter == nil {
-		return m.showDatabaseSwitchError(fmt.Errorf("failed to initialize database %q: adapter is nil", selectedDb.DatabaseID()))
+		return nil, fmt.Errorf("failed to initialize database %q: adapter is nil", selectedDb.DatabaseID())
 	}
 
 	if err := newAdapter.Connect(m.ctx); err != nil {
 		if closeErr := newAdapter.Close(m.ctx); closeErr != nil {
 			logger.Warn("failed to close database adapter after failed connectivity probe", "databaseID", selectedDb.DatabaseID(), "error", closeErr)
 		}
-		return m.showDatabaseSwitchError(fmt.Errorf("failed to connect to database %q: %w", selectedDb.DatabaseID(), err))
+		return nil, fmt.Errorf("failed to connect to database %q: %w", selectedDb.DatabaseID(), err)
 	}
 	if err := newAdapter.Close(m.ctx); err != nil {
 		logger.Warn("failed to close validated database adapter", "databaseID", selectedDb.DatabaseID(), "error", err)
@@ -662,10 +673,10 @@ func (m *Model) handleSettingsSetAsMain(selectedDb domain.DatabaseSettings) (*Mo
 	previousConfig := m.appConfig
 	updatedSelected, err := databaseSettingsWithDefaultState(selectedDb, true)
 	if err != nil {
-		return m.showDatabaseSwitchError(fmt.Errorf("failed to prepare database %q as default: %w", selectedDb.DatabaseID(), err))
+		return nil, fmt.Errorf("failed to prepare database %q as default: %w", selectedDb.DatabaseID(), err)
 	}
 	if err := m.persistDefaultDatabaseSelection(previousConfig, updatedSelected); err != nil {
-		return m.showDatabaseSwitchError(fmt.Errorf("failed to persist database %q as default: %w", selectedDb.DatabaseID(), err))
+		return nil, fmt.Errorf("failed to persist database %q as default: %w", selectedDb.DatabaseID(), err)
 	}
 
 	m.resetConnectionEventStream()
@@ -701,7 +712,7 @@ func (m *Model) handleSettingsSetAsMain(selectedDb domain.DatabaseSettings) (*Mo
 	m.loading.complete = false
 	m.loading.retryCount = 0
 	m.loading.current = "Connecting..."
-	return m, connectDBCmd(m, true)
+	return connectDBCmd(m, true), nil
 }
 
 // reloadDatabaseList: reloads the database list from BoltDB storage and updates the UI list.
@@ -0,0 +1,359 @@
+package ui
+
+import (
+	"OmniView/internal/adapter/export"
+	"OmniView/internal/adapter/ui/styles"
+	"OmniView/internal/core/domain"
+	"fmt"
+	"image/color"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+)
+
+// ==========================================
+// Export Dialog Sub-State
+// ==========================================
+
+const (
+	exportFieldPath = iota
+	exportFieldFormat
+	exportBtnSave
+	exportBtnCancel
+	exportMaxCursor = exportBtnCancel
+)
+
+type exportDialogState struct {
+	visible   bool
+	cursor    int
+	input     string
+	format    export.Format
+	exporting bool
+	dialog    settingsDialog
+}
+
+// ==========================================
+// Helpers
+// ==========================================
+
+// openExportDialog shows the export panel with a timestamped default path in
+// the current working directory.
+func (m *Model) openExportDialog() {
+	format := export.FormatNDJSON
+	name := export.DefaultFileName(format, time.Now())
+	if cwd, err := os.Getwd(); err == nil {
+		name = filepath.Join(cwd, name)
+	}
+
+	m.exportDialog = exportDialogState{
+		visible: true,
+		input:   name,
+		format:  format,
+	}
+}
+
+// closeExportDialog closes the export overlay and resets the sub-state.
+func (m *Model) closeExportDialog() {
+	m.exportDialog = exportDialogState{}
+}
+
+// cycleExportFormat advances the selected format and keeps a recognised file
+// extension in the path in sync with it.
+func (m *Model) cycleExportFormat() {
+	m.exportDialog.format = m.exportDialog.format.Next()
+	path := m.exportDialog.input
+	if _, err := export.FormatForPath(path); err == nil {
+		m.exportDialog.input = strings.TrimSuffix(path, filepath.Ext(path)) + m.exportDialog.format.Extension()
+	}
+}
+
+// resolveExportTarget expands a leading "~" and derives the format from the
+// file extension, appending the selected format's extension when none is given.
+func resolveExportTarget(input string, selected export.Format) (string, export.Format, error) {
+	path := strings.TrimSpace(input)
+	if path == "" {
+		return "", selected, fmt.Errorf("enter a file path")
+	}
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", selected, fmt.Errorf("resolve home directory: %w", err)
+		}
+		path = filepath.Join(home, strings.TrimPrefix(path, "~"))
+	}
+	if filepath.Ext(path) == "" {
+		return path + selected.Extension(), selected, nil
+	}
+	format, err := export.FormatForPath(path)
+	if err != nil {
+		return "", selected, err
+	}
+	return path, format, nil
+}
+
+// exportSnapshot returns a copy of the messages currently visible on the main
+// screen, with the same filters the viewport applies.
+func (m *Model) exportSnapshot() []*domain.QueueMessage {
+	return slices.Clone(m.filterMessages(m.main.messages))
+}
+
+// exportReport describes the current view for the header of an export.
+func (m *Model) exportReport() export.Report {
+	report := export.Report{
+		Mode:        m.broadcastMode,
+		Filter:      m.traceFilter.active.String(),
+		GeneratedAt: time.Now(),
+		Palette:     exportPalette(),
+	}
+	if m.appConfig != nil {
+		report.Database = m.appConfig.ID()
+	}
+	return report
+}
+
+// exportPalette maps the terminal theme onto the HTML report colours.
+func exportPalette() export.Palette {
+	return export.Palette{
+		Background: colorHex(styles.BackgroundColor),
+		Surface:    colorHex(styles.SurfaceBackgroundColor),
+		Text:       colorHex(styles.TextColor),
+		Muted:      colorHex(styles.MutedColor),
+		Levels: map[domain.LogLevel]string{
+			domain.LogLevelDebug:    colorHex(styles.DebugColor),
+			domain.LogLevelInfo:     colorHex(styles.InfoColor),
+			domain.LogLevelWarning:  colorHex(styles.WarningColor),
+			domain.LogLevelError:    colorHex(styles.ErrorColor),
+			domain.LogLevelCritical: colorHex(styles.CriticalColor),
+		},
+	}
+}
+
+// colorHex formats c as a CSS #RRGGBB value.
+func colorHex(c color.Color) string {
+	if c == nil {
+		return ""
+	}
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("#%02X%02X%02X", r>>8, g>>8, b>>8)
+}
+
+// ==========================================
+// Update
+// ==========================================
+
+// updateExportDialog handles keyboard and paste input for the export panel.
+func (m *Model) updateExportDialog(msg tea.Msg) (*Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.PasteMsg:
+		if m.exportDialog.cursor == exportFieldPath {
+			m.exportDialog.input += sanitizePasteInput(msg.Content)
+			m.exportDialog.dialog.clear()
+		}
+		return m, nil
+
+	case tea.KeyPressMsg:
+		key := msg.String()
+		switch key {
+		case "ctrl+c":
+			m.cancel()
+			return m, tea.Quit
+		case "esc":
+			if m.exportDialog.dialog.visible {
+				m.exportDialog.dialog.clear()
+				return m, nil
+			}
+			m.closeExportDialog()
+			return m, nil
+		case "up", "shift+tab":
+			if m.exportDialog.cursor > 0 {
+				m.exportDialog.cursor--
+			}
+			m.exportDialog.dialog.clear()
+			return m, nil
+		case "down":
+			if m.exportDialog.cursor < exportMaxCursor {
+				m.exportDialog.cursor++
+			}
+			m.exportDialog.dialog.clear()
+			return m, nil
+		case "tab":
+			m.exportDialog.cursor = (m.exportDialog.cursor + 1) % (exportMaxCursor + 1)
+			m.exportDialog.dialog.clear()
+			return m, nil
+		case "enter":
+			switch m.exportDialog.cursor {
+			case exportBtnCancel:
+				m.closeExportDialog()
+				return m, nil
+			case exportFieldFormat:
+				m.cycleExportFormat()
+				m.exportDialog.dialog.clear()
+				return m, nil
+			default:
+				return m, m.exportMessagesCmd()
+			}
+		}
+
+		if m.exportDialog.cursor == exportFieldFormat {
+			switch key {
+			case "left", "right", "space":
+				m.cycleExportFormat()
+				m.exportDialog.dialog.clear()
+			}
+			return m, nil
+		}
+
+		if m.exportDialog.cursor != exportFieldPath {
+			return m, nil
+		}
+		switch key {
+		case "backspace":
+			value := m.exportDialog.input
+			if len(value) > 0 {
+				_, size := utf8.DecodeLastRuneInString(value)
+				m.exportDialog.input = value[:len(value)-size]
+			}
+			m.exportDialog.dialog.clear()
+			return m, nil
+		case "ctrl+u":
+			m.exportDialog.input = ""
+			m.exportDialog.dialog.clear()
+			return m, nil
+		}
+		if len(msg.Text) > 0 && !msg.Mod.Contains(tea.ModCtrl) {
+			m.exportDialog.input += msg.Text
+			m.exportDialog.dialog.clear()
+		}
+		return m, nil
+
+	case exportCompletedMsg:
+		m.exportDialog.exporting = false
+		if msg.err != nil {
+			m.exportDialog.dialog.set(msg.err.Error(), true)
+			return m, nil
+		}
+		m.exportDialog.input = msg.path
+		m.exportDialog.format = msg.format
+		m.exportDialog.dialog.set(fmt.Sprintf("Exported %d messages to %s", msg.count, msg.path), false)
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// ==========================================
+// View
+// ==========================================
+
+// viewExportDialog renders the export panel as a string.
+func (m *Model) viewExportDialog() string {
+	panelWidth := settingsPanelWidth(m.width)
+	innerWidth := max(panelWidth-4, 1)
+	_, contentHeight := screenContentSize(m.width, m.height)
+	compact := contentHeight <= 16
+
+	pathValue := formValueStyle.Render(m.exportDialog.input)
+	if strings.TrimSpace(m.exportDialog.input) == "" {
+		pathValue = formPlaceholder.Render("./omniview-trace.ndjson")
+	}
+	if m.exportDialog.cursor == exportFieldPath {
+		pathValue += formCursorStyle.Render("_")
+	}
+
+	formatNames := make([]string, 0, 3)
+	for _, format := range []export.Format{export.FormatNDJSON, export.FormatCSV, export.FormatHTML} {
+		name := strings.ToUpper(format.String())
+		if format == m.exportDialog.format {
+			formatNames = append(formatNames, formCursorStyle.Render("["+name+"]"))
+		} else {
+			formatNames = append(formatNames, formPlaceholder.Render(" "+name+" "))
+		}
+	}
+
+	visible := len(m.filterMessages(m.main.messages))
+	summary := fmt.Sprintf("%d of %d buffered messages match the current view [%s].", visible, len(m.main.messages), m.broadcastMode)
+
+	parts := make([]string, 0, 10)
+	appendSpacer := func() {
+		if !compact {
+			parts = append(parts, "")
+		}
+	}
+
+	parts = append(parts, styles.SubtitleStyle.Width(innerWidth).Render(summary))
+	appendSpacer()
+	parts = append(parts, renderEmbeddedField(embeddedFieldOptions{
+		Label:      "File Path",
+		Value:      pathValue,
+		Width:      innerWidth,
+		Focused:    m.exportDialog.cursor == exportFieldPath,
+		FooterText: "Existing files are replaced atomically.",
+	}))
+	appendSpacer()
+	parts = append(parts, renderEmbeddedField(embeddedFieldOptions{
+		Label:   "Format",
+		Value:   strings.Join(formatNames, " "),
+		Width:   innerWidth,
+		Focused: m.exportDialog.cursor == exportFieldFormat,
+	}))
+	appendSpacer()
+	parts = append(parts, renderCenteredActionButtons(
+		innerWidth,
+		"Export",
+		m.exportDialog.cursor == exportBtnSave,
+		"Cancel",
+		m.exportDialog.cursor == exportBtnCancel,
+	))
+
+	parts = append(parts, renderSettingsDialogLines(m.exportDialog.dialog, innerWidth)...)
+
+	if m.exportDialog.exporting {
+		parts = append(parts, "", styles.SubtitleStyle.Render("Exporting..."))
+	} else if !m.exportDialog.dialog.visible && !compact {
+		parts = append(parts, "", styles.OnboardingHintStyle.Width(innerWidth).Render("↑/↓ Navigate  •  ←/→ Format  •  Enter Confirm  •  Ctrl+U Clear  •  Esc Back"))
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left, parts...)
+	return renderFramedPanel("Export Trace", panelWidth, panelTypeInfo, content)
+}
+
+// ==========================================
+// Async Commands
+// ==========================================
+
+// exportMessagesCmd snapshots the visible messages and writes them to the
+// chosen path off the UI goroutine.
+func (m *Model) exportMessagesCmd() tea.Cmd {
+	if m.exportDialog.exporting {
+		return nil
+	}
+
+	path, format, err := resolveExportTarget(m.exportDialog.input, m.exportDialog.format)
+	if err != nil {
+		m.exportDialog.dialog.set(err.Error(), true)
+		return nil
+	}
+
+	msgs := m.exportSnapshot()
+	if len(msgs) == 0 {
+		m.exportDialog.dialog.set("nothing to export: no messages match the current view", true)
+		return nil
+	}
+
+	report := m.exportReport()
+	m.exportDialog.exporting = true
+	m.exportDialog.dialog.clear()
+
+	return func() tea.Msg {
+		if err := export.WriteFile(path, format, msgs, report); err != nil {
+			return exportCompletedMsg{path: path, format: format, err: err}
+		}
+		return exportCompletedMsg{path: path, format: format, count: len(msgs)}
+	}
+}
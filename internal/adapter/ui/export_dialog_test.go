@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"OmniView/internal/adapter/export"
+	"OmniView/internal/core/domain"
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+func TestExportDialog_ExportsOnlyMessagesMatchingBroadcastMode(t *testing.T) {
+	t.Parallel()
+
+	m := newTestMainModel(t, 140, 40)
+	m.initViewport()
+	m.broadcastMode = domain.BroadcastModeBroadcast
+	m.main.messages = []*domain.QueueMessage{
+		mustNewTestQueueMessageWithMode(t, "global-1", "Global"),
+		mustNewTestQueueMessageWithMode(t, "mine-1", "Only Subscriber"),
+		mustNewTestQueueMessageWithMode(t, "global-2", "Global"),
+	}
+
+	m.Update(makeCharPress("e"))
+	if !m.exportDialog.visible {
+		t.Fatal("expected E to open the export dialog")
+	}
+
+	path := filepath.Join(t.TempDir(), "trace")
+	m.exportDialog.input = path
+	_, cmd := m.Update(makeKeyPress(tea.KeyEnter))
+	if cmd == nil {
+		t.Fatal("expected Enter on the path field to start the export")
+	}
+	m.Update(cmd())
+
+	if m.exportDialog.dialog.isError {
+		t.Fatalf("export failed: %s", m.exportDialog.dialog.msg)
+	}
+	if want := path + ".ndjson"; m.exportDialog.input != want {
+		t.Fatalf("expected the default extension to be appended, got %q", m.exportDialog.input)
+	}
+
+	f, err := os.Open(path + ".ndjson")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer f.Close()
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if len(lines) != 2 || !strings.Contains(lines[0], `"global-1"`) || !strings.Contains(lines[1], `"global-2"`) {
+		t.Fatalf("expected only broadcast messages in export, got %v", lines)
+	}
+}
+
+func TestExportDialog_FormatCycleRewritesExtension(t *testing.T) {
+	t.Parallel()
+
+	m := newTestMainModel(t, 140, 40)
+	m.initViewport()
+	m.openExportDialog()
+	m.exportDialog.input = "/tmp/trace.ndjson"
+	m.exportDialog.cursor = exportFieldFormat
+
+	m.Update(makeKeyPress(tea.KeyRight))
+	if m.exportDialog.format != export.FormatCSV || m.exportDialog.input != "/tmp/trace.csv" {
+		t.Fatalf("after one cycle: format=%s input=%q", m.exportDialog.format, m.exportDialog.input)
+	}
+
+	m.Update(makeKeyPress(tea.KeyRight))
+	if m.exportDialog.format != export.FormatHTML || m.exportDialog.input != "/tmp/trace.html" {
+		t.Fatalf("after two cycles: format=%s input=%q", m.exportDialog.format, m.exportDialog.input)
+	}
+}
+
+func TestExportDialog_RejectsEmptyViewAndQDoesNotQuit(t *testing.T) {
+	t.Parallel()
+
+	m := newTestMainModel(t, 140, 40)
+	m.initViewport()
+	m.openExportDialog()
+
+	if _, cmd := m.Update(makeCharPress("q")); cmd != nil {
+		t.Fatal("expected q to be typed into the path field, not quit")
+	}
+	if !strings.HasSuffix(m.exportDialog.input, "q") {
+		t.Fatalf("expected q to be appended to the path, got %q", m.exportDialog.input)
+	}
+
+	m.exportDialog.input = filepath.Join(t.TempDir(), "empty.csv")
+	if _, cmd := m.Update(makeKeyPress(tea.KeyEnter)); cmd != nil {
+		t.Fatal("expected no export command with an empty buffer")
+	}
+	if !m.exportDialog.dialog.visible || !m.exportDialog.dialog.isError {
+		t.Fatal("expected an inline error for an empty export")
+	}
+
+	m.Update(makeKeyPress(tea.KeyEscape))
+	m.Update(makeKeyPress(tea.KeyEscape))
+	if m.exportDialog.visible {
+		t.Fatal("expected Esc to dismiss the message and then close the dialog")
+	}
+}
@@ -93,13 +93,58 @@ func (m *Model) renderHelpOverlay() string {
 		styles.SubtitleStyle.Render("N = New  •  E = Edit  •  Enter = Switch to selected"),
 		"",
 		styles.SectionTitleStyle.Render("4. Webhook Configuration  [S]"),
-		styles.BodyTextStyle.Render("Open Settings → Webhook section."),
-		styles.SubtitleStyle.Render("Configure endpoint URL and toggle delivery on/off."),
+		styles.BodyTextStyle.Render("Open Settings → Webhooks list."),
+		styles.SubtitleStyle.Render("Enter = Edit  •  A = Security  •  B = Batching  •  L = Limits  •  P = Preview  •  Space = Toggle  •  * = Default  •  D = Delete"),
+		styles.SubtitleStyle.Render("Payload Format = Slack, Teams, Discord or a Go template  •  Ctrl+P in the form = Preview"),
+		styles.SubtitleStyle.Render("Outputs & Routing… = Route messages to webhooks, files, syslog or OTLP collectors by level, process, mode, payload and webhook flag"),
+		styles.SubtitleStyle.Render("Dead Letters… = Replay or discard webhook messages that could not be delivered"),
 		"",
 		styles.SectionTitleStyle.Render("5. Message Filtering  [B]"),
 		styles.BodyTextStyle.Render("Cycle: Global → Subscriber Only → Broadcast Only → Global"),
 		styles.SubtitleStyle.Render("Global: all messages  •  Subscriber: yours only  •  Broadcast: broadcast only"),
 		"",
+		styles.SectionTitleStyle.Render("6. Trace History  [T]"),
+		styles.BodyTextStyle.Render("Browse past sessions recorded for the active database."),
+		styles.SubtitleStyle.Render("Enter = Open  •  ←/→ = Page  •  R / Z = Cycle retention age / size"),
+		"",
+		styles.SectionTitleStyle.Render("7. Export  [E]"),
+		styles.BodyTextStyle.Render("Save the messages currently in view to a file."),
+		styles.SubtitleStyle.Render("NDJSON, CSV or a standalone HTML report  •  respects the active filter"),
+		"",
+		styles.SectionTitleStyle.Render("8. Search  [/]"),
+		styles.BodyTextStyle.Render("Find text in payloads and process names; matches are highlighted."),
+		styles.SubtitleStyle.Render("Tab = Case  •  Ctrl+R = Regex  •  Enter = Jump  •  n / N = Next / Previous  •  Esc = Clear"),
+		"",
+		styles.SectionTitleStyle.Render("9. Filter  [F]"),
+		styles.BodyTextStyle.Render("Show only matching traces; saved per database."),
+		styles.SubtitleStyle.Render(`e.g. level>=WARNING and process~"ORDER_" and payload contains "timeout"`),
+		styles.SubtitleStyle.Render("Fields: level, process, payload, mode, id, attr.<name>  •  and / or / not  •  Enter = Apply  •  Esc = Cancel"),
+		"",
+		styles.SectionTitleStyle.Render("10. Log Levels  [L]"),
+		styles.BodyTextStyle.Render("Hide noisy levels without losing them; hidden counts appear in the status bar."),
+		styles.SubtitleStyle.Render("1-5 = Toggle DEBUG … CRITICAL  •  M = Minimum level (in overlay)  •  R = Show all"),
+		"",
+		styles.SectionTitleStyle.Render("11. Pause  [P]"),
+		styles.BodyTextStyle.Render("Freeze the feed to read a trace; new messages queue up and are merged on resume."),
+		"",
+		styles.SectionTitleStyle.Render("12. Message Details  [Enter, J/K]"),
+		styles.BodyTextStyle.Render("Press Enter to show the row cursor, move it with J / K and press Enter again to inspect the full message. Esc hides the cursor; without it J / K scroll the log."),
+		styles.SubtitleStyle.Render("C = Copy payload  •  Y = Copy JSON  •  F = Filter to process  •  P = Pin  •  W = Forward to webhook"),
+		"",
+		styles.SectionTitleStyle.Render("13. Structured Payloads  [X]"),
+		styles.BodyTextStyle.Render("JSON, XML and SQL payloads are indented and colourised in the detail pane; X does the same in the feed."),
+		styles.SubtitleStyle.Render("In the detail pane: Space = Fold JSON node  •  - = Fold all  •  + = Unfold all"),
+		"",
+		styles.SectionTitleStyle.Render("14. Attribute Columns  [O]"),
+		styles.BodyTextStyle.Render("Show attributes from Trace_Message_With_Props as columns; filter on them with attr.<name>."),
+		"",
+		styles.SectionTitleStyle.Render("15. Span Timeline  [V]"),
+		styles.BodyTextStyle.Render("Spans opened with Trace_Begin and closed with Trace_End show ▶ / ◀ markers and their duration; V draws them as a waterfall per process."),
+		"",
+		styles.SectionTitleStyle.Render("16. Connection Health  [I]"),
+		styles.BodyTextStyle.Render("The header shows probe latency, messages per second and queue depth; I charts the last five minutes."),
+		styles.BodyTextStyle.Render("If the session drops, OmniView reconnects with backoff and shows the attempt in the header."),
+		"",
 		centerLineStyle.Render(styles.SubtitleStyle.Render(strings.Repeat("─", min(innerWidth, helpOverlaySepMaxWidth)))),
 		centerLineStyle.Render(styles.SubtitleStyle.Render("Made With Love 💖 by Basuru Balasuriya")),
 		"",
@@ -0,0 +1,472 @@
+package ui
+
+import (
+	"OmniView/internal/adapter/logger"
+	"OmniView/internal/adapter/storage/boltdb"
+	"OmniView/internal/adapter/ui/styles"
+	"OmniView/internal/core/domain"
+	"OmniView/internal/core/ports"
+	"context"
+	"fmt"
+
+	"charm.land/bubbles/v2/viewport"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ==========================================
+// Constants
+// ==========================================
+
+// historyPageSize is the number of stored messages loaded per history page.
+const historyPageSize = 500
+
+// ==========================================
+// History State
+// ==========================================
+
+// historyState holds the trace history browser. With no selected session the
+// viewport lists the sessions of the active database; once a session is
+// opened it shows one page of that session's messages.
+type historyState struct {
+	sessions []domain.TraceSession // Stored sessions for the active database, newest first
+	cursor   int                   // Highlighted session in the list
+	selected *domain.TraceSession  // Opened session, nil while browsing the list
+	offset   int                   // Index of the first message on the current page
+	starts   []string              // Position each page up to the current one was read after
+	next     string                // Position the next page starts after
+	page     []*domain.QueueMessage
+	viewport viewport.Model
+	loading  bool
+	err      error
+}
+
+// ==========================================
+// Commands
+// ==========================================
+
+// loadHistorySessionsCmd lists the stored sessions for a database.
+func loadHistorySessionsCmd(ctx context.Context, repo ports.TraceHistoryRepository, databaseID string) tea.Cmd {
+	return func() tea.Msg {
+		sessions, err := repo.ListSessions(ctx, databaseID)
+		return historySessionsLoadedMsg{sessions: sessions, err: err}
+	}
+}
+
+// loadHistoryPageCmd loads the page of messages from a stored session that
+// follows the position after; offset is the index of its first message.
+func loadHistoryPageCmd(ctx context.Context, repo ports.TraceHistoryRepository, session domain.TraceSession, offset int, after string) tea.Cmd {
+	return func() tea.Msg {
+		messages, next, err := repo.ReadSession(ctx, session, after, historyPageSize)
+		return historyPageLoadedMsg{sessionID: session.ID, offset: offset, after: after, next: next, messages: messages, err: err}
+	}
+}
+
+// saveHistoryRetentionCmd persists new retention limits and prunes the store to match.
+func saveHistoryRetentionCmd(ctx context.Context, bolt *boltdb.BoltAdapter, repo ports.TraceHistoryRepository, retention domain.HistoryRetention) tea.Cmd {
+	return func() tea.Msg {
+		if bolt != nil {
+			if err := bolt.SetHistoryRetention(retention); err != nil {
+				return historyRetentionSavedMsg{retention: retention, err: err}
+			}
+		}
+		repo.SetRetention(retention)
+		return historyRetentionSavedMsg{retention: retention, err: repo.Prune(ctx)}
+	}
+}
+
+// ==========================================
+// Navigation
+// ==========================================
+
+// openHistory switches to the history screen for the active database.
+// It is a no-op when trace history is disabled.
+func (m *Model) openHistory() tea.Cmd {
+	if m.historyRepo == nil || m.appConfig == nil {
+		return nil
+	}
+	m.screen = screenHistory
+	m.history = historyState{loading: true}
+	m.initHistoryViewport()
+	return loadHistorySessionsCmd(m.ctx, m.historyRepo, m.appConfig.DatabaseID())
+}
+
+// closeHistory returns to the live trace console.
+func (m *Model) closeHistory() {
+	m.screen = screenMain
+	m.history = historyState{}
+	if m.main.ready {
+		m.resizeMainViewport()
+	}
+}
+
+// ==========================================
+// History Update
+// ==========================================
+
+// updateHistory handles messages when screen == "history".
+func (m *Model) updateHistory(msg tea.Msg) (*Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	// Keep the live feed flowing into the main buffer while browsing history.
+	case queueMessageMsg, eventChannelClosedMsg:
+		return m.updateMain(msg)
+
+	case historySessionsLoadedMsg:
+		m.history.loading = false
+		m.history.err = msg.err
+		if msg.err != nil {
+			logger.Error("failed to load trace history sessions", "error", msg.err)
+		}
+		m.history.sessions = msg.sessions
+		m.history.cursor = min(m.history.cursor, max(len(msg.sessions)-1, 0))
+		m.refreshHistoryContent()
+		return m, nil
+
+	case historyPageLoadedMsg:
+		if m.history.selected == nil || m.history.selected.ID != msg.sessionID {
+			return m, nil
+		}
+		m.history.loading = false
+		m.history.err = msg.err
+		if msg.err != nil {
+			logger.Error("failed to load trace history page", "session", msg.sessionID, "error", msg.err)
+			return m, nil
+		}
+		m.history.offset = msg.offset
+		m.history.starts = append(m.history.starts[:msg.offset/historyPageSize], msg.after)
+		m.history.next = msg.next
+		m.history.page = msg.messages
+		m.refreshHistoryContent()
+		m.history.viewport.GotoTop()
+		return m, nil
+
+	case historyRetentionSavedMsg:
+		m.history.err = msg.err
+		if msg.err != nil {
+			logger.Error("failed to save history retention", "error", msg.err)
+			return m, nil
+		}
+		m.history.loading = true
+		return m, loadHistorySessionsCmd(m.ctx, m.historyRepo, m.appConfig.DatabaseID())
+
+	case tea.KeyPressMsg:
+		if m.history.selected != nil {
+			return m.updateHistorySession(msg)
+		}
+		return m.updateHistoryList(msg)
+	}
+
+	var cmd tea.Cmd
+	m.history.viewport, cmd = m.history.viewport.Update(msg)
+	return m, cmd
+}
+
+// updateHistoryList handles key presses while browsing the session list.
+func (m *Model) updateHistoryList(msg tea.KeyPressMsg) (*Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q", "t":
+		m.closeHistory()
+		return m, nil
+	case "up", "k":
+		if m.history.cursor > 0 {
+			m.history.cursor--
+			m.refreshHistoryContent()
+		}
+		return m, nil
+	case "down", "j":
+		if m.history.cursor < len(m.history.sessions)-1 {
+			m.history.cursor++
+			m.refreshHistoryContent()
+		}
+		return m, nil
+	case "enter":
+		if len(m.history.sessions) == 0 {
+			return m, nil
+		}
+		session := m.history.sessions[m.history.cursor]
+		m.history.selected = &session
+		m.history.page = nil
+		m.history.offset = 0
+		m.history.starts, m.history.next = nil, ""
+		m.history.loading = true
+		m.refreshHistoryContent()
+		return m, loadHistoryPageCmd(m.ctx, m.historyRepo, session, 0, "")
+	case "r":
+		return m, saveHistoryRetentionCmd(m.ctx, m.boltAdapter, m.historyRepo, m.historyRepo.Retention().NextAge())
+	case "z":
+		return m, saveHistoryRetentionCmd(m.ctx, m.boltAdapter, m.historyRepo, m.historyRepo.Retention().NextSize())
+	}
+	return m, nil
+}
+
+// updateHistorySession handles key presses while reading an opened session.
+func (m *Model) updateHistorySession(msg tea.KeyPressMsg) (*Model, tea.Cmd) {
+	session := *m.history.selected
+	switch msg.String() {
+	case "esc", "backspace":
+		m.history.selected = nil
+		m.history.page = nil
+		m.history.offset = 0
+		m.history.starts, m.history.next = nil, ""
+		m.history.loading = false
+		m.history.err = nil
+		m.refreshHistoryContent()
+		return m, nil
+	case "q", "t":
+		m.closeHistory()
+		return m, nil
+	case "right", "]":
+		next := m.history.offset + historyPageSize
+		if m.history.loading || next >= session.MessageCount {
+			return m, nil
+		}
+		m.history.loading = true
+		return m, loadHistoryPageCmd(m.ctx, m.historyRepo, session, next, m.history.next)
+	case "left", "[":
+		if m.history.loading || m.history.offset == 0 {
+			return m, nil
+		}
+		m.history.loading = true
+		previous := m.history.offset/historyPageSize - 1
+		return m, loadHistoryPageCmd(m.ctx, m.historyRepo, session, previous*historyPageSize, m.history.starts[previous])
+	}
+
+	var cmd tea.Cmd
+	m.history.viewport, cmd = m.history.viewport.Update(msg)
+	return m, cmd
+}
+
+// ==========================================
+// History View
+// ==========================================
+
+// computeHistoryLayout computes the layout pieces for the history screen,
+// mirroring the main screen so both share the same panel geometry.
+func (m *Model) computeHistoryLayout() mainLayoutParts {
+	contentWidth, contentHeight := screenContentSize(m.width, m.height)
+	header := renderScreenHeader(
+		contentWidth,
+		"OmniView Trace History",
+		m.mainSubtitle(),
+		"",
+		m.historyRetentionMeta(),
+	)
+	statusBar := renderInfoBar(contentWidth, m.historyStatusText())
+	footer := renderFooterBar(contentWidth, m.historyFooterText())
+
+	sectionGapCount := mainGapAfterHeader + mainGapAfterStatus + mainGapAfterPanel
+	availableForPanel := contentHeight -
+		lipgloss.Height(header) -
+		lipgloss.Height(statusBar) -
+		lipgloss.Height(footer) -
+		sectionGapCount
+
+	panelHeight := max(availableForPanel, minPanelHeight, 1)
+	panelWidth, viewportWidth, viewportHeight := m.mainViewportDimensions(contentWidth, panelHeight)
+
+	return mainLayoutParts{
+		header:         header,
+		statusBar:      statusBar,
+		footer:         footer,
+		panelHeight:    panelHeight,
+		panelWidth:     panelWidth,
+		viewportWidth:  viewportWidth,
+		viewportHeight: viewportHeight,
+	}
+}
+
+// viewHistory renders the trace history screen.
+func (m *Model) viewHistory() string {
+	layout := m.computeHistoryLayout()
+
+	title := "Recorded Sessions"
+	subtitle := "Select a session to replay its trace messages"
+	if m.history.selected != nil {
+		title = "Session " + m.history.selected.StartedAt.Format("2006-01-02 15:04:05")
+		subtitle = fmt.Sprintf("Subscriber %s", sanitizeLogString(m.history.selected.Subscriber))
+	}
+
+	panelContent := lipgloss.JoinVertical(
+		lipgloss.Left,
+		styles.SectionTitleStyle.Render(title),
+		styles.SubtitleStyle.Render(subtitle),
+		"",
+		m.history.viewport.View(),
+	)
+	panel := applyTotalSize(styles.PrimaryPanelStyle, layout.panelWidth, layout.panelHeight).Render(panelContent)
+
+	sections := []string{layout.header}
+	sections = append(sections, repeatSectionGaps(mainGapAfterHeader)...)
+	sections = append(sections, layout.statusBar)
+	sections = append(sections, repeatSectionGaps(mainGapAfterStatus)...)
+	sections = append(sections, panel)
+	sections = append(sections, repeatSectionGaps(mainGapAfterPanel)...)
+	sections = append(sections, layout.footer)
+
+	return renderScreen(m.width, m.height, sections...)
+}
+
+// initHistoryViewport creates the history viewport for the current terminal size.
+func (m *Model) initHistoryViewport() {
+	layout := m.computeHistoryLayout()
+	m.history.viewport = viewport.New(
+		viewport.WithWidth(layout.viewportWidth),
+		viewport.WithHeight(layout.viewportHeight),
+	)
+	m.refreshHistoryContent()
+}
+
+// resizeHistoryViewport keeps the history viewport aligned with the terminal size.
+func (m *Model) resizeHistoryViewport() {
+	layout := m.computeHistoryLayout()
+	m.history.viewport.SetWidth(layout.viewportWidth)
+	m.history.viewport.SetHeight(layout.viewportHeight)
+	m.refreshHistoryContent()
+}
+
+// refreshHistoryContent re-renders the session list or the current page into the viewport.
+func (m *Model) refreshHistoryContent() {
+	if m.history.selected != nil {
+		m.history.viewport.SetContentLines(m.historyPageLines(m.history.viewport.Width()))
+		return
+	}
+
+	m.history.viewport.SetContentLines(m.historySessionLines(m.history.viewport.Width()))
+
+	// Keep the highlighted session inside the visible window.
+	height := m.history.viewport.Height()
+	offset := m.history.viewport.YOffset()
+	if m.history.cursor < offset {
+		m.history.viewport.SetYOffset(m.history.cursor)
+	} else if height > 0 && m.history.cursor >= offset+height {
+		m.history.viewport.SetYOffset(m.history.cursor - height + 1)
+	}
+}
+
+// historySessionLines renders one line per stored session.
+func (m *Model) historySessionLines(width int) []string {
+	if m.history.loading && len(m.history.sessions) == 0 {
+		return []string{styles.EmptyStateStyle.Render("Loading trace history...")}
+	}
+	if len(m.history.sessions) == 0 {
+		return []string{styles.EmptyStateStyle.Render("No trace history recorded for this database yet.")}
+	}
+
+	lines := make([]string, 0, len(m.history.sessions))
+	for i, session := range m.history.sessions {
+		cursor := "  "
+		if i == m.history.cursor {
+			cursor = listCursor.Render("▶ ")
+		}
+		span := fmt.Sprintf("%s → %s",
+			session.StartedAt.Format("2006-01-02 15:04:05"),
+			session.LastActivity().Format("15:04:05"),
+		)
+		stats := fmt.Sprintf("%6d messages  %9s", session.MessageCount, formatByteSize(session.Bytes))
+		line := cursor +
+			styles.LogTimestampStyle.Render(span) + "  " +
+			listItemNormal.Render(stats) + "  " +
+			styles.LogProcessStyle.Render(sanitizeLogString(session.Subscriber))
+		lines = append(lines, truncateRendered(line, width))
+	}
+	return lines
+}
+
+// historyPageLines renders the loaded page using the live console's trace columns.
+func (m *Model) historyPageLines(width int) []string {
+	if m.history.loading && len(m.history.page) == 0 {
+		return []string{styles.EmptyStateStyle.Render("Loading session messages...")}
+	}
+	if len(m.history.page) == 0 {
+		return []string{styles.EmptyStateStyle.Render("This session has no stored messages.")}
+	}
+
+	lines := make([]string, 0, len(m.history.page))
+	if width < colMinWidth {
+		for _, msg := range m.history.page {
+			lines = append(lines, m.formatLogLine(msg))
+		}
+		return lines
+	}
+
+	levelWidth, longestAPI := scanTraceColumnWidths(m.history.page)
+	layout := newTraceColumnLayout(width, levelWidth, longestAPI)
+	for _, msg := range m.history.page {
+		lines = append(lines, renderTraceColumns(parseTraceLine(msg), layout))
+	}
+	return lines
+}
+
+// historyRetentionMeta shows the active retention limits in the header.
+func (m *Model) historyRetentionMeta() string {
+	if m.historyRepo == nil {
+		return ""
+	}
+	retention := m.historyRepo.Retention()
+	return fmt.Sprintf("Retention %s • %s", retention.AgeString(), retention.SizeString())
+}
+
+// historyStatusText returns the status bar text for the history screen.
+func (m *Model) historyStatusText() string {
+	if m.history.err != nil {
+		return lipgloss.NewStyle().Foreground(styles.ErrorColor).Bold(true).Render("History unavailable: " + m.history.err.Error())
+	}
+
+	separator := styles.SubtitleStyle.Render("  •  ")
+	if m.history.selected == nil {
+		return lipgloss.JoinHorizontal(
+			lipgloss.Center,
+			styles.BodyTextStyle.Render(fmt.Sprintf("Sessions %d", len(m.history.sessions))),
+			separator,
+			styles.BodyTextStyle.Render(fmt.Sprintf("Live buffer %d/%d", len(m.main.messages), maxMessages)),
+		)
+	}
+
+	session := m.history.selected
+	first := min(m.history.offset+1, session.MessageCount)
+	last := min(m.history.offset+len(m.history.page), session.MessageCount)
+	pages := max((session.MessageCount+historyPageSize-1)/historyPageSize, 1)
+	return lipgloss.JoinHorizontal(
+		lipgloss.Center,
+		styles.BodyTextStyle.Render(fmt.Sprintf("Messages %d-%d of %d", first, last, session.MessageCount)),
+		separator,
+		styles.BodyTextStyle.Render(fmt.Sprintf("Page %d/%d", m.history.offset/historyPageSize+1, pages)),
+		separator,
+		styles.BodyTextStyle.Render(formatByteSize(session.Bytes)),
+	)
+}
+
+// historyFooterText returns the footer help text for the history screen.
+func (m *Model) historyFooterText() string {
+	if m.history.selected != nil {
+		return "↑/↓ Scroll  •  ←/→ Page  •  Esc Sessions  •  T Live Console"
+	}
+	return "↑/↓ Select  •  Enter Open  •  R Retention Age  •  Z Retention Size  •  Esc Live Console"
+}
+
+// ==========================================
+// Helpers
+// ==========================================
+
+// truncateRendered cuts a styled line to width terminal cells.
+func truncateRendered(line string, width int) string {
+	if width <= 0 || lipgloss.Width(line) <= width {
+		return line
+	}
+	return ansi.Truncate(line, width, "…")
+}
+
+// formatByteSize renders a byte count using binary units.
+func formatByteSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,155 @@
+package ui
+
+import (
+	"OmniView/internal/adapter/storage/boltdb"
+	"OmniView/internal/core/domain"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// newTestHistoryModel builds a main-screen model backed by a real history store
+// holding one recorded session with messageCount messages for the "HIST-DB" database.
+func newTestHistoryModel(t *testing.T, messageCount int) (*Model, *domain.TraceSession) {
+	t.Helper()
+
+	adapter := newTestBoltAdapter(t)
+	repo := boltdb.NewTraceHistoryRepository(adapter, domain.HistoryRetention{})
+
+	ctx := context.Background()
+	session, err := repo.BeginSession(ctx, "HIST-DB", "SUB_A")
+	if err != nil {
+		t.Fatalf("BeginSession: %v", err)
+	}
+	msgs := make([]*domain.QueueMessage, 0, messageCount)
+	for i := 0; i < messageCount; i++ {
+		msg, err := domain.NewQueueMessage(fmt.Sprintf("id-%d", i), "ORDER_API", domain.LogLevelInfo, fmt.Sprintf("payload %d", i), time.Unix(1700000000, 0))
+		if err != nil {
+			t.Fatalf("NewQueueMessage: %v", err)
+		}
+		msgs = append(msgs, msg)
+	}
+	if err := repo.Append(ctx, session, msgs); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+
+	m := newTestMainModel(t, 140, 40)
+	m.ctx = ctx
+	m.boltAdapter = adapter
+	m.historyRepo = repo
+	m.appConfig = newTestDatabaseSettings(t, "HIST-DB")
+	m.initViewport()
+	return m, session
+}
+
+// runHistoryCmd executes cmd and feeds the resulting message back into Update.
+func runHistoryCmd(t *testing.T, m *Model, cmd tea.Cmd) {
+	t.Helper()
+	if cmd == nil {
+		t.Fatal("expected a command")
+	}
+	m.Update(cmd())
+}
+
+func TestHistoryScreen_OpensFromMainAndListsSessions(t *testing.T) {
+	t.Parallel()
+
+	m, session := newTestHistoryModel(t, 3)
+
+	_, cmd := m.Update(makeCharPress("t"))
+	if m.screen != screenHistory {
+		t.Fatalf("expected T to open the history screen, got %q", m.screen)
+	}
+	runHistoryCmd(t, m, cmd)
+
+	if len(m.history.sessions) != 1 || m.history.sessions[0].ID != session.ID {
+		t.Fatalf("expected the recorded session to be listed, got %+v", m.history.sessions)
+	}
+	if view := m.viewHistory(); !strings.Contains(view, "3 messages") {
+		t.Fatalf("expected session list to show the message count, got:\n%s", view)
+	}
+
+	m.Update(makeKeyPress(tea.KeyEscape))
+	if m.screen != screenMain {
+		t.Fatalf("expected Esc to return to the main screen, got %q", m.screen)
+	}
+}
+
+func TestHistoryScreen_PagesThroughSession(t *testing.T) {
+	t.Parallel()
+
+	m, _ := newTestHistoryModel(t, historyPageSize+10)
+
+	_, cmd := m.Update(makeCharPress("t"))
+	runHistoryCmd(t, m, cmd)
+
+	_, cmd = m.Update(makeKeyPress(tea.KeyEnter))
+	runHistoryCmd(t, m, cmd)
+	if len(m.history.page) != historyPageSize || m.history.offset != 0 {
+		t.Fatalf("first page = %d messages at offset %d, want %d at 0", len(m.history.page), m.history.offset, historyPageSize)
+	}
+
+	_, cmd = m.Update(makeKeyPress(tea.KeyRight))
+	runHistoryCmd(t, m, cmd)
+	if len(m.history.page) != 10 || m.history.offset != historyPageSize {
+		t.Fatalf("second page = %d messages at offset %d, want 10 at %d", len(m.history.page), m.history.offset, historyPageSize)
+	}
+	if got := m.history.page[0].MessageID(); got != fmt.Sprintf("id-%d", historyPageSize) {
+		t.Fatalf("second page starts at %q", got)
+	}
+
+	if _, cmd = m.Update(makeKeyPress(tea.KeyRight)); cmd != nil {
+		t.Fatal("expected no page load past the last page")
+	}
+
+	_, cmd = m.Update(makeKeyPress(tea.KeyLeft))
+	runHistoryCmd(t, m, cmd)
+	if len(m.history.page) != historyPageSize || m.history.offset != 0 {
+		t.Fatalf("previous page = %d messages at offset %d, want %d at 0", len(m.history.page), m.history.offset, historyPageSize)
+	}
+	if got := m.history.page[0].MessageID(); got != "id-0" {
+		t.Fatalf("previous page starts at %q", got)
+	}
+
+	m.Update(makeKeyPress(tea.KeyEscape))
+	if m.screen != screenHistory || m.history.selected != nil {
+		t.Fatal("expected Esc in a session to return to the session list")
+	}
+}
+
+func TestHistoryScreen_KeepsBufferingLiveMessages(t *testing.T) {
+	t.Parallel()
+
+	m, _ := newTestHistoryModel(t, 1)
+	_, cmd := m.Update(makeCharPress("t"))
+	runHistoryCmd(t, m, cmd)
+
+	live, err := domain.NewQueueMessage("live", "LIVE_API", domain.LogLevelWarning, "still streaming", time.Unix(1700000100, 0))
+	if err != nil {
+		t.Fatalf("NewQueueMessage: %v", err)
+	}
+	_, cmd = m.Update(queueMessageMsg{message: live})
+
+	if len(m.main.messages) != 1 {
+		t.Fatalf("expected live message to reach the main buffer, got %d messages", len(m.main.messages))
+	}
+	if cmd == nil {
+		t.Fatal("expected the event subscription to be re-issued while on the history screen")
+	}
+}
+
+func TestHistoryScreen_DisabledWithoutRepository(t *testing.T) {
+	t.Parallel()
+
+	m := newTestMainModel(t, 120, 36)
+	m.initViewport()
+	m.Update(makeCharPress("t"))
+
+	if m.screen != screenMain {
+		t.Fatalf("expected T to be ignored without a history repository, got %q", m.screen)
+	}
+}
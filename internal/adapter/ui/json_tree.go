@@ -0,0 +1,263 @@
+package ui
+
+import (
+	"OmniView/internal/adapter/ui/styles"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// jsonAutoCollapseLines is the rendered size above which the detail pane opens
+// JSON payloads with everything below the second level folded.
+const jsonAutoCollapseLines = 200
+
+// ==========================================
+// JSON Tree
+// ==========================================
+
+type jsonNodeKind int
+
+const (
+	jsonScalar jsonNodeKind = iota
+	jsonObject
+	jsonArray
+)
+
+// jsonNode is one value of a parsed JSON payload. Object keys keep their
+// document order, which encoding/json maps would lose.
+type jsonNode struct {
+	key       string       // Member name when the parent is an object
+	hasKey    bool         // Whether key is set
+	kind      jsonNodeKind // Scalar, object or array
+	scalar    string       // JSON text of a scalar value
+	children  []*jsonNode  // Members or elements of a container
+	collapsed bool         // Whether the container is folded
+}
+
+// jsonLine is one rendered line of a jsonTree. Containers render an opening
+// and a closing line; both refer to the container node.
+type jsonLine struct {
+	node    *jsonNode
+	closing bool
+	text    string
+}
+
+// jsonTree is a foldable view over a JSON payload with a line cursor.
+type jsonTree struct {
+	root   *jsonNode
+	lines  []jsonLine
+	cursor int
+}
+
+// parseJSONTree decodes payload into a jsonTree. Returns an error when the
+// payload is not a single JSON value.
+func parseJSONTree(payload string) (*jsonTree, error) {
+	dec := json.NewDecoder(strings.NewReader(payload))
+	dec.UseNumber()
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	root, err := decodeJSONNode(dec, tok)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, errors.New("unexpected data after the JSON value")
+	}
+
+	tree := &jsonTree{root: root}
+	tree.refresh()
+	return tree, nil
+}
+
+func decodeJSONNode(dec *json.Decoder, tok json.Token) (*jsonNode, error) {
+	switch v := tok.(type) {
+	case json.Delim:
+		node := &jsonNode{kind: jsonArray}
+		if v == '{' {
+			node.kind = jsonObject
+		}
+		for dec.More() {
+			var key string
+			if node.kind == jsonObject {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				key, _ = keyTok.(string)
+			}
+			valueTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			child, err := decodeJSONNode(dec, valueTok)
+			if err != nil {
+				return nil, err
+			}
+			child.key, child.hasKey = key, node.kind == jsonObject
+			node.children = append(node.children, child)
+		}
+		// Consume the closing delimiter.
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return node, nil
+	case string:
+		quoted, _ := json.Marshal(v)
+		return &jsonNode{scalar: string(quoted)}, nil
+	case json.Number:
+		return &jsonNode{scalar: v.String()}, nil
+	case bool:
+		return &jsonNode{scalar: fmt.Sprint(v)}, nil
+	case nil:
+		return &jsonNode{scalar: "null"}, nil
+	default:
+		return nil, fmt.Errorf("unexpected JSON token %v", tok)
+	}
+}
+
+// ==========================================
+// Helpers
+// ==========================================
+
+// refresh re-renders the visible lines after a fold change, keeping the
+// cursor in range.
+func (t *jsonTree) refresh() {
+	t.lines = t.lines[:0]
+	t.root.render(&t.lines, 0, true)
+	t.cursor = min(max(t.cursor, 0), len(t.lines)-1)
+}
+
+// plainLines renders the fully expanded tree without a cursor.
+func (t *jsonTree) plainLines() []string {
+	lines := make([]string, len(t.lines))
+	for i, line := range t.lines {
+		lines[i] = line.text
+	}
+	return lines
+}
+
+// moveCursor moves the line cursor by delta, clamped to the tree.
+func (t *jsonTree) moveCursor(delta int) {
+	t.cursor = min(max(t.cursor+delta, 0), len(t.lines)-1)
+}
+
+// toggleAtCursor folds or unfolds the container under the cursor. Folding from
+// a closing line moves the cursor to the folded line.
+func (t *jsonTree) toggleAtCursor() {
+	line := t.lines[t.cursor]
+	node := line.node
+	if node.kind == jsonScalar || len(node.children) == 0 {
+		return
+	}
+	node.collapsed = !node.collapsed
+	if line.closing {
+		for i := t.cursor - 1; i >= 0; i-- {
+			if t.lines[i].node == node {
+				t.cursor = i
+				break
+			}
+		}
+	}
+	t.refresh()
+}
+
+// setAllCollapsed folds or unfolds every container below the root, so a fully
+// folded tree still lists the top-level members.
+func (t *jsonTree) setAllCollapsed(collapsed bool) {
+	current := t.lines[t.cursor].node
+	var walk func(n *jsonNode, depth int)
+	walk = func(n *jsonNode, depth int) {
+		if n.kind != jsonScalar && depth > 0 {
+			n.collapsed = collapsed
+		}
+		for _, child := range n.children {
+			walk(child, depth+1)
+		}
+	}
+	walk(t.root, 0)
+	t.refresh()
+	t.cursor = 0
+	for i, line := range t.lines {
+		if line.node == current && !line.closing {
+			t.cursor = i
+			break
+		}
+	}
+}
+
+// collapseBelow folds every container nested deeper than depth.
+func (t *jsonTree) collapseBelow(depth int) {
+	var walk func(n *jsonNode, level int)
+	walk = func(n *jsonNode, level int) {
+		if n.kind != jsonScalar && level > depth {
+			n.collapsed = true
+		}
+		for _, child := range n.children {
+			walk(child, level+1)
+		}
+	}
+	walk(t.root, 0)
+	t.refresh()
+}
+
+// ==========================================
+// View
+// ==========================================
+
+// render appends the lines for n at depth; last omits the trailing comma.
+func (n *jsonNode) render(lines *[]jsonLine, depth int, last bool) {
+	punct := styles.SyntaxPunctuationStyle.Render
+	indent := strings.Repeat("  ", depth)
+	prefix := indent
+	if n.hasKey {
+		quoted, _ := json.Marshal(n.key)
+		prefix += styles.SyntaxKeyStyle.Render(sanitizeLogString(string(quoted))) + punct(": ")
+	}
+	comma := ""
+	if !last {
+		comma = punct(",")
+	}
+
+	if n.kind == jsonScalar {
+		*lines = append(*lines, jsonLine{node: n, text: prefix + n.renderScalar() + comma})
+		return
+	}
+
+	open, closeDelim, noun := "{", "}", "keys"
+	if n.kind == jsonArray {
+		open, closeDelim, noun = "[", "]", "items"
+	}
+	switch {
+	case len(n.children) == 0:
+		*lines = append(*lines, jsonLine{node: n, text: prefix + punct(open+closeDelim) + comma})
+	case n.collapsed:
+		if len(n.children) == 1 {
+			noun = strings.TrimSuffix(noun, "s")
+		}
+		summary := styles.SyntaxCommentStyle.Render(fmt.Sprintf(" %d %s", len(n.children), noun))
+		*lines = append(*lines, jsonLine{node: n, text: prefix + punct(open+" … "+closeDelim) + comma + summary})
+	default:
+		*lines = append(*lines, jsonLine{node: n, text: prefix + punct(open)})
+		for i, child := range n.children {
+			child.render(lines, depth+1, i == len(n.children)-1)
+		}
+		*lines = append(*lines, jsonLine{node: n, closing: true, text: indent + punct(closeDelim) + comma})
+	}
+}
+
+func (n *jsonNode) renderScalar() string {
+	text := sanitizeLogString(n.scalar)
+	switch {
+	case strings.HasPrefix(n.scalar, `"`):
+		return styles.SyntaxStringStyle.Render(text)
+	case n.scalar == "true" || n.scalar == "false" || n.scalar == "null":
+		return styles.SyntaxLiteralStyle.Render(text)
+	default:
+		return styles.SyntaxNumberStyle.Render(text)
+	}
+}
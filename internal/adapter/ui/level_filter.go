@@ -0,0 +1,228 @@
+package ui
+
+import (
+	"OmniView/internal/adapter/ui/styles"
+	"OmniView/internal/core/domain"
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+)
+
+// levelOverlayMaxWidth caps the level visibility overlay on wide terminals.
+const levelOverlayMaxWidth = 60
+
+// ==========================================
+// Level Filter Sub-State
+// ==========================================
+
+// levelFilterState controls which log levels are rendered on the main screen.
+// Hidden messages stay in the buffer and reappear when their level is shown again.
+type levelFilterState struct {
+	visible     bool                     // Whether the "l" overlay is open
+	cursor      int                      // Selected row in domain.LogLevels()
+	hidden      map[domain.LogLevel]bool // Levels switched off individually
+	minSeverity int                      // Minimum LogLevel.Severity shown; 0 disables the threshold
+}
+
+// shows reports whether messages at level are rendered.
+func (s levelFilterState) shows(level domain.LogLevel) bool {
+	return !s.hidden[level] && level.Severity() >= s.minSeverity
+}
+
+// active reports whether any level is currently hidden.
+func (s levelFilterState) active() bool {
+	if s.minSeverity > 0 {
+		return true
+	}
+	for _, hidden := range s.hidden {
+		if hidden {
+			return true
+		}
+	}
+	return false
+}
+
+// ==========================================
+// Helpers
+// ==========================================
+
+// openLevelFilterOverlay shows the level overlay with the cursor on the first level.
+func (m *Model) openLevelFilterOverlay() {
+	m.levelFilter.visible = true
+	m.levelFilter.cursor = 0
+}
+
+// toggleLevel switches a single level on or off.
+func (m *Model) toggleLevel(level domain.LogLevel) {
+	if m.levelFilter.hidden == nil {
+		m.levelFilter.hidden = make(map[domain.LogLevel]bool)
+	}
+	m.levelFilter.hidden[level] = !m.levelFilter.hidden[level]
+	m.applyLevelFilter()
+}
+
+// toggleLevelByKey maps the number keys 1-5 onto DEBUG…CRITICAL.
+func (m *Model) toggleLevelByKey(key string) {
+	levels := domain.LogLevels()
+	if len(key) != 1 || key[0] < '1' || int(key[0]-'1') >= len(levels) {
+		return
+	}
+	m.toggleLevel(levels[key[0]-'1'])
+}
+
+// setMinimumLevel hides every level less severe than level. Selecting the
+// current minimum again removes the threshold.
+func (m *Model) setMinimumLevel(level domain.LogLevel) {
+	if m.levelFilter.minSeverity == level.Severity() {
+		m.levelFilter.minSeverity = 0
+	} else {
+		m.levelFilter.minSeverity = level.Severity()
+	}
+	m.applyLevelFilter()
+}
+
+// resetLevelFilter shows every level again.
+func (m *Model) resetLevelFilter() {
+	m.levelFilter.hidden = nil
+	m.levelFilter.minSeverity = 0
+	m.applyLevelFilter()
+}
+
+// applyLevelFilter re-renders the viewport after the visible levels changed.
+func (m *Model) applyLevelFilter() {
+	if !m.main.ready {
+		return
+	}
+	m.rebuildRenderedContent(m.main.viewport.Width())
+	if m.main.autoScroll {
+		m.main.viewport.GotoBottom()
+	}
+}
+
+// levelCount pairs a level with a message count.
+type levelCount struct {
+	level domain.LogLevel
+	count int
+}
+
+// hiddenLevelCounts returns the number of buffered messages hidden by the level
+// settings, in total and per level from least to most severe.
+func (m *Model) hiddenLevelCounts() (int, []levelCount) {
+	total := 0
+	var perLevel []levelCount
+	for _, level := range domain.LogLevels() {
+		count := m.main.levelCounts[level]
+		if m.levelFilter.shows(level) || count <= 0 {
+			continue
+		}
+		total += count
+		perLevel = append(perLevel, levelCount{level: level, count: count})
+	}
+	return total, perLevel
+}
+
+// ==========================================
+// Update
+// ==========================================
+
+// updateLevelFilterOverlay handles keyboard input while the level overlay is open.
+// Changes apply immediately so the feed behind the overlay updates as levels are toggled.
+func (m *Model) updateLevelFilterOverlay(msg tea.KeyPressMsg) (*Model, tea.Cmd) {
+	levels := domain.LogLevels()
+	switch key := msg.String(); key {
+	case "ctrl+c":
+		m.cancel()
+		return m, tea.Quit
+	case "esc", "l", "q":
+		m.levelFilter.visible = false
+	case "up", "k":
+		m.levelFilter.cursor = (m.levelFilter.cursor - 1 + len(levels)) % len(levels)
+	case "down", "j":
+		m.levelFilter.cursor = (m.levelFilter.cursor + 1) % len(levels)
+	case "space", "enter":
+		m.toggleLevel(levels[m.levelFilter.cursor])
+	case "m":
+		m.setMinimumLevel(levels[m.levelFilter.cursor])
+	case "r":
+		m.resetLevelFilter()
+	case "1", "2", "3", "4", "5":
+		m.toggleLevelByKey(key)
+	}
+	return m, nil
+}
+
+// ==========================================
+// View
+// ==========================================
+
+// viewLevelFilterOverlay renders the per-level visibility switches and the
+// minimum-severity threshold.
+func (m *Model) viewLevelFilterOverlay() string {
+	contentWidth, _ := screenContentSize(m.width, m.height)
+	panelWidth := max(min(contentWidth-4, levelOverlayMaxWidth), 1)
+	innerWidth := max(panelWidth-4, 1)
+
+	rows := make([]string, 0, len(domain.LogLevels()))
+	for i, level := range domain.LogLevels() {
+		marker := "  "
+		if i == m.levelFilter.cursor {
+			marker = formCursorStyle.Render("› ")
+		}
+
+		check := styles.SubtitleStyle.Render("[ ]")
+		if !m.levelFilter.hidden[level] {
+			check = lipgloss.NewStyle().Foreground(styles.SuccessColor).Render("[x]")
+		}
+
+		name := getLevelStyle(level).Render(fmt.Sprintf("%d %-8s", i+1, level))
+		note := styles.BodyTextStyle.Render(fmt.Sprintf("%5d buffered", m.main.levelCounts[level]))
+		switch {
+		case level.Severity() < m.levelFilter.minSeverity:
+			note += styles.SubtitleStyle.Render("  below minimum")
+		case level.Severity() == m.levelFilter.minSeverity:
+			note += lipgloss.NewStyle().Foreground(styles.AccentColor).Render("  minimum")
+		}
+
+		rows = append(rows, marker+check+" "+name+"  "+note)
+	}
+
+	minimum := "none"
+	if m.levelFilter.minSeverity > 0 {
+		minimum = domain.LogLevels()[m.levelFilter.minSeverity-1].String()
+	}
+	hidden, _ := m.hiddenLevelCounts()
+
+	parts := []string{
+		styles.SubtitleStyle.Width(innerWidth).Render("Hidden messages stay buffered and return when their level is shown again."),
+		"",
+		strings.Join(rows, "\n"),
+		"",
+		styles.BodyTextStyle.Render(fmt.Sprintf("Minimum level: %s  •  Hidden: %d", minimum, hidden)),
+		"",
+		styles.OnboardingHintStyle.Width(innerWidth).Render("↑/↓ Select  •  Space Toggle  •  1-5 Toggle Level  •  M Set Minimum  •  R Show All  •  Esc Close"),
+	}
+
+	return renderFramedPanel("Log Levels", panelWidth, panelTypeInfo, lipgloss.JoinVertical(lipgloss.Left, parts...))
+}
+
+// levelFilterStatusText summarises hidden messages for the status bar, e.g.
+// "Hidden 42 (DEBUG 40, INFO 2)". Returns an empty string when every level is shown.
+func (m *Model) levelFilterStatusText() string {
+	if !m.levelFilter.active() {
+		return ""
+	}
+	total, perLevel := m.hiddenLevelCounts()
+	text := styles.BodyTextStyle.Render(fmt.Sprintf("Hidden %d", total))
+	if len(perLevel) == 0 {
+		return text
+	}
+	details := make([]string, 0, len(perLevel))
+	for _, lc := range perLevel {
+		details = append(details, getLevelStyle(lc.level).Render(fmt.Sprintf("%s %d", lc.level, lc.count)))
+	}
+	return text + styles.SubtitleStyle.Render(" (") +
+		strings.Join(details, styles.SubtitleStyle.Render(", ")) +
+		styles.SubtitleStyle.Render(")")
+}
@@ -0,0 +1,136 @@
+package ui
+
+import (
+	"OmniView/internal/core/domain"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// newTestLevelModel returns a ready main-screen model holding, for each level,
+// as many messages as its severity (1 DEBUG, 2 INFO … 5 CRITICAL).
+func newTestLevelModel(t *testing.T) *Model {
+	t.Helper()
+
+	m := newTestMainModel(t, 140, 30)
+	m.initViewport()
+	for _, level := range domain.LogLevels() {
+		for i := 0; i < level.Severity(); i++ {
+			pushLevelTestMessage(t, m, level, fmt.Sprintf("%s-%d", level, i))
+		}
+	}
+	return m
+}
+
+func pushLevelTestMessage(t *testing.T, m *Model, level domain.LogLevel, id string) {
+	t.Helper()
+
+	msg, err := domain.NewQueueMessage(id, "ORDER_API", level, "payload "+id, time.Unix(1700000000, 0))
+	if err != nil {
+		t.Fatalf("NewQueueMessage: %v", err)
+	}
+	m.Update(queueMessageMsg{message: msg})
+}
+
+func TestLevelFilter_NumberKeysToggleLevelsWithoutDroppingMessages(t *testing.T) {
+	t.Parallel()
+
+	m := newTestLevelModel(t)
+	m.Update(makeCharPress("1"))
+	m.Update(makeCharPress("2"))
+
+	if got := len(m.main.renderedLines); got != 12 {
+		t.Fatalf("expected 12 visible lines with DEBUG and INFO hidden, got %d", got)
+	}
+	if len(m.main.messages) != 15 {
+		t.Fatal("hidden messages must stay in the buffer")
+	}
+	if status := m.mainStatusText(); !strings.Contains(status, "Hidden 3") || !strings.Contains(status, "DEBUG 1") || !strings.Contains(status, "INFO 2") {
+		t.Fatalf("expected hidden counts in the status bar, got %q", status)
+	}
+
+	// New messages at a hidden level are counted but not rendered.
+	pushLevelTestMessage(t, m, domain.LogLevelDebug, "late-debug")
+	pushLevelTestMessage(t, m, domain.LogLevelError, "late-error")
+	if got := len(m.main.renderedLines); got != 13 {
+		t.Fatalf("expected only the ERROR message to be appended, got %d lines", got)
+	}
+	if !strings.Contains(m.mainStatusText(), "DEBUG 2") {
+		t.Fatal("expected the late DEBUG message to be counted as hidden")
+	}
+
+	m.Update(makeCharPress("1"))
+	if got := len(m.main.renderedLines); got != 15 {
+		t.Fatalf("expected DEBUG messages to reappear when re-enabled, got %d lines", got)
+	}
+}
+
+func TestLevelFilter_OverlaySetsMinimumSeverity(t *testing.T) {
+	t.Parallel()
+
+	m := newTestLevelModel(t)
+	m.Update(makeCharPress("l"))
+	if !m.levelFilter.visible {
+		t.Fatal("expected l to open the level overlay")
+	}
+
+	m.Update(makeKeyPress(tea.KeyDown))
+	m.Update(makeKeyPress(tea.KeyDown))
+	m.Update(makeCharPress("m"))
+	if m.levelFilter.minSeverity != domain.LogLevelWarning.Severity() {
+		t.Fatalf("minSeverity = %d, want WARNING", m.levelFilter.minSeverity)
+	}
+	if got := len(m.main.renderedLines); got != 12 {
+		t.Fatalf("expected WARNING and above (12 messages), got %d", got)
+	}
+	if !strings.Contains(m.viewLevelFilterOverlay(), "below minimum") {
+		t.Fatal("expected the overlay to mark levels below the minimum")
+	}
+
+	// Space toggles the selected level in addition to the threshold.
+	m.Update(makeKeyPress(tea.KeySpace))
+	if got := len(m.main.renderedLines); got != 9 {
+		t.Fatalf("expected WARNING to be hidden as well, got %d lines", got)
+	}
+
+	if _, cmd := m.Update(makeCharPress("q")); cmd != nil {
+		t.Fatal("q should close the overlay, not quit")
+	}
+	if m.levelFilter.visible {
+		t.Fatal("expected q to close the overlay")
+	}
+
+	m.Update(makeCharPress("l"))
+	m.Update(makeCharPress("r"))
+	if m.levelFilter.active() || len(m.main.renderedLines) != 15 {
+		t.Fatal("expected R to show every level again")
+	}
+	if strings.Contains(m.mainStatusText(), "Hidden") {
+		t.Fatal("expected no hidden segment once every level is shown")
+	}
+}
+
+func TestLevelFilter_CountsFollowEvictionAndClear(t *testing.T) {
+	t.Parallel()
+
+	m := newTestLevelModel(t)
+	// Fill the buffer without rendering to keep the test fast.
+	m.main.ready = false
+	for i := len(m.main.messages); i < maxMessages; i++ {
+		pushLevelTestMessage(t, m, domain.LogLevelInfo, fmt.Sprintf("fill-%d", i))
+	}
+	m.main.ready = true
+	// Evicts the single DEBUG message at the head of the buffer.
+	pushLevelTestMessage(t, m, domain.LogLevelInfo, "overflow")
+	if got := m.main.levelCounts[domain.LogLevelDebug]; got != 0 {
+		t.Fatalf("DEBUG count after eviction = %d, want 0", got)
+	}
+
+	m.Update(makeCharPress("c"))
+	if total, _ := m.hiddenLevelCounts(); total != 0 || m.main.levelCounts != nil {
+		t.Fatal("expected clearing the feed to reset level counts")
+	}
+}
@@ -48,6 +48,10 @@ func (s stubDatabaseSettingsRepository) Replace(context.Context, string, domain.
 	return nil
 }
 
+func (s stubDatabaseSettingsRepository) SwitchDefault(context.Context, *domain.DatabaseSettings, domain.DatabaseSettings) error {
+	return nil
+}
+
 type stubPermissionsRepository struct{}
 
 func (stubPermissionsRepository) Save(context.Context, *domain.DatabasePermissions) error {
@@ -76,6 +80,10 @@ func (stubConfigRepository) SaveWebhookConfig(*domain.WebhookConfig) error { ret
 func (stubConfigRepository) GetWebhookConfig() (*domain.WebhookConfig, error) {
 	return nil, nil
 }
+func (stubConfigRepository) ListWebhookConfigs() ([]domain.WebhookConfig, error) {
+	return nil, nil
+}
+func (stubConfigRepository) SetDefaultWebhook(string) error           { return nil }
 func (stubConfigRepository) DeleteWebhookConfig(string) error         { return nil }
 func (stubConfigRepository) GetTracerPackageVersion() (string, error) { return "", nil }
 func (stubConfigRepository) SetTracerPackageVersion(string) error     { return nil }
@@ -4,7 +4,6 @@ import (
 	"OmniView/internal/adapter/logger"
 	"OmniView/internal/adapter/ui/styles"
 	"OmniView/internal/core/domain"
-	"errors"
 	"fmt"
 	"regexp"
 	"slices"
@@ -31,6 +30,9 @@ const (
 	mainGapAfterHeader  = 1
 	mainGapAfterStatus  = 0
 	mainGapAfterPanel   = 0
+	maxFooterLines      = 4
+
+	footerHintSeparator = "  •  "
 )
 
 // ==========================================
@@ -59,12 +61,18 @@ type traceLine struct {
 	api        string
 	payload    string
 	raw        *domain.QueueMessage
+	highlight  *traceHighlight // Search matches to mark; nil when no search is active
+	selected   bool            // Whether the row cursor is on this message
+	formatted  []string        // Pretty-printed payload lines; nil renders payload word-wrapped
+	attrs      []string        // Values for the layout's attribute columns
+	span       string          // Rendered span marker and duration leading the payload
 }
 
 type traceColumnLayout struct {
 	timestampWidth int
 	levelWidth     int
 	apiWidth       int
+	attrColumns    []attributeColumn // Attribute columns between process and payload
 	payloadWidth   int
 }
 
@@ -105,25 +113,33 @@ func (m *Model) updateMain(msg tea.Msg) (*Model, tea.Cmd) {
 			return m.updateDatabaseSettings(msg)
 		}
 		return m, nil
-	case webhookConfigSavedMsg:
+	case webhooksChangedMsg, deadLettersChangedMsg:
 		if m.webhookSettings.visible {
 			return m.updateWebhookSettings(msg)
 		}
 		return m, nil
+	case outputsChangedMsg:
+		if m.outputSettings.visible {
+			return m.updateOutputSettings(msg)
+		}
+		return m, nil
+	case exportCompletedMsg:
+		if m.exportDialog.visible {
+			return m.updateExportDialog(msg)
+		}
+		return m, nil
+	case webhookForwardedMsg:
+		m.handleWebhookForwarded(msg)
+		return m, nil
 
 	// New log message from event listener
 	case queueMessageMsg:
-		newPayload := len(msg.message.Payload())
-		evicted := false
-		// Evict oldest until adding the new message keeps us under both caps.
-		for len(m.main.messages) > 0 &&
-			(len(m.main.messages) >= maxMessages || m.main.totalRawBytes+newPayload > maxRawBytes) {
-			m.main.totalRawBytes -= len(m.main.messages[0].Payload())
-			m.main.messages = m.main.messages[1:]
-			evicted = true
-		}
-		m.main.messages = append(m.main.messages, msg.message)
-		m.main.totalRawBytes += newPayload
+		if m.pause.active {
+			// Frozen view: hold the message back until the stream is resumed.
+			m.queueBacklog(msg.message)
+			return m, waitForEventCmd(m.eventStreamCtx, m.eventChannel)
+		}
+		evicted := m.bufferMessage(msg.message)
 		if evicted {
 			// Column-width cache is stale after eviction — invalidate and rebuild.
 			m.invalidateColumnWidthCache()
@@ -137,6 +153,7 @@ func (m *Model) updateMain(msg tea.Msg) (*Model, tea.Cmd) {
 		if m.main.ready && m.main.autoScroll {
 			m.main.viewport.GotoBottom()
 		}
+		m.refreshSpanTimeline()
 		return m, waitForEventCmd(m.eventStreamCtx, m.eventChannel)
 
 	// Event channel closed (shutdown)
@@ -152,6 +169,18 @@ func (m *Model) updateMain(msg tea.Msg) (*Model, tea.Cmd) {
 		if m.webhookSettings.visible {
 			return m.updateWebhookSettings(msg)
 		}
+		if m.outputSettings.visible {
+			return m.updateOutputSettings(msg)
+		}
+		if m.exportDialog.visible {
+			return m.updateExportDialog(msg)
+		}
+		if m.search.prompt {
+			return m.updateSearchPrompt(msg)
+		}
+		if m.traceFilter.prompt {
+			return m.updateTraceFilterPrompt(msg)
+		}
 
 	// Keyboard input
 	case tea.KeyPressMsg:
@@ -161,6 +190,33 @@ func (m *Model) updateMain(msg tea.Msg) (*Model, tea.Cmd) {
 		if m.webhookSettings.visible {
 			return m.updateWebhookSettings(msg)
 		}
+		if m.outputSettings.visible {
+			return m.updateOutputSettings(msg)
+		}
+		if m.exportDialog.visible {
+			return m.updateExportDialog(msg)
+		}
+		if m.search.prompt {
+			return m.updateSearchPrompt(msg)
+		}
+		if m.traceFilter.prompt {
+			return m.updateTraceFilterPrompt(msg)
+		}
+		if m.levelFilter.visible {
+			return m.updateLevelFilterOverlay(msg)
+		}
+		if m.attrColumns.visible {
+			return m.updateAttributeColumnsOverlay(msg)
+		}
+		if m.timeline.visible {
+			return m.updateSpanTimeline(msg)
+		}
+		if m.health.visible {
+			return m.updateConnectionHealthOverlay(msg)
+		}
+		if m.selection.detail {
+			return m.updateMessageDetail(msg)
+		}
 		// Help overlay keyboard handling
 		if m.showHelp {
 			switch msg.String() {
@@ -188,7 +244,7 @@ func (m *Model) updateMain(msg tea.Msg) (*Model, tea.Cmd) {
 		case "c":
 			// Clear all messages
 			m.resetMainLogState()
-			m.main.viewport.SetContentLines(m.viewportLines())
+			m.syncViewportContent()
 			m.main.viewport.GotoTop()
 			return m, nil
 		case "d":
@@ -204,22 +260,93 @@ func (m *Model) updateMain(msg tea.Msg) (*Model, tea.Cmd) {
 			}
 			m.initDatabaseSettings(databases, activeID)
 			return m, nil
+		case "e":
+			// Open export dialog
+			m.openExportDialog()
+			return m, nil
+		case "f":
+			// Open trace filter prompt
+			m.openTraceFilterPrompt()
+			return m, nil
+		case "p":
+			// Pause or resume the live stream
+			m.togglePause()
+			return m, nil
+		case "o":
+			// Open attribute column picker
+			m.openAttributeColumnsOverlay()
+			return m, nil
+		case "i":
+			// Open connection health overlay
+			m.refreshConnectionHealth()
+			m.health.visible = true
+			return m, nil
+		case "v":
+			// Open span timeline
+			m.openSpanTimeline()
+			return m, nil
+		case "x":
+			// Toggle pretty-printed JSON, XML and SQL payloads
+			m.toggleExpandedPayloads()
+			return m, nil
+		case "l":
+			// Open level visibility overlay
+			m.openLevelFilterOverlay()
+			return m, nil
+		case "1", "2", "3", "4", "5":
+			// Toggle a log level (1 = DEBUG … 5 = CRITICAL)
+			m.toggleLevelByKey(msg.String())
+			return m, nil
 		case "h":
 			// Open help overlay
 			m.showHelp = true
 			return m, nil
-		case "s":
-			// Open settings
-			webhookConfig, err := m.boltAdapter.GetWebhookConfig()
-			if err != nil {
-				if errors.Is(err, domain.ErrWebhookConfigNotFound) {
-					webhookConfig = nil
-				} else {
-					logger.Error("failed to load webhook config", "error", err)
-					webhookConfig = nil
+		case "/":
+			// Open search prompt
+			m.openSearchPrompt()
+			return m, nil
+		case "n":
+			// Jump to next search match
+			m.jumpToSearchMatch(true)
+			return m, nil
+		case "N":
+			// Jump to previous search match
+			m.jumpToSearchMatch(false)
+			return m, nil
+		case "j", "k":
+			// Move the row cursor while it is shown; otherwise the viewport scrolls
+			if m.selection.active {
+				delta := 1
+				if msg.String() == "k" {
+					delta = -1
 				}
+				m.moveSelection(delta)
+				return m, nil
+			}
+		case "enter":
+			// Show the row cursor, or open the detail pane for the selected message
+			if m.selection.active {
+				m.openMessageDetail()
+			} else {
+				m.moveSelection(0)
 			}
-			m.initWebhookSettings(webhookConfig)
+			return m, nil
+		case "esc":
+			// Hide the row cursor, then clear the active search
+			if m.selection.active {
+				m.clearSelection()
+				return m, nil
+			}
+			if m.search.matcher != nil {
+				m.clearSearch()
+				return m, nil
+			}
+		case "t":
+			// Open trace history browser
+			return m, m.openHistory()
+		case "s":
+			// Open settings
+			m.openWebhookSettings()
 			return m, nil
 		}
 	}
@@ -230,11 +357,50 @@ func (m *Model) updateMain(msg tea.Msg) (*Model, tea.Cmd) {
 	return m, cmd
 }
 
+// bufferMessage appends msg to the ring buffer, evicting the oldest messages
+// until both caps hold again. Reports whether anything was evicted.
+func (m *Model) bufferMessage(msg *domain.QueueMessage) bool {
+	newPayload := len(msg.Payload())
+	evicted := false
+	if m.main.levelCounts == nil {
+		m.main.levelCounts = make(map[domain.LogLevel]int)
+	}
+	if m.main.spans == nil {
+		m.main.spans = domain.NewSpanTracker()
+	}
+	// Evict oldest until adding the new message keeps us under both caps.
+	for len(m.main.messages) > 0 &&
+		(len(m.main.messages) >= maxMessages || m.main.totalRawBytes+newPayload > maxRawBytes) {
+		m.main.totalRawBytes -= len(m.main.messages[0].Payload())
+		m.main.levelCounts[m.main.messages[0].LogLevel()]--
+		m.main.spans.Forget(m.main.messages[0])
+		m.main.messages = m.main.messages[1:]
+		evicted = true
+	}
+	m.main.messages = append(m.main.messages, msg)
+	m.main.totalRawBytes += newPayload
+	m.main.levelCounts[msg.LogLevel()]++
+	m.main.spans.Observe(msg)
+	return evicted
+}
+
 // resetMainLogState clears all buffered log state and invalidates cached widths.
 func (m *Model) resetMainLogState() {
 	m.main.messages = nil
 	m.main.renderedLines = nil
 	m.main.totalRawBytes = 0
+	m.main.levelCounts = nil
+	m.main.spans = nil
+	m.pause.backlog = nil
+	m.pause.backlogBytes = 0
+	m.pause.received = 0
+	m.search.matches = nil
+	m.search.current = -1
+	m.search.currentMsg = nil
+	m.selection.active = false
+	m.selection.msg = nil
+	m.selection.index = -1
+	m.selection.detail = false
 	m.invalidateColumnWidthCache()
 }
 
@@ -272,7 +438,7 @@ func (m *Model) computeMainLayout() mainLayoutParts {
 		m.mainConnectionMeta(),
 	)
 	statusBar := renderInfoBar(contentWidth, m.mainStatusText())
-	footer := renderFooterBar(contentWidth, m.mainFooterText())
+	footer := renderFooterBar(contentWidth, fitFooterHints(m.mainFooterHints(), contentWidth))
 
 	// Reserve one blank spacer line between each main section so the panel height
 	// calculation matches the final rendered layout exactly.
@@ -313,13 +479,14 @@ func (m *Model) viewMain() string {
 
 	viewportView := m.main.viewport.View()
 
-	logPanelContent := lipgloss.JoinVertical(
-		lipgloss.Left,
+	panelParts := []string{
 		styles.SectionTitleStyle.Render("Live Trace Feed"),
 		styles.SubtitleStyle.Render("Awaiting Trace Messages..."),
-		"",
-		viewportView,
-	)
+		m.mainColumnHeader(layout.viewportWidth),
+	}
+	panelParts = append(panelParts, m.pinnedLines(layout.viewportWidth)...)
+	panelParts = append(panelParts, viewportView)
+	logPanelContent := lipgloss.JoinVertical(lipgloss.Left, panelParts...)
 
 	logPanel := applyTotalSize(styles.PrimaryPanelStyle, layout.panelWidth, layout.panelHeight).Render(logPanelContent)
 
@@ -357,6 +524,9 @@ func (m *Model) formatLogLine(msg *domain.QueueMessage) string {
 	levelStyle := getLevelStyle(msg.LogLevel())
 
 	renderedTimestamp := styles.LogTimestampStyle.Render(timestamp)
+	if m.isSelected(msg) {
+		renderedTimestamp = styles.SelectedRowStyle.Render(timestamp)
+	}
 	renderedLevel := levelStyle.Render(fmt.Sprintf("[%-8s]", msg.LogLevel()))
 	renderedProcess := styles.LogProcessStyle.Render(truncate(sanitizeLogString(msg.ProcessName()), maxProcessNameWidth))
 	prefix := renderedTimestamp + " " + renderedLevel + " " + renderedProcess + " "
@@ -365,8 +535,11 @@ func (m *Model) formatLogLine(msg *domain.QueueMessage) string {
 	if payload == "" {
 		return prefix
 	}
+	if highlighted, ok := m.searchHighlightFor(msg).apply(payload, lipgloss.NewStyle()); ok {
+		payload = highlighted
+	}
 
-	return prefix + payload
+	return prefix + m.spanPrefix(msg) + payload
 }
 
 // parseTraceLine extracts structured data from a QueueMessage
@@ -413,30 +586,72 @@ func renderTraceColumns(line traceLine, layout traceColumnLayout) string {
 
 	// Build column styles
 	tsStyle := styles.LogTimestampStyle.Width(layout.timestampWidth)
+	if line.selected {
+		tsStyle = styles.SelectedRowStyle.Width(layout.timestampWidth)
+	}
 	lvlStyle := line.levelStyle.Width(layout.levelWidth)
 	apiStyle := styles.LogProcessStyle.Width(layout.apiWidth)
 	payStyle := lipgloss.NewStyle().Width(layout.payloadWidth)
 
-	// Word-wrap the payload text to fit within payloadWidth
-	wrappedPayload := wrapText(line.payload, layout.payloadWidth)
-	payloadLines := strings.Split(wrappedPayload, "\n")
+	// Word-wrap the payload text to fit within payloadWidth, leaving room for
+	// the span marker, which hangs in front of the payload.
+	spanWidth := lipgloss.Width(line.span)
+	if spanWidth > layout.payloadWidth/2 {
+		line.span, spanWidth = "", 0
+	}
+	wrapWidth := layout.payloadWidth - spanWidth
+	var payloadLines []string
+	if line.formatted != nil {
+		payloadLines = capFormattedLines(wrapFormattedLines(line.formatted, wrapWidth))
+	} else {
+		payloadLines = strings.Split(wrapText(line.payload, wrapWidth), "\n")
+	}
+
+	// Mark search matches after wrapping so the wrap widths stay based on plain text.
+	// A match split across a wrap boundary is not highlighted, and neither are
+	// pretty-printed payloads, whose lines already carry syntax colours.
+	api := line.api
+	if line.highlight != nil {
+		if highlighted, ok := line.highlight.apply(api, styles.LogProcessStyle); ok {
+			api = highlighted
+		}
+		if line.formatted == nil {
+			plain := lipgloss.NewStyle()
+			for i, payloadLine := range payloadLines {
+				if highlighted, ok := line.highlight.apply(payloadLine, plain); ok {
+					payloadLines[i] = highlighted
+				}
+			}
+		}
+	}
 
 	// Build continuation line indent (spaces for fixed columns + separator)
-	indent := strings.Repeat(" ", layout.timestampWidth+layout.levelWidth+layout.apiWidth+(len(colSeparator)*3))
+	indentWidth := layout.timestampWidth + layout.levelWidth + layout.apiWidth + (len(colSeparator) * 3) + spanWidth
+	for _, col := range layout.attrColumns {
+		indentWidth += col.width + len(colSeparator)
+	}
+	indent := strings.Repeat(" ", indentWidth)
 
 	var result strings.Builder
 
 	// Render first line with columns
-	result.WriteString(lipgloss.JoinHorizontal(
-		lipgloss.Top,
+	cells := []string{
 		tsStyle.Render(line.timestamp),
 		colSeparator,
 		lvlStyle.Render(line.level),
 		colSeparator,
-		apiStyle.Render(line.api),
+		apiStyle.Render(api),
 		colSeparator,
-		payStyle.Render(payloadLines[0]),
-	))
+	}
+	for i, col := range layout.attrColumns {
+		value := ""
+		if i < len(line.attrs) {
+			value = line.attrs[i]
+		}
+		cells = append(cells, styles.SyntaxAttrStyle.Width(col.width).Render(value), colSeparator)
+	}
+	cells = append(cells, payStyle.Render(line.span+payloadLines[0]))
+	result.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, cells...))
 
 	// Render continuation lines if payload wrapped
 	for i := 1; i < len(payloadLines); i++ {
@@ -570,13 +785,16 @@ func wrapText(text string, width int) string {
 // renderCompactLine is a fallback format for narrow terminals
 func renderCompactLine(line traceLine) string {
 	tsStyle := styles.LogTimestampStyle
+	if line.selected {
+		tsStyle = styles.SelectedRowStyle
+	}
 	lvlStyle := line.levelStyle
 	apiStyle := styles.LogProcessStyle
 
 	return tsStyle.Render(line.timestamp) + " " +
 		lvlStyle.Render(line.level) + " " +
 		apiStyle.Render(truncate(line.api, 15)) + " " +
-		line.payload
+		line.span + line.payload
 }
 
 // initViewport creates and configures the viewport for the main screen.
@@ -627,10 +845,14 @@ func (m *Model) resizeMainViewport() {
 // rebuildRenderedContent regenerates the viewport buffer for the current width
 // without changing the trace formatting rules.
 func (m *Model) rebuildRenderedContent(viewportWidth int) {
+	m.search.matches = nil
+	m.search.current = -1
+	m.selection.index = -1
+
 	// Preserve the empty-state content on rebuild.
 	if len(m.main.messages) == 0 {
 		m.main.renderedLines = nil
-		m.main.viewport.SetContentLines(m.viewportLines())
+		m.syncViewportContent()
 		return
 	}
 
@@ -639,66 +861,70 @@ func (m *Model) rebuildRenderedContent(viewportWidth int) {
 
 	filtered := m.filterMessages(m.main.messages)
 	rendered := make([]string, 0, len(filtered))
-	for _, queuedMsg := range filtered {
-		if useColumns {
-			rendered = append(rendered, renderTraceColumns(parseTraceLine(queuedMsg), layout))
-		} else {
-			rendered = append(rendered, m.formatLogLine(queuedMsg))
-		}
+	for i, queuedMsg := range filtered {
+		rendered = append(rendered, m.renderMessage(queuedMsg, layout, useColumns))
+		m.recordSearchMatch(i, queuedMsg)
+		m.recordSelection(i, queuedMsg)
 	}
 	m.main.renderedLines = rendered
 
-	m.main.viewport.SetContentLines(m.main.renderedLines)
+	m.syncViewportContent()
 }
 
-func (m *Model) traceColumnLayout(availableWidth int) traceColumnLayout {
-	separatorWidth := len(colSeparator) * 3
-	cacheValid := m.main.cachedWidthKey == availableWidth && len(m.main.messages) > 0
+// renderMessage renders one trace message for the main viewport, marking
+// search matches and the row cursor.
+func (m *Model) renderMessage(msg *domain.QueueMessage, layout traceColumnLayout, useColumns bool) string {
+	if !useColumns {
+		return m.formatLogLine(msg)
+	}
+	line := parseTraceLine(msg)
+	line.highlight = m.searchHighlightFor(msg)
+	line.selected = m.isSelected(msg)
+	line.attrs = attributeValues(msg, layout)
+	line.span = m.spanPrefix(msg)
+	if m.main.expanded {
+		line.formatted = expandedPayloadLines(msg.Payload())
+	}
+	return renderTraceColumns(line, layout)
+}
 
+// syncViewportContent hands the rendered lines to the viewport. The viewport
+// splits multi-line entries in place, so it gets a copy to keep renderedLines
+// at one entry per message.
+func (m *Model) syncViewportContent() {
+	m.main.viewport.SetContentLines(slices.Clone(m.viewportLines()))
+}
+
+func (m *Model) traceColumnLayout(availableWidth int) traceColumnLayout {
 	// Use cached values if availableWidth matches the cached width key
 	// (messages were added incrementally and width hasn't changed)
-	var levelWidth int
-	if cacheValid {
-		// Fast path: use cached column widths
-		levelWidth = m.main.cachedLevelWidth
-	} else {
+	if m.main.cachedWidthKey != availableWidth || len(m.main.messages) == 0 {
 		// Slow path: full scan needed (width changed, messages removed, or initial build)
-		levelWidth = colMinLevelWidth
-		for _, queuedMsg := range m.main.messages {
-			width := lipgloss.Width(formatTraceLevel(queuedMsg.LogLevel()))
-			if width > levelWidth {
-				levelWidth = width
-			}
-		}
-		levelWidth = min(max(levelWidth, colMinLevelWidth), colMaxLevelWidth)
+		m.main.cachedLevelWidth, m.main.cachedAPIWidth = scanTraceColumnWidths(m.main.messages)
+		m.main.cachedWidthKey = availableWidth
+	}
+
+	return m.withAttributeColumns(newTraceColumnLayout(availableWidth, m.main.cachedLevelWidth, m.main.cachedAPIWidth))
+}
 
-		// Update cache
-		m.main.cachedLevelWidth = levelWidth
+// scanTraceColumnWidths returns the clamped level column width and the longest
+// process name width across msgs.
+func scanTraceColumnWidths(msgs []*domain.QueueMessage) (levelWidth int, longestAPI int) {
+	levelWidth = colMinLevelWidth
+	for _, queuedMsg := range msgs {
+		levelWidth = max(levelWidth, lipgloss.Width(formatTraceLevel(queuedMsg.LogLevel())))
+		longestAPI = max(longestAPI, lipgloss.Width(truncate(sanitizeLogString(queuedMsg.ProcessName()), colMaxAPIWidth)))
 	}
+	return min(max(levelWidth, colMinLevelWidth), colMaxLevelWidth), longestAPI
+}
 
+// newTraceColumnLayout distributes availableWidth across the trace columns.
+func newTraceColumnLayout(availableWidth, levelWidth, longestAPI int) traceColumnLayout {
+	separatorWidth := len(colSeparator) * 3
 	baseWidth := colTimestampWidth + levelWidth + separatorWidth
 	maxAllowedAPIWidth := max(availableWidth-baseWidth-colMinPayloadWidth, colMinAPIWidth)
 	apiWidth := min(colMaxAPIWidth, maxAllowedAPIWidth)
 
-	// Use cached API width if availableWidth matches
-	var longestAPI int
-	if cacheValid {
-		longestAPI = m.main.cachedAPIWidth
-	} else {
-		// Full scan for API width
-		longestAPI = 0
-		for _, queuedMsg := range m.main.messages {
-			width := lipgloss.Width(truncate(sanitizeLogString(queuedMsg.ProcessName()), colMaxAPIWidth))
-			if width > longestAPI {
-				longestAPI = width
-			}
-		}
-
-		// Update cache
-		m.main.cachedAPIWidth = longestAPI
-		m.main.cachedWidthKey = availableWidth
-	}
-
 	if longestAPI > 0 {
 		apiWidth = min(apiWidth, max(longestAPI, colMinAPIWidth))
 	}
@@ -719,7 +945,8 @@ func (m *Model) mainViewportDimensions(contentWidth, panelHeight int) (int, int,
 	panelHorizontalFrame, panelVerticalFrame := styles.PrimaryPanelStyle.GetFrameSize()
 	panelTextHeight := lipgloss.Height(styles.SectionTitleStyle.Render("Live Trace Feed")) +
 		lipgloss.Height(styles.SubtitleStyle.Render("Awaiting Trace Messages...")) +
-		1
+		1 +
+		len(m.selection.pinned)
 
 	viewportWidth := max(panelWidth-panelHorizontalFrame, 1)
 	viewportHeight := max(panelHeight-panelVerticalFrame-panelTextHeight, 1)
@@ -743,13 +970,16 @@ func (m *Model) mainSubtitle() string {
 	)
 }
 
-// mainConnectionMeta: returns connection metadata for display in the status bar (currently unused, reserved for future health details).
-func (m *Model) mainConnectionMeta() string {
-	return ""
-}
-
-// mainStatusText: returns the status bar text showing subscriber name, auto-scroll state, message count, and broadcast mode.
+// mainStatusText: returns the status bar text showing subscriber name, auto-scroll state, message count, broadcast mode, hidden levels, the active filter and the active search.
 func (m *Model) mainStatusText() string {
+	// The search and filter prompts take over the status bar while they are edited.
+	if m.search.prompt {
+		return m.searchStatusText()
+	}
+	if m.traceFilter.prompt {
+		return m.traceFilterStatusText()
+	}
+
 	autoScroll := styles.WarningColor
 	autoScrollText := "manual"
 	if m.main.autoScroll {
@@ -779,16 +1009,26 @@ func (m *Model) mainStatusText() string {
 		broadcastModeStyle = styles.WarningColor
 	}
 
-	return lipgloss.JoinHorizontal(
-		lipgloss.Center,
-		styles.BodyTextStyle.Render(subscriberLabel+" ")+subscriberNameStyle.Render(subscriberName),
+	segments := []string{
+		styles.BodyTextStyle.Render(subscriberLabel+" ") + subscriberNameStyle.Render(subscriberName),
 		styles.SubtitleStyle.Render("  •  "),
 		styles.BodyTextStyle.Render(fmt.Sprintf("Messages %d/%d", len(m.main.messages), maxMessages)),
 		styles.SubtitleStyle.Render("  •  "),
-		lipgloss.NewStyle().Foreground(autoScroll).Bold(true).Render("Auto Scroll ["+autoScrollText+"]"),
+		lipgloss.NewStyle().Foreground(autoScroll).Bold(true).Render("Auto Scroll [" + autoScrollText + "]"),
 		styles.SubtitleStyle.Render("  •  "),
-		lipgloss.NewStyle().Foreground(broadcastModeStyle).Bold(true).Render("["+broadcastModeText+"]"),
-	)
+		lipgloss.NewStyle().Foreground(broadcastModeStyle).Bold(true).Render("[" + broadcastModeText + "]"),
+	}
+	if hidden := m.levelFilterStatusText(); hidden != "" {
+		segments = append(segments, styles.SubtitleStyle.Render("  •  "), hidden)
+	}
+	if filter := m.traceFilterStatusText(); filter != "" {
+		segments = append(segments, styles.SubtitleStyle.Render("  •  "), filter)
+	}
+	if search := m.searchStatusText(); search != "" {
+		segments = append(segments, styles.SubtitleStyle.Render("  •  "), search)
+	}
+
+	return lipgloss.JoinHorizontal(lipgloss.Center, segments...)
 }
 
 func (m *Model) mainProcedureCall() string {
@@ -808,9 +1048,76 @@ func (m *Model) mainProcedureCall() string {
 	)
 }
 
+// mainFooterHints: returns the keyboard shortcut hints shown in the footer, in display order.
+func (m *Model) mainFooterHints() []string {
+	pauseHint := "P Pause"
+	if m.pause.active {
+		pauseHint = "P Resume"
+	}
+	expandHint := "X Expand Payloads"
+	if m.main.expanded {
+		expandHint = "X Collapse Payloads"
+	}
+	hints := []string{
+		"↑/↓ Scroll",
+		"/ Search",
+		"Enter Select",
+		pauseHint,
+		"A Auto Scroll",
+		"B Mode",
+		"C Clear",
+		"D Database Settings",
+		"E Export",
+		"F Filter",
+		"H Help",
+		"I Health",
+		"L Levels",
+		"O Columns",
+		"S Settings",
+		"T History",
+		"V Timeline",
+		expandHint,
+		"Q Quit",
+	}
+	// The backlog notice leads so it survives fitFooterHints on narrow terminals.
+	if notice := m.pauseFooterHint(); notice != "" {
+		hints = append([]string{notice}, hints...)
+	}
+	return hints
+}
+
 // mainFooterText: returns the footer help text showing available keyboard shortcuts.
 func (m *Model) mainFooterText() string {
-	return "↑/↓ Scroll  •  A Auto Scroll  •  B Mode  •  C Clear  •  D Database Settings  •  H Help  •  S Settings  •  Q Quit"
+	return strings.Join(m.mainFooterHints(), footerHintSeparator)
+}
+
+// fitFooterHints joins hints into footer text that wraps to at most
+// maxFooterLines inside a footer of the given width. Hints are dropped from the
+// end of the list, keeping Help and Quit, so narrow terminals still point users
+// at the full shortcut reference.
+func fitFooterHints(hints []string, width int) string {
+	horizontalFrame, _ := styles.FooterStyle.GetFrameSize()
+	textWidth := max(width-horizontalFrame, 1)
+	textStyle := lipgloss.NewStyle().Width(textWidth)
+
+	kept := slices.Clone(hints)
+	for {
+		text := strings.Join(kept, footerHintSeparator)
+		if lipgloss.Height(textStyle.Render(text)) <= maxFooterLines {
+			return text
+		}
+		drop := -1
+		for i := len(kept) - 1; i >= 0; i-- {
+			if kept[i] != "H Help" && kept[i] != "Q Quit" {
+				drop = i
+				break
+			}
+		}
+		if drop < 0 {
+			return text
+		}
+		kept = slices.Delete(kept, drop, drop+1)
+	}
 }
 
 // appendSingleMessage appends only the newly-arrived message to the rendered buffer.
@@ -870,10 +1177,11 @@ func (m *Model) appendSingleMessage(msg *domain.QueueMessage, viewportWidth int)
 			}
 		}
 
-		m.main.renderedLines = append(m.main.renderedLines, renderTraceColumns(parseTraceLine(msg), layout))
+		m.main.renderedLines = append(m.main.renderedLines, m.renderMessage(msg, layout, true))
 	} else {
 		m.main.renderedLines = append(m.main.renderedLines, m.formatLogLine(msg))
 	}
+	m.recordSearchMatch(len(m.main.renderedLines)-1, msg)
 
-	m.main.viewport.SetContentLines(m.main.renderedLines)
+	m.syncViewportContent()
 }
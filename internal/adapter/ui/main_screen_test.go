@@ -738,3 +738,22 @@ func assertRenderedWithinTerminal(t *testing.T, rendered string, width, height i
 		t.Fatalf("rendered height mismatch: got %d want %d", got, height)
 	}
 }
+
+func TestFitFooterHints_DropsTrailingHintsButKeepsHelpAndQuit(t *testing.T) {
+	t.Parallel()
+
+	m := newTestMainModel(t, 120, 36)
+	hints := m.mainFooterHints()
+
+	if got := fitFooterHints(hints, 400); got != m.mainFooterText() {
+		t.Fatalf("expected all hints on a wide terminal, got %q", got)
+	}
+
+	narrow := fitFooterHints(hints, 30)
+	if !strings.Contains(narrow, "H Help") || !strings.Contains(narrow, "Q Quit") {
+		t.Fatalf("expected Help and Quit to survive on a narrow terminal, got %q", narrow)
+	}
+	if strings.Contains(narrow, "T History") {
+		t.Fatalf("expected trailing hints to be dropped on a narrow terminal, got %q", narrow)
+	}
+}
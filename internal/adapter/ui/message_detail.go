@@ -0,0 +1,451 @@
+package ui
+
+import (
+	"OmniView/internal/adapter/ui/styles"
+	"OmniView/internal/core/domain"
+	"OmniView/internal/service/tracer"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"charm.land/bubbles/v2/viewport"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+)
+
+// maxPinnedMessages bounds the pinned strip above the trace feed.
+const maxPinnedMessages = 3
+
+// ==========================================
+// Selection Sub-State
+// ==========================================
+
+// selectionState holds the row cursor over the filtered messages, the detail
+// pane for the selected message and the pinned messages.
+type selectionState struct {
+	active     bool                   // Whether the row cursor is shown
+	msg        *domain.QueueMessage   // Selected message, survives rebuilds
+	index      int                    // renderedLines index of msg, -1 when it is filtered out
+	detail     bool                   // Whether the detail pane is open
+	detailView viewport.Model         // Scrollable body of the detail pane
+	notice     settingsDialog         // Feedback from the last detail-pane action
+	forwarding bool                   // Whether a manual webhook forward is in flight
+	pinned     []*domain.QueueMessage // Pinned messages, oldest first
+	jsonTree   *jsonTree              // Foldable view of a JSON payload in the detail pane, nil otherwise
+}
+
+// ==========================================
+// Cursor
+// ==========================================
+
+// isSelected reports whether msg is under the row cursor.
+func (m *Model) isSelected(msg *domain.QueueMessage) bool {
+	return m.selection.active && msg == m.selection.msg
+}
+
+// recordSelection notes the rendered index of the selected message.
+// Called while (re)building renderedLines so the index stays aligned with them.
+func (m *Model) recordSelection(index int, msg *domain.QueueMessage) {
+	if m.isSelected(msg) {
+		m.selection.index = index
+	}
+}
+
+// moveSelection moves the row cursor by delta entries, placing it on the last
+// visible entry when the cursor is not shown yet. Auto-scroll is paused so the
+// selected row stays put while new messages arrive.
+func (m *Model) moveSelection(delta int) {
+	if !m.main.ready || len(m.main.renderedLines) == 0 {
+		return
+	}
+	filtered := m.filterMessages(m.main.messages)
+	m.main.autoScroll = false
+
+	previous := m.selection.index
+	next := previous + delta
+	switch {
+	case !m.selection.active || previous < 0:
+		next = m.lastVisibleEntry()
+	case next < 0:
+		next = 0
+	case next >= len(filtered):
+		next = len(filtered) - 1
+	}
+
+	m.selection.active = true
+	m.selection.index = next
+	m.selection.msg = filtered[next]
+	if previous >= 0 && previous != next {
+		m.rerenderEntry(previous, filtered)
+	}
+	m.rerenderEntry(next, filtered)
+	m.syncViewportContent()
+	m.ensureSelectionVisible()
+}
+
+// clearSelection hides the row cursor.
+func (m *Model) clearSelection() {
+	previous := m.selection.index
+	m.selection.active = false
+	m.selection.msg = nil
+	m.selection.index = -1
+	m.selection.detail = false
+	if m.main.ready && previous >= 0 {
+		m.rerenderEntry(previous, m.filterMessages(m.main.messages))
+		m.syncViewportContent()
+	}
+}
+
+// lastVisibleEntry returns the last rendered entry that starts inside the viewport.
+func (m *Model) lastVisibleEntry() int {
+	bottom := m.main.viewport.YOffset() + m.main.viewport.Height() - 1
+	offsets := m.renderedLineOffsets()
+	for i := len(offsets) - 1; i >= 0; i-- {
+		if offsets[i] <= bottom {
+			return i
+		}
+	}
+	return 0
+}
+
+// ensureSelectionVisible scrolls the viewport just enough to show the selected entry.
+func (m *Model) ensureSelectionVisible() {
+	entry := m.selection.index
+	if entry < 0 || entry >= len(m.main.renderedLines) {
+		return
+	}
+	top := m.renderedLineOffset(entry)
+	bottom := top + strings.Count(m.main.renderedLines[entry], "\n")
+	height := m.main.viewport.Height()
+	switch {
+	case top < m.main.viewport.YOffset():
+		m.main.viewport.SetYOffset(top)
+	case bottom >= m.main.viewport.YOffset()+height:
+		m.main.viewport.SetYOffset(max(bottom-height+1, 0))
+	}
+}
+
+// ==========================================
+// Pins
+// ==========================================
+
+// isPinned reports whether msg is in the pinned strip.
+func (m *Model) isPinned(msg *domain.QueueMessage) bool {
+	return slices.Contains(m.selection.pinned, msg)
+}
+
+// togglePin pins or unpins msg. Pinning beyond maxPinnedMessages drops the
+// oldest pin. The viewport shrinks or grows to make room for the strip.
+func (m *Model) togglePin(msg *domain.QueueMessage) bool {
+	pinned := !m.isPinned(msg)
+	if pinned {
+		m.selection.pinned = append(m.selection.pinned, msg)
+		if len(m.selection.pinned) > maxPinnedMessages {
+			m.selection.pinned = m.selection.pinned[1:]
+		}
+	} else {
+		m.selection.pinned = slices.DeleteFunc(m.selection.pinned, func(p *domain.QueueMessage) bool { return p == msg })
+	}
+	if m.main.ready {
+		m.resizeMainViewport()
+	}
+	return pinned
+}
+
+// pinnedLines renders one line per pinned message, truncated to width.
+func (m *Model) pinnedLines(width int) []string {
+	lines := make([]string, 0, len(m.selection.pinned))
+	for _, msg := range m.selection.pinned {
+		line := styles.PinnedMessageStyle.Render("★ ") +
+			styles.LogTimestampStyle.Render(msg.Timestamp().Format("15:04:05")) + " " +
+			getLevelStyle(msg.LogLevel()).Render(formatTraceLevel(msg.LogLevel())) + " " +
+			styles.LogProcessStyle.Render(truncate(sanitizeLogString(msg.ProcessName()), colMaxAPIWidth)) + " " +
+			strings.Join(strings.Fields(sanitizeLogString(msg.Payload())), " ")
+		lines = append(lines, truncateRendered(line, width))
+	}
+	return lines
+}
+
+// ==========================================
+// Detail Pane
+// ==========================================
+
+// openMessageDetail shows the detail pane for the selected message.
+func (m *Model) openMessageDetail() {
+	if !m.selection.active || m.selection.msg == nil {
+		return
+	}
+	m.selection.detail = true
+	m.selection.notice.clear()
+	m.selection.jsonTree = nil
+	if payload := m.selection.msg.Payload(); detectPayloadKind(payload) == payloadJSON {
+		if tree, err := parseJSONTree(payload); err == nil {
+			if len(tree.lines) > jsonAutoCollapseLines {
+				tree.collapseBelow(1)
+			}
+			m.selection.jsonTree = tree
+		}
+	}
+	m.selection.detailView = viewport.New()
+	m.resizeMessageDetail()
+	m.selection.detailView.GotoTop()
+}
+
+// messageDetailSize returns the detail panel width and the body viewport size.
+func (m *Model) messageDetailSize() (panelWidth, bodyWidth, bodyHeight int) {
+	panelWidth = settingsPanelWidth(m.width)
+	_, contentHeight := screenContentSize(m.width, m.height)
+	// Frame (2), notice and spacing (3) and the action hint (2).
+	return panelWidth, max(panelWidth-4, 1), max(contentHeight-9, 3)
+}
+
+// resizeMessageDetail fits the detail body to the terminal and re-renders it.
+func (m *Model) resizeMessageDetail() {
+	if !m.selection.detail {
+		return
+	}
+	_, bodyWidth, bodyHeight := m.messageDetailSize()
+	m.selection.detailView.SetWidth(bodyWidth)
+	m.selection.detailView.SetHeight(bodyHeight)
+	m.refreshMessageDetail()
+}
+
+// refreshMessageDetail re-renders the detail body and scrolls the JSON tree
+// cursor into view.
+func (m *Model) refreshMessageDetail() {
+	body, cursorLine := m.renderMessageDetail(m.selection.msg, m.selection.detailView.Width())
+	m.selection.detailView.SetContent(body)
+	if cursorLine < 0 {
+		return
+	}
+	view := &m.selection.detailView
+	switch {
+	case m.selection.jsonTree.cursor == 0:
+		view.GotoTop()
+	case cursorLine < view.YOffset():
+		view.SetYOffset(cursorLine)
+	case cursorLine >= view.YOffset()+view.Height():
+		view.SetYOffset(cursorLine - view.Height() + 1)
+	}
+}
+
+// messageDetailBody renders every field of msg and the full payload wrapped to width.
+func (m *Model) messageDetailBody(msg *domain.QueueMessage, width int) string {
+	body, _ := m.renderMessageDetail(msg, width)
+	return body
+}
+
+// renderMessageDetail renders the detail body for msg. JSON, XML and SQL
+// payloads are pretty-printed; for a JSON tree it also returns the body line
+// of the tree cursor, or -1 when there is no tree.
+func (m *Model) renderMessageDetail(msg *domain.QueueMessage, width int) (string, int) {
+	label := func(name string) string {
+		return styles.SubtitleStyle.Render(fmt.Sprintf("%-16s", name))
+	}
+	value := styles.BodyTextStyle
+
+	webhook := "no"
+	if msg.SendToWebhook() {
+		webhook = "yes"
+		if target := msg.WebhookTarget(); target != "" {
+			webhook += " → " + sanitizeLogString(target)
+		}
+	}
+	pinned := ""
+	if m.isPinned(msg) {
+		pinned = styles.PinnedMessageStyle.Render("  ★ pinned")
+	}
+
+	lines := []string{
+		label("Message ID") + value.Render(sanitizeLogString(msg.MessageID())) + pinned,
+		label("Timestamp") + value.Render(msg.Timestamp().Format(time.RFC3339Nano)),
+		label("Level") + getLevelStyle(msg.LogLevel()).Render(msg.LogLevel().String()),
+		label("Process") + styles.LogProcessStyle.Render(sanitizeLogString(msg.ProcessName())),
+		label("Mode") + value.Render(sanitizeLogString(msg.Mode())),
+		label("Send to Webhook") + value.Render(webhook),
+	}
+	lines = append(lines, m.spanDetailLines(msg, label)...)
+	lines = append(lines, "")
+	if keys := msg.AttributeKeys(); len(keys) > 0 {
+		lines = append(lines, styles.SectionTitleStyle.Render("Attributes"))
+		for _, key := range keys {
+			attr, _ := msg.Attribute(key)
+			lines = append(lines, wrapFormattedLines([]string{styles.SyntaxAttrStyle.Render(fmt.Sprintf("%-16s", sanitizeLogString(key))) + " " + value.Render(sanitizeLogString(attr))}, width)...)
+		}
+		lines = append(lines, "")
+	}
+
+	cursorLine := -1
+	tree := m.selection.jsonTree
+	if tree != nil && msg == m.selection.msg {
+		lines = append(lines, styles.SectionTitleStyle.Render("Payload (JSON)"))
+		for i, line := range tree.lines {
+			marker := "  "
+			if i == tree.cursor {
+				marker = styles.SelectedRowStyle.Render("›") + " "
+				cursorLine = len(lines)
+			}
+			for j, wrapped := range wrapFormattedLines([]string{line.text}, max(width-2, 1)) {
+				if j > 0 {
+					marker = "  "
+				}
+				lines = append(lines, marker+wrapped)
+			}
+		}
+		return strings.Join(lines, "\n"), cursorLine
+	}
+
+	kind := detectPayloadKind(msg.Payload())
+	if formatted, ok := formatPayload(msg.Payload(), kind); ok {
+		lines = append(lines, styles.SectionTitleStyle.Render("Payload ("+kind.String()+")"))
+		lines = append(lines, wrapFormattedLines(formatted, width)...)
+	} else {
+		lines = append(lines, styles.SectionTitleStyle.Render("Payload"), wrapText(sanitizeLogString(msg.Payload()), width))
+	}
+	return strings.Join(lines, "\n"), cursorLine
+}
+
+// updateJSONTree handles tree navigation and folding keys. Returns false for
+// keys it does not handle.
+func (m *Model) updateJSONTree(key string) bool {
+	tree := m.selection.jsonTree
+	switch key {
+	case "up", "k":
+		tree.moveCursor(-1)
+	case "down", "j":
+		tree.moveCursor(1)
+	case "space":
+		tree.toggleAtCursor()
+	case "-":
+		tree.setAllCollapsed(true)
+	case "+", "=":
+		tree.setAllCollapsed(false)
+	default:
+		return false
+	}
+	m.refreshMessageDetail()
+	return true
+}
+
+// filterToProcessExpr builds a trace filter expression matching name exactly.
+func filterToProcessExpr(name string) string {
+	return "process = " + strconv.Quote(name)
+}
+
+// forwardToWebhookCmd queues msg for the configured webhook off the UI goroutine.
+func forwardToWebhookCmd(ts *tracer.TracerService, msg *domain.QueueMessage) tea.Cmd {
+	return func() tea.Msg {
+		return webhookForwardedMsg{message: msg, err: ts.ForwardToWebhook(msg)}
+	}
+}
+
+// ==========================================
+// Update
+// ==========================================
+
+// updateMessageDetail handles keyboard input while the detail pane is open.
+func (m *Model) updateMessageDetail(msg tea.KeyPressMsg) (*Model, tea.Cmd) {
+	selected := m.selection.msg
+	switch msg.String() {
+	case "ctrl+c":
+		m.cancel()
+		return m, tea.Quit
+	case "esc", "enter", "q":
+		m.selection.detail = false
+		return m, nil
+	case "c":
+		m.selection.notice.set("Payload copied to clipboard.", false)
+		return m, tea.SetClipboard(selected.Payload())
+	case "y":
+		raw, err := json.Marshal(selected)
+		if err != nil {
+			m.selection.notice.set(fmt.Sprintf("copy as JSON: %v", err), true)
+			return m, nil
+		}
+		m.selection.notice.set("Message JSON copied to clipboard.", false)
+		return m, tea.SetClipboard(string(raw))
+	case "f":
+		filter, err := domain.ParseTraceFilter(filterToProcessExpr(selected.ProcessName()))
+		if err != nil {
+			m.selection.notice.set(err.Error(), true)
+			return m, nil
+		}
+		m.selection.detail = false
+		m.applyTraceFilter(filter)
+		m.ensureSelectionVisible()
+		return m, nil
+	case "p":
+		if m.togglePin(selected) {
+			m.selection.notice.set("Pinned above the trace feed.", false)
+		} else {
+			m.selection.notice.set("Unpinned.", false)
+		}
+		m.resizeMessageDetail()
+		return m, nil
+	case "w":
+		if m.selection.forwarding {
+			return m, nil
+		}
+		if m.tracerService == nil {
+			m.selection.notice.set("not connected: the webhook dispatcher is unavailable", true)
+			return m, nil
+		}
+		m.selection.forwarding = true
+		m.selection.notice.set("Forwarding to webhook...", false)
+		return m, forwardToWebhookCmd(m.tracerService, selected)
+	}
+	if m.selection.jsonTree != nil && m.updateJSONTree(msg.String()) {
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.selection.detailView, cmd = m.selection.detailView.Update(msg)
+	return m, cmd
+}
+
+// handleWebhookForwarded reports the outcome of a manual forward in the detail pane.
+func (m *Model) handleWebhookForwarded(msg webhookForwardedMsg) {
+	m.selection.forwarding = false
+	if !m.selection.detail || msg.message != m.selection.msg {
+		return
+	}
+	if msg.err != nil {
+		m.selection.notice.set(msg.err.Error(), true)
+		return
+	}
+	m.selection.notice.set("Queued for delivery to the configured webhook.", false)
+}
+
+// ==========================================
+// View
+// ==========================================
+
+// viewMessageDetail renders the detail pane overlay for the selected message.
+func (m *Model) viewMessageDetail() string {
+	panelWidth, bodyWidth, _ := m.messageDetailSize()
+
+	parts := []string{m.selection.detailView.View()}
+	if m.selection.notice.visible {
+		style := styles.OnboardingSavedStyle
+		if m.selection.notice.isError {
+			style = styles.OnboardingErrorStyle
+		}
+		parts = append(parts, "", style.Width(bodyWidth).Render(m.selection.notice.msg))
+	}
+
+	pinAction := "P Pin"
+	if m.isPinned(m.selection.msg) {
+		pinAction = "P Unpin"
+	}
+	navigation := []string{"↑/↓ Scroll"}
+	if m.selection.jsonTree != nil {
+		navigation = []string{"↑/↓ Move", "Space Fold", "-/+ Fold All", "PgUp/PgDn Scroll"}
+	}
+	hints := append(navigation, "C Copy Payload", "Y Copy JSON", "F Filter to Process", pinAction, "W Forward to Webhook", "Esc Close")
+	parts = append(parts, "", styles.OnboardingHintStyle.Width(bodyWidth).Render(strings.Join(hints, "  •  ")))
+
+	return renderFramedPanel("Message Details", panelWidth, panelTypeInfo, lipgloss.JoinVertical(lipgloss.Left, parts...))
+}
@@ -0,0 +1,224 @@
+package ui
+
+import (
+	"OmniView/internal/adapter/ui/styles"
+	"OmniView/internal/core/domain"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// newTestDetailModel returns a ready main-screen model with count messages
+// alternating between the ORDER_API and BILLING_RUN processes.
+func newTestDetailModel(t *testing.T, count int) *Model {
+	t.Helper()
+
+	m := newTestMainModel(t, 140, 30)
+	m.initViewport()
+	for i := 0; i < count; i++ {
+		process := "ORDER_API"
+		if i%2 == 1 {
+			process = "BILLING_RUN"
+		}
+		msg, err := domain.NewQueueMessage(fmt.Sprintf("id-%d", i), process, domain.LogLevelInfo, fmt.Sprintf("payload %d", i), time.Unix(1700000000+int64(i), 0))
+		if err != nil {
+			t.Fatalf("NewQueueMessage: %v", err)
+		}
+		m.Update(queueMessageMsg{message: msg})
+	}
+	return m
+}
+
+// selectedTimestamp renders msg's timestamp column the way the row cursor marks it.
+func selectedTimestamp(msg *domain.QueueMessage) string {
+	return styles.SelectedRowStyle.Width(colTimestampWidth).Render(msg.Timestamp().Format("2006-01-02 15:04:05"))
+}
+
+func TestSelection_CursorMovesOverFilteredMessages(t *testing.T) {
+	t.Parallel()
+
+	m := newTestDetailModel(t, 6)
+	m.Update(makeKeyPress(tea.KeyEnter))
+
+	if !m.selection.active || m.selection.msg.MessageID() != "id-5" {
+		t.Fatalf("expected Enter to show the cursor on the last visible message, got %+v", m.selection.msg)
+	}
+	if m.main.autoScroll {
+		t.Fatal("expected selecting a row to pause auto-scroll")
+	}
+	if !strings.Contains(m.main.renderedLines[5], selectedTimestamp(m.main.messages[5])) {
+		t.Fatal("expected the selected row to be marked")
+	}
+
+	m.Update(makeCharPress("k"))
+	m.Update(makeCharPress("k"))
+	if got := m.selection.msg.MessageID(); got != "id-3" {
+		t.Fatalf("after two k presses selected %q, want id-3", got)
+	}
+	if strings.Contains(m.main.renderedLines[5], selectedTimestamp(m.main.messages[5])) {
+		t.Fatal("expected the previous row to lose the cursor")
+	}
+
+	// Hiding INFO removes every row; the cursor survives and returns with them.
+	m.Update(makeCharPress("2"))
+	if m.selection.index != -1 {
+		t.Fatalf("expected the hidden selection to have no rendered index, got %d", m.selection.index)
+	}
+	m.Update(makeCharPress("2"))
+	if m.selection.index != 3 {
+		t.Fatalf("expected the selection to be re-indexed after the rebuild, got %d", m.selection.index)
+	}
+
+	m.Update(makeKeyPress(tea.KeyEscape))
+	if m.selection.active {
+		t.Fatal("expected Esc to hide the row cursor")
+	}
+}
+
+func TestSelection_JKScrollWithoutTheCursor(t *testing.T) {
+	t.Parallel()
+
+	m := newTestMainModel(t, 140, 12)
+	m.initViewport()
+	for i := range 40 {
+		msg, err := domain.NewQueueMessage(fmt.Sprintf("id-%d", i), "ORDER_API", domain.LogLevelInfo, fmt.Sprintf("payload %d", i), time.Unix(1700000000+int64(i), 0))
+		if err != nil {
+			t.Fatalf("NewQueueMessage: %v", err)
+		}
+		m.Update(queueMessageMsg{message: msg})
+	}
+	m.main.viewport.GotoTop()
+
+	m.Update(makeCharPress("j"))
+	if m.selection.active {
+		t.Fatal("expected j not to show the row cursor")
+	}
+	if got := m.main.viewport.YOffset(); got != 1 {
+		t.Fatalf("viewport offset after j = %d, want 1", got)
+	}
+	m.Update(makeCharPress("k"))
+	if got := m.main.viewport.YOffset(); got != 0 {
+		t.Fatalf("viewport offset after k = %d, want 0", got)
+	}
+}
+
+func TestMessageDetail_ShowsAllFieldsAndUntruncatedPayload(t *testing.T) {
+	t.Parallel()
+
+	m := newTestMainModel(t, 140, 40)
+	m.initViewport()
+	payload := strings.Repeat("segment ", 60) + "END_OF_PAYLOAD"
+	msg, err := domain.NewQueueMessage("msg-42", "ORDER_API", domain.LogLevelError, payload, time.Unix(1700000000, 0), true)
+	if err != nil {
+		t.Fatalf("NewQueueMessage: %v", err)
+	}
+	m.Update(queueMessageMsg{message: msg})
+
+	m.Update(makeKeyPress(tea.KeyEnter))
+	m.Update(makeKeyPress(tea.KeyEnter))
+	if !m.selection.detail {
+		t.Fatal("expected Enter to open the detail pane")
+	}
+
+	body := m.messageDetailBody(msg, 80)
+	for _, want := range []string{"msg-42", "ERROR", "ORDER_API", "Global", msg.Timestamp().Format(time.RFC3339Nano), "yes", "END_OF_PAYLOAD"} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("detail body is missing %q", want)
+		}
+	}
+
+	_, cmd := m.Update(makeCharPress("c"))
+	if cmd == nil || !strings.Contains(m.viewMessageDetail(), "Payload copied") {
+		t.Fatal("expected C to copy the payload via OSC 52 and confirm it")
+	}
+
+	if _, cmd := m.Update(makeCharPress("q")); cmd != nil || m.selection.detail {
+		t.Fatal("expected q to close the detail pane without quitting")
+	}
+	if !m.selection.active {
+		t.Fatal("closing the pane should keep the row cursor")
+	}
+}
+
+func TestMessageDetail_FilterToProcess(t *testing.T) {
+	t.Parallel()
+
+	m := newTestDetailModel(t, 6)
+	m.Update(makeKeyPress(tea.KeyEnter))
+	m.Update(makeKeyPress(tea.KeyEnter))
+	m.Update(makeCharPress("f"))
+
+	if m.selection.detail {
+		t.Fatal("expected filtering to close the detail pane")
+	}
+	if got := m.traceFilter.active.String(); got != `process = "BILLING_RUN"` {
+		t.Fatalf("active filter = %q", got)
+	}
+	if len(m.main.renderedLines) != 3 {
+		t.Fatalf("expected 3 BILLING_RUN rows, got %d", len(m.main.renderedLines))
+	}
+	if m.selection.index != 2 {
+		t.Fatalf("expected the selected message to stay selected at index 2, got %d", m.selection.index)
+	}
+}
+
+func TestMessageDetail_PinShrinksViewportAndRendersStrip(t *testing.T) {
+	t.Parallel()
+
+	m := newTestDetailModel(t, 4)
+	height := m.main.viewport.Height()
+
+	m.Update(makeKeyPress(tea.KeyEnter))
+	m.Update(makeKeyPress(tea.KeyEnter))
+	m.Update(makeCharPress("p"))
+
+	if !m.isPinned(m.selection.msg) {
+		t.Fatal("expected P to pin the selected message")
+	}
+	if got := m.main.viewport.Height(); got != height-1 {
+		t.Fatalf("viewport height = %d, want %d", got, height-1)
+	}
+	m.Update(makeKeyPress(tea.KeyEscape))
+	if !strings.Contains(m.viewMain(), "payload 3") || !strings.Contains(m.viewMain(), "★") {
+		t.Fatal("expected the pinned strip above the feed")
+	}
+
+	// Pins survive clearing the feed.
+	m.Update(makeCharPress("c"))
+	if len(m.selection.pinned) != 1 {
+		t.Fatal("expected pins to survive clearing the feed")
+	}
+
+	m.togglePin(m.selection.pinned[0])
+	if got := m.main.viewport.Height(); got != height {
+		t.Fatalf("viewport height after unpinning = %d, want %d", got, height)
+	}
+}
+
+func TestMessageDetail_ForwardToWebhookReportsOutcome(t *testing.T) {
+	t.Parallel()
+
+	m := newTestDetailModel(t, 2)
+	m.Update(makeKeyPress(tea.KeyEnter))
+	m.Update(makeKeyPress(tea.KeyEnter))
+
+	if _, cmd := m.Update(makeCharPress("w")); cmd != nil {
+		t.Fatal("expected no forward command without a tracer service")
+	}
+	if !m.selection.notice.isError {
+		t.Fatal("expected an inline error when the dispatcher is unavailable")
+	}
+
+	m.Update(webhookForwardedMsg{message: m.selection.msg, err: fmt.Errorf("ForwardToWebhook: %w", domain.ErrWebhookConfigNotFound)})
+	if !strings.Contains(m.viewMessageDetail(), "webhook config not found") {
+		t.Fatal("expected the forward error in the detail pane")
+	}
+
+	m.Update(webhookForwardedMsg{message: m.selection.msg})
+	if m.selection.notice.isError || !strings.Contains(m.viewMessageDetail(), "Queued for delivery") {
+		t.Fatal("expected a confirmation once the message is queued")
+	}
+}
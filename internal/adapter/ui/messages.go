@@ -1,8 +1,10 @@
 package ui
 
 import (
+	"OmniView/internal/adapter/export"
 	"OmniView/internal/core/domain"
 	"OmniView/internal/updater"
+	"encoding/json"
 )
 
 // ==========================================
@@ -65,6 +67,49 @@ type queueMessageMsg struct {
 	message *domain.QueueMessage
 }
 
+// healthTickMsg refreshes the connection health snapshot shown in the header.
+type healthTickMsg struct{}
+
+// exportCompletedMsg is returned after writing the visible messages to a file.
+type exportCompletedMsg struct {
+	path   string
+	format export.Format
+	count  int
+	err    error
+}
+
+// webhookForwardedMsg is returned after manually forwarding a message to the webhook.
+type webhookForwardedMsg struct {
+	message *domain.QueueMessage
+	err     error
+}
+
+// ==========================================
+// History Screen messages
+// ==========================================
+
+// historySessionsLoadedMsg is returned after listing stored trace sessions.
+type historySessionsLoadedMsg struct {
+	sessions []domain.TraceSession
+	err      error
+}
+
+// historyPageLoadedMsg is returned after reading one page of a stored session.
+type historyPageLoadedMsg struct {
+	sessionID string
+	offset    int
+	after     string // Position the page was read after
+	next      string // Position the following page starts after
+	messages  []*domain.QueueMessage
+	err       error
+}
+
+// historyRetentionSavedMsg is returned after updating the history retention limits.
+type historyRetentionSavedMsg struct {
+	retention domain.HistoryRetention
+	err       error
+}
+
 // ==========================================
 // Onboarding Screen messages
 // ==========================================
@@ -116,11 +161,28 @@ type dropSubscriberProcedureResultMsg struct {
 	err error
 }
 
-// webhookConfigSavedMsg is returned after attempting to save or clear the webhook configuration.
-type webhookConfigSavedMsg struct {
-	config  *domain.WebhookConfig
-	deleted bool
-	err     error
+// webhooksChangedMsg is returned after a change to the webhooks, with the
+// stored webhooks and the ID of the default one.
+type webhooksChangedMsg struct {
+	webhooks  []domain.WebhookConfig
+	defaultID string
+	err       error
+}
+
+// deadLettersChangedMsg is returned after webhook dead letters were replayed
+// or discarded, with the entries still stored.
+type deadLettersChangedMsg struct {
+	deliveries []domain.WebhookDelivery
+	replayed   int
+	err        error
+}
+
+// outputsChangedMsg is returned after a change to the output sinks or routing
+// rules, with the stored entries after the sink router was reloaded.
+type outputsChangedMsg struct {
+	sinks []domain.SinkConfig
+	rules []domain.RoutingRule
+	err   error
 }
 
 // ==========================================
@@ -150,3 +212,21 @@ type updateCompleteMsg struct{}
 type updateErrorMsg struct {
 	err error
 }
+
+// ==========================================
+// Control API messages
+// ==========================================
+
+// controlRequestMsg carries a control socket call onto the UI goroutine.
+// Exactly one controlReply is sent on reply, which is buffered.
+type controlRequestMsg struct {
+	method string
+	params json.RawMessage
+	reply  chan controlReply
+}
+
+// controlReply is the outcome of a controlRequestMsg.
+type controlReply struct {
+	result any
+	err    error
+}
@@ -9,6 +9,7 @@ import (
 	"OmniView/internal/core/domain"
 	"OmniView/internal/core/ports"
 	"OmniView/internal/service/permissions"
+	"OmniView/internal/service/sinks"
 	"OmniView/internal/service/subscribers"
 	"OmniView/internal/service/tracer"
 	updaterSvc "OmniView/internal/service/updater"
@@ -33,6 +34,7 @@ const (
 	screenLoading    = "loading"
 	screenMain       = "main"
 	screenOnboarding = "onboarding"
+	screenHistory    = "history"
 
 	// Panel height calculation: subtract border/padding and spacing from content height
 	panelHeightCompensation = 3
@@ -69,12 +71,15 @@ type loadingState struct {
 }
 
 type mainState struct {
-	messages      []*domain.QueueMessage // Log messages to display (bounded ring buffer, max 10000)
-	renderedLines []string               // Pre-rendered lines per filtered message at cachedWidthKey
-	viewport      viewport.Model         // Scrollable viewport for messages
-	autoScroll    bool                   // Whether to auto-scroll to the latest message
-	ready         bool                   // Whether the main screen is ready to display messages
-	totalRawBytes int                    // Sum of payload bytes across messages (drives maxRawBytes eviction)
+	messages      []*domain.QueueMessage  // Log messages to display (bounded ring buffer, max 10000)
+	renderedLines []string                // Pre-rendered lines per filtered message at cachedWidthKey
+	viewport      viewport.Model          // Scrollable viewport for messages
+	autoScroll    bool                    // Whether to auto-scroll to the latest message
+	ready         bool                    // Whether the main screen is ready to display messages
+	totalRawBytes int                     // Sum of payload bytes across messages (drives maxRawBytes eviction)
+	levelCounts   map[domain.LogLevel]int // Buffered messages per level (drives hidden-message counts)
+	expanded      bool                    // Whether JSON, XML and SQL payloads are pretty-printed in the feed
+	spans         *domain.SpanTracker     // Trace_Begin/Trace_End spans paired from the buffered messages
 
 	// Cached column widths for trace layout optimization
 	// Avoids O(n) full scan of messages on each new message
@@ -127,7 +132,18 @@ type Model struct {
 	onboarding      onboardingState
 	dbSettings      databaseSettingsState
 	webhookSettings webhookSettingsState
+	outputSettings  outputSettingsState
+	exportDialog    exportDialogState
+	search          searchState
+	traceFilter     traceFilterState
+	levelFilter     levelFilterState
+	attrColumns     attributeColumnsState
+	timeline        spanTimelineState
+	health          connectionHealthState
+	pause           pauseState
+	selection       selectionState
 	update          updateState
+	history         historyState
 
 	// Cancellable contexts for all background operations
 	ctx               context.Context
@@ -141,6 +157,9 @@ type Model struct {
 
 	// Application Services (injected via NewModel)
 	dbSettingsRepo    ports.DatabaseSettingsRepository
+	historyRepo       ports.TraceHistoryRepository
+	sinkRouter        *sinks.Router
+	deadLetters       ports.WebhookDeadLetterRepository
 	dbAdapter         ports.DatabaseRepository
 	permissionService *permissions.PermissionService
 	tracerService     *tracer.TracerService
@@ -171,6 +190,9 @@ type ModelOpts struct {
 	BoltAdapter        *boltdb.BoltAdapter
 	DBFactory          DatabaseAdapterFactory
 	DBSettingsRepo     ports.DatabaseSettingsRepository
+	HistoryRepo        ports.TraceHistoryRepository      // Optional — disables trace history when nil
+	SinkRouter         *sinks.Router                     // Optional — routes delivered messages to output sinks and webhooks; disables the Outputs screen when nil
+	DeadLetters        ports.WebhookDeadLetterRepository // Optional — webhook deliveries that could not be sent; disables the Dead Letters screen when nil
 	DBAdapter          ports.DatabaseRepository
 	PermissionService  *permissions.PermissionService
 	TracerService      *tracer.TracerService
@@ -232,6 +254,9 @@ func NewModel(opts ModelOpts) (*Model, error) {
 		boltAdapter:        opts.BoltAdapter,
 		dbFactory:          opts.DBFactory,
 		dbSettingsRepo:     opts.DBSettingsRepo,
+		historyRepo:        opts.HistoryRepo,
+		sinkRouter:         opts.SinkRouter,
+		deadLetters:        opts.DeadLetters,
 		app:                opts.App,
 		dbAdapter:          opts.DBAdapter,
 		permissionService:  opts.PermissionService,
@@ -269,13 +294,17 @@ func (m *Model) resetStartupUpdateState() {
 }
 
 func (m *Model) cycleBroadcastMode() error {
-	nextMode := m.broadcastMode.Next()
+	return m.setBroadcastMode(m.broadcastMode.Next())
+}
+
+// setBroadcastMode persists mode and makes it the active mode.
+func (m *Model) setBroadcastMode(mode domain.BroadcastMode) error {
 	if m.boltAdapter != nil {
-		if err := m.boltAdapter.SetBroadcastMode(nextMode); err != nil {
+		if err := m.boltAdapter.SetBroadcastMode(mode); err != nil {
 			return err
 		}
 	}
-	m.broadcastMode = nextMode
+	m.broadcastMode = mode
 	return nil
 }
 
@@ -291,33 +320,41 @@ func (m *Model) loadBroadcastMode() error {
 	return nil
 }
 
+// filterMessages returns the messages visible under the current broadcast mode
+// trace filter expression and level visibility settings.
 func (m *Model) filterMessages(msgs []*domain.QueueMessage) []*domain.QueueMessage {
-	if m.broadcastMode == domain.BroadcastModeGlobal {
+	if m.broadcastMode == domain.BroadcastModeGlobal && m.traceFilter.active == nil && !m.levelFilter.active() {
 		return msgs
 	}
 	filtered := make([]*domain.QueueMessage, 0, len(msgs))
 	for _, msg := range msgs {
-		switch m.broadcastMode {
-		case domain.BroadcastModeSubscriber:
-			if !msg.IsGlobalMessage() {
-				filtered = append(filtered, msg)
-			}
-		case domain.BroadcastModeBroadcast:
-			if msg.IsGlobalMessage() {
-				filtered = append(filtered, msg)
-			}
+		if m.messageVisible(msg) {
+			filtered = append(filtered, msg)
 		}
 	}
 	return filtered
 }
 
+// messageVisible reports whether msg passes the broadcast mode, the level
+// visibility settings and the active trace filter.
+func (m *Model) messageVisible(msg *domain.QueueMessage) bool {
+	if !m.broadcastMode.Shows(msg) {
+		return false
+	}
+	return m.levelFilter.shows(msg.LogLevel()) && m.traceFilter.active.Match(msg)
+}
+
 func (m *Model) enterMainScreen() tea.Cmd {
 	m.screen = screenMain
 	if err := m.loadBroadcastMode(); err != nil {
 		logger.Warn("failed to load broadcast mode", "error", err)
 	}
+	m.loadTraceFilter()
+	m.loadAttributeColumns()
+	m.pause = pauseState{}
+	m.selection = selectionState{index: -1}
 	m.initViewport()
-	return waitForEventCmd(m.eventStreamCtx, m.eventChannel)
+	return tea.Batch(waitForEventCmd(m.eventStreamCtx, m.eventChannel), m.startHealthTicker())
 }
 
 func (m *Model) isStartupGateActive() bool {
@@ -379,6 +416,12 @@ func (m *Model) initializeServices() error {
 			return fmt.Errorf("initializeServices: failed to create tracer service: %w", err)
 		}
 	}
+	if m.historyRepo != nil {
+		m.tracerService.SetHistoryRepository(m.historyRepo, m.appConfig.DatabaseID())
+	}
+	if m.sinkRouter != nil {
+		m.tracerService.SetMessagePublisher(m.sinkRouter)
+	}
 	if m.subscriberService == nil {
 		subscriberRepo := boltdb.NewSubscriberRepository(m.boltAdapter)
 		procGen, err := subscribers.NewProcedureGenerator(m.dbAdapter)
@@ -426,8 +469,8 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		case "q":
 			// Only quit from screens that don't need 'q' for navigation.
-			// Do NOT quit if an overlay (Help, DB, Webhook) is visible.
-			if !m.showHelp && ((m.screen == screenMain && !m.dbSettings.visible && !m.webhookSettings.visible) || m.screen == screenWelcome || (m.screen == screenLoading && !m.dbSettings.visible)) {
+			// Do NOT quit if an overlay (Help, DB, Webhook, Export, Levels, Details) or the search/filter prompt is open.
+			if !m.showHelp && ((m.screen == screenMain && !m.dbSettings.visible && !m.webhookSettings.visible && !m.outputSettings.visible && !m.exportDialog.visible && !m.search.prompt && !m.traceFilter.prompt && !m.levelFilter.visible && !m.attrColumns.visible && !m.timeline.visible && !m.health.visible && !m.selection.detail) || m.screen == screenWelcome || (m.screen == screenLoading && !m.dbSettings.visible)) {
 				if m.tracerService != nil {
 					m.tracerService.StopConnectionListener()
 				}
@@ -436,6 +479,16 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+	case controlRequestMsg:
+		// Control socket calls are answered on every screen; each method
+		// checks whether it can run on the current one.
+		return m, m.handleControlRequest(msg)
+
+	case healthTickMsg:
+		// Keeps running on every screen once the main screen was entered, so
+		// the header is current when returning from history or a reconnect.
+		return m, m.handleHealthTick()
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
@@ -443,10 +496,16 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Resize main viewport on Main Screen (after first init).
 		if m.screen == screenMain && m.main.ready {
 			m.resizeMainViewport()
+			m.resizeMessageDetail()
+			m.resizeSpanTimeline()
+		}
+		if m.screen == screenHistory {
+			m.resizeHistoryViewport()
 		}
 
 		m.resizeDatabaseSettings(msg.Width, msg.Height)
 		m.resizeWebhookSettings(msg.Width, msg.Height)
+		m.resizeOutputSettings(msg.Width, msg.Height)
 
 		// Resize AddDatabaseForm if on Onboarding Screen
 		if m.screen == screenOnboarding {
@@ -476,6 +535,8 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.updateMain(msg)
 	case screenOnboarding:
 		return m.updateOnboarding(msg)
+	case screenHistory:
+		return m.updateHistory(msg)
 	}
 
 	return m, nil
@@ -510,12 +571,28 @@ func (m *Model) View() tea.View {
 				}
 			} else if m.webhookSettings.visible {
 				content = renderCenteredOverlay(content, m.viewWebhookSettings(), m.width, m.height)
+			} else if m.outputSettings.visible {
+				content = renderCenteredOverlay(content, m.viewOutputSettings(), m.width, m.height)
+			} else if m.exportDialog.visible {
+				content = renderCenteredOverlay(content, m.viewExportDialog(), m.width, m.height)
+			} else if m.levelFilter.visible {
+				content = renderCenteredOverlay(content, m.viewLevelFilterOverlay(), m.width, m.height)
+			} else if m.attrColumns.visible {
+				content = renderCenteredOverlay(content, m.viewAttributeColumnsOverlay(), m.width, m.height)
+			} else if m.timeline.visible {
+				content = renderCenteredOverlay(content, m.viewSpanTimeline(), m.width, m.height)
+			} else if m.health.visible {
+				content = renderCenteredOverlay(content, m.viewConnectionHealthOverlay(), m.width, m.height)
+			} else if m.selection.detail {
+				content = renderCenteredOverlay(content, m.viewMessageDetail(), m.width, m.height)
 			} else if m.showHelp {
 				content = renderCenteredOverlay(content, m.renderHelpOverlay(), m.width, m.height)
 			}
 		}
 	case screenOnboarding:
 		content = m.viewOnboarding()
+	case screenHistory:
+		content = m.viewHistory()
 	}
 
 	if m.width > 0 && m.height > 0 {
@@ -0,0 +1,899 @@
+package ui
+
+import (
+	"OmniView/internal/adapter/logger"
+	"OmniView/internal/adapter/ui/styles"
+	"OmniView/internal/core/domain"
+	"OmniView/internal/core/ports"
+	"OmniView/internal/service/sinks"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+)
+
+// ==========================================
+// Outputs & Routing Settings Sub-State
+// ==========================================
+// Lists the output sinks and routing rules stored in BoltDB and edits them
+// one at a time. Every change is saved straight away and the sink router
+// reloaded, so the list always shows what is running.
+
+const (
+	outputsViewList = iota
+	outputsViewSinkForm
+	outputsViewRuleForm
+)
+
+// Sink form fields. An otlp sink is edited with the OTLP fields instead of
+// the generic settings.
+const (
+	sinkFieldID = iota
+	sinkFieldType
+	sinkFieldSettings
+	sinkFieldOTLPEndpoint
+	sinkFieldOTLPHeaders
+	sinkFieldOTLPBatchSize
+	sinkFieldOTLPFlushInterval
+	sinkFieldOTLPRetryAttempts
+	sinkFieldEnabled
+)
+
+// Routing rule form fields
+const (
+	ruleFieldID = iota
+	ruleFieldSinks
+	ruleFieldLevel
+	ruleFieldProcess
+	ruleFieldMode
+	ruleFieldPayload
+	ruleFieldFlagged
+	ruleFieldEnabled
+)
+
+// Buttons below the list, after one row per sink and rule
+const (
+	outputsBtnAddSink = iota
+	outputsBtnAddRule
+	outputsBtnClose
+	outputsButtonCount
+)
+
+// otlpHeaderSettingPrefix marks the otlp sink settings that become request
+// headers, as read by the otlp plug-in.
+const otlpHeaderSettingPrefix = "header."
+
+// anyLevel is the level choice for rules without a minimum level.
+const anyLevel = "Any"
+
+type outputFieldKind int
+
+const (
+	outputFieldText   outputFieldKind = iota // Free text
+	outputFieldChoice                        // One of choices, cycled with ←/→ or Space
+	outputFieldToggle                        // Checkbox
+)
+
+type outputField struct {
+	label       string
+	placeholder string
+	footer      string
+	kind        outputFieldKind
+	value       string // Typed text or the selected choice
+	choices     []string
+	checked     bool
+	masked      bool // Text shown as dots, for secrets
+	hidden      bool // Neither shown nor reachable, e.g. the settings of another sink type
+}
+
+// outputForm edits one sink or rule. Save and Cancel follow the fields.
+type outputForm struct {
+	fields   []outputField
+	cursor   int
+	original string // ID of the entry being edited; empty when adding one
+}
+
+func (f *outputForm) saveButton() int   { return len(f.fields) }
+func (f *outputForm) cancelButton() int { return len(f.fields) + 1 }
+
+// focusedField returns the field under the cursor, or nil on a button.
+func (f *outputForm) focusedField() *outputField {
+	if f.cursor < len(f.fields) {
+		return &f.fields[f.cursor]
+	}
+	return nil
+}
+
+// showSinkTypeFields shows the fields that configure the chosen sink type:
+// the OTLP fields for an otlp sink and the generic settings otherwise.
+func (f *outputForm) showSinkTypeFields() {
+	otlp := f.fields[sinkFieldType].value == domain.OTLPSinkType
+	f.fields[sinkFieldSettings].hidden = otlp
+	for i := sinkFieldOTLPEndpoint; i <= sinkFieldOTLPRetryAttempts; i++ {
+		f.fields[i].hidden = !otlp
+	}
+}
+
+// step moves the cursor by delta over the visible fields and the buttons.
+// With wrap set it continues from the other end instead of stopping.
+func (f *outputForm) step(delta int, wrap bool) {
+	n := f.cancelButton() + 1
+	for cursor := f.cursor + delta; ; cursor += delta {
+		if wrap {
+			cursor = (cursor + n) % n
+		} else if cursor < 0 || cursor >= n {
+			return
+		}
+		if cursor >= len(f.fields) || !f.fields[cursor].hidden {
+			f.cursor = cursor
+			return
+		}
+	}
+}
+
+type outputSettingsState struct {
+	visible bool
+	view    int
+	cursor  int // Row in the list view: sinks, then rules, then buttons
+	sinks   []domain.SinkConfig
+	rules   []domain.RoutingRule
+	form    outputForm
+	dialog  settingsDialog
+	layout  outputSettingsLayout
+
+	confirmDelete string // Row key of the seeded webhook sink or rule waiting for a second D
+}
+
+type outputSettingsLayout struct {
+	panelWidth int
+	innerWidth int
+	compact    bool
+	showHint   bool
+}
+
+// ==========================================
+// Helpers
+// ==========================================
+
+// openOutputSettings loads the stored sinks and rules and shows the panel.
+func (m *Model) openOutputSettings() {
+	m.outputSettings = outputSettingsState{visible: true}
+	m.resizeOutputSettings(m.width, m.height)
+	if m.sinkRouter == nil {
+		m.outputSettings.dialog.set("Output sinks are not available in this session.", true)
+		return
+	}
+
+	repo := m.sinkRouter.Repository()
+	sinkConfigs, err := repo.ListSinks(m.ctx)
+	if err == nil {
+		m.outputSettings.sinks = sinkConfigs
+		m.outputSettings.rules, err = repo.ListRoutingRules(m.ctx)
+	}
+	if err != nil {
+		logger.Error("failed to load output sinks", "error", err)
+		m.outputSettings.dialog.set(err.Error(), true)
+	}
+}
+
+// resizeOutputSettings resizes the outputs panel to the given dimensions.
+func (m *Model) resizeOutputSettings(width, height int) {
+	if !m.outputSettings.visible {
+		return
+	}
+
+	_, contentHeight := screenContentSize(width, height)
+	panelWidth := settingsPanelWidth(width)
+
+	m.outputSettings.layout = outputSettingsLayout{
+		panelWidth: panelWidth,
+		innerWidth: max(panelWidth-4, 1),
+		compact:    contentHeight <= 34,
+		showHint:   contentHeight >= 22,
+	}
+}
+
+// closeOutputSettings closes the outputs overlay and resets the sub-state.
+func (m *Model) closeOutputSettings() {
+	m.outputSettings = outputSettingsState{}
+}
+
+// outputRowCount returns the number of selectable rows in the list view.
+func (s *outputSettingsState) outputRowCount() int {
+	return len(s.sinks) + len(s.rules) + outputsButtonCount
+}
+
+// sinkTypes returns the registered sink types for the type choice.
+func (m *Model) sinkTypes() []string {
+	if m.sinkRouter == nil {
+		return nil
+	}
+	plugins := m.sinkRouter.Registry().Plugins()
+	types := make([]string, 0, len(plugins))
+	for _, plugin := range plugins {
+		types = append(types, plugin.Type)
+	}
+	return types
+}
+
+// sinkSettingsExample returns the example settings for a sink type.
+func (m *Model) sinkSettingsExample(sinkType string) string {
+	if m.sinkRouter == nil {
+		return ""
+	}
+	for _, plugin := range m.sinkRouter.Registry().Plugins() {
+		if plugin.Type == sinkType {
+			return plugin.Example
+		}
+	}
+	return ""
+}
+
+// editSink shows the sink form, filled from cfg when editing.
+func (m *Model) editSink(cfg *domain.SinkConfig) {
+	types := m.sinkTypes()
+	form := outputForm{fields: []outputField{
+		sinkFieldID:       {label: "Sink ID", placeholder: "audit-log", footer: "Letters, digits, '.', '_' or '-'. Rules refer to the sink by this ID.", kind: outputFieldText},
+		sinkFieldType:     {label: "Type", kind: outputFieldChoice, choices: types},
+		sinkFieldSettings: {label: "Settings", kind: outputFieldText, footer: "Comma-separated key=value pairs, stored encrypted."},
+
+		sinkFieldOTLPEndpoint:      {label: "Collector Endpoint", placeholder: "http://localhost:4318", footer: "/v1/logs is added when the URL has no path.", kind: outputFieldText},
+		sinkFieldOTLPHeaders:       {label: "Headers", placeholder: "api-key=secret,X-Scope-OrgID=ops", footer: "Comma-separated name=value pairs, stored encrypted.", kind: outputFieldText},
+		sinkFieldOTLPBatchSize:     {label: "Batch Size", placeholder: strconv.Itoa(domain.DefaultOTLPBatchSize), footer: fmt.Sprintf("Records per request, up to %d.", domain.MaxOTLPBatchSize), kind: outputFieldText},
+		sinkFieldOTLPFlushInterval: {label: "Flush Interval", placeholder: domain.DefaultOTLPFlushInterval.String(), footer: "Longest a record waits for its batch to fill, e.g. 500ms or 10s.", kind: outputFieldText},
+		sinkFieldOTLPRetryAttempts: {label: "Retry Attempts", placeholder: strconv.Itoa(domain.DefaultOTLPRetryAttempts), footer: fmt.Sprintf("Tries per batch, the first included, up to %d. Network errors and throttling are retried with backoff.", domain.MaxOTLPRetryAttempts), kind: outputFieldText},
+
+		sinkFieldEnabled: {label: "Sink", kind: outputFieldToggle, value: "Write routed messages to this sink", checked: true},
+	}}
+	if len(types) > 0 {
+		form.fields[sinkFieldType].value = types[0]
+	}
+	if cfg != nil {
+		form.original = cfg.ID
+		form.fields[sinkFieldID].value = cfg.ID
+		form.fields[sinkFieldType].value = cfg.Type
+		form.fields[sinkFieldSettings].value = cfg.SettingsString()
+		form.fields[sinkFieldEnabled].checked = cfg.Enabled
+		if cfg.Type == domain.OTLPSinkType {
+			headers := make(map[string]string)
+			for key, value := range cfg.Settings {
+				if name, ok := strings.CutPrefix(key, otlpHeaderSettingPrefix); ok {
+					headers[name] = value
+				}
+			}
+			form.fields[sinkFieldOTLPEndpoint].value = cfg.Setting("endpoint")
+			form.fields[sinkFieldOTLPHeaders].value = (&domain.OTLPConfig{Headers: headers}).HeadersString()
+			form.fields[sinkFieldOTLPBatchSize].value = cfg.Setting("batch_size")
+			form.fields[sinkFieldOTLPFlushInterval].value = cfg.Setting("flush_interval")
+			form.fields[sinkFieldOTLPRetryAttempts].value = cfg.Setting("retry_attempts")
+		}
+	}
+	form.showSinkTypeFields()
+	m.outputSettings.form = form
+	m.outputSettings.view = outputsViewSinkForm
+	m.outputSettings.dialog.clear()
+}
+
+// editRule shows the rule form, filled from rule when editing.
+func (m *Model) editRule(rule *domain.RoutingRule) {
+	levels := []string{anyLevel}
+	for _, level := range domain.LogLevels() {
+		levels = append(levels, string(level))
+	}
+	modes := []string{
+		domain.BroadcastModeGlobal.String(),
+		domain.BroadcastModeSubscriber.String(),
+		domain.BroadcastModeBroadcast.String(),
+	}
+	form := outputForm{fields: []outputField{
+		ruleFieldID:      {label: "Rule ID", placeholder: "errors-to-syslog", kind: outputFieldText},
+		ruleFieldSinks:   {label: "Sinks", placeholder: "audit-log,siem", footer: "Comma-separated sink IDs.", kind: outputFieldText},
+		ruleFieldLevel:   {label: "Minimum Level", kind: outputFieldChoice, choices: levels, value: anyLevel},
+		ruleFieldProcess: {label: "Process Pattern", placeholder: "^ORDER_", footer: "Regular expression on the process name. Empty matches every process.", kind: outputFieldText},
+		ruleFieldMode:    {label: "Mode", kind: outputFieldChoice, choices: modes, value: modes[0]},
+		ruleFieldPayload: {label: "Payload Pattern", placeholder: "(?i)timeout", footer: "Regular expression on the payload. Empty matches every payload.", kind: outputFieldText},
+		ruleFieldFlagged: {label: "Webhook Flag", kind: outputFieldToggle, value: "Only messages sent with Trace_Message_To_Webhook"},
+		ruleFieldEnabled: {label: "Rule", kind: outputFieldToggle, value: "Route the messages this rule matches", checked: true},
+	}}
+	if rule != nil {
+		form.original = rule.ID
+		form.fields[ruleFieldID].value = rule.ID
+		form.fields[ruleFieldSinks].value = strings.Join(rule.Sinks, ",")
+		if rule.MinLevel != "" {
+			form.fields[ruleFieldLevel].value = string(rule.MinLevel)
+		}
+		form.fields[ruleFieldProcess].value = rule.Process
+		form.fields[ruleFieldMode].value = rule.Mode.String()
+		form.fields[ruleFieldPayload].value = rule.Payload
+		form.fields[ruleFieldFlagged].checked = rule.Flagged
+		form.fields[ruleFieldEnabled].checked = rule.Enabled
+	}
+	m.outputSettings.form = form
+	m.outputSettings.view = outputsViewRuleForm
+	m.outputSettings.dialog.clear()
+}
+
+// cycleChoice moves a choice field by delta, wrapping around.
+func cycleChoice(field *outputField, delta int) {
+	if len(field.choices) == 0 {
+		return
+	}
+	i := slices.Index(field.choices, field.value)
+	field.value = field.choices[((i+delta)%len(field.choices)+len(field.choices))%len(field.choices)]
+}
+
+// ==========================================
+// Update
+// ==========================================
+
+// updateOutputSettings handles input for the outputs panel.
+func (m *Model) updateOutputSettings(msg tea.Msg) (*Model, tea.Cmd) {
+	state := &m.outputSettings
+
+	switch msg := msg.(type) {
+	case outputsChangedMsg:
+		if msg.err != nil {
+			state.dialog.set(msg.err.Error(), true)
+			return m, nil
+		}
+		state.sinks, state.rules = msg.sinks, msg.rules
+		state.view = outputsViewList
+		state.cursor = min(state.cursor, state.outputRowCount()-1)
+		state.dialog.clear()
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.resizeOutputSettings(msg.Width, msg.Height)
+		return m, nil
+
+	case tea.PasteMsg:
+		if state.view != outputsViewList {
+			if field := state.form.focusedField(); field != nil && field.kind == outputFieldText {
+				field.value += sanitizePasteInput(msg.Content)
+				state.dialog.clear()
+			}
+		}
+		return m, nil
+
+	case tea.KeyPressMsg:
+		if msg.String() == "ctrl+c" {
+			m.cancel()
+			return m, tea.Quit
+		}
+		if state.view == outputsViewList {
+			return m.updateOutputList(msg)
+		}
+		return m.updateOutputForm(msg)
+	}
+
+	return m, nil
+}
+
+// updateOutputList handles keys on the sink and rule list.
+func (m *Model) updateOutputList(msg tea.KeyPressMsg) (*Model, tea.Cmd) {
+	state := &m.outputSettings
+	rows := state.outputRowCount()
+	sinkIndex := state.cursor
+	ruleIndex := state.cursor - len(state.sinks)
+	button := state.cursor - len(state.sinks) - len(state.rules)
+	confirmDelete := state.confirmDelete
+	state.confirmDelete = ""
+
+	switch msg.String() {
+	case "esc", "q":
+		if msg.String() == "esc" && state.dialog.visible {
+			state.dialog.clear()
+			return m, nil
+		}
+		m.closeOutputSettings()
+		return m, nil
+	case "up", "shift+tab":
+		if state.cursor > 0 {
+			state.cursor--
+		}
+	case "down":
+		if state.cursor < rows-1 {
+			state.cursor++
+		}
+	case "tab":
+		state.cursor = (state.cursor + 1) % rows
+	case "enter":
+		switch {
+		case sinkIndex < len(state.sinks):
+			m.editSink(&state.sinks[sinkIndex])
+		case ruleIndex < len(state.rules):
+			m.editRule(&state.rules[ruleIndex])
+		case button == outputsBtnAddSink:
+			m.editSink(nil)
+		case button == outputsBtnAddRule:
+			m.editRule(nil)
+		case button == outputsBtnClose:
+			m.closeOutputSettings()
+		}
+		return m, nil
+	case "space":
+		switch {
+		case sinkIndex < len(state.sinks):
+			cfg := state.sinks[sinkIndex]
+			cfg.Enabled = !cfg.Enabled
+			return m, m.changeOutputsCmd(func(ctx context.Context, repo ports.SinkRepository) error {
+				return repo.SaveSink(ctx, cfg)
+			})
+		case ruleIndex < len(state.rules):
+			rule := state.rules[ruleIndex]
+			rule.Enabled = !rule.Enabled
+			return m, m.changeOutputsCmd(func(ctx context.Context, repo ports.SinkRepository) error {
+				return repo.SaveRoutingRule(ctx, rule)
+			})
+		}
+	case "delete", "d":
+		switch {
+		case sinkIndex < len(state.sinks):
+			id := state.sinks[sinkIndex].ID
+			if m.confirmWebhookRouteDelete("sink:"+id, id, confirmDelete) {
+				return m, nil
+			}
+			return m, m.changeOutputsCmd(func(ctx context.Context, repo ports.SinkRepository) error {
+				return repo.DeleteSink(ctx, id)
+			})
+		case ruleIndex < len(state.rules):
+			id := state.rules[ruleIndex].ID
+			if m.confirmWebhookRouteDelete("rule:"+id, id, confirmDelete) {
+				return m, nil
+			}
+			return m, m.changeOutputsCmd(func(ctx context.Context, repo ports.SinkRepository) error {
+				return repo.DeleteRoutingRule(ctx, id)
+			})
+		}
+	}
+	state.dialog.clear()
+	return m, nil
+}
+
+// confirmWebhookRouteDelete warns before the seeded webhook sink or rule is
+// deleted, as without them Trace_Message_To_Webhook messages reach no
+// webhook. It reports true while the second D is awaited.
+func (m *Model) confirmWebhookRouteDelete(key, id, pending string) bool {
+	if id != domain.WebhookRouteID || pending == key {
+		return false
+	}
+	state := &m.outputSettings
+	state.confirmDelete = key
+	state.dialog.set(fmt.Sprintf("Messages sent with Trace_Message_To_Webhook reach their webhooks through %q. Press D again to delete it, or Space to disable it instead.", id), true)
+	return true
+}
+
+// updateOutputForm handles keys on the sink or rule form.
+func (m *Model) updateOutputForm(msg tea.KeyPressMsg) (*Model, tea.Cmd) {
+	state := &m.outputSettings
+
+	if msg.String() == "esc" {
+		if state.dialog.visible {
+			state.dialog.clear()
+			return m, nil
+		}
+		state.view = outputsViewList
+		return m, nil
+	}
+
+	result := state.form.update(msg)
+	if state.view == outputsViewSinkForm {
+		state.form.showSinkTypeFields()
+	}
+	switch result {
+	case formKeySave:
+		if state.view == outputsViewSinkForm {
+			return m, m.saveSinkCmd()
+		}
+		return m, m.saveRuleCmd()
+	case formKeyCancel:
+		state.view = outputsViewList
+		state.dialog.clear()
+	case formKeyHandled:
+		state.dialog.clear()
+	}
+	return m, nil
+}
+
+// formKeyResult tells the owner of a form what a key press did.
+type formKeyResult int
+
+const (
+	formKeyIgnored formKeyResult = iota
+	formKeyHandled               // Moved the cursor or edited a field
+	formKeySave                  // Enter on Save
+	formKeyCancel                // Enter on Cancel
+)
+
+// update applies a key press to the form: moving between fields, typing,
+// cycling choices and toggling checkboxes. Esc is left to the caller.
+func (f *outputForm) update(msg tea.KeyPressMsg) formKeyResult {
+	field := f.focusedField()
+	onText := field != nil && field.kind == outputFieldText
+
+	switch msg.String() {
+	case "up", "shift+tab":
+		f.step(-1, false)
+		return formKeyHandled
+	case "down":
+		f.step(1, false)
+		return formKeyHandled
+	case "tab":
+		f.step(1, true)
+		return formKeyHandled
+	case "enter":
+		switch {
+		case f.cursor == f.saveButton():
+			return formKeySave
+		case f.cursor == f.cancelButton():
+			return formKeyCancel
+		case field.kind == outputFieldToggle:
+			field.checked = !field.checked
+		default:
+			f.step(1, false)
+		}
+		return formKeyHandled
+	case "space", "right", "left":
+		if field != nil && field.kind == outputFieldChoice {
+			delta := 1
+			if msg.String() == "left" {
+				delta = -1
+			}
+			cycleChoice(field, delta)
+			return formKeyHandled
+		}
+		if field != nil && field.kind == outputFieldToggle && msg.String() == "space" {
+			field.checked = !field.checked
+			return formKeyHandled
+		}
+	case "backspace":
+		if !onText {
+			return formKeyIgnored
+		}
+		if _, size := utf8.DecodeLastRuneInString(field.value); size > 0 {
+			field.value = field.value[:len(field.value)-size]
+		}
+		return formKeyHandled
+	case "ctrl+u":
+		if !onText {
+			return formKeyIgnored
+		}
+		field.value = ""
+		return formKeyHandled
+	}
+
+	if onText && len(msg.Text) > 0 && !msg.Mod.Contains(tea.ModCtrl) {
+		field.value += msg.Text
+		return formKeyHandled
+	}
+	return formKeyIgnored
+}
+
+// ==========================================
+// View
+// ==========================================
+
+// viewOutputSettings renders the outputs panel as a string.
+func (m *Model) viewOutputSettings() string {
+	layout := m.outputSettings.layout
+	if layout.panelWidth == 0 {
+		m.resizeOutputSettings(m.width, m.height)
+		layout = m.outputSettings.layout
+	}
+
+	var content, title string
+	switch m.outputSettings.view {
+	case outputsViewSinkForm:
+		content, title = m.viewOutputForm(m.sinkSettingsExample(m.outputSettings.form.fields[sinkFieldType].value)), "Output Sink"
+	case outputsViewRuleForm:
+		content, title = m.viewOutputForm(""), "Routing Rule"
+	default:
+		content, title = m.viewOutputList(), "Outputs & Routing"
+	}
+	return renderFramedPanel(title, layout.panelWidth, panelTypeInfo, content)
+}
+
+// viewOutputList renders the sinks with their status and the rules.
+func (m *Model) viewOutputList() string {
+	state := m.outputSettings
+	innerWidth := state.layout.innerWidth
+
+	statuses := make(map[string]sinks.SinkStatus)
+	if m.sinkRouter != nil {
+		for _, status := range m.sinkRouter.Status() {
+			statuses[status.Config.ID] = status
+		}
+	}
+
+	row := func(index int, enabled bool, text string) string {
+		marker := "  "
+		if index == state.cursor {
+			marker = formCursorStyle.Render("› ")
+		}
+		check := styles.SubtitleStyle.Render("[ ]")
+		if enabled {
+			check = lipgloss.NewStyle().Foreground(styles.SuccessColor).Render("[x]")
+		}
+		return truncateRendered(marker+check+" "+text, innerWidth)
+	}
+
+	sinkRows := make([]string, 0, len(state.sinks))
+	for i, cfg := range state.sinks {
+		text := styles.BodyTextStyle.Render(sanitizeLogString(cfg.ID)) + styles.SubtitleStyle.Render("  "+cfg.Type)
+		switch status, ok := statuses[cfg.ID]; {
+		case !cfg.Enabled:
+		case ok && status.Err != nil:
+			text += lipgloss.NewStyle().Foreground(styles.ErrorColor).Render("  " + sanitizeLogString(status.Err.Error()))
+		case ok && status.Running:
+			text += lipgloss.NewStyle().Foreground(styles.SuccessColor).Render(fmt.Sprintf("  running, %d queued", status.Queued))
+		}
+		sinkRows = append(sinkRows, row(i, cfg.Enabled, text))
+	}
+	if len(sinkRows) == 0 {
+		sinkRows = append(sinkRows, formPlaceholder.Render("No sinks yet."))
+	}
+
+	ruleRows := make([]string, 0, len(state.rules))
+	for i, rule := range state.rules {
+		text := styles.BodyTextStyle.Render(sanitizeLogString(rule.ID)) +
+			styles.SubtitleStyle.Render("  → "+sanitizeLogString(strings.Join(rule.Sinks, ", "))+"  "+describeRoutingRule(rule))
+		ruleRows = append(ruleRows, row(len(state.sinks)+i, rule.Enabled, text))
+	}
+	if len(ruleRows) == 0 {
+		ruleRows = append(ruleRows, formPlaceholder.Render("No rules yet. Sinks receive nothing until a rule names them."))
+	}
+
+	button := state.cursor - len(state.sinks) - len(state.rules)
+	parts := []string{
+		styles.SubtitleStyle.Width(innerWidth).Render("Send delivered trace messages to webhooks, files, syslog or OpenTelemetry collectors. Each message goes once to every sink named by an enabled rule it matches."),
+		"",
+		renderEmbeddedField(embeddedFieldOptions{Label: "Sinks", Value: strings.Join(sinkRows, "\n"), Width: innerWidth, Focused: state.cursor < len(state.sinks)}),
+		renderEmbeddedField(embeddedFieldOptions{Label: "Routing Rules", Value: strings.Join(ruleRows, "\n"), Width: innerWidth, Focused: button < 0 && state.cursor >= len(state.sinks)}),
+	}
+	if !state.layout.compact {
+		parts = append(parts, "")
+	}
+	parts = append(parts, lipgloss.PlaceHorizontal(innerWidth, lipgloss.Center, lipgloss.JoinHorizontal(lipgloss.Center,
+		renderActionButton("Add Sink", 0, button == outputsBtnAddSink, buttonVariantPrimary), "  ",
+		renderActionButton("Add Rule", 0, button == outputsBtnAddRule, buttonVariantPrimary), "  ",
+		renderActionButton("Close", 0, button == outputsBtnClose, buttonVariantPrimary),
+	)))
+	parts = append(parts, renderSettingsDialogLines(state.dialog, innerWidth)...)
+
+	if !state.dialog.visible && state.layout.showHint {
+		parts = append(parts, "", styles.OnboardingHintStyle.Width(innerWidth).Render("↑/↓ Navigate  •  Enter Edit  •  Space Toggle  •  D Delete  •  Esc Close"))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
+
+// describeRoutingRule summarizes a rule's conditions for the list.
+func describeRoutingRule(rule domain.RoutingRule) string {
+	var conditions []string
+	if rule.Flagged {
+		conditions = append(conditions, "flagged")
+	}
+	if rule.MinLevel != "" {
+		conditions = append(conditions, string(rule.MinLevel)+"+")
+	}
+	if rule.Mode != domain.BroadcastModeGlobal {
+		conditions = append(conditions, rule.Mode.String())
+	}
+	if rule.Process != "" {
+		conditions = append(conditions, "process~"+sanitizeLogString(rule.Process))
+	}
+	if rule.Payload != "" {
+		conditions = append(conditions, "payload~"+sanitizeLogString(rule.Payload))
+	}
+	if len(conditions) == 0 {
+		return "all messages"
+	}
+	return strings.Join(conditions, " ")
+}
+
+// viewOutputForm renders the sink or rule form. example, when set, is shown
+// under the settings field.
+func (m *Model) viewOutputForm(example string) string {
+	state := m.outputSettings
+	layout := state.layout
+	innerWidth := layout.innerWidth
+	form := state.form
+
+	parts := make([]string, 0, 2*len(form.fields)+4)
+	for i, field := range form.fields {
+		if field.hidden {
+			continue
+		}
+		placeholder, footer := field.placeholder, field.footer
+		if state.view == outputsViewSinkForm && i == sinkFieldSettings {
+			placeholder = example
+			if example != "" {
+				footer = "e.g. " + example
+			}
+		}
+		if !layout.compact {
+			parts = append(parts, "")
+		}
+		parts = append(parts, renderOutputField(field, form.cursor == i, placeholder, footer, innerWidth))
+	}
+
+	if !layout.compact {
+		parts = append(parts, "")
+	}
+	parts = append(parts, renderCenteredActionButtons(
+		innerWidth,
+		"Save",
+		form.cursor == form.saveButton(),
+		"Cancel",
+		form.cursor == form.cancelButton(),
+	))
+	parts = append(parts, renderSettingsDialogLines(state.dialog, innerWidth)...)
+
+	if !state.dialog.visible && layout.showHint {
+		parts = append(parts, "", styles.OnboardingHintStyle.Width(innerWidth).Render("↑/↓ Navigate  •  ←/→ Choose  •  Space Toggle  •  Enter Confirm  •  Esc Back"))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
+
+// renderOutputField renders one form field as an embedded field box.
+func renderOutputField(field outputField, focused bool, placeholder, footer string, width int) string {
+	var value string
+	switch field.kind {
+	case outputFieldToggle:
+		check := styles.SubtitleStyle.Render("[ ]")
+		if field.checked {
+			check = lipgloss.NewStyle().Foreground(styles.SuccessColor).Render("[x]")
+		}
+		value = check + " " + styles.BodyTextStyle.Render(field.value)
+	case outputFieldChoice:
+		value = formValueStyle.Render(field.value)
+		if focused {
+			value = formCursorStyle.Render("‹ ") + value + formCursorStyle.Render(" ›")
+		}
+	default:
+		value = formPlaceholder.Render(placeholder)
+		switch {
+		case field.value != "" && field.masked:
+			value = formValueStyle.Render(strings.Repeat("•", min(len(field.value), 30)))
+		case field.value != "":
+			value = formValueStyle.Render(field.value)
+		}
+		if focused {
+			value += formCursorStyle.Render("_")
+		}
+	}
+	return renderEmbeddedField(embeddedFieldOptions{
+		Label:      field.label,
+		Value:      value,
+		Width:      width,
+		Focused:    focused,
+		FooterText: footer,
+	})
+}
+
+// ==========================================
+// Async Commands
+// ==========================================
+
+// changeOutputsCmd returns an async command that applies change to the
+// repository, reloads the sink router and lists the result.
+func (m *Model) changeOutputsCmd(change func(context.Context, ports.SinkRepository) error) tea.Cmd {
+	ctx := m.ctx
+	router := m.sinkRouter
+
+	return func() tea.Msg {
+		if router == nil {
+			return outputsChangedMsg{err: fmt.Errorf("output sinks are not available in this session")}
+		}
+		repo := router.Repository()
+		if err := change(ctx, repo); err != nil {
+			return outputsChangedMsg{err: err}
+		}
+		if err := router.Reload(ctx); err != nil {
+			return outputsChangedMsg{err: err}
+		}
+		sinkConfigs, err := repo.ListSinks(ctx)
+		if err != nil {
+			return outputsChangedMsg{err: err}
+		}
+		rules, err := repo.ListRoutingRules(ctx)
+		if err != nil {
+			return outputsChangedMsg{err: err}
+		}
+		return outputsChangedMsg{sinks: sinkConfigs, rules: rules}
+	}
+}
+
+// saveSinkCmd validates the sink form with its plug-in and saves it,
+// removing the old entry when the ID was changed.
+func (m *Model) saveSinkCmd() tea.Cmd {
+	form := m.outputSettings.form
+	fields := form.fields
+	router := m.sinkRouter
+
+	return m.changeOutputsCmd(func(ctx context.Context, repo ports.SinkRepository) error {
+		settings, err := sinkFormSettings(fields)
+		if err != nil {
+			return err
+		}
+		cfg, err := domain.NewSinkConfig(fields[sinkFieldID].value, fields[sinkFieldType].value, settings, fields[sinkFieldEnabled].checked)
+		if err != nil {
+			return err
+		}
+		if err := router.Registry().Validate(*cfg); err != nil {
+			return err
+		}
+		if err := repo.SaveSink(ctx, *cfg); err != nil {
+			return fmt.Errorf("save sink: %w", err)
+		}
+		if form.original != "" && form.original != cfg.ID {
+			return repo.DeleteSink(ctx, form.original)
+		}
+		return nil
+	})
+}
+
+// sinkFormSettings returns the settings entered in the sink form: the OTLP
+// fields for an otlp sink and the generic settings otherwise.
+func sinkFormSettings(fields []outputField) (map[string]string, error) {
+	if fields[sinkFieldType].value != domain.OTLPSinkType {
+		return domain.ParseSinkSettings(fields[sinkFieldSettings].value)
+	}
+	headers, err := domain.ParseOTLPHeaders(fields[sinkFieldOTLPHeaders].value)
+	if err != nil {
+		return nil, err
+	}
+	settings := make(map[string]string, len(headers)+4)
+	for name, value := range headers {
+		settings[otlpHeaderSettingPrefix+name] = value
+	}
+	for key, field := range map[string]int{
+		"endpoint":       sinkFieldOTLPEndpoint,
+		"batch_size":     sinkFieldOTLPBatchSize,
+		"flush_interval": sinkFieldOTLPFlushInterval,
+		"retry_attempts": sinkFieldOTLPRetryAttempts,
+	} {
+		if value := strings.TrimSpace(fields[field].value); value != "" {
+			settings[key] = value
+		}
+	}
+	return settings, nil
+}
+
+// saveRuleCmd validates and saves the rule form, removing the old entry
+// when the ID was changed.
+func (m *Model) saveRuleCmd() tea.Cmd {
+	form := m.outputSettings.form
+	fields := form.fields
+
+	return m.changeOutputsCmd(func(ctx context.Context, repo ports.SinkRepository) error {
+		var level domain.LogLevel
+		if value := fields[ruleFieldLevel].value; value != anyLevel {
+			level = domain.LogLevel(value)
+		}
+		rule, err := domain.NewRoutingRule(
+			fields[ruleFieldID].value,
+			strings.Split(fields[ruleFieldSinks].value, ","),
+			level,
+			fields[ruleFieldProcess].value,
+			domain.NewBroadcastMode(fields[ruleFieldMode].value),
+			fields[ruleFieldPayload].value,
+			fields[ruleFieldEnabled].checked,
+		)
+		if err != nil {
+			return err
+		}
+		rule.Flagged = fields[ruleFieldFlagged].checked
+		if err := repo.SaveRoutingRule(ctx, *rule); err != nil {
+			return fmt.Errorf("save routing rule: %w", err)
+		}
+		if form.original != "" && form.original != rule.ID {
+			return repo.DeleteRoutingRule(ctx, form.original)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,254 @@
+package ui
+
+import (
+	"OmniView/internal/adapter/sink"
+	"OmniView/internal/adapter/storage/boltdb"
+	"OmniView/internal/core/domain"
+	"OmniView/internal/service/sinks"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// newTestModelForOutputSettings returns a model with a sink router backed by
+// its BoltDB file and the built-in plug-ins. The webhook route seeded with
+// the store is removed, so the lists start empty.
+func newTestModelForOutputSettings(t *testing.T) *Model {
+	t.Helper()
+
+	m := newTestModelForWebhookSettings(t)
+	repo := boltdb.NewSinkRepository(m.boltAdapter)
+	if err := repo.DeleteRoutingRule(m.ctx, domain.WebhookRouteID); err != nil {
+		t.Fatalf("DeleteRoutingRule: %v", err)
+	}
+	if err := repo.DeleteSink(m.ctx, domain.WebhookRouteID); err != nil {
+		t.Fatalf("DeleteSink: %v", err)
+	}
+	registry := sinks.NewRegistry()
+	sink.Register(registry, m.boltAdapter)
+	router, err := sinks.NewRouter(registry, repo)
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+	t.Cleanup(router.Close)
+	m.sinkRouter = router
+	return m
+}
+
+func typeOutputText(m *Model, text string) {
+	for _, r := range text {
+		m.updateOutputSettings(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+}
+
+func TestWebhookSettings_OpensOutputSettings(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModelForOutputSettings(t)
+	m.openWebhookSettings()
+	m.webhookSettings.cursor = webhookBtnOutputs
+	updated, _ := m.updateWebhookSettings(tea.KeyPressMsg{Code: tea.KeyEnter})
+
+	if updated.webhookSettings.visible || !updated.outputSettings.visible {
+		t.Fatal("expected the Outputs button to swap the webhook panel for the outputs panel")
+	}
+	if view := updated.viewOutputSettings(); !strings.Contains(view, "No sinks yet.") {
+		t.Fatalf("expected an empty sink list, got:\n%s", view)
+	}
+}
+
+func TestOutputSettings_AddSinkAndRuleStartsRouting(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModelForOutputSettings(t)
+	m.openOutputSettings()
+	path := filepath.Join(t.TempDir(), "trace.ndjson")
+
+	m.outputSettings.cursor = outputsBtnAddSink
+	m.updateOutputSettings(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if m.outputSettings.view != outputsViewSinkForm || m.outputSettings.form.fields[sinkFieldType].value != "file" {
+		t.Fatalf("expected the sink form with the first type selected, got view %d", m.outputSettings.view)
+	}
+	typeOutputText(m, "audit")
+	m.outputSettings.form.cursor = sinkFieldSettings
+	m.updateOutputSettings(tea.PasteMsg{Content: "path=" + path})
+
+	changed, ok := m.saveSinkCmd()().(outputsChangedMsg)
+	if !ok || changed.err != nil {
+		t.Fatalf("expected the sink to save, got %+v", changed)
+	}
+	m.updateOutputSettings(changed)
+	if m.outputSettings.view != outputsViewList || len(m.outputSettings.sinks) != 1 {
+		t.Fatalf("expected the list with one sink, got view %d and %d sinks", m.outputSettings.view, len(m.outputSettings.sinks))
+	}
+	if status := m.sinkRouter.Status(); len(status) != 1 || !status[0].Running {
+		t.Fatalf("expected the router to run the new sink, got %+v", status)
+	}
+
+	m.editRule(nil)
+	fields := m.outputSettings.form.fields
+	fields[ruleFieldID].value = "errors"
+	fields[ruleFieldSinks].value = "audit"
+	fields[ruleFieldPayload].value = "("
+	rejected := m.saveRuleCmd()().(outputsChangedMsg)
+	if !errors.Is(rejected.err, domain.ErrInvalidRoutingRule) {
+		t.Fatalf("expected an invalid payload pattern to be rejected, got %v", rejected.err)
+	}
+	m.updateOutputSettings(rejected)
+	if m.outputSettings.view != outputsViewRuleForm || !m.outputSettings.dialog.visible {
+		t.Fatal("expected the rule form to stay open with the error shown")
+	}
+
+	fields[ruleFieldPayload].value = ""
+	m.outputSettings.form.cursor = ruleFieldLevel
+	for range 4 {
+		m.updateOutputSettings(tea.KeyPressMsg{Code: tea.KeyRight})
+	}
+	if fields[ruleFieldLevel].value != string(domain.LogLevelError) {
+		t.Fatalf("expected → to cycle the level to ERROR, got %q", fields[ruleFieldLevel].value)
+	}
+	m.outputSettings.form.cursor = ruleFieldFlagged
+	m.updateOutputSettings(tea.KeyPressMsg{Code: tea.KeySpace, Text: " "})
+	changed = m.saveRuleCmd()().(outputsChangedMsg)
+	if changed.err != nil {
+		t.Fatalf("save rule: %v", changed.err)
+	}
+	m.updateOutputSettings(changed)
+	if len(m.outputSettings.rules) != 1 || m.outputSettings.rules[0].MinLevel != domain.LogLevelError || !m.outputSettings.rules[0].Flagged {
+		t.Fatalf("expected the saved flagged rule in the list, got %+v", m.outputSettings.rules)
+	}
+	if view := m.viewOutputSettings(); !strings.Contains(view, "flagged ERROR+") {
+		t.Fatalf("expected the rule summary in the list, got:\n%s", view)
+	}
+}
+
+func TestOutputSettings_ListTogglesAndDeletesEntries(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModelForOutputSettings(t)
+	repo := m.sinkRouter.Repository()
+	cfg, err := domain.NewSinkConfig("audit", "file", map[string]string{"path": filepath.Join(t.TempDir(), "a.log")}, true)
+	if err != nil {
+		t.Fatalf("NewSinkConfig: %v", err)
+	}
+	rule, err := domain.NewRoutingRule("all", []string{"audit"}, "", "", domain.BroadcastModeGlobal, "", true)
+	if err != nil {
+		t.Fatalf("NewRoutingRule: %v", err)
+	}
+	if err := repo.SaveSink(m.ctx, *cfg); err != nil {
+		t.Fatalf("SaveSink: %v", err)
+	}
+	if err := repo.SaveRoutingRule(m.ctx, *rule); err != nil {
+		t.Fatalf("SaveRoutingRule: %v", err)
+	}
+	m.openOutputSettings()
+
+	// Row 1 is the rule, after the one sink.
+	m.outputSettings.cursor = 1
+	_, cmd := m.updateOutputSettings(tea.KeyPressMsg{Code: tea.KeySpace, Text: " "})
+	m.updateOutputSettings(cmd())
+	if m.outputSettings.rules[0].Enabled {
+		t.Fatal("expected Space to disable the rule")
+	}
+
+	m.outputSettings.cursor = 0
+	_, cmd = m.updateOutputSettings(tea.KeyPressMsg{Code: 'd', Text: "d"})
+	m.updateOutputSettings(cmd())
+	if len(m.outputSettings.sinks) != 0 || len(m.outputSettings.rules) != 1 {
+		t.Fatalf("expected D to delete only the sink, got %d sinks and %d rules", len(m.outputSettings.sinks), len(m.outputSettings.rules))
+	}
+	if status := m.sinkRouter.Status(); len(status) != 0 {
+		t.Fatalf("expected the router to stop the deleted sink, got %+v", status)
+	}
+}
+
+func TestOutputSettings_DeletingTheWebhookRuleNeedsConfirmation(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModelForWebhookSettings(t)
+	registry := sinks.NewRegistry()
+	sink.Register(registry, m.boltAdapter)
+	router, err := sinks.NewRouter(registry, boltdb.NewSinkRepository(m.boltAdapter))
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+	t.Cleanup(router.Close)
+	m.sinkRouter = router
+	m.openOutputSettings()
+
+	// Row 1 is the seeded rule, after the seeded sink.
+	m.outputSettings.cursor = 1
+	_, cmd := m.updateOutputSettings(tea.KeyPressMsg{Code: 'd', Text: "d"})
+	if cmd != nil || len(m.outputSettings.rules) != 1 {
+		t.Fatal("expected the first D on the webhook rule to only warn")
+	}
+	if !strings.Contains(m.outputSettings.dialog.msg, "Trace_Message_To_Webhook") {
+		t.Fatalf("expected a warning about Trace_Message_To_Webhook, got %q", m.outputSettings.dialog.msg)
+	}
+
+	_, cmd = m.updateOutputSettings(tea.KeyPressMsg{Code: 'd', Text: "d"})
+	if cmd == nil {
+		t.Fatal("expected the second D to delete the webhook rule")
+	}
+	m.updateOutputSettings(cmd())
+	if len(m.outputSettings.rules) != 0 {
+		t.Fatalf("expected the webhook rule to be deleted, got %+v", m.outputSettings.rules)
+	}
+}
+
+func TestOutputSettings_OTLPSinkHasDedicatedFields(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModelForOutputSettings(t)
+	m.openOutputSettings()
+	m.editSink(nil)
+	typeOutputText(m, "otel")
+	m.updateOutputSettings(tea.KeyPressMsg{Code: tea.KeyDown})
+	for m.outputSettings.form.fields[sinkFieldType].value != domain.OTLPSinkType {
+		m.updateOutputSettings(tea.KeyPressMsg{Code: tea.KeyRight})
+	}
+	view := m.viewOutputSettings()
+	for _, label := range []string{"Collector Endpoint", "Headers", "Batch Size", "Flush Interval", "Retry Attempts"} {
+		if !strings.Contains(view, label) {
+			t.Fatalf("expected the %q field on the otlp form, got:\n%s", label, view)
+		}
+	}
+
+	m.updateOutputSettings(tea.KeyPressMsg{Code: tea.KeyDown})
+	if m.outputSettings.form.cursor != sinkFieldOTLPEndpoint {
+		t.Fatalf("expected ↓ to skip the generic settings, cursor at %d", m.outputSettings.form.cursor)
+	}
+	fields := m.outputSettings.form.fields
+	fields[sinkFieldOTLPEndpoint].value = "http://otel:4318"
+	fields[sinkFieldOTLPHeaders].value = "api-key=secret"
+	fields[sinkFieldOTLPBatchSize].value = "100"
+	fields[sinkFieldOTLPRetryAttempts].value = "3"
+	changed := m.saveSinkCmd()().(outputsChangedMsg)
+	if changed.err != nil {
+		t.Fatalf("save sink: %v", changed.err)
+	}
+	m.updateOutputSettings(changed)
+
+	if len(m.outputSettings.sinks) != 1 {
+		t.Fatalf("expected one saved sink, got %+v", m.outputSettings.sinks)
+	}
+	saved := m.outputSettings.sinks[0]
+	want := map[string]string{"endpoint": "http://otel:4318", "header.api-key": "secret", "batch_size": "100", "retry_attempts": "3"}
+	if len(saved.Settings) != len(want) {
+		t.Fatalf("saved settings = %v, want %v", saved.Settings, want)
+	}
+	for key, value := range want {
+		if got := saved.Setting(key); got != value {
+			t.Errorf("Setting(%q) = %q, want %q", key, got, value)
+		}
+	}
+
+	m.editSink(&saved)
+	fields = m.outputSettings.form.fields
+	if fields[sinkFieldOTLPEndpoint].value != "http://otel:4318" || fields[sinkFieldOTLPHeaders].value != "api-key=secret" || fields[sinkFieldOTLPRetryAttempts].value != "3" {
+		t.Fatalf("expected the otlp fields filled from the sink, got %+v", fields)
+	}
+}
@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"OmniView/internal/adapter/ui/styles"
+	"OmniView/internal/core/domain"
+	"fmt"
+
+	"charm.land/lipgloss/v2"
+)
+
+// ==========================================
+// Pause Sub-State
+// ==========================================
+
+// pauseState freezes the main viewport. While active, incoming messages are
+// still dequeued but held in backlog instead of the ring buffer, so neither new
+// lines nor evictions move the content being read.
+type pauseState struct {
+	active       bool                   // Whether the live stream is frozen
+	backlog      []*domain.QueueMessage // Messages received while paused, in arrival order
+	backlogBytes int                    // Sum of payload bytes held in backlog
+	received     int                    // Messages received while paused, including any dropped from backlog
+}
+
+// ==========================================
+// Helpers
+// ==========================================
+
+// togglePause freezes the stream, or resumes it and merges the backlog.
+func (m *Model) togglePause() {
+	if m.pause.active {
+		m.resumeStream()
+		return
+	}
+	m.pause.active = true
+}
+
+// queueBacklog holds msg until the stream is resumed. The backlog is bounded by
+// the same caps as the ring buffer; anything beyond them would be evicted on
+// resume anyway, so the oldest held messages are dropped first.
+func (m *Model) queueBacklog(msg *domain.QueueMessage) {
+	newPayload := len(msg.Payload())
+	for len(m.pause.backlog) > 0 &&
+		(len(m.pause.backlog) >= maxMessages || m.pause.backlogBytes+newPayload > maxRawBytes) {
+		m.pause.backlogBytes -= len(m.pause.backlog[0].Payload())
+		m.pause.backlog = m.pause.backlog[1:]
+	}
+	m.pause.backlog = append(m.pause.backlog, msg)
+	m.pause.backlogBytes += newPayload
+	m.pause.received++
+}
+
+// resumeStream unfreezes the viewport and merges the backlog into the ring
+// buffer in arrival order, rendering once at the end.
+func (m *Model) resumeStream() {
+	backlog := m.pause.backlog
+	m.pause = pauseState{}
+	if len(backlog) == 0 {
+		return
+	}
+
+	for _, msg := range backlog {
+		m.bufferMessage(msg)
+	}
+	m.invalidateColumnWidthCache()
+	if m.main.ready {
+		m.rebuildRenderedContent(m.main.viewport.Width())
+		if m.main.autoScroll {
+			m.main.viewport.GotoBottom()
+		}
+	}
+}
+
+// ==========================================
+// View
+// ==========================================
+
+// pauseFooterHint returns the footer notice shown while paused, or an empty
+// string when the stream is live.
+func (m *Model) pauseFooterHint() string {
+	if !m.pause.active {
+		return ""
+	}
+	noun := "messages"
+	if m.pause.received == 1 {
+		noun = "message"
+	}
+	return lipgloss.NewStyle().Foreground(styles.WarningColor).Bold(true).
+		Render(fmt.Sprintf("⏸ Paused — %d new %s", m.pause.received, noun))
+}
@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"OmniView/internal/core/domain"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func pushPauseTestMessage(t *testing.T, m *Model, id string) {
+	t.Helper()
+
+	msg, err := domain.NewQueueMessage(id, "ORDER_API", domain.LogLevelInfo, "payload "+id, time.Unix(1700000000, 0))
+	if err != nil {
+		t.Fatalf("NewQueueMessage: %v", err)
+	}
+	if _, cmd := m.Update(queueMessageMsg{message: msg}); cmd == nil {
+		t.Fatal("expected the event listener to keep draining while paused")
+	}
+}
+
+func TestPause_FreezesViewportAndMergesBacklogOnResume(t *testing.T) {
+	t.Parallel()
+
+	m := newTestMainModel(t, 140, 30)
+	m.initViewport()
+	for i := 0; i < 3; i++ {
+		pushPauseTestMessage(t, m, fmt.Sprintf("id-%d", i))
+	}
+
+	m.Update(makeCharPress("p"))
+	if !m.pause.active {
+		t.Fatal("expected p to pause the stream")
+	}
+	frozen := slices.Clone(m.main.renderedLines)
+
+	for i := 3; i < 8; i++ {
+		pushPauseTestMessage(t, m, fmt.Sprintf("id-%d", i))
+	}
+	if !slices.Equal(m.main.renderedLines, frozen) || len(m.main.messages) != 3 {
+		t.Fatal("expected the viewport and buffer to stay frozen while paused")
+	}
+	if footer := m.mainFooterText(); !strings.Contains(footer, "5 new messages") || !strings.Contains(footer, "P Resume") {
+		t.Fatalf("expected the backlog count in the footer, got %q", footer)
+	}
+
+	m.Update(makeCharPress("p"))
+	if m.pause.active || len(m.pause.backlog) != 0 {
+		t.Fatal("expected p to resume and drain the backlog")
+	}
+	if len(m.main.renderedLines) != 8 {
+		t.Fatalf("expected 8 rendered lines after resume, got %d", len(m.main.renderedLines))
+	}
+	for i, msg := range m.main.messages {
+		if want := fmt.Sprintf("id-%d", i); msg.MessageID() != want {
+			t.Fatalf("message %d = %q, want %q (backlog must merge in order)", i, msg.MessageID(), want)
+		}
+	}
+	if strings.Contains(m.mainFooterText(), "new message") {
+		t.Fatal("expected the backlog notice to disappear after resume")
+	}
+}
+
+func TestPause_BacklogDoesNotEvictWhilePaused(t *testing.T) {
+	t.Parallel()
+
+	m := newTestMainModel(t, 140, 30)
+	m.initViewport()
+	m.main.ready = false
+	for i := 0; i < maxMessages; i++ {
+		pushPauseTestMessage(t, m, fmt.Sprintf("id-%d", i))
+	}
+	m.main.ready = true
+	m.rebuildRenderedContent(m.main.viewport.Width())
+	first := m.main.messages[0]
+
+	m.togglePause()
+	pushPauseTestMessage(t, m, "late-1")
+	pushPauseTestMessage(t, m, "late-2")
+	if m.main.messages[0] != first || len(m.main.renderedLines) != maxMessages {
+		t.Fatal("ring-buffer eviction must wait until the stream is resumed")
+	}
+
+	m.togglePause()
+	if got := m.main.messages[0].MessageID(); got != "id-2" {
+		t.Fatalf("oldest message after resume = %q, want id-2", got)
+	}
+	if got := m.main.messages[len(m.main.messages)-1].MessageID(); got != "late-2" {
+		t.Fatalf("newest message after resume = %q, want late-2", got)
+	}
+}
+
+func TestPause_ClearDropsBacklog(t *testing.T) {
+	t.Parallel()
+
+	m := newTestMainModel(t, 140, 30)
+	m.initViewport()
+	m.Update(makeCharPress("p"))
+	pushPauseTestMessage(t, m, "held")
+
+	m.Update(makeCharPress("c"))
+	if len(m.pause.backlog) != 0 || !m.pause.active {
+		t.Fatal("expected clear to drop the backlog but stay paused")
+	}
+	if !strings.Contains(m.mainFooterText(), "0 new messages") {
+		t.Fatalf("expected the backlog count to reset, got %q", m.mainFooterText())
+	}
+}
@@ -0,0 +1,504 @@
+package ui
+
+import (
+	"OmniView/internal/adapter/ui/styles"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ==========================================
+// Payload Kinds
+// ==========================================
+
+// payloadKind classifies a trace payload for pretty-printing.
+type payloadKind int
+
+const (
+	payloadPlain payloadKind = iota
+	payloadJSON
+	payloadXML
+	payloadSQL
+)
+
+// String returns the label shown next to formatted payloads.
+func (k payloadKind) String() string {
+	switch k {
+	case payloadJSON:
+		return "JSON"
+	case payloadXML:
+		return "XML"
+	case payloadSQL:
+		return "SQL"
+	default:
+		return "Text"
+	}
+}
+
+// maxExpandedPayloadLines caps how many lines one pretty-printed payload
+// takes up in the trace feed; the detail pane always shows all of it.
+const maxExpandedPayloadLines = 40
+
+// sqlDetectPrefix bounds how much of a payload is inspected for SQL markers,
+// keeping detection constant-time for large payloads.
+const sqlDetectPrefix = 512
+
+// sqlStatementMarkers maps a leading SQL keyword to the words, at least one of
+// which must follow it, so plain sentences such as "Update complete" are not
+// mistaken for SQL.
+var sqlStatementMarkers = map[string][]string{
+	"SELECT":   {" FROM ", " FROM DUAL"},
+	"INSERT":   {" INTO "},
+	"UPDATE":   {" SET "},
+	"DELETE":   {" FROM ", " WHERE "},
+	"MERGE":    {" INTO ", " USING "},
+	"WITH":     {" AS (", " AS(", " SELECT "},
+	"BEGIN":    {" END;", " END "},
+	"DECLARE":  {" BEGIN "},
+	"CREATE":   {" TABLE ", " INDEX ", " VIEW ", " SEQUENCE ", " PACKAGE ", " PROCEDURE ", " FUNCTION ", " TRIGGER ", " TYPE ", " OR REPLACE "},
+	"ALTER":    {" TABLE ", " INDEX ", " SESSION ", " SEQUENCE ", " PACKAGE ", " USER "},
+	"DROP":     {" TABLE ", " INDEX ", " VIEW ", " SEQUENCE ", " PACKAGE ", " PROCEDURE ", " FUNCTION ", " TRIGGER "},
+	"TRUNCATE": {" TABLE "},
+}
+
+// detectPayloadKind guesses the payload format from its first and last
+// non-space characters (JSON, XML) or its leading keyword (SQL). It never
+// parses the payload, so it is cheap enough for the append hot path;
+// formatPayload validates the guess and falls back to plain text.
+func detectPayloadKind(payload string) payloadKind {
+	trimmed := strings.TrimSpace(payload)
+	if len(trimmed) < 2 {
+		return payloadPlain
+	}
+
+	first, last := trimmed[0], trimmed[len(trimmed)-1]
+	switch {
+	case (first == '{' && last == '}') || (first == '[' && last == ']'):
+		return payloadJSON
+	case first == '<' && last == '>':
+		return payloadXML
+	}
+
+	end := 0
+	for end < len(trimmed) && end < 10 && unicode.IsLetter(rune(trimmed[end])) {
+		end++
+	}
+	markers, ok := sqlStatementMarkers[strings.ToUpper(trimmed[:end])]
+	if !ok {
+		return payloadPlain
+	}
+	prefix := trimmed[:min(len(trimmed), sqlDetectPrefix)]
+	normalized := " " + strings.ToUpper(strings.Join(strings.Fields(prefix), " ")) + " "
+	for _, marker := range markers {
+		if strings.Contains(normalized, marker) {
+			return payloadSQL
+		}
+	}
+	return payloadPlain
+}
+
+// formatPayload pretty-prints and colourises payload as kind. Returns false
+// when the payload does not parse as kind, in which case callers render it as
+// plain text.
+func formatPayload(payload string, kind payloadKind) ([]string, bool) {
+	switch kind {
+	case payloadJSON:
+		tree, err := parseJSONTree(payload)
+		if err != nil {
+			return nil, false
+		}
+		return tree.plainLines(), true
+	case payloadXML:
+		return formatXML(payload)
+	case payloadSQL:
+		return formatSQL(payload), true
+	default:
+		return nil, false
+	}
+}
+
+// wrapFormattedLines hard-wraps styled lines to width, indenting continuation
+// lines by the original line's leading spaces so nesting stays readable.
+func wrapFormattedLines(lines []string, width int) []string {
+	if width <= 0 {
+		return lines
+	}
+	wrapped := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if ansi.StringWidth(line) <= width {
+			wrapped = append(wrapped, line)
+			continue
+		}
+		plain := ansi.Strip(line)
+		indent := len(plain) - len(strings.TrimLeft(plain, " "))
+		if indent >= width/2 {
+			indent = 0
+		}
+		parts := strings.Split(ansi.Hardwrap(line, width, true), "\n")
+		wrapped = append(wrapped, parts[0])
+		for _, part := range parts[1:] {
+			wrapped = append(wrapped, strings.Split(ansi.Hardwrap(strings.Repeat(" ", indent)+part, width, true), "\n")...)
+		}
+	}
+	return wrapped
+}
+
+// ==========================================
+// Expanded Feed
+// ==========================================
+
+// toggleExpandedPayloads switches the trace feed between word-wrapped and
+// pretty-printed structured payloads.
+func (m *Model) toggleExpandedPayloads() {
+	m.main.expanded = !m.main.expanded
+	if m.main.ready {
+		m.rebuildRenderedContent(m.main.viewport.Width())
+		m.ensureSelectionVisible()
+	}
+}
+
+// expandedPayloadLines returns the pretty-printed payload for the expanded
+// feed, or nil when the payload is plain text or fails to parse.
+func expandedPayloadLines(payload string) []string {
+	kind := detectPayloadKind(payload)
+	if kind == payloadPlain {
+		return nil
+	}
+	lines, ok := formatPayload(payload, kind)
+	if !ok {
+		return nil
+	}
+	return lines
+}
+
+// capFormattedLines trims lines to maxExpandedPayloadLines, noting how many
+// were left out.
+func capFormattedLines(lines []string) []string {
+	if len(lines) <= maxExpandedPayloadLines {
+		return lines
+	}
+	hidden := len(lines) - maxExpandedPayloadLines + 1
+	capped := slices.Clone(lines[:maxExpandedPayloadLines-1])
+	return append(capped, styles.SyntaxCommentStyle.Render(fmt.Sprintf("… %d more lines", hidden)))
+}
+
+// ==========================================
+// XML
+// ==========================================
+
+// formatXML re-indents an XML document or fragment, keeping text-only elements
+// on one line. Returns false when the tokens do not form balanced XML.
+func formatXML(payload string) ([]string, bool) {
+	dec := xml.NewDecoder(strings.NewReader(payload))
+	var tokens []xml.Token
+	var open []string
+	for {
+		tok, err := dec.RawToken()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, false
+		}
+		tok = xml.CopyToken(tok)
+		switch t := tok.(type) {
+		case xml.StartElement:
+			open = append(open, xmlName(t.Name))
+		case xml.EndElement:
+			if len(open) == 0 || open[len(open)-1] != xmlName(t.Name) {
+				return nil, false
+			}
+			open = open[:len(open)-1]
+		case xml.CharData:
+			if len(strings.TrimSpace(string(t))) == 0 {
+				continue
+			}
+		}
+		tokens = append(tokens, tok)
+	}
+	if len(open) > 0 || len(tokens) == 0 {
+		return nil, false
+	}
+
+	punct := styles.SyntaxPunctuationStyle.Render
+	var lines []string
+	depth := 0
+	indent := func() string { return strings.Repeat("  ", depth) }
+
+	for i := 0; i < len(tokens); i++ {
+		switch t := tokens[i].(type) {
+		case xml.StartElement:
+			openTag := punct("<") + styles.SyntaxTagStyle.Render(sanitizeLogString(xmlName(t.Name))) + xmlAttrs(t.Attr)
+			// <a/> for empty elements.
+			if i+1 < len(tokens) {
+				if _, ok := tokens[i+1].(xml.EndElement); ok {
+					lines = append(lines, indent()+openTag+punct("/>"))
+					i++
+					continue
+				}
+			}
+			// <a>text</a> for text-only elements.
+			if i+2 < len(tokens) {
+				text, isText := tokens[i+1].(xml.CharData)
+				_, closes := tokens[i+2].(xml.EndElement)
+				if isText && closes {
+					lines = append(lines, indent()+openTag+punct(">")+
+						styles.BodyTextStyle.Render(xmlText(text))+
+						punct("</")+styles.SyntaxTagStyle.Render(sanitizeLogString(xmlName(t.Name)))+punct(">"))
+					i += 2
+					continue
+				}
+			}
+			lines = append(lines, indent()+openTag+punct(">"))
+			depth++
+		case xml.EndElement:
+			depth--
+			lines = append(lines, indent()+punct("</")+styles.SyntaxTagStyle.Render(sanitizeLogString(xmlName(t.Name)))+punct(">"))
+		case xml.CharData:
+			lines = append(lines, indent()+styles.BodyTextStyle.Render(xmlText(t)))
+		case xml.Comment:
+			lines = append(lines, indent()+styles.SyntaxCommentStyle.Render("<!-- "+sanitizeLogString(string(t))+" -->"))
+		case xml.ProcInst:
+			lines = append(lines, indent()+punct("<?"+sanitizeLogString(t.Target+" "+string(t.Inst))+"?>"))
+		case xml.Directive:
+			lines = append(lines, indent()+punct("<!"+sanitizeLogString(string(t))+">"))
+		}
+	}
+	return lines, true
+}
+
+func xmlName(name xml.Name) string {
+	if name.Space != "" {
+		return name.Space + ":" + name.Local
+	}
+	return name.Local
+}
+
+func xmlAttrs(attrs []xml.Attr) string {
+	var b strings.Builder
+	for _, attr := range attrs {
+		b.WriteString(" ")
+		b.WriteString(styles.SyntaxAttrStyle.Render(sanitizeLogString(xmlName(attr.Name))))
+		b.WriteString(styles.SyntaxPunctuationStyle.Render("="))
+		b.WriteString(styles.SyntaxStringStyle.Render(`"` + sanitizeLogString(attr.Value) + `"`))
+	}
+	return b.String()
+}
+
+// xmlText collapses whitespace runs in character data.
+func xmlText(data xml.CharData) string {
+	return sanitizeLogString(strings.Join(strings.Fields(string(data)), " "))
+}
+
+// ==========================================
+// SQL
+// ==========================================
+
+type sqlTokenKind int
+
+const (
+	sqlWord sqlTokenKind = iota
+	sqlString
+	sqlQuotedIdent
+	sqlNumber
+	sqlComment
+	sqlPunct
+)
+
+type sqlToken struct {
+	kind sqlTokenKind
+	text string
+}
+
+// sqlKeywords are highlighted when they appear as bare words.
+var sqlKeywords = map[string]bool{
+	"SELECT": true, "FROM": true, "WHERE": true, "AND": true, "OR": true, "NOT": true, "IN": true,
+	"IS": true, "NULL": true, "LIKE": true, "BETWEEN": true, "EXISTS": true, "AS": true, "ON": true,
+	"JOIN": true, "INNER": true, "LEFT": true, "RIGHT": true, "FULL": true, "OUTER": true, "CROSS": true,
+	"GROUP": true, "ORDER": true, "BY": true, "HAVING": true, "UNION": true, "ALL": true, "DISTINCT": true,
+	"INSERT": true, "INTO": true, "VALUES": true, "UPDATE": true, "SET": true, "DELETE": true,
+	"MERGE": true, "USING": true, "MATCHED": true, "WHEN": true, "THEN": true, "ELSE": true, "END": true,
+	"CASE": true, "WITH": true, "BEGIN": true, "DECLARE": true, "RETURNING": true, "INTERSECT": true,
+	"MINUS": true, "CREATE": true, "ALTER": true, "DROP": true, "TABLE": true, "INDEX": true, "VIEW": true,
+	"TRUNCATE": true, "ASC": true, "DESC": true, "FETCH": true, "FIRST": true, "NEXT": true, "ROWS": true,
+	"ONLY": true, "OFFSET": true, "FOR": true, "LOOP": true, "IF": true, "ELSIF": true, "EXCEPTION": true,
+	"COMMIT": true, "ROLLBACK": true, "EXECUTE": true, "IMMEDIATE": true, "DUAL": true,
+}
+
+// sqlClauseKeywords start a new line when pretty-printing.
+var sqlClauseKeywords = map[string]bool{
+	"SELECT": true, "FROM": true, "WHERE": true, "GROUP": true, "ORDER": true, "HAVING": true,
+	"UNION": true, "INTERSECT": true, "MINUS": true, "VALUES": true, "SET": true, "INTO": true,
+	"JOIN": true, "INNER": true, "LEFT": true, "RIGHT": true, "FULL": true, "CROSS": true,
+	"USING": true, "WHEN": true, "RETURNING": true, "FETCH": true, "OFFSET": true,
+}
+
+// sqlJoinModifiers are words that may precede JOIN on the same line.
+var sqlJoinModifiers = map[string]bool{
+	"INNER": true, "LEFT": true, "RIGHT": true, "FULL": true, "CROSS": true, "OUTER": true, "NATURAL": true,
+}
+
+// tokenizeSQL splits SQL text into words, literals, comments and punctuation.
+func tokenizeSQL(sql string) []sqlToken {
+	var tokens []sqlToken
+	runes := []rune(sql)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			start := i
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+			tokens = append(tokens, sqlToken{sqlComment, string(runes[start:i])})
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			start := i
+			i += 2
+			for i < len(runes) && !(runes[i] == '*' && i+1 < len(runes) && runes[i+1] == '/') {
+				i++
+			}
+			i = min(i+2, len(runes))
+			tokens = append(tokens, sqlToken{sqlComment, string(runes[start:i])})
+		case r == '\'' || r == '"':
+			start := i
+			i++
+			for i < len(runes) {
+				if runes[i] == r {
+					// '' escapes a quote inside a string literal.
+					if i+1 < len(runes) && runes[i+1] == r {
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				i++
+			}
+			kind := sqlString
+			if r == '"' {
+				kind = sqlQuotedIdent
+			}
+			tokens = append(tokens, sqlToken{kind, string(runes[start:i])})
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, sqlToken{sqlNumber, string(runes[start:i])})
+		case unicode.IsLetter(r) || r == '_' || r == ':' && i+1 < len(runes) && unicode.IsLetter(runes[i+1]):
+			start := i
+			i++
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || strings.ContainsRune("_$#", runes[i])) {
+				i++
+			}
+			tokens = append(tokens, sqlToken{sqlWord, string(runes[start:i])})
+		default:
+			start := i
+			i++
+			if i < len(runes) && strings.Contains("<=|>=|<>|!=|:=|\\|\\||=>", string(runes[start:i+1])) {
+				i++
+			}
+			tokens = append(tokens, sqlToken{sqlPunct, string(runes[start:i])})
+		}
+	}
+	return tokens
+}
+
+// formatSQL puts each major clause on its own line, indents sub-queries by
+// parenthesis depth and colourises keywords, literals and comments.
+func formatSQL(sql string) []string {
+	tokens := tokenizeSQL(sql)
+
+	var lines []string
+	var current strings.Builder
+	depth := 0
+	betweenPending := false
+	needSpace := false
+
+	flush := func() {
+		if strings.TrimSpace(ansi.Strip(current.String())) != "" {
+			lines = append(lines, current.String())
+		}
+		current.Reset()
+		needSpace = false
+	}
+	newLine := func(extra int) {
+		flush()
+		current.WriteString(strings.Repeat("  ", max(depth+extra, 0)))
+	}
+
+	for i, tok := range tokens {
+		upper := strings.ToUpper(tok.text)
+		prevUpper := ""
+		if i > 0 && tokens[i-1].kind == sqlWord {
+			prevUpper = strings.ToUpper(tokens[i-1].text)
+		}
+
+		if tok.kind == sqlWord && i > 0 {
+			switch {
+			case sqlClauseKeywords[upper] && !sqlJoinModifiers[prevUpper] && !(upper == "BY" || prevUpper == "UNION"):
+				newLine(0)
+			case (upper == "AND" && !betweenPending) || upper == "OR":
+				newLine(1)
+			}
+		}
+		if upper == "BETWEEN" {
+			betweenPending = true
+		} else if upper == "AND" {
+			betweenPending = false
+		}
+
+		switch tok.text {
+		case ")":
+			depth = max(depth-1, 0)
+		case ",", ";", ".":
+			needSpace = false
+		}
+		if needSpace && tok.text != ")" {
+			current.WriteString(" ")
+		}
+
+		current.WriteString(renderSQLToken(tok, upper))
+		needSpace = tok.text != "(" && tok.text != "."
+
+		switch {
+		case tok.text == "(":
+			depth++
+		case tok.kind == sqlComment && strings.HasPrefix(tok.text, "--"):
+			newLine(0)
+		case tok.text == ";":
+			newLine(0)
+		}
+	}
+	flush()
+	return lines
+}
+
+func renderSQLToken(tok sqlToken, upper string) string {
+	text := sanitizeLogString(tok.text)
+	switch tok.kind {
+	case sqlWord:
+		if sqlKeywords[upper] {
+			return styles.SyntaxKeywordStyle.Render(text)
+		}
+		return styles.BodyTextStyle.Render(text)
+	case sqlString:
+		return styles.SyntaxStringStyle.Render(text)
+	case sqlQuotedIdent:
+		return styles.SyntaxKeyStyle.Render(text)
+	case sqlNumber:
+		return styles.SyntaxNumberStyle.Render(text)
+	case sqlComment:
+		return styles.SyntaxCommentStyle.Render(text)
+	default:
+		return styles.SyntaxPunctuationStyle.Render(text)
+	}
+}
@@ -0,0 +1,235 @@
+package ui
+
+import (
+	"OmniView/internal/core/domain"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// plainLines strips styling so tests can compare the pretty-printed text.
+func plainLines(lines []string) []string {
+	plain := make([]string, len(lines))
+	for i, line := range lines {
+		plain[i] = ansi.Strip(line)
+	}
+	return plain
+}
+
+func TestDetectPayloadKind(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		payload string
+		want    payloadKind
+	}{
+		{`{"order": 42}`, payloadJSON},
+		{`  [1, 2, 3]  `, payloadJSON},
+		{`<order id="42"><line/></order>`, payloadXML},
+		{"select id from orders where status = 'OPEN'", payloadSQL},
+		{"UPDATE orders\nSET status = 'DONE'", payloadSQL},
+		{"BEGIN pkg.run; END;", payloadSQL},
+		{"Update complete for order 42", payloadPlain},
+		{"Select a warehouse before shipping", payloadPlain},
+		{"[step 1] started", payloadPlain},
+		{"{", payloadPlain},
+		{"", payloadPlain},
+	}
+	for _, tt := range tests {
+		if got := detectPayloadKind(tt.payload); got != tt.want {
+			t.Errorf("detectPayloadKind(%q) = %v, want %v", tt.payload, got, tt.want)
+		}
+	}
+}
+
+func TestFormatPayload_JSONKeepsKeyOrderAndIndents(t *testing.T) {
+	t.Parallel()
+
+	lines, ok := formatPayload(`{"z":1,"a":{"list":[true,null,"x\ty"]},"empty":{}}`, payloadJSON)
+	if !ok {
+		t.Fatal("expected valid JSON to format")
+	}
+	want := []string{
+		`{`,
+		`  "z": 1,`,
+		`  "a": {`,
+		`    "list": [`,
+		`      true,`,
+		`      null,`,
+		`      "x\ty"`,
+		`    ]`,
+		`  },`,
+		`  "empty": {}`,
+		`}`,
+	}
+	if got := plainLines(lines); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("formatted JSON:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if _, ok := formatPayload(`{"a":1} trailing`, payloadJSON); ok {
+		t.Fatal("expected trailing data to fall back to plain text")
+	}
+}
+
+func TestFormatPayload_XML(t *testing.T) {
+	t.Parallel()
+
+	lines, ok := formatPayload(`<order id="42"><!-- draft --><line sku="A1">2</line><note/></order>`, payloadXML)
+	if !ok {
+		t.Fatal("expected valid XML to format")
+	}
+	want := []string{
+		`<order id="42">`,
+		`  <!-- draft -->`,
+		`  <line sku="A1">2</line>`,
+		`  <note/>`,
+		`</order>`,
+	}
+	if got := plainLines(lines); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("formatted XML:\n%s", strings.Join(got, "\n"))
+	}
+
+	if _, ok := formatPayload(`<a><b></a></b>`, payloadXML); ok {
+		t.Fatal("expected mismatched tags to fall back to plain text")
+	}
+}
+
+func TestFormatPayload_SQLBreaksClauses(t *testing.T) {
+	t.Parallel()
+
+	lines, ok := formatPayload("select o.id, o.total from orders o join lines l on l.order_id = o.id where o.status = 'it''s' and o.total between 1 and 10 order by o.id", payloadSQL)
+	if !ok {
+		t.Fatal("expected SQL to format")
+	}
+	want := []string{
+		`select o.id, o.total`,
+		`from orders o`,
+		`join lines l on l.order_id = o.id`,
+		`where o.status = 'it''s'`,
+		`  and o.total between 1 and 10`,
+		`order by o.id`,
+	}
+	if got := plainLines(lines); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("formatted SQL:\n%s", strings.Join(got, "\n"))
+	}
+}
+
+func TestJSONTree_FoldAndUnfold(t *testing.T) {
+	t.Parallel()
+
+	tree, err := parseJSONTree(`{"a":{"b":1,"c":2},"d":[1,2]}`)
+	if err != nil {
+		t.Fatalf("parseJSONTree: %v", err)
+	}
+	if len(tree.lines) != 10 {
+		t.Fatalf("expanded tree has %d lines, want 10", len(tree.lines))
+	}
+
+	tree.moveCursor(1)
+	tree.toggleAtCursor()
+	if got := ansi.Strip(tree.lines[1].text); got != `  "a": { … }, 2 keys` {
+		t.Fatalf("folded line = %q", got)
+	}
+	if len(tree.lines) != 7 {
+		t.Fatalf("tree with a folded object has %d lines, want 7", len(tree.lines))
+	}
+
+	tree.setAllCollapsed(true)
+	if len(tree.lines) != 4 || tree.lines[tree.cursor].node != tree.root.children[0] {
+		t.Fatalf("expected only the top-level members with the cursor kept on \"a\", got %d lines", len(tree.lines))
+	}
+	tree.setAllCollapsed(false)
+	if len(tree.lines) != 10 {
+		t.Fatalf("expected expanding all to restore 10 lines, got %d", len(tree.lines))
+	}
+}
+
+func TestExpandedPayloads_FeedPrettyPrintsStructuredMessages(t *testing.T) {
+	t.Parallel()
+
+	m := newTestMainModel(t, 140, 30)
+	m.initViewport()
+	for i, payload := range []string{`{"order":42,"status":"OPEN"}`, "plain payload"} {
+		msg, err := domain.NewQueueMessage(fmt.Sprintf("id-%d", i), "ORDER_API", domain.LogLevelInfo, payload, time.Unix(1700000000, 0))
+		if err != nil {
+			t.Fatalf("NewQueueMessage: %v", err)
+		}
+		m.Update(queueMessageMsg{message: msg})
+	}
+	if strings.Count(m.main.renderedLines[0], "\n") != 0 {
+		t.Fatal("expected payloads on one line until expanded")
+	}
+
+	m.Update(makeCharPress("x"))
+	if !m.main.expanded {
+		t.Fatal("expected x to expand structured payloads")
+	}
+	if got := strings.Count(m.main.renderedLines[0], "\n"); got != 3 {
+		t.Fatalf("expected the JSON payload on 4 lines, got %d", got+1)
+	}
+	if strings.Count(m.main.renderedLines[1], "\n") != 0 {
+		t.Fatal("plain payloads should not change")
+	}
+	if !strings.Contains(m.mainFooterText(), "X Collapse Payloads") {
+		t.Fatal("expected the footer to offer collapsing")
+	}
+
+	m.Update(makeCharPress("x"))
+	if strings.Count(m.main.renderedLines[0], "\n") != 0 {
+		t.Fatal("expected x to collapse payloads again")
+	}
+}
+
+func TestExpandedPayloads_CapsLongPayloads(t *testing.T) {
+	t.Parallel()
+
+	items := make([]string, 100)
+	for i := range items {
+		items[i] = fmt.Sprint(i)
+	}
+	line := traceLine{formatted: expandedPayloadLines("[" + strings.Join(items, ",") + "]")}
+	rendered := renderTraceColumns(line, newTraceColumnLayout(140, colMinLevelWidth, colMinAPIWidth))
+
+	if got := strings.Count(rendered, "\n") + 1; got != maxExpandedPayloadLines {
+		t.Fatalf("rendered %d lines, want %d", got, maxExpandedPayloadLines)
+	}
+	if !strings.Contains(rendered, "… 63 more lines") {
+		t.Fatal("expected a note about the hidden lines")
+	}
+}
+
+func TestMessageDetail_JSONTreeNavigation(t *testing.T) {
+	t.Parallel()
+
+	m := newTestMainModel(t, 140, 40)
+	m.initViewport()
+	msg, err := domain.NewQueueMessage("msg-1", "ORDER_API", domain.LogLevelInfo, `{"order":{"id":42,"lines":[1,2]},"ok":true}`, time.Unix(1700000000, 0))
+	if err != nil {
+		t.Fatalf("NewQueueMessage: %v", err)
+	}
+	m.Update(queueMessageMsg{message: msg})
+	m.Update(makeKeyPress(tea.KeyEnter))
+	m.Update(makeKeyPress(tea.KeyEnter))
+
+	if m.selection.jsonTree == nil {
+		t.Fatal("expected a JSON tree for a JSON payload")
+	}
+	body := ansi.Strip(m.messageDetailBody(msg, 80))
+	if !strings.Contains(body, "Payload (JSON)") || !strings.Contains(body, `"lines": [`) {
+		t.Fatalf("expected the pretty-printed payload in the detail body:\n%s", body)
+	}
+
+	m.Update(makeKeyPress(tea.KeyDown))
+	m.Update(makeKeyPress(tea.KeySpace))
+	body = ansi.Strip(m.messageDetailBody(msg, 80))
+	if !strings.Contains(body, `›   "order": { … }, 2 keys`) {
+		t.Fatalf("expected Space to fold the object under the cursor:\n%s", body)
+	}
+	if !m.selection.detail {
+		t.Fatal("tree keys should not close the pane")
+	}
+}
@@ -0,0 +1,355 @@
+package ui
+
+import (
+	"OmniView/internal/adapter/ui/styles"
+	"OmniView/internal/core/domain"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+)
+
+// ==========================================
+// Search Sub-State
+// ==========================================
+
+// searchState holds the main-screen search prompt and the committed query.
+// matches indexes into renderedLines (one entry per filtered message).
+type searchState struct {
+	prompt        bool                 // Whether the "/" prompt is accepting input
+	input         string               // Query text being edited
+	caseSensitive bool                 // Match case exactly when true
+	regex         bool                 // Treat the query as a regular expression when true
+	matcher       *searchMatcher       // Compiled query; nil when no search is active
+	err           string               // Compile error for the current input, if any
+	matches       []int                // renderedLines indexes whose message matches
+	current       int                  // Position in matches of the focused match, -1 when none
+	currentMsg    *domain.QueueMessage // Message of the focused match, survives rebuilds
+}
+
+// searchMatcher matches a compiled query against trace payloads and process names.
+type searchMatcher struct {
+	re *regexp.Regexp
+}
+
+// newSearchMatcher compiles query. Plain queries are matched literally.
+// Returns nil without error for an empty query.
+func newSearchMatcher(query string, caseSensitive, useRegex bool) (*searchMatcher, error) {
+	if query == "" {
+		return nil, nil
+	}
+	pattern := query
+	if !useRegex {
+		pattern = regexp.QuoteMeta(query)
+	}
+	if !caseSensitive {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+	return &searchMatcher{re: re}, nil
+}
+
+// matches reports whether msg's payload or process name contains the query.
+func (s *searchMatcher) matches(msg *domain.QueueMessage) bool {
+	return s.re.MatchString(sanitizeLogString(msg.Payload())) ||
+		s.re.MatchString(sanitizeLogString(msg.ProcessName()))
+}
+
+// traceHighlight pairs a matcher with the style used to mark its matches in one rendered line.
+type traceHighlight struct {
+	matcher *searchMatcher
+	style   lipgloss.Style
+}
+
+// apply renders text with base, wrapping every non-empty match in the highlight
+// style. Text without matches is returned unchanged so the caller can keep its
+// regular rendering path.
+func (h *traceHighlight) apply(text string, base lipgloss.Style) (string, bool) {
+	if h == nil || text == "" {
+		return text, false
+	}
+	spans := h.matcher.re.FindAllStringIndex(text, -1)
+	if len(spans) == 0 {
+		return text, false
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, span := range spans {
+		if span[0] == span[1] {
+			continue
+		}
+		if span[0] > last {
+			b.WriteString(base.Render(text[last:span[0]]))
+		}
+		b.WriteString(h.style.Render(text[span[0]:span[1]]))
+		last = span[1]
+	}
+	if last == 0 {
+		return text, false
+	}
+	if last < len(text) {
+		b.WriteString(base.Render(text[last:]))
+	}
+	return b.String(), true
+}
+
+// ==========================================
+// Helpers
+// ==========================================
+
+// searchHighlightFor returns the highlight for msg, or nil when no search is active.
+func (m *Model) searchHighlightFor(msg *domain.QueueMessage) *traceHighlight {
+	if m.search.matcher == nil {
+		return nil
+	}
+	style := styles.SearchMatchStyle
+	if msg == m.search.currentMsg {
+		style = styles.SearchCurrentMatchStyle
+	}
+	return &traceHighlight{matcher: m.search.matcher, style: style}
+}
+
+// openSearchPrompt starts editing the search query. The previous query stays
+// in the input so it can be refined.
+func (m *Model) openSearchPrompt() {
+	m.search.prompt = true
+}
+
+// applySearchInput recompiles the prompt input and re-renders the viewport with
+// the new highlights. An invalid pattern keeps the error for inline display and
+// clears the active search.
+func (m *Model) applySearchInput() {
+	matcher, err := newSearchMatcher(m.search.input, m.search.caseSensitive, m.search.regex)
+	m.search.err = ""
+	if err != nil {
+		m.search.err = err.Error()
+	}
+	m.search.matcher = matcher
+	m.search.current = -1
+	m.search.currentMsg = nil
+	if m.main.ready {
+		m.rebuildRenderedContent(m.main.viewport.Width())
+	}
+}
+
+// clearSearch removes the active query and its highlights.
+func (m *Model) clearSearch() {
+	m.search = searchState{current: -1}
+	if m.main.ready {
+		m.rebuildRenderedContent(m.main.viewport.Width())
+	}
+}
+
+// recordSearchMatch notes that the rendered entry at index matched the query.
+// Called while (re)building renderedLines so matches stay aligned with them.
+func (m *Model) recordSearchMatch(index int, msg *domain.QueueMessage) {
+	if m.search.matcher == nil || !m.search.matcher.matches(msg) {
+		return
+	}
+	if msg == m.search.currentMsg {
+		m.search.current = len(m.search.matches)
+	}
+	m.search.matches = append(m.search.matches, index)
+}
+
+// jumpToSearchMatch focuses the next (or previous) match, scrolls the viewport
+// to it and pauses auto-scroll so new messages do not pull the view away.
+func (m *Model) jumpToSearchMatch(forward bool) {
+	if len(m.search.matches) == 0 || !m.main.ready {
+		return
+	}
+	m.main.autoScroll = false
+
+	total := len(m.search.matches)
+	next := m.search.current
+	switch {
+	case next < 0:
+		next = m.firstSearchMatchFromViewport(forward)
+	case forward:
+		next = (next + 1) % total
+	default:
+		next = (next - 1 + total) % total
+	}
+
+	filtered := m.filterMessages(m.main.messages)
+	previous := m.search.current
+	m.search.current = next
+	entry := m.search.matches[next]
+	m.search.currentMsg = filtered[entry]
+
+	// Re-render only the entries whose highlight style changed.
+	if previous >= 0 && previous < total && previous != next {
+		m.rerenderEntry(m.search.matches[previous], filtered)
+	}
+	m.rerenderEntry(entry, filtered)
+	m.syncViewportContent()
+
+	m.main.viewport.SetYOffset(max(m.renderedLineOffset(entry)-searchJumpContext, 0))
+}
+
+// searchJumpContext is the number of lines kept above a match after a jump.
+const searchJumpContext = 2
+
+// firstSearchMatchFromViewport returns the first match at or below the top of
+// the viewport when moving forward, or the last match above it when moving back.
+func (m *Model) firstSearchMatchFromViewport(forward bool) int {
+	top := m.main.viewport.YOffset()
+	offsets := m.renderedLineOffsets()
+	if forward {
+		for i, entry := range m.search.matches {
+			if offsets[entry] >= top {
+				return i
+			}
+		}
+		return 0
+	}
+	for i := len(m.search.matches) - 1; i >= 0; i-- {
+		if offsets[m.search.matches[i]] < top {
+			return i
+		}
+	}
+	return len(m.search.matches) - 1
+}
+
+// renderedLineOffset returns the viewport line at which renderedLines[entry] starts.
+func (m *Model) renderedLineOffset(entry int) int {
+	offset := 0
+	for _, rendered := range m.main.renderedLines[:min(entry, len(m.main.renderedLines))] {
+		offset += strings.Count(rendered, "\n") + 1
+	}
+	return offset
+}
+
+// renderedLineOffsets returns the starting viewport line of every rendered entry.
+func (m *Model) renderedLineOffsets() []int {
+	offsets := make([]int, len(m.main.renderedLines))
+	offset := 0
+	for i, rendered := range m.main.renderedLines {
+		offsets[i] = offset
+		offset += strings.Count(rendered, "\n") + 1
+	}
+	return offsets
+}
+
+// rerenderEntry re-renders renderedLines[entry] from its message in filtered.
+func (m *Model) rerenderEntry(entry int, filtered []*domain.QueueMessage) {
+	if entry < 0 || entry >= len(m.main.renderedLines) || entry >= len(filtered) {
+		return
+	}
+	width := m.main.viewport.Width()
+	m.main.renderedLines[entry] = m.renderMessage(filtered[entry], m.traceColumnLayout(width), width >= colMinWidth)
+}
+
+// ==========================================
+// Update
+// ==========================================
+
+// updateSearchPrompt handles keyboard and paste input while the "/" prompt is open.
+// The query is applied as it is typed; Enter commits and jumps to the first match.
+func (m *Model) updateSearchPrompt(msg tea.Msg) (*Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.PasteMsg:
+		m.search.input += sanitizePasteInput(msg.Content)
+		m.applySearchInput()
+		return m, nil
+
+	case tea.KeyPressMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			m.cancel()
+			return m, tea.Quit
+		case "esc":
+			m.clearSearch()
+			return m, nil
+		case "enter":
+			m.search.prompt = false
+			if m.search.matcher == nil {
+				m.clearSearch()
+				return m, nil
+			}
+			m.jumpToSearchMatch(true)
+			return m, nil
+		case "tab":
+			m.search.caseSensitive = !m.search.caseSensitive
+			m.applySearchInput()
+			return m, nil
+		case "ctrl+r":
+			m.search.regex = !m.search.regex
+			m.applySearchInput()
+			return m, nil
+		case "ctrl+u":
+			m.search.input = ""
+			m.applySearchInput()
+			return m, nil
+		case "backspace":
+			if value := m.search.input; len(value) > 0 {
+				_, size := utf8.DecodeLastRuneInString(value)
+				m.search.input = value[:len(value)-size]
+				m.applySearchInput()
+			}
+			return m, nil
+		}
+
+		if len(msg.Text) > 0 && !msg.Mod.Contains(tea.ModCtrl) {
+			m.search.input += msg.Text
+			m.applySearchInput()
+		}
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// ==========================================
+// View
+// ==========================================
+
+// searchStatusText renders the prompt while editing, or a summary of the
+// committed query. Returns an empty string when no search is active.
+func (m *Model) searchStatusText() string {
+	if !m.search.prompt && m.search.matcher == nil {
+		return ""
+	}
+
+	toggle := func(label string, on bool) string {
+		if on {
+			return lipgloss.NewStyle().Foreground(styles.AccentColor).Bold(true).Render("[" + label + "]")
+		}
+		return styles.SubtitleStyle.Render("[" + label + "]")
+	}
+	flags := toggle("Aa", m.search.caseSensitive) + " " + toggle(".*", m.search.regex)
+
+	var result string
+	switch {
+	case m.search.err != "":
+		result = styles.OnboardingErrorStyle.Render(m.search.err)
+	case m.search.matcher == nil:
+		result = ""
+	case len(m.search.matches) == 0:
+		result = lipgloss.NewStyle().Foreground(styles.WarningColor).Render("no matches")
+	case m.search.current >= 0:
+		result = styles.BodyTextStyle.Render(fmt.Sprintf("%d/%d", m.search.current+1, len(m.search.matches)))
+	default:
+		result = styles.BodyTextStyle.Render(fmt.Sprintf("%d matches", len(m.search.matches)))
+	}
+
+	if m.search.prompt {
+		prompt := formCursorStyle.Render("/") + formValueStyle.Render(m.search.input) + formCursorStyle.Render("_")
+		parts := []string{prompt, flags}
+		if result != "" {
+			parts = append(parts, result)
+		}
+		return strings.Join(parts, "  ")
+	}
+
+	return styles.BodyTextStyle.Render("Search ") +
+		formValueStyle.Render(fmt.Sprintf("%q", m.search.input)) + " " +
+		flags + "  " + result
+}
@@ -0,0 +1,192 @@
+package ui
+
+import (
+	"OmniView/internal/adapter/ui/styles"
+	"OmniView/internal/core/domain"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// newTestSearchModel returns a ready main-screen model holding count messages,
+// where every tenth payload contains "ORA-00942".
+func newTestSearchModel(t *testing.T, count int) *Model {
+	t.Helper()
+
+	m := newTestMainModel(t, 140, 30)
+	m.initViewport()
+	for i := 0; i < count; i++ {
+		payload := fmt.Sprintf("step %d ok", i)
+		if i%10 == 0 {
+			payload = fmt.Sprintf("step %d failed: ORA-00942 table or view does not exist", i)
+		}
+		msg, err := domain.NewQueueMessage(fmt.Sprintf("id-%d", i), "ORDER_API", domain.LogLevelInfo, payload, time.Unix(1700000000, 0))
+		if err != nil {
+			t.Fatalf("NewQueueMessage: %v", err)
+		}
+		m.Update(queueMessageMsg{message: msg})
+	}
+	return m
+}
+
+func typeSearch(m *Model, query string) {
+	m.Update(makeCharPress("/"))
+	for _, r := range query {
+		m.Update(makeCharPress(string(r)))
+	}
+}
+
+func TestSearch_IncrementalHighlightAndEnterJumps(t *testing.T) {
+	t.Parallel()
+
+	m := newTestSearchModel(t, 100)
+	typeSearch(m, "ora-00942")
+
+	if !m.search.prompt {
+		t.Fatal("expected / to open the search prompt")
+	}
+	if len(m.search.matches) != 10 {
+		t.Fatalf("expected 10 case-insensitive matches while typing, got %d", len(m.search.matches))
+	}
+	if !strings.Contains(m.main.renderedLines[0], styles.SearchMatchStyle.Render("ORA-00942")) {
+		t.Fatal("expected the match to be highlighted in the rendered trace columns")
+	}
+	if !m.main.autoScroll {
+		t.Fatal("typing the query should not pause auto-scroll")
+	}
+
+	m.main.viewport.GotoTop()
+	m.Update(makeKeyPress(tea.KeyEnter))
+
+	if m.search.prompt {
+		t.Fatal("expected Enter to close the prompt")
+	}
+	if m.main.autoScroll {
+		t.Fatal("expected jumping to a match to pause auto-scroll")
+	}
+	if m.search.current != 0 || m.search.currentMsg.MessageID() != "id-0" {
+		t.Fatalf("expected the first match to be focused, got %d", m.search.current)
+	}
+
+	m.Update(makeCharPress("n"))
+	if m.search.currentMsg.MessageID() != "id-10" {
+		t.Fatalf("n focused %q, want id-10", m.search.currentMsg.MessageID())
+	}
+	if got, want := m.main.viewport.YOffset(), m.renderedLineOffset(10)-searchJumpContext; got != want {
+		t.Fatalf("viewport YOffset = %d, want %d", got, want)
+	}
+	if !strings.Contains(m.main.renderedLines[10], styles.SearchCurrentMatchStyle.Render("ORA-00942")) {
+		t.Fatal("expected the focused match to use the current-match style")
+	}
+	if strings.Contains(m.main.renderedLines[0], styles.SearchCurrentMatchStyle.Render("ORA-00942")) {
+		t.Fatal("expected the previously focused match to lose the current-match style")
+	}
+
+	m.Update(makeCharPress("N"))
+	m.Update(makeCharPress("N"))
+	if m.search.currentMsg.MessageID() != "id-90" {
+		t.Fatalf("N should wrap around to the last match, got %q", m.search.currentMsg.MessageID())
+	}
+}
+
+func TestSearch_CaseAndRegexToggles(t *testing.T) {
+	t.Parallel()
+
+	m := newTestSearchModel(t, 20)
+	typeSearch(m, "ora-")
+
+	m.Update(makeKeyPress(tea.KeyTab))
+	if !m.search.caseSensitive || len(m.search.matches) != 0 {
+		t.Fatalf("expected Tab to enable case-sensitive matching, got %d matches", len(m.search.matches))
+	}
+	m.Update(makeKeyPress(tea.KeyTab))
+
+	m.Update(tea.KeyPressMsg{Code: 'u', Mod: tea.ModCtrl})
+	for _, r := range `ORA-\d+ (` {
+		m.Update(makeCharPress(string(r)))
+	}
+	if len(m.search.matches) != 0 || m.search.err != "" {
+		t.Fatal("a literal query with regex metacharacters should match literally")
+	}
+
+	m.Update(tea.KeyPressMsg{Code: 'r', Mod: tea.ModCtrl})
+	if !m.search.regex || m.search.err == "" {
+		t.Fatal("expected Ctrl+R to enable regex mode and report the unbalanced group")
+	}
+	if !strings.Contains(m.mainStatusText(), "invalid pattern") {
+		t.Fatal("expected the regex error to be shown inline in the prompt")
+	}
+
+	m.Update(makeKeyPress(tea.KeyBackspace))
+	m.Update(makeKeyPress(tea.KeyBackspace))
+	if m.search.err != "" || len(m.search.matches) != 2 {
+		t.Fatalf("expected the regex to match 2 messages, got %d (err %q)", len(m.search.matches), m.search.err)
+	}
+}
+
+func TestSearch_TracksNewMessagesAndClearsOnEsc(t *testing.T) {
+	t.Parallel()
+
+	m := newTestSearchModel(t, 10)
+	typeSearch(m, "ORA-")
+	m.Update(makeKeyPress(tea.KeyEnter))
+
+	msg, err := domain.NewQueueMessage("late", "ORDER_API", domain.LogLevelError, "late ORA-01403", time.Unix(1700000100, 0))
+	if err != nil {
+		t.Fatalf("NewQueueMessage: %v", err)
+	}
+	m.Update(queueMessageMsg{message: msg})
+	if len(m.search.matches) != 2 || m.search.matches[1] != len(m.main.renderedLines)-1 {
+		t.Fatalf("expected the appended message to be recorded as a match, got %v", m.search.matches)
+	}
+
+	m.Update(makeKeyPress(tea.KeyEscape))
+	if m.search.matcher != nil || len(m.search.matches) != 0 {
+		t.Fatal("expected Esc to clear the active search")
+	}
+	if strings.Contains(m.main.renderedLines[0], styles.SearchMatchStyle.Render("ORA-")) {
+		t.Fatal("expected highlights to be removed after clearing the search")
+	}
+}
+
+func TestSearch_QTypedIntoPromptDoesNotQuit(t *testing.T) {
+	t.Parallel()
+
+	m := newTestSearchModel(t, 1)
+	m.Update(makeCharPress("/"))
+	if _, cmd := m.Update(makeCharPress("q")); cmd != nil {
+		t.Fatal("expected q to be typed into the search prompt")
+	}
+	if m.search.input != "q" {
+		t.Fatalf("search input = %q, want q", m.search.input)
+	}
+}
+
+func TestRebuildRenderedContent_KeepsWrappedEntriesIntact(t *testing.T) {
+	t.Parallel()
+
+	m := newTestMainModel(t, 100, 30)
+	m.initViewport()
+	for i := 0; i < 9; i++ {
+		payload := "short"
+		if i == 4 {
+			payload = strings.Repeat("word ", 40)
+		}
+		msg, err := domain.NewQueueMessage(fmt.Sprintf("id-%d", i), "P", domain.LogLevelInfo, payload, time.Unix(1700000000, 0))
+		if err != nil {
+			t.Fatalf("NewQueueMessage: %v", err)
+		}
+		m.Update(queueMessageMsg{message: msg})
+	}
+
+	wrapped := strings.Count(m.main.renderedLines[4], "\n")
+	if wrapped == 0 {
+		t.Fatal("expected the long payload to wrap onto continuation lines")
+	}
+	if got, want := m.main.viewport.TotalLineCount(), len(m.main.renderedLines)+wrapped; got != want {
+		t.Fatalf("viewport has %d lines, want %d", got, want)
+	}
+}
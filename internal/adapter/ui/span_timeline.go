@@ -0,0 +1,335 @@
+package ui
+
+import (
+	"OmniView/internal/adapter/ui/styles"
+	"OmniView/internal/core/domain"
+	"fmt"
+	"strings"
+	"time"
+
+	"charm.land/bubbles/v2/viewport"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+)
+
+const (
+	// timelineMaxWidth caps the span timeline overlay on wide terminals.
+	timelineMaxWidth = 140
+	// Column widths of a timeline row; the bar takes the rest.
+	timelineMaxNameWidth  = 32
+	timelineDurationWidth = 8
+	timelineMinBarWidth   = 10
+)
+
+// ==========================================
+// Span Timeline Sub-State
+// ==========================================
+
+// spanTimelineState holds the "v" overlay that draws the buffered spans as a
+// waterfall per process.
+type spanTimelineState struct {
+	visible bool
+	view    viewport.Model
+}
+
+// timelineRow is one span in the waterfall with its nesting depth.
+type timelineRow struct {
+	span  *domain.Span
+	depth int
+}
+
+// ==========================================
+// Helpers
+// ==========================================
+
+// spanPrefix renders the marker leading the payload of a Trace_Begin or
+// Trace_End message. END markers carry the span duration once the matching
+// BEGIN message has been seen.
+func (m *Model) spanPrefix(msg *domain.QueueMessage) string {
+	marker, ok := msg.Span()
+	if !ok {
+		return ""
+	}
+	if marker.Event == domain.SpanEventBegin {
+		return styles.SpanMarkerStyle.Render("▶") + " "
+	}
+	prefix := styles.SpanMarkerStyle.Render("◀") + " "
+	if m.main.spans == nil {
+		return prefix
+	}
+	if span, ok := m.main.spans.Span(marker.ID); ok {
+		if d, ok := span.Duration(); ok {
+			prefix += styles.SpanDurationStyle.Render("("+formatSpanDuration(d)+")") + " "
+		}
+	}
+	return prefix
+}
+
+// formatSpanDuration renders d with millisecond precision below a minute.
+func formatSpanDuration(d time.Duration) string {
+	switch {
+	case d < time.Millisecond:
+		return "<1ms"
+	case d < time.Second:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	case d < time.Minute:
+		return fmt.Sprintf("%.2fs", d.Seconds())
+	default:
+		return d.Round(time.Second).String()
+	}
+}
+
+// spanDetailLines renders the span fields of the detail pane for a
+// Trace_Begin or Trace_End message.
+func (m *Model) spanDetailLines(msg *domain.QueueMessage, label func(string) string) []string {
+	marker, ok := msg.Span()
+	if !ok {
+		return nil
+	}
+	value := styles.BodyTextStyle
+	lines := []string{label("Span") + value.Render(strings.TrimSpace(fmt.Sprintf("%s %s", marker.Event, sanitizeLogString(marker.Name))))}
+	lines = append(lines, label("Span ID")+value.Render(sanitizeLogString(marker.ID)))
+	if marker.ParentID != "" {
+		lines = append(lines, label("Parent Span ID")+value.Render(sanitizeLogString(marker.ParentID)))
+	}
+	if m.main.spans == nil {
+		return lines
+	}
+	if span, ok := m.main.spans.Span(marker.ID); ok {
+		duration := "open"
+		if d, ok := span.Duration(); ok {
+			duration = formatSpanDuration(d)
+		} else if !span.Open() {
+			duration = "unknown (begin not buffered)"
+		}
+		lines = append(lines, label("Span Duration")+styles.SpanDurationStyle.Render(duration))
+	}
+	return lines
+}
+
+// openSpanTimeline shows the waterfall of the buffered spans.
+func (m *Model) openSpanTimeline() {
+	m.timeline.visible = true
+	m.timeline.view = viewport.New()
+	m.resizeSpanTimeline()
+	m.timeline.view.GotoTop()
+}
+
+// spanTimelineSize returns the overlay width and the body viewport size.
+func (m *Model) spanTimelineSize() (panelWidth, bodyWidth, bodyHeight int) {
+	contentWidth, contentHeight := screenContentSize(m.width, m.height)
+	panelWidth = max(min(contentWidth-4, timelineMaxWidth), 1)
+	// Frame (2), spacing (2) and the key hint (2).
+	return panelWidth, max(panelWidth-4, 1), max(contentHeight-8, 3)
+}
+
+// resizeSpanTimeline fits the overlay to the terminal and re-renders it.
+func (m *Model) resizeSpanTimeline() {
+	if !m.timeline.visible {
+		return
+	}
+	_, bodyWidth, bodyHeight := m.spanTimelineSize()
+	m.timeline.view.SetWidth(bodyWidth)
+	m.timeline.view.SetHeight(bodyHeight)
+	m.refreshSpanTimeline()
+}
+
+// refreshSpanTimeline re-renders the waterfall, keeping the scroll position.
+func (m *Model) refreshSpanTimeline() {
+	if !m.timeline.visible {
+		return
+	}
+	offset := m.timeline.view.YOffset()
+	m.timeline.view.SetContent(m.renderSpanTimeline(m.timeline.view.Width()))
+	m.timeline.view.SetYOffset(offset)
+}
+
+// timelineRows orders the spans of one process depth-first, children after
+// their parent by start time. Spans whose parent is not buffered are roots.
+func timelineRows(spans []*domain.Span) []timelineRow {
+	byID := make(map[string]bool, len(spans))
+	for _, span := range spans {
+		byID[span.ID] = true
+	}
+	children := make(map[string][]*domain.Span)
+	var roots []*domain.Span
+	for _, span := range spans {
+		if span.ParentID != "" && span.ParentID != span.ID && byID[span.ParentID] {
+			children[span.ParentID] = append(children[span.ParentID], span)
+		} else {
+			roots = append(roots, span)
+		}
+	}
+
+	rows := make([]timelineRow, 0, len(spans))
+	visited := make(map[string]bool, len(spans))
+	var walk func(span *domain.Span, depth int)
+	walk = func(span *domain.Span, depth int) {
+		// Guard against parent cycles from hand-written span IDs.
+		if visited[span.ID] {
+			return
+		}
+		visited[span.ID] = true
+		rows = append(rows, timelineRow{span: span, depth: depth})
+		for _, child := range children[span.ID] {
+			walk(child, depth+1)
+		}
+	}
+	for _, root := range roots {
+		walk(root, 0)
+	}
+	return rows
+}
+
+// ==========================================
+// Update
+// ==========================================
+
+// updateSpanTimeline handles keyboard input while the timeline is open.
+func (m *Model) updateSpanTimeline(msg tea.KeyPressMsg) (*Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		m.cancel()
+		return m, tea.Quit
+	case "esc", "v", "q":
+		m.timeline.visible = false
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.timeline.view, cmd = m.timeline.view.Update(msg)
+	return m, cmd
+}
+
+// ==========================================
+// View
+// ==========================================
+
+// renderSpanTimeline draws one waterfall per process. Each process gets its
+// own time scale from its first span start to its last span end; spans that
+// are still open extend to the newest buffered message.
+func (m *Model) renderSpanTimeline(width int) string {
+	if m.main.spans == nil || m.main.spans.Len() == 0 {
+		return styles.BodyTextStyle.Width(width).Render("No spans buffered yet. Open one with OMNI_TRACER_API.Trace_Begin and close it with Trace_End.")
+	}
+
+	now := time.Time{}
+	if n := len(m.main.messages); n > 0 {
+		now = m.main.messages[n-1].Timestamp()
+	}
+
+	var processes []string
+	byProcess := make(map[string][]*domain.Span)
+	for _, span := range m.main.spans.Spans() {
+		if _, ok := byProcess[span.Process]; !ok {
+			processes = append(processes, span.Process)
+		}
+		byProcess[span.Process] = append(byProcess[span.Process], span)
+	}
+
+	nameWidth := min(timelineMaxNameWidth, max(width/3, 8))
+	barWidth := max(width-nameWidth-timelineDurationWidth-2*len(colSeparator), timelineMinBarWidth)
+
+	var lines []string
+	for i, process := range processes {
+		spans := byProcess[process]
+		start, end := timelineWindow(spans, now)
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		title := sanitizeLogString(process)
+		if title == "" {
+			title = "(no process)"
+		}
+		noun := "spans"
+		if len(spans) == 1 {
+			noun = "span"
+		}
+		lines = append(lines, truncateRendered(styles.LogProcessStyle.Render(title)+
+			styles.SubtitleStyle.Render(fmt.Sprintf("  %d %s  •  %s", len(spans), noun, formatSpanDuration(end.Sub(start)))), width))
+
+		for _, row := range timelineRows(spans) {
+			name := strings.Repeat("  ", row.depth) + sanitizeLogString(row.span.Name)
+			if row.span.Name == "" {
+				name = strings.Repeat("  ", row.depth) + sanitizeLogString(row.span.ID)
+			}
+			cells := []string{
+				lipgloss.NewStyle().Width(nameWidth).Render(truncate(name, nameWidth)),
+				timelineBar(row.span, start, end, now, barWidth),
+				lipgloss.NewStyle().Width(timelineDurationWidth).Align(lipgloss.Right).Render(timelineDuration(row.span)),
+			}
+			lines = append(lines, truncateRendered(strings.Join(cells, colSeparator), width))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// timelineWindow returns the time range covered by spans.
+func timelineWindow(spans []*domain.Span, now time.Time) (start, end time.Time) {
+	for _, span := range spans {
+		from, to := spanExtent(span, now)
+		if start.IsZero() || from.Before(start) {
+			start = from
+		}
+		if to.After(end) {
+			end = to
+		}
+	}
+	return start, end
+}
+
+// spanExtent returns the drawn range of span. A span without a buffered BEGIN
+// message is drawn as a point at its end.
+func spanExtent(span *domain.Span, now time.Time) (from, to time.Time) {
+	from, to = span.Start, span.End
+	if from.IsZero() {
+		from = to
+	}
+	if to.IsZero() {
+		to = now
+		if to.Before(from) {
+			to = from
+		}
+	}
+	return from, to
+}
+
+// timelineBar draws span as a bar of width cells scaled to [start, end].
+func timelineBar(span *domain.Span, start, end, now time.Time, width int) string {
+	from, to := spanExtent(span, now)
+	total := end.Sub(start)
+	offset, length := 0, width
+	if total > 0 {
+		offset = int(int64(width) * int64(from.Sub(start)) / int64(total))
+		length = int(int64(width) * int64(to.Sub(from)) / int64(total))
+	}
+	offset = min(max(offset, 0), width-1)
+	length = min(max(length, 1), width-offset)
+
+	glyph, style := "█", styles.SpanBarStyle
+	if span.Open() {
+		glyph, style = "▒", styles.SpanOpenBarStyle
+	}
+	return strings.Repeat(" ", offset) + style.Render(strings.Repeat(glyph, length)) + strings.Repeat(" ", width-offset-length)
+}
+
+// timelineDuration labels the duration column of a timeline row.
+func timelineDuration(span *domain.Span) string {
+	if d, ok := span.Duration(); ok {
+		return formatSpanDuration(d)
+	}
+	if span.Open() {
+		return "open"
+	}
+	return "?"
+}
+
+// viewSpanTimeline renders the timeline overlay.
+func (m *Model) viewSpanTimeline() string {
+	panelWidth, bodyWidth, _ := m.spanTimelineSize()
+	parts := []string{
+		m.timeline.view.View(),
+		"",
+		styles.OnboardingHintStyle.Width(bodyWidth).Render("↑/↓ Scroll  •  █ Closed  •  ▒ Open  •  Esc Close"),
+	}
+	return renderFramedPanel("Span Timeline", panelWidth, panelTypeInfo, lipgloss.JoinVertical(lipgloss.Left, parts...))
+}
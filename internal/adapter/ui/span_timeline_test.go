@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"OmniView/internal/core/domain"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// newTestSpanModel returns a ready main-screen model holding a closed "batch"
+// span with a nested closed "order" span, and an open "audit" span in another process.
+func newTestSpanModel(t *testing.T) *Model {
+	t.Helper()
+
+	m := newTestMainModel(t, 160, 40)
+	m.initViewport()
+	start := time.Unix(1700000000, 0)
+	send := func(id, process string, offset time.Duration, marker domain.SpanMarker) {
+		t.Helper()
+		payload := "Begin " + marker.Name
+		if marker.Event == domain.SpanEventEnd {
+			payload = "End " + marker.Name
+		}
+		msg, err := domain.NewQueueMessage(id, process, domain.LogLevelInfo, payload, start.Add(offset))
+		if err != nil {
+			t.Fatalf("NewQueueMessage: %v", err)
+		}
+		msg.SetSpan(marker)
+		m.Update(queueMessageMsg{message: msg})
+	}
+	send("1", "ORDER_API", 0, domain.SpanMarker{ID: "B", Name: "batch", Event: domain.SpanEventBegin})
+	send("2", "ORDER_API", 100*time.Millisecond, domain.SpanMarker{ID: "O", ParentID: "B", Name: "order", Event: domain.SpanEventBegin})
+	send("3", "AUDIT_API", 200*time.Millisecond, domain.SpanMarker{ID: "A", Name: "audit", Event: domain.SpanEventBegin})
+	send("4", "ORDER_API", 350*time.Millisecond, domain.SpanMarker{ID: "O", Name: "order", Event: domain.SpanEventEnd})
+	send("5", "ORDER_API", 1500*time.Millisecond, domain.SpanMarker{ID: "B", Name: "batch", Event: domain.SpanEventEnd})
+	return m
+}
+
+func TestSpans_FeedShowsMarkersAndDurations(t *testing.T) {
+	t.Parallel()
+
+	m := newTestSpanModel(t)
+	if first := ansi.Strip(m.main.renderedLines[0]); !strings.Contains(first, "▶ Begin batch") {
+		t.Fatalf("expected a begin marker, got %q", first)
+	}
+	if end := ansi.Strip(m.main.renderedLines[3]); !strings.Contains(end, "◀ (250ms) End order") {
+		t.Fatalf("expected the nested span duration, got %q", end)
+	}
+	if end := ansi.Strip(m.main.renderedLines[4]); !strings.Contains(end, "◀ (1.50s) End batch") {
+		t.Fatalf("expected the outer span duration, got %q", end)
+	}
+
+	body := ansi.Strip(m.messageDetailBody(m.main.messages[3], 80))
+	for _, want := range []string{"Span            END order", "Span ID         O", "Span Duration   250ms"} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("detail body is missing %q:\n%s", want, body)
+		}
+	}
+
+	m.Update(makeCharPress("c"))
+	if m.main.spans != nil {
+		t.Fatal("expected clearing the feed to drop the tracked spans")
+	}
+}
+
+func TestSpanTimeline_WaterfallPerProcess(t *testing.T) {
+	t.Parallel()
+
+	m := newTestSpanModel(t)
+	m.Update(makeCharPress("v"))
+	if !m.timeline.visible {
+		t.Fatal("expected v to open the span timeline")
+	}
+
+	lines := strings.Split(ansi.Strip(m.renderSpanTimeline(100)), "\n")
+	want := []struct{ prefix, suffix string }{
+		{"ORDER_API  2 spans  •  1.50s", ""},
+		{"batch ", "1.50s"},
+		{"  order ", "250ms"},
+		{"", ""},
+		{"AUDIT_API  1 span", ""},
+		{"audit ", "open"},
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("timeline has %d lines, want %d:\n%s", len(lines), len(want), strings.Join(lines, "\n"))
+	}
+	for i, w := range want {
+		line := strings.TrimRight(lines[i], " ")
+		if !strings.HasPrefix(line, w.prefix) || !strings.HasSuffix(line, w.suffix) {
+			t.Fatalf("line %d = %q, want prefix %q and suffix %q", i, line, w.prefix, w.suffix)
+		}
+	}
+	// The nested span starts after and ends before its parent.
+	batch, order := strings.Index(lines[1], "█"), strings.Index(lines[2], "█")
+	if order <= batch || strings.LastIndex(lines[2], "█") >= strings.LastIndex(lines[1], "█") {
+		t.Fatalf("expected the order bar inside the batch bar:\n%s\n%s", lines[1], lines[2])
+	}
+	if !strings.Contains(lines[5], "▒") {
+		t.Fatalf("expected an open bar for audit, got %q", lines[5])
+	}
+
+	if _, cmd := m.Update(makeCharPress("q")); cmd != nil || m.timeline.visible {
+		t.Fatal("expected q to close the timeline without quitting")
+	}
+}
+
+func TestFormatSpanDuration(t *testing.T) {
+	t.Parallel()
+
+	tests := map[time.Duration]string{
+		400 * time.Microsecond:  "<1ms",
+		42 * time.Millisecond:   "42ms",
+		1250 * time.Millisecond: "1.25s",
+		90 * time.Second:        "1m30s",
+	}
+	for d, want := range tests {
+		if got := formatSpanDuration(d); got != want {
+			t.Errorf("formatSpanDuration(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -154,6 +154,78 @@ var (
 	ProcedureCallStyle = lipgloss.NewStyle().
 				Foreground(ApiCallerColor).
 				Bold(true)
+
+	SearchMatchStyle = lipgloss.NewStyle().
+				Foreground(BackgroundColor).
+				Background(ConnectionBorderColor)
+
+	SearchCurrentMatchStyle = lipgloss.NewStyle().
+				Foreground(BackgroundColor).
+				Background(WarningColor).
+				Bold(true)
+
+	SelectedRowStyle = lipgloss.NewStyle().
+				Foreground(BackgroundColor).
+				Background(SelectionColor).
+				Bold(true)
+
+	PinnedMessageStyle = lipgloss.NewStyle().
+				Foreground(ConnectionBorderColor).
+				Bold(true)
+)
+
+// ==========================================
+// Payload Syntax Styles
+// ==========================================
+
+var (
+	SyntaxKeyStyle = lipgloss.NewStyle().
+			Foreground(AccentColor)
+
+	SyntaxStringStyle = lipgloss.NewStyle().
+				Foreground(SecondaryColor)
+
+	SyntaxNumberStyle = lipgloss.NewStyle().
+				Foreground(WarningColor)
+
+	SyntaxLiteralStyle = lipgloss.NewStyle().
+				Foreground(CriticalColor)
+
+	SyntaxPunctuationStyle = lipgloss.NewStyle().
+				Foreground(MutedColor)
+
+	SyntaxKeywordStyle = lipgloss.NewStyle().
+				Foreground(PrimaryColor).
+				Bold(true)
+
+	SyntaxCommentStyle = lipgloss.NewStyle().
+				Foreground(MutedColor).
+				Italic(true)
+
+	SyntaxTagStyle = lipgloss.NewStyle().
+			Foreground(AccentColor)
+
+	SyntaxAttrStyle = lipgloss.NewStyle().
+			Foreground(ApiCallerColor)
+)
+
+// ==========================================
+// Span Styles
+// ==========================================
+
+var (
+	SpanMarkerStyle = lipgloss.NewStyle().
+			Foreground(AccentColor).
+			Bold(true)
+
+	SpanDurationStyle = lipgloss.NewStyle().
+				Foreground(ConnectionBorderColor)
+
+	SpanBarStyle = lipgloss.NewStyle().
+			Foreground(SecondaryColor)
+
+	SpanOpenBarStyle = lipgloss.NewStyle().
+				Foreground(WarningColor)
 )
 
 // ==========================================
@@ -0,0 +1,163 @@
+package ui
+
+import (
+	"OmniView/internal/adapter/logger"
+	"OmniView/internal/adapter/ui/styles"
+	"OmniView/internal/core/domain"
+	"strings"
+	"unicode/utf8"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+)
+
+// ==========================================
+// Trace Filter Sub-State
+// ==========================================
+
+// traceFilterState holds the main-screen filter expression and its "f" prompt.
+type traceFilterState struct {
+	active *domain.TraceFilter // Applied filter; nil shows every message
+	prompt bool                // Whether the "f" prompt is accepting input
+	input  string              // Expression being edited
+	err    error               // Parse error for the current input, if any
+}
+
+// ==========================================
+// Helpers
+// ==========================================
+
+// loadTraceFilter restores the filter saved for the active database. A stored
+// expression that no longer parses is logged and ignored.
+func (m *Model) loadTraceFilter() {
+	m.traceFilter = traceFilterState{}
+	if m.boltAdapter == nil || m.appConfig == nil {
+		return
+	}
+	expr, err := m.boltAdapter.GetTraceFilter(m.appConfig.DatabaseID())
+	if err != nil {
+		logger.Warn("failed to load trace filter", "databaseID", m.appConfig.DatabaseID(), "error", err)
+		return
+	}
+	filter, err := domain.ParseTraceFilter(expr)
+	if err != nil {
+		logger.Warn("ignoring invalid saved trace filter", "databaseID", m.appConfig.DatabaseID(), "expression", expr, "error", err)
+		return
+	}
+	m.traceFilter.active = filter
+	m.traceFilter.input = filter.String()
+}
+
+// openTraceFilterPrompt starts editing the filter, seeded with the active expression.
+func (m *Model) openTraceFilterPrompt() {
+	m.traceFilter.prompt = true
+	m.traceFilter.input = m.traceFilter.active.String()
+	m.traceFilter.err = nil
+}
+
+// validateTraceFilterInput re-parses the prompt input so errors show while typing.
+func (m *Model) validateTraceFilterInput() {
+	_, m.traceFilter.err = domain.ParseTraceFilter(m.traceFilter.input)
+}
+
+// applyTraceFilter makes filter the active filter, saves it for the current
+// database and re-renders the viewport.
+func (m *Model) applyTraceFilter(filter *domain.TraceFilter) {
+	m.traceFilter.active = filter
+	m.traceFilter.input = filter.String()
+	if m.boltAdapter != nil && m.appConfig != nil {
+		if err := m.boltAdapter.SetTraceFilter(m.appConfig.DatabaseID(), filter.String()); err != nil {
+			logger.Error("failed to save trace filter", "databaseID", m.appConfig.DatabaseID(), "error", err)
+		}
+	}
+	if m.main.ready {
+		m.rebuildRenderedContent(m.main.viewport.Width())
+		if m.main.autoScroll {
+			m.main.viewport.GotoBottom()
+		}
+	}
+}
+
+// ==========================================
+// Update
+// ==========================================
+
+// updateTraceFilterPrompt handles keyboard and paste input while the "f" prompt
+// is open. Enter applies a valid expression (an empty one clears the filter);
+// Esc discards the edit and keeps the active filter.
+func (m *Model) updateTraceFilterPrompt(msg tea.Msg) (*Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.PasteMsg:
+		m.traceFilter.input += sanitizePasteInput(msg.Content)
+		m.validateTraceFilterInput()
+		return m, nil
+
+	case tea.KeyPressMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			m.cancel()
+			return m, tea.Quit
+		case "esc":
+			m.traceFilter.prompt = false
+			m.traceFilter.input = m.traceFilter.active.String()
+			m.traceFilter.err = nil
+			return m, nil
+		case "enter":
+			filter, err := domain.ParseTraceFilter(m.traceFilter.input)
+			if err != nil {
+				m.traceFilter.err = err
+				return m, nil
+			}
+			m.traceFilter.prompt = false
+			m.traceFilter.err = nil
+			m.applyTraceFilter(filter)
+			return m, nil
+		case "ctrl+u":
+			m.traceFilter.input = ""
+			m.validateTraceFilterInput()
+			return m, nil
+		case "backspace":
+			if value := m.traceFilter.input; len(value) > 0 {
+				_, size := utf8.DecodeLastRuneInString(value)
+				m.traceFilter.input = value[:len(value)-size]
+				m.validateTraceFilterInput()
+			}
+			return m, nil
+		}
+
+		if len(msg.Text) > 0 && !msg.Mod.Contains(tea.ModCtrl) {
+			m.traceFilter.input += msg.Text
+			m.validateTraceFilterInput()
+		}
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// ==========================================
+// View
+// ==========================================
+
+// traceFilterStatusText renders the prompt with any parse error while editing,
+// or the active expression. Returns an empty string when no filter is set.
+func (m *Model) traceFilterStatusText() string {
+	if m.traceFilter.prompt {
+		parts := []string{
+			formCursorStyle.Render("filter: ") + formValueStyle.Render(m.traceFilter.input) + formCursorStyle.Render("_"),
+		}
+		switch {
+		case m.traceFilter.err != nil:
+			parts = append(parts, styles.OnboardingErrorStyle.Render(m.traceFilter.err.Error()))
+		case strings.TrimSpace(m.traceFilter.input) == "":
+			parts = append(parts, styles.SubtitleStyle.Render("enter clears the filter"))
+		}
+		return strings.Join(parts, "  ")
+	}
+
+	if m.traceFilter.active == nil {
+		return ""
+	}
+	return styles.BodyTextStyle.Render("Filter ") +
+		lipgloss.NewStyle().Foreground(styles.AccentColor).Render(m.traceFilter.active.String())
+}
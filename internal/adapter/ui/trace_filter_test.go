@@ -0,0 +1,146 @@
+package ui
+
+import (
+	"OmniView/internal/core/domain"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// newTestFilterModel returns a ready main-screen model backed by a real BoltDB
+// store, holding messages that alternate between INFO and ERROR levels.
+func newTestFilterModel(t *testing.T, databaseID string, count int) *Model {
+	t.Helper()
+
+	m := newTestMainModel(t, 140, 30)
+	m.boltAdapter = newTestBoltAdapter(t)
+	m.appConfig = newTestDatabaseSettings(t, databaseID)
+	m.initViewport()
+	for i := 0; i < count; i++ {
+		pushFilterTestMessage(t, m, fmt.Sprintf("id-%d", i), i%2 == 1)
+	}
+	return m
+}
+
+func pushFilterTestMessage(t *testing.T, m *Model, id string, isError bool) {
+	t.Helper()
+
+	level, process := domain.LogLevelInfo, "BILLING_RUN"
+	if isError {
+		level, process = domain.LogLevelError, "ORDER_SUBMIT"
+	}
+	msg, err := domain.NewQueueMessage(id, process, level, "request timeout", time.Unix(1700000000, 0))
+	if err != nil {
+		t.Fatalf("NewQueueMessage: %v", err)
+	}
+	m.Update(queueMessageMsg{message: msg})
+}
+
+func typeTraceFilter(m *Model, expr string) {
+	m.Update(makeCharPress("f"))
+	m.Update(tea.KeyPressMsg{Code: 'u', Mod: tea.ModCtrl})
+	for _, r := range expr {
+		m.Update(makeCharPress(string(r)))
+	}
+}
+
+func TestTraceFilter_AppliesToRebuildAndAppendPaths(t *testing.T) {
+	t.Parallel()
+
+	m := newTestFilterModel(t, "DEV", 10)
+	typeTraceFilter(m, `level>=WARNING and process~"ORDER_"`)
+	if len(m.main.renderedLines) != 10 {
+		t.Fatal("the filter should not apply until Enter is pressed")
+	}
+	m.Update(makeKeyPress(tea.KeyEnter))
+
+	if m.traceFilter.prompt {
+		t.Fatal("expected Enter to close the filter prompt")
+	}
+	if len(m.main.renderedLines) != 5 {
+		t.Fatalf("expected 5 ERROR lines after filtering, got %d", len(m.main.renderedLines))
+	}
+	if len(m.main.messages) != 10 {
+		t.Fatal("filtering must not drop buffered messages")
+	}
+
+	pushFilterTestMessage(t, m, "late-info", false)
+	pushFilterTestMessage(t, m, "late-error", true)
+	if len(m.main.renderedLines) != 6 {
+		t.Fatalf("expected only the matching new message to be appended, got %d lines", len(m.main.renderedLines))
+	}
+	if !strings.Contains(m.mainStatusText(), `process~"ORDER_"`) {
+		t.Fatal("expected the active filter to be shown in the status bar")
+	}
+}
+
+func TestTraceFilter_ParseErrorShownInline(t *testing.T) {
+	t.Parallel()
+
+	m := newTestFilterModel(t, "DEV", 4)
+	typeTraceFilter(m, "level >= LOUD")
+
+	if m.traceFilter.err == nil {
+		t.Fatal("expected the unknown level to be reported while typing")
+	}
+	if status := m.mainStatusText(); !strings.Contains(status, `unknown level "LOUD" at column 10`) {
+		t.Fatalf("expected inline parse error with its column, got %q", status)
+	}
+
+	m.Update(makeKeyPress(tea.KeyEnter))
+	if !m.traceFilter.prompt || m.traceFilter.active != nil {
+		t.Fatal("Enter on an invalid expression should keep the prompt open and not apply it")
+	}
+
+	m.Update(makeKeyPress(tea.KeyEscape))
+	if m.traceFilter.prompt || m.traceFilter.err != nil || len(m.main.renderedLines) != 4 {
+		t.Fatal("Esc should discard the invalid edit and leave the feed unfiltered")
+	}
+}
+
+func TestTraceFilter_PersistedPerDatabase(t *testing.T) {
+	t.Parallel()
+
+	m := newTestFilterModel(t, "DEV", 2)
+	typeTraceFilter(m, "level = ERROR")
+	m.Update(makeKeyPress(tea.KeyEnter))
+
+	if got, err := m.boltAdapter.GetTraceFilter("DEV"); err != nil || got != "level = ERROR" {
+		t.Fatalf("saved filter = %q, %v", got, err)
+	}
+
+	// Switching to another database starts unfiltered; switching back restores it.
+	m.appConfig = newTestDatabaseSettings(t, "PROD")
+	m.loadTraceFilter()
+	if m.traceFilter.active != nil {
+		t.Fatalf("expected no filter for PROD, got %q", m.traceFilter.active)
+	}
+	m.appConfig = newTestDatabaseSettings(t, "DEV")
+	m.loadTraceFilter()
+	if m.traceFilter.active.String() != "level = ERROR" {
+		t.Fatalf("expected the DEV filter to be restored, got %q", m.traceFilter.active)
+	}
+
+	// An empty expression clears the saved filter.
+	typeTraceFilter(m, "")
+	m.Update(makeKeyPress(tea.KeyEnter))
+	if got, _ := m.boltAdapter.GetTraceFilter("DEV"); got != "" || m.traceFilter.active != nil {
+		t.Fatalf("expected the filter to be cleared, still have %q", got)
+	}
+}
+
+func TestTraceFilter_QTypedIntoPromptDoesNotQuit(t *testing.T) {
+	t.Parallel()
+
+	m := newTestFilterModel(t, "DEV", 1)
+	m.Update(makeCharPress("f"))
+	if _, cmd := m.Update(makeCharPress("q")); cmd != nil {
+		t.Fatal("expected q to be typed into the filter prompt")
+	}
+	if m.traceFilter.input != "q" {
+		t.Fatalf("filter input = %q, want q", m.traceFilter.input)
+	}
+}
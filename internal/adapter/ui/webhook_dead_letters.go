@@ -0,0 +1,229 @@
+package ui
+
+import (
+	"OmniView/internal/adapter/ui/styles"
+	"OmniView/internal/core/domain"
+	"OmniView/internal/service/tracer"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+)
+
+// ==========================================
+// Webhook Dead Letters View
+// ==========================================
+// Lists the webhook deliveries the dispatcher saved after failing to send
+// them, so they can be replayed once the endpoint is fixed or discarded.
+// Part of the webhook settings panel; Esc returns to the webhook list.
+
+// openDeadLetters shows the dead-letter list.
+func (m *Model) openDeadLetters() {
+	state := &m.webhookSettings
+	state.view = webhooksViewDeadLetters
+	state.deadLetterCursor = 0
+	state.dialog.clear()
+	if m.deadLetters == nil {
+		state.dialog.set("Webhook dead letters are not available in this session.", true)
+	}
+}
+
+// applyDeadLetters updates the list after a replay or discard.
+func (m *Model) applyDeadLetters(msg deadLettersChangedMsg) {
+	state := &m.webhookSettings
+	if msg.deliveries != nil {
+		state.deadLetters = msg.deliveries
+		state.deadLetterCursor = max(min(state.deadLetterCursor, len(state.deadLetters)-1), 0)
+	}
+	switch {
+	case msg.err != nil:
+		state.dialog.set(msg.err.Error(), true)
+	case msg.replayed == 1:
+		state.dialog.set("Queued 1 delivery again.", false)
+	case msg.replayed > 1:
+		state.dialog.set(fmt.Sprintf("Queued %d deliveries again.", msg.replayed), false)
+	default:
+		state.dialog.clear()
+	}
+}
+
+// ==========================================
+// Update
+// ==========================================
+
+// updateDeadLetters handles keys on the dead-letter list.
+func (m *Model) updateDeadLetters(msg tea.KeyPressMsg) (*Model, tea.Cmd) {
+	state := &m.webhookSettings
+
+	switch msg.String() {
+	case "esc", "q":
+		if msg.String() == "esc" && state.dialog.visible {
+			state.dialog.clear()
+			return m, nil
+		}
+		state.view = webhooksViewList
+		state.dialog.clear()
+		return m, nil
+	case "up", "shift+tab":
+		if state.deadLetterCursor > 0 {
+			state.deadLetterCursor--
+		}
+	case "down", "tab":
+		if state.deadLetterCursor < len(state.deadLetters)-1 {
+			state.deadLetterCursor++
+		}
+	case "enter", "r":
+		if delivery, ok := state.selectedDeadLetter(); ok {
+			return m, m.replayDeadLettersCmd([]domain.WebhookDelivery{delivery})
+		}
+	case "a":
+		if len(state.deadLetters) > 0 {
+			return m, m.replayDeadLettersCmd(state.deadLetters)
+		}
+	case "delete", "d":
+		if delivery, ok := state.selectedDeadLetter(); ok {
+			return m, m.discardDeadLetterCmd(delivery.ID)
+		}
+	}
+	state.dialog.clear()
+	return m, nil
+}
+
+// selectedDeadLetter returns the delivery under the cursor.
+func (s *webhookSettingsState) selectedDeadLetter() (domain.WebhookDelivery, bool) {
+	if s.deadLetterCursor < 0 || s.deadLetterCursor >= len(s.deadLetters) {
+		return domain.WebhookDelivery{}, false
+	}
+	return s.deadLetters[s.deadLetterCursor], true
+}
+
+// ==========================================
+// View
+// ==========================================
+
+// viewDeadLetters renders the saved deliveries and details of the selected one.
+func (m *Model) viewDeadLetters() string {
+	state := m.webhookSettings
+	layout := state.layout
+	innerWidth := layout.innerWidth
+
+	rows := make([]string, 0, len(state.deadLetters))
+	for i, delivery := range state.deadLetters {
+		marker := "  "
+		if i == state.deadLetterCursor {
+			marker = formCursorStyle.Render("› ")
+		}
+		target := delivery.WebhookID
+		if target == "" {
+			target = delivery.URL
+		}
+		text := styles.LogTimestampStyle.Render(delivery.SavedAt.Format("2006-01-02 15:04:05")) + " " +
+			styles.BodyTextStyle.Render(sanitizeLogString(target))
+		if delivery.LogLevel != "" {
+			text += styles.SubtitleStyle.Render("  " + sanitizeLogString(delivery.LogLevel))
+		}
+		text += styles.SubtitleStyle.Render("  " + string(delivery.Reason) + " after " + attemptsLabel(delivery.Attempts))
+		rows = append(rows, truncateRendered(marker+text, innerWidth))
+	}
+	if len(rows) == 0 {
+		rows = append(rows, formPlaceholder.Render("No undelivered webhook messages."))
+	}
+
+	parts := make([]string, 0, 8)
+	if layout.showSubtitle {
+		parts = append(parts, styles.SubtitleStyle.Width(innerWidth).Render("Webhook messages that could not be sent. Replaying sends to the webhook's current URL."))
+		if !layout.compact {
+			parts = append(parts, "")
+		}
+	}
+	parts = append(parts, renderEmbeddedField(embeddedFieldOptions{
+		Label:   "Undelivered",
+		Value:   strings.Join(rows, "\n"),
+		Width:   innerWidth,
+		Focused: true,
+	}))
+
+	if delivery, ok := state.selectedDeadLetter(); ok {
+		detail := "URL: " + sanitizeLogString(delivery.URL)
+		if delivery.LastError != "" {
+			detail += "\nLast error: " + sanitizeLogString(delivery.LastError)
+		}
+		parts = append(parts, styles.SubtitleStyle.Width(innerWidth).Render(detail))
+	}
+
+	parts = append(parts, renderSettingsDialogLines(state.dialog, innerWidth)...)
+
+	if !state.dialog.visible && layout.showHint {
+		if !layout.compact {
+			parts = append(parts, "")
+		}
+		parts = append(parts, styles.OnboardingHintStyle.Width(innerWidth).Render("↑/↓ Navigate  •  Enter/R Replay  •  A Replay All  •  D Discard  •  Esc Back"))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
+
+func attemptsLabel(attempts int) string {
+	if attempts == 1 {
+		return "1 attempt"
+	}
+	return strconv.Itoa(attempts) + " attempts"
+}
+
+// ==========================================
+// Async Commands
+// ==========================================
+
+// replayDeadLettersCmd queues deliveries again, in order, stopping at the
+// first that cannot be queued. A delivery whose webhook still exists is sent
+// with that webhook's current URL, format and credentials.
+func (m *Model) replayDeadLettersCmd(deliveries []domain.WebhookDelivery) tea.Cmd {
+	ctx := m.ctx
+	boltAdapter := m.boltAdapter
+
+	return m.changeDeadLettersCmd(func() (int, error) {
+		replayed := 0
+		for _, delivery := range deliveries {
+			if err := tracer.ReplayWebhookDeadLetter(ctx, delivery, boltAdapter); err != nil {
+				return replayed, fmt.Errorf("replay dead letter: %w", err)
+			}
+			replayed++
+		}
+		return replayed, nil
+	})
+}
+
+// discardDeadLetterCmd removes a delivery without sending it.
+func (m *Model) discardDeadLetterCmd(id string) tea.Cmd {
+	repo, ctx := m.deadLetters, m.ctx
+
+	return m.changeDeadLettersCmd(func() (int, error) {
+		if err := repo.DeleteDeadLetter(ctx, id); err != nil {
+			return 0, fmt.Errorf("discard dead letter: %w", err)
+		}
+		return 0, nil
+	})
+}
+
+// changeDeadLettersCmd returns an async command that runs change and lists
+// the deliveries still stored.
+func (m *Model) changeDeadLettersCmd(change func() (int, error)) tea.Cmd {
+	repo, ctx := m.deadLetters, m.ctx
+
+	return func() tea.Msg {
+		if repo == nil {
+			return deadLettersChangedMsg{err: errors.New("webhook dead letters are not available in this session")}
+		}
+		replayed, err := change()
+		deliveries, listErr := repo.ListDeadLetters(ctx)
+		if listErr != nil {
+			return deadLettersChangedMsg{replayed: replayed, err: errors.Join(err, fmt.Errorf("load dead letters: %w", listErr))}
+		}
+		if deliveries == nil {
+			deliveries = []domain.WebhookDelivery{}
+		}
+		return deadLettersChangedMsg{deliveries: deliveries, replayed: replayed, err: err}
+	}
+}
@@ -0,0 +1,119 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+
+	"OmniView/internal/adapter/storage/boltdb"
+	"OmniView/internal/core/domain"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// runDeadLetterKey presses key in the dead-letter view and feeds the result
+// of its command back.
+func runDeadLetterKey(t *testing.T, m *Model, key tea.KeyPressMsg) deadLettersChangedMsg {
+	t.Helper()
+	_, cmd := m.updateWebhookSettings(key)
+	if cmd == nil {
+		t.Fatalf("expected %q to return a command", key.String())
+	}
+	msg, ok := cmd().(deadLettersChangedMsg)
+	if !ok {
+		t.Fatal("expected the command to return deadLettersChangedMsg")
+	}
+	m.updateWebhookSettings(msg)
+	return msg
+}
+
+func TestWebhookDeadLetters_ListAndDiscard(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModelForWebhookSettings(t)
+	repo := boltdb.NewDeadLetterRepository(m.boltAdapter)
+	m.deadLetters = repo
+	for _, webhookID := range []string{"ops", "alerts"} {
+		if err := repo.SaveDeadLetter(m.ctx, &domain.WebhookDelivery{
+			WebhookID: webhookID,
+			URL:       "https://example.com/" + webhookID,
+			Payload:   "{}",
+			LogLevel:  "CRITICAL",
+			Attempts:  5,
+			LastError: "webhook returned non-success status: 503",
+			Reason:    domain.DeadLetterRetriesExhausted,
+		}); err != nil {
+			t.Fatalf("SaveDeadLetter: %v", err)
+		}
+	}
+
+	m.openWebhookSettings()
+	if view := m.viewWebhookSettings(); !containsAll(view, "Dead Letters (2)") {
+		t.Fatalf("expected the list to count the dead letters, got:\n%s", view)
+	}
+	m.webhookSettings.cursor = webhookBtnDeadLetters
+	m.updateWebhookSettings(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if m.webhookSettings.view != webhooksViewDeadLetters {
+		t.Fatalf("expected the dead-letter view, got view=%d", m.webhookSettings.view)
+	}
+	if view := m.viewWebhookSettings(); !containsAll(view, "ops", "alerts", "retries_exhausted after 5 attempts", "status: 503") {
+		t.Fatalf("expected both deliveries and the selected error, got:\n%s", view)
+	}
+
+	m.updateWebhookSettings(tea.KeyPressMsg{Code: tea.KeyDown})
+	if msg := runDeadLetterKey(t, m, makeCharPress("d")); msg.err != nil {
+		t.Fatalf("discard: %v", msg.err)
+	}
+	stored, err := repo.ListDeadLetters(m.ctx)
+	if err != nil {
+		t.Fatalf("ListDeadLetters: %v", err)
+	}
+	if len(stored) != 1 || stored[0].WebhookID != "ops" || len(m.webhookSettings.deadLetters) != 1 {
+		t.Fatalf("expected only ops to remain, stored=%+v shown=%+v", stored, m.webhookSettings.deadLetters)
+	}
+	if m.webhookSettings.deadLetterCursor != 0 {
+		t.Fatalf("expected the cursor to move back onto the remaining entry, got %d", m.webhookSettings.deadLetterCursor)
+	}
+
+	m.updateWebhookSettings(tea.KeyPressMsg{Code: tea.KeyEscape})
+	if m.webhookSettings.view != webhooksViewList || !m.webhookSettings.visible {
+		t.Fatalf("expected Esc to return to the webhook list, got view=%d", m.webhookSettings.view)
+	}
+}
+
+// The tracer has no dead-letter store in UI tests, so a replay cannot queue
+// anything; the entry must then stay where it is.
+func TestWebhookDeadLetters_FailedReplayKeepsEntry(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModelForWebhookSettings(t)
+	repo := boltdb.NewDeadLetterRepository(m.boltAdapter)
+	m.deadLetters = repo
+	if err := repo.SaveDeadLetter(m.ctx, &domain.WebhookDelivery{URL: "https://example.com/hook", Payload: "{}", Reason: domain.DeadLetterRejected}); err != nil {
+		t.Fatalf("SaveDeadLetter: %v", err)
+	}
+	m.openWebhookSettings()
+	m.openDeadLetters()
+
+	msg := runDeadLetterKey(t, m, makeCharPress("r"))
+	if !errors.Is(msg.err, domain.ErrNilRepository) || msg.replayed != 0 {
+		t.Fatalf("replay = %d, %v; want 0, ErrNilRepository", msg.replayed, msg.err)
+	}
+	if len(m.webhookSettings.deadLetters) != 1 || !m.webhookSettings.dialog.isError {
+		t.Fatalf("expected the entry kept and the error shown, got %+v dialog=%+v", m.webhookSettings.deadLetters, m.webhookSettings.dialog)
+	}
+}
+
+func TestWebhookDeadLetters_UnavailableWithoutStore(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModelForWebhookSettings(t)
+	m.openWebhookSettings()
+	m.openDeadLetters()
+
+	if !m.webhookSettings.dialog.visible || !m.webhookSettings.dialog.isError {
+		t.Fatal("expected an error when no dead-letter store is configured")
+	}
+	if _, cmd := m.updateWebhookSettings(makeCharPress("a")); cmd != nil {
+		t.Fatal("expected Replay All to do nothing without entries")
+	}
+}
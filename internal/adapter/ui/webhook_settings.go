@@ -1,11 +1,19 @@
 package ui
 
 import (
+	"OmniView/internal/adapter/logger"
 	"OmniView/internal/adapter/ui/styles"
 	"OmniView/internal/core/domain"
+	"OmniView/internal/core/ports"
+	"OmniView/internal/service/tracer"
+	"OmniView/internal/service/webhook"
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
-	"unicode/utf8"
+	"time"
 
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
@@ -14,50 +22,130 @@ import (
 // ==========================================
 // Webhook Settings Sub-State
 // ==========================================
+// Lists the named webhooks stored in BoltDB and edits them one at a time.
+// Trace_Message_To_Webhook picks a webhook by ID through target_; messages
+// without a target go to the default webhook, marked with a star.
+// Each webhook's signing secret, credentials and mTLS files are edited on a
+// separate Security form, its batching on a Batching form and its
+// suppression and rate limit on a Limits form. Deliveries
+// that could not be sent are listed under Dead Letters. Preview shows the
+// request body a webhook's payload format gives for a sample message.
 
 const (
-	webhookFieldURL = iota
-	webhookBtnSave
-	webhookBtnCancel
-	webhookMaxCursor = webhookBtnCancel
+	webhooksViewList = iota
+	webhooksViewForm
+	webhooksViewAuth
+	webhooksViewBatching
+	webhooksViewLimits
+	webhooksViewDeadLetters
+	webhooksViewPreview
+)
+
+// Webhook form fields
+const (
+	webhookFieldID = iota
+	webhookFieldURL
+	webhookFieldLevel
+	webhookFieldFormat
+	webhookFieldTemplate
+	webhookFieldEnabled
+)
+
+// Webhook security form fields
+const (
+	webhookAuthFieldSecret = iota
+	webhookAuthFieldBearer
+	webhookAuthFieldHeaders
+	webhookAuthFieldClientCert
+	webhookAuthFieldClientKey
+	webhookAuthFieldCABundle
+)
+
+// Webhook batching form fields
+const (
+	webhookBatchFieldWindow = iota
+	webhookBatchFieldMax
+	webhookBatchFieldCritical
+)
+
+// Webhook limits form fields
+const (
+	webhookLimitFieldSuppress = iota
+	webhookLimitFieldRate
+	webhookLimitFieldBurst
+)
+
+// Buttons below the list, after one row per webhook
+const (
+	webhookBtnAdd = iota
+	webhookBtnOutputs
+	webhookBtnDeadLetters
+	webhookBtnClose
+	webhookButtonCount
 )
 
 type webhookSettingsState struct {
-	config  *domain.WebhookConfig
-	visible bool
-	cursor  int
-	input   string
-	dialog  settingsDialog
-	layout  webhookSettingsLayout
+	visible   bool
+	view      int
+	cursor    int // Row in the list view: webhooks, then buttons
+	webhooks  []domain.WebhookConfig
+	defaultID string // Webhook used for messages without a target; empty when none
+	form      outputForm
+	dialog    settingsDialog
+	layout    webhookSettingsLayout
+
+	deadLetters      []domain.WebhookDelivery // Deliveries that could not be sent, oldest first
+	deadLetterCursor int
+
+	preview webhookPreview
+}
+
+// webhookPreview is a sample request body shown by the preview view.
+type webhookPreview struct {
+	title    string
+	format   domain.WebhookFormat
+	template string
+	digest   bool   // Shows a digest of a sample batch instead of one message
+	body     string // Indented when the body is JSON
+	err      error  // Why the format could not render the sample
+	back     int    // View Esc returns to
 }
 
 type webhookSettingsLayout struct {
-	panelWidth        int
-	innerWidth        int
-	compact           bool
-	showSubtitle      bool
-	showCurrentStatus bool
-	showHint          bool
+	panelWidth   int
+	innerWidth   int
+	compact      bool
+	showSubtitle bool
+	showHint     bool
 }
 
 // ==========================================
 // Helpers
 // ==========================================
 
-// initWebhookSettings initializes the webhook settings sub-state with the given config.
-func (m *Model) initWebhookSettings(config *domain.WebhookConfig) {
-	input := ""
-	if config != nil {
-		input = config.URL
-	}
+// openWebhookSettings loads the stored webhooks and shows the panel.
+func (m *Model) openWebhookSettings() {
+	m.webhookSettings = webhookSettingsState{visible: true}
+	m.resizeWebhookSettings(m.width, m.height)
 
-	m.webhookSettings = webhookSettingsState{
-		config:  config,
-		visible: true,
-		input:   input,
+	webhooks, defaultID, err := loadWebhooks(m.boltAdapter)
+	if err != nil {
+		logger.Error("failed to load webhook configs", "error", err)
+		m.webhookSettings.dialog.set(err.Error(), true)
+		return
 	}
+	m.webhookSettings.webhooks = webhooks
+	m.webhookSettings.defaultID = defaultID
 
-	m.resizeWebhookSettings(m.width, m.height)
+	if m.deadLetters != nil {
+		deliveries, err := m.deadLetters.ListDeadLetters(m.ctx)
+		if err != nil {
+			logger.Error("failed to load webhook dead letters", "error", err)
+			m.webhookSettings.dialog.set(err.Error(), true)
+			return
+		}
+		m.webhookSettings.deadLetters = deliveries
+	}
 }
 
 // resizeWebhookSettings resizes the webhook settings panel to the given dimensions.
@@ -68,15 +156,13 @@ func (m *Model) resizeWebhookSettings(width, height int) {
 
 	_, contentHeight := screenContentSize(width, height)
 	panelWidth := settingsPanelWidth(width)
-	innerWidth := max(panelWidth-4, 1)
 
 	m.webhookSettings.layout = webhookSettingsLayout{
-		panelWidth:        panelWidth,
-		innerWidth:        innerWidth,
-		compact:           contentHeight <= 16,
-		showSubtitle:      contentHeight >= 10,
-		showCurrentStatus: contentHeight >= 14,
-		showHint:          contentHeight >= 12,
+		panelWidth:   panelWidth,
+		innerWidth:   max(panelWidth-4, 1),
+		compact:      contentHeight <= 24,
+		showSubtitle: contentHeight >= 10,
+		showHint:     contentHeight >= 12,
 	}
 }
 
@@ -85,118 +171,332 @@ func (m *Model) closeWebhookSettings() {
 	m.webhookSettings = webhookSettingsState{}
 }
 
-// clearWebhookSettingsDialog dismisses the error/info dialog in the webhook settings panel.
-func (m *Model) clearWebhookSettingsDialog() {
+// webhookRowCount returns the number of selectable rows in the list view.
+func (s *webhookSettingsState) webhookRowCount() int {
+	return len(s.webhooks) + webhookButtonCount
+}
+
+// editWebhook shows the webhook form, filled from config when editing. The
+// first webhook added is offered the default ID.
+func (m *Model) editWebhook(config *domain.WebhookConfig) {
+	levels := []string{anyLevel}
+	for _, level := range domain.LogLevels() {
+		levels = append(levels, string(level))
+	}
+	var formats []string
+	for _, format := range domain.WebhookFormats() {
+		formats = append(formats, format.Label())
+	}
+	form := outputForm{fields: []outputField{
+		webhookFieldID:       {label: "Webhook ID", placeholder: "ops", footer: "Letters, digits, '.', '_' or '-'. PL/SQL targets it with target_ => 'ID'.", kind: outputFieldText},
+		webhookFieldURL:      {label: "Webhook URL", placeholder: "https://example.com/webhook", kind: outputFieldText},
+		webhookFieldLevel:    {label: "Minimum Level", footer: "Less severe messages are not sent to this webhook.", kind: outputFieldChoice, choices: levels, value: anyLevel},
+		webhookFieldFormat:   {label: "Payload Format", footer: "Ctrl+P previews the request body.", kind: outputFieldChoice, choices: formats, value: domain.WebhookFormatJSON.Label()},
+		webhookFieldTemplate: {label: "Template", placeholder: `{"text": {{json .Payload}}}`, footer: "Go text/template, used by Custom template. Fields: .Level .Process .Payload .Timestamp .Attributes ...", kind: outputFieldText},
+		webhookFieldEnabled:  {label: "Webhook", kind: outputFieldToggle, value: "Send messages to this webhook", checked: true},
+	}}
+	if len(m.webhookSettings.webhooks) == 0 {
+		form.fields[webhookFieldID].value = domain.DefaultWebhookID
+	}
+	if config != nil {
+		form.original = config.ID
+		form.fields[webhookFieldID].value = config.ID
+		form.fields[webhookFieldURL].value = config.URL
+		if config.MinLevel != "" {
+			form.fields[webhookFieldLevel].value = string(config.MinLevel)
+		}
+		form.fields[webhookFieldFormat].value = config.Format.Label()
+		form.fields[webhookFieldTemplate].value = config.Template
+		form.fields[webhookFieldEnabled].checked = config.Enabled
+	}
+	m.webhookSettings.form = form
+	m.webhookSettings.view = webhooksViewForm
+	m.webhookSettings.dialog.clear()
+}
+
+// editWebhookAuth shows the security form for config.
+func (m *Model) editWebhookAuth(config *domain.WebhookConfig) {
+	auth := config.Auth
+	m.webhookSettings.form = outputForm{
+		original: config.ID,
+		fields: []outputField{
+			webhookAuthFieldSecret:     {label: "Signing Secret", placeholder: "none", footer: "Signs each request with HMAC-SHA256 in " + domain.WebhookSignatureHeader + ".", kind: outputFieldText, value: auth.SigningSecret, masked: true},
+			webhookAuthFieldBearer:     {label: "Bearer Token", placeholder: "none", kind: outputFieldText, value: auth.BearerToken, masked: true},
+			webhookAuthFieldHeaders:    {label: "Headers", placeholder: "X-Api-Key=abc123,X-Team=ops", footer: "Comma-separated key=value pairs; URL-encode commas in values.", kind: outputFieldText, value: auth.HeadersString()},
+			webhookAuthFieldClientCert: {label: "Client Certificate", placeholder: "/path/to/client.pem", footer: "PEM files for mTLS; leave empty to connect without one.", kind: outputFieldText, value: auth.ClientCert},
+			webhookAuthFieldClientKey:  {label: "Client Key", placeholder: "/path/to/client-key.pem", kind: outputFieldText, value: auth.ClientKey},
+			webhookAuthFieldCABundle:   {label: "CA Bundle", placeholder: "system roots", footer: "PEM file with the CAs that may sign the server certificate.", kind: outputFieldText, value: auth.CABundle},
+		},
+	}
+	m.webhookSettings.view = webhooksViewAuth
+	m.webhookSettings.dialog.clear()
+}
+
+// editWebhookBatching shows the batching form for config.
+func (m *Model) editWebhookBatching(config *domain.WebhookConfig) {
+	form := outputForm{
+		original: config.ID,
+		fields: []outputField{
+			webhookBatchFieldWindow:   {label: "Batch Window (seconds)", placeholder: "off", footer: fmt.Sprintf("Messages are held and sent as one digest per window, %s to %s. Empty sends each message at once.", domain.MinWebhookBatchWindow, domain.MaxWebhookBatchWindow), kind: outputFieldText},
+			webhookBatchFieldMax:      {label: "Batch Size", placeholder: "no limit", footer: fmt.Sprintf("Sends the digest early once it holds this many messages, up to %d.", domain.MaxWebhookBatchMessages), kind: outputFieldText},
+			webhookBatchFieldCritical: {label: "CRITICAL", kind: outputFieldToggle, value: "Send CRITICAL messages at once", checked: true},
+		},
+	}
+	if batching := config.Batching; batching.Enabled() {
+		form.fields[webhookBatchFieldWindow].value = strconv.FormatFloat(batching.Window.Seconds(), 'f', -1, 64)
+		if batching.MaxMessages > 0 {
+			form.fields[webhookBatchFieldMax].value = strconv.Itoa(batching.MaxMessages)
+		}
+		form.fields[webhookBatchFieldCritical].checked = batching.CriticalImmediate
+	}
+	m.webhookSettings.form = form
+	m.webhookSettings.view = webhooksViewBatching
+	m.webhookSettings.dialog.clear()
+}
+
+// editWebhookLimits shows the suppression and rate limit form for config.
+func (m *Model) editWebhookLimits(config *domain.WebhookConfig) {
+	form := outputForm{
+		original: config.ID,
+		fields: []outputField{
+			webhookLimitFieldSuppress: {label: "Suppress Repeats (seconds)", placeholder: "off", footer: fmt.Sprintf("Repeats of a sent message, ignoring numbers and IDs, are counted for this long and reported in one follow-up, %s to %s.", domain.MinWebhookSuppressWindow, domain.MaxWebhookSuppressWindow), kind: outputFieldText},
+			webhookLimitFieldRate:     {label: "Rate Limit (requests/minute)", placeholder: "no limit", footer: fmt.Sprintf("Requests to the URL beyond this wait their turn, up to %d per minute.", domain.MaxWebhookRatePerMinute), kind: outputFieldText},
+			webhookLimitFieldBurst:    {label: "Burst", placeholder: "one minute's worth", footer: "Requests sent back to back before the rate limit applies.", kind: outputFieldText},
+		},
+	}
+	limits := config.Limits
+	if limits.Suppresses() {
+		form.fields[webhookLimitFieldSuppress].value = strconv.FormatFloat(limits.SuppressWindow.Seconds(), 'f', -1, 64)
+	}
+	if limits.RateLimited() {
+		form.fields[webhookLimitFieldRate].value = strconv.Itoa(limits.RatePerMinute)
+		form.fields[webhookLimitFieldBurst].value = strconv.Itoa(limits.Burst)
+	}
+	m.webhookSettings.form = form
+	m.webhookSettings.view = webhooksViewLimits
 	m.webhookSettings.dialog.clear()
 }
 
+// showWebhookPreview shows the sample message, or a sample digest, in format.
+func (m *Model) showWebhookPreview(id string, format domain.WebhookFormat, template string, digest bool) {
+	state := &m.webhookSettings
+	preview := webhookPreview{title: format.Label(), format: format, template: strings.TrimSpace(template), digest: digest, back: state.view}
+	if id != "" {
+		preview.title = id + " - " + preview.title
+	}
+	preview.render()
+	state.preview = preview
+	state.view = webhooksViewPreview
+	state.dialog.clear()
+}
+
+// render lays out the sample for the preview's format.
+func (p *webhookPreview) render() {
+	body, err := webhook.Preview(p.format, p.template, p.digest)
+	if err == nil {
+		var indented bytes.Buffer
+		if json.Indent(&indented, body, "", "  ") == nil {
+			body = indented.Bytes()
+		}
+	}
+	p.body, p.err = string(body), err
+}
+
+// webhookFormatFromLabel returns the format a Payload Format choice names.
+func webhookFormatFromLabel(label string) domain.WebhookFormat {
+	for _, format := range domain.WebhookFormats() {
+		if format.Label() == label {
+			return format
+		}
+	}
+	return domain.WebhookFormatJSON
+}
+
 // ==========================================
 // Update
 // ==========================================
 
 // updateWebhookSettings handles keyboard and paste input for the webhook settings panel.
 func (m *Model) updateWebhookSettings(msg tea.Msg) (*Model, tea.Cmd) {
+	state := &m.webhookSettings
+
 	switch msg := msg.(type) {
+	case webhooksChangedMsg:
+		if msg.err != nil {
+			state.dialog.set(msg.err.Error(), true)
+			return m, nil
+		}
+		state.webhooks, state.defaultID = msg.webhooks, msg.defaultID
+		state.view = webhooksViewList
+		state.cursor = min(state.cursor, state.webhookRowCount()-1)
+		state.dialog.clear()
+		return m, nil
+
+	case deadLettersChangedMsg:
+		m.applyDeadLetters(msg)
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.resizeWebhookSettings(msg.Width, msg.Height)
+		return m, nil
+
 	case tea.PasteMsg:
-		if m.webhookSettings.cursor == webhookFieldURL {
-			m.webhookSettings.input += sanitizePasteInput(msg.Content)
-			m.clearWebhookSettingsDialog()
+		if state.view == webhooksViewForm || state.view == webhooksViewAuth || state.view == webhooksViewBatching || state.view == webhooksViewLimits {
+			if field := state.form.focusedField(); field != nil && field.kind == outputFieldText {
+				field.value += sanitizePasteInput(msg.Content)
+				state.dialog.clear()
+			}
 		}
 		return m, nil
 
 	case tea.KeyPressMsg:
-		switch msg.String() {
-		case "ctrl+c":
+		if msg.String() == "ctrl+c" {
 			m.cancel()
 			return m, tea.Quit
-		case "q", "esc":
-			// Allow 'q' to be typed in the URL field
-			if msg.String() == "q" && m.webhookSettings.cursor == webhookFieldURL {
-				m.webhookSettings.input += "q"
-				m.clearWebhookSettingsDialog()
-				return m, nil
-			}
-			if m.webhookSettings.dialog.visible {
-				m.clearWebhookSettingsDialog()
-				return m, nil
+		}
+		switch state.view {
+		case webhooksViewList:
+			return m.updateWebhookList(msg)
+		case webhooksViewDeadLetters:
+			return m.updateDeadLetters(msg)
+		case webhooksViewPreview:
+			switch msg.String() {
+			case "esc", "q":
+				state.view = state.preview.back
+			case "tab":
+				state.preview.digest = !state.preview.digest
+				state.preview.render()
 			}
-			m.closeWebhookSettings()
 			return m, nil
-		case "up", "shift+tab":
-			if m.webhookSettings.cursor > 0 {
-				m.webhookSettings.cursor--
-			}
-			m.clearWebhookSettingsDialog()
+		}
+		return m.updateWebhookForm(msg)
+	}
+
+	return m, nil
+}
+
+// updateWebhookList handles keys on the webhook list.
+func (m *Model) updateWebhookList(msg tea.KeyPressMsg) (*Model, tea.Cmd) {
+	state := &m.webhookSettings
+	rows := state.webhookRowCount()
+	button := state.cursor - len(state.webhooks)
+
+	switch msg.String() {
+	case "esc", "q":
+		if msg.String() == "esc" && state.dialog.visible {
+			state.dialog.clear()
 			return m, nil
-		case "down":
-			if m.webhookSettings.cursor < webhookMaxCursor {
-				m.webhookSettings.cursor++
-			}
-			m.clearWebhookSettingsDialog()
+		}
+		m.closeWebhookSettings()
+		return m, nil
+	case "up", "shift+tab":
+		if state.cursor > 0 {
+			state.cursor--
+		}
+	case "down":
+		if state.cursor < rows-1 {
+			state.cursor++
+		}
+	case "tab":
+		state.cursor = (state.cursor + 1) % rows
+	case "enter":
+		switch {
+		case button < 0:
+			m.editWebhook(&state.webhooks[state.cursor])
+		case button == webhookBtnAdd:
+			m.editWebhook(nil)
+		case button == webhookBtnOutputs:
+			m.closeWebhookSettings()
+			m.openOutputSettings()
+		case button == webhookBtnDeadLetters:
+			m.openDeadLetters()
+		case button == webhookBtnClose:
+			m.closeWebhookSettings()
+		}
+		return m, nil
+	case "space":
+		if button < 0 {
+			config := state.webhooks[state.cursor]
+			config.Enabled = !config.Enabled
+			return m, m.changeWebhooksCmd(func(repo ports.ConfigRepository) error {
+				return repo.SaveWebhookConfig(&config)
+			})
+		}
+	case "a":
+		if button < 0 {
+			m.editWebhookAuth(&state.webhooks[state.cursor])
 			return m, nil
-		case "tab":
-			m.webhookSettings.cursor = (m.webhookSettings.cursor + 1) % (webhookMaxCursor + 1)
-			m.clearWebhookSettingsDialog()
+		}
+	case "p":
+		if button < 0 {
+			config := state.webhooks[state.cursor]
+			m.showWebhookPreview(config.ID, config.Format, config.Template, config.Batching.Enabled())
 			return m, nil
-		case "enter":
-			switch m.webhookSettings.cursor {
-			case webhookBtnCancel:
-				m.closeWebhookSettings()
-				return m, nil
-			case webhookBtnSave:
-				return m, m.saveWebhookSettingsCmd()
-			default:
-				m.webhookSettings.cursor = webhookBtnSave
-				m.clearWebhookSettingsDialog()
-				return m, nil
-			}
-		case "backspace":
-			if m.webhookSettings.cursor == webhookFieldURL {
-				value := m.webhookSettings.input
-				if len(value) > 0 {
-					_, size := utf8.DecodeLastRuneInString(value)
-					if size > 0 {
-						m.webhookSettings.input = value[:len(value)-size]
-					}
-				}
-				m.clearWebhookSettingsDialog()
-			}
+		}
+	case "b":
+		if button < 0 {
+			m.editWebhookBatching(&state.webhooks[state.cursor])
 			return m, nil
-		case "ctrl+u":
-			if m.webhookSettings.cursor == webhookFieldURL {
-				m.webhookSettings.input = ""
-				m.clearWebhookSettingsDialog()
-			}
+		}
+	case "l":
+		if button < 0 {
+			m.editWebhookLimits(&state.webhooks[state.cursor])
 			return m, nil
 		}
-
-		if m.webhookSettings.cursor == webhookFieldURL && len(msg.Text) > 0 && !msg.Mod.Contains(tea.ModCtrl) {
-			m.webhookSettings.input += msg.Text
-			m.clearWebhookSettingsDialog()
+	case "*":
+		if button < 0 {
+			id := state.webhooks[state.cursor].ID
+			return m, m.changeWebhooksCmd(func(repo ports.ConfigRepository) error {
+				return repo.SetDefaultWebhook(id)
+			})
 		}
-		return m, nil
-
-	case webhookConfigSavedMsg:
-		if msg.err != nil {
-			m.webhookSettings.dialog.set(msg.err.Error(), true)
-			return m, nil
+	case "delete", "d":
+		if button < 0 {
+			id := state.webhooks[state.cursor].ID
+			return m, m.changeWebhooksCmd(func(repo ports.ConfigRepository) error {
+				return repo.DeleteWebhookConfig(id)
+			})
 		}
+	}
+	state.dialog.clear()
+	return m, nil
+}
 
-		if msg.deleted {
-			m.webhookSettings.config = nil
-			m.closeWebhookSettings()
+// updateWebhookForm handles keys on the webhook and security forms.
+func (m *Model) updateWebhookForm(msg tea.KeyPressMsg) (*Model, tea.Cmd) {
+	state := &m.webhookSettings
+
+	if msg.String() == "esc" {
+		if state.dialog.visible {
+			state.dialog.clear()
 			return m, nil
 		}
-
-		m.webhookSettings.config = msg.config
-		m.closeWebhookSettings()
+		state.view = webhooksViewList
 		return m, nil
-
-	case tea.WindowSizeMsg:
-		m.resizeWebhookSettings(msg.Width, msg.Height)
+	}
+	if msg.String() == "ctrl+p" && state.view == webhooksViewForm {
+		fields := state.form.fields
+		m.showWebhookPreview(strings.TrimSpace(fields[webhookFieldID].value), webhookFormatFromLabel(fields[webhookFieldFormat].value), fields[webhookFieldTemplate].value, false)
 		return m, nil
 	}
 
+	switch state.form.update(msg) {
+	case formKeySave:
+		switch state.view {
+		case webhooksViewAuth:
+			return m, m.saveWebhookAuthCmd()
+		case webhooksViewBatching:
+			return m, m.saveWebhookBatchingCmd()
+		case webhooksViewLimits:
+			return m, m.saveWebhookLimitsCmd()
+		}
+		return m, m.saveWebhookCmd()
+	case formKeyCancel:
+		state.view = webhooksViewList
+		state.dialog.clear()
+	case formKeyHandled:
+		state.dialog.clear()
+	}
 	return m, nil
 }
 
@@ -212,28 +512,69 @@ func (m *Model) viewWebhookSettings() string {
 		layout = m.webhookSettings.layout
 	}
 
-	panelWidth := layout.panelWidth
-	innerWidth := layout.innerWidth
-
-	currentWebhook := styles.EmptyStateStyle.Render("No webhook configured.")
-	if m.webhookSettings.config != nil && strings.TrimSpace(m.webhookSettings.config.URL) != "" {
-		currentWebhook = styles.BodyTextStyle.Render(m.webhookSettings.config.URL)
+	switch m.webhookSettings.view {
+	case webhooksViewForm:
+		return renderFramedPanel("Webhook", layout.panelWidth, panelTypeInfo, m.viewWebhookForm())
+	case webhooksViewAuth:
+		return renderFramedPanel("Webhook Security: "+sanitizeLogString(m.webhookSettings.form.original), layout.panelWidth, panelTypeInfo, m.viewWebhookForm())
+	case webhooksViewBatching:
+		return renderFramedPanel("Webhook Batching: "+sanitizeLogString(m.webhookSettings.form.original), layout.panelWidth, panelTypeInfo, m.viewWebhookForm())
+	case webhooksViewLimits:
+		return renderFramedPanel("Webhook Limits: "+sanitizeLogString(m.webhookSettings.form.original), layout.panelWidth, panelTypeInfo, m.viewWebhookForm())
+	case webhooksViewDeadLetters:
+		return renderFramedPanel("Dead Letters", layout.panelWidth, panelTypeInfo, m.viewDeadLetters())
+	case webhooksViewPreview:
+		return renderFramedPanel("Preview: "+sanitizeLogString(m.webhookSettings.preview.title), layout.panelWidth, panelTypeInfo, m.viewWebhookPreview())
 	}
+	return renderFramedPanel("Settings", layout.panelWidth, panelTypeInfo, m.viewWebhookList())
+}
 
-	inputValue := strings.TrimSpace(m.webhookSettings.input)
-	editValue := formPlaceholder.Render("https://example.com/webhook")
-	if inputValue != "" {
-		editValue = formValueStyle.Render(m.webhookSettings.input)
-	}
-	if m.webhookSettings.cursor == webhookFieldURL {
-		if inputValue == "" {
-			editValue = formPlaceholder.Render("https://example.com/webhook") + formCursorStyle.Render("_")
-		} else {
-			editValue = formValueStyle.Render(m.webhookSettings.input) + formCursorStyle.Render("_")
+// viewWebhookList renders the webhooks and the buttons below them.
+func (m *Model) viewWebhookList() string {
+	state := m.webhookSettings
+	layout := state.layout
+	innerWidth := layout.innerWidth
+
+	rows := make([]string, 0, len(state.webhooks))
+	for i, config := range state.webhooks {
+		marker := "  "
+		if i == state.cursor {
+			marker = formCursorStyle.Render("› ")
 		}
+		check := styles.SubtitleStyle.Render("[ ]")
+		if config.Enabled {
+			check = lipgloss.NewStyle().Foreground(styles.SuccessColor).Render("[x]")
+		}
+		text := styles.BodyTextStyle.Render(sanitizeLogString(config.ID))
+		if config.ID == state.defaultID {
+			text += lipgloss.NewStyle().Foreground(styles.SuccessColor).Render(" ★")
+		}
+		if config.MinLevel != "" {
+			text += styles.SubtitleStyle.Render("  " + string(config.MinLevel) + "+")
+		}
+		if config.Format != domain.WebhookFormatJSON {
+			text += styles.SubtitleStyle.Render("  " + config.Format.Label())
+		}
+		if config.Batching.Enabled() {
+			text += styles.SubtitleStyle.Render("  batched " + config.Batching.Window.String())
+		}
+		if config.Limits.Suppresses() {
+			text += styles.SubtitleStyle.Render("  dedup " + config.Limits.SuppressWindow.String())
+		}
+		if config.Limits.RateLimited() {
+			text += styles.SubtitleStyle.Render(fmt.Sprintf("  %d/min", config.Limits.RatePerMinute))
+		}
+		if !config.Auth.IsZero() {
+			text += styles.SubtitleStyle.Render("  secured")
+		}
+		text += styles.SubtitleStyle.Render("  " + sanitizeLogString(config.URL))
+		rows = append(rows, truncateRendered(marker+check+" "+text, innerWidth))
+	}
+	if len(rows) == 0 {
+		rows = append(rows, formPlaceholder.Render("No webhooks yet."))
 	}
 
-	parts := make([]string, 0, 8)
+	parts := make([]string, 0, 10)
 	appendSpacer := func() {
 		if !layout.compact {
 			parts = append(parts, "")
@@ -241,92 +582,350 @@ func (m *Model) viewWebhookSettings() string {
 	}
 
 	if layout.showSubtitle {
-		parts = append(parts, styles.SubtitleStyle.Width(innerWidth).Render("Configure the optional webhook endpoint used for trace delivery."))
-	}
-	if layout.showCurrentStatus {
+		parts = append(parts, styles.SubtitleStyle.Width(innerWidth).Render("Webhooks receive messages sent with Trace_Message_To_Webhook. ★ marks the default, used when no target_ is given."))
 		appendSpacer()
-		parts = append(parts, renderEmbeddedField(embeddedFieldOptions{
-			Label:       "Current Webhook",
-			Value:       currentWebhook,
-			Width:       innerWidth,
-			BorderColor: styles.ConnectionBorderColor,
-		}))
 	}
-
-	appendSpacer()
+	button := state.cursor - len(state.webhooks)
 	parts = append(parts, renderEmbeddedField(embeddedFieldOptions{
-		Label:      "Webhook URL",
-		Value:      editValue,
-		Width:      innerWidth,
-		Focused:    m.webhookSettings.cursor == webhookFieldURL,
-		FooterText: "Leave empty to disable webhook delivery.",
+		Label:   "Webhooks",
+		Value:   strings.Join(rows, "\n"),
+		Width:   innerWidth,
+		Focused: button < 0,
 	}))
 
 	appendSpacer()
+	parts = append(parts, lipgloss.PlaceHorizontal(innerWidth, lipgloss.Center, lipgloss.JoinHorizontal(lipgloss.Center,
+		renderActionButton("Add Webhook", 0, button == webhookBtnAdd, buttonVariantPrimary), "  ",
+		renderActionButton("Close", 0, button == webhookBtnClose, buttonVariantPrimary),
+	)))
+	parts = append(parts, lipgloss.PlaceHorizontal(innerWidth, lipgloss.Center,
+		renderActionButton("Outputs & Routing…", 0, button == webhookBtnOutputs, buttonVariantPrimary)))
+	parts = append(parts, lipgloss.PlaceHorizontal(innerWidth, lipgloss.Center,
+		renderActionButton(fmt.Sprintf("Dead Letters (%d)…", len(state.deadLetters)), 0, button == webhookBtnDeadLetters, buttonVariantPrimary)))
+
+	parts = append(parts, renderSettingsDialogLines(state.dialog, innerWidth)...)
+
+	if !state.dialog.visible && layout.showHint {
+		appendSpacer()
+		parts = append(parts, styles.OnboardingHintStyle.Width(innerWidth).Render("↑/↓ Navigate  •  Enter Edit  •  A Security  •  B Batching  •  L Limits  •  P Preview  •  Space Toggle  •  * Default  •  D Delete  •  Esc Close"))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
+
+// viewWebhookForm renders the form for adding or editing a webhook, or
+// its security, batching or limits settings.
+func (m *Model) viewWebhookForm() string {
+	state := m.webhookSettings
+	layout := state.layout
+	innerWidth := layout.innerWidth
+	form := state.form
+
+	parts := make([]string, 0, 2*len(form.fields)+4)
+	for i, field := range form.fields {
+		if !layout.compact {
+			parts = append(parts, "")
+		}
+		parts = append(parts, renderOutputField(field, form.cursor == i, field.placeholder, field.footer, innerWidth))
+	}
+
+	if !layout.compact {
+		parts = append(parts, "")
+	}
 	parts = append(parts, renderCenteredActionButtons(
 		innerWidth,
 		"Save",
-		m.webhookSettings.cursor == webhookBtnSave,
+		form.cursor == form.saveButton(),
 		"Cancel",
-		m.webhookSettings.cursor == webhookBtnCancel,
+		form.cursor == form.cancelButton(),
 	))
+	parts = append(parts, renderSettingsDialogLines(state.dialog, innerWidth)...)
 
-	parts = append(parts, renderSettingsDialogLines(m.webhookSettings.dialog, innerWidth)...)
+	if !state.dialog.visible && layout.showHint {
+		hint := "↑/↓ Navigate  •  ←/→ Choose  •  Ctrl+U Clear  •  Enter Confirm  •  Esc Back"
+		if state.view == webhooksViewForm {
+			hint = "↑/↓ Navigate  •  ←/→ Choose  •  Ctrl+U Clear  •  Ctrl+P Preview  •  Enter Confirm  •  Esc Back"
+		}
+		parts = append(parts, "", styles.OnboardingHintStyle.Width(innerWidth).Render(hint))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
 
-	if !m.webhookSettings.dialog.visible && layout.showHint {
-		appendSpacer()
-		parts = append(parts, styles.OnboardingHintStyle.Width(innerWidth).Render("↑/↓ Navigate  •  Enter Confirm  •  Ctrl+U Clear  •  Esc Back"))
+// viewWebhookPreview renders the sample request body, cut to fit the screen.
+func (m *Model) viewWebhookPreview() string {
+	state := m.webhookSettings
+	layout := state.layout
+	innerWidth := layout.innerWidth
+	preview := state.preview
+
+	parts := make([]string, 0, 6)
+	if layout.showSubtitle {
+		subtitle := "The request body a sample ERROR message is sent as."
+		if preview.digest {
+			subtitle = "The request body a batch of sample messages is sent as."
+		}
+		parts = append(parts, styles.SubtitleStyle.Width(innerWidth).Render(subtitle))
+		if !layout.compact {
+			parts = append(parts, "")
+		}
 	}
 
-	content := lipgloss.JoinVertical(lipgloss.Left, parts...)
-	return renderFramedPanel("Settings", panelWidth, panelTypeInfo, content)
+	var value string
+	if preview.err != nil {
+		value = lipgloss.NewStyle().Foreground(styles.ErrorColor).Width(max(innerWidth-4, 1)).Render(sanitizeLogString(preview.err.Error()))
+	} else {
+		_, contentHeight := screenContentSize(m.width, m.height)
+		maxLines := max(contentHeight-12, 3)
+		lines := strings.Split(preview.body, "\n")
+		if len(lines) > maxLines {
+			lines = append(lines[:maxLines-1], fmt.Sprintf("… %d more lines", len(lines)-maxLines+1))
+		}
+		for i, line := range lines {
+			// sanitizeLogString trims the line, so the indentation is put back
+			indent := line[:len(line)-len(strings.TrimLeft(line, " "))]
+			lines[i] = truncateRendered(styles.BodyTextStyle.Render(indent+sanitizeLogString(line)), max(innerWidth-4, 1))
+		}
+		value = strings.Join(lines, "\n")
+	}
+	parts = append(parts, renderEmbeddedField(embeddedFieldOptions{
+		Label:   "Request Body",
+		Value:   value,
+		Width:   innerWidth,
+		Focused: true,
+	}))
+
+	if layout.showHint {
+		if !layout.compact {
+			parts = append(parts, "")
+		}
+		parts = append(parts, styles.OnboardingHintStyle.Width(innerWidth).Render("Tab Message/Digest  •  Esc Back"))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
 }
 
 // ==========================================
 // Async Commands
 // ==========================================
 
-// saveWebhookSettingsCmd returns an async command that saves or deletes the webhook configuration.
-// It derives the target ID from the existing config when present, falling back to the default ID.
-func (m *Model) saveWebhookSettingsCmd() tea.Cmd {
-	input := strings.TrimSpace(m.webhookSettings.input)
+// loadWebhooks returns the stored webhooks and the ID of the default one,
+// which is empty when there is none.
+func loadWebhooks(repo ports.ConfigRepository) ([]domain.WebhookConfig, string, error) {
+	webhooks, err := repo.ListWebhookConfigs()
+	if err != nil {
+		return nil, "", fmt.Errorf("load webhooks: %w", err)
+	}
+	defaultID := ""
+	config, err := repo.GetWebhookConfig()
+	switch {
+	case err == nil && config != nil:
+		defaultID = config.ID
+	case err != nil && !errors.Is(err, domain.ErrWebhookConfigNotFound):
+		return nil, "", fmt.Errorf("load default webhook: %w", err)
+	}
+	return webhooks, defaultID, nil
+}
+
+// changeWebhooksCmd returns an async command that applies change to the
+// stored webhooks, has the webhook sinks load them again and lists the
+// result.
+func (m *Model) changeWebhooksCmd(change func(ports.ConfigRepository) error) tea.Cmd {
 	boltAdapter := m.boltAdapter
-	existing := m.webhookSettings.config
 
-	// Derive target ID from existing config, falling back to default
-	targetID := domain.DefaultWebhookID
-	if existing != nil && existing.ID != "" {
-		targetID = existing.ID
+	return func() tea.Msg {
+		err := change(boltAdapter)
+		tracer.InvalidateWebhookConfigs()
+		if err != nil {
+			return webhooksChangedMsg{err: err}
+		}
+		webhooks, defaultID, err := loadWebhooks(boltAdapter)
+		return webhooksChangedMsg{webhooks: webhooks, defaultID: defaultID, err: err}
 	}
+}
 
-	return func() tea.Msg {
-		if input == "" {
-			if existing == nil {
-				return webhookConfigSavedMsg{deleted: true}
-			}
-			if err := boltAdapter.DeleteWebhookConfig(targetID); err != nil {
-				return webhookConfigSavedMsg{deleted: true, err: fmt.Errorf("clear webhook configuration: %w", err)}
+// saveWebhookCmd validates and saves the webhook form. A renamed webhook
+// replaces the old entry and keeps its creation time and default status.
+func (m *Model) saveWebhookCmd() tea.Cmd {
+	form := m.webhookSettings.form
+	fields := form.fields
+	existing := m.webhookSettings.webhooks
+	wasDefault := form.original != "" && form.original == m.webhookSettings.defaultID
+
+	return m.changeWebhooksCmd(func(repo ports.ConfigRepository) error {
+		config, err := domain.NewWebhookConfig(fields[webhookFieldID].value, strings.TrimSpace(fields[webhookFieldURL].value), fields[webhookFieldEnabled].checked)
+		if err != nil {
+			return fmt.Errorf("invalid webhook: %w", err)
+		}
+		level := fields[webhookFieldLevel].value
+		if level == anyLevel {
+			level = ""
+		}
+		if err := config.SetMinLevel(level); err != nil {
+			return err
+		}
+		if err := config.SetFormat(webhookFormatFromLabel(fields[webhookFieldFormat].value), fields[webhookFieldTemplate].value); err != nil {
+			return err
+		}
+		// Executing the template catches unknown fields as well as syntax errors
+		if _, err := webhook.Preview(config.Format, config.Template, false); err != nil {
+			return err
+		}
+		for _, other := range existing {
+			switch {
+			case other.ID == form.original:
+				if !other.CreatedAt.IsZero() {
+					config.CreatedAt = other.CreatedAt
+				}
+				config.Auth = other.Auth
+				config.Batching = other.Batching
+				config.Limits = other.Limits
+			case other.MatchesTarget(config.ID):
+				// Targets match case-insensitively, so OPS and ops would be ambiguous
+				return fmt.Errorf("a webhook named %q already exists", other.ID)
 			}
-			return webhookConfigSavedMsg{deleted: true}
 		}
 
-		config, err := domain.NewWebhookConfig(targetID, input, true)
+		if err := repo.SaveWebhookConfig(config); err != nil {
+			return fmt.Errorf("save webhook configuration: %w", err)
+		}
+		if form.original == "" || form.original == config.ID {
+			return nil
+		}
+		if err := repo.DeleteWebhookConfig(form.original); err != nil {
+			return fmt.Errorf("remove renamed webhook: %w", err)
+		}
+		if wasDefault {
+			return repo.SetDefaultWebhook(config.ID)
+		}
+		return nil
+	})
+}
+
+// saveWebhookAuthCmd validates and saves the security form. The TLS files are
+// read once here so a wrong path is reported now instead of on every send.
+func (m *Model) saveWebhookAuthCmd() tea.Cmd {
+	form := m.webhookSettings.form
+	fields := form.fields
+	var config domain.WebhookConfig
+	for _, existing := range m.webhookSettings.webhooks {
+		if existing.ID == form.original {
+			config = existing
+		}
+	}
+
+	return m.changeWebhooksCmd(func(repo ports.ConfigRepository) error {
+		if config.ID == "" {
+			return fmt.Errorf("%w: %s", domain.ErrWebhookConfigNotFound, form.original)
+		}
+		auth, err := domain.NewWebhookAuth(
+			fields[webhookAuthFieldSecret].value,
+			fields[webhookAuthFieldBearer].value,
+			fields[webhookAuthFieldHeaders].value,
+			fields[webhookAuthFieldClientCert].value,
+			fields[webhookAuthFieldClientKey].value,
+			fields[webhookAuthFieldCABundle].value,
+		)
 		if err != nil {
-			return webhookConfigSavedMsg{err: fmt.Errorf("invalid webhook URL: %w", err)}
+			return err
+		}
+		if _, err := webhook.LoadTLSConfig(auth); err != nil {
+			return err
+		}
+		config.Auth = auth
+		config.UpdatedAt = time.Now()
+		if err := repo.SaveWebhookConfig(&config); err != nil {
+			return fmt.Errorf("save webhook security: %w", err)
+		}
+		return nil
+	})
+}
+
+// saveWebhookBatchingCmd validates and saves the batching form.
+func (m *Model) saveWebhookBatchingCmd() tea.Cmd {
+	form := m.webhookSettings.form
+	fields := form.fields
+	var config domain.WebhookConfig
+	for _, existing := range m.webhookSettings.webhooks {
+		if existing.ID == form.original {
+			config = existing
+		}
+	}
+
+	return m.changeWebhooksCmd(func(repo ports.ConfigRepository) error {
+		if config.ID == "" {
+			return fmt.Errorf("%w: %s", domain.ErrWebhookConfigNotFound, form.original)
 		}
-		if existing != nil {
-			if existing.ID != "" {
-				config.ID = existing.ID
+		var window time.Duration
+		if value := strings.TrimSpace(fields[webhookBatchFieldWindow].value); value != "" {
+			seconds, err := strconv.ParseFloat(value, 64)
+			if err != nil || seconds <= 0 {
+				return fmt.Errorf("batch window must be a positive number of seconds")
 			}
-			if !existing.CreatedAt.IsZero() {
-				config.CreatedAt = existing.CreatedAt
+			window = time.Duration(seconds * float64(time.Second))
+		}
+		maxMessages := 0
+		if value := strings.TrimSpace(fields[webhookBatchFieldMax].value); value != "" {
+			var err error
+			if maxMessages, err = strconv.Atoi(value); err != nil {
+				return fmt.Errorf("batch size must be a whole number")
 			}
 		}
-
-		if err := boltAdapter.SaveWebhookConfig(config); err != nil {
-			return webhookConfigSavedMsg{err: fmt.Errorf("save webhook configuration: %w", err)}
+		batching, err := domain.NewWebhookBatching(window, maxMessages, fields[webhookBatchFieldCritical].checked)
+		if err != nil {
+			return err
 		}
+		config.Batching = batching
+		config.UpdatedAt = time.Now()
+		if err := repo.SaveWebhookConfig(&config); err != nil {
+			return fmt.Errorf("save webhook batching: %w", err)
+		}
+		return nil
+	})
+}
 
-		return webhookConfigSavedMsg{config: config}
+// saveWebhookLimitsCmd validates and saves the limits form.
+func (m *Model) saveWebhookLimitsCmd() tea.Cmd {
+	form := m.webhookSettings.form
+	fields := form.fields
+	var config domain.WebhookConfig
+	for _, existing := range m.webhookSettings.webhooks {
+		if existing.ID == form.original {
+			config = existing
+		}
 	}
+
+	return m.changeWebhooksCmd(func(repo ports.ConfigRepository) error {
+		if config.ID == "" {
+			return fmt.Errorf("%w: %s", domain.ErrWebhookConfigNotFound, form.original)
+		}
+		var window time.Duration
+		if value := strings.TrimSpace(fields[webhookLimitFieldSuppress].value); value != "" {
+			seconds, err := strconv.ParseFloat(value, 64)
+			if err != nil || seconds <= 0 {
+				return fmt.Errorf("suppress window must be a positive number of seconds")
+			}
+			window = time.Duration(seconds * float64(time.Second))
+		}
+		rate, burst := 0, 0
+		if value := strings.TrimSpace(fields[webhookLimitFieldRate].value); value != "" {
+			var err error
+			if rate, err = strconv.Atoi(value); err != nil {
+				return fmt.Errorf("rate limit must be a whole number of requests")
+			}
+		}
+		if value := strings.TrimSpace(fields[webhookLimitFieldBurst].value); value != "" {
+			var err error
+			if burst, err = strconv.Atoi(value); err != nil {
+				return fmt.Errorf("burst must be a whole number of requests")
+			}
+		}
+		limits, err := domain.NewWebhookLimits(window, rate, burst)
+		if err != nil {
+			return err
+		}
+		config.Limits = limits
+		config.UpdatedAt = time.Now()
+		if err := repo.SaveWebhookConfig(&config); err != nil {
+			return fmt.Errorf("save webhook limits: %w", err)
+		}
+		return nil
+	})
 }
@@ -58,17 +58,40 @@ func newTestModelForWebhookSettings(t *testing.T) *Model {
 	}
 }
 
-func TestUpdateMain_SKeyOpensWebhookSettings(t *testing.T) {
-	t.Parallel()
-
-	m := newTestModelForWebhookSettings(t)
-	config, err := domain.NewWebhookConfig(domain.DefaultWebhookID, "https://example.com/trace", true)
+func saveTestWebhook(t *testing.T, m *Model, id, url string) *domain.WebhookConfig {
+	t.Helper()
+	config, err := domain.NewWebhookConfig(id, url, true)
 	if err != nil {
 		t.Fatalf("NewWebhookConfig: %v", err)
 	}
 	if err := m.boltAdapter.SaveWebhookConfig(config); err != nil {
 		t.Fatalf("SaveWebhookConfig: %v", err)
 	}
+	return config
+}
+
+// submitWebhookForm runs the save command of the open form and feeds its result back.
+func submitWebhookForm(t *testing.T, m *Model) webhooksChangedMsg {
+	t.Helper()
+	m.webhookSettings.form.cursor = m.webhookSettings.form.saveButton()
+	_, cmd := m.updateWebhookSettings(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected Save to return a command")
+	}
+	msg, ok := cmd().(webhooksChangedMsg)
+	if !ok {
+		t.Fatal("expected the save command to return webhooksChangedMsg")
+	}
+	m.updateWebhookSettings(msg)
+	return msg
+}
+
+func TestUpdateMain_SKeyOpensWebhookSettings(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModelForWebhookSettings(t)
+	saveTestWebhook(t, m, domain.DefaultWebhookID, "https://example.com/trace")
+	saveTestWebhook(t, m, "OPS", "https://example.com/ops")
 
 	updated, cmd := m.updateMain(makeCharPress("s"))
 	if cmd != nil {
@@ -77,188 +100,173 @@ func TestUpdateMain_SKeyOpensWebhookSettings(t *testing.T) {
 	if !updated.webhookSettings.visible {
 		t.Fatal("expected webhook settings overlay to be visible")
 	}
-	if updated.webhookSettings.input != config.URL {
-		t.Fatalf("expected webhook input %q, got %q", config.URL, updated.webhookSettings.input)
+	if len(updated.webhookSettings.webhooks) != 2 || updated.webhookSettings.defaultID != domain.DefaultWebhookID {
+		t.Fatalf("expected both webhooks with the first as default, got %+v default=%q",
+			updated.webhookSettings.webhooks, updated.webhookSettings.defaultID)
+	}
+	if view := updated.viewWebhookSettings(); !containsAll(view, "https://example.com/trace", "OPS", "★") {
+		t.Fatalf("expected the list to show both webhooks and the default marker, got:\n%s", view)
 	}
 }
 
-func TestSaveWebhookSettingsCmd_PersistsURL(t *testing.T) {
+func TestWebhookSettings_AddFirstWebhookBecomesDefault(t *testing.T) {
 	t.Parallel()
 
 	m := newTestModelForWebhookSettings(t)
-	m.initWebhookSettings(nil)
-	m.webhookSettings.input = "https://example.com/webhook"
+	m.openWebhookSettings()
+	m.webhookSettings.cursor = webhookBtnAdd
+	m.updateWebhookSettings(tea.KeyPressMsg{Code: tea.KeyEnter})
 
-	msg := m.saveWebhookSettingsCmd()()
-	saved, ok := msg.(webhookConfigSavedMsg)
-	if !ok {
-		t.Fatalf("expected webhookConfigSavedMsg, got %T", msg)
-	}
-	if saved.err != nil {
-		t.Fatalf("expected save to succeed, got %v", saved.err)
+	if m.webhookSettings.view != webhooksViewForm || m.webhookSettings.form.fields[webhookFieldID].value != domain.DefaultWebhookID {
+		t.Fatalf("expected the form with the default ID offered, got view=%d fields=%+v", m.webhookSettings.view, m.webhookSettings.form.fields)
 	}
+	m.webhookSettings.form.cursor = webhookFieldURL
+	m.updateWebhookSettings(tea.PasteMsg{Content: "https://example.com/webhook"})
 
-	updated, cmd := m.updateWebhookSettings(saved)
-	if cmd != nil {
-		t.Fatal("expected no follow-up command after save")
+	if msg := submitWebhookForm(t, m); msg.err != nil {
+		t.Fatalf("save webhook: %v", msg.err)
 	}
-	if updated.webhookSettings.visible {
-		t.Fatal("expected webhook settings overlay to close after save")
+	if m.webhookSettings.view != webhooksViewList || m.webhookSettings.defaultID != domain.DefaultWebhookID {
+		t.Fatalf("expected the list with the new webhook as default, got view=%d default=%q", m.webhookSettings.view, m.webhookSettings.defaultID)
 	}
-
 	config, err := m.boltAdapter.GetWebhookConfig()
 	if err != nil {
 		t.Fatalf("GetWebhookConfig: %v", err)
 	}
-	if config.URL != "https://example.com/webhook" {
-		t.Fatalf("expected stored webhook URL to match, got %q", config.URL)
+	if config.URL != "https://example.com/webhook" || !config.Enabled || config.MinLevel != "" {
+		t.Fatalf("unexpected stored webhook %+v", config)
 	}
 }
 
-func TestSaveWebhookSettingsCmd_EmptyURLClearsConfig(t *testing.T) {
+func TestWebhookSettings_AddNamedWebhookWithLevel(t *testing.T) {
 	t.Parallel()
 
 	m := newTestModelForWebhookSettings(t)
-	config, err := domain.NewWebhookConfig(domain.DefaultWebhookID, "https://example.com/trace", true)
-	if err != nil {
-		t.Fatalf("NewWebhookConfig: %v", err)
-	}
-	if err := m.boltAdapter.SaveWebhookConfig(config); err != nil {
-		t.Fatalf("SaveWebhookConfig: %v", err)
-	}
-
-	m.initWebhookSettings(config)
-	m.webhookSettings.input = ""
+	saveTestWebhook(t, m, domain.DefaultWebhookID, "https://example.com/trace")
+	m.openWebhookSettings()
+	m.editWebhook(nil)
 
-	msg := m.saveWebhookSettingsCmd()()
-	saved, ok := msg.(webhookConfigSavedMsg)
-	if !ok {
-		t.Fatalf("expected webhookConfigSavedMsg, got %T", msg)
-	}
-	if !saved.deleted {
-		t.Fatal("expected empty webhook input to clear the stored config")
+	form := &m.webhookSettings.form
+	form.fields[webhookFieldID].value = "OPS"
+	form.fields[webhookFieldURL].value = "https://example.com/ops"
+	form.cursor = webhookFieldLevel
+	for range 3 {
+		m.updateWebhookSettings(tea.KeyPressMsg{Code: tea.KeyRight})
 	}
-	if saved.err != nil {
-		t.Fatalf("expected clear to succeed, got %v", saved.err)
+	if msg := submitWebhookForm(t, m); msg.err != nil {
+		t.Fatalf("save webhook: %v", msg.err)
 	}
 
-	updated, cmd := m.updateWebhookSettings(saved)
-	if cmd != nil {
-		t.Fatal("expected no follow-up command after clearing webhook")
+	webhooks, err := m.boltAdapter.ListWebhookConfigs()
+	if err != nil {
+		t.Fatalf("ListWebhookConfigs: %v", err)
 	}
-	if updated.webhookSettings.visible {
-		t.Fatal("expected webhook settings overlay to close after clearing")
+	if len(webhooks) != 2 || webhooks[0].ID != "OPS" || webhooks[0].MinLevel != domain.LogLevelWarning {
+		t.Fatalf("expected OPS with a WARNING filter beside the default, got %+v", webhooks)
 	}
-
-	if _, err := m.boltAdapter.GetWebhookConfig(); !errors.Is(err, domain.ErrWebhookConfigNotFound) {
-		t.Fatalf("expected ErrWebhookConfigNotFound after clearing, got %v", err)
+	if m.webhookSettings.defaultID != domain.DefaultWebhookID {
+		t.Fatalf("expected the default to stay unchanged, got %q", m.webhookSettings.defaultID)
 	}
 }
 
-func TestSaveWebhookSettingsCmd_EmptyURLWithoutExistingIsIdempotent(t *testing.T) {
+func TestWebhookSettings_RejectsDuplicateAndInvalidWebhooks(t *testing.T) {
 	t.Parallel()
 
 	m := newTestModelForWebhookSettings(t)
-	if _, err := m.boltAdapter.GetWebhookConfig(); !errors.Is(err, domain.ErrWebhookConfigNotFound) {
-		t.Fatalf("expected no webhook before save, got %v", err)
+	saveTestWebhook(t, m, "ops", "https://example.com/ops")
+	m.openWebhookSettings()
+
+	for _, tc := range []struct{ id, url string }{
+		{"OPS", "https://example.com/other"},
+		{"audit", "localhost-only"},
+		{"has space", "https://example.com/audit"},
+	} {
+		m.editWebhook(nil)
+		m.webhookSettings.form.fields[webhookFieldID].value = tc.id
+		m.webhookSettings.form.fields[webhookFieldURL].value = tc.url
+		if msg := submitWebhookForm(t, m); msg.err == nil {
+			t.Errorf("expected saving %q -> %q to fail", tc.id, tc.url)
+		}
+		if !m.webhookSettings.dialog.visible || m.webhookSettings.view != webhooksViewForm {
+			t.Errorf("expected the form to stay open with an error for %q", tc.id)
+		}
 	}
 
-	m.initWebhookSettings(nil)
-	m.webhookSettings.input = ""
-
-	msg := m.saveWebhookSettingsCmd()()
-	saved, ok := msg.(webhookConfigSavedMsg)
-	if !ok {
-		t.Fatalf("expected webhookConfigSavedMsg, got %T", msg)
-	}
-	if !saved.deleted {
-		t.Fatal("expected empty save with no prior config to report deleted (close overlay)")
-	}
-	if saved.err != nil {
-		t.Fatalf("expected idempotent clear to succeed, got %v", saved.err)
+	webhooks, err := m.boltAdapter.ListWebhookConfigs()
+	if err != nil {
+		t.Fatalf("ListWebhookConfigs: %v", err)
 	}
-
-	if _, err := m.boltAdapter.GetWebhookConfig(); !errors.Is(err, domain.ErrWebhookConfigNotFound) {
-		t.Fatalf("expected ErrWebhookConfigNotFound after idempotent clear, got %v", err)
+	if len(webhooks) != 1 || webhooks[0].URL != "https://example.com/ops" {
+		t.Fatalf("expected only the original webhook, got %+v", webhooks)
 	}
 }
 
-func TestSaveWebhookSettingsCmd_UpdatePreservesCreatedAt(t *testing.T) {
+func TestWebhookSettings_RenamePreservesCreatedAtAndDefault(t *testing.T) {
 	t.Parallel()
 
 	m := newTestModelForWebhookSettings(t)
-	m.initWebhookSettings(nil)
-	m.webhookSettings.input = "https://example.com/first"
-
-	first := m.saveWebhookSettingsCmd()()
-	savedFirst, ok := first.(webhookConfigSavedMsg)
-	if !ok || savedFirst.err != nil || savedFirst.config == nil {
-		t.Fatalf("expected first save to succeed, got %#v", savedFirst)
-	}
-
-	stored, err := m.boltAdapter.GetWebhookConfig()
-	if err != nil {
-		t.Fatalf("GetWebhookConfig: %v", err)
-	}
-	createdAt := stored.CreatedAt
-	if createdAt.IsZero() {
-		t.Fatal("expected non-zero CreatedAt after first save")
-	}
-
+	original := saveTestWebhook(t, m, domain.DefaultWebhookID, "https://example.com/first")
 	time.Sleep(2 * time.Millisecond)
 
-	m.initWebhookSettings(stored)
-	m.webhookSettings.input = "https://example.com/second"
-
-	second := m.saveWebhookSettingsCmd()()
-	savedSecond, ok := second.(webhookConfigSavedMsg)
-	if !ok || savedSecond.err != nil || savedSecond.config == nil {
-		t.Fatalf("expected second save to succeed, got %#v", savedSecond)
-	}
-	if !savedSecond.config.CreatedAt.Equal(createdAt) {
-		t.Fatalf("expected CreatedAt preserved on URL update, got %v want %v", savedSecond.config.CreatedAt, createdAt)
-	}
-	if !savedSecond.config.UpdatedAt.After(createdAt) {
-		t.Fatalf("expected UpdatedAt after CreatedAt, got UpdatedAt=%v CreatedAt=%v", savedSecond.config.UpdatedAt, createdAt)
+	m.openWebhookSettings()
+	m.updateWebhookSettings(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m.webhookSettings.form.fields[webhookFieldID].value = "primary"
+	m.webhookSettings.form.fields[webhookFieldURL].value = "https://example.com/second"
+	if msg := submitWebhookForm(t, m); msg.err != nil {
+		t.Fatalf("save webhook: %v", msg.err)
 	}
 
 	reloaded, err := m.boltAdapter.GetWebhookConfig()
 	if err != nil {
 		t.Fatalf("GetWebhookConfig: %v", err)
 	}
-	if reloaded.URL != "https://example.com/second" {
-		t.Fatalf("expected updated URL, got %q", reloaded.URL)
+	if reloaded.ID != "primary" || reloaded.URL != "https://example.com/second" {
+		t.Fatalf("expected the renamed webhook to stay the default, got %+v", reloaded)
+	}
+	if !reloaded.CreatedAt.Equal(original.CreatedAt) || !reloaded.UpdatedAt.After(original.CreatedAt) {
+		t.Fatalf("expected CreatedAt preserved and UpdatedAt advanced, got %+v want CreatedAt %v", reloaded, original.CreatedAt)
 	}
-	if !reloaded.CreatedAt.Equal(createdAt) {
-		t.Fatalf("expected persisted CreatedAt unchanged, got %v want %v", reloaded.CreatedAt, createdAt)
+	if webhooks, _ := m.boltAdapter.ListWebhookConfigs(); len(webhooks) != 1 {
+		t.Fatalf("expected the old entry to be removed, got %+v", webhooks)
 	}
 }
 
-func TestSaveWebhookSettingsCmd_InvalidURLShowsError(t *testing.T) {
+func TestWebhookSettings_ListTogglesSetsDefaultAndDeletes(t *testing.T) {
 	t.Parallel()
 
 	m := newTestModelForWebhookSettings(t)
-	m.initWebhookSettings(nil)
-	m.webhookSettings.input = "localhost-only"
-
-	msg := m.saveWebhookSettingsCmd()()
-	saved, ok := msg.(webhookConfigSavedMsg)
-	if !ok {
-		t.Fatalf("expected webhookConfigSavedMsg, got %T", msg)
-	}
-	if saved.err == nil {
-		t.Fatal("expected invalid webhook URL to return an error")
+	saveTestWebhook(t, m, domain.DefaultWebhookID, "https://example.com/trace")
+	saveTestWebhook(t, m, "ops", "https://example.com/ops")
+	m.openWebhookSettings()
+
+	press := func(key tea.KeyPressMsg) {
+		t.Helper()
+		_, cmd := m.updateWebhookSettings(key)
+		if cmd == nil {
+			t.Fatalf("expected %q to return a command", key.String())
+		}
+		msg := cmd().(webhooksChangedMsg)
+		if msg.err != nil {
+			t.Fatalf("%q: %v", key.String(), msg.err)
+		}
+		m.updateWebhookSettings(msg)
 	}
 
-	updated, cmd := m.updateWebhookSettings(saved)
-	if cmd != nil {
-		t.Fatal("expected no follow-up command after invalid webhook save")
+	m.webhookSettings.cursor = 1 // ops, after default in ID order
+	press(tea.KeyPressMsg{Code: tea.KeySpace, Text: " "})
+	press(makeCharPress("*"))
+	config, err := m.boltAdapter.GetWebhookConfig()
+	if err != nil {
+		t.Fatalf("GetWebhookConfig: %v", err)
 	}
-	if !updated.webhookSettings.visible {
-		t.Fatal("expected webhook settings overlay to remain visible after invalid input")
+	if config.ID != "ops" || config.Enabled {
+		t.Fatalf("expected ops to be the disabled default, got %+v", config)
 	}
-	if !updated.webhookSettings.dialog.visible {
-		t.Fatal("expected invalid webhook input to show an error dialog")
+
+	press(makeCharPress("d"))
+	if len(m.webhookSettings.webhooks) != 1 || m.webhookSettings.defaultID != domain.DefaultWebhookID {
+		t.Fatalf("expected ops removed and the default to fall back, got %+v default=%q",
+			m.webhookSettings.webhooks, m.webhookSettings.defaultID)
 	}
 }
 
@@ -266,8 +274,7 @@ func TestModelUpdate_MainWebhookOverlayQClosesOverlay(t *testing.T) {
 	t.Parallel()
 
 	m := newTestModelForWebhookSettings(t)
-	m.initWebhookSettings(nil)
-	m.webhookSettings.cursor = webhookBtnCancel // q should close when not on URL field
+	m.openWebhookSettings()
 
 	updatedModel, cmd := m.Update(makeCharPress("q"))
 	if cmd != nil {
@@ -279,7 +286,7 @@ func TestModelUpdate_MainWebhookOverlayQClosesOverlay(t *testing.T) {
 		t.Fatalf("expected Update to return *Model, got %T", updatedModel)
 	}
 	if updated.webhookSettings.visible {
-		t.Fatal("expected q to close the webhook settings overlay when cursor is on Cancel button")
+		t.Fatal("expected q to close the webhook settings overlay from the list")
 	}
 }
 
@@ -287,35 +294,22 @@ func TestUpdateWebhookSettings_QLetterInURLField(t *testing.T) {
 	t.Parallel()
 
 	m := newTestModelForWebhookSettings(t)
-	m.initWebhookSettings(nil)
-	m.webhookSettings.cursor = webhookFieldURL
-	m.webhookSettings.input = "https://example."
+	m.openWebhookSettings()
+	m.editWebhook(nil)
+	m.webhookSettings.form.cursor = webhookFieldURL
+	m.webhookSettings.form.fields[webhookFieldURL].value = "https://example."
 
 	updated, cmd := m.updateWebhookSettings(makeCharPress("q"))
 	if cmd != nil {
 		t.Fatal("expected no follow-up command for character input")
 	}
-	if updated.webhookSettings.input != "https://example.q" {
-		t.Fatalf("expected q to be appended to input, got %q", updated.webhookSettings.input)
-	}
-	if !updated.webhookSettings.visible {
-		t.Fatal("expected webhook settings overlay to remain visible")
+	if got := updated.webhookSettings.form.fields[webhookFieldURL].value; got != "https://example.q" {
+		t.Fatalf("expected q to be appended to input, got %q", got)
 	}
-}
-
-func TestUpdateWebhookSettings_QKeyOnSaveButtonClosesOverlay(t *testing.T) {
-	t.Parallel()
-
-	m := newTestModelForWebhookSettings(t)
-	m.initWebhookSettings(nil)
-	m.webhookSettings.cursor = webhookBtnSave
 
-	updated, cmd := m.updateWebhookSettings(makeCharPress("q"))
-	if cmd != nil {
-		t.Fatal("expected no follow-up command for q key")
-	}
-	if updated.webhookSettings.visible {
-		t.Fatal("expected q to close webhook settings when cursor is on Save button")
+	updated, _ = updated.updateWebhookSettings(tea.KeyPressMsg{Code: tea.KeyEscape})
+	if !updated.webhookSettings.visible || updated.webhookSettings.view != webhooksViewList {
+		t.Fatal("expected Esc to return from the form to the list")
 	}
 }
 
@@ -325,7 +319,7 @@ func TestResizeWebhookSettings_RecomputesCompactLayout(t *testing.T) {
 	m := newTestModelForWebhookSettings(t)
 	m.width = 48
 	m.height = 12
-	m.initWebhookSettings(nil)
+	m.openWebhookSettings()
 
 	m.resizeWebhookSettings(m.width, m.height)
 
@@ -338,9 +332,6 @@ func TestResizeWebhookSettings_RecomputesCompactLayout(t *testing.T) {
 	if !m.webhookSettings.layout.compact {
 		t.Fatal("expected small terminal height to enable compact webhook layout")
 	}
-	if m.webhookSettings.layout.showCurrentStatus {
-		t.Fatal("expected compact layout to hide the current webhook summary")
-	}
 }
 
 func TestViewWebhookSettings_StaysWithinNarrowWindowWidth(t *testing.T) {
@@ -349,12 +340,14 @@ func TestViewWebhookSettings_StaysWithinNarrowWindowWidth(t *testing.T) {
 	m := newTestModelForWebhookSettings(t)
 	m.width = 48
 	m.height = 14
-	m.initWebhookSettings(nil)
-
-	rendered := m.viewWebhookSettings()
-	for _, line := range strings.Split(rendered, "\n") {
-		if got := lipgloss.Width(line); got > m.width {
-			t.Fatalf("expected webhook settings line width <= %d, got %d\nline: %q", m.width, got, line)
+	saveTestWebhook(t, m, "ops", "https://example.com/a/very/long/path/that/does/not/fit/in/the/panel")
+	m.openWebhookSettings()
+
+	for _, view := range []func() string{m.viewWebhookSettings, func() string { m.editWebhook(nil); return m.viewWebhookSettings() }} {
+		for _, line := range strings.Split(view(), "\n") {
+			if got := lipgloss.Width(line); got > m.width {
+				t.Fatalf("expected webhook settings line width <= %d, got %d\nline: %q", m.width, got, line)
+			}
 		}
 	}
 }
@@ -376,3 +369,226 @@ func containsAll(text string, fragments ...string) bool {
 	}
 	return true
 }
+
+func TestWebhookSettings_SecurityFormSavesCredentials(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModelForWebhookSettings(t)
+	saveTestWebhook(t, m, "ops", "https://example.com/ops")
+	m.openWebhookSettings()
+	m.updateWebhookSettings(makeCharPress("a"))
+	if m.webhookSettings.view != webhooksViewAuth || m.webhookSettings.form.original != "ops" {
+		t.Fatalf("expected the security form for ops, got view=%d original=%q", m.webhookSettings.view, m.webhookSettings.form.original)
+	}
+
+	for field, value := range map[int]string{
+		webhookAuthFieldSecret:  "s3cret",
+		webhookAuthFieldBearer:  "tok",
+		webhookAuthFieldHeaders: "X-Api-Key=abc",
+	} {
+		m.webhookSettings.form.cursor = field
+		m.updateWebhookSettings(tea.PasteMsg{Content: value})
+	}
+	if view := m.viewWebhookSettings(); strings.Contains(view, "s3cret") || strings.Contains(view, "tok") {
+		t.Fatalf("expected secrets to be masked, got:\n%s", view)
+	}
+
+	if msg := submitWebhookForm(t, m); msg.err != nil {
+		t.Fatalf("save security: %v", msg.err)
+	}
+	webhooks, err := m.boltAdapter.ListWebhookConfigs()
+	if err != nil {
+		t.Fatalf("ListWebhookConfigs: %v", err)
+	}
+	auth := webhooks[0].Auth
+	if auth.SigningSecret != "s3cret" || auth.BearerToken != "tok" || auth.Headers["X-Api-Key"] != "abc" {
+		t.Fatalf("unexpected stored auth %+v", auth)
+	}
+	if view := m.viewWebhookSettings(); !containsAll(view, "secured") {
+		t.Fatalf("expected the list to mark the webhook as secured, got:\n%s", view)
+	}
+
+	// Editing the webhook itself keeps its credentials
+	m.webhookSettings.cursor = 0
+	m.updateWebhookSettings(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if msg := submitWebhookForm(t, m); msg.err != nil {
+		t.Fatalf("save webhook: %v", msg.err)
+	}
+	webhooks, err = m.boltAdapter.ListWebhookConfigs()
+	if err != nil {
+		t.Fatalf("ListWebhookConfigs: %v", err)
+	}
+	if webhooks[0].Auth.SigningSecret != "s3cret" {
+		t.Fatalf("expected the edit to keep the credentials, got %+v", webhooks[0].Auth)
+	}
+}
+
+func TestWebhookSettings_SecurityFormRejectsMissingCertificate(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModelForWebhookSettings(t)
+	saveTestWebhook(t, m, "ops", "https://example.com/ops")
+	m.openWebhookSettings()
+	m.updateWebhookSettings(makeCharPress("a"))
+
+	m.webhookSettings.form.cursor = webhookAuthFieldClientCert
+	m.updateWebhookSettings(tea.PasteMsg{Content: t.TempDir() + "/missing.pem"})
+	m.webhookSettings.form.cursor = webhookAuthFieldClientKey
+	m.updateWebhookSettings(tea.PasteMsg{Content: t.TempDir() + "/missing-key.pem"})
+
+	msg := submitWebhookForm(t, m)
+	if !errors.Is(msg.err, domain.ErrInvalidWebhookAuth) {
+		t.Fatalf("save error = %v, want ErrInvalidWebhookAuth", msg.err)
+	}
+	if m.webhookSettings.view != webhooksViewAuth || !m.webhookSettings.dialog.isError {
+		t.Fatal("expected the form to stay open with the error shown")
+	}
+}
+
+func TestWebhookSettings_FormatSavesAndPreviews(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModelForWebhookSettings(t)
+	saveTestWebhook(t, m, "ops", "https://example.com/ops")
+	m.openWebhookSettings()
+	m.updateWebhookSettings(tea.KeyPressMsg{Code: tea.KeyEnter})
+
+	form := &m.webhookSettings.form
+	form.cursor = webhookFieldFormat
+	m.updateWebhookSettings(tea.KeyPressMsg{Code: tea.KeyLeft}) // Wraps to the last choice
+	if got := form.fields[webhookFieldFormat].value; got != domain.WebhookFormatTemplate.Label() {
+		t.Fatalf("format = %q, want the custom template", got)
+	}
+	form.cursor = webhookFieldTemplate
+	m.updateWebhookSettings(tea.PasteMsg{Content: `{"text": {{json .Payload}}, "level": "{{.Level}}"}`})
+
+	m.updateWebhookSettings(tea.KeyPressMsg{Code: 'p', Mod: tea.ModCtrl})
+	if m.webhookSettings.view != webhooksViewPreview {
+		t.Fatalf("expected Ctrl+P to open the preview, got view=%d", m.webhookSettings.view)
+	}
+	if view := m.viewWebhookSettings(); !containsAll(view, "Preview: ops - Custom template", `"level": "ERROR"`) {
+		t.Fatalf("expected the rendered sample, got:\n%s", view)
+	}
+	m.updateWebhookSettings(tea.KeyPressMsg{Code: tea.KeyEscape})
+	if m.webhookSettings.view != webhooksViewForm {
+		t.Fatalf("expected Esc to return to the form, got view=%d", m.webhookSettings.view)
+	}
+
+	if msg := submitWebhookForm(t, m); msg.err != nil {
+		t.Fatalf("save webhook: %v", msg.err)
+	}
+	webhooks, err := m.boltAdapter.ListWebhookConfigs()
+	if err != nil {
+		t.Fatalf("ListWebhookConfigs: %v", err)
+	}
+	if webhooks[0].Format != domain.WebhookFormatTemplate || !strings.Contains(webhooks[0].Template, "json .Payload") {
+		t.Fatalf("unexpected stored webhook %+v", webhooks[0])
+	}
+
+	// The list previews the saved webhook
+	m.updateWebhookSettings(makeCharPress("p"))
+	if view := m.viewWebhookSettings(); m.webhookSettings.preview.back != webhooksViewList || !containsAll(view, `"text": "Invoice run failed`) {
+		t.Fatalf("expected the list to preview the saved template, got:\n%s", view)
+	}
+}
+
+func TestWebhookSettings_RejectsBrokenTemplate(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModelForWebhookSettings(t)
+	saveTestWebhook(t, m, "ops", "https://example.com/ops")
+	m.openWebhookSettings()
+	m.updateWebhookSettings(tea.KeyPressMsg{Code: tea.KeyEnter})
+
+	form := &m.webhookSettings.form
+	form.fields[webhookFieldFormat].value = domain.WebhookFormatTemplate.Label()
+	form.fields[webhookFieldTemplate].value = "{{.Severity}}"
+
+	msg := submitWebhookForm(t, m)
+	if !errors.Is(msg.err, domain.ErrInvalidWebhookFormat) {
+		t.Fatalf("save error = %v, want ErrInvalidWebhookFormat", msg.err)
+	}
+	if m.webhookSettings.view != webhooksViewForm || !m.webhookSettings.dialog.isError {
+		t.Fatal("expected the form to stay open with the error shown")
+	}
+}
+
+func TestWebhookSettings_BatchingFormSavesAndPreviewsDigest(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModelForWebhookSettings(t)
+	saveTestWebhook(t, m, "ops", "https://example.com/ops")
+	m.openWebhookSettings()
+	m.updateWebhookSettings(makeCharPress("b"))
+	if m.webhookSettings.view != webhooksViewBatching || m.webhookSettings.form.original != "ops" {
+		t.Fatalf("expected the batching form for ops, got view=%d original=%q", m.webhookSettings.view, m.webhookSettings.form.original)
+	}
+
+	m.webhookSettings.form.cursor = webhookBatchFieldWindow
+	m.updateWebhookSettings(tea.PasteMsg{Content: "0.5"})
+	if msg := submitWebhookForm(t, m); !errors.Is(msg.err, domain.ErrInvalidWebhookBatching) {
+		t.Fatalf("save error = %v, want ErrInvalidWebhookBatching for a window under a second", msg.err)
+	}
+
+	m.webhookSettings.form.fields[webhookBatchFieldWindow].value = "30"
+	m.webhookSettings.form.fields[webhookBatchFieldMax].value = "20"
+	if msg := submitWebhookForm(t, m); msg.err != nil {
+		t.Fatalf("save batching: %v", msg.err)
+	}
+	webhooks, err := m.boltAdapter.ListWebhookConfigs()
+	if err != nil {
+		t.Fatalf("ListWebhookConfigs: %v", err)
+	}
+	want := domain.WebhookBatching{Window: 30 * time.Second, MaxMessages: 20, CriticalImmediate: true}
+	if webhooks[0].Batching != want {
+		t.Fatalf("batching = %+v, want %+v", webhooks[0].Batching, want)
+	}
+	if view := m.viewWebhookSettings(); !containsAll(view, "batched 30s") {
+		t.Fatalf("expected the list to show the batch window, got:\n%s", view)
+	}
+
+	// A batching webhook previews its digest first; Tab switches to one message
+	m.updateWebhookSettings(makeCharPress("p"))
+	if view := m.viewWebhookSettings(); !m.webhookSettings.preview.digest || !containsAll(view, `"digest": true`) {
+		t.Fatalf("expected the digest preview, got:\n%s", view)
+	}
+	m.updateWebhookSettings(tea.KeyPressMsg{Code: tea.KeyTab})
+	if view := m.viewWebhookSettings(); m.webhookSettings.preview.digest || strings.Contains(view, `"digest"`) {
+		t.Fatalf("expected Tab to preview one message, got:\n%s", view)
+	}
+}
+
+func TestWebhookSettings_LimitsFormSaves(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModelForWebhookSettings(t)
+	saveTestWebhook(t, m, "ops", "https://example.com/ops")
+	m.openWebhookSettings()
+	m.updateWebhookSettings(makeCharPress("l"))
+	if m.webhookSettings.view != webhooksViewLimits || m.webhookSettings.form.original != "ops" {
+		t.Fatalf("expected the limits form for ops, got view=%d original=%q", m.webhookSettings.view, m.webhookSettings.form.original)
+	}
+
+	m.webhookSettings.form.cursor = webhookLimitFieldRate
+	m.updateWebhookSettings(tea.PasteMsg{Content: "100000"})
+	if msg := submitWebhookForm(t, m); !errors.Is(msg.err, domain.ErrInvalidWebhookLimits) {
+		t.Fatalf("save error = %v, want ErrInvalidWebhookLimits for a rate over the maximum", msg.err)
+	}
+
+	m.webhookSettings.form.fields[webhookLimitFieldSuppress].value = "60"
+	m.webhookSettings.form.fields[webhookLimitFieldRate].value = "20"
+	if msg := submitWebhookForm(t, m); msg.err != nil {
+		t.Fatalf("save limits: %v", msg.err)
+	}
+	webhooks, err := m.boltAdapter.ListWebhookConfigs()
+	if err != nil {
+		t.Fatalf("ListWebhookConfigs: %v", err)
+	}
+	want := domain.WebhookLimits{SuppressWindow: time.Minute, RatePerMinute: 20, Burst: 20}
+	if webhooks[0].Limits != want {
+		t.Fatalf("limits = %+v, want %+v", webhooks[0].Limits, want)
+	}
+	if view := m.viewWebhookSettings(); !containsAll(view, "dedup 1m0s", "20/min") {
+		t.Fatalf("expected the list to show the limits, got:\n%s", view)
+	}
+}
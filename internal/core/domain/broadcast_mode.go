@@ -1,5 +1,10 @@
 package domain
 
+import (
+	"fmt"
+	"strings"
+)
+
 // ==========================================
 // BroadcastMode
 // ==========================================
@@ -37,6 +42,22 @@ func NewBroadcastMode(mode string) BroadcastMode {
 	}
 }
 
+// ParseBroadcastMode parses a mode name case-insensitively, accepting the
+// short names "global", "subscriber" and "broadcast" as well as the display names.
+// Unlike NewBroadcastMode it rejects unknown input.
+func ParseBroadcastMode(mode string) (BroadcastMode, error) {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "global":
+		return BroadcastModeGlobal, nil
+	case "subscriber", "only subscriber":
+		return BroadcastModeSubscriber, nil
+	case "broadcast", "only broadcast":
+		return BroadcastModeBroadcast, nil
+	default:
+		return BroadcastModeGlobal, fmt.Errorf("%w: %q", ErrInvalidBroadcastMode, mode)
+	}
+}
+
 // ==========================================
 // String
 // ==========================================
@@ -72,3 +93,21 @@ func (m BroadcastMode) Next() BroadcastMode {
 		return BroadcastModeGlobal
 	}
 }
+
+// ==========================================
+// Visibility
+// ==========================================
+
+// Shows reports whether msg is visible in this mode: everything in Global,
+// only messages addressed to the subscriber in Subscriber, and only messages
+// sent to every subscriber in Broadcast.
+func (m BroadcastMode) Shows(msg *QueueMessage) bool {
+	switch m {
+	case BroadcastModeSubscriber:
+		return !msg.IsGlobalMessage()
+	case BroadcastModeBroadcast:
+		return msg.IsGlobalMessage()
+	default:
+		return true
+	}
+}
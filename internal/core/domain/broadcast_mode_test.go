@@ -1,6 +1,10 @@
 package domain
 
-import "testing"
+import (
+	"errors"
+	"testing"
+	"time"
+)
 
 // ==========================================
 // NewBroadcastMode Tests
@@ -90,3 +94,63 @@ func TestBroadcastMode_Next_CyclesGlobalSubscriberBroadcastGlobal(t *testing.T)
 		}
 	}
 }
+
+// ==========================================
+// ParseBroadcastMode Tests
+// ==========================================
+
+func TestParseBroadcastMode_AcceptsShortNamesAndRejectsUnknown(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input string
+		want  BroadcastMode
+	}{
+		{"global", BroadcastModeGlobal},
+		{"SUBSCRIBER", BroadcastModeSubscriber},
+		{" Only Broadcast ", BroadcastModeBroadcast},
+	}
+	for _, tt := range tests {
+		got, err := ParseBroadcastMode(tt.input)
+		if err != nil || got != tt.want {
+			t.Fatalf("ParseBroadcastMode(%q) = %v, %v; want %v", tt.input, got, err, tt.want)
+		}
+	}
+	if _, err := ParseBroadcastMode("everyone"); !errors.Is(err, ErrInvalidBroadcastMode) {
+		t.Fatalf("expected ErrInvalidBroadcastMode, got %v", err)
+	}
+}
+
+// ==========================================
+// BroadcastMode.Shows Tests
+// ==========================================
+
+func TestBroadcastMode_Shows(t *testing.T) {
+	t.Parallel()
+
+	global, err := NewQueueMessage("1", "P", LogLevelInfo, "to everyone", time.Now())
+	if err != nil {
+		t.Fatalf("NewQueueMessage: %v", err)
+	}
+	targeted := &QueueMessage{}
+	if err := targeted.UnmarshalJSON([]byte(`{"message_id":"2","process_name":"P","log_level":"INFO","payload":"to one","timestamp":1700000000,"mode":"Subscriber"}`)); err != nil {
+		t.Fatalf("UnmarshalJSON: %v", err)
+	}
+
+	tests := []struct {
+		mode           BroadcastMode
+		global, direct bool
+	}{
+		{BroadcastModeGlobal, true, true},
+		{BroadcastModeSubscriber, false, true},
+		{BroadcastModeBroadcast, true, false},
+	}
+	for _, tt := range tests {
+		if got := tt.mode.Shows(global); got != tt.global {
+			t.Errorf("%v.Shows(global) = %v, want %v", tt.mode, got, tt.global)
+		}
+		if got := tt.mode.Shows(targeted); got != tt.direct {
+			t.Errorf("%v.Shows(targeted) = %v, want %v", tt.mode, got, tt.direct)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ==========================================
+// Connection Health Value Objects
+// ==========================================
+
+// ConnectionStatus summarises the most recent connection health probe.
+type ConnectionStatus string
+
+const (
+	ConnectionStatusUnknown  ConnectionStatus = "UNKNOWN"  // No probe has completed yet
+	ConnectionStatusHealthy  ConnectionStatus = "HEALTHY"  // The probe succeeded within DegradedLatency
+	ConnectionStatusDegraded ConnectionStatus = "DEGRADED" // The probe succeeded slowly or the queue depth could not be read
+	ConnectionStatusDown     ConnectionStatus = "DOWN"     // The probe query failed
+	// The session dropped and the listener is re-creating the connection
+	ConnectionStatusReconnecting ConnectionStatus = "RECONNECTING"
+)
+
+// DegradedLatency is the probe round trip above which a connection counts as degraded.
+const DegradedLatency = 500 * time.Millisecond
+
+func (s ConnectionStatus) String() string { return string(s) }
+
+// HealthSample is the result of one connection health probe.
+type HealthSample struct {
+	At                time.Time     // When the probe completed
+	Latency           time.Duration // Round trip of the probe query
+	QueueDepth        int           // READY messages waiting for our consumer; -1 when unknown
+	MessagesPerSecond float64       // Messages dequeued per second since the previous probe
+	Err               string        // Probe failure; empty when the connection answered
+}
+
+// Status classifies the sample.
+func (s HealthSample) Status() ConnectionStatus {
+	switch {
+	case s.Err != "":
+		return ConnectionStatusDown
+	case s.Latency > DegradedLatency || s.QueueDepth < 0:
+		return ConnectionStatusDegraded
+	default:
+		return ConnectionStatusHealthy
+	}
+}
+
+// ConnectionHealth is a snapshot of the recent probes of the active connection.
+type ConnectionHealth struct {
+	Samples       []HealthSample // Oldest first
+	ProbeInterval time.Duration  // Time between probes
+
+	ReconnectAttempt int    // Current reconnect attempt; 0 while the session is up
+	ReconnectErr     string // Error that caused the reconnect, or the last failed attempt
+}
+
+// Reconnecting reports whether the listener is re-creating a lost session.
+func (h ConnectionHealth) Reconnecting() bool {
+	return h.ReconnectAttempt > 0
+}
+
+// Latest returns the most recent sample.
+func (h ConnectionHealth) Latest() (HealthSample, bool) {
+	if len(h.Samples) == 0 {
+		return HealthSample{}, false
+	}
+	return h.Samples[len(h.Samples)-1], true
+}
+
+// Status classifies the latest sample, or reports UNKNOWN before the first probe.
+// A reconnect in progress takes precedence over the samples.
+func (h ConnectionHealth) Status() ConnectionStatus {
+	if h.Reconnecting() {
+		return ConnectionStatusReconnecting
+	}
+	latest, ok := h.Latest()
+	if !ok {
+		return ConnectionStatusUnknown
+	}
+	return latest.Status()
+}
+
+// Stale reports whether the latest probe is older than maxAge at now, which
+// means the probe itself is stuck behind an unresponsive connection.
+func (h ConnectionHealth) Stale(now time.Time, maxAge time.Duration) bool {
+	latest, ok := h.Latest()
+	return ok && now.Sub(latest.At) > maxAge
+}
+
+// ==========================================
+// Connection Loss
+// ==========================================
+
+// connectionLostCodes are the Oracle and ODPI-C error codes that mean the
+// session is gone and has to be re-created rather than retried.
+var connectionLostCodes = []string{
+	"ORA-00028", // Session killed
+	"ORA-01012", // Not logged on
+	"ORA-02396", // Exceeded maximum idle time
+	"ORA-03113", // End-of-file on communication channel
+	"ORA-03114", // Not connected to Oracle
+	"ORA-03135", // Connection lost contact
+	"ORA-12170", // Connect timeout
+	"ORA-12537", // Connection closed
+	"ORA-12547", // Lost contact
+	"ORA-12571", // Packet writer failure
+	"DPI-1010",  // Not connected
+	"DPI-1080",  // Connection closed by ORA error
+	"database connection is not established",
+}
+
+// IsConnectionLostCode reports whether the Oracle error number code, as
+// ODPI-C reports it, means the database session dropped.
+func IsConnectionLostCode(code int) bool {
+	return code > 0 && slices.Contains(connectionLostCodes, fmt.Sprintf("ORA-%05d", code))
+}
+
+// IsConnectionLost reports whether err means the database session dropped.
+// The Oracle adapter wraps the driver errors it recognises with
+// ErrConnectionLost; the codes are also matched in the message, for errors
+// that only carry them as text.
+func IsConnectionLost(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrConnectionLost) {
+		return true
+	}
+	msg := err.Error()
+	for _, code := range connectionLostCodes {
+		if strings.Contains(msg, code) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestIsConnectionLost(t *testing.T) {
+	t.Parallel()
+
+	lost := []error{
+		errors.New("failed to dequeue messages: ORA-03113: end-of-file on communication channel"),
+		errors.New("ORA-03114: not connected to ORACLE"),
+		errors.New("DPI-1080: connection was closed by ORA-3113"),
+		fmt.Errorf("reconnect: %w", ErrConnectionLost),
+	}
+	for _, err := range lost {
+		if !IsConnectionLost(err) {
+			t.Errorf("IsConnectionLost(%q) = false, want true", err)
+		}
+	}
+	for _, err := range []error{nil, errors.New("ORA-00942: table or view does not exist"), errors.New("ORA-25228: timeout in dequeue")} {
+		if IsConnectionLost(err) {
+			t.Errorf("IsConnectionLost(%v) = true, want false", err)
+		}
+	}
+}
+
+func TestIsConnectionLostCode(t *testing.T) {
+	t.Parallel()
+
+	for _, code := range []int{28, 3113, 12571} {
+		if !IsConnectionLostCode(code) {
+			t.Errorf("IsConnectionLostCode(%d) = false, want true", code)
+		}
+	}
+	for _, code := range []int{0, 942, 25228} {
+		if IsConnectionLostCode(code) {
+			t.Errorf("IsConnectionLostCode(%d) = true, want false", code)
+		}
+	}
+}
+
+func TestConnectionHealth_ReconnectTakesPrecedence(t *testing.T) {
+	t.Parallel()
+
+	h := ConnectionHealth{Samples: []HealthSample{{At: time.Now(), QueueDepth: 0}}}
+	if h.Status() != ConnectionStatusHealthy {
+		t.Fatalf("Status() = %v, want HEALTHY", h.Status())
+	}
+	h.ReconnectAttempt = 2
+	if !h.Reconnecting() || h.Status() != ConnectionStatusReconnecting {
+		t.Fatalf("Status() while reconnecting = %v, want RECONNECTING", h.Status())
+	}
+}
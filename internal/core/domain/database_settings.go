@@ -198,6 +198,11 @@ func (dbs *DatabaseSettings) SetAsDefault() {
 	dbs.isDefault = true
 }
 
+// ClearDefault removes the default marker from this database
+func (dbs *DatabaseSettings) ClearDefault() {
+	dbs.isDefault = false
+}
+
 // MarkPermissionsValidated records that permissions were successfully verified for this connection.
 func (dbs *DatabaseSettings) MarkPermissionsValidated() {
 	dbs.validated = true
@@ -30,6 +30,9 @@ var (
 	ErrInvalidPayload   = errors.New("payload cannot be empty")
 	ErrInvalidTimestamp = errors.New("invalid timestamp")
 
+	// Broadcast mode errors
+	ErrInvalidBroadcastMode = errors.New("invalid broadcast mode")
+
 	// Database settings errors
 	ErrEmptyDatabaseID            = errors.New("database ID cannot be empty")
 	ErrKeyCollision               = errors.New("key collision")
@@ -61,7 +64,42 @@ var (
 	ErrUnsafeArchivePath      = errors.New("unsafe archive path")
 
 	// Webhook config errors
-	ErrWebhookConfigNotFound = errors.New("webhook config not found")
+	ErrWebhookConfigNotFound  = errors.New("webhook config not found")
+	ErrWebhookDisabled        = errors.New("webhook is disabled")
+	ErrInvalidWebhookAuth     = errors.New("invalid webhook authentication")
+	ErrInvalidWebhookFormat   = errors.New("invalid webhook payload format")
+	ErrInvalidWebhookBatching = errors.New("invalid webhook batching")
+	ErrInvalidWebhookLimits   = errors.New("invalid webhook limits")
+
+	// Webhook delivery errors
+	ErrWebhookQueueFull         = errors.New("webhook queue is full")
+	ErrWebhookDispatcherStopped = errors.New("webhook dispatcher is stopped")
+	ErrWebhookRateLimited       = errors.New("webhook rate limit exceeded")
+	ErrInvalidWebhookURL        = errors.New("invalid webhook URL")
+	ErrDeadLetterNotFound       = errors.New("webhook dead letter not found")
+
+	// OTLP export errors
+	ErrInvalidOTLPConfig = errors.New("invalid OTLP export config")
+
+	// Output sink errors
+	ErrSinkNotFound        = errors.New("sink not found")
+	ErrInvalidSink         = errors.New("invalid sink config")
+	ErrUnknownSinkType     = errors.New("unknown sink type")
+	ErrRoutingRuleNotFound = errors.New("routing rule not found")
+	ErrInvalidRoutingRule  = errors.New("invalid routing rule")
+
+	// Trace history errors
+	ErrInvalidRetention     = errors.New("invalid history retention")
+	ErrTraceSessionNotFound = errors.New("trace session not found")
+
+	// Trace filter errors
+	ErrInvalidTraceFilter = errors.New("invalid trace filter")
+
+	// Span errors
+	ErrInvalidSpan = errors.New("invalid span")
+
+	// Connection errors
+	ErrConnectionLost = errors.New("database connection lost")
 
 	// Internal/Adapter sentinel errors
 	ErrEarlyAbort = errors.New("early return: encrypted credential found")
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+// ==========================================
+// Message Fingerprints
+// ==========================================
+// A fingerprint identifies messages that say the same thing. Payloads are
+// compared with their variable parts masked, so "lock on order 1042" and
+// "lock on order 1043" count as repeats of one another.
+
+var (
+	// uuidPattern matches UUIDs and GUIDs, with or without braces.
+	uuidPattern = regexp.MustCompile(`(?i)\{?\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b\}?`)
+
+	// hexIDPattern matches hex runs long enough to be IDs, such as hashes
+	// and RAW keys. Runs without a digit are words and runs without a letter
+	// are numbers.
+	hexIDPattern = regexp.MustCompile(`(?i)\b(?:0x)?[0-9a-f]{8,}\b`)
+
+	// numberPattern matches error codes such as ORA-00054 and PLS-00201,
+	// which are kept, and numbers, which are masked.
+	numberPattern = regexp.MustCompile(`\b[A-Z]{2,4}-\d{3,5}\b|\d+(?:[.,:]\d+)*`)
+
+	errorCodePattern = regexp.MustCompile(`^[A-Z]{2,4}-\d{3,5}$`)
+)
+
+// NormalizePayload masks the variable parts of a payload: UUIDs and long
+// hex IDs become "<id>", numbers become "#" and runs of whitespace become
+// one space. Error codes keep their digits, so ORA-00054 and ORA-00060
+// stay distinct.
+func NormalizePayload(payload string) string {
+	normalized := uuidPattern.ReplaceAllString(payload, "<id>")
+	normalized = hexIDPattern.ReplaceAllStringFunc(normalized, func(match string) string {
+		digits := strings.TrimPrefix(strings.ToLower(match), "0x")
+		if strings.ContainsAny(digits, "0123456789") && strings.ContainsAny(digits, "abcdef") {
+			return "<id>"
+		}
+		return match
+	})
+	normalized = numberPattern.ReplaceAllStringFunc(normalized, func(match string) string {
+		if errorCodePattern.MatchString(match) {
+			return match
+		}
+		return "#"
+	})
+	return strings.Join(strings.Fields(normalized), " ")
+}
+
+// Fingerprint returns a short hash of the message's process, level and
+// normalized payload. Messages with the same fingerprint differ only in
+// the numbers and IDs they mention.
+func (m *QueueMessage) Fingerprint() string {
+	sum := sha256.Sum256([]byte(m.processName + "\x00" + string(m.logLevel) + "\x00" + NormalizePayload(m.payload)))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNormalizePayload(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		payload, want string
+	}{
+		{"ORA-00054: resource busy, order 1042 locked", "ORA-00054: resource busy, order # locked"},
+		{"job 17 failed after 2.5s", "job # failed after #s"},
+		{"session 3f2a9c41-77e0-4d1b-9a6e-0c5d8b2e1f00 expired", "session <id> expired"},
+		{"hash a1b2c3d4e5f6 mismatch", "hash <id> mismatch"},
+		{"unexpected  feedbead\ttoken", "unexpected feedbead token"},
+		{"PLS-00201: identifier must be declared", "PLS-00201: identifier must be declared"},
+	} {
+		if got := NormalizePayload(tt.payload); got != tt.want {
+			t.Errorf("NormalizePayload(%q) = %q, want %q", tt.payload, got, tt.want)
+		}
+	}
+}
+
+func TestQueueMessage_Fingerprint(t *testing.T) {
+	t.Parallel()
+
+	message := func(process string, level LogLevel, payload string) *QueueMessage {
+		t.Helper()
+		msg, err := NewQueueMessage("1", process, level, payload, time.Now())
+		if err != nil {
+			t.Fatalf("NewQueueMessage: %v", err)
+		}
+		return msg
+	}
+
+	base := message("BILLING_BATCH", LogLevelError, "ORA-00054 on order 1042").Fingerprint()
+	if got := message("BILLING_BATCH", LogLevelError, "ORA-00054 on order 2291").Fingerprint(); got != base {
+		t.Fatal("expected messages differing only in numbers to share a fingerprint")
+	}
+	for _, other := range []*QueueMessage{
+		message("BILLING_BATCH", LogLevelError, "ORA-00060 on order 1042"),
+		message("BILLING_BATCH", LogLevelWarning, "ORA-00054 on order 1042"),
+		message("INVOICE_JOB", LogLevelError, "ORA-00054 on order 1042"),
+	} {
+		if other.Fingerprint() == base {
+			t.Errorf("expected %q from %s at %s to have its own fingerprint", other.Payload(), other.ProcessName(), other.LogLevel())
+		}
+	}
+}
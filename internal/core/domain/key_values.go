@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// ==========================================
+// Key=Value Lists
+// ==========================================
+// Settings typed into a single field, such as OTLP headers or sink settings,
+// use the OTEL_EXPORTER_OTLP_HEADERS format: comma-separated key=value pairs
+// with URL-encoded values.
+
+// formatKeyValues renders values as key=value pairs sorted by key.
+func formatKeyValues(values map[string]string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = key + "=" + url.QueryEscape(values[key])
+	}
+	return strings.Join(pairs, ",")
+}
+
+// parseKeyValues reads pairs written by formatKeyValues, wrapping errors in
+// errKind. An empty string yields a nil map.
+func parseKeyValues(s string, errKind error) (map[string]string, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	values := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%w: %q must be key=value", errKind, strings.TrimSpace(pair))
+		}
+		decoded, err := url.QueryUnescape(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %v", errKind, key, err)
+		}
+		values[key] = decoded
+	}
+	return values, nil
+}
@@ -0,0 +1,211 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ==========================================
+// OTLP Export Configuration Entity
+// ==========================================
+
+// OTLPConfig describes the OpenTelemetry collector that receives every
+// delivered trace message as a log record over OTLP/HTTP.
+type OTLPConfig struct {
+	Endpoint      string            // Collector base URL, e.g. http://otel:4318, or the full /v1/logs URL
+	Headers       map[string]string // Sent with every export, e.g. an API key; encrypted at rest
+	Enabled       bool
+	BatchSize     int           // Records per export request
+	FlushInterval time.Duration // Longest a record waits for its batch to fill
+	UpdatedAt     time.Time
+}
+
+// OTLPSinkType is the output sink type that exports to a collector.
+const OTLPSinkType = "otlp"
+
+const (
+	DefaultOTLPBatchSize     = 512
+	MaxOTLPBatchSize         = 10000
+	DefaultOTLPFlushInterval = 5 * time.Second
+	MaxOTLPFlushInterval     = 5 * time.Minute
+	DefaultOTLPRetryAttempts = 6 // Tries per batch, the first included
+	MaxOTLPRetryAttempts     = 20
+
+	// otlpLogsPath is appended to endpoints given without a path, as the
+	// OTLP/HTTP specification does for OTEL_EXPORTER_OTLP_ENDPOINT.
+	otlpLogsPath = "/v1/logs"
+)
+
+// NewOTLPConfig creates an OTLPConfig with validation. A zero batch size or
+// flush interval selects the default.
+func NewOTLPConfig(endpoint string, headers map[string]string, enabled bool, batchSize int, flushInterval time.Duration) (*OTLPConfig, error) {
+	endpoint = strings.TrimSpace(endpoint)
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid endpoint: %v", ErrInvalidOTLPConfig, err)
+	}
+	scheme := strings.ToLower(parsed.Scheme)
+	if scheme != "http" && scheme != "https" {
+		return nil, fmt.Errorf("%w: endpoint must use http or https", ErrInvalidOTLPConfig)
+	}
+	if parsed.Host == "" {
+		return nil, fmt.Errorf("%w: endpoint must have a host", ErrInvalidOTLPConfig)
+	}
+
+	if batchSize == 0 {
+		batchSize = DefaultOTLPBatchSize
+	}
+	if batchSize < 1 || batchSize > MaxOTLPBatchSize {
+		return nil, fmt.Errorf("%w: batch size must be between 1 and %d", ErrInvalidOTLPConfig, MaxOTLPBatchSize)
+	}
+	if flushInterval == 0 {
+		flushInterval = DefaultOTLPFlushInterval
+	}
+	if flushInterval < 100*time.Millisecond || flushInterval > MaxOTLPFlushInterval {
+		return nil, fmt.Errorf("%w: flush interval must be between 100ms and %s", ErrInvalidOTLPConfig, MaxOTLPFlushInterval)
+	}
+	for key := range headers {
+		if strings.TrimSpace(key) == "" || strings.ContainsAny(key, " \t\r\n:") {
+			return nil, fmt.Errorf("%w: invalid header name %q", ErrInvalidOTLPConfig, key)
+		}
+	}
+
+	return &OTLPConfig{
+		Endpoint:      endpoint,
+		Headers:       headers,
+		Enabled:       enabled,
+		BatchSize:     batchSize,
+		FlushInterval: flushInterval,
+		UpdatedAt:     time.Now(),
+	}, nil
+}
+
+// ==========================================
+// Business Methods
+// ==========================================
+
+// IsConfigured returns true if an endpoint is set and export is enabled
+func (c *OTLPConfig) IsConfigured() bool {
+	return c != nil && c.Endpoint != "" && c.Enabled
+}
+
+// LogsURL returns the URL export requests are posted to.
+func (c *OTLPConfig) LogsURL() string {
+	parsed, err := url.Parse(c.Endpoint)
+	if err != nil || (parsed.Path != "" && parsed.Path != "/") {
+		return c.Endpoint
+	}
+	parsed.Path = otlpLogsPath
+	return parsed.String()
+}
+
+// HeadersString renders the headers in the key=value,key=value form read by
+// ParseOTLPHeaders, sorted by name.
+func (c *OTLPConfig) HeadersString() string {
+	if c == nil {
+		return ""
+	}
+	return formatKeyValues(c.Headers)
+}
+
+// ParseOTLPHeaders reads headers in the format of OTEL_EXPORTER_OTLP_HEADERS:
+// comma-separated key=value pairs with URL-encoded values.
+func ParseOTLPHeaders(s string) (map[string]string, error) {
+	return parseKeyValues(s, ErrInvalidOTLPConfig)
+}
+
+// ==========================================
+// Severity
+// ==========================================
+
+// OTLPSeverity maps a log level onto the OpenTelemetry severity number and
+// short name. CRITICAL becomes FATAL, the only level above ERROR.
+func OTLPSeverity(level LogLevel) (int, string) {
+	switch level {
+	case LogLevelDebug:
+		return 5, "DEBUG"
+	case LogLevelInfo:
+		return 9, "INFO"
+	case LogLevelWarning:
+		return 13, "WARN"
+	case LogLevelError:
+		return 17, "ERROR"
+	case LogLevelCritical:
+		return 21, "FATAL"
+	default:
+		return 0, ""
+	}
+}
+
+// ==========================================
+// JSON Marshaling
+// ==========================================
+
+type otlpConfigJSON struct {
+	Endpoint      string    `json:"endpoint"`
+	Headers       string    `json:"headers,omitempty"`
+	Enabled       bool      `json:"enabled"`
+	BatchSize     int       `json:"batch_size"`
+	FlushInterval string    `json:"flush_interval"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// MarshalJSON implements custom JSON marshaling for OTLPConfig.
+// Headers usually carry API keys, so they are encrypted at rest via the
+// configured CredentialCipher.
+func (c *OTLPConfig) MarshalJSON() ([]byte, error) {
+	headers := c.HeadersString()
+	if headers != "" {
+		encrypted, err := credentialCipher.Encrypt(headers)
+		if err != nil {
+			return nil, fmt.Errorf("encrypt OTLP headers: %w", err)
+		}
+		headers = encrypted
+	}
+	return json.Marshal(otlpConfigJSON{
+		Endpoint:      c.Endpoint,
+		Headers:       headers,
+		Enabled:       c.Enabled,
+		BatchSize:     c.BatchSize,
+		FlushInterval: c.FlushInterval.String(),
+		UpdatedAt:     c.UpdatedAt,
+	})
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling for OTLPConfig
+func (c *OTLPConfig) UnmarshalJSON(data []byte) error {
+	var j otlpConfigJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	headers := j.Headers
+	if headers != "" {
+		decrypted, err := credentialCipher.Decrypt(headers)
+		if err != nil {
+			return fmt.Errorf("decrypt OTLP headers: %w", err)
+		}
+		headers = decrypted
+	}
+	parsedHeaders, err := ParseOTLPHeaders(headers)
+	if err != nil {
+		return err
+	}
+	interval, err := time.ParseDuration(j.FlushInterval)
+	if err != nil {
+		return fmt.Errorf("%w: flush interval %s", ErrInvalidOTLPConfig, strconv.Quote(j.FlushInterval))
+	}
+
+	*c = OTLPConfig{
+		Endpoint:      j.Endpoint,
+		Headers:       parsedHeaders,
+		Enabled:       j.Enabled,
+		BatchSize:     j.BatchSize,
+		FlushInterval: interval,
+		UpdatedAt:     j.UpdatedAt,
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewOTLPConfig_ValidatesAndDefaults(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := NewOTLPConfig(" http://otel:4318 ", nil, true, 0, 0)
+	if err != nil {
+		t.Fatalf("NewOTLPConfig: %v", err)
+	}
+	if cfg.BatchSize != DefaultOTLPBatchSize || cfg.FlushInterval != DefaultOTLPFlushInterval {
+		t.Fatalf("defaults not applied: %+v", cfg)
+	}
+	if !cfg.IsConfigured() {
+		t.Fatal("expected an enabled config with an endpoint to be configured")
+	}
+
+	invalid := []struct {
+		endpoint string
+		headers  map[string]string
+		batch    int
+		interval time.Duration
+	}{
+		{"otel:4318", nil, 0, 0},
+		{"ftp://otel", nil, 0, 0},
+		{"http://", nil, 0, 0},
+		{"http://otel:4318", nil, MaxOTLPBatchSize + 1, 0},
+		{"http://otel:4318", nil, 0, time.Millisecond},
+		{"http://otel:4318", map[string]string{"bad name": "x"}, 0, 0},
+	}
+	for _, tc := range invalid {
+		if _, err := NewOTLPConfig(tc.endpoint, tc.headers, true, tc.batch, tc.interval); !errors.Is(err, ErrInvalidOTLPConfig) {
+			t.Errorf("NewOTLPConfig(%q, %v, %d, %s) = %v, want ErrInvalidOTLPConfig", tc.endpoint, tc.headers, tc.batch, tc.interval, err)
+		}
+	}
+}
+
+func TestOTLPConfig_LogsURL(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"http://otel:4318":                   "http://otel:4318/v1/logs",
+		"https://otel:4318/":                 "https://otel:4318/v1/logs",
+		"https://gateway.example/otlp/logs":  "https://gateway.example/otlp/logs",
+		"http://otel:4318/v1/logs?tenant=ab": "http://otel:4318/v1/logs?tenant=ab",
+	}
+	for endpoint, want := range cases {
+		cfg := &OTLPConfig{Endpoint: endpoint}
+		if got := cfg.LogsURL(); got != want {
+			t.Errorf("LogsURL(%q) = %q, want %q", endpoint, got, want)
+		}
+	}
+}
+
+func TestParseOTLPHeaders_RoundTrips(t *testing.T) {
+	t.Parallel()
+
+	headers, err := ParseOTLPHeaders(" api-key = s3cr%3Dt , X-Scope-OrgID=ops")
+	if err != nil {
+		t.Fatalf("ParseOTLPHeaders: %v", err)
+	}
+	if headers["api-key"] != "s3cr=t" || headers["X-Scope-OrgID"] != "ops" {
+		t.Fatalf("headers = %v", headers)
+	}
+	cfg := &OTLPConfig{Headers: headers}
+	if got := cfg.HeadersString(); got != "X-Scope-OrgID=ops,api-key=s3cr%3Dt" {
+		t.Fatalf("HeadersString() = %q", got)
+	}
+
+	if _, err := ParseOTLPHeaders("api-key"); !errors.Is(err, ErrInvalidOTLPConfig) {
+		t.Fatalf("expected a pair without = to be rejected, got %v", err)
+	}
+}
+
+func TestOTLPConfig_JSONRoundTripEncryptsHeaders(t *testing.T) {
+	// Not parallel: swaps the package-level credential cipher.
+	SetCredentialCipher(prefixCipher{})
+	defer SetCredentialCipher(nil)
+
+	cfg, err := NewOTLPConfig("http://otel:4318", map[string]string{"api-key": "secret"}, true, 100, 2*time.Second)
+	if err != nil {
+		t.Fatalf("NewOTLPConfig: %v", err)
+	}
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if strings.Contains(string(data), `"headers":"api-key=secret"`) || !strings.Contains(string(data), "enc:") {
+		t.Fatalf("expected headers to be encrypted, got %s", data)
+	}
+
+	var decoded OTLPConfig
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if decoded.Headers["api-key"] != "secret" || decoded.BatchSize != 100 || decoded.FlushInterval != 2*time.Second || !decoded.Enabled {
+		t.Fatalf("decoded = %+v", decoded)
+	}
+}
+
+func TestOTLPSeverity(t *testing.T) {
+	t.Parallel()
+
+	if n, text := OTLPSeverity(LogLevelCritical); n != 21 || text != "FATAL" {
+		t.Fatalf("OTLPSeverity(CRITICAL) = %d %s", n, text)
+	}
+	previous := 0
+	for _, level := range LogLevels() {
+		n, _ := OTLPSeverity(level)
+		if n <= previous {
+			t.Fatalf("severity numbers must increase with the level, %s = %d", level, n)
+		}
+		previous = n
+	}
+}
+
+// prefixCipher marks values so tests can tell they went through the cipher.
+type prefixCipher struct{}
+
+func (prefixCipher) Encrypt(s string) (string, error) { return "enc:" + s, nil }
+func (prefixCipher) Decrypt(s string) (string, error) { return strings.TrimPrefix(s, "enc:"), nil }
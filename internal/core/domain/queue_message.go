@@ -1,8 +1,12 @@
 package domain
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -40,9 +44,33 @@ func NewLogLevel(level string) (LogLevel, error) {
 	}
 }
 
+// LogLevels returns every valid level ordered from least to most severe.
+func LogLevels() []LogLevel {
+	return []LogLevel{LogLevelDebug, LogLevelInfo, LogLevelWarning, LogLevelError, LogLevelCritical}
+}
+
 func (l LogLevel) String() string { return string(l) }
 func (l LogLevel) IsError() bool  { return l == LogLevelError || l == LogLevelCritical }
 
+// Severity returns the ordinal of the level from DEBUG (1) to CRITICAL (5),
+// or 0 for an unknown level, so levels can be compared by importance.
+func (l LogLevel) Severity() int {
+	switch l {
+	case LogLevelDebug:
+		return 1
+	case LogLevelInfo:
+		return 2
+	case LogLevelWarning:
+		return 3
+	case LogLevelError:
+		return 4
+	case LogLevelCritical:
+		return 5
+	default:
+		return 0
+	}
+}
+
 // Entity : Represents a message in the tracer queue
 type QueueMessage struct {
 	messageID     string
@@ -51,7 +79,10 @@ type QueueMessage struct {
 	payload       string
 	timestamp     time.Time
 	sendToWebhook bool
+	webhookTarget string // Named webhook chosen by Trace_Message_To_Webhook; empty for the default webhook
 	mode          string
+	attributes    map[string]string // Structured fields such as order IDs or tenant codes; nil when there are none
+	span          *SpanMarker       // Set on messages sent by Trace_Begin and Trace_End
 }
 
 // NewQueueMessage creates a new QueueMessage with validation
@@ -101,13 +132,44 @@ func NewQueueMessage(messageID string, processName string, logLevel LogLevel, pa
 // Getters (Read-Only Accessors)
 // ==========================================
 
-func (m *QueueMessage) MessageID() string    { return m.messageID }
-func (m *QueueMessage) ProcessName() string  { return m.processName }
-func (m *QueueMessage) LogLevel() LogLevel   { return m.logLevel }
-func (m *QueueMessage) Payload() string      { return m.payload }
-func (m *QueueMessage) Timestamp() time.Time { return m.timestamp }
-func (m *QueueMessage) SendToWebhook() bool  { return m.sendToWebhook }
-func (m *QueueMessage) Mode() string         { return m.mode }
+func (m *QueueMessage) MessageID() string     { return m.messageID }
+func (m *QueueMessage) ProcessName() string   { return m.processName }
+func (m *QueueMessage) LogLevel() LogLevel    { return m.logLevel }
+func (m *QueueMessage) Payload() string       { return m.payload }
+func (m *QueueMessage) Timestamp() time.Time  { return m.timestamp }
+func (m *QueueMessage) SendToWebhook() bool   { return m.sendToWebhook }
+func (m *QueueMessage) WebhookTarget() string { return m.webhookTarget }
+func (m *QueueMessage) Mode() string          { return m.mode }
+
+// Attributes returns a copy of the message attributes, or nil when there are none.
+func (m *QueueMessage) Attributes() map[string]string { return maps.Clone(m.attributes) }
+
+// Attribute returns the value stored under key. An exact match wins; otherwise
+// the key is matched case-insensitively, since PL/SQL callers often differ in case.
+func (m *QueueMessage) Attribute(key string) (string, bool) {
+	if value, ok := m.attributes[key]; ok {
+		return value, true
+	}
+	for k, value := range m.attributes {
+		if strings.EqualFold(k, key) {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// AttributeKeys returns the attribute names in sorted order.
+func (m *QueueMessage) AttributeKeys() []string {
+	return slices.Sorted(maps.Keys(m.attributes))
+}
+
+// Span returns the span marker of a message sent by Trace_Begin or Trace_End.
+func (m *QueueMessage) Span() (SpanMarker, bool) {
+	if m.span == nil {
+		return SpanMarker{}, false
+	}
+	return *m.span, true
+}
 
 // IsGlobalMessage returns true when the message was broadcast to all subscribers (mode is "Global").
 // This is distinct from UI "Broadcast" filters.
@@ -117,6 +179,33 @@ func (m *QueueMessage) IsGlobalMessage() bool { return m.mode == "Global" }
 // Business Methods
 // ==========================================
 
+// SetAttributes replaces the message attributes. Blank keys are dropped and
+// keys are trimmed; an empty map clears the attributes.
+func (m *QueueMessage) SetAttributes(attrs map[string]string) {
+	m.attributes = nil
+	for key, value := range attrs {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		if m.attributes == nil {
+			m.attributes = make(map[string]string, len(attrs))
+		}
+		m.attributes[key] = value
+	}
+}
+
+// SetWebhookTarget names the webhook the message is sent to. An empty target
+// selects the default webhook.
+func (m *QueueMessage) SetWebhookTarget(target string) {
+	m.webhookTarget = strings.TrimSpace(target)
+}
+
+// SetSpan marks the message as opening or closing a span.
+func (m *QueueMessage) SetSpan(marker SpanMarker) {
+	m.span = &marker
+}
+
 // IsCritical returns true if this is an error or critical message
 func (m *QueueMessage) IsCritical() bool {
 	return m.logLevel.IsError()
@@ -150,8 +239,29 @@ type queueMessageJSON struct {
 	LogLevel      string          `json:"log_level"`
 	Payload       string          `json:"payload"`
 	Timestamp     json.RawMessage `json:"timestamp"`
+	TimestampNS   *int64          `json:"timestamp_ns,omitempty"` // Unix nanoseconds; written when the time has a fractional second
 	SendToWebhook string          `json:"send_to_webhook"`
+	WebhookTarget string          `json:"webhook_target,omitempty"`
 	Mode          string          `json:"mode"`
+
+	Attributes map[string]json.RawMessage `json:"attributes,omitempty"`
+	Span       json.RawMessage            `json:"span,omitempty"`
+}
+
+// spanMarkerJSON is the "span" object of a message. Field names match the
+// upper-case keys sent by Trace_Begin and Trace_End case-insensitively.
+type spanMarkerJSON struct {
+	ID       string `json:"id"`
+	ParentID string `json:"parent_id,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Event    string `json:"event"`
+}
+
+// queueMessageFields are the top-level keys of queueMessageJSON. Any other
+// top-level key is kept as an attribute, since Enqueue_Event___ merges its
+// additional properties into the message object.
+var queueMessageFields = []string{
+	"message_id", "process_name", "log_level", "payload", "timestamp", "timestamp_ns", "send_to_webhook", "webhook_target", "mode", "attributes", "span",
 }
 
 // MarshalJSON implements custom JSON marshaling for QueueMessage
@@ -161,10 +271,39 @@ func (m *QueueMessage) MarshalJSON() ([]byte, error) {
 		ProcessName:   m.processName,
 		LogLevel:      string(m.logLevel),
 		Payload:       m.payload,
-		Timestamp:     []byte(fmt.Sprintf(`%d`, m.timestamp.Unix())),
+		Timestamp:     []byte(strconv.FormatInt(m.timestamp.Unix(), 10)),
 		SendToWebhook: fmt.Sprintf(`%t`, m.sendToWebhook),
+		WebhookTarget: m.webhookTarget,
 		Mode:          m.mode,
 	}
+	// timestamp stays whole Unix seconds for consumers parsing it as a
+	// number; the fraction span durations need travels in timestamp_ns.
+	if m.timestamp.Nanosecond() != 0 {
+		ns := m.timestamp.UnixNano()
+		j.TimestampNS = &ns
+	}
+	if len(m.attributes) > 0 {
+		j.Attributes = make(map[string]json.RawMessage, len(m.attributes))
+		for key, value := range m.attributes {
+			raw, err := json.Marshal(value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal attribute %q: %w", key, err)
+			}
+			j.Attributes[key] = raw
+		}
+	}
+	if m.span != nil {
+		raw, err := json.Marshal(spanMarkerJSON{
+			ID:       m.span.ID,
+			ParentID: m.span.ParentID,
+			Name:     m.span.Name,
+			Event:    string(m.span.Event),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal span: %w", err)
+		}
+		j.Span = raw
+	}
 	return json.Marshal(j)
 }
 
@@ -181,9 +320,12 @@ func (m *QueueMessage) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	// Parse timestamp - handle both int64 and string formats
+	// Parse timestamp - timestamp_ns when written by MarshalJSON, otherwise
+	// both int64 and string formats
 	var ts time.Time
-	if len(j.Timestamp) == 0 {
+	if j.TimestampNS != nil {
+		ts = time.Unix(0, *j.TimestampNS)
+	} else if len(j.Timestamp) == 0 {
 		return ErrInvalidTimestamp
 	} else {
 		// Try parsing as int64 first (Unix timestamp)
@@ -227,6 +369,67 @@ func (m *QueueMessage) UnmarshalJSON(data []byte) error {
 		return err
 	}
 	qm.mode = mode
+	qm.SetWebhookTarget(j.WebhookTarget)
+
+	attrs, err := unmarshalAttributes(data, j.Attributes)
+	if err != nil {
+		return err
+	}
+	qm.SetAttributes(attrs)
+	qm.span = unmarshalSpan(j.Span)
+
 	*m = *qm
 	return nil
 }
+
+// unmarshalAttributes collects the nested "attributes" object and any unknown
+// top-level keys into one map. Explicit attributes win over top-level keys.
+// String values are unquoted; other JSON values keep their compact JSON text.
+func unmarshalAttributes(data []byte, nested map[string]json.RawMessage) (map[string]string, error) {
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal(data, &top); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal QueueMessage: %w", err)
+	}
+
+	attrs := make(map[string]string)
+	for key, raw := range top {
+		if slices.ContainsFunc(queueMessageFields, func(field string) bool { return strings.EqualFold(field, key) }) {
+			continue
+		}
+		attrs[key] = attributeValue(raw)
+	}
+	for key, raw := range nested {
+		attrs[key] = attributeValue(raw)
+	}
+	return attrs, nil
+}
+
+// unmarshalSpan decodes the "span" object of a message. A malformed marker is
+// ignored rather than failing the message, which is still worth showing.
+func unmarshalSpan(raw json.RawMessage) *SpanMarker {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	var j spanMarkerJSON
+	if err := json.Unmarshal(raw, &j); err != nil {
+		return nil
+	}
+	marker, err := NewSpanMarker(j.ID, j.ParentID, j.Name, SpanEvent(j.Event))
+	if err != nil {
+		return nil
+	}
+	return &marker
+}
+
+// attributeValue renders a raw JSON attribute value as display text.
+func attributeValue(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, raw); err != nil {
+		return string(raw)
+	}
+	return compact.String()
+}
@@ -2,6 +2,8 @@ package domain
 
 import (
 	"encoding/json"
+	"maps"
+	"strings"
 	"testing"
 	"time"
 )
@@ -81,3 +83,155 @@ func TestQueueMessage_JSONRoundTrip_PreservesNonDefaultMode(t *testing.T) {
 		t.Fatalf("IsGlobalMessage() = %v, want %v", got.IsGlobalMessage(), msg.IsGlobalMessage())
 	}
 }
+
+// TestQueueMessage_JSONRoundTrip_PreservesAttributes verifies that attributes
+// from Trace_Message_With_Props, including non-string values and extra
+// top-level keys, survive Unmarshal → Marshal → Unmarshal.
+func TestQueueMessage_JSONRoundTrip_PreservesAttributes(t *testing.T) {
+	t.Parallel()
+
+	oracle := []byte(`{
+		"MESSAGE_ID": "7", "PROCESS_NAME": "ORDER_API", "LOG_LEVEL": "INFO", "PAYLOAD": "order placed",
+		"TIMESTAMP": 1700000000, "MODE": "Global", "TRACE_ORIGIN": "batch",
+		"ATTRIBUTES": {"order_id": 4711, "tenant": "ACME", "flags": {"rush": true}}
+	}`)
+
+	var msg QueueMessage
+	if err := json.Unmarshal(oracle, &msg); err != nil {
+		t.Fatalf("UnmarshalJSON: %v", err)
+	}
+	want := map[string]string{"order_id": "4711", "tenant": "ACME", "flags": `{"rush":true}`, "TRACE_ORIGIN": "batch"}
+	if !maps.Equal(msg.Attributes(), want) {
+		t.Fatalf("Attributes() = %v, want %v", msg.Attributes(), want)
+	}
+	if got, ok := msg.Attribute("TENANT"); !ok || got != "ACME" {
+		t.Fatalf("Attribute(TENANT) = %q, %v; want a case-insensitive match", got, ok)
+	}
+
+	data, err := msg.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON: %v", err)
+	}
+	var got QueueMessage
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("UnmarshalJSON: %v", err)
+	}
+	if !maps.Equal(got.Attributes(), want) {
+		t.Fatalf("Attributes() after round trip = %v, want %v", got.Attributes(), want)
+	}
+	if keys := got.AttributeKeys(); strings.Join(keys, ",") != "TRACE_ORIGIN,flags,order_id,tenant" {
+		t.Fatalf("AttributeKeys() = %v", keys)
+	}
+}
+
+func TestQueueMessage_MarshalJSON_OmitsEmptyAttributes(t *testing.T) {
+	t.Parallel()
+
+	msg := newTestQueueMessage(t)
+	msg.SetAttributes(map[string]string{"  ": "dropped"})
+
+	data, err := msg.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON: %v", err)
+	}
+	if strings.Contains(string(data), "attributes") || msg.Attributes() != nil {
+		t.Fatalf("expected no attributes, got %s", data)
+	}
+}
+
+func TestQueueMessage_MarshalJSON_KeepsTimestampNumeric(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		at     time.Time
+		wantNS bool
+	}{
+		{at: time.Unix(1700000000, 0)},
+		{at: time.Unix(1700000000, 125_000_000), wantNS: true},
+	} {
+		msg, err := NewQueueMessage("MSG-001", "TEST_PROC", LogLevelInfo, "test payload", tt.at)
+		if err != nil {
+			t.Fatalf("NewQueueMessage: %v", err)
+		}
+		data, err := msg.MarshalJSON()
+		if err != nil {
+			t.Fatalf("MarshalJSON: %v", err)
+		}
+
+		var fields map[string]any
+		if err := json.Unmarshal(data, &fields); err != nil {
+			t.Fatalf("Unmarshal: %v", err)
+		}
+		if ts, ok := fields["timestamp"].(float64); !ok || int64(ts) != 1700000000 {
+			t.Fatalf("timestamp = %v, want the Unix seconds as a number", fields["timestamp"])
+		}
+		if _, ok := fields["timestamp_ns"]; ok != tt.wantNS {
+			t.Fatalf("timestamp_ns present = %t, want %t in %s", ok, tt.wantNS, data)
+		}
+
+		var got QueueMessage
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("UnmarshalJSON: %v", err)
+		}
+		if !got.Timestamp().Equal(tt.at) || got.Attributes() != nil {
+			t.Fatalf("round trip gave %v with attributes %v, want %v and none", got.Timestamp(), got.Attributes(), tt.at)
+		}
+	}
+}
+
+func TestQueueMessage_JSONRoundTrip_PreservesWebhookTarget(t *testing.T) {
+	t.Parallel()
+
+	oracle := []byte(`{
+		"MESSAGE_ID": "8", "PROCESS_NAME": "OMNI_TRACER_API", "LOG_LEVEL": "ERROR", "PAYLOAD": "disk full",
+		"TIMESTAMP": 1700000000, "MODE": "Global", "SEND_TO_WEBHOOK": "TRUE", "WEBHOOK_TARGET": " OPS "
+	}`)
+
+	var msg QueueMessage
+	if err := json.Unmarshal(oracle, &msg); err != nil {
+		t.Fatalf("UnmarshalJSON: %v", err)
+	}
+	if !msg.SendToWebhook() || msg.WebhookTarget() != "OPS" {
+		t.Fatalf("SendToWebhook() = %v, WebhookTarget() = %q; want true, OPS", msg.SendToWebhook(), msg.WebhookTarget())
+	}
+	if msg.Attributes() != nil {
+		t.Fatalf("expected WEBHOOK_TARGET not to become an attribute, got %v", msg.Attributes())
+	}
+
+	data, err := msg.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON: %v", err)
+	}
+	var got QueueMessage
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("UnmarshalJSON: %v", err)
+	}
+	if got.WebhookTarget() != "OPS" {
+		t.Fatalf("WebhookTarget() after round trip = %q, want OPS", got.WebhookTarget())
+	}
+
+	untargeted, err := newTestQueueMessage(t).MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON: %v", err)
+	}
+	if strings.Contains(string(untargeted), "webhook_target") {
+		t.Fatalf("expected no webhook_target without a target, got %s", untargeted)
+	}
+}
+
+func TestLogLevels_OrderedBySeverity(t *testing.T) {
+	t.Parallel()
+
+	levels := LogLevels()
+	if len(levels) != 5 {
+		t.Fatalf("LogLevels() returned %d levels, want 5", len(levels))
+	}
+	for i, level := range levels {
+		if got := level.Severity(); got != i+1 {
+			t.Fatalf("%s.Severity() = %d, want %d", level, got, i+1)
+		}
+	}
+	if got := LogLevel("TRACE").Severity(); got != 0 {
+		t.Fatalf("unknown level severity = %d, want 0", got)
+	}
+}
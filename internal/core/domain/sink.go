@@ -0,0 +1,290 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ==========================================
+// Output Sink Configuration Entity
+// ==========================================
+
+// SinkConfig describes one output that routed trace messages are written to,
+// such as a file or a syslog server. Type names the plug-in that implements
+// it; Settings are interpreted by that plug-in.
+type SinkConfig struct {
+	ID        string
+	Type      string
+	Settings  map[string]string // Plug-in specific, e.g. path=/var/log/omniview.ndjson; encrypted at rest
+	Enabled   bool
+	UpdatedAt time.Time
+}
+
+// Webhook delivery is the "webhook" sink type. A sink and a rule under
+// WebhookRouteID are created with a new store, so messages sent with
+// Trace_Message_To_Webhook reach their webhook until the route is changed.
+const (
+	WebhookSinkType = "webhook"
+	WebhookRouteID  = "webhooks"
+)
+
+// DefaultWebhookRoute returns the webhook sink and rule a new store is
+// seeded with.
+func DefaultWebhookRoute() (SinkConfig, RoutingRule) {
+	now := time.Now()
+	sink := SinkConfig{ID: WebhookRouteID, Type: WebhookSinkType, Enabled: true, UpdatedAt: now}
+	rule := RoutingRule{
+		ID:        WebhookRouteID,
+		Sinks:     []string{WebhookRouteID},
+		Mode:      BroadcastModeGlobal,
+		Flagged:   true,
+		Enabled:   true,
+		UpdatedAt: now,
+	}
+	return sink, rule
+}
+
+// idPattern limits sink, rule and webhook IDs to names that are easy to type
+// in a rule's sink list or a PL/SQL target_ argument. It excludes ':', which
+// marks the pointer keys stored beside the webhooks.
+var idPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,64}$`)
+
+// NewSinkConfig creates a SinkConfig with validation. The settings are
+// checked by the plug-in when the sink is opened.
+func NewSinkConfig(id, sinkType string, settings map[string]string, enabled bool) (*SinkConfig, error) {
+	id = strings.TrimSpace(id)
+	if !idPattern.MatchString(id) {
+		return nil, fmt.Errorf("%w: ID must be 1-64 letters, digits, '.', '_' or '-'", ErrInvalidSink)
+	}
+	sinkType = strings.ToLower(strings.TrimSpace(sinkType))
+	if sinkType == "" {
+		return nil, fmt.Errorf("%w: type cannot be empty", ErrInvalidSink)
+	}
+
+	return &SinkConfig{
+		ID:        id,
+		Type:      sinkType,
+		Settings:  settings,
+		Enabled:   enabled,
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+// Setting returns the trimmed value of a setting, or "" when it is not set.
+func (c SinkConfig) Setting(key string) string {
+	return strings.TrimSpace(c.Settings[key])
+}
+
+// SettingsString renders the settings in the key=value,key=value form read
+// by ParseSinkSettings, sorted by key.
+func (c SinkConfig) SettingsString() string {
+	return formatKeyValues(c.Settings)
+}
+
+// ParseSinkSettings reads comma-separated key=value pairs with URL-encoded values.
+func ParseSinkSettings(s string) (map[string]string, error) {
+	return parseKeyValues(s, ErrInvalidSink)
+}
+
+type sinkConfigJSON struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Settings  string    `json:"settings,omitempty"`
+	Enabled   bool      `json:"enabled"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// MarshalJSON implements custom JSON marshaling for SinkConfig.
+// Settings may carry credentials, such as a collector API key, so the whole
+// set is encrypted at rest via the configured CredentialCipher.
+func (c SinkConfig) MarshalJSON() ([]byte, error) {
+	settings := c.SettingsString()
+	if settings != "" {
+		encrypted, err := credentialCipher.Encrypt(settings)
+		if err != nil {
+			return nil, fmt.Errorf("encrypt sink settings: %w", err)
+		}
+		settings = encrypted
+	}
+	return json.Marshal(sinkConfigJSON{
+		ID:        c.ID,
+		Type:      c.Type,
+		Settings:  settings,
+		Enabled:   c.Enabled,
+		UpdatedAt: c.UpdatedAt,
+	})
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling for SinkConfig
+func (c *SinkConfig) UnmarshalJSON(data []byte) error {
+	var j sinkConfigJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	settings := j.Settings
+	if settings != "" {
+		decrypted, err := credentialCipher.Decrypt(settings)
+		if err != nil {
+			return fmt.Errorf("decrypt sink settings: %w", err)
+		}
+		settings = decrypted
+	}
+	parsed, err := ParseSinkSettings(settings)
+	if err != nil {
+		return err
+	}
+
+	*c = SinkConfig{
+		ID:        j.ID,
+		Type:      j.Type,
+		Settings:  parsed,
+		Enabled:   j.Enabled,
+		UpdatedAt: j.UpdatedAt,
+	}
+	return nil
+}
+
+// ==========================================
+// Routing Rule Entity
+// ==========================================
+
+// RoutingRule sends the messages it matches to a list of sinks. Empty
+// conditions match everything, so a rule with only sinks routes every
+// message. A message is written once to each sink named by any enabled rule
+// it matches.
+type RoutingRule struct {
+	ID        string
+	Sinks     []string      // Sink IDs
+	MinLevel  LogLevel      // Lowest level matched; empty for every level
+	Process   string        // Regular expression on the process name; empty for every process
+	Mode      BroadcastMode // Same meaning as the mode toggle in the UI
+	Payload   string        // Regular expression on the payload; empty for every payload
+	Flagged   bool          // Matches only messages sent with Trace_Message_To_Webhook
+	Enabled   bool
+	UpdatedAt time.Time
+
+	processRE *regexp.Regexp
+	payloadRE *regexp.Regexp
+}
+
+// NewRoutingRule creates a RoutingRule with validation, compiling its patterns.
+func NewRoutingRule(id string, sinks []string, minLevel LogLevel, process string, mode BroadcastMode, payload string, enabled bool) (*RoutingRule, error) {
+	id = strings.TrimSpace(id)
+	if !idPattern.MatchString(id) {
+		return nil, fmt.Errorf("%w: ID must be 1-64 letters, digits, '.', '_' or '-'", ErrInvalidRoutingRule)
+	}
+	rule := &RoutingRule{
+		ID:        id,
+		MinLevel:  minLevel,
+		Process:   process,
+		Mode:      mode,
+		Payload:   payload,
+		Enabled:   enabled,
+		UpdatedAt: time.Now(),
+	}
+	for _, sink := range sinks {
+		if sink = strings.TrimSpace(sink); sink != "" && !slices.Contains(rule.Sinks, sink) {
+			rule.Sinks = append(rule.Sinks, sink)
+		}
+	}
+	if len(rule.Sinks) == 0 {
+		return nil, fmt.Errorf("%w: name at least one sink", ErrInvalidRoutingRule)
+	}
+	if minLevel != "" {
+		level, err := NewLogLevel(string(minLevel))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRoutingRule, err)
+		}
+		rule.MinLevel = level
+	}
+	if err := rule.compile(); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// compile prepares the process and payload patterns.
+func (r *RoutingRule) compile() error {
+	var err error
+	r.processRE, r.payloadRE = nil, nil
+	if r.Process != "" {
+		if r.processRE, err = regexp.Compile(r.Process); err != nil {
+			return fmt.Errorf("%w: invalid process pattern: %v", ErrInvalidRoutingRule, err)
+		}
+	}
+	if r.Payload != "" {
+		if r.payloadRE, err = regexp.Compile(r.Payload); err != nil {
+			return fmt.Errorf("%w: invalid payload pattern: %v", ErrInvalidRoutingRule, err)
+		}
+	}
+	return nil
+}
+
+// Matches reports whether msg passes every condition of the rule. It does
+// not look at Enabled.
+func (r *RoutingRule) Matches(msg *QueueMessage) bool {
+	if r.Flagged && !msg.SendToWebhook() {
+		return false
+	}
+	if r.MinLevel != "" && msg.LogLevel().Severity() < r.MinLevel.Severity() {
+		return false
+	}
+	if !r.Mode.Shows(msg) {
+		return false
+	}
+	if r.processRE != nil && !r.processRE.MatchString(msg.ProcessName()) {
+		return false
+	}
+	return r.payloadRE == nil || r.payloadRE.MatchString(msg.Payload())
+}
+
+type routingRuleJSON struct {
+	ID        string    `json:"id"`
+	Sinks     []string  `json:"sinks"`
+	MinLevel  string    `json:"min_level,omitempty"`
+	Process   string    `json:"process,omitempty"`
+	Mode      string    `json:"mode"`
+	Payload   string    `json:"payload,omitempty"`
+	Flagged   bool      `json:"flagged,omitempty"`
+	Enabled   bool      `json:"enabled"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// MarshalJSON implements custom JSON marshaling for RoutingRule
+func (r RoutingRule) MarshalJSON() ([]byte, error) {
+	return json.Marshal(routingRuleJSON{
+		ID:        r.ID,
+		Sinks:     r.Sinks,
+		MinLevel:  string(r.MinLevel),
+		Process:   r.Process,
+		Mode:      r.Mode.String(),
+		Payload:   r.Payload,
+		Flagged:   r.Flagged,
+		Enabled:   r.Enabled,
+		UpdatedAt: r.UpdatedAt,
+	})
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling for RoutingRule
+func (r *RoutingRule) UnmarshalJSON(data []byte) error {
+	var j routingRuleJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	*r = RoutingRule{
+		ID:        j.ID,
+		Sinks:     j.Sinks,
+		MinLevel:  LogLevel(j.MinLevel),
+		Process:   j.Process,
+		Mode:      NewBroadcastMode(j.Mode),
+		Payload:   j.Payload,
+		Flagged:   j.Flagged,
+		Enabled:   j.Enabled,
+		UpdatedAt: j.UpdatedAt,
+	}
+	return r.compile()
+}
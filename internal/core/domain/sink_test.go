@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewSinkConfig_Validates(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := NewSinkConfig(" audit-log ", " File ", map[string]string{"path": "/tmp/a.ndjson"}, true)
+	if err != nil {
+		t.Fatalf("NewSinkConfig: %v", err)
+	}
+	if cfg.ID != "audit-log" || cfg.Type != "file" || cfg.Setting("path") != "/tmp/a.ndjson" {
+		t.Fatalf("cfg = %+v", cfg)
+	}
+
+	for _, tc := range []struct{ id, sinkType string }{{"", "file"}, {"has space", "file"}, {"ok", " "}} {
+		if _, err := NewSinkConfig(tc.id, tc.sinkType, nil, true); !errors.Is(err, ErrInvalidSink) {
+			t.Errorf("NewSinkConfig(%q, %q) = %v, want ErrInvalidSink", tc.id, tc.sinkType, err)
+		}
+	}
+}
+
+func TestSinkConfig_JSONRoundTripEncryptsSettings(t *testing.T) {
+	// Not parallel: swaps the package-level credential cipher.
+	SetCredentialCipher(prefixCipher{})
+	defer SetCredentialCipher(nil)
+
+	cfg, err := NewSinkConfig("otel", "otlp", map[string]string{"endpoint": "http://otel:4318", "headers": "api-key=secret"}, true)
+	if err != nil {
+		t.Fatalf("NewSinkConfig: %v", err)
+	}
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if !strings.Contains(string(data), `"settings":"enc:`) {
+		t.Fatalf("expected the settings to be encrypted, got %s", data)
+	}
+
+	var decoded SinkConfig
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if decoded.Setting("headers") != "api-key=secret" || decoded.Setting("endpoint") != "http://otel:4318" || !decoded.Enabled {
+		t.Fatalf("decoded = %+v", decoded)
+	}
+}
+
+func TestRoutingRule_Matches(t *testing.T) {
+	t.Parallel()
+
+	rule, err := NewRoutingRule("errors", []string{"ops", " ops ", "audit", ""}, "warning", `^ORDER_`, BroadcastModeGlobal, `(?i)timeout`, true)
+	if err != nil {
+		t.Fatalf("NewRoutingRule: %v", err)
+	}
+	if len(rule.Sinks) != 2 || rule.MinLevel != LogLevelWarning {
+		t.Fatalf("expected deduplicated sinks and a normalized level, got %+v", rule)
+	}
+
+	newMsg := func(level LogLevel, process, payload string) *QueueMessage {
+		msg, err := NewQueueMessage("1", process, level, payload, time.Now())
+		if err != nil {
+			t.Fatalf("NewQueueMessage: %v", err)
+		}
+		return msg
+	}
+	cases := []struct {
+		msg  *QueueMessage
+		want bool
+	}{
+		{newMsg(LogLevelError, "ORDER_API", "DB Timeout"), true},
+		{newMsg(LogLevelInfo, "ORDER_API", "DB Timeout"), false},
+		{newMsg(LogLevelError, "BILLING", "DB Timeout"), false},
+		{newMsg(LogLevelError, "ORDER_API", "done"), false},
+	}
+	for i, tc := range cases {
+		if got := rule.Matches(tc.msg); got != tc.want {
+			t.Errorf("case %d: Matches = %v, want %v", i, got, tc.want)
+		}
+	}
+
+	everything, err := NewRoutingRule("all", []string{"ops"}, "", "", BroadcastModeGlobal, "", true)
+	if err != nil {
+		t.Fatalf("NewRoutingRule: %v", err)
+	}
+	if !everything.Matches(newMsg(LogLevelDebug, "", "x")) {
+		t.Fatal("expected a rule without conditions to match every message")
+	}
+
+	everything.Flagged = true
+	flagged, err := NewQueueMessage("2", "", LogLevelDebug, "x", time.Now(), true)
+	if err != nil {
+		t.Fatalf("NewQueueMessage: %v", err)
+	}
+	if everything.Matches(newMsg(LogLevelDebug, "", "x")) || !everything.Matches(flagged) {
+		t.Fatal("expected a flagged rule to match only messages sent with the webhook flag")
+	}
+}
+
+func TestNewRoutingRule_RejectsInvalidRules(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		sinks            []string
+		level            LogLevel
+		process, payload string
+	}{
+		{nil, "", "", ""},
+		{[]string{"ops"}, "LOUD", "", ""},
+		{[]string{"ops"}, "", "(", ""},
+		{[]string{"ops"}, "", "", "["},
+	}
+	for _, tc := range cases {
+		if _, err := NewRoutingRule("r", tc.sinks, tc.level, tc.process, BroadcastModeGlobal, tc.payload, true); !errors.Is(err, ErrInvalidRoutingRule) {
+			t.Errorf("NewRoutingRule(%v, %q, %q, %q) = %v, want ErrInvalidRoutingRule", tc.sinks, tc.level, tc.process, tc.payload, err)
+		}
+	}
+}
+
+func TestRoutingRule_JSONRoundTripRecompilesPatterns(t *testing.T) {
+	t.Parallel()
+
+	rule, err := NewRoutingRule("subs", []string{"file"}, LogLevelInfo, `^P$`, BroadcastModeSubscriber, "", false)
+	if err != nil {
+		t.Fatalf("NewRoutingRule: %v", err)
+	}
+	rule.Flagged = true
+	data, err := json.Marshal(rule)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var decoded RoutingRule
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if decoded.Mode != BroadcastModeSubscriber || decoded.Enabled || decoded.MinLevel != LogLevelInfo || !decoded.Flagged {
+		t.Fatalf("decoded = %+v", decoded)
+	}
+	msg, _ := NewQueueMessage("1", "Q", LogLevelInfo, "x", time.Now())
+	if decoded.Matches(msg) {
+		t.Fatal("expected the decoded process pattern to reject another process")
+	}
+}
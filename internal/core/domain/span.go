@@ -0,0 +1,177 @@
+package domain
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ==========================================
+// Span Marker Value Object
+// ==========================================
+
+// SpanEvent tells whether a trace message opens or closes a span.
+type SpanEvent string
+
+const (
+	SpanEventBegin SpanEvent = "BEGIN"
+	SpanEventEnd   SpanEvent = "END"
+)
+
+// SpanMarker is the span information carried by a message sent through
+// OMNI_TRACER_API.Trace_Begin or Trace_End.
+type SpanMarker struct {
+	ID       string
+	ParentID string // Empty for a root span
+	Name     string // Always set on BEGIN; END carries it when the package still knew the span
+	Event    SpanEvent
+}
+
+// NewSpanMarker creates a SpanMarker with validation
+func NewSpanMarker(id, parentID, name string, event SpanEvent) (SpanMarker, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return SpanMarker{}, fmt.Errorf("%w: span ID cannot be empty", ErrInvalidSpan)
+	}
+	event = SpanEvent(strings.ToUpper(strings.TrimSpace(string(event))))
+	if event != SpanEventBegin && event != SpanEventEnd {
+		return SpanMarker{}, fmt.Errorf("%w: unknown event %q", ErrInvalidSpan, event)
+	}
+	return SpanMarker{
+		ID:       id,
+		ParentID: strings.TrimSpace(parentID),
+		Name:     strings.TrimSpace(name),
+		Event:    event,
+	}, nil
+}
+
+// ==========================================
+// Span Entity
+// ==========================================
+
+// Span is a named unit of work paired from its BEGIN and END messages.
+// Either end may be missing: Start is zero when only the END message was
+// seen, End is zero while the span is still open.
+type Span struct {
+	ID       string
+	ParentID string
+	Name     string
+	Process  string
+	Start    time.Time
+	End      time.Time
+
+	beginMessageID string
+	endMessageID   string
+}
+
+// Open reports whether the span has started and not yet ended.
+func (s *Span) Open() bool { return !s.Start.IsZero() && s.End.IsZero() }
+
+// Duration returns the time between the BEGIN and END messages. ok is false
+// until both have been seen.
+func (s *Span) Duration() (d time.Duration, ok bool) {
+	if s.Start.IsZero() || s.End.IsZero() {
+		return 0, false
+	}
+	return max(s.End.Sub(s.Start), 0), true
+}
+
+// ==========================================
+// Span Tracker
+// ==========================================
+
+// SpanTracker pairs span BEGIN and END messages by span ID. It only holds
+// spans whose messages are still buffered; Forget drops them again as
+// messages are evicted.
+type SpanTracker struct {
+	spans map[string]*Span
+}
+
+// NewSpanTracker creates an empty SpanTracker.
+func NewSpanTracker() *SpanTracker {
+	return &SpanTracker{spans: make(map[string]*Span)}
+}
+
+// Observe records the span marker of msg and returns the updated span, or
+// nil when msg carries no marker.
+func (t *SpanTracker) Observe(msg *QueueMessage) *Span {
+	marker, ok := msg.Span()
+	if !ok {
+		return nil
+	}
+	span, ok := t.spans[marker.ID]
+	if !ok {
+		span = &Span{ID: marker.ID}
+		t.spans[marker.ID] = span
+	}
+	switch marker.Event {
+	case SpanEventBegin:
+		span.Start = msg.Timestamp()
+		span.Process = msg.ProcessName()
+		span.ParentID = marker.ParentID
+		span.beginMessageID = msg.MessageID()
+		if marker.Name != "" {
+			span.Name = marker.Name
+		}
+	case SpanEventEnd:
+		span.End = msg.Timestamp()
+		span.endMessageID = msg.MessageID()
+		if span.Name == "" {
+			span.Name = marker.Name
+		}
+		if span.Process == "" {
+			span.Process = msg.ProcessName()
+		}
+	}
+	return span
+}
+
+// Span returns the tracked span with the given ID.
+func (t *SpanTracker) Span(id string) (*Span, bool) {
+	span, ok := t.spans[id]
+	return span, ok
+}
+
+// Spans returns every tracked span ordered by start time. Spans whose BEGIN
+// message was not seen sort by their end time.
+func (t *SpanTracker) Spans() []*Span {
+	spans := slices.Collect(maps.Values(t.spans))
+	slices.SortFunc(spans, func(a, b *Span) int {
+		return cmp.Or(spanSortTime(a).Compare(spanSortTime(b)), strings.Compare(a.ID, b.ID))
+	})
+	return spans
+}
+
+// Forget drops the span of an evicted message once nothing useful remains:
+// when its END message goes, or when its BEGIN message goes before it ended.
+func (t *SpanTracker) Forget(msg *QueueMessage) {
+	marker, ok := msg.Span()
+	if !ok {
+		return
+	}
+	span, ok := t.spans[marker.ID]
+	if !ok {
+		return
+	}
+	switch msg.MessageID() {
+	case span.endMessageID:
+		delete(t.spans, marker.ID)
+	case span.beginMessageID:
+		if span.End.IsZero() {
+			delete(t.spans, marker.ID)
+		}
+	}
+}
+
+// Len returns the number of tracked spans.
+func (t *SpanTracker) Len() int { return len(t.spans) }
+
+func spanSortTime(s *Span) time.Time {
+	if s.Start.IsZero() {
+		return s.End
+	}
+	return s.Start
+}
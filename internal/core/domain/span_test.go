@@ -0,0 +1,144 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+// newTestSpanMessage creates a message carrying a span marker at the given time.
+func newTestSpanMessage(t *testing.T, id string, at time.Time, marker SpanMarker) *QueueMessage {
+	t.Helper()
+	msg, err := NewQueueMessage(id, "ORDER_API", LogLevelInfo, "span "+string(marker.Event), at)
+	if err != nil {
+		t.Fatalf("NewQueueMessage() returned error: %v", err)
+	}
+	msg.SetSpan(marker)
+	return msg
+}
+
+func TestNewSpanMarker_Validation(t *testing.T) {
+	t.Parallel()
+
+	marker, err := NewSpanMarker(" ABC ", "", "load", "begin")
+	if err != nil {
+		t.Fatalf("NewSpanMarker() returned error: %v", err)
+	}
+	if marker.ID != "ABC" || marker.Event != SpanEventBegin {
+		t.Fatalf("NewSpanMarker() = %+v, want trimmed ID and upper-case event", marker)
+	}
+
+	if _, err := NewSpanMarker("", "", "load", SpanEventBegin); !errors.Is(err, ErrInvalidSpan) {
+		t.Fatalf("empty ID error = %v, want ErrInvalidSpan", err)
+	}
+	if _, err := NewSpanMarker("ABC", "", "load", "PAUSE"); !errors.Is(err, ErrInvalidSpan) {
+		t.Fatalf("unknown event error = %v, want ErrInvalidSpan", err)
+	}
+}
+
+// TestQueueMessage_UnmarshalJSON_SpanMarker verifies that the SPAN object sent
+// by Trace_Begin is decoded as a marker rather than an attribute, and that the
+// marker and millisecond timestamp survive a round trip.
+func TestQueueMessage_UnmarshalJSON_SpanMarker(t *testing.T) {
+	t.Parallel()
+
+	oracle := []byte(`{
+		"MESSAGE_ID": "9", "PROCESS_NAME": "ORDER_API", "LOG_LEVEL": "INFO", "PAYLOAD": "Begin load",
+		"TIMESTAMP": "2026-03-03T13:43:07.125+05:30", "MODE": "Global",
+		"SPAN": {"ID": "4F1A", "NAME": "load", "PARENT_ID": "0B2C", "EVENT": "BEGIN"}
+	}`)
+
+	var msg QueueMessage
+	if err := json.Unmarshal(oracle, &msg); err != nil {
+		t.Fatalf("UnmarshalJSON: %v", err)
+	}
+	want := SpanMarker{ID: "4F1A", ParentID: "0B2C", Name: "load", Event: SpanEventBegin}
+	if got, ok := msg.Span(); !ok || got != want {
+		t.Fatalf("Span() = %+v, %v; want %+v", got, ok, want)
+	}
+	if msg.Attributes() != nil {
+		t.Fatalf("expected the span not to be kept as an attribute, got %v", msg.Attributes())
+	}
+
+	data, err := msg.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON: %v", err)
+	}
+	var got QueueMessage
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("UnmarshalJSON: %v", err)
+	}
+	if marker, _ := got.Span(); marker != want {
+		t.Fatalf("Span() after round trip = %+v, want %+v", marker, want)
+	}
+	if !got.Timestamp().Equal(msg.Timestamp()) {
+		t.Fatalf("Timestamp() after round trip = %v, want %v", got.Timestamp(), msg.Timestamp())
+	}
+}
+
+func TestQueueMessage_UnmarshalJSON_IgnoresMalformedSpan(t *testing.T) {
+	t.Parallel()
+
+	var msg QueueMessage
+	data := []byte(`{"message_id":"1","log_level":"INFO","payload":"p","timestamp":1700000000,"span":"not an object"}`)
+	if err := json.Unmarshal(data, &msg); err != nil {
+		t.Fatalf("UnmarshalJSON: %v", err)
+	}
+	if _, ok := msg.Span(); ok {
+		t.Fatal("expected a malformed span to be ignored")
+	}
+}
+
+func TestSpanTracker_PairsBeginAndEnd(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(1700000000, 0)
+	tracker := NewSpanTracker()
+	begin := newTestSpanMessage(t, "1", start, SpanMarker{ID: "S1", Name: "load", Event: SpanEventBegin})
+	end := newTestSpanMessage(t, "2", start.Add(1250*time.Millisecond), SpanMarker{ID: "S1", Event: SpanEventEnd})
+
+	if span := tracker.Observe(begin); span == nil || !span.Open() {
+		t.Fatal("expected the BEGIN message to open the span")
+	}
+	span := tracker.Observe(end)
+	if span == nil || span.Open() || span.Name != "load" || span.Process != "ORDER_API" {
+		t.Fatalf("expected the END message to close the span, got %+v", span)
+	}
+	if d, ok := span.Duration(); !ok || d != 1250*time.Millisecond {
+		t.Fatalf("Duration() = %v, %v; want 1.25s", d, ok)
+	}
+	if tracker.Observe(newTestQueueMessage(t)) != nil {
+		t.Fatal("expected a message without a marker to be ignored")
+	}
+}
+
+func TestSpanTracker_ForgetFollowsEviction(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(1700000000, 0)
+	tracker := NewSpanTracker()
+	closedBegin := newTestSpanMessage(t, "1", start, SpanMarker{ID: "S1", Name: "outer", Event: SpanEventBegin})
+	openBegin := newTestSpanMessage(t, "2", start.Add(time.Second), SpanMarker{ID: "S2", ParentID: "S1", Name: "inner", Event: SpanEventBegin})
+	closedEnd := newTestSpanMessage(t, "3", start.Add(2*time.Second), SpanMarker{ID: "S1", Event: SpanEventEnd})
+	for _, msg := range []*QueueMessage{closedBegin, openBegin, closedEnd} {
+		tracker.Observe(msg)
+	}
+	if spans := tracker.Spans(); len(spans) != 2 || spans[0].ID != "S1" || spans[1].ID != "S2" {
+		t.Fatalf("Spans() = %+v, want S1 then S2", spans)
+	}
+
+	// A closed span keeps its duration when only the BEGIN message is evicted.
+	tracker.Forget(closedBegin)
+	if _, ok := tracker.Span("S1"); !ok {
+		t.Fatal("expected S1 to survive eviction of its BEGIN message")
+	}
+	tracker.Forget(openBegin)
+	if _, ok := tracker.Span("S2"); ok {
+		t.Fatal("expected an open span to be dropped with its BEGIN message")
+	}
+	tracker.Forget(closedEnd)
+	if tracker.Len() != 0 {
+		t.Fatalf("expected no spans after evicting every message, got %d", tracker.Len())
+	}
+}
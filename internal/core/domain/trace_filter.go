@@ -0,0 +1,405 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// ==========================================
+// Trace Filter
+// ==========================================
+
+// TraceFilter is a parsed filter expression that selects trace messages, e.g.
+//
+//	level>=WARNING and process~"ORDER_" and payload contains "timeout"
+//
+// Grammar (keywords are case-insensitive):
+//
+//	expr       = term { "or" term }
+//	term       = factor { "and" factor }
+//	factor     = "not" factor | "(" expr ")" | comparison
+//	comparison = field op value
+//	field      = level | process | payload | mode | id | "attr." name
+//	op         = "=" | "==" | "!=" | "<" | "<=" | ">" | ">=" | "~" | "!~" | "contains"
+//
+// Values are bare words or single/double quoted strings. level supports the
+// ordering operators by severity; the other fields support equality
+// (case-insensitive), "~" / "!~" (regular expression) and "contains"
+// (case-insensitive substring). attr.<name> compares a message attribute and
+// reads as empty when the message lacks it. A nil *TraceFilter matches every
+// message.
+type TraceFilter struct {
+	expr string
+	root filterNode
+}
+
+// TraceFilterError describes where an expression failed to parse.
+// It wraps ErrInvalidTraceFilter.
+type TraceFilterError struct {
+	Pos int // Byte offset into the expression
+	Msg string
+}
+
+func (e *TraceFilterError) Error() string {
+	return fmt.Sprintf("%s at column %d", e.Msg, e.Pos+1)
+}
+
+func (e *TraceFilterError) Unwrap() error { return ErrInvalidTraceFilter }
+
+// ParseTraceFilter parses expr into a TraceFilter. An empty expression
+// returns a nil filter, which matches everything.
+func ParseTraceFilter(expr string) (*TraceFilter, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, nil
+	}
+
+	tokens, err := lexTraceFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, &TraceFilterError{Pos: tok.pos, Msg: fmt.Sprintf("unexpected %q", tok.text)}
+	}
+	return &TraceFilter{expr: expr, root: root}, nil
+}
+
+// Match reports whether msg satisfies the filter.
+func (f *TraceFilter) Match(msg *QueueMessage) bool {
+	if f == nil || f.root == nil {
+		return true
+	}
+	return f.root.match(msg)
+}
+
+// String returns the source expression.
+func (f *TraceFilter) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.expr
+}
+
+// ==========================================
+// Evaluation
+// ==========================================
+
+type filterNode interface {
+	match(msg *QueueMessage) bool
+}
+
+type filterAnd struct{ left, right filterNode }
+type filterOr struct{ left, right filterNode }
+type filterNot struct{ inner filterNode }
+
+func (n filterAnd) match(msg *QueueMessage) bool { return n.left.match(msg) && n.right.match(msg) }
+func (n filterOr) match(msg *QueueMessage) bool  { return n.left.match(msg) || n.right.match(msg) }
+func (n filterNot) match(msg *QueueMessage) bool { return !n.inner.match(msg) }
+
+// filterLevel compares the message level by severity.
+type filterLevel struct {
+	op       string
+	severity int
+}
+
+func (n filterLevel) match(msg *QueueMessage) bool {
+	got := msg.LogLevel().Severity()
+	switch n.op {
+	case "=":
+		return got == n.severity
+	case "!=":
+		return got != n.severity
+	case "<":
+		return got < n.severity
+	case "<=":
+		return got <= n.severity
+	case ">":
+		return got > n.severity
+	default: // ">="
+		return got >= n.severity
+	}
+}
+
+// filterText applies a string operator to one message field.
+type filterText struct {
+	field func(*QueueMessage) string
+	op    string
+	value string         // Lower-cased for "=", "!=" and "contains"
+	re    *regexp.Regexp // Set for "~" and "!~"
+}
+
+func (n filterText) match(msg *QueueMessage) bool {
+	got := n.field(msg)
+	switch n.op {
+	case "~":
+		return n.re.MatchString(got)
+	case "!~":
+		return !n.re.MatchString(got)
+	case "contains":
+		return strings.Contains(strings.ToLower(got), n.value)
+	case "!=":
+		return strings.ToLower(got) != n.value
+	default: // "="
+		return strings.ToLower(got) == n.value
+	}
+}
+
+// attrFieldPrefix introduces an attribute field, e.g. attr.tenant.
+const attrFieldPrefix = "attr."
+
+// attributeField returns a field getter for the attribute name.
+func attributeField(name string) func(*QueueMessage) string {
+	return func(msg *QueueMessage) string {
+		value, _ := msg.Attribute(name)
+		return value
+	}
+}
+
+var traceFilterFields = map[string]func(*QueueMessage) string{
+	"process": (*QueueMessage).ProcessName,
+	"payload": (*QueueMessage).Payload,
+	"mode":    (*QueueMessage).Mode,
+	"id":      (*QueueMessage).MessageID,
+}
+
+// ==========================================
+// Lexer
+// ==========================================
+
+type filterTokenKind int
+
+const (
+	tokEOF filterTokenKind = iota
+	tokWord
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+)
+
+type filterToken struct {
+	kind filterTokenKind
+	text string
+	pos  int
+}
+
+func lexTraceFilter(expr string) ([]filterToken, error) {
+	var tokens []filterToken
+	runes := []rune(expr)
+	// offsets maps rune index to byte offset for error positions.
+	offsets := make([]int, len(runes)+1)
+	for i, off := 0, 0; i < len(runes); i++ {
+		offsets[i] = off
+		off += len(string(runes[i]))
+		offsets[i+1] = off
+	}
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, filterToken{kind: tokLParen, text: "(", pos: offsets[i]})
+			i++
+		case r == ')':
+			tokens = append(tokens, filterToken{kind: tokRParen, text: ")", pos: offsets[i]})
+			i++
+		case r == '"' || r == '\'':
+			start := i
+			var b strings.Builder
+			i++
+			closed := false
+			for i < len(runes) {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					b.WriteRune(runes[i+1])
+					i += 2
+					continue
+				}
+				if runes[i] == r {
+					closed = true
+					i++
+					break
+				}
+				b.WriteRune(runes[i])
+				i++
+			}
+			if !closed {
+				return nil, &TraceFilterError{Pos: offsets[start], Msg: "unterminated string"}
+			}
+			tokens = append(tokens, filterToken{kind: tokString, text: b.String(), pos: offsets[start]})
+		case strings.ContainsRune("=!<>~", r):
+			start := i
+			op := string(r)
+			if i+1 < len(runes) && (runes[i+1] == '=' || (r == '!' && runes[i+1] == '~')) {
+				op += string(runes[i+1])
+			}
+			i += len([]rune(op))
+			switch op {
+			case "==":
+				op = "="
+			case "=", "!=", "<", "<=", ">", ">=", "~", "!~":
+			default:
+				return nil, &TraceFilterError{Pos: offsets[start], Msg: fmt.Sprintf("unknown operator %q", op)}
+			}
+			tokens = append(tokens, filterToken{kind: tokOp, text: op, pos: offsets[start]})
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune(`()"'=!<>~`, runes[i]) {
+				i++
+			}
+			tokens = append(tokens, filterToken{kind: tokWord, text: string(runes[start:i]), pos: offsets[start]})
+		}
+	}
+
+	tokens = append(tokens, filterToken{kind: tokEOF, pos: len(expr)})
+	return tokens, nil
+}
+
+// ==========================================
+// Parser
+// ==========================================
+
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+}
+
+func (p *filterParser) peek() filterToken { return p.tokens[p.pos] }
+
+func (p *filterParser) next() filterToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// peekKeyword reports whether the next token is the bare keyword kw.
+func (p *filterParser) peekKeyword(kw string) bool {
+	tok := p.peek()
+	return tok.kind == tokWord && strings.EqualFold(tok.text, kw)
+}
+
+func (p *filterParser) parseOr() (filterNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = filterOr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filterNode, error) {
+	left, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("and") {
+		p.next()
+		right, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		left = filterAnd{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseFactor() (filterNode, error) {
+	tok := p.peek()
+	switch {
+	case p.peekKeyword("not"):
+		p.next()
+		inner, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return filterNot{inner: inner}, nil
+	case tok.kind == tokLParen:
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			return nil, &TraceFilterError{Pos: closing.pos, Msg: "expected )"}
+		}
+		return inner, nil
+	default:
+		return p.parseComparison()
+	}
+}
+
+func (p *filterParser) parseComparison() (filterNode, error) {
+	fieldTok := p.next()
+	if fieldTok.kind != tokWord {
+		return nil, &TraceFilterError{Pos: fieldTok.pos, Msg: "expected a field name"}
+	}
+	field := strings.ToLower(fieldTok.text)
+
+	opTok := p.next()
+	op := opTok.text
+	switch {
+	case opTok.kind == tokOp:
+	case opTok.kind == tokWord && strings.EqualFold(opTok.text, "contains"):
+		op = "contains"
+	default:
+		return nil, &TraceFilterError{Pos: opTok.pos, Msg: fmt.Sprintf("expected an operator after %q", fieldTok.text)}
+	}
+
+	valueTok := p.next()
+	if valueTok.kind != tokWord && valueTok.kind != tokString {
+		return nil, &TraceFilterError{Pos: valueTok.pos, Msg: fmt.Sprintf("expected a value after %q", op)}
+	}
+
+	if field == "level" {
+		switch op {
+		case "=", "!=", "<", "<=", ">", ">=":
+		default:
+			return nil, &TraceFilterError{Pos: opTok.pos, Msg: fmt.Sprintf("operator %q is not supported for level", op)}
+		}
+		level, err := NewLogLevel(valueTok.text)
+		if err != nil {
+			return nil, &TraceFilterError{Pos: valueTok.pos, Msg: fmt.Sprintf("unknown level %q", valueTok.text)}
+		}
+		return filterLevel{op: op, severity: level.Severity()}, nil
+	}
+
+	getter, ok := traceFilterFields[field]
+	if strings.HasPrefix(field, attrFieldPrefix) && len(field) > len(attrFieldPrefix) {
+		getter, ok = attributeField(fieldTok.text[len(attrFieldPrefix):]), true
+	}
+	if !ok {
+		return nil, &TraceFilterError{Pos: fieldTok.pos, Msg: fmt.Sprintf("unknown field %q (use level, process, payload, mode, id or attr.<name>)", fieldTok.text)}
+	}
+
+	node := filterText{field: getter, op: op}
+	switch op {
+	case "~", "!~":
+		re, err := regexp.Compile(valueTok.text)
+		if err != nil {
+			return nil, &TraceFilterError{Pos: valueTok.pos, Msg: fmt.Sprintf("invalid pattern: %v", err)}
+		}
+		node.re = re
+	case "=", "!=", "contains":
+		node.value = strings.ToLower(valueTok.text)
+	default:
+		return nil, &TraceFilterError{Pos: opTok.pos, Msg: fmt.Sprintf("operator %q is not supported for %s", op, field)}
+	}
+	return node, nil
+}
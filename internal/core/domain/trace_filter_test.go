@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func mustNewFilterMessage(t *testing.T, process string, level LogLevel, payload string) *QueueMessage {
+	t.Helper()
+
+	msg, err := NewQueueMessage("id-1", process, level, payload, time.Unix(1700000000, 0))
+	if err != nil {
+		t.Fatalf("NewQueueMessage: %v", err)
+	}
+	return msg
+}
+
+func TestParseTraceFilter_Match(t *testing.T) {
+	t.Parallel()
+
+	orderTimeout := mustNewFilterMessage(t, "ORDER_SUBMIT", LogLevelError, "Gateway Timeout after 30s")
+	orderDebug := mustNewFilterMessage(t, "ORDER_SUBMIT", LogLevelDebug, "timeout budget 30s")
+	billing := mustNewFilterMessage(t, "BILLING_RUN", LogLevelWarning, "slow query")
+
+	tests := []struct {
+		expr string
+		want []bool // orderTimeout, orderDebug, billing
+	}{
+		{`level>=WARNING and process~"ORDER_" and payload contains "timeout"`, []bool{true, false, false}},
+		{`level < warning`, []bool{false, true, false}},
+		{`level = ERROR or process = billing_run`, []bool{true, false, true}},
+		{`not (process ~ "^ORDER_")`, []bool{false, false, true}},
+		{`payload !~ "(?i)timeout" and level != DEBUG`, []bool{false, false, true}},
+		{`mode = global and id == 'id-1'`, []bool{true, true, true}},
+		{`LEVEL >= info AND NOT payload CONTAINS "budget"`, []bool{true, false, true}},
+	}
+
+	for _, tt := range tests {
+		filter, err := ParseTraceFilter(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseTraceFilter(%q): %v", tt.expr, err)
+		}
+		for i, msg := range []*QueueMessage{orderTimeout, orderDebug, billing} {
+			if got := filter.Match(msg); got != tt.want[i] {
+				t.Fatalf("%q on message %d = %v, want %v", tt.expr, i, got, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestParseTraceFilter_MatchesAttributes(t *testing.T) {
+	t.Parallel()
+
+	acme := mustNewFilterMessage(t, "ORDER_API", LogLevelInfo, "order placed")
+	acme.SetAttributes(map[string]string{"tenant": "ACME", "order_id": "4711"})
+	plain := mustNewFilterMessage(t, "ORDER_API", LogLevelInfo, "order placed")
+
+	tests := []struct {
+		expr string
+		want []bool // acme, plain
+	}{
+		{`attr.tenant = acme`, []bool{true, false}},
+		{`ATTR.Order_ID ~ "^47"`, []bool{true, false}},
+		{`attr.tenant = ""`, []bool{false, true}},
+		{`attr.region != emea`, []bool{true, true}},
+	}
+	for _, tt := range tests {
+		filter, err := ParseTraceFilter(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseTraceFilter(%q): %v", tt.expr, err)
+		}
+		for i, msg := range []*QueueMessage{acme, plain} {
+			if got := filter.Match(msg); got != tt.want[i] {
+				t.Fatalf("%q on message %d = %v, want %v", tt.expr, i, got, tt.want[i])
+			}
+		}
+	}
+
+	if _, err := ParseTraceFilter(`attr. = x`); !errors.Is(err, ErrInvalidTraceFilter) {
+		t.Fatalf("expected an attribute name to be required, got %v", err)
+	}
+}
+
+func TestParseTraceFilter_EmptyMatchesEverything(t *testing.T) {
+	t.Parallel()
+
+	filter, err := ParseTraceFilter("   ")
+	if err != nil || filter != nil {
+		t.Fatalf("expected nil filter for blank input, got %v / %v", filter, err)
+	}
+	if !filter.Match(mustNewFilterMessage(t, "P", LogLevelInfo, "x")) {
+		t.Fatal("nil filter should match every message")
+	}
+}
+
+func TestParseTraceFilter_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		expr string
+		pos  int
+	}{
+		{`level >= LOUD`, 9},
+		{`colour = red`, 0},
+		{`payload contains`, 16},
+		{`process ~ "[unclosed"`, 10},
+		{`payload contains "open`, 17},
+		{`(level = INFO`, 13},
+		{`level ~ INFO`, 6},
+		{`level = INFO extra`, 13},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseTraceFilter(tt.expr)
+		if !errors.Is(err, ErrInvalidTraceFilter) {
+			t.Fatalf("ParseTraceFilter(%q) error = %v, want ErrInvalidTraceFilter", tt.expr, err)
+		}
+		var filterErr *TraceFilterError
+		if !errors.As(err, &filterErr) || filterErr.Pos != tt.pos {
+			t.Fatalf("ParseTraceFilter(%q) error position = %+v, want %d", tt.expr, filterErr, tt.pos)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ==========================================
+// Trace Session Entity
+// ==========================================
+
+// TraceSession describes one listener run recorded in the trace history store.
+// A session starts when the event listener starts for a database and collects
+// every message delivered until the listener is replaced.
+type TraceSession struct {
+	ID            string    `json:"id"`
+	DatabaseID    string    `json:"database_id"`
+	Subscriber    string    `json:"subscriber"`
+	StartedAt     time.Time `json:"started_at"`
+	LastMessageAt time.Time `json:"last_message_at"`
+	MessageCount  int       `json:"message_count"`
+	Bytes         int64     `json:"bytes"`
+}
+
+// NewTraceSession creates a new TraceSession keyed by its start time.
+func NewTraceSession(databaseID string, subscriber string, startedAt time.Time) (*TraceSession, error) {
+	databaseID = strings.TrimSpace(databaseID)
+	if databaseID == "" {
+		return nil, ErrEmptyDatabaseID
+	}
+	if startedAt.IsZero() {
+		return nil, ErrInvalidTimestamp
+	}
+
+	return &TraceSession{
+		ID:         TraceSessionID(startedAt),
+		DatabaseID: databaseID,
+		Subscriber: subscriber,
+		StartedAt:  startedAt,
+	}, nil
+}
+
+// TraceSessionID returns the sortable session key for a start time.
+// Fixed-width hex keeps lexical order identical to chronological order.
+func TraceSessionID(startedAt time.Time) string {
+	return fmt.Sprintf("%016x", startedAt.UnixNano())
+}
+
+// LastActivity returns the time of the last recorded message, or the start time for empty sessions.
+func (s TraceSession) LastActivity() time.Time {
+	if s.LastMessageAt.IsZero() {
+		return s.StartedAt
+	}
+	return s.LastMessageAt
+}
+
+// ==========================================
+// History Retention Value Object
+// ==========================================
+
+// HistoryRetention bounds the trace history store by age and total size per database.
+// A zero MaxAge or MaxBytes disables that limit.
+type HistoryRetention struct {
+	MaxAge   time.Duration `json:"max_age"`
+	MaxBytes int64         `json:"max_bytes"`
+}
+
+const (
+	DefaultHistoryMaxAge   = 7 * 24 * time.Hour
+	DefaultHistoryMaxBytes = 256 << 20
+)
+
+var (
+	historyAgePresets  = []time.Duration{24 * time.Hour, DefaultHistoryMaxAge, 30 * 24 * time.Hour, 0}
+	historySizePresets = []int64{64 << 20, DefaultHistoryMaxBytes, 1 << 30, 0}
+)
+
+// NewHistoryRetention creates a HistoryRetention with validation
+func NewHistoryRetention(maxAge time.Duration, maxBytes int64) (HistoryRetention, error) {
+	if maxAge < 0 || maxBytes < 0 {
+		return HistoryRetention{}, fmt.Errorf("%w: age=%s bytes=%d", ErrInvalidRetention, maxAge, maxBytes)
+	}
+	return HistoryRetention{MaxAge: maxAge, MaxBytes: maxBytes}, nil
+}
+
+// DefaultHistoryRetention returns the retention used when none has been stored yet.
+func DefaultHistoryRetention() HistoryRetention {
+	return HistoryRetention{MaxAge: DefaultHistoryMaxAge, MaxBytes: DefaultHistoryMaxBytes}
+}
+
+// Cutoff returns the oldest timestamp still retained at now.
+// Returns the zero time when age-based retention is disabled.
+func (r HistoryRetention) Cutoff(now time.Time) time.Time {
+	if r.MaxAge <= 0 {
+		return time.Time{}
+	}
+	return now.Add(-r.MaxAge)
+}
+
+// NextAge returns a copy with MaxAge advanced to the next preset.
+func (r HistoryRetention) NextAge() HistoryRetention {
+	r.MaxAge = historyAgePresets[nextPresetIndex(historyAgePresets, r.MaxAge)]
+	return r
+}
+
+// NextSize returns a copy with MaxBytes advanced to the next preset.
+func (r HistoryRetention) NextSize() HistoryRetention {
+	r.MaxBytes = historySizePresets[nextPresetIndex(historySizePresets, r.MaxBytes)]
+	return r
+}
+
+// AgeString returns a human-readable form of MaxAge.
+func (r HistoryRetention) AgeString() string {
+	if r.MaxAge <= 0 {
+		return "unlimited"
+	}
+	if r.MaxAge%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", int(r.MaxAge/(24*time.Hour)))
+	}
+	return r.MaxAge.String()
+}
+
+// SizeString returns a human-readable form of MaxBytes.
+func (r HistoryRetention) SizeString() string {
+	if r.MaxBytes <= 0 {
+		return "unlimited"
+	}
+	if r.MaxBytes >= 1<<30 && r.MaxBytes%(1<<30) == 0 {
+		return fmt.Sprintf("%d GiB", r.MaxBytes>>30)
+	}
+	return fmt.Sprintf("%d MiB", r.MaxBytes>>20)
+}
+
+func nextPresetIndex[T comparable](presets []T, current T) int {
+	for i, preset := range presets {
+		if preset == current {
+			return (i + 1) % len(presets)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewTraceSession_Validation(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewTraceSession("  ", "SUB", time.Now()); !errors.Is(err, ErrEmptyDatabaseID) {
+		t.Fatalf("expected ErrEmptyDatabaseID, got %v", err)
+	}
+	if _, err := NewTraceSession("DEV", "SUB", time.Time{}); !errors.Is(err, ErrInvalidTimestamp) {
+		t.Fatalf("expected ErrInvalidTimestamp, got %v", err)
+	}
+
+	earlier := time.Unix(1700000000, 0)
+	later := earlier.Add(time.Nanosecond)
+	a, err := NewTraceSession("DEV", "SUB", earlier)
+	if err != nil {
+		t.Fatalf("NewTraceSession: %v", err)
+	}
+	b, _ := NewTraceSession("DEV", "SUB", later)
+	if !(a.ID < b.ID) {
+		t.Fatalf("expected session IDs to sort chronologically, got %q >= %q", a.ID, b.ID)
+	}
+	if !a.LastActivity().Equal(earlier) {
+		t.Fatalf("LastActivity() of an empty session = %v, want start time", a.LastActivity())
+	}
+}
+
+func TestNewHistoryRetention_RejectsNegativeLimits(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewHistoryRetention(-time.Hour, 0); !errors.Is(err, ErrInvalidRetention) {
+		t.Fatalf("expected ErrInvalidRetention for negative age, got %v", err)
+	}
+	if _, err := NewHistoryRetention(0, -1); !errors.Is(err, ErrInvalidRetention) {
+		t.Fatalf("expected ErrInvalidRetention for negative size, got %v", err)
+	}
+	if r, err := NewHistoryRetention(0, 0); err != nil || !r.Cutoff(time.Now()).IsZero() {
+		t.Fatalf("expected unlimited retention to have no cutoff, got %+v / %v", r, err)
+	}
+}
+
+func TestHistoryRetention_PresetsCycle(t *testing.T) {
+	t.Parallel()
+
+	r := DefaultHistoryRetention()
+	seen := map[string]bool{}
+	for i := 0; i < len(historyAgePresets); i++ {
+		r = r.NextAge()
+		seen[r.AgeString()] = true
+	}
+	if r.MaxAge != DefaultHistoryMaxAge {
+		t.Fatalf("expected age presets to cycle back to default, got %s", r.AgeString())
+	}
+	if !seen["unlimited"] || !seen["30d"] {
+		t.Fatalf("expected presets to include 30d and unlimited, got %v", seen)
+	}
+
+	if got := r.NextSize().SizeString(); got != "1 GiB" {
+		t.Fatalf("NextSize() from default = %q, want 1 GiB", got)
+	}
+}
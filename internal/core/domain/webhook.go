@@ -1,8 +1,12 @@
 package domain
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/textproto"
 	"net/url"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -11,17 +15,30 @@ import (
 // Webhook Configuration Entity
 // ==========================================
 
-// WebhookConfig represents a webhook configuration stored in BoltDB
+// WebhookConfig represents a named webhook endpoint stored in BoltDB.
+// Trace_Message_To_Webhook picks one by ID through its target_ parameter;
+// messages without a target go to the default webhook.
 type WebhookConfig struct {
-	ID        string    `json:"id"`
-	URL       string    `json:"url"`
-	Enabled   bool      `json:"enabled"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        string          `json:"id"`
+	URL       string          `json:"url"`
+	Enabled   bool            `json:"enabled"`
+	MinLevel  LogLevel        // Least severe level delivered; empty delivers every level
+	Format    WebhookFormat   // Request body layout; empty sends the OmniView JSON envelope
+	Template  string          // text/template source for WebhookFormatTemplate
+	Batching  WebhookBatching // Groups bursts into digests; the zero value sends every message on its own
+	Limits    WebhookLimits   // Suppresses repeats and caps the request rate; the zero value limits nothing
+	Auth      WebhookAuth     // How requests prove their origin; secrets are encrypted at rest
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
 
-// NewWebhookConfig creates a new WebhookConfig with URL validation
+// NewWebhookConfig creates a new WebhookConfig with ID and URL validation
 func NewWebhookConfig(id string, urlStr string, enabled bool) (*WebhookConfig, error) {
+	id = strings.TrimSpace(id)
+	if !idPattern.MatchString(id) {
+		return nil, fmt.Errorf("webhook ID must be 1-64 letters, digits, '.', '_' or '-'")
+	}
+
 	parsedURL, err := url.Parse(urlStr)
 	if err != nil {
 		return nil, fmt.Errorf("invalid URL: %w", err)
@@ -55,11 +72,559 @@ func (w *WebhookConfig) IsConfigured() bool {
 	return w != nil && w.URL != "" && w.Enabled
 }
 
+// SetMinLevel sets the least severe level the webhook delivers. An empty
+// level delivers every message.
+func (w *WebhookConfig) SetMinLevel(level string) error {
+	if strings.TrimSpace(level) == "" {
+		w.MinLevel = ""
+		return nil
+	}
+	normalized, err := NewLogLevel(level)
+	if err != nil {
+		return err
+	}
+	w.MinLevel = normalized
+	return nil
+}
+
+// Accepts reports whether a message at level should be delivered: the
+// webhook must be configured and the level at least MinLevel.
+func (w *WebhookConfig) Accepts(level LogLevel) bool {
+	if !w.IsConfigured() {
+		return false
+	}
+	return w.MinLevel == "" || level.Severity() >= w.MinLevel.Severity()
+}
+
+// MatchesTarget reports whether the webhook is the one a message names.
+// Targets are compared case-insensitively, since PL/SQL callers tend to
+// write them in upper case.
+func (w *WebhookConfig) MatchesTarget(target string) bool {
+	return w != nil && strings.EqualFold(w.ID, strings.TrimSpace(target))
+}
+
+// SetFormat sets the request body layout. A custom template must be given
+// with WebhookFormatTemplate and is dropped for every other format.
+func (w *WebhookConfig) SetFormat(format WebhookFormat, template string) error {
+	if !slices.Contains(WebhookFormats(), format) {
+		return fmt.Errorf("%w: unknown format %q", ErrInvalidWebhookFormat, format)
+	}
+	template = strings.TrimSpace(template)
+	if format != WebhookFormatTemplate {
+		template = ""
+	} else if template == "" {
+		return fmt.Errorf("%w: the custom format needs a template", ErrInvalidWebhookFormat)
+	}
+	w.Format = format
+	w.Template = template
+	return nil
+}
+
+// ==========================================
+// Webhook Payload Formats
+// ==========================================
+
+// WebhookFormat selects how a message is laid out in the request body.
+type WebhookFormat string
+
+const (
+	WebhookFormatJSON        WebhookFormat = ""                  // {message, log_level, timestamp} with the message as a JSON string
+	WebhookFormatSlack       WebhookFormat = "slack"             // Slack Block Kit
+	WebhookFormatTeams       WebhookFormat = "teams"             // Microsoft Teams Adaptive Card, for Workflows webhooks
+	WebhookFormatMessageCard WebhookFormat = "teams-messagecard" // Microsoft Teams MessageCard, for Office 365 connectors
+	WebhookFormatDiscord     WebhookFormat = "discord"           // Discord embed
+	WebhookFormatTemplate    WebhookFormat = "template"          // User-defined Go text/template
+)
+
+// WebhookFormats returns every format in the order the settings list them.
+func WebhookFormats() []WebhookFormat {
+	return []WebhookFormat{
+		WebhookFormatJSON, WebhookFormatSlack, WebhookFormatTeams,
+		WebhookFormatMessageCard, WebhookFormatDiscord, WebhookFormatTemplate,
+	}
+}
+
+// Label returns the name shown for the format in the settings.
+func (f WebhookFormat) Label() string {
+	switch f {
+	case WebhookFormatJSON:
+		return "OmniView JSON"
+	case WebhookFormatSlack:
+		return "Slack"
+	case WebhookFormatTeams:
+		return "Teams (Adaptive Card)"
+	case WebhookFormatMessageCard:
+		return "Teams (MessageCard)"
+	case WebhookFormatDiscord:
+		return "Discord"
+	case WebhookFormatTemplate:
+		return "Custom template"
+	default:
+		return string(f)
+	}
+}
+
+// ==========================================
+// Webhook Batching
+// ==========================================
+
+// Batching limits
+const (
+	MinWebhookBatchWindow    = time.Second
+	MaxWebhookBatchWindow    = time.Hour
+	MaxWebhookBatchMessages  = 10000
+	WebhookDigestTopPayloads = 5    // Distinct payloads listed in a digest
+	webhookDigestMaxDistinct = 1000 // Distinct payloads counted per digest; the rest only add to Count
+)
+
+// WebhookBatching holds a webhook's messages back and sends them as one
+// digest, so a burst of errors becomes one request instead of hundreds.
+type WebhookBatching struct {
+	Window            time.Duration // Longest the first message of a batch waits; zero disables batching
+	MaxMessages       int           // Sends the batch early once it holds this many; zero for no limit
+	CriticalImmediate bool          // CRITICAL messages are sent at once instead of batched
+}
+
+// NewWebhookBatching creates a WebhookBatching with validation. A zero
+// window disables batching and ignores the other settings.
+func NewWebhookBatching(window time.Duration, maxMessages int, criticalImmediate bool) (WebhookBatching, error) {
+	if window == 0 {
+		return WebhookBatching{}, nil
+	}
+	if window < MinWebhookBatchWindow || window > MaxWebhookBatchWindow {
+		return WebhookBatching{}, fmt.Errorf("%w: window must be between %s and %s", ErrInvalidWebhookBatching, MinWebhookBatchWindow, MaxWebhookBatchWindow)
+	}
+	if maxMessages != 0 && (maxMessages < 2 || maxMessages > MaxWebhookBatchMessages) {
+		return WebhookBatching{}, fmt.Errorf("%w: batch size must be between 2 and %d", ErrInvalidWebhookBatching, MaxWebhookBatchMessages)
+	}
+	return WebhookBatching{Window: window, MaxMessages: maxMessages, CriticalImmediate: criticalImmediate}, nil
+}
+
+// Enabled reports whether messages are batched at all.
+func (b WebhookBatching) Enabled() bool { return b.Window > 0 }
+
+// Batches reports whether a message at level is held for the next digest.
+func (b WebhookBatching) Batches(level LogLevel) bool {
+	return b.Enabled() && !(b.CriticalImmediate && level == LogLevelCritical)
+}
+
+// ==========================================
+// Webhook Digest
+// ==========================================
+
+// WebhookDigest summarises the messages of one batch: how many there were
+// at each level, when the first and last were sent and which payloads
+// repeated most.
+type WebhookDigest struct {
+	Count     int                  `json:"count"`
+	Levels    map[LogLevel]int     `json:"levels"`
+	Processes []string             `json:"processes"` // Distinct process names, in the order first seen
+	First     time.Time            `json:"first"`
+	Last      time.Time            `json:"last"`
+	Top       []WebhookDigestEntry `json:"top"` // Most frequent payloads, most frequent first
+
+	index map[string]int // Position in Top by payload, while messages are added
+}
+
+// WebhookDigestEntry counts one distinct payload within a digest.
+type WebhookDigestEntry struct {
+	Payload string   `json:"payload"`
+	Process string   `json:"process"` // Process that sent it first
+	Level   LogLevel `json:"level"`   // Most severe level it was sent at
+	Count   int      `json:"count"`
+}
+
+// Add counts msg in the digest.
+func (d *WebhookDigest) Add(msg *QueueMessage) {
+	if d.Levels == nil {
+		d.Levels = make(map[LogLevel]int)
+		d.index = make(map[string]int)
+	}
+	d.Count++
+	d.Levels[msg.LogLevel()]++
+	if !slices.Contains(d.Processes, msg.ProcessName()) {
+		d.Processes = append(d.Processes, msg.ProcessName())
+	}
+	if ts := msg.Timestamp(); d.First.IsZero() || ts.Before(d.First) {
+		d.First = ts
+	}
+	if ts := msg.Timestamp(); ts.After(d.Last) {
+		d.Last = ts
+	}
+
+	if i, ok := d.index[msg.Payload()]; ok {
+		entry := &d.Top[i]
+		entry.Count++
+		if msg.LogLevel().Severity() > entry.Level.Severity() {
+			entry.Level = msg.LogLevel()
+		}
+		return
+	}
+	if len(d.Top) < webhookDigestMaxDistinct {
+		d.index[msg.Payload()] = len(d.Top)
+		d.Top = append(d.Top, WebhookDigestEntry{Payload: msg.Payload(), Process: msg.ProcessName(), Level: msg.LogLevel(), Count: 1})
+	}
+}
+
+// Finish orders the payloads by frequency and keeps the top
+// WebhookDigestTopPayloads. No more messages can be added afterwards.
+func (d *WebhookDigest) Finish() {
+	slices.SortStableFunc(d.Top, func(a, b WebhookDigestEntry) int { return b.Count - a.Count })
+	d.Top = slices.Clip(d.Top[:min(len(d.Top), WebhookDigestTopPayloads)])
+	d.index = nil
+}
+
+// Others returns how many messages are not among the listed payloads.
+func (d *WebhookDigest) Others() int {
+	others := d.Count
+	for _, entry := range d.Top {
+		others -= entry.Count
+	}
+	return others
+}
+
+// Highest returns the most severe level in the digest.
+func (d *WebhookDigest) Highest() LogLevel {
+	var highest LogLevel
+	for level := range d.Levels {
+		if highest == "" || level.Severity() > highest.Severity() {
+			highest = level
+		}
+	}
+	return highest
+}
+
+// ==========================================
+// Webhook Limits
+// ==========================================
+
+// Limit bounds
+const (
+	MinWebhookSuppressWindow = time.Second
+	MaxWebhookSuppressWindow = 24 * time.Hour
+	MaxWebhookRatePerMinute  = 6000
+)
+
+// WebhookLimits keeps a webhook from flooding its endpoint. Repeats of a
+// message, by fingerprint, are counted instead of sent while its suppress
+// window is open, and requests to the endpoint are paced by a token bucket.
+type WebhookLimits struct {
+	SuppressWindow time.Duration // How long repeats of a sent message are held back; zero disables suppression
+	RatePerMinute  int           // Requests per minute to the webhook URL; zero for no limit
+	Burst          int           // Requests sent back to back before the rate applies
+}
+
+// NewWebhookLimits creates a WebhookLimits with validation. A zero burst
+// allows one minute's worth of requests at once; it is ignored without a
+// rate.
+func NewWebhookLimits(suppressWindow time.Duration, ratePerMinute, burst int) (WebhookLimits, error) {
+	if suppressWindow != 0 && (suppressWindow < MinWebhookSuppressWindow || suppressWindow > MaxWebhookSuppressWindow) {
+		return WebhookLimits{}, fmt.Errorf("%w: suppress window must be between %s and %s", ErrInvalidWebhookLimits, MinWebhookSuppressWindow, MaxWebhookSuppressWindow)
+	}
+	if ratePerMinute < 0 || ratePerMinute > MaxWebhookRatePerMinute {
+		return WebhookLimits{}, fmt.Errorf("%w: rate must be between 1 and %d requests per minute", ErrInvalidWebhookLimits, MaxWebhookRatePerMinute)
+	}
+	if ratePerMinute == 0 {
+		return WebhookLimits{SuppressWindow: suppressWindow}, nil
+	}
+	if burst == 0 {
+		burst = ratePerMinute
+	}
+	if burst < 1 || burst > MaxWebhookRatePerMinute {
+		return WebhookLimits{}, fmt.Errorf("%w: burst must be between 1 and %d requests", ErrInvalidWebhookLimits, MaxWebhookRatePerMinute)
+	}
+	return WebhookLimits{SuppressWindow: suppressWindow, RatePerMinute: ratePerMinute, Burst: burst}, nil
+}
+
+// Suppresses reports whether repeated messages are held back.
+func (l WebhookLimits) Suppresses() bool { return l.SuppressWindow > 0 }
+
+// RateLimited reports whether requests to the endpoint are paced.
+func (l WebhookLimits) RateLimited() bool { return l.RatePerMinute > 0 }
+
+// ==========================================
+// Webhook Authentication
+// ==========================================
+
+// Headers set on every signed webhook request. The signature is the hex
+// HMAC-SHA256 of "<timestamp>.<body>" keyed with the signing secret, so a
+// receiver can reject bodies that were altered or replayed later.
+const (
+	WebhookSignatureHeader = "X-OmniView-Signature"
+	WebhookTimestampHeader = "X-OmniView-Timestamp"
+)
+
+// WebhookAuth holds the credentials sent with a webhook's requests. Every
+// option is independent; the zero value sends unauthenticated requests.
+type WebhookAuth struct {
+	SigningSecret string            // Key for the HMAC-SHA256 signature header; unsigned when empty
+	BearerToken   string            // Sent as "Authorization: Bearer <token>"
+	Headers       map[string]string // Static headers, e.g. an API key
+	ClientCert    string            // PEM file with the mTLS client certificate
+	ClientKey     string            // PEM file with the client certificate's private key
+	CABundle      string            // PEM file with the CAs trusted for the server; system roots when empty
+}
+
+// NewWebhookAuth creates a WebhookAuth with validation. headers is in the
+// key=value,key=value form of HeadersString, with URL-encoded values.
+func NewWebhookAuth(signingSecret, bearerToken, headers, clientCert, clientKey, caBundle string) (WebhookAuth, error) {
+	parsed, err := parseKeyValues(headers, ErrInvalidWebhookAuth)
+	if err != nil {
+		return WebhookAuth{}, err
+	}
+	auth := WebhookAuth{
+		SigningSecret: strings.TrimSpace(signingSecret),
+		BearerToken:   strings.TrimSpace(bearerToken),
+		Headers:       parsed,
+		ClientCert:    strings.TrimSpace(clientCert),
+		ClientKey:     strings.TrimSpace(clientKey),
+		CABundle:      strings.TrimSpace(caBundle),
+	}
+	if err := auth.Validate(); err != nil {
+		return WebhookAuth{}, err
+	}
+	return auth, nil
+}
+
+// Validate checks that the headers can be sent and do not replace the ones
+// OmniView sets itself, and that mTLS has both a certificate and a key.
+func (a WebhookAuth) Validate() error {
+	for name := range a.Headers {
+		if name == "" || strings.ContainsAny(name, " \t\r\n:") {
+			return fmt.Errorf("%w: invalid header name %q", ErrInvalidWebhookAuth, name)
+		}
+		switch textproto.CanonicalMIMEHeaderKey(name) {
+		case "Content-Type", "Content-Length", "Host",
+			textproto.CanonicalMIMEHeaderKey(WebhookSignatureHeader), textproto.CanonicalMIMEHeaderKey(WebhookTimestampHeader):
+			return fmt.Errorf("%w: header %s is set by OmniView", ErrInvalidWebhookAuth, name)
+		case "Authorization":
+			if a.BearerToken != "" {
+				return fmt.Errorf("%w: Authorization header conflicts with the bearer token", ErrInvalidWebhookAuth)
+			}
+		}
+	}
+	if (a.ClientCert == "") != (a.ClientKey == "") {
+		return fmt.Errorf("%w: mTLS needs both a client certificate and a key", ErrInvalidWebhookAuth)
+	}
+	return nil
+}
+
+// IsZero reports whether no option is set.
+func (a WebhookAuth) IsZero() bool {
+	return a.SigningSecret == "" && a.BearerToken == "" && len(a.Headers) == 0 &&
+		a.ClientCert == "" && a.ClientKey == "" && a.CABundle == ""
+}
+
+// UsesTLSFiles reports whether requests need a client certificate or a
+// custom CA bundle.
+func (a WebhookAuth) UsesTLSFiles() bool {
+	return a.ClientCert != "" || a.CABundle != ""
+}
+
+// HeadersString renders the static headers in the form read by
+// NewWebhookAuth, sorted by name.
+func (a WebhookAuth) HeadersString() string {
+	return formatKeyValues(a.Headers)
+}
+
+// ==========================================
+// Webhook Dead Letter Entity
+// ==========================================
+
+// DeadLetterReason records why a webhook delivery was saved instead of sent.
+type DeadLetterReason string
+
+const (
+	DeadLetterRetriesExhausted DeadLetterReason = "retries_exhausted" // Every attempt failed with a retryable error
+	DeadLetterRejected         DeadLetterReason = "rejected"          // The endpoint or URL check refused it; retrying would not help
+	DeadLetterQueueFull        DeadLetterReason = "queue_full"        // The dispatcher queue had no room
+	DeadLetterInterrupted      DeadLetterReason = "interrupted"       // Still pending at shutdown; queued again on the next start
+	DeadLetterRateLimited      DeadLetterReason = "rate_limited"      // Its endpoint's rate limit was already a full burst behind
+)
+
+// WebhookDelivery is a webhook message kept in the dead-letter store so it
+// survives restarts and can be replayed.
+type WebhookDelivery struct {
+	ID        string           `json:"id"` // Assigned by the store; sorts in save order
+	WebhookID string           `json:"webhook_id,omitempty"`
+	URL       string           `json:"url"`
+	Payload   string           `json:"payload"` // Message JSON, or WebhookDigest JSON when Digest is set; laid out in the webhook's format when sent
+	Digest    bool             `json:"digest,omitempty"`
+	LogLevel  string           `json:"log_level,omitempty"`
+	Timestamp string           `json:"timestamp,omitempty"`
+	Attempts  int              `json:"attempts"`
+	LastError string           `json:"last_error,omitempty"`
+	Reason    DeadLetterReason `json:"reason"`
+	SavedAt   time.Time        `json:"saved_at"`
+}
+
 // ==========================================
 // Constants
 // ==========================================
 
 const (
-	// DefaultWebhookID is the single webhook ID used
+	// DefaultWebhookID is the webhook used when no default has been chosen
 	DefaultWebhookID = "default"
 )
+
+// ==========================================
+// JSON Marshaling
+// ==========================================
+
+type webhookConfigJSON struct {
+	ID        string              `json:"id"`
+	URL       string              `json:"url"`
+	Enabled   bool                `json:"enabled"`
+	MinLevel  LogLevel            `json:"min_level,omitempty"`
+	Format    WebhookFormat       `json:"format,omitempty"`
+	Template  string              `json:"template,omitempty"`
+	Batching  webhookBatchingJSON `json:"batching,omitzero"`
+	Limits    webhookLimitsJSON   `json:"limits,omitzero"`
+	Auth      webhookAuthJSON     `json:"auth,omitzero"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+type webhookBatchingJSON struct {
+	Window            string `json:"window,omitempty"`
+	MaxMessages       int    `json:"max_messages,omitempty"`
+	CriticalImmediate bool   `json:"critical_immediate,omitempty"`
+}
+
+type webhookLimitsJSON struct {
+	SuppressWindow string `json:"suppress_window,omitempty"`
+	RatePerMinute  int    `json:"rate_per_minute,omitempty"`
+	Burst          int    `json:"burst,omitempty"`
+}
+
+type webhookAuthJSON struct {
+	SigningSecret string `json:"signing_secret,omitempty"`
+	BearerToken   string `json:"bearer_token,omitempty"`
+	Headers       string `json:"headers,omitempty"`
+	ClientCert    string `json:"client_cert,omitempty"`
+	ClientKey     string `json:"client_key,omitempty"`
+	CABundle      string `json:"ca_bundle,omitempty"`
+}
+
+// MarshalJSON implements custom JSON marshaling for WebhookConfig. The
+// signing secret, bearer token and headers are encrypted at rest via the
+// configured CredentialCipher; the mTLS settings are file paths and are
+// stored as they are.
+func (w *WebhookConfig) MarshalJSON() ([]byte, error) {
+	auth := webhookAuthJSON{
+		ClientCert: w.Auth.ClientCert,
+		ClientKey:  w.Auth.ClientKey,
+		CABundle:   w.Auth.CABundle,
+	}
+	for _, secret := range []struct {
+		plain string
+		out   *string
+	}{
+		{w.Auth.SigningSecret, &auth.SigningSecret},
+		{w.Auth.BearerToken, &auth.BearerToken},
+		{w.Auth.HeadersString(), &auth.Headers},
+	} {
+		if secret.plain == "" {
+			continue
+		}
+		encrypted, err := credentialCipher.Encrypt(secret.plain)
+		if err != nil {
+			return nil, fmt.Errorf("encrypt webhook credentials: %w", err)
+		}
+		*secret.out = encrypted
+	}
+	var batching webhookBatchingJSON
+	if w.Batching.Enabled() {
+		batching = webhookBatchingJSON{
+			Window:            w.Batching.Window.String(),
+			MaxMessages:       w.Batching.MaxMessages,
+			CriticalImmediate: w.Batching.CriticalImmediate,
+		}
+	}
+	limits := webhookLimitsJSON{RatePerMinute: w.Limits.RatePerMinute, Burst: w.Limits.Burst}
+	if w.Limits.Suppresses() {
+		limits.SuppressWindow = w.Limits.SuppressWindow.String()
+	}
+	return json.Marshal(webhookConfigJSON{
+		ID:        w.ID,
+		URL:       w.URL,
+		Enabled:   w.Enabled,
+		MinLevel:  w.MinLevel,
+		Format:    w.Format,
+		Template:  w.Template,
+		Batching:  batching,
+		Limits:    limits,
+		Auth:      auth,
+		CreatedAt: w.CreatedAt,
+		UpdatedAt: w.UpdatedAt,
+	})
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling for WebhookConfig
+func (w *WebhookConfig) UnmarshalJSON(data []byte) error {
+	var j webhookConfigJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	auth := WebhookAuth{
+		ClientCert: j.Auth.ClientCert,
+		ClientKey:  j.Auth.ClientKey,
+		CABundle:   j.Auth.CABundle,
+	}
+	var headers string
+	for _, secret := range []struct {
+		stored string
+		out    *string
+	}{
+		{j.Auth.SigningSecret, &auth.SigningSecret},
+		{j.Auth.BearerToken, &auth.BearerToken},
+		{j.Auth.Headers, &headers},
+	} {
+		if secret.stored == "" {
+			continue
+		}
+		decrypted, err := credentialCipher.Decrypt(secret.stored)
+		if err != nil {
+			return fmt.Errorf("decrypt webhook credentials: %w", err)
+		}
+		*secret.out = decrypted
+	}
+	parsedHeaders, err := parseKeyValues(headers, ErrInvalidWebhookAuth)
+	if err != nil {
+		return err
+	}
+	auth.Headers = parsedHeaders
+
+	var batching WebhookBatching
+	if j.Batching.Window != "" {
+		window, err := time.ParseDuration(j.Batching.Window)
+		if err != nil {
+			return fmt.Errorf("%w: window %s", ErrInvalidWebhookBatching, strconv.Quote(j.Batching.Window))
+		}
+		batching = WebhookBatching{Window: window, MaxMessages: j.Batching.MaxMessages, CriticalImmediate: j.Batching.CriticalImmediate}
+	}
+
+	limits := WebhookLimits{RatePerMinute: j.Limits.RatePerMinute, Burst: j.Limits.Burst}
+	if j.Limits.SuppressWindow != "" {
+		window, err := time.ParseDuration(j.Limits.SuppressWindow)
+		if err != nil {
+			return fmt.Errorf("%w: suppress window %s", ErrInvalidWebhookLimits, strconv.Quote(j.Limits.SuppressWindow))
+		}
+		limits.SuppressWindow = window
+	}
+
+	*w = WebhookConfig{
+		ID:        j.ID,
+		URL:       j.URL,
+		Enabled:   j.Enabled,
+		MinLevel:  j.MinLevel,
+		Format:    j.Format,
+		Template:  j.Template,
+		Batching:  batching,
+		Limits:    limits,
+		Auth:      auth,
+		CreatedAt: j.CreatedAt,
+		UpdatedAt: j.UpdatedAt,
+	}
+	return nil
+}
@@ -0,0 +1,299 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewWebhookConfig_ValidatesIDAndURL(t *testing.T) {
+	t.Parallel()
+
+	config, err := NewWebhookConfig(" ops ", "https://example.com/hook", true)
+	if err != nil {
+		t.Fatalf("NewWebhookConfig: %v", err)
+	}
+	if config.ID != "ops" || config.MinLevel != "" {
+		t.Fatalf("config = %+v", config)
+	}
+
+	for _, tc := range []struct{ id, url string }{
+		{"", "https://example.com/hook"},
+		{"webhook:default", "https://example.com/hook"},
+		{"ops", "ftp://example.com/hook"},
+		{"ops", "https://"},
+	} {
+		if _, err := NewWebhookConfig(tc.id, tc.url, true); err == nil {
+			t.Errorf("NewWebhookConfig(%q, %q) succeeded, want an error", tc.id, tc.url)
+		}
+	}
+}
+
+func TestWebhookConfig_AcceptsFiltersByLevel(t *testing.T) {
+	t.Parallel()
+
+	config, err := NewWebhookConfig("ops", "https://example.com/hook", true)
+	if err != nil {
+		t.Fatalf("NewWebhookConfig: %v", err)
+	}
+	if !config.Accepts(LogLevelDebug) {
+		t.Fatal("expected a webhook without a level filter to accept DEBUG")
+	}
+
+	if err := config.SetMinLevel("warning"); err != nil {
+		t.Fatalf("SetMinLevel: %v", err)
+	}
+	if config.MinLevel != LogLevelWarning || config.Accepts(LogLevelInfo) || !config.Accepts(LogLevelCritical) {
+		t.Fatalf("expected WARNING and above only, got MinLevel %q", config.MinLevel)
+	}
+	if err := config.SetMinLevel("LOUD"); err == nil {
+		t.Fatal("expected an unknown level to be rejected")
+	}
+
+	config.Enabled = false
+	if config.Accepts(LogLevelCritical) {
+		t.Fatal("expected a disabled webhook to accept nothing")
+	}
+}
+
+func TestWebhookConfig_MatchesTargetIgnoresCase(t *testing.T) {
+	t.Parallel()
+
+	config := &WebhookConfig{ID: "ops"}
+	if !config.MatchesTarget(" OPS ") || config.MatchesTarget("ops2") {
+		t.Fatal("expected targets to match the ID case-insensitively and exactly otherwise")
+	}
+	var missing *WebhookConfig
+	if missing.MatchesTarget("ops") {
+		t.Fatal("expected a nil webhook to match nothing")
+	}
+}
+
+func TestWebhookConfig_SetFormat(t *testing.T) {
+	t.Parallel()
+
+	config := &WebhookConfig{ID: "ops", Template: "stale"}
+	if err := config.SetFormat(WebhookFormatSlack, "{{.Level}}"); err != nil {
+		t.Fatalf("SetFormat: %v", err)
+	}
+	if config.Format != WebhookFormatSlack || config.Template != "" {
+		t.Fatalf("expected a built-in format to drop the template, got %+v", config)
+	}
+	if err := config.SetFormat(WebhookFormatTemplate, " {{.Level}} "); err != nil || config.Template != "{{.Level}}" {
+		t.Fatalf("SetFormat(template) = %v, Template %q", err, config.Template)
+	}
+	for _, tt := range []struct {
+		format   WebhookFormat
+		template string
+	}{
+		{WebhookFormatTemplate, "  "},
+		{"pager", ""},
+	} {
+		if err := config.SetFormat(tt.format, tt.template); !errors.Is(err, ErrInvalidWebhookFormat) {
+			t.Errorf("SetFormat(%q, %q) error = %v, want ErrInvalidWebhookFormat", tt.format, tt.template, err)
+		}
+	}
+	if config.Format != WebhookFormatTemplate || config.Template != "{{.Level}}" {
+		t.Fatalf("expected a rejected format to leave the config alone, got %+v", config)
+	}
+}
+
+func TestNewWebhookBatching(t *testing.T) {
+	t.Parallel()
+
+	batching, err := NewWebhookBatching(30*time.Second, 50, true)
+	if err != nil {
+		t.Fatalf("NewWebhookBatching: %v", err)
+	}
+	if !batching.Batches(LogLevelError) || batching.Batches(LogLevelCritical) {
+		t.Fatal("expected errors to be batched and CRITICAL to be sent at once")
+	}
+	if off, err := NewWebhookBatching(0, 50, true); err != nil || off.Enabled() || off.Batches(LogLevelInfo) {
+		t.Fatalf("expected a zero window to disable batching, got %+v, %v", off, err)
+	}
+
+	for _, tt := range []struct {
+		window time.Duration
+		max    int
+	}{
+		{500 * time.Millisecond, 0},
+		{2 * time.Hour, 0},
+		{time.Minute, 1},
+		{time.Minute, MaxWebhookBatchMessages + 1},
+	} {
+		if _, err := NewWebhookBatching(tt.window, tt.max, false); !errors.Is(err, ErrInvalidWebhookBatching) {
+			t.Errorf("NewWebhookBatching(%s, %d) error = %v, want ErrInvalidWebhookBatching", tt.window, tt.max, err)
+		}
+	}
+}
+
+func TestNewWebhookLimits(t *testing.T) {
+	t.Parallel()
+
+	limits, err := NewWebhookLimits(time.Minute, 30, 0)
+	if err != nil {
+		t.Fatalf("NewWebhookLimits: %v", err)
+	}
+	if !limits.Suppresses() || !limits.RateLimited() || limits.Burst != 30 {
+		t.Fatalf("expected suppression and a burst of one minute's rate, got %+v", limits)
+	}
+	if off, err := NewWebhookLimits(0, 0, 10); err != nil || off != (WebhookLimits{}) {
+		t.Fatalf("expected no limits without a window or rate, got %+v, %v", off, err)
+	}
+
+	for _, tt := range []struct {
+		window      time.Duration
+		rate, burst int
+	}{
+		{500 * time.Millisecond, 0, 0},
+		{25 * time.Hour, 0, 0},
+		{0, -1, 0},
+		{0, MaxWebhookRatePerMinute + 1, 0},
+		{0, 30, -1},
+		{0, 30, MaxWebhookRatePerMinute + 1},
+	} {
+		if _, err := NewWebhookLimits(tt.window, tt.rate, tt.burst); !errors.Is(err, ErrInvalidWebhookLimits) {
+			t.Errorf("NewWebhookLimits(%s, %d, %d) error = %v, want ErrInvalidWebhookLimits", tt.window, tt.rate, tt.burst, err)
+		}
+	}
+}
+
+func TestWebhookDigest_CountsAndRanksPayloads(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	var digest WebhookDigest
+	for i, m := range []struct {
+		process, payload string
+		level            LogLevel
+	}{
+		{"JOB_A", "ORA-00054", LogLevelWarning},
+		{"JOB_A", "ORA-00060", LogLevelError},
+		{"JOB_B", "ORA-00054", LogLevelCritical},
+		{"JOB_A", "ORA-00054", LogLevelWarning},
+	} {
+		msg, err := NewQueueMessage("m", m.process, m.level, m.payload, at.Add(time.Duration(i)*time.Second))
+		if err != nil {
+			t.Fatalf("NewQueueMessage: %v", err)
+		}
+		digest.Add(msg)
+	}
+	digest.Finish()
+
+	if digest.Count != 4 || digest.Highest() != LogLevelCritical || digest.Levels[LogLevelWarning] != 2 {
+		t.Fatalf("unexpected counts %+v", digest)
+	}
+	if len(digest.Processes) != 2 || digest.Processes[0] != "JOB_A" || !digest.First.Equal(at) || !digest.Last.Equal(at.Add(3*time.Second)) {
+		t.Fatalf("unexpected processes or time span %+v", digest)
+	}
+	top := digest.Top[0]
+	if top.Payload != "ORA-00054" || top.Count != 3 || top.Level != LogLevelCritical || top.Process != "JOB_A" || digest.Others() != 0 {
+		t.Fatalf("expected ORA-00054 first, at its most severe level, got %+v", digest.Top)
+	}
+}
+
+func TestNewWebhookAuth_Validates(t *testing.T) {
+	t.Parallel()
+
+	auth, err := NewWebhookAuth(" s3cret ", "", "X-Api-Key=abc", "/etc/omni/client.pem", "/etc/omni/client-key.pem", "")
+	if err != nil {
+		t.Fatalf("NewWebhookAuth: %v", err)
+	}
+	if auth.SigningSecret != "s3cret" || auth.Headers["X-Api-Key"] != "abc" || !auth.UsesTLSFiles() || auth.IsZero() {
+		t.Fatalf("unexpected auth %+v", auth)
+	}
+	if empty, err := NewWebhookAuth("", "", "", "", "", ""); err != nil || !empty.IsZero() {
+		t.Fatalf("expected empty settings to give the zero auth, got %+v, %v", empty, err)
+	}
+
+	for _, tt := range []struct {
+		name                                 string
+		bearer, headers, cert, key, caBundle string
+	}{
+		{name: "malformed headers", headers: "X-Api-Key"},
+		{name: "header set by OmniView", headers: "content-type=text/plain"},
+		{name: "signature header", headers: WebhookSignatureHeader + "=forged"},
+		{name: "authorization beside a bearer token", bearer: "tok", headers: "Authorization=Basic%20abc"},
+		{name: "certificate without key", cert: "/etc/omni/client.pem"},
+		{name: "key without certificate", key: "/etc/omni/client-key.pem"},
+	} {
+		if _, err := NewWebhookAuth("", tt.bearer, tt.headers, tt.cert, tt.key, tt.caBundle); !errors.Is(err, ErrInvalidWebhookAuth) {
+			t.Errorf("%s: error = %v, want ErrInvalidWebhookAuth", tt.name, err)
+		}
+	}
+}
+
+func TestWebhookConfig_JSONRoundTripEncryptsCredentials(t *testing.T) {
+	// Not parallel: swaps the package-level credential cipher.
+	SetCredentialCipher(prefixCipher{})
+	defer SetCredentialCipher(nil)
+
+	config, err := NewWebhookConfig("ops", "https://example.com/hook", true)
+	if err != nil {
+		t.Fatalf("NewWebhookConfig: %v", err)
+	}
+	config.Auth, err = NewWebhookAuth("s3cret", "tok", "X-Api-Key=abc", "/certs/client.pem", "/certs/client-key.pem", "/certs/ca.pem")
+	if err != nil {
+		t.Fatalf("NewWebhookAuth: %v", err)
+	}
+	if err := config.SetFormat(WebhookFormatTemplate, `{"text":{{json .Payload}}}`); err != nil {
+		t.Fatalf("SetFormat: %v", err)
+	}
+	if config.Batching, err = NewWebhookBatching(90*time.Second, 20, true); err != nil {
+		t.Fatalf("NewWebhookBatching: %v", err)
+	}
+	if config.Limits, err = NewWebhookLimits(5*time.Minute, 30, 5); err != nil {
+		t.Fatalf("NewWebhookLimits: %v", err)
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	for _, plain := range []string{`"s3cret"`, `"tok"`, `"X-Api-Key=abc"`} {
+		if strings.Contains(string(data), plain) {
+			t.Fatalf("expected %s to be encrypted, got %s", plain, data)
+		}
+	}
+
+	var decoded WebhookConfig
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if decoded.ID != "ops" || decoded.Auth.SigningSecret != "s3cret" || decoded.Auth.BearerToken != "tok" ||
+		decoded.Auth.Headers["X-Api-Key"] != "abc" || decoded.Auth.CABundle != "/certs/ca.pem" ||
+		decoded.Format != WebhookFormatTemplate || decoded.Template != config.Template || decoded.Batching != config.Batching ||
+		decoded.Limits != config.Limits {
+		t.Fatalf("decoded = %+v", decoded)
+	}
+}
+
+func TestWebhookConfig_UnmarshalsConfigWithoutAuth(t *testing.T) {
+	t.Parallel()
+
+	var config WebhookConfig
+	data := `{"id":"default","url":"https://example.com/hook","enabled":true,"created_at":"2025-01-02T03:04:05Z","updated_at":"2025-01-02T03:04:05Z"}`
+	if err := json.Unmarshal([]byte(data), &config); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if config.ID != "default" || !config.Enabled || !config.Auth.IsZero() || !config.CreatedAt.Equal(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Fatalf("unexpected config %+v", config)
+	}
+	out, err := json.Marshal(&config)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if containsAny(string(out), `"auth"`, `"format"`, `"batching"`) {
+		t.Fatalf("expected no auth, format or batching for a default webhook, got %s", out)
+	}
+}
+
+func containsAny(s string, substrs ...string) bool {
+	for _, substr := range substrs {
+		if strings.Contains(s, substr) {
+			return true
+		}
+	}
+	return false
+}
@@ -51,6 +51,10 @@ type DatabaseSettingsRepository interface {
 	// the call is equivalent to Save. Use this when renaming a database ID to avoid
 	// a window where neither key exists.
 	Replace(ctx context.Context, id string, newRecord domain.DatabaseSettings) error
+
+	// SwitchDefault stores newDefault as the default database and, when given,
+	// the previous default with its flag cleared, in a single transaction.
+	SwitchDefault(ctx context.Context, previousDefault *domain.DatabaseSettings, newDefault domain.DatabaseSettings) error
 }
 
 // ==========================================
@@ -68,6 +72,36 @@ type PermissionsRepository interface {
 	Exists(ctx context.Context, schema string) (bool, error)
 }
 
+// ==========================================
+// Trace History Repository Interface
+// ==========================================
+
+type TraceHistoryRepository interface {
+	// BeginSession creates and stores a new history session for a database
+	BeginSession(ctx context.Context, databaseID string, subscriberName string) (*domain.TraceSession, error)
+
+	// Append stores messages under the session and updates its counters in place
+	Append(ctx context.Context, session *domain.TraceSession, messages []*domain.QueueMessage) error
+
+	// ListSessions returns the stored sessions for a database, newest first
+	ListSessions(ctx context.Context, databaseID string) ([]domain.TraceSession, error)
+
+	// ReadSession returns up to limit messages of a session, oldest first, that follow
+	// the position after; an empty after starts at the first message. It also returns
+	// the position of the last message read, which is after when none was read, to
+	// pass for the next page. Positions are opaque.
+	ReadSession(ctx context.Context, session domain.TraceSession, after string, limit int) ([]*domain.QueueMessage, string, error)
+
+	// Retention returns the active retention limits
+	Retention() domain.HistoryRetention
+
+	// SetRetention replaces the active retention limits
+	SetRetention(retention domain.HistoryRetention)
+
+	// Prune removes history that falls outside the active retention limits
+	Prune(ctx context.Context) error
+}
+
 // ==========================================
 // Database Repository Interface (Oracle)
 // ==========================================
@@ -163,9 +197,15 @@ type ConfigRepository interface {
 	// SaveWebhookConfig saves a webhook configuration
 	SaveWebhookConfig(config *domain.WebhookConfig) error
 
-	// GetWebhookConfig retrieves the webhook configuration (uses default ID)
+	// GetWebhookConfig retrieves the default webhook configuration
 	GetWebhookConfig() (*domain.WebhookConfig, error)
 
+	// ListWebhookConfigs retrieves every named webhook configuration
+	ListWebhookConfigs() ([]domain.WebhookConfig, error)
+
+	// SetDefaultWebhook chooses the webhook used for messages without a target
+	SetDefaultWebhook(id string) error
+
 	// DeleteWebhookConfig deletes a webhook configuration
 	DeleteWebhookConfig(id string) error
 
@@ -183,3 +223,69 @@ type ConfigRepository interface {
 	// SetBroadcastMode stores the broadcast mode.
 	SetBroadcastMode(mode domain.BroadcastMode) error
 }
+
+// ==========================================
+// Message Publisher Interface
+// ==========================================
+
+// MessagePublisher receives every delivered trace message alongside the UI
+// event channel. Publish is called on the dequeue path and must not block.
+type MessagePublisher interface {
+	Publish(msg *domain.QueueMessage)
+}
+
+// ==========================================
+// Webhook Dead Letter Interface
+// ==========================================
+
+// WebhookDeadLetterRepository keeps webhook deliveries that could not be sent.
+type WebhookDeadLetterRepository interface {
+	// SaveDeadLetter stores a delivery, assigning its ID
+	SaveDeadLetter(ctx context.Context, delivery *domain.WebhookDelivery) error
+
+	// ListDeadLetters returns every stored delivery, oldest first
+	ListDeadLetters(ctx context.Context) ([]domain.WebhookDelivery, error)
+
+	// DeleteDeadLetter removes a delivery; returns domain.ErrDeadLetterNotFound when it does not exist
+	DeleteDeadLetter(ctx context.Context, id string) error
+}
+
+// ==========================================
+// Output Sink Interfaces
+// ==========================================
+
+// MessageSink is an output that routed trace messages are written to. Each
+// sink is fed from its own queue by a single goroutine, so Write may block;
+// it should return once ctx is done.
+type MessageSink interface {
+	Write(ctx context.Context, msg *domain.QueueMessage) error
+	Close() error
+}
+
+// SinkFactory opens the sink described by cfg. It validates the settings but
+// must not contact the destination: the settings screen opens sinks just to
+// check them, so connections and files are opened on the first Write.
+type SinkFactory func(cfg domain.SinkConfig) (MessageSink, error)
+
+// SinkRepository stores the output sinks and the routing rules that select
+// the messages each receives. The sink router reloads from it whenever the
+// settings screen saves a change.
+type SinkRepository interface {
+	// ListSinks returns every stored sink, ordered by ID
+	ListSinks(ctx context.Context) ([]domain.SinkConfig, error)
+
+	// SaveSink stores a sink, replacing one with the same ID
+	SaveSink(ctx context.Context, sink domain.SinkConfig) error
+
+	// DeleteSink removes a sink; returns domain.ErrSinkNotFound when it does not exist
+	DeleteSink(ctx context.Context, id string) error
+
+	// ListRoutingRules returns every stored routing rule, ordered by ID
+	ListRoutingRules(ctx context.Context) ([]domain.RoutingRule, error)
+
+	// SaveRoutingRule stores a rule, replacing one with the same ID
+	SaveRoutingRule(ctx context.Context, rule domain.RoutingRule) error
+
+	// DeleteRoutingRule removes a rule; returns domain.ErrRoutingRuleNotFound when it does not exist
+	DeleteRoutingRule(ctx context.Context, id string) error
+}
@@ -2,6 +2,7 @@ package permissions
 
 import (
 	"OmniView/assets"
+	"OmniView/internal/adapter/logger"
 	"OmniView/internal/core/domain"
 	"OmniView/internal/core/ports"
 	"context"
@@ -69,6 +70,29 @@ func (ps *PermissionService) DeployAndCheck(ctx context.Context, schema string)
 	return true, nil
 }
 
+// Verify deploys the permission checks package, checks the grants of schema and
+// drops the package again. Unlike DeployAndCheck it neither reads nor updates
+// the cached result, so it always reflects the current grants.
+func (ps *PermissionService) Verify(ctx context.Context, schema string) (*domain.DatabasePermissions, error) {
+	if err := deployPermissionChecksPackage(ctx, ps); err != nil {
+		if dropErr := dropPermissionChecksPackage(ctx, ps); dropErr != nil {
+			return nil, fmt.Errorf("failed to deploy permission checks package: %w; cleanup failed: %v", err, dropErr)
+		}
+		return nil, err
+	}
+	perStatus, err := checkPermissions(ctx, ps, schema)
+	if dropErr := dropPermissionChecksPackage(ctx, ps); dropErr != nil {
+		if err != nil {
+			return nil, fmt.Errorf("failed to check permissions: %w; cleanup failed: %v", err, dropErr)
+		}
+		return nil, dropErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return perStatus, nil
+}
+
 // DeployPermissionChecksPackage deploys the permission checks package to the database if not already present
 func deployPermissionChecksPackage(ctx context.Context, ps *PermissionService) error {
 	// Check if the permission checks package is already deployed
@@ -156,7 +180,7 @@ func checkPermissions(ctx context.Context, ps *PermissionService, schema string)
 	if !perStatus.IsValid() {
 		return nil, fmt.Errorf("permission checks failed for schema %s: %+v", schema, permStructTable)
 	} else {
-		fmt.Printf("All permission checks passed for schema %s %s\n", schema, permStructTable)
+		logger.Info("all permission checks passed", "schema", schema, "details", permStructTable)
 	}
 
 	return perStatus, nil
@@ -0,0 +1,78 @@
+package sinks
+
+import (
+	"OmniView/internal/core/domain"
+	"OmniView/internal/core/ports"
+	"cmp"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// ==========================================
+// Sink Plug-in Registry
+// ==========================================
+
+// Plugin describes one type of output sink.
+type Plugin struct {
+	Type    string            // Name stored in domain.SinkConfig.Type, e.g. "file"
+	Example string            // Example settings shown in the settings screen
+	Open    ports.SinkFactory // Opens a sink of this type
+}
+
+// Registry holds the sink types that configured sinks may use. Adapters add
+// their plug-ins at start-up, so a new output needs no change to the tracer
+// or the router.
+type Registry struct {
+	mu      sync.RWMutex
+	plugins map[string]Plugin
+}
+
+// NewRegistry creates an empty Registry
+func NewRegistry() *Registry {
+	return &Registry{plugins: make(map[string]Plugin)}
+}
+
+// Register adds a plug-in, replacing any registered under the same type.
+func (r *Registry) Register(plugin Plugin) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.plugins[plugin.Type] = plugin
+}
+
+// Plugins returns the registered plug-ins ordered by type.
+func (r *Registry) Plugins() []Plugin {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	plugins := make([]Plugin, 0, len(r.plugins))
+	for _, plugin := range r.plugins {
+		plugins = append(plugins, plugin)
+	}
+	slices.SortFunc(plugins, func(a, b Plugin) int { return cmp.Compare(a.Type, b.Type) })
+	return plugins
+}
+
+// Open opens the sink described by cfg with the plug-in for its type.
+func (r *Registry) Open(cfg domain.SinkConfig) (ports.MessageSink, error) {
+	r.mu.RLock()
+	plugin, ok := r.plugins[cfg.Type]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", domain.ErrUnknownSinkType, cfg.Type)
+	}
+	sink, err := plugin.Open(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("open %s sink %s: %w", cfg.Type, cfg.ID, err)
+	}
+	return sink, nil
+}
+
+// Validate checks cfg by opening and closing its sink. Factories do not
+// contact the destination, so this has no side effects.
+func (r *Registry) Validate(cfg domain.SinkConfig) error {
+	sink, err := r.Open(cfg)
+	if err != nil {
+		return err
+	}
+	return sink.Close()
+}
@@ -0,0 +1,375 @@
+package sinks
+
+// ==========================================
+// Sink Router
+// ==========================================
+// Routes delivered trace messages to the configured output sinks, webhooks
+// included. The Router is the ports.MessagePublisher set on the tracer
+// service beside the UI event channel, and the only fan-out on the dequeue
+// path. Each running sink has its own bounded queue and goroutine, so a
+// slow or failing sink drops its own messages without holding up the
+// dequeue loop or the other sinks.
+
+import (
+	"OmniView/internal/adapter/logger"
+	"OmniView/internal/adapter/metrics"
+	"OmniView/internal/core/domain"
+	"OmniView/internal/core/ports"
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// sinkQueueSize is how many messages a sink may fall behind before its
+	// messages are dropped.
+	sinkQueueSize = 1024
+
+	// stopTimeout bounds how long a stopped sink may spend writing the
+	// messages still queued for it.
+	stopTimeout = 5 * time.Second
+)
+
+var (
+	sinkWrites = metrics.NewCounterVec("omniview_sink_writes_total",
+		"Messages written to an output sink, by sink ID.", "sink")
+	sinkFailures = metrics.NewCounterVec("omniview_sink_failures_total",
+		"Messages an output sink failed to write, by sink ID.", "sink")
+	sinkDrops = metrics.NewCounterVec("omniview_sink_drops_total",
+		"Messages dropped because an output sink's queue was full, by sink ID.", "sink")
+)
+
+// SinkStatus reports the state of one configured sink.
+type SinkStatus struct {
+	Config  domain.SinkConfig
+	Running bool
+	Queued  int   // Messages waiting to be written
+	Err     error // Why the sink could not be opened, or its last write error
+}
+
+// Router routes messages to sinks according to the routing rules.
+type Router struct {
+	registry *Registry
+	repo     ports.SinkRepository
+
+	applyMu  sync.Mutex // Held by Apply while it opens sinks without mu
+	mu       sync.RWMutex
+	configs  []domain.SinkConfig
+	rules    []domain.RoutingRule // Enabled rules only
+	outputs  map[string]*output   // Running sinks by ID
+	openErrs map[string]error
+	taps     []ports.MessagePublisher
+	closed   bool
+}
+
+// NewRouter creates a Router that opens sinks through registry and loads its
+// configuration from repo. It routes nothing until Reload or Apply is called.
+func NewRouter(registry *Registry, repo ports.SinkRepository) (*Router, error) {
+	if registry == nil {
+		return nil, fmt.Errorf("NewRouter: registry cannot be nil")
+	}
+	if repo == nil {
+		return nil, fmt.Errorf("NewRouter: %w", domain.ErrNilRepository)
+	}
+	return &Router{
+		registry: registry,
+		repo:     repo,
+		outputs:  make(map[string]*output),
+		openErrs: make(map[string]error),
+	}, nil
+}
+
+// Registry returns the plug-ins sinks are opened with.
+func (r *Router) Registry() *Registry { return r.registry }
+
+// Repository returns the store the router loads its configuration from.
+func (r *Router) Repository() ports.SinkRepository { return r.repo }
+
+// Tap hands every published message to p, whatever the rules, before it is
+// routed. The stream hub uses it to see all messages. Like Publish, p must
+// not block.
+func (r *Router) Tap(p ports.MessagePublisher) {
+	if p == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.taps = append(r.taps, p)
+}
+
+// Reload reads the sinks and rules from the repository and applies them.
+func (r *Router) Reload(ctx context.Context) error {
+	sinks, err := r.repo.ListSinks(ctx)
+	if err != nil {
+		return fmt.Errorf("Reload: %w", err)
+	}
+	rules, err := r.repo.ListRoutingRules(ctx)
+	if err != nil {
+		return fmt.Errorf("Reload: %w", err)
+	}
+	r.Apply(sinks, rules)
+	return nil
+}
+
+// Apply replaces the routing configuration. Sinks whose configuration is
+// unchanged keep running; changed and removed sinks are stopped after
+// writing what is already queued for them. A changed sink's replacement
+// queues messages at once but writes them only once the old sink has
+// drained and closed, so two never write to the same destination. A sink
+// that fails to open is reported by Status and skipped.
+//
+// New sinks are opened before the router is locked, so a slow Open (an OTLP
+// dial, a file create) does not hold up Publish on the dequeue path.
+func (r *Router) Apply(sinks []domain.SinkConfig, rules []domain.RoutingRule) {
+	r.applyMu.Lock()
+	defer r.applyMu.Unlock()
+
+	wanted := make(map[string]domain.SinkConfig, len(sinks))
+	for _, cfg := range sinks {
+		if cfg.Enabled {
+			wanted[cfg.ID] = cfg
+		}
+	}
+
+	// Only Apply and Close change the outputs, and applyMu keeps Applies
+	// apart, so what is read here still holds once the write lock is taken
+	// unless the router was closed in between.
+	r.mu.RLock()
+	if r.closed {
+		r.mu.RUnlock()
+		return
+	}
+	var toOpen []domain.SinkConfig
+	for id, cfg := range wanted {
+		if out := r.outputs[id]; out == nil || !reflect.DeepEqual(cfg, out.cfg) {
+			toOpen = append(toOpen, cfg)
+		}
+	}
+	r.mu.RUnlock()
+
+	opened := make(map[string]ports.MessageSink, len(toOpen))
+	openErrs := make(map[string]error)
+	for _, cfg := range toOpen {
+		sink, err := r.registry.Open(cfg)
+		if err != nil {
+			logger.Warn("failed to open output sink", "sink", cfg.ID, "error", err)
+			openErrs[cfg.ID] = err
+			continue
+		}
+		opened[cfg.ID] = sink
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		for id, sink := range opened {
+			if err := sink.Close(); err != nil {
+				logger.Warn("failed to close output sink", "sink", id, "error", err)
+			}
+		}
+		return
+	}
+
+	stopped := make(map[string]*output)
+	for id, out := range r.outputs {
+		if cfg, ok := wanted[id]; ok && reflect.DeepEqual(cfg, out.cfg) {
+			continue
+		}
+		out.stop()
+		delete(r.outputs, id)
+		stopped[id] = out
+	}
+	for id, sink := range opened {
+		var prev <-chan struct{}
+		if old := stopped[id]; old != nil {
+			prev = old.done
+		}
+		r.outputs[id] = startOutput(wanted[id], sink, prev)
+	}
+
+	r.openErrs = openErrs
+	r.configs = slices.Clone(sinks)
+	r.rules = slices.DeleteFunc(slices.Clone(rules), func(rule domain.RoutingRule) bool { return !rule.Enabled })
+}
+
+// ApplyDefaultRoute routes only the messages sent with
+// Trace_Message_To_Webhook, to their webhooks, as a new store does. It stands
+// in for the stored configuration when Reload fails, so the PL/SQL webhook
+// API keeps working.
+func (r *Router) ApplyDefaultRoute() {
+	sink, rule := domain.DefaultWebhookRoute()
+	r.Apply([]domain.SinkConfig{sink}, []domain.RoutingRule{rule})
+}
+
+// Publish queues msg for every sink named by an enabled rule it matches,
+// once per sink. It never blocks.
+func (r *Router) Publish(msg *domain.QueueMessage) {
+	if msg == nil {
+		return
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, tap := range r.taps {
+		tap.Publish(msg)
+	}
+	var routed []string
+	for i := range r.rules {
+		rule := &r.rules[i]
+		if !rule.Matches(msg) {
+			continue
+		}
+		for _, id := range rule.Sinks {
+			if slices.Contains(routed, id) {
+				continue
+			}
+			routed = append(routed, id)
+			if out := r.outputs[id]; out != nil {
+				out.enqueue(msg)
+			}
+		}
+	}
+}
+
+// Status returns the state of every configured sink, in configuration order.
+func (r *Router) Status() []SinkStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	statuses := make([]SinkStatus, 0, len(r.configs))
+	for _, cfg := range r.configs {
+		status := SinkStatus{Config: cfg, Err: r.openErrs[cfg.ID]}
+		if out := r.outputs[cfg.ID]; out != nil {
+			status.Running = true
+			status.Queued = len(out.queue)
+			status.Err = out.lastError()
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// Close stops every sink, giving each a short time to write what is still
+// queued, and drops the taps. Messages published afterwards are ignored.
+func (r *Router) Close() {
+	r.mu.Lock()
+	outputs := r.outputs
+	r.outputs = make(map[string]*output)
+	r.rules = nil
+	r.taps = nil
+	r.closed = true
+	for _, out := range outputs {
+		out.stop()
+	}
+	r.mu.Unlock()
+
+	deadline := time.After(stopTimeout + time.Second)
+	for id, out := range outputs {
+		select {
+		case <-out.done:
+		case <-deadline:
+			logger.Warn("output sink did not stop in time", "sink", id)
+			return
+		}
+	}
+}
+
+// ==========================================
+// Output
+// ==========================================
+
+// output is one running sink with its queue.
+type output struct {
+	cfg    domain.SinkConfig
+	sink   ports.MessageSink
+	queue  chan *domain.QueueMessage
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+	prev   <-chan struct{} // Closed once the sink this one replaced has stopped
+
+	dropping atomic.Bool // Set while messages are being dropped, so the warning is logged once per streak
+	failing  atomic.Bool // Set while writes fail, for the same reason
+
+	mu      sync.Mutex
+	lastErr error
+}
+
+// startOutput starts writing to sink. When prev is set, writes wait until
+// it is closed.
+func startOutput(cfg domain.SinkConfig, sink ports.MessageSink, prev <-chan struct{}) *output {
+	ctx, cancel := context.WithCancel(context.Background())
+	o := &output{
+		cfg:    cfg,
+		sink:   sink,
+		queue:  make(chan *domain.QueueMessage, sinkQueueSize),
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+		prev:   prev,
+	}
+	go o.run()
+	return o
+}
+
+// enqueue is called with the router's read lock held, so stop, which needs
+// the write lock, cannot close the queue underneath it.
+func (o *output) enqueue(msg *domain.QueueMessage) {
+	select {
+	case o.queue <- msg:
+		if o.dropping.Load() {
+			o.dropping.Store(false)
+		}
+	default:
+		sinkDrops.With(o.cfg.ID).Inc()
+		if !o.dropping.Swap(true) {
+			logger.Warn("output sink fell behind, dropping messages", "sink", o.cfg.ID, "capacity", sinkQueueSize)
+		}
+	}
+}
+
+// stop closes the queue and cancels writes still running after stopTimeout.
+// It is called with the router's write lock held.
+func (o *output) stop() {
+	close(o.queue)
+	time.AfterFunc(stopTimeout, o.cancel)
+}
+
+func (o *output) run() {
+	defer close(o.done)
+	defer o.cancel()
+
+	if o.prev != nil {
+		<-o.prev
+	}
+	for msg := range o.queue {
+		err := o.sink.Write(o.ctx, msg)
+		o.mu.Lock()
+		o.lastErr = err
+		o.mu.Unlock()
+		if err != nil {
+			sinkFailures.With(o.cfg.ID).Inc()
+			if !o.failing.Swap(true) {
+				logger.Warn("output sink write failed", "sink", o.cfg.ID, "type", o.cfg.Type, "error", err)
+			}
+			continue
+		}
+		sinkWrites.With(o.cfg.ID).Inc()
+		if o.failing.Swap(false) {
+			logger.Info("output sink recovered", "sink", o.cfg.ID)
+		}
+	}
+	if err := o.sink.Close(); err != nil {
+		logger.Warn("failed to close output sink", "sink", o.cfg.ID, "error", err)
+	}
+}
+
+func (o *output) lastError() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.lastErr
+}
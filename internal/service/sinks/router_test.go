@@ -0,0 +1,383 @@
+package sinks
+
+import (
+	"OmniView/internal/core/domain"
+	"OmniView/internal/core/ports"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memorySink records the payloads written to it. A non-nil block channel
+// holds every write until it is closed.
+type memorySink struct {
+	mu       sync.Mutex
+	payloads []string
+	closed   bool
+	block    chan struct{}
+}
+
+func (s *memorySink) Write(ctx context.Context, msg *domain.QueueMessage) error {
+	if s.block != nil {
+		select {
+		case <-s.block:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.payloads = append(s.payloads, msg.Payload())
+	return nil
+}
+
+func (s *memorySink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+func (s *memorySink) snapshot() ([]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.payloads...), s.closed
+}
+
+// memoryRegistry keeps the latest memorySink opened for each sink ID and
+// counts how often each ID was opened.
+type memoryRegistry struct {
+	mu    sync.Mutex
+	sinks map[string]*memorySink
+	opens map[string]int
+}
+
+func newMemoryRegistry(t *testing.T) (*Registry, *memoryRegistry) {
+	t.Helper()
+	mem := &memoryRegistry{sinks: make(map[string]*memorySink), opens: make(map[string]int)}
+	registry := NewRegistry()
+	registry.Register(Plugin{Type: "memory", Open: func(cfg domain.SinkConfig) (ports.MessageSink, error) {
+		mem.mu.Lock()
+		defer mem.mu.Unlock()
+		mem.opens[cfg.ID]++
+		sink := &memorySink{}
+		if cfg.Setting("block") != "" {
+			sink.block = make(chan struct{})
+		}
+		mem.sinks[cfg.ID] = sink
+		return sink, nil
+	}})
+	return registry, mem
+}
+
+func (m *memoryRegistry) sink(id string) *memorySink {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.sinks[id]
+}
+
+func (m *memoryRegistry) openCount(id string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.opens[id]
+}
+
+func newTestRouter(t *testing.T) (*Router, *memoryRegistry) {
+	t.Helper()
+	registry, mem := newMemoryRegistry(t)
+	router, err := NewRouter(registry, stubSinkRepository{})
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+	return router, mem
+}
+
+// stubSinkRepository satisfies ports.SinkRepository for tests that call Apply directly.
+type stubSinkRepository struct{ ports.SinkRepository }
+
+func testSink(t *testing.T, id string, enabled bool, settings map[string]string) domain.SinkConfig {
+	t.Helper()
+	cfg, err := domain.NewSinkConfig(id, "memory", settings, enabled)
+	if err != nil {
+		t.Fatalf("NewSinkConfig: %v", err)
+	}
+	return *cfg
+}
+
+func testRule(t *testing.T, id string, sinks []string, level domain.LogLevel, payload string, enabled bool) domain.RoutingRule {
+	t.Helper()
+	rule, err := domain.NewRoutingRule(id, sinks, level, "", domain.BroadcastModeGlobal, payload, enabled)
+	if err != nil {
+		t.Fatalf("NewRoutingRule: %v", err)
+	}
+	return *rule
+}
+
+func testMessage(t *testing.T, level domain.LogLevel, payload string) *domain.QueueMessage {
+	t.Helper()
+	msg, err := domain.NewQueueMessage("1", "PROC", level, payload, time.Now())
+	if err != nil {
+		t.Fatalf("NewQueueMessage: %v", err)
+	}
+	return msg
+}
+
+func TestRouter_RoutesEachMessageOncePerSink(t *testing.T) {
+	t.Parallel()
+
+	router, mem := newTestRouter(t)
+	router.Apply(
+		[]domain.SinkConfig{testSink(t, "a", true, nil), testSink(t, "b", true, nil), testSink(t, "off", false, nil)},
+		[]domain.RoutingRule{
+			testRule(t, "all", []string{"a", "off", "missing"}, "", "", true),
+			testRule(t, "errors", []string{"a", "b"}, domain.LogLevelError, "", true),
+			testRule(t, "disabled", []string{"b"}, "", "", false),
+		},
+	)
+	if mem.sink("off") != nil {
+		t.Fatal("expected a disabled sink not to be opened")
+	}
+
+	router.Publish(testMessage(t, domain.LogLevelInfo, "info"))
+	router.Publish(testMessage(t, domain.LogLevelError, "error"))
+	router.Close()
+
+	a, closed := mem.sink("a").snapshot()
+	if len(a) != 2 || a[0] != "info" || a[1] != "error" || !closed {
+		t.Fatalf("sink a got %v (closed=%v), want [info error] once each and closed", a, closed)
+	}
+	if b, _ := mem.sink("b").snapshot(); len(b) != 1 || b[0] != "error" {
+		t.Fatalf("sink b got %v, want only the error", b)
+	}
+}
+
+func TestRouter_DropsWhenASinkFallsBehind(t *testing.T) {
+	t.Parallel()
+
+	router, mem := newTestRouter(t)
+	router.Apply(
+		[]domain.SinkConfig{testSink(t, "slow", true, map[string]string{"block": "1"})},
+		[]domain.RoutingRule{testRule(t, "all", []string{"slow"}, "", "", true)},
+	)
+
+	before := sinkDrops.With("slow").Value()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		// One message is held by the blocked write, the rest fill the queue.
+		for range sinkQueueSize + 5 {
+			router.Publish(testMessage(t, domain.LogLevelInfo, "x"))
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Publish blocked on a full sink queue")
+	}
+	if dropped := sinkDrops.With("slow").Value() - before; dropped < 4 {
+		t.Fatalf("dropped %d messages, want at least 4", dropped)
+	}
+
+	close(mem.sink("slow").block)
+	router.Close()
+}
+
+func TestRouter_ApplyRestartsOnlyChangedSinks(t *testing.T) {
+	t.Parallel()
+
+	router, mem := newTestRouter(t)
+	keep := testSink(t, "keep", true, nil)
+	change := testSink(t, "change", true, map[string]string{"v": "1"})
+	rules := []domain.RoutingRule{testRule(t, "all", []string{"keep", "change"}, "", "", true)}
+	router.Apply([]domain.SinkConfig{keep, change}, rules)
+
+	first := mem.sink("change")
+	change.Settings = map[string]string{"v": "2"}
+	router.Apply([]domain.SinkConfig{keep, change}, rules)
+	defer router.Close()
+
+	if mem.openCount("keep") != 1 {
+		t.Fatalf("unchanged sink opened %d times, want 1", mem.openCount("keep"))
+	}
+	if mem.openCount("change") != 2 {
+		t.Fatalf("changed sink opened %d times, want 2", mem.openCount("change"))
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, closed := first.snapshot(); closed {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the replaced sink to be closed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestRouter_ApplyDrainsTheOldSinkBeforeItsReplacementWrites(t *testing.T) {
+	t.Parallel()
+
+	router, mem := newTestRouter(t)
+	rules := []domain.RoutingRule{testRule(t, "all", []string{"out"}, "", "", true)}
+	router.Apply([]domain.SinkConfig{testSink(t, "out", true, map[string]string{"block": "1"})}, rules)
+	first := mem.sink("out")
+	router.Publish(testMessage(t, domain.LogLevelInfo, "old"))
+
+	router.Apply([]domain.SinkConfig{testSink(t, "out", true, nil)}, rules)
+	second := mem.sink("out")
+	router.Publish(testMessage(t, domain.LogLevelInfo, "new"))
+
+	time.Sleep(50 * time.Millisecond)
+	if got, _ := second.snapshot(); len(got) != 0 {
+		t.Fatalf("replacement wrote %v while the old sink was still draining", got)
+	}
+
+	close(first.block)
+	router.Close()
+	if got, closed := first.snapshot(); len(got) != 1 || got[0] != "old" || !closed {
+		t.Fatalf("old sink got %v (closed=%v), want [old] and closed", got, closed)
+	}
+	if got, _ := second.snapshot(); len(got) != 1 || got[0] != "new" {
+		t.Fatalf("replacement got %v, want [new]", got)
+	}
+}
+
+// recordingPublisher records published payloads.
+type recordingPublisher struct{ payloads []string }
+
+func (p *recordingPublisher) Publish(msg *domain.QueueMessage) {
+	p.payloads = append(p.payloads, msg.Payload())
+}
+
+func TestRouter_TapSeesEveryMessage(t *testing.T) {
+	t.Parallel()
+
+	router, _ := newTestRouter(t)
+	tap := &recordingPublisher{}
+	router.Tap(tap)
+	router.Tap(nil)
+	router.Apply(nil, nil)
+	defer router.Close()
+
+	router.Publish(testMessage(t, domain.LogLevelInfo, "unrouted"))
+	if len(tap.payloads) != 1 || tap.payloads[0] != "unrouted" {
+		t.Fatalf("tap got %v, want the unrouted message", tap.payloads)
+	}
+}
+
+func TestRouter_StatusReportsOpenErrors(t *testing.T) {
+	t.Parallel()
+
+	router, _ := newTestRouter(t)
+	unknown, err := domain.NewSinkConfig("elsewhere", "carrier-pigeon", nil, true)
+	if err != nil {
+		t.Fatalf("NewSinkConfig: %v", err)
+	}
+	router.Apply([]domain.SinkConfig{testSink(t, "a", true, nil), *unknown}, nil)
+	defer router.Close()
+
+	statuses := router.Status()
+	if len(statuses) != 2 {
+		t.Fatalf("Status() returned %d entries, want 2", len(statuses))
+	}
+	if !statuses[0].Running || statuses[0].Err != nil {
+		t.Fatalf("status[0] = %+v, want running without error", statuses[0])
+	}
+	if statuses[1].Running || !errors.Is(statuses[1].Err, domain.ErrUnknownSinkType) {
+		t.Fatalf("status[1] = %+v, want ErrUnknownSinkType", statuses[1])
+	}
+}
+
+func TestRouter_PublishIsNotHeldUpByASlowOpen(t *testing.T) {
+	t.Parallel()
+
+	registry, mem := newMemoryRegistry(t)
+	release := make(chan struct{})
+	registry.Register(Plugin{Type: "slow", Open: func(cfg domain.SinkConfig) (ports.MessageSink, error) {
+		<-release
+		return &memorySink{}, nil
+	}})
+	router, err := NewRouter(registry, stubSinkRepository{})
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+	defer router.Close()
+
+	a := testSink(t, "a", true, nil)
+	rules := []domain.RoutingRule{testRule(t, "all", []string{"a"}, "", "", true)}
+	router.Apply([]domain.SinkConfig{a}, rules)
+
+	slow, err := domain.NewSinkConfig("slow", "slow", nil, true)
+	if err != nil {
+		t.Fatalf("NewSinkConfig: %v", err)
+	}
+	applied := make(chan struct{})
+	go func() {
+		defer close(applied)
+		router.Apply([]domain.SinkConfig{a, *slow}, rules)
+	}()
+
+	published := make(chan struct{})
+	go func() {
+		defer close(published)
+		router.Publish(testMessage(t, domain.LogLevelInfo, "during"))
+	}()
+	select {
+	case <-published:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Publish blocked while a sink was being opened")
+	}
+	close(release)
+	<-applied
+
+	router.Close()
+	if got, _ := mem.sink("a").snapshot(); len(got) != 1 || got[0] != "during" {
+		t.Fatalf("sink a got %v, want [during]", got)
+	}
+}
+
+func TestRouter_CloseDropsTaps(t *testing.T) {
+	t.Parallel()
+
+	router, _ := newTestRouter(t)
+	tap := &recordingPublisher{}
+	router.Tap(tap)
+	router.Close()
+
+	router.Publish(testMessage(t, domain.LogLevelInfo, "late"))
+	if len(tap.payloads) != 0 {
+		t.Fatalf("tap got %v after Close, want nothing", tap.payloads)
+	}
+}
+
+func TestRouter_ApplyDefaultRouteSendsOnlyFlaggedMessages(t *testing.T) {
+	t.Parallel()
+
+	registry, mem := newMemoryRegistry(t)
+	registry.Register(Plugin{Type: domain.WebhookSinkType, Open: func(cfg domain.SinkConfig) (ports.MessageSink, error) {
+		sink := &memorySink{}
+		mem.mu.Lock()
+		defer mem.mu.Unlock()
+		mem.sinks[cfg.ID] = sink
+		return sink, nil
+	}})
+	router, err := NewRouter(registry, stubSinkRepository{})
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+	router.ApplyDefaultRoute()
+
+	flagged, err := domain.NewQueueMessage("1", "PROC", domain.LogLevelInfo, "flagged", time.Now(), true)
+	if err != nil {
+		t.Fatalf("NewQueueMessage: %v", err)
+	}
+	router.Publish(testMessage(t, domain.LogLevelInfo, "plain"))
+	router.Publish(flagged)
+	router.Close()
+
+	if got, _ := mem.sink(domain.WebhookRouteID).snapshot(); len(got) != 1 || got[0] != "flagged" {
+		t.Fatalf("webhook sink got %v, want [flagged]", got)
+	}
+}
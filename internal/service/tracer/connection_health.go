@@ -0,0 +1,148 @@
+package tracer
+
+import (
+	"OmniView/internal/adapter/logger"
+	"OmniView/internal/core/domain"
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// Connection health probe settings
+	healthProbeInterval = 5 * time.Second
+	healthHistorySize   = 60 // Five minutes of samples at healthProbeInterval
+	healthProbeQuery    = "SELECT 1 FROM DUAL"
+)
+
+// healthMonitor keeps the recent connection health samples and counts the
+// messages dequeued between probes. The zero value is ready to use.
+type healthMonitor struct {
+	mu        sync.Mutex
+	samples   []domain.HealthSample
+	dequeued  int       // Messages dequeued since the previous sample
+	countFrom time.Time // Start of the current dequeue count
+
+	reconnectAttempt int    // Current reconnect attempt; 0 while connected
+	reconnectErr     string // Why the listener is reconnecting
+}
+
+// reset drops the samples of a previous listener.
+func (h *healthMonitor) reset(now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.samples = nil
+	h.dequeued = 0
+	h.countFrom = now
+	h.reconnectAttempt = 0
+	h.reconnectErr = ""
+}
+
+// setReconnecting records a reconnect attempt and its cause; attempt 0 marks
+// the session as re-established.
+func (h *healthMonitor) setReconnecting(attempt int, cause error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.reconnectAttempt = attempt
+	h.reconnectErr = ""
+	if cause != nil {
+		h.reconnectErr = cause.Error()
+	}
+}
+
+// recordDequeued adds a dequeued batch to the throughput count.
+func (h *healthMonitor) recordDequeued(count int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.dequeued += count
+}
+
+// record completes sample with the throughput since the previous sample and
+// appends it, keeping at most healthHistorySize samples.
+func (h *healthMonitor) record(sample domain.HealthSample) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if elapsed := sample.At.Sub(h.countFrom); !h.countFrom.IsZero() && elapsed > 0 {
+		sample.MessagesPerSecond = float64(h.dequeued) / elapsed.Seconds()
+	}
+	h.dequeued = 0
+	h.countFrom = sample.At
+	h.samples = append(h.samples, sample)
+	if excess := len(h.samples) - healthHistorySize; excess > 0 {
+		h.samples = slices.Delete(h.samples, 0, excess)
+	}
+}
+
+// reconnecting reports whether a reconnect is in progress.
+func (h *healthMonitor) reconnecting() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.reconnectAttempt > 0
+}
+
+// snapshot returns a copy of the samples.
+func (h *healthMonitor) snapshot() domain.ConnectionHealth {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return domain.ConnectionHealth{
+		Samples:          slices.Clone(h.samples),
+		ProbeInterval:    healthProbeInterval,
+		ReconnectAttempt: h.reconnectAttempt,
+		ReconnectErr:     h.reconnectErr,
+	}
+}
+
+// ConnectionHealth returns the recent health probes of the active listener.
+func (ts *TracerService) ConnectionHealth() domain.ConnectionHealth {
+	return ts.health.snapshot()
+}
+
+// healthProbeLoop probes the connection every healthProbeInterval until the
+// context is cancelled, so silence in the trace feed can be told apart from a
+// dead connection.
+func (ts *TracerService) healthProbeLoop(ctx context.Context, subscriber *domain.Subscriber) {
+	defer ts.listenerWg.Done()
+
+	ticker := time.NewTicker(healthProbeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ts.probeConnection(ctx, subscriber)
+		}
+	}
+}
+
+// probeConnection measures the round trip of a trivial query and samples the
+// queue depth of our consumer. It takes processMu because the listener shares
+// one connection with the blocking dequeue: probing between batches keeps the
+// dequeue wait out of the measured latency. Probes are skipped while the
+// consumer loop re-creates a lost session.
+func (ts *TracerService) probeConnection(ctx context.Context, subscriber *domain.Subscriber) {
+	ts.processMu.Lock()
+	defer ts.processMu.Unlock()
+	if ctx.Err() != nil || ts.health.reconnecting() {
+		return
+	}
+
+	start := time.Now()
+	_, err := ts.db.Fetch(ctx, healthProbeQuery)
+	sample := domain.HealthSample{At: time.Now(), Latency: time.Since(start), QueueDepth: -1}
+	if err != nil {
+		sample.Err = err.Error()
+		logger.Warn("connection health probe failed", "subscriber", subscriber.Name(), "error", err)
+		ts.health.record(sample)
+		return
+	}
+
+	depth, err := ts.db.CheckQueueDepth(ctx, subscriber.ConsumerName(), domain.QueueTableName)
+	if err != nil {
+		logger.Warn("failed to sample queue depth", "subscriber", subscriber.Name(), "error", err)
+	} else {
+		sample.QueueDepth = depth
+	}
+	ts.health.record(sample)
+}
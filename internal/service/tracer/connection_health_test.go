@@ -0,0 +1,89 @@
+package tracer
+
+import (
+	"OmniView/internal/core/domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// probeDatabaseRepository answers the health probe with configurable results.
+type probeDatabaseRepository struct {
+	stubDatabaseRepository
+	fetchErr    error
+	depth       int
+	depthErr    error
+	depthCalled string
+}
+
+func (p *probeDatabaseRepository) Fetch(context.Context, string) ([]string, error) {
+	return []string{"1"}, p.fetchErr
+}
+
+func (p *probeDatabaseRepository) CheckQueueDepth(_ context.Context, subscriberID string, _ string) (int, error) {
+	p.depthCalled = subscriberID
+	return p.depth, p.depthErr
+}
+
+func TestProbeConnection_RecordsLatencyDepthAndThroughput(t *testing.T) {
+	t.Parallel()
+
+	db := &probeDatabaseRepository{depth: 7}
+	ts := &TracerService{db: db}
+	sub := newTestSubscriber(t)
+
+	ts.health.reset(time.Now().Add(-2 * time.Second))
+	ts.health.recordDequeued(10)
+	ts.probeConnection(context.Background(), sub)
+
+	health := ts.ConnectionHealth()
+	sample, ok := health.Latest()
+	if !ok {
+		t.Fatal("expected a health sample")
+	}
+	if sample.QueueDepth != 7 || db.depthCalled != sub.ConsumerName() {
+		t.Fatalf("queue depth = %d for %q, want 7 for the consumer %q", sample.QueueDepth, db.depthCalled, sub.ConsumerName())
+	}
+	if sample.MessagesPerSecond < 4 || sample.MessagesPerSecond > 5.1 {
+		t.Fatalf("MessagesPerSecond = %v, want about 5", sample.MessagesPerSecond)
+	}
+	if health.Status() != domain.ConnectionStatusHealthy {
+		t.Fatalf("Status() = %v, want HEALTHY", health.Status())
+	}
+
+	db.depthErr = errors.New("ORA-00942: table or view does not exist")
+	ts.probeConnection(context.Background(), sub)
+	if got := ts.ConnectionHealth().Status(); got != domain.ConnectionStatusDegraded {
+		t.Fatalf("Status() with an unreadable queue depth = %v, want DEGRADED", got)
+	}
+
+	db.fetchErr = errors.New("ORA-03113: end-of-file on communication channel")
+	ts.probeConnection(context.Background(), sub)
+	latest, _ := ts.ConnectionHealth().Latest()
+	if latest.Status() != domain.ConnectionStatusDown || latest.Err == "" {
+		t.Fatalf("expected a failed probe to report DOWN with the error, got %+v", latest)
+	}
+}
+
+func TestHealthMonitor_KeepsBoundedHistory(t *testing.T) {
+	t.Parallel()
+
+	var h healthMonitor
+	start := time.Now()
+	h.reset(start)
+	for i := 1; i <= healthHistorySize+5; i++ {
+		h.record(domain.HealthSample{At: start.Add(time.Duration(i) * time.Second)})
+	}
+
+	health := h.snapshot()
+	if len(health.Samples) != healthHistorySize {
+		t.Fatalf("kept %d samples, want %d", len(health.Samples), healthHistorySize)
+	}
+	if first := health.Samples[0].At; !first.Equal(start.Add(6 * time.Second)) {
+		t.Fatalf("oldest sample at %v, want the first five dropped", first)
+	}
+	if !health.Stale(start.Add(time.Duration(healthHistorySize+5)*time.Second+time.Minute), 30*time.Second) {
+		t.Fatal("expected an old sample to be stale")
+	}
+}
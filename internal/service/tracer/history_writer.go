@@ -0,0 +1,93 @@
+package tracer
+
+import (
+	"OmniView/internal/adapter/logger"
+	"OmniView/internal/core/domain"
+	"OmniView/internal/core/ports"
+	"context"
+	"time"
+)
+
+// ==========================================
+// Trace History Writer
+// ==========================================
+// Records delivered batches into the trace history on a goroutine of its
+// own. processBatch delivers under processMu, so it only hands the batch
+// over; a BoltDB write there would hold up the next dequeue. The writer also
+// applies the retention limits while a session is recorded, as Append
+// leaves them to Prune.
+
+const (
+	// historyQueueSize is how many delivered batches may wait to be recorded
+	// before more are left out of the history.
+	historyQueueSize = 64
+	// historyPruneInterval is how often the writer prunes the history.
+	historyPruneInterval = 5 * time.Minute
+)
+
+// historyWriter appends the batches of one history session.
+type historyWriter struct {
+	history   ports.TraceHistoryRepository
+	session   *domain.TraceSession // Updated by Append, so only the writer goroutine reads it
+	sessionID string
+	batches   chan []*domain.QueueMessage
+	done      chan struct{}
+}
+
+// startHistoryWriter starts recording into session.
+func startHistoryWriter(history ports.TraceHistoryRepository, session *domain.TraceSession) *historyWriter {
+	w := &historyWriter{
+		history:   history,
+		session:   session,
+		sessionID: session.ID,
+		batches:   make(chan []*domain.QueueMessage, historyQueueSize),
+		done:      make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+func (w *historyWriter) run() {
+	defer close(w.done)
+	ticker := time.NewTicker(historyPruneInterval)
+	defer ticker.Stop()
+
+	// Batches are persisted even when the listener is being cancelled.
+	ctx := context.Background()
+	for {
+		select {
+		case batch, ok := <-w.batches:
+			if !ok {
+				return
+			}
+			if err := w.history.Append(ctx, w.session, batch); err != nil {
+				logger.Warn("failed to record trace history", "session", w.session.ID, "error", err)
+			}
+		case <-ticker.C:
+			if err := w.history.Prune(ctx); err != nil {
+				logger.Warn("failed to prune trace history", "error", err)
+			}
+		}
+	}
+}
+
+// record hands batch to the writer without blocking. When the writer has
+// fallen behind the batch is left out of the history and counted.
+func (w *historyWriter) record(batch []*domain.QueueMessage) {
+	select {
+	case w.batches <- batch:
+	default:
+		historyDrops.Add(uint64(len(batch)))
+		logger.Warn("trace history writer fell behind, dropping batch",
+			"session", w.sessionID,
+			"messages", len(batch),
+			"capacity", historyQueueSize)
+	}
+}
+
+// stop waits until every batch handed to the writer has been recorded. The
+// writer must not be handed more batches afterwards.
+func (w *historyWriter) stop() {
+	close(w.batches)
+	<-w.done
+}
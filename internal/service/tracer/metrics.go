@@ -0,0 +1,94 @@
+package tracer
+
+import (
+	"OmniView/internal/adapter/metrics"
+	"OmniView/internal/core/domain"
+)
+
+// ==========================================
+// Pipeline Metrics
+// ==========================================
+// Counters for every point where the pipeline can lose or delay a message,
+// so a headless instance can be alerted on instead of read from omniview.log.
+
+// Webhook drop reasons
+const (
+	webhookDropQueueFull     = "queue_full"
+	webhookDropStopped       = "stopped"
+	webhookDropUnknownTarget = "unknown_target"
+)
+
+// Webhook delivery results
+const (
+	webhookResultSuccess = "success"
+	webhookResultFailure = "failure"
+)
+
+var (
+	messagesDequeued = metrics.NewCounterVec("omniview_messages_dequeued_total",
+		"Trace messages dequeued from Oracle AQ, by log level.", "level", levelNames()...)
+	dequeueBatchSize = metrics.NewHistogram("omniview_dequeue_batch_size",
+		"Messages returned by one bulk dequeue, including empty waits.",
+		[]float64{0, 1, 5, 10, 25, 50, 100, 250, 500, 1000, 5000, 10000})
+	dequeueWait = metrics.NewHistogram("omniview_dequeue_wait_seconds",
+		"Time spent in one blocking bulk dequeue.",
+		[]float64{0.01, 0.05, 0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 300})
+	unmarshalFailures = metrics.NewCounter("omniview_unmarshal_failures_total",
+		"Dequeued messages dropped because their JSON could not be decoded.")
+	eventChannelDrops = metrics.NewCounter("omniview_event_channel_drops_total",
+		"Messages dropped because the UI event channel was full.")
+	historyDrops = metrics.NewCounter("omniview_history_drops_total",
+		"Delivered messages left out of the trace history because its writer fell behind.")
+
+	webhookDrops = metrics.NewCounterVec("omniview_webhook_drops_total",
+		"Webhook deliveries dropped before sending, by reason.", "reason",
+		webhookDropQueueFull, webhookDropStopped, webhookDropUnknownTarget)
+	webhookDeliveries = metrics.NewCounterVec("omniview_webhook_deliveries_total",
+		"Webhook deliveries attempted, by result.", "result", webhookResultSuccess, webhookResultFailure)
+	webhookRetries = metrics.NewCounter("omniview_webhook_retries_total",
+		"Failed webhook deliveries scheduled for another attempt.")
+	webhookDeadLetters = metrics.NewCounterVec("omniview_webhook_dead_letters_total",
+		"Webhook deliveries saved to the dead-letter store, by reason.", "reason",
+		string(domain.DeadLetterRetriesExhausted), string(domain.DeadLetterRejected),
+		string(domain.DeadLetterQueueFull), string(domain.DeadLetterInterrupted), string(domain.DeadLetterRateLimited))
+	webhookBatched = metrics.NewCounter("omniview_webhook_batched_total",
+		"Webhook messages held for a digest instead of sent on their own.")
+	webhookDigests = metrics.NewCounter("omniview_webhook_digests_total",
+		"Digest deliveries queued, each summarising a batch of webhook messages.")
+	webhookSuppressed = metrics.NewCounter("omniview_webhook_suppressed_total",
+		"Repeated webhook messages counted in a follow-up instead of sent.")
+	webhookRateLimited = metrics.NewCounter("omniview_webhook_rate_limited_total",
+		"Webhook requests held back by their endpoint's rate limit.")
+	webhookAttemptLatency = metrics.NewHistogram("omniview_webhook_attempt_seconds",
+		"Time taken by one webhook HTTP attempt, successful or not. Retried deliveries are observed once per attempt.",
+		[]float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30})
+	_ = metrics.NewGaugeFunc("omniview_webhook_queue_depth",
+		"Webhook deliveries waiting for a worker.", webhookQueueDepth)
+	_ = metrics.NewGaugeFunc("omniview_webhook_queue_capacity",
+		"Webhook deliveries the queue holds before dropping.", func() float64 { return webhookQueueSize })
+
+	reconnects = metrics.NewCounter("omniview_reconnects_total",
+		"Lost database sessions that were re-established.")
+	reconnectFailures = metrics.NewCounter("omniview_reconnect_failures_total",
+		"Reconnect attempts that failed and were retried.")
+)
+
+// levelNames lists the log levels so every level is exported before its first message.
+func levelNames() []string {
+	levels := domain.LogLevels()
+	names := make([]string, len(levels))
+	for i, level := range levels {
+		names[i] = level.String()
+	}
+	return names
+}
+
+// webhookQueueDepth reports the queued deliveries of the global dispatcher,
+// or zero before the first webhook.
+func webhookQueueDepth() float64 {
+	d := startedWebhookDispatcher.Load()
+	if d == nil {
+		return 0
+	}
+	return float64(len(d.queue))
+}
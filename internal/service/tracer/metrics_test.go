@@ -0,0 +1,97 @@
+package tracer
+
+import (
+	"OmniView/internal/core/domain"
+	"OmniView/internal/service/webhook"
+	"context"
+	"testing"
+)
+
+// Not parallel: the pipeline metrics are process-wide, so the deltas below
+// must not pick up batches processed by other tests.
+func TestProcessBatch_CountsDequeuedAndDroppedMessages(t *testing.T) {
+	db := batchDatabaseRepository{messages: []string{
+		`{"message_id":"1","process_name":"P","log_level":"INFO","payload":"one","timestamp":1700000000}`,
+		`not json`,
+		`{"message_id":"2","process_name":"P","log_level":"ERROR","payload":"two","timestamp":1700000001}`,
+	}}
+	// Room for one message, so the second decodable message is dropped
+	ts := &TracerService{db: db, bolt: &stubConfigRepository{}, eventChannel: make(chan *domain.QueueMessage, 1)}
+
+	infoBefore := messagesDequeued.With("INFO").Value()
+	errorBefore := messagesDequeued.With("ERROR").Value()
+	unmarshalBefore := unmarshalFailures.Value()
+	dropsBefore := eventChannelDrops.Value()
+	batchesBefore := dequeueBatchSize.Count()
+	waitsBefore := dequeueWait.Count()
+
+	if err := ts.processBatch(context.Background(), newTestSubscriber(t)); err != nil {
+		t.Fatalf("processBatch: %v", err)
+	}
+
+	checks := []struct {
+		name        string
+		before, got uint64
+		want        uint64
+	}{
+		{"INFO dequeued", infoBefore, messagesDequeued.With("INFO").Value(), 1},
+		{"ERROR dequeued", errorBefore, messagesDequeued.With("ERROR").Value(), 1},
+		{"unmarshal failures", unmarshalBefore, unmarshalFailures.Value(), 1},
+		{"event channel drops", dropsBefore, eventChannelDrops.Value(), 1},
+		{"batch size observations", batchesBefore, dequeueBatchSize.Count(), 1},
+		{"dequeue wait observations", waitsBefore, dequeueWait.Count(), 1},
+	}
+	for _, c := range checks {
+		if delta := c.got - c.before; delta != c.want {
+			t.Errorf("%s increased by %d, want %d", c.name, delta, c.want)
+		}
+	}
+}
+
+func TestWebhookDispatcherEnqueue_CountsDrops(t *testing.T) {
+	d := &webhookDispatcher{queue: make(chan webhookJob, 1)}
+	fullBefore := webhookDrops.With(webhookDropQueueFull).Value()
+	stoppedBefore := webhookDrops.With(webhookDropStopped).Value()
+
+	job := webhookJob{payload: []byte("{}"), url: "https://example.com/hook", meta: webhook.WebhookMetadata{LogLevel: "ERROR"}}
+	_ = d.Enqueue(job)
+	_ = d.Enqueue(job)
+	d.stopped = true
+	_ = d.Enqueue(job)
+
+	if delta := webhookDrops.With(webhookDropQueueFull).Value() - fullBefore; delta != 1 {
+		t.Errorf("queue_full drops increased by %d, want 1", delta)
+	}
+	if delta := webhookDrops.With(webhookDropStopped).Value() - stoppedBefore; delta != 1 {
+		t.Errorf("stopped drops increased by %d, want 1", delta)
+	}
+}
+
+// Not parallel: reads the process-wide event channel drop count.
+func TestProcessBatch_BlockingDeliveryWaitsForTheConsumer(t *testing.T) {
+	db := batchDatabaseRepository{messages: []string{
+		`{"message_id":"1","process_name":"P","log_level":"INFO","payload":"one","timestamp":1700000000}`,
+		`{"message_id":"2","process_name":"P","log_level":"INFO","payload":"two","timestamp":1700000001}`,
+	}}
+	events := make(chan *domain.QueueMessage, 1)
+	ts := &TracerService{db: db, bolt: &stubConfigRepository{}, eventChannel: events}
+	ts.SetBlockingDelivery(true)
+	dropsBefore := eventChannelDrops.Value()
+
+	done := make(chan error, 1)
+	go func() { done <- ts.processBatch(context.Background(), newTestSubscriber(t)) }()
+
+	var payloads []string
+	for range 2 {
+		payloads = append(payloads, (<-events).Payload())
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("processBatch: %v", err)
+	}
+	if len(payloads) != 2 || payloads[0] != "one" || payloads[1] != "two" {
+		t.Fatalf("received %v, want [one two]", payloads)
+	}
+	if delta := eventChannelDrops.Value() - dropsBefore; delta != 0 {
+		t.Fatalf("event channel drops increased by %d, want 0", delta)
+	}
+}
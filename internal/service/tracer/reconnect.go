@@ -0,0 +1,81 @@
+package tracer
+
+import (
+	"OmniView/internal/adapter/logger"
+	"OmniView/internal/core/domain"
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// Reconnect backoff settings
+	reconnectBaseDelay = time.Second
+	reconnectMaxDelay  = time.Minute
+)
+
+// reconnectDelay returns how long to wait before the given reconnect attempt.
+// The first attempt runs at once; later ones back off exponentially from
+// reconnectBaseDelay up to reconnectMaxDelay.
+func reconnectDelay(attempt int) time.Duration {
+	if attempt <= 1 {
+		return 0
+	}
+	delay := reconnectBaseDelay
+	for i := 2; i < attempt && delay < reconnectMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, reconnectMaxDelay)
+}
+
+// reconnect re-creates a lost database session and re-registers subscriber,
+// retrying with exponential backoff until it succeeds. It returns false when
+// ctx is cancelled first.
+func (ts *TracerService) reconnect(ctx context.Context, subscriber *domain.Subscriber, cause error) bool {
+	defer ts.health.setReconnecting(0, nil)
+
+	for attempt := 1; ; attempt++ {
+		ts.health.setReconnecting(attempt, cause)
+		logger.Warn("database connection lost, reconnecting", "subscriber", subscriber.Name(), "attempt", attempt, "error", cause)
+
+		select {
+		case <-time.After(reconnectDelay(attempt)):
+		case <-ctx.Done():
+			return false
+		}
+
+		err := ts.reestablishConnection(ctx, subscriber)
+		if err == nil {
+			reconnects.Inc()
+			logger.Info("database connection re-established", "subscriber", subscriber.Name(), "attempts", attempt)
+			return true
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+		reconnectFailures.Inc()
+		cause = err
+	}
+}
+
+// reestablishConnection tears down the current connection, connects again and
+// re-registers the subscriber. It holds processMu so neither the dequeue nor the
+// health probe touches the connection while it is replaced.
+func (ts *TracerService) reestablishConnection(ctx context.Context, subscriber *domain.Subscriber) error {
+	ts.processMu.Lock()
+	defer ts.processMu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := ts.db.Close(ctx); err != nil {
+		logger.Warn("failed to close lost database connection", "error", err)
+	}
+	if err := ts.db.Connect(ctx); err != nil {
+		return fmt.Errorf("reconnect: %w", err)
+	}
+	if err := ts.db.RegisterNewSubscriber(ctx, *subscriber); err != nil {
+		return fmt.Errorf("reconnect: %w", err)
+	}
+	return nil
+}
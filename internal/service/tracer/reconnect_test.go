@@ -0,0 +1,133 @@
+package tracer
+
+import (
+	"OmniView/internal/core/domain"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// droppingDatabaseRepository loses its session on the first dequeue and
+// counts the calls made to re-create it.
+type droppingDatabaseRepository struct {
+	stubDatabaseRepository
+	mu         sync.Mutex
+	dequeues   int
+	closes     int
+	connects   int
+	registered []string
+	connectErr error
+	resumed    chan struct{}
+}
+
+func (d *droppingDatabaseRepository) BulkDequeueTracerMessages(context.Context, domain.Subscriber) ([]string, [][]byte, int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.dequeues++
+	switch d.dequeues {
+	case 1:
+		return nil, nil, 0, errors.New("failed to dequeue messages: ORA-03113: end-of-file on communication channel")
+	case 2:
+		close(d.resumed)
+	}
+	return nil, nil, 0, nil
+}
+
+func (d *droppingDatabaseRepository) Close(context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.closes++
+	return nil
+}
+
+func (d *droppingDatabaseRepository) Connect(context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.connects++
+	return d.connectErr
+}
+
+func (d *droppingDatabaseRepository) RegisterNewSubscriber(_ context.Context, sub domain.Subscriber) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.registered = append(d.registered, sub.ConsumerName())
+	return nil
+}
+
+func TestBlockingConsumerLoop_ReconnectsAfterLostSession(t *testing.T) {
+	t.Parallel()
+
+	db := &droppingDatabaseRepository{resumed: make(chan struct{})}
+	ts := &TracerService{db: db}
+	sub := newTestSubscriber(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts.listenerWg.Add(1)
+	go ts.blockingConsumerLoop(ctx, sub)
+
+	select {
+	case <-db.resumed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the consumer loop to resume dequeuing after reconnecting")
+	}
+	cancel()
+	ts.listenerWg.Wait()
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.closes != 1 || db.connects != 1 {
+		t.Fatalf("closes = %d, connects = %d, want one of each", db.closes, db.connects)
+	}
+	if len(db.registered) != 1 || db.registered[0] != sub.ConsumerName() {
+		t.Fatalf("registered = %v, want the consumer %q re-registered once", db.registered, sub.ConsumerName())
+	}
+	if ts.ConnectionHealth().Reconnecting() {
+		t.Fatal("expected the reconnect state to clear once the session is back")
+	}
+}
+
+func TestReconnect_ReportsAttemptsAndStopsOnCancel(t *testing.T) {
+	t.Parallel()
+
+	db := &droppingDatabaseRepository{connectErr: errors.New("ORA-12170: TNS:Connect timeout occurred")}
+	ts := &TracerService{db: db}
+	sub := newTestSubscriber(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan bool)
+	go func() { done <- ts.reconnect(ctx, sub, errors.New("ORA-03135: connection lost contact")) }()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for ts.ConnectionHealth().ReconnectAttempt < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected a second reconnect attempt after the first one failed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	health := ts.ConnectionHealth()
+	if health.Status() != domain.ConnectionStatusReconnecting || health.ReconnectErr == "" {
+		t.Fatalf("expected RECONNECTING with the failed attempt's error, got %v %q", health.Status(), health.ReconnectErr)
+	}
+
+	cancel()
+	if <-done {
+		t.Fatal("expected reconnect to give up when cancelled")
+	}
+	if ts.ConnectionHealth().Reconnecting() {
+		t.Fatal("expected the reconnect state to clear on cancel")
+	}
+}
+
+func TestReconnectDelay_BacksOffExponentially(t *testing.T) {
+	t.Parallel()
+
+	want := map[int]time.Duration{1: 0, 2: time.Second, 3: 2 * time.Second, 4: 4 * time.Second, 8: time.Minute, 50: time.Minute}
+	for attempt, delay := range want {
+		if got := reconnectDelay(attempt); got != delay {
+			t.Errorf("reconnectDelay(%d) = %v, want %v", attempt, got, delay)
+		}
+	}
+}
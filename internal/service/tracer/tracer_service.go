@@ -5,7 +5,6 @@ import (
 	"OmniView/internal/adapter/logger"
 	"OmniView/internal/core/domain"
 	"OmniView/internal/core/ports"
-	"OmniView/internal/service/webhook"
 	"context"
 	"crypto/sha256"
 	"encoding/json"
@@ -14,111 +13,6 @@ import (
 	"time"
 )
 
-const (
-	// Webhook worker pool settings
-	webhookWorkers   = 4
-	webhookQueueSize = 100
-)
-
-// webhookDispatcher handles bounded webhook delivery
-type webhookDispatcher struct {
-	service  *webhook.WebhookService
-	queue    chan webhookJob
-	wg       sync.WaitGroup
-	stopped  bool
-	mu       sync.RWMutex
-	stopOnce sync.Once
-}
-
-// webhookJob represents a single webhook delivery task
-type webhookJob struct {
-	payload []byte
-	url     string
-	meta    webhook.WebhookMetadata
-}
-
-// newWebhookDispatcher creates and starts a new webhookDispatcher with a worker pool
-func newWebhookDispatcher() *webhookDispatcher {
-	d := &webhookDispatcher{
-		service: webhook.NewWebhookService(),
-		queue:   make(chan webhookJob, webhookQueueSize),
-	}
-	// Start worker pool
-	for i := 0; i < webhookWorkers; i++ {
-		d.wg.Add(1)
-		go d.worker()
-	}
-	return d
-}
-
-// worker processes webhook jobs from the queue
-func (d *webhookDispatcher) worker() {
-	defer d.wg.Done()
-	for job := range d.queue {
-		if err := d.service.SendToWebhook(job.payload, job.url, job.meta); err != nil {
-			logger.Error("webhook send failed", "error", err)
-		}
-	}
-}
-
-// Enqueue adds a webhook job to the dispatcher's queue if not stopped
-func (d *webhookDispatcher) Enqueue(payload []byte, url string, meta webhook.WebhookMetadata) {
-	d.mu.RLock()
-	defer d.mu.RUnlock()
-	if d.stopped {
-		logger.Warn("webhook dispatcher stopped, dropping message",
-			"url", url,
-			"logLevel", meta.LogLevel,
-			"timestamp", meta.Timestamp,
-			"queue_len", len(d.queue),
-			"queue_cap", cap(d.queue),
-			"dispatcherStopped", d.stopped)
-		return
-	}
-
-	select {
-	case d.queue <- webhookJob{payload: payload, url: url, meta: meta}:
-		// Job queued successfully
-	default:
-		// Queue full - drop the message
-		logger.Warn("webhook queue full, dropping message",
-			"url", url,
-			"logLevel", meta.LogLevel,
-			"timestamp", meta.Timestamp,
-			"queue_len", len(d.queue),
-			"queue_cap", cap(d.queue))
-	}
-}
-
-// Stop signals the dispatcher to stop accepting new jobs and waits for in-flight deliveries to complete
-func (d *webhookDispatcher) Stop() {
-	d.stopOnce.Do(func() {
-		d.mu.Lock()
-		d.stopped = true
-		close(d.queue)
-		d.mu.Unlock()
-		d.wg.Wait()
-	})
-}
-
-// Global webhook dispatcher (initialized on first use)
-var globalWebhookDispatcher *webhookDispatcher
-var dispatcherOnce sync.Once
-
-func getWebhookDispatcher() *webhookDispatcher {
-	dispatcherOnce.Do(func() {
-		globalWebhookDispatcher = newWebhookDispatcher()
-	})
-	return globalWebhookDispatcher
-}
-
-// StopWebhookDispatcher flushes any queued webhook deliveries and stops the global dispatcher if it was initialized.
-func StopWebhookDispatcher() {
-	if globalWebhookDispatcher != nil {
-		globalWebhookDispatcher.Stop()
-	}
-}
-
 // StopAll is deprecated. Use StopConnectionListener on TracerService and stop the
 // global webhook dispatcher separately via StopWebhookDispatcher.
 func StopAll(tracerService *TracerService) {
@@ -139,6 +33,12 @@ type TracerService struct {
 	listenerCancel   context.CancelFunc
 	listenerWg       sync.WaitGroup
 	activeSubscriber *domain.Subscriber
+	history          ports.TraceHistoryRepository
+	historyDB        string
+	historyWriter    *historyWriter
+	health           healthMonitor
+	publisher        ports.MessagePublisher
+	blockOnFull      bool // Wait for room on a full event channel instead of dropping
 }
 
 // Constructor: NewTracerService Constructor for TracerService
@@ -161,6 +61,37 @@ func NewTracerService(
 	}, nil
 }
 
+// SetHistoryRepository enables recording of delivered messages into the trace history
+// store under databaseID. A new history session is started with each event listener.
+// Passing a nil repository disables recording.
+func (ts *TracerService) SetHistoryRepository(history ports.TraceHistoryRepository, databaseID string) {
+	ts.stopHistoryWriter()
+	ts.processMu.Lock()
+	defer ts.processMu.Unlock()
+	ts.history = history
+	ts.historyDB = databaseID
+}
+
+// SetMessagePublisher hands every delivered message to publisher as well as
+// the event channel; the sink router is the publisher that fans messages out
+// to the outputs and webhooks. Passing nil stops publishing.
+func (ts *TracerService) SetMessagePublisher(publisher ports.MessagePublisher) {
+	ts.processMu.Lock()
+	defer ts.processMu.Unlock()
+	ts.publisher = publisher
+}
+
+// SetBlockingDelivery makes delivery wait for room on a full event channel
+// instead of dropping the message, so a slow consumer holds up dequeuing and
+// the messages wait in the queue. The wait holds the batch lock until the
+// consumer catches up or the listener stops, so it suits a headless
+// consumer such as tail, not the TUI.
+func (ts *TracerService) SetBlockingDelivery(block bool) {
+	ts.processMu.Lock()
+	defer ts.processMu.Unlock()
+	ts.blockOnFull = block
+}
+
 // StopConnectionListener stops the current connection-scoped listener and clears
 // any queued connection events that raced with cancellation.
 func (ts *TracerService) StopConnectionListener() {
@@ -181,6 +112,7 @@ func (ts *TracerService) CancelConnectionListener() {
 		ts.listenerCtx = nil
 	}
 	ts.drainEventChannel()
+	ts.stopHistoryWriter()
 
 	ts.subscriberMu.Lock()
 	var sub *domain.Subscriber
@@ -212,6 +144,9 @@ func (ts *TracerService) StartEventListener(ctx context.Context, subscriber *dom
 	*ts.activeSubscriber = *subscriber
 	ts.subscriberMu.Unlock()
 
+	ts.beginHistorySession(ctx, subscriber)
+	ts.health.reset(time.Now())
+
 	// Create a cancellable context for event listeners
 	ts.listenerCtx, ts.listenerCancel = context.WithCancel(ctx)
 
@@ -229,6 +164,10 @@ func (ts *TracerService) StartEventListener(ctx context.Context, subscriber *dom
 	ts.listenerWg.Add(1)
 	go ts.blockingConsumerLoop(ts.listenerCtx, subscriber)
 
+	// Probe latency and queue depth alongside the consumer loop
+	ts.listenerWg.Add(1)
+	go ts.healthProbeLoop(ts.listenerCtx, subscriber)
+
 	return nil
 }
 
@@ -245,7 +184,8 @@ func (ts *TracerService) drainEventChannel() {
 	}
 }
 
-// blockingConsumerLoop continuously waits for new messages for the subscriber and processes them until the context is cancelled
+// blockingConsumerLoop continuously waits for new messages for the subscriber and processes them until the context is cancelled.
+// When the database session drops it reconnects with backoff before resuming.
 func (ts *TracerService) blockingConsumerLoop(ctx context.Context, subscriber *domain.Subscriber) {
 	defer ts.listenerWg.Done()
 
@@ -262,6 +202,13 @@ func (ts *TracerService) blockingConsumerLoop(ctx context.Context, subscriber *d
 
 		// Blocking wait — Oracle holds this call until messages arrive or wait time expires
 		err := ts.processBatch(ctx, subscriber)
+		if err != nil && domain.IsConnectionLost(err) {
+			// The session is gone; retrying the dequeue cannot succeed until it is re-created
+			if !ts.reconnect(ctx, subscriber, err) {
+				return
+			}
+			continue
+		}
 		if err != nil {
 			logger.Error("batch processing failed", "subscriber", subscriber.Name(), "error", err)
 			select {
@@ -286,10 +233,14 @@ func (ts *TracerService) processBatch(ctx context.Context, subscriber *domain.Su
 		ts.processMu.Lock()
 		defer ts.processMu.Unlock()
 
+		start := time.Now()
 		messages, msgIDs, count, err := ts.db.BulkDequeueTracerMessages(ctx, *subscriber)
 		if err != nil {
 			return err
 		}
+		dequeueWait.Observe(time.Since(start).Seconds())
+		dequeueBatchSize.Observe(float64(count))
+		ts.health.recordDequeued(count)
 
 		if count == 0 {
 			return nil
@@ -299,64 +250,164 @@ func (ts *TracerService) processBatch(ctx context.Context, subscriber *domain.Su
 			return fmt.Errorf("bulk dequeue invariant violated: count=%d messages=%d msgIDs=%d", count, len(messages), len(msgIDs))
 		}
 
+		delivered := make([]*domain.QueueMessage, 0, count)
 		for i := 0; i < count; i++ {
 			msg := &domain.QueueMessage{}
 			if err := json.Unmarshal([]byte(messages[i]), msg); err != nil {
+				unmarshalFailures.Inc()
 				logger.Error("failed to unmarshal message", "msgID", msgIDs[i], "error", err)
 				continue
 			}
+			messagesDequeued.With(msg.LogLevel().String()).Inc()
 			// Deliver while holding lock to preserve ordering
 			if !ts.handleTracerMessage(ctx, msg) {
+				ts.recordHistory(delivered)
 				return ctx.Err()
 			}
+			delivered = append(delivered, msg)
 		}
+		ts.recordHistory(delivered)
 		return nil
 	}()
 
 	return err
 }
 
-// handleTracerMessage processes a single tracer message and dispatches to UI and webhooks
+// beginHistorySession opens a new trace history session for the listener being started.
+// History is best effort; failures are logged and recording stays off for this listener.
+func (ts *TracerService) beginHistorySession(ctx context.Context, subscriber *domain.Subscriber) {
+	ts.stopHistoryWriter()
+	ts.processMu.Lock()
+	defer ts.processMu.Unlock()
+
+	if ts.history == nil || ts.historyDB == "" {
+		return
+	}
+	session, err := ts.history.BeginSession(ctx, ts.historyDB, subscriber.Name())
+	if err != nil {
+		logger.Warn("failed to start trace history session", "databaseID", ts.historyDB, "error", err)
+		return
+	}
+	ts.historyWriter = startHistoryWriter(ts.history, session)
+}
+
+// stopHistoryWriter ends the active history session once its queued batches
+// are recorded.
+func (ts *TracerService) stopHistoryWriter() {
+	ts.processMu.Lock()
+	w := ts.historyWriter
+	ts.historyWriter = nil
+	ts.processMu.Unlock()
+
+	if w != nil {
+		w.stop()
+	}
+}
+
+// recordHistory hands a delivered batch to the history writer of the active session.
+// The caller must hold processMu.
+func (ts *TracerService) recordHistory(delivered []*domain.QueueMessage) {
+	if ts.historyWriter == nil || len(delivered) == 0 {
+		return
+	}
+	ts.historyWriter.record(delivered)
+}
+
+// handleTracerMessage processes a single tracer message and dispatches it to the UI and the publisher
 func (ts *TracerService) handleTracerMessage(ctx context.Context, msg *domain.QueueMessage) bool {
 	// Always send to TUI if channel is available.
-	// Non-blocking: processMu is held by the caller (processBatch), so we must not
-	// block here — a full channel would stall the lock until the UI consumer catches up.
-	// The buffered channel absorbs normal bursts; drops only occur when the consumer
-	// is not yet running (e.g., during the welcome animation) and the buffer is full.
-	if ts.eventChannel != nil {
+	// Non-blocking unless SetBlockingDelivery asked otherwise: processMu is held by
+	// the caller (processBatch), so a full channel would stall the lock until the
+	// UI consumer catches up. The buffered channel absorbs normal bursts; drops only
+	// occur when the consumer is not yet running (e.g., during the welcome
+	// animation) and the buffer is full.
+	if ts.eventChannel != nil && ts.blockOnFull {
+		select {
+		case ts.eventChannel <- msg:
+		case <-ctx.Done():
+			return false
+		}
+	} else if ts.eventChannel != nil {
 		select {
 		case ts.eventChannel <- msg:
 		case <-ctx.Done():
 			return false
 		default:
+			eventChannelDrops.Inc()
 			logger.Warn("event channel full, dropping message")
 		}
 	} else {
 		logger.Info("event channel unavailable, emitting via structured logger", "msg", msg.Format())
 	}
+	if ts.publisher != nil {
+		ts.publisher.Publish(msg)
+	}
+	return true
+}
 
-	if !msg.SendToWebhook() {
-		return true
+// ForwardToWebhook queues msg for delivery to the webhook it targets, or the
+// default webhook, regardless of its send_to_webhook flag and the webhook's
+// level filter. Used for manual forwarding from the UI.
+func (ts *TracerService) ForwardToWebhook(msg *domain.QueueMessage) error {
+	webhookConfig, err := resolveWebhook(ts.bolt, msg.WebhookTarget())
+	if err != nil {
+		return fmt.Errorf("ForwardToWebhook: %w", err)
 	}
+	if webhookConfig.URL == "" {
+		return fmt.Errorf("ForwardToWebhook: %w", domain.ErrWebhookConfigNotFound)
+	}
+	if !webhookConfig.Enabled {
+		return fmt.Errorf("ForwardToWebhook %s: %w", webhookConfig.ID, domain.ErrWebhookDisabled)
+	}
+	if err := enqueueWebhook(msg, webhookConfig, false); err != nil {
+		return fmt.Errorf("ForwardToWebhook: %w", err)
+	}
+	return nil
+}
 
-	// Dispatch to webhook if configured
-	webhookConfig, err := ts.bolt.GetWebhookConfig()
-	if err != nil || webhookConfig == nil || webhookConfig.URL == "" {
-		return true
+// resolveWebhook returns the webhook in configs named by target, matched
+// case-insensitively, or the default webhook when target is empty.
+func resolveWebhook(configs ports.ConfigRepository, target string) (*domain.WebhookConfig, error) {
+	if target == "" {
+		webhookConfig, err := configs.GetWebhookConfig()
+		if err != nil {
+			return nil, err
+		}
+		if webhookConfig == nil {
+			return nil, domain.ErrWebhookConfigNotFound
+		}
+		return webhookConfig, nil
 	}
 
-	payload, err := json.Marshal(msg)
+	webhooks, err := configs.ListWebhookConfigs()
 	if err != nil {
-		logger.Error("failed to marshal message for webhook", "error", err)
-		return true
+		return nil, err
+	}
+	return matchWebhook(webhooks, target)
+}
+
+// matchWebhook returns a copy of the webhook named by target, matched
+// case-insensitively.
+func matchWebhook(webhooks []domain.WebhookConfig, target string) (*domain.WebhookConfig, error) {
+	for i := range webhooks {
+		if webhooks[i].MatchesTarget(target) {
+			webhookConfig := webhooks[i]
+			return &webhookConfig, nil
+		}
 	}
+	return nil, fmt.Errorf("%w: %q", domain.ErrWebhookConfigNotFound, target)
+}
 
-	meta := webhook.WebhookMetadata{
-		LogLevel:  string(msg.LogLevel()),
-		Timestamp: msg.Timestamp().Format(time.RFC3339),
+// enqueueWebhook hands msg to the global webhook dispatcher for delivery to
+// webhookConfig. With batch set, a repeat of a message sent within the
+// webhook's suppress window is only counted, and a message the webhook
+// batches is held for its next digest.
+func enqueueWebhook(msg *domain.QueueMessage, webhookConfig *domain.WebhookConfig, batch bool) error {
+	d := getWebhookDispatcher()
+	if batch && d.Suppress(msg, *webhookConfig) {
+		return nil
 	}
-	getWebhookDispatcher().Enqueue(payload, webhookConfig.URL, meta)
-	return true
+	return d.Submit(msg, webhookConfig, batch)
 }
 
 // DeployAndCheck ensures the necessary tracer package is deployed and initialized
@@ -15,27 +15,40 @@ func (r *stubConfigRepository) SaveDatabaseConfig(*domain.DatabaseSettings) erro
 func (r *stubConfigRepository) GetDefaultDatabaseConfig() (*domain.DatabaseSettings, error) {
 	return nil, nil
 }
-func (r *stubConfigRepository) IsApplicationFirstRun() (bool, error)               { return false, nil }
-func (r *stubConfigRepository) SetFirstRunCycleStatus(domain.RunCycleStatus) error { return nil }
-func (r *stubConfigRepository) SaveWebhookConfig(*domain.WebhookConfig) error      { return nil }
-func (r *stubConfigRepository) GetWebhookConfig() (*domain.WebhookConfig, error)   { return nil, nil }
-func (r *stubConfigRepository) DeleteWebhookConfig(string) error                   { return nil }
-func (r *stubConfigRepository) GetTracerPackageVersion() (string, error)           { return "", nil }
-func (r *stubConfigRepository) SetTracerPackageVersion(string) error               { return nil }
+func (r *stubConfigRepository) IsApplicationFirstRun() (bool, error)                { return false, nil }
+func (r *stubConfigRepository) SetFirstRunCycleStatus(domain.RunCycleStatus) error  { return nil }
+func (r *stubConfigRepository) SaveWebhookConfig(*domain.WebhookConfig) error       { return nil }
+func (r *stubConfigRepository) GetWebhookConfig() (*domain.WebhookConfig, error)    { return nil, nil }
+func (r *stubConfigRepository) DeleteWebhookConfig(string) error                    { return nil }
+func (r *stubConfigRepository) ListWebhookConfigs() ([]domain.WebhookConfig, error) { return nil, nil }
+func (r *stubConfigRepository) SetDefaultWebhook(string) error                      { return nil }
+func (r *stubConfigRepository) GetTracerPackageVersion() (string, error)            { return "", nil }
+func (r *stubConfigRepository) SetTracerPackageVersion(string) error                { return nil }
 func (r *stubConfigRepository) GetBroadcastMode() (domain.BroadcastMode, error) {
 	return domain.BroadcastModeGlobal, nil
 }
 func (r *stubConfigRepository) SetBroadcastMode(domain.BroadcastMode) error { return nil }
 
+// webhookConfigRepository serves config as the default webhook beside the
+// named webhooks.
 type webhookConfigRepository struct {
 	stubConfigRepository
 	config *domain.WebhookConfig
+	named  []domain.WebhookConfig
 }
 
 func (r *webhookConfigRepository) GetWebhookConfig() (*domain.WebhookConfig, error) {
 	return r.config, nil
 }
 
+func (r *webhookConfigRepository) ListWebhookConfigs() ([]domain.WebhookConfig, error) {
+	configs := append([]domain.WebhookConfig(nil), r.named...)
+	if r.config != nil {
+		configs = append(configs, *r.config)
+	}
+	return configs, nil
+}
+
 type stubDatabaseRepository struct{}
 
 func (stubDatabaseRepository) Connect(context.Context) error { return nil }
@@ -255,7 +268,137 @@ func TestCancelConnectionListener_ContinuesOnUnregisterError(t *testing.T) {
 	}
 }
 
-func TestHandleTracerMessage_QueuesWebhookOnlyWithOptIn(t *testing.T) {
+func TestForwardToWebhook_RejectsDisabledWebhook(t *testing.T) {
+	t.Parallel()
+
+	paused, err := domain.NewWebhookConfig("paused", "https://example.com/paused", false)
+	if err != nil {
+		t.Fatalf("failed to create webhook config: %v", err)
+	}
+	ts := &TracerService{bolt: &webhookConfigRepository{named: []domain.WebhookConfig{*paused}}}
+
+	msg, err := domain.NewQueueMessage("message", "TEST_PROCESS", domain.LogLevelInfo, "payload", time.Unix(1700000000, 0))
+	if err != nil {
+		t.Fatalf("failed to create queue message: %v", err)
+	}
+	msg.SetWebhookTarget("paused")
+	if err := ts.ForwardToWebhook(msg); !errors.Is(err, domain.ErrWebhookDisabled) {
+		t.Fatalf("ForwardToWebhook to a disabled webhook error = %v, want ErrWebhookDisabled", err)
+	}
+}
+
+// batchDatabaseRepository returns a fixed batch from BulkDequeueTracerMessages.
+type batchDatabaseRepository struct {
+	stubDatabaseRepository
+	messages []string
+}
+
+func (r batchDatabaseRepository) BulkDequeueTracerMessages(context.Context, domain.Subscriber) ([]string, [][]byte, int, error) {
+	ids := make([][]byte, len(r.messages))
+	return r.messages, ids, len(r.messages), nil
+}
+
+// spyHistoryRepository records appended batches in memory.
+type spyHistoryRepository struct {
+	mu       sync.Mutex
+	begun    []string
+	appended [][]*domain.QueueMessage
+}
+
+func (s *spyHistoryRepository) BeginSession(_ context.Context, databaseID string, subscriberName string) (*domain.TraceSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.begun = append(s.begun, databaseID)
+	return domain.NewTraceSession(databaseID, subscriberName, time.Now())
+}
+
+func (s *spyHistoryRepository) Append(_ context.Context, _ *domain.TraceSession, messages []*domain.QueueMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.appended = append(s.appended, messages)
+	return nil
+}
+
+func (s *spyHistoryRepository) ListSessions(context.Context, string) ([]domain.TraceSession, error) {
+	return nil, nil
+}
+
+func (s *spyHistoryRepository) ReadSession(context.Context, domain.TraceSession, string, int) ([]*domain.QueueMessage, string, error) {
+	return nil, "", nil
+}
+
+func (s *spyHistoryRepository) Retention() domain.HistoryRetention   { return domain.HistoryRetention{} }
+func (s *spyHistoryRepository) SetRetention(domain.HistoryRetention) {}
+func (s *spyHistoryRepository) Prune(context.Context) error          { return nil }
+
+func TestProcessBatch_RecordsDeliveredMessagesInHistory(t *testing.T) {
+	t.Parallel()
+
+	db := batchDatabaseRepository{messages: []string{
+		`{"message_id":"1","process_name":"P","log_level":"INFO","payload":"one","timestamp":1700000000}`,
+		`not json`,
+		`{"message_id":"2","process_name":"P","log_level":"ERROR","payload":"two","timestamp":1700000001}`,
+	}}
+	history := &spyHistoryRepository{}
+	ts := &TracerService{db: db, bolt: &stubConfigRepository{}, eventChannel: make(chan *domain.QueueMessage, 4)}
+	ts.SetHistoryRepository(history, "DEV")
+
+	sub := newTestSubscriber(t)
+	ts.beginHistorySession(context.Background(), sub)
+	if err := ts.processBatch(context.Background(), sub); err != nil {
+		t.Fatalf("processBatch: %v", err)
+	}
+	ts.stopHistoryWriter()
+
+	history.mu.Lock()
+	defer history.mu.Unlock()
+	if len(history.begun) != 1 || history.begun[0] != "DEV" {
+		t.Fatalf("expected one history session for DEV, got %v", history.begun)
+	}
+	if len(history.appended) != 1 || len(history.appended[0]) != 2 {
+		t.Fatalf("expected one batch with the two decodable messages, got %v", history.appended)
+	}
+}
+
+// blockingHistoryRepository holds every Append until release is closed.
+type blockingHistoryRepository struct {
+	spyHistoryRepository
+	release chan struct{}
+}
+
+func (b *blockingHistoryRepository) Append(ctx context.Context, session *domain.TraceSession, messages []*domain.QueueMessage) error {
+	<-b.release
+	return b.spyHistoryRepository.Append(ctx, session, messages)
+}
+
+func TestProcessBatch_DoesNotWaitForTheHistoryStore(t *testing.T) {
+	t.Parallel()
+
+	db := batchDatabaseRepository{messages: []string{
+		`{"message_id":"1","process_name":"P","log_level":"INFO","payload":"one","timestamp":1700000000}`,
+	}}
+	history := &blockingHistoryRepository{release: make(chan struct{})}
+	ts := &TracerService{db: db, bolt: &stubConfigRepository{}}
+	ts.SetHistoryRepository(history, "DEV")
+
+	sub := newTestSubscriber(t)
+	ts.beginHistorySession(context.Background(), sub)
+	for i := range 3 {
+		if err := ts.processBatch(context.Background(), sub); err != nil {
+			t.Fatalf("processBatch %d: %v", i, err)
+		}
+	}
+
+	close(history.release)
+	ts.stopHistoryWriter()
+	history.mu.Lock()
+	defer history.mu.Unlock()
+	if len(history.appended) != 3 {
+		t.Fatalf("recorded %d batches after the store caught up, want 3", len(history.appended))
+	}
+}
+
+func TestForwardToWebhook_IgnoresOptInAndReportsMissingConfig(t *testing.T) {
 	previousDispatcher := globalWebhookDispatcher
 
 	t.Cleanup(func() {
@@ -263,47 +406,63 @@ func TestHandleTracerMessage_QueuesWebhookOnlyWithOptIn(t *testing.T) {
 		dispatcherOnce = sync.Once{}
 	})
 
+	injectedDispatcher := &webhookDispatcher{queue: make(chan webhookJob, 1)}
+	globalWebhookDispatcher = injectedDispatcher
+	dispatcherOnce = sync.Once{}
+	dispatcherOnce.Do(func() {})
+
+	msg, err := domain.NewQueueMessage("message", "TEST_PROCESS", domain.LogLevelInfo, "payload", time.Unix(1700000000, 0))
+	if err != nil {
+		t.Fatalf("failed to create queue message: %v", err)
+	}
+
+	unconfigured := &TracerService{bolt: &stubConfigRepository{}}
+	if err := unconfigured.ForwardToWebhook(msg); !errors.Is(err, domain.ErrWebhookConfigNotFound) {
+		t.Fatalf("ForwardToWebhook without config error = %v, want ErrWebhookConfigNotFound", err)
+	}
+
 	config, err := domain.NewWebhookConfig(domain.DefaultWebhookID, "https://example.com/webhook", true)
 	if err != nil {
 		t.Fatalf("failed to create webhook config: %v", err)
 	}
+	ts := &TracerService{bolt: &webhookConfigRepository{config: config}}
+	if err := ts.ForwardToWebhook(msg); err != nil {
+		t.Fatalf("ForwardToWebhook: %v", err)
+	}
+	if queued := len(injectedDispatcher.queue); queued != 1 {
+		t.Fatalf("expected the message to be queued without send_to_webhook, got %d jobs", queued)
+	}
 
-	for _, tt := range []struct {
-		name          string
-		sendToWebhook bool
-		wantQueued    int
-	}{
-		{name: "without opt-in", sendToWebhook: false, wantQueued: 0},
-		{name: "with opt-in", sendToWebhook: true, wantQueued: 1},
-	} {
-		t.Run(tt.name, func(t *testing.T) {
-			injectedDispatcher := &webhookDispatcher{queue: make(chan webhookJob, 1)}
-			globalWebhookDispatcher = injectedDispatcher
-			dispatcherOnce = sync.Once{}
-			// Mark sync.Once as used so getWebhookDispatcher keeps the injected dispatcher
-			// instead of replacing it with a real worker-backed dispatcher.
-			dispatcherOnce.Do(func() {})
-
-			msg, err := domain.NewQueueMessage(
-				"message",
-				"TEST_PROCESS",
-				domain.LogLevelInfo,
-				"payload",
-				time.Unix(1700000000, 0),
-				tt.sendToWebhook,
-			)
-			if err != nil {
-				t.Fatalf("failed to create queue message: %v", err)
-			}
-
-			ts := &TracerService{bolt: &webhookConfigRepository{config: config}}
-			if ok := ts.handleTracerMessage(context.Background(), msg); !ok {
-				t.Fatal("expected handleTracerMessage to continue processing")
-			}
-
-			if queued := len(injectedDispatcher.queue); queued != tt.wantQueued {
-				t.Fatalf("expected %d webhook jobs to be queued, got %d", tt.wantQueued, queued)
-			}
-		})
+	if err := ts.ForwardToWebhook(msg); !errors.Is(err, domain.ErrWebhookQueueFull) {
+		t.Fatalf("ForwardToWebhook on a full queue error = %v, want ErrWebhookQueueFull", err)
+	}
+}
+
+// spyPublisher records published messages.
+type spyPublisher struct{ published []*domain.QueueMessage }
+
+func (p *spyPublisher) Publish(msg *domain.QueueMessage) { p.published = append(p.published, msg) }
+
+func TestProcessBatch_PublishesBesideTheEventChannel(t *testing.T) {
+	t.Parallel()
+
+	db := batchDatabaseRepository{messages: []string{
+		`{"message_id":"1","process_name":"P","log_level":"INFO","payload":"one","timestamp":1700000000}`,
+		`{"message_id":"2","process_name":"P","log_level":"ERROR","payload":"two","timestamp":1700000001}`,
+	}}
+	// The event channel only has room for one message; publishing must not depend on it
+	events := make(chan *domain.QueueMessage, 1)
+	publisher := &spyPublisher{}
+	ts := &TracerService{db: db, bolt: &stubConfigRepository{}, eventChannel: events}
+	ts.SetMessagePublisher(publisher)
+
+	if err := ts.processBatch(context.Background(), newTestSubscriber(t)); err != nil {
+		t.Fatalf("processBatch: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected the event channel to still receive messages, got %d", len(events))
+	}
+	if len(publisher.published) != 2 || publisher.published[1].MessageID() != "2" {
+		t.Fatalf("expected both messages to be published in order, got %v", publisher.published)
 	}
 }
@@ -0,0 +1,115 @@
+package tracer
+
+import (
+	"OmniView/internal/adapter/logger"
+	"OmniView/internal/core/domain"
+	"OmniView/internal/service/webhook"
+	"encoding/json"
+	"time"
+)
+
+// ==========================================
+// Webhook Batching
+// ==========================================
+// Holds the messages of webhooks that batch and sends each batch as one
+// digest through the dispatcher queue, so a job failing in a loop produces
+// one request per window instead of one per error. A batch is sent when
+// its window ends, when it reaches the webhook's message limit, or when
+// the dispatcher stops.
+
+// webhookBatch collects the messages held for one webhook.
+type webhookBatch struct {
+	target webhookJob // Latest message's job; the digest goes to the config it was sent with
+	first  webhookJob // Sent on its own when the batch holds only one message
+	digest domain.WebhookDigest
+	timer  *time.Timer
+}
+
+// Batch holds job, the delivery of msg, for its webhook's next digest. The
+// batch is sent as soon as it holds batching.MaxMessages messages, or when
+// batching.Window has passed since its first message.
+func (d *webhookDispatcher) Batch(job webhookJob, msg *domain.QueueMessage, batching domain.WebhookBatching) error {
+	d.batchMu.Lock()
+	if d.batchesClosed {
+		d.batchMu.Unlock()
+		// Enqueue saves what it can no longer queue
+		return d.Enqueue(job)
+	}
+	if d.batches == nil {
+		d.batches = make(map[string]*webhookBatch)
+	}
+	b := d.batches[job.webhookID]
+	if b == nil {
+		b = &webhookBatch{first: job}
+		d.batches[job.webhookID] = b
+		b.timer = time.AfterFunc(batching.Window, func() { d.flushBatch(job.webhookID, b) })
+	}
+	b.target = job
+	b.digest.Add(msg)
+	full := batching.MaxMessages > 0 && b.digest.Count >= batching.MaxMessages
+	d.batchMu.Unlock()
+
+	webhookBatched.Inc()
+	if full {
+		d.flushBatch(job.webhookID, b)
+	}
+	return nil
+}
+
+// flushBatch sends b if it is still the open batch for webhookID. Whoever
+// removes a batch from the map sends it, so a batch that fills up as its
+// window ends is sent once.
+func (d *webhookDispatcher) flushBatch(webhookID string, b *webhookBatch) {
+	d.batchMu.Lock()
+	if d.batches[webhookID] != b {
+		d.batchMu.Unlock()
+		return
+	}
+	delete(d.batches, webhookID)
+	b.timer.Stop()
+	d.batchMu.Unlock()
+
+	// Failures are logged, and saved as dead letters, by Enqueue
+	_ = d.Enqueue(b.job())
+}
+
+// flushBatches sends every open batch and stops batching. Stop calls it
+// before closing the queue, so held messages are sent or saved rather than
+// lost.
+func (d *webhookDispatcher) flushBatches() {
+	d.batchMu.Lock()
+	batches := d.batches
+	d.batches = nil
+	d.batchesClosed = true
+	d.batchMu.Unlock()
+
+	for _, b := range batches {
+		b.timer.Stop()
+		_ = d.Enqueue(b.job())
+	}
+}
+
+// job returns the delivery for the batch: its only message as it is, or a
+// digest of all of them.
+func (b *webhookBatch) job() webhookJob {
+	if b.digest.Count == 1 {
+		return b.first
+	}
+	b.digest.Finish()
+	job := b.target
+	job.meta = webhook.WebhookMetadata{
+		WebhookID: job.webhookID,
+		LogLevel:  string(b.digest.Highest()),
+		Timestamp: b.digest.Last.Format(time.RFC3339),
+		Digest:    true,
+	}
+	payload, err := json.Marshal(&b.digest)
+	if err != nil {
+		// The empty payload fails to render, so the delivery is kept as a
+		// rejected dead letter
+		logger.Error("failed to marshal webhook digest", "webhook", job.webhookID, "error", err)
+	}
+	job.payload = payload
+	webhookDigests.Inc()
+	return job
+}
@@ -0,0 +1,116 @@
+package tracer
+
+import (
+	"OmniView/internal/core/domain"
+	"OmniView/internal/service/webhook"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// batchJob returns the delivery of a message to the ops webhook in the
+// default JSON format, with the message itself.
+func batchJob(t *testing.T, level domain.LogLevel, payload string) (webhookJob, *domain.QueueMessage) {
+	t.Helper()
+	msg, err := domain.NewQueueMessage("m1", "BILLING", level, payload, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), true)
+	if err != nil {
+		t.Fatalf("NewQueueMessage: %v", err)
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	return webhookJob{
+		webhookID: "ops",
+		payload:   data,
+		url:       "https://example.com/hook",
+		meta:      webhook.WebhookMetadata{LogLevel: string(level)},
+	}, msg
+}
+
+// decodeDigest reads the digest out of a request body in the default format.
+func decodeDigest(t *testing.T, body []byte) (webhook.WebhookPayload, domain.WebhookDigest) {
+	t.Helper()
+	var envelope webhook.WebhookPayload
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		t.Fatalf("Unmarshal envelope: %v", err)
+	}
+	var digest domain.WebhookDigest
+	if envelope.Digest {
+		if err := json.Unmarshal([]byte(envelope.Message), &digest); err != nil {
+			t.Fatalf("Unmarshal digest: %v", err)
+		}
+	}
+	return envelope, digest
+}
+
+func TestWebhookDispatcherBatch_SendsDigestWhenFull(t *testing.T) {
+	sender := &fakeSender{}
+	d := startTestDispatcher(sender, &memoryDeadLetters{}, fastRetries)
+	defer d.Stop()
+
+	batching := domain.WebhookBatching{Window: time.Hour, MaxMessages: 4}
+	for i := range 4 {
+		level, payload := domain.LogLevelError, "ORA-00054: resource busy"
+		if i == 3 {
+			level, payload = domain.LogLevelWarning, "retrying"
+		}
+		job, msg := batchJob(t, level, payload)
+		if err := d.Batch(job, msg, batching); err != nil {
+			t.Fatalf("Batch: %v", err)
+		}
+	}
+	waitFor(t, func() bool { return sender.sendCount() == 1 })
+
+	envelope, digest := decodeDigest(t, sender.sentBodies()[0])
+	if !envelope.Digest || envelope.LogLevel != "ERROR" {
+		t.Fatalf("envelope = %+v, want an ERROR digest", envelope)
+	}
+	if digest.Count != 4 || digest.Levels[domain.LogLevelError] != 3 || len(digest.Top) != 2 ||
+		digest.Top[0].Payload != "ORA-00054: resource busy" || digest.Top[0].Count != 3 {
+		t.Fatalf("unexpected digest %+v", digest)
+	}
+}
+
+func TestWebhookDispatcherBatch_WindowSendsLoneMessageAsItIs(t *testing.T) {
+	sender := &fakeSender{}
+	d := startTestDispatcher(sender, &memoryDeadLetters{}, fastRetries)
+	defer d.Stop()
+
+	job, msg := batchJob(t, domain.LogLevelError, "disk full")
+	if err := d.Batch(job, msg, domain.WebhookBatching{Window: 10 * time.Millisecond}); err != nil {
+		t.Fatalf("Batch: %v", err)
+	}
+	waitFor(t, func() bool { return sender.sendCount() == 1 })
+
+	if envelope, _ := decodeDigest(t, sender.sentBodies()[0]); envelope.Digest || envelope.Message != string(job.payload) {
+		t.Fatalf("envelope = %+v, want the message on its own", envelope)
+	}
+}
+
+func TestWebhookDispatcherStop_SendsOpenBatches(t *testing.T) {
+	sender := &fakeSender{}
+	d := startTestDispatcher(sender, &memoryDeadLetters{}, fastRetries)
+
+	for i := range 3 {
+		job, msg := batchJob(t, domain.LogLevelError, fmt.Sprintf("failure %d", i))
+		if err := d.Batch(job, msg, domain.WebhookBatching{Window: time.Hour}); err != nil {
+			t.Fatalf("Batch: %v", err)
+		}
+	}
+	d.Stop()
+
+	if sends := sender.sendCount(); sends != 1 {
+		t.Fatalf("sends = %d, want the open batch sent as one digest", sends)
+	}
+	if _, digest := decodeDigest(t, sender.sentBodies()[0]); digest.Count != 3 {
+		t.Fatalf("digest = %+v, want all three messages", digest)
+	}
+
+	// Once stopped, messages are no longer held
+	job, msg := batchJob(t, domain.LogLevelError, "late")
+	if err := d.Batch(job, msg, domain.WebhookBatching{Window: time.Hour}); err == nil {
+		t.Fatal("expected a message batched after Stop to be refused")
+	}
+}
@@ -0,0 +1,77 @@
+package tracer
+
+import (
+	"OmniView/internal/adapter/logger"
+	"OmniView/internal/core/domain"
+)
+
+// ==========================================
+// Webhook Dead-Letter Writer
+// ==========================================
+// Saves the jobs Enqueue turns away on a goroutine of its own. Enqueue runs
+// on the dequeue path under the dispatcher's read lock, so it only hands the
+// job over; a BoltDB write there would hold up both. Workers save the
+// deliveries they give up on themselves, as they hold no lock.
+
+// deadLetterQueueSize is how many turned-away jobs may wait to be saved
+// before more are dropped.
+const deadLetterQueueSize = 256
+
+// deadLetterRequest is one job to save, or, with flushed set, a marker the
+// writer closes once every request before it has been handled.
+type deadLetterRequest struct {
+	job     webhookJob
+	reason  domain.DeadLetterReason
+	cause   error
+	drop    string // webhookDrops reason counted when the job cannot be saved
+	flushed chan struct{}
+}
+
+// startDeadLetterWriter starts the writer on first use. It runs for the
+// life of the dispatcher, so jobs turned away after Stop are still saved.
+func (d *webhookDispatcher) startDeadLetterWriter() {
+	d.deadLetterOnce.Do(func() {
+		d.deadLetters = make(chan deadLetterRequest, deadLetterQueueSize)
+		go d.writeDeadLetters()
+	})
+}
+
+func (d *webhookDispatcher) writeDeadLetters() {
+	for req := range d.deadLetters {
+		if req.flushed != nil {
+			close(req.flushed)
+			continue
+		}
+		if !d.deadLetter(d.deadLetterStore(), req.job, 0, req.reason, req.cause) {
+			webhookDrops.With(req.drop).Inc()
+		}
+	}
+}
+
+// saveLater hands job to the writer without blocking. When the writer has
+// fallen behind the job is dropped, counted under drop, and false returned.
+func (d *webhookDispatcher) saveLater(job webhookJob, reason domain.DeadLetterReason, cause error, drop string) bool {
+	d.startDeadLetterWriter()
+	select {
+	case d.deadLetters <- deadLetterRequest{job: job, reason: reason, cause: cause, drop: drop}:
+		return true
+	default:
+		webhookDrops.With(drop).Inc()
+		logger.Warn("webhook dead-letter writer fell behind, dropping message",
+			"webhook", job.webhookID,
+			"reason", reason,
+			"logLevel", job.meta.LogLevel,
+			"timestamp", job.meta.Timestamp,
+			"capacity", deadLetterQueueSize)
+		return false
+	}
+}
+
+// flushDeadLetters waits until the writer has saved every job handed to it
+// so far.
+func (d *webhookDispatcher) flushDeadLetters() {
+	d.startDeadLetterWriter()
+	flushed := make(chan struct{})
+	d.deadLetters <- deadLetterRequest{flushed: flushed}
+	<-flushed
+}
@@ -0,0 +1,527 @@
+package tracer
+
+import (
+	"OmniView/internal/adapter/logger"
+	"OmniView/internal/core/domain"
+	"OmniView/internal/core/ports"
+	"OmniView/internal/service/webhook"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ==========================================
+// Webhook Dispatcher
+// ==========================================
+// Delivers webhook messages from a bounded queue on a small worker pool.
+// Failed sends are retried with exponential backoff; a delivery that cannot
+// be sent is written to the dead-letter store instead of being dropped, so it
+// survives a restart and can be replayed from the webhook settings.
+
+const (
+	// Webhook worker pool settings
+	webhookWorkers   = 4
+	webhookQueueSize = 100
+
+	// webhookStopGrace bounds how long Stop lets queued and in-flight
+	// deliveries run before the rest are saved for the next start.
+	webhookStopGrace = 5 * time.Second
+)
+
+// retryPolicy controls how often and how long a failed delivery is retried.
+type retryPolicy struct {
+	attempts int           // Sends per delivery, including the first
+	base     time.Duration // Wait before the first retry; doubled for each retry after it
+	max      time.Duration // Upper bound for any wait, including Retry-After
+}
+
+var defaultRetryPolicy = retryPolicy{attempts: 5, base: time.Second, max: time.Minute}
+
+// backoff returns the wait before the given retry, counting from 1. A
+// Retry-After from the endpoint is honoured up to max; otherwise the wait
+// doubles per retry and is jittered so that workers retrying the same
+// endpoint do not send in step.
+func (p retryPolicy) backoff(retry int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return min(retryAfter, p.max)
+	}
+	d := p.max
+	if shift := retry - 1; shift < 30 && p.base<<shift < p.max {
+		d = p.base << shift
+	}
+	half := d / 2
+	return half + rand.N(half+1)
+}
+
+// webhookSender sends one webhook request; *webhook.WebhookService in
+// production.
+type webhookSender interface {
+	SendToWebhook(ctx context.Context, body []byte, webhookURL string, auth domain.WebhookAuth) error
+}
+
+// webhookDispatcher handles bounded webhook delivery
+type webhookDispatcher struct {
+	service  webhookSender
+	policy   retryPolicy
+	store    ports.WebhookDeadLetterRepository // Nil until SetWebhookDeadLetterRepository; failed deliveries are then only logged
+	queue    chan webhookJob
+	ctx      context.Context // Cancelled when the stop grace period ends
+	cancel   context.CancelFunc
+	stopping chan struct{} // Closed by Stop so pending retries are saved instead of waited for
+	wg       sync.WaitGroup
+	stopped  bool
+	mu       sync.RWMutex
+	stopOnce sync.Once
+
+	batchMu       sync.Mutex
+	batches       map[string]*webhookBatch // Open batches by webhook ID
+	batchesClosed bool                     // Set by Stop; later messages are sent on their own
+
+	suppressMu     sync.Mutex
+	suppressions   map[string]*suppression // Open suppress windows by webhook ID and fingerprint
+	suppressClosed bool                    // Set by Stop; later repeats are sent
+
+	limitMu  sync.Mutex
+	limiters map[string]*tokenBucket // Request allowance by webhook URL
+
+	deadLetterOnce sync.Once
+	deadLetters    chan deadLetterRequest // Jobs Enqueue turned away, saved by the dead-letter writer
+}
+
+// webhookJob represents a single webhook delivery task
+type webhookJob struct {
+	webhookID string
+	payload   []byte // Message JSON, or digest JSON when meta.Digest is set; laid out in format when delivered
+	url       string
+	format    domain.WebhookFormat
+	template  string
+	auth      domain.WebhookAuth // Not saved with dead letters; replay takes it from the webhook's current config
+	limits    domain.WebhookLimits
+	meta      webhook.WebhookMetadata
+}
+
+// newWebhookDispatcher creates and starts a new webhookDispatcher with a worker pool
+func newWebhookDispatcher() *webhookDispatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &webhookDispatcher{
+		service:  webhook.NewWebhookService(),
+		policy:   defaultRetryPolicy,
+		queue:    make(chan webhookJob, webhookQueueSize),
+		ctx:      ctx,
+		cancel:   cancel,
+		stopping: make(chan struct{}),
+	}
+	// Start worker pool
+	for i := 0; i < webhookWorkers; i++ {
+		d.wg.Add(1)
+		go d.worker()
+	}
+	return d
+}
+
+// worker processes webhook jobs from the queue
+func (d *webhookDispatcher) worker() {
+	defer d.wg.Done()
+	for job := range d.queue {
+		d.deliver(job)
+	}
+}
+
+// deliver sends job, retrying temporary failures, and saves it as a dead
+// letter when it cannot be sent. A job its webhook's format cannot lay out
+// is saved as rejected without being sent. Every attempt first waits for
+// the rate limit of the job's URL, or is saved as rate limited when the URL
+// is already a full burst behind.
+func (d *webhookDispatcher) deliver(job webhookJob) {
+	body, err := webhook.Render(job.format, job.template, job.payload, job.meta)
+	if err != nil {
+		d.deadLetter(d.deadLetterStore(), job, 0, domain.DeadLetterRejected, err)
+		return
+	}
+
+	for attempt := 1; ; attempt++ {
+		if d.ctx.Err() != nil {
+			d.deadLetter(d.deadLetterStore(), job, attempt-1, domain.DeadLetterInterrupted, d.ctx.Err())
+			return
+		}
+
+		wait, ok := d.reserve(job.url, job.limits)
+		if !ok {
+			d.deadLetter(d.deadLetterStore(), job, attempt-1, domain.DeadLetterRateLimited, domain.ErrWebhookRateLimited)
+			return
+		}
+		if wait > 0 {
+			webhookRateLimited.Inc()
+			if !d.sleep(wait) {
+				d.deadLetter(d.deadLetterStore(), job, attempt-1, domain.DeadLetterInterrupted, domain.ErrWebhookDispatcherStopped)
+				return
+			}
+		}
+
+		start := time.Now()
+		err := d.service.SendToWebhook(d.ctx, body, job.url, job.auth)
+		webhookAttemptLatency.Observe(time.Since(start).Seconds())
+		if err == nil {
+			webhookDeliveries.With(webhookResultSuccess).Inc()
+			return
+		}
+		webhookDeliveries.With(webhookResultFailure).Inc()
+
+		switch {
+		case d.ctx.Err() != nil:
+			d.deadLetter(d.deadLetterStore(), job, attempt, domain.DeadLetterInterrupted, err)
+			return
+		case !webhook.IsRetryable(err):
+			d.deadLetter(d.deadLetterStore(), job, attempt, domain.DeadLetterRejected, err)
+			return
+		case attempt >= d.policy.attempts:
+			d.deadLetter(d.deadLetterStore(), job, attempt, domain.DeadLetterRetriesExhausted, err)
+			return
+		}
+
+		wait = d.policy.backoff(attempt, webhook.RetryAfter(err))
+		webhookRetries.Inc()
+		logger.Warn("webhook send failed, retrying",
+			"webhook", job.webhookID,
+			"attempt", attempt,
+			"retry_in", wait,
+			"error", err)
+
+		if !d.sleep(wait) {
+			d.deadLetter(d.deadLetterStore(), job, attempt, domain.DeadLetterInterrupted, err)
+			return
+		}
+	}
+}
+
+// sleep waits for wait and reports whether it did. It returns false as soon as
+// the dispatcher starts stopping, so the caller saves its job instead.
+func (d *webhookDispatcher) sleep(wait time.Duration) bool {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-d.stopping:
+		return false
+	case <-d.ctx.Done():
+		return false
+	}
+}
+
+// deadLetter saves job to store and reports whether it was saved. Without a
+// store, or when saving fails, the delivery is lost and logged as an error.
+func (d *webhookDispatcher) deadLetter(store ports.WebhookDeadLetterRepository, job webhookJob, attempts int, reason domain.DeadLetterReason, cause error) bool {
+	delivery := &domain.WebhookDelivery{
+		WebhookID: job.webhookID,
+		URL:       job.url,
+		Payload:   string(job.payload),
+		Digest:    job.meta.Digest,
+		LogLevel:  job.meta.LogLevel,
+		Timestamp: job.meta.Timestamp,
+		Attempts:  attempts,
+		Reason:    reason,
+	}
+	if cause != nil {
+		delivery.LastError = cause.Error()
+	}
+
+	if store != nil {
+		// The dispatcher context may already be cancelled at shutdown, which is
+		// exactly when pending deliveries must still reach the store.
+		err := store.SaveDeadLetter(context.Background(), delivery)
+		if err == nil {
+			webhookDeadLetters.With(string(reason)).Inc()
+			logger.Warn("webhook delivery saved as dead letter",
+				"webhook", job.webhookID,
+				"reason", reason,
+				"attempts", attempts,
+				"error", cause)
+			return true
+		}
+		logger.Error("failed to save webhook dead letter", "webhook", job.webhookID, "error", err)
+	}
+
+	logger.Error("webhook delivery lost",
+		"webhook", job.webhookID,
+		"url", job.url,
+		"logLevel", job.meta.LogLevel,
+		"timestamp", job.meta.Timestamp,
+		"reason", reason,
+		"error", cause)
+	return false
+}
+
+// deadLetterStore returns the store failed deliveries are saved to.
+func (d *webhookDispatcher) deadLetterStore() ports.WebhookDeadLetterRepository {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.store
+}
+
+func (d *webhookDispatcher) setDeadLetterStore(store ports.WebhookDeadLetterRepository) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.store = store
+}
+
+// Submit marshals msg and queues its delivery to webhookConfig. With batch
+// set, a message the webhook batches is held for its next digest.
+func (d *webhookDispatcher) Submit(msg *domain.QueueMessage, webhookConfig *domain.WebhookConfig, batch bool) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		logger.Error("failed to marshal message for webhook", "error", err)
+		return fmt.Errorf("marshal message for webhook: %w", err)
+	}
+
+	meta := webhook.WebhookMetadata{
+		WebhookID: webhookConfig.ID,
+		LogLevel:  string(msg.LogLevel()),
+		Timestamp: msg.Timestamp().Format(time.RFC3339),
+	}
+	job := webhookJob{
+		webhookID: webhookConfig.ID,
+		payload:   payload,
+		url:       webhookConfig.URL,
+		format:    webhookConfig.Format,
+		template:  webhookConfig.Template,
+		auth:      webhookConfig.Auth,
+		limits:    webhookConfig.Limits,
+		meta:      meta,
+	}
+	if batch && webhookConfig.Batching.Batches(msg.LogLevel()) {
+		return d.Batch(job, msg, webhookConfig.Batching)
+	}
+	return d.Enqueue(job)
+}
+
+// Enqueue adds a webhook job to the dispatcher's queue if not stopped. It
+// never blocks: a job that cannot be queued is handed to the dead-letter
+// writer, or dropped when there is no store; either way the returned error
+// reports why it was not queued.
+func (d *webhookDispatcher) Enqueue(job webhookJob) error {
+	return d.enqueue(job, true)
+}
+
+// enqueue queues job. When save is false a job that cannot be queued is
+// neither saved nor counted, which replay relies on to keep the original
+// dead letter instead of duplicating it.
+func (d *webhookDispatcher) enqueue(job webhookJob, save bool) error {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.stopped {
+		if !save {
+			return domain.ErrWebhookDispatcherStopped
+		}
+		if d.store == nil {
+			logger.Warn("webhook dispatcher stopped, dropping message",
+				"url", job.url,
+				"logLevel", job.meta.LogLevel,
+				"timestamp", job.meta.Timestamp,
+				"queue_len", len(d.queue),
+				"queue_cap", cap(d.queue),
+				"dispatcherStopped", d.stopped)
+			webhookDrops.With(webhookDropStopped).Inc()
+		} else if d.saveLater(job, domain.DeadLetterInterrupted, domain.ErrWebhookDispatcherStopped, webhookDropStopped) {
+			return fmt.Errorf("%w; saving it as a dead letter", domain.ErrWebhookDispatcherStopped)
+		}
+		return domain.ErrWebhookDispatcherStopped
+	}
+
+	select {
+	case d.queue <- job:
+		// Job queued successfully
+		return nil
+	default:
+		if !save {
+			return domain.ErrWebhookQueueFull
+		}
+		if d.store == nil {
+			// Queue full and nowhere to save it - drop the message
+			logger.Warn("webhook queue full, dropping message",
+				"url", job.url,
+				"logLevel", job.meta.LogLevel,
+				"timestamp", job.meta.Timestamp,
+				"queue_len", len(d.queue),
+				"queue_cap", cap(d.queue))
+			webhookDrops.With(webhookDropQueueFull).Inc()
+		} else if d.saveLater(job, domain.DeadLetterQueueFull, domain.ErrWebhookQueueFull, webhookDropQueueFull) {
+			return fmt.Errorf("%w; saving it as a dead letter", domain.ErrWebhookQueueFull)
+		}
+		return domain.ErrWebhookQueueFull
+	}
+}
+
+// Stop stops accepting new jobs and waits for the workers. Follow-ups of
+// suppressed repeats are queued first, then batched messages as digests.
+// Queued and in-flight deliveries get webhookStopGrace to finish; retries
+// waiting for their backoff or a rate limit, and anything still unsent when
+// the grace period ends, are saved as interrupted dead letters so the next
+// start sends them. Stop returns once the dead-letter writer has saved the
+// jobs handed to it.
+func (d *webhookDispatcher) Stop() {
+	d.stopOnce.Do(func() {
+		d.flushSuppressions()
+		d.flushBatches()
+
+		d.mu.Lock()
+		d.stopped = true
+		close(d.queue)
+		if d.stopping != nil {
+			close(d.stopping)
+		}
+		d.mu.Unlock()
+
+		if d.cancel != nil {
+			grace := time.AfterFunc(webhookStopGrace, d.cancel)
+			defer grace.Stop()
+			defer d.cancel()
+		}
+		d.wg.Wait()
+		d.flushDeadLetters()
+	})
+}
+
+// Global webhook dispatcher (initialized on first use)
+var globalWebhookDispatcher *webhookDispatcher
+var dispatcherOnce sync.Once
+
+// startedWebhookDispatcher publishes the global dispatcher to metric scrapes,
+// which may run on any goroutine.
+var startedWebhookDispatcher atomic.Pointer[webhookDispatcher]
+
+// webhookDeadLetterStore is handed to the global dispatcher when it starts.
+var (
+	webhookDeadLetterMu    sync.Mutex
+	webhookDeadLetterStore ports.WebhookDeadLetterRepository
+)
+
+func getWebhookDispatcher() *webhookDispatcher {
+	dispatcherOnce.Do(func() {
+		globalWebhookDispatcher = newWebhookDispatcher()
+		webhookDeadLetterMu.Lock()
+		globalWebhookDispatcher.store = webhookDeadLetterStore
+		webhookDeadLetterMu.Unlock()
+		startedWebhookDispatcher.Store(globalWebhookDispatcher)
+	})
+	return globalWebhookDispatcher
+}
+
+// StopWebhookDispatcher flushes any queued webhook deliveries and stops the global dispatcher if it was initialized.
+func StopWebhookDispatcher() {
+	if globalWebhookDispatcher != nil {
+		globalWebhookDispatcher.Stop()
+	}
+}
+
+// SetWebhookDeadLetterRepository sets where undeliverable webhook messages
+// are saved. Without one they are logged and lost.
+func SetWebhookDeadLetterRepository(store ports.WebhookDeadLetterRepository) {
+	webhookDeadLetterMu.Lock()
+	webhookDeadLetterStore = store
+	webhookDeadLetterMu.Unlock()
+	if d := startedWebhookDispatcher.Load(); d != nil {
+		d.setDeadLetterStore(store)
+	}
+}
+
+// ResumeWebhookDeliveries queues the deliveries interrupted by the last
+// shutdown again and removes them from the store. It returns how many were
+// queued; those that do not fit stay in the store for the next start.
+// Each is sent as its webhook in configs is now set up; see
+// ReplayWebhookDeadLetter.
+func ResumeWebhookDeliveries(ctx context.Context, configs ports.ConfigRepository) (int, error) {
+	webhookDeadLetterMu.Lock()
+	store := webhookDeadLetterStore
+	webhookDeadLetterMu.Unlock()
+	if store == nil {
+		return 0, nil
+	}
+
+	deliveries, err := store.ListDeadLetters(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("ResumeWebhookDeliveries: %w", err)
+	}
+	webhooks, err := listWebhooks(configs)
+	if err != nil {
+		return 0, fmt.Errorf("ResumeWebhookDeliveries: %w", err)
+	}
+	resumed := 0
+	for _, delivery := range deliveries {
+		if delivery.Reason != domain.DeadLetterInterrupted {
+			continue
+		}
+		if err := replay(ctx, store, webhooks, delivery); err != nil {
+			return resumed, fmt.Errorf("ResumeWebhookDeliveries: %w", err)
+		}
+		resumed++
+	}
+	return resumed, nil
+}
+
+// ReplayWebhookDeadLetter queues a saved delivery again and removes it from
+// the store. If it fails again it is saved as a new dead letter. When the
+// queue has no room the delivery stays in the store and
+// ErrWebhookQueueFull is returned.
+//
+// A delivery whose webhook is still in configs is sent to that webhook's
+// current URL, format and credentials, so fixing a wrong URL, a broken
+// template or an expired token and replaying works. Otherwise it goes to the
+// saved URL as the default JSON envelope without credentials.
+func ReplayWebhookDeadLetter(ctx context.Context, delivery domain.WebhookDelivery, configs ports.ConfigRepository) error {
+	webhookDeadLetterMu.Lock()
+	store := webhookDeadLetterStore
+	webhookDeadLetterMu.Unlock()
+	if store == nil {
+		return fmt.Errorf("ReplayWebhookDeadLetter: %w", domain.ErrNilRepository)
+	}
+	webhooks, err := listWebhooks(configs)
+	if err != nil {
+		return fmt.Errorf("ReplayWebhookDeadLetter: %w", err)
+	}
+	if err := replay(ctx, store, webhooks, delivery); err != nil {
+		return fmt.Errorf("ReplayWebhookDeadLetter: %w", err)
+	}
+	return nil
+}
+
+// listWebhooks returns the configured webhooks, or none when configs is nil.
+func listWebhooks(configs ports.ConfigRepository) ([]domain.WebhookConfig, error) {
+	if configs == nil {
+		return nil, nil
+	}
+	return configs.ListWebhookConfigs()
+}
+
+func replay(ctx context.Context, store ports.WebhookDeadLetterRepository, webhooks []domain.WebhookConfig, delivery domain.WebhookDelivery) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	job := webhookJob{
+		webhookID: delivery.WebhookID,
+		payload:   []byte(delivery.Payload),
+		url:       delivery.URL,
+		meta:      webhook.WebhookMetadata{WebhookID: delivery.WebhookID, LogLevel: delivery.LogLevel, Timestamp: delivery.Timestamp, Digest: delivery.Digest},
+	}
+	for _, config := range webhooks {
+		if config.ID == delivery.WebhookID {
+			job.url, job.auth = config.URL, config.Auth
+			job.format, job.template = config.Format, config.Template
+			job.limits = config.Limits
+			break
+		}
+	}
+	if err := getWebhookDispatcher().enqueue(job, false); err != nil {
+		return err
+	}
+	// The job is queued, so a failure to delete only risks a duplicate send
+	if err := store.DeleteDeadLetter(ctx, delivery.ID); err != nil && !errors.Is(err, domain.ErrDeadLetterNotFound) {
+		logger.Warn("failed to remove replayed webhook dead letter", "id", delivery.ID, "error", err)
+	}
+	return nil
+}
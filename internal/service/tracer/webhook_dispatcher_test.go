@@ -0,0 +1,378 @@
+package tracer
+
+import (
+	"OmniView/internal/core/domain"
+	"OmniView/internal/service/webhook"
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeSender returns the queued errors in order, then nil.
+type fakeSender struct {
+	mu     sync.Mutex
+	errs   []error
+	sends  int
+	bodies [][]byte      // Every body sent, in order
+	sent   chan struct{} // Signalled after every send when set
+}
+
+func (s *fakeSender) SendToWebhook(_ context.Context, body []byte, _ string, _ domain.WebhookAuth) error {
+	s.mu.Lock()
+	s.sends++
+	s.bodies = append(s.bodies, body)
+	var err error
+	if len(s.errs) > 0 {
+		err, s.errs = s.errs[0], s.errs[1:]
+	}
+	s.mu.Unlock()
+	if s.sent != nil {
+		s.sent <- struct{}{}
+	}
+	return err
+}
+
+func (s *fakeSender) sendCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sends
+}
+
+func (s *fakeSender) sentBodies() [][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([][]byte(nil), s.bodies...)
+}
+
+// memoryDeadLetters is an in-memory ports.WebhookDeadLetterRepository.
+type memoryDeadLetters struct {
+	mu        sync.Mutex
+	next      int
+	saved     []domain.WebhookDelivery
+	deleted   []string
+	saveError error
+}
+
+func (r *memoryDeadLetters) SaveDeadLetter(_ context.Context, delivery *domain.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.saveError != nil {
+		return r.saveError
+	}
+	r.next++
+	delivery.ID = string(rune('a' + r.next))
+	r.saved = append(r.saved, *delivery)
+	return nil
+}
+
+func (r *memoryDeadLetters) ListDeadLetters(context.Context) ([]domain.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]domain.WebhookDelivery(nil), r.saved...), nil
+}
+
+func (r *memoryDeadLetters) DeleteDeadLetter(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.saved {
+		if r.saved[i].ID == id {
+			r.saved = append(r.saved[:i], r.saved[i+1:]...)
+			r.deleted = append(r.deleted, id)
+			return nil
+		}
+	}
+	return domain.ErrDeadLetterNotFound
+}
+
+func (r *memoryDeadLetters) entries() []domain.WebhookDelivery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]domain.WebhookDelivery(nil), r.saved...)
+}
+
+// startTestDispatcher starts a one-worker dispatcher around sender and store.
+func startTestDispatcher(sender webhookSender, store *memoryDeadLetters, policy retryPolicy) *webhookDispatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &webhookDispatcher{
+		service:  sender,
+		policy:   policy,
+		store:    store,
+		queue:    make(chan webhookJob, 4),
+		ctx:      ctx,
+		cancel:   cancel,
+		stopping: make(chan struct{}),
+	}
+	d.wg.Add(1)
+	go d.worker()
+	return d
+}
+
+// waitFor polls cond until it holds or a second has passed.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the webhook delivery")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+var fastRetries = retryPolicy{attempts: 3, base: time.Millisecond, max: 2 * time.Millisecond}
+
+func testJob() webhookJob {
+	return webhookJob{
+		webhookID: "ops",
+		payload:   []byte(`{"message":"disk full"}`),
+		url:       "https://example.com/hook",
+		meta:      webhook.WebhookMetadata{LogLevel: "CRITICAL", Timestamp: "2026-01-02T03:04:05Z"},
+	}
+}
+
+func TestWebhookDispatcher_RetriesAndDeadLetters(t *testing.T) {
+	unavailable := &webhook.StatusError{StatusCode: http.StatusServiceUnavailable}
+	for _, tt := range []struct {
+		name       string
+		errs       []error
+		wantSends  int
+		wantReason domain.DeadLetterReason // Empty when the delivery should succeed
+	}{
+		{name: "succeeds after a retry", errs: []error{unavailable}, wantSends: 2},
+		{name: "temporary failures exhaust the retries", errs: []error{unavailable, unavailable, unavailable}, wantSends: 3, wantReason: domain.DeadLetterRetriesExhausted},
+		{name: "rejected status is not retried", errs: []error{&webhook.StatusError{StatusCode: http.StatusNotFound}}, wantSends: 1, wantReason: domain.DeadLetterRejected},
+		{name: "rejected URL is not retried", errs: []error{domain.ErrInvalidWebhookURL}, wantSends: 1, wantReason: domain.DeadLetterRejected},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := &fakeSender{errs: tt.errs}
+			store := &memoryDeadLetters{}
+			d := startTestDispatcher(sender, store, fastRetries)
+
+			if err := d.Enqueue(testJob()); err != nil {
+				t.Fatalf("Enqueue: %v", err)
+			}
+			// Stop cuts retries short, so let the delivery finish first
+			waitFor(t, func() bool {
+				return sender.sendCount() == tt.wantSends && (tt.wantReason == "" || len(store.entries()) > 0)
+			})
+			d.Stop()
+
+			if sends := sender.sendCount(); sends != tt.wantSends {
+				t.Errorf("sends = %d, want %d", sends, tt.wantSends)
+			}
+			saved := store.entries()
+			if tt.wantReason == "" {
+				if len(saved) != 0 {
+					t.Fatalf("expected no dead letters, got %+v", saved)
+				}
+				return
+			}
+			if len(saved) != 1 {
+				t.Fatalf("expected one dead letter, got %+v", saved)
+			}
+			got := saved[0]
+			if got.Reason != tt.wantReason || got.Attempts != tt.wantSends || got.LastError == "" {
+				t.Errorf("dead letter = %+v, want reason %s after %d attempts with the last error", got, tt.wantReason, tt.wantSends)
+			}
+			if got.WebhookID != "ops" || got.URL != "https://example.com/hook" || got.Payload != `{"message":"disk full"}` || got.LogLevel != "CRITICAL" {
+				t.Errorf("dead letter did not keep the delivery: %+v", got)
+			}
+		})
+	}
+}
+
+func TestWebhookDispatcher_UnrenderableFormatIsRejected(t *testing.T) {
+	sender := &fakeSender{}
+	store := &memoryDeadLetters{}
+	d := startTestDispatcher(sender, store, fastRetries)
+
+	job := testJob()
+	job.format = domain.WebhookFormatSlack // The test payload is not a trace message
+	if err := d.Enqueue(job); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	waitFor(t, func() bool { return len(store.entries()) > 0 })
+	d.Stop()
+
+	if sends := sender.sendCount(); sends != 0 {
+		t.Errorf("sends = %d, want none for a body that could not be rendered", sends)
+	}
+	if got := store.entries()[0]; got.Reason != domain.DeadLetterRejected || got.Attempts != 0 {
+		t.Errorf("dead letter = %+v, want rejected with no attempts", got)
+	}
+}
+
+func TestWebhookDispatcherStop_SavesPendingRetries(t *testing.T) {
+	sender := &fakeSender{
+		errs: []error{&webhook.StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Hour}},
+		sent: make(chan struct{}, 1),
+	}
+	store := &memoryDeadLetters{}
+	d := startTestDispatcher(sender, store, retryPolicy{attempts: 5, base: time.Hour, max: time.Hour})
+
+	if err := d.Enqueue(testJob()); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	<-sender.sent
+
+	stopped := make(chan struct{})
+	go func() {
+		d.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Stop waited for the retry backoff instead of saving the delivery")
+	}
+
+	saved := store.entries()
+	if len(saved) != 1 || saved[0].Reason != domain.DeadLetterInterrupted || saved[0].Attempts != 1 {
+		t.Fatalf("expected one interrupted dead letter after one attempt, got %+v", saved)
+	}
+}
+
+func TestWebhookDispatcherEnqueue_SavesWhatDoesNotFit(t *testing.T) {
+	store := &memoryDeadLetters{}
+	d := &webhookDispatcher{queue: make(chan webhookJob, 1), store: store}
+	fullBefore := webhookDrops.With(webhookDropQueueFull).Value()
+	savedBefore := webhookDeadLetters.With(string(domain.DeadLetterQueueFull)).Value()
+
+	if err := d.Enqueue(testJob()); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if err := d.Enqueue(testJob()); !errors.Is(err, domain.ErrWebhookQueueFull) {
+		t.Fatalf("Enqueue on a full queue error = %v, want ErrWebhookQueueFull", err)
+	}
+	d.stopped = true
+	if err := d.Enqueue(testJob()); !errors.Is(err, domain.ErrWebhookDispatcherStopped) {
+		t.Fatalf("Enqueue after Stop error = %v, want ErrWebhookDispatcherStopped", err)
+	}
+
+	d.flushDeadLetters()
+	saved := store.entries()
+	if len(saved) != 2 || saved[0].Reason != domain.DeadLetterQueueFull || saved[1].Reason != domain.DeadLetterInterrupted {
+		t.Fatalf("expected queue_full and interrupted dead letters, got %+v", saved)
+	}
+	if delta := webhookDrops.With(webhookDropQueueFull).Value() - fullBefore; delta != 0 {
+		t.Errorf("queue_full drops increased by %d for a saved delivery, want 0", delta)
+	}
+	if delta := webhookDeadLetters.With(string(domain.DeadLetterQueueFull)).Value() - savedBefore; delta != 1 {
+		t.Errorf("queue_full dead letters increased by %d, want 1", delta)
+	}
+}
+
+func TestWebhookDispatcherEnqueue_DoesNotWaitForTheStore(t *testing.T) {
+	t.Parallel()
+
+	store := &blockingDeadLetters{release: make(chan struct{})}
+	d := &webhookDispatcher{queue: make(chan webhookJob), store: store}
+
+	// Each call finds the queue full; the first occupies the writer, the
+	// next fill its buffer and the rest are dropped, all without blocking
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range deadLetterQueueSize + 5 {
+			_ = d.Enqueue(testJob())
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Enqueue waited for the dead-letter store")
+	}
+
+	close(store.release)
+	d.flushDeadLetters()
+	if saved := store.saved.Load(); saved < deadLetterQueueSize || saved > deadLetterQueueSize+1 {
+		t.Fatalf("saved %d dead letters, want the writer's buffer and the one in progress", saved)
+	}
+}
+
+// blockingDeadLetters holds every save until release is closed.
+type blockingDeadLetters struct {
+	memoryDeadLetters
+	release chan struct{}
+	saved   atomic.Int64
+}
+
+func (r *blockingDeadLetters) SaveDeadLetter(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	<-r.release
+	r.saved.Add(1)
+	return r.memoryDeadLetters.SaveDeadLetter(ctx, delivery)
+}
+
+// Not parallel: swaps the global webhook dispatcher and dead-letter store.
+func TestResumeAndReplayWebhookDeliveries(t *testing.T) {
+	previousDispatcher := globalWebhookDispatcher
+	t.Cleanup(func() {
+		globalWebhookDispatcher = previousDispatcher
+		dispatcherOnce = sync.Once{}
+		SetWebhookDeadLetterRepository(nil)
+	})
+
+	injectedDispatcher := &webhookDispatcher{queue: make(chan webhookJob, 2)}
+	globalWebhookDispatcher = injectedDispatcher
+	dispatcherOnce = sync.Once{}
+	dispatcherOnce.Do(func() {})
+
+	store := &memoryDeadLetters{}
+	for _, reason := range []domain.DeadLetterReason{domain.DeadLetterInterrupted, domain.DeadLetterRejected, domain.DeadLetterInterrupted} {
+		if err := store.SaveDeadLetter(context.Background(), &domain.WebhookDelivery{URL: "https://example.com/hook", Payload: "{}", Reason: reason}); err != nil {
+			t.Fatalf("SaveDeadLetter: %v", err)
+		}
+	}
+	SetWebhookDeadLetterRepository(store)
+
+	resumed, err := ResumeWebhookDeliveries(context.Background(), nil)
+	if err != nil || resumed != 2 {
+		t.Fatalf("ResumeWebhookDeliveries = %d, %v; want 2, nil", resumed, err)
+	}
+	remaining := store.entries()
+	if len(remaining) != 1 || remaining[0].Reason != domain.DeadLetterRejected {
+		t.Fatalf("expected only the rejected delivery to remain, got %+v", remaining)
+	}
+
+	// The queue is now full, so the replay must leave the entry in place
+	if err := ReplayWebhookDeadLetter(context.Background(), remaining[0], nil); !errors.Is(err, domain.ErrWebhookQueueFull) {
+		t.Fatalf("ReplayWebhookDeadLetter on a full queue error = %v, want ErrWebhookQueueFull", err)
+	}
+	if got := store.entries(); len(got) != 1 {
+		t.Fatalf("expected a failed replay to keep exactly one entry, got %+v", got)
+	}
+
+	<-injectedDispatcher.queue
+	if err := ReplayWebhookDeadLetter(context.Background(), remaining[0], nil); err != nil {
+		t.Fatalf("ReplayWebhookDeadLetter: %v", err)
+	}
+	if got := store.entries(); len(got) != 0 {
+		t.Fatalf("expected the replayed entry to be removed, got %+v", got)
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := retryPolicy{attempts: 5, base: time.Second, max: 10 * time.Second}
+	for _, tt := range []struct {
+		retry      int
+		retryAfter time.Duration
+		low, high  time.Duration
+	}{
+		{retry: 1, low: 500 * time.Millisecond, high: time.Second},
+		{retry: 3, low: 2 * time.Second, high: 4 * time.Second},
+		{retry: 10, low: 5 * time.Second, high: 10 * time.Second},
+		{retry: 100, low: 5 * time.Second, high: 10 * time.Second},
+		{retry: 1, retryAfter: 3 * time.Second, low: 3 * time.Second, high: 3 * time.Second},
+		{retry: 1, retryAfter: time.Hour, low: 10 * time.Second, high: 10 * time.Second},
+	} {
+		for range 20 {
+			if got := policy.backoff(tt.retry, tt.retryAfter); got < tt.low || got > tt.high {
+				t.Fatalf("backoff(%d, %s) = %s, want within [%s, %s]", tt.retry, tt.retryAfter, got, tt.low, tt.high)
+			}
+		}
+	}
+}
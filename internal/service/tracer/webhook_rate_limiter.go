@@ -0,0 +1,69 @@
+package tracer
+
+import (
+	"OmniView/internal/core/domain"
+	"time"
+)
+
+// ==========================================
+// Webhook Rate Limiting
+// ==========================================
+// Paces the requests sent to each webhook URL with a token bucket, so a
+// burst of deliveries, retries included, stays within what the endpoint
+// accepts. Chat providers revoke webhooks that exceed their limits; waiting
+// costs only latency. A request that would have to wait behind a full burst
+// is saved as a rate-limited dead letter instead, so a flood cannot keep
+// every worker asleep.
+
+// tokenBucket holds the request allowance of one endpoint. The balance goes
+// negative as workers reserve requests ahead of the rate, so each waits in
+// turn, but never below minus the burst: a flood would otherwise queue
+// sleeping workers without end.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// reserve takes one request from the bucket at now and returns how long the
+// caller must wait before sending it. When a full burst is already reserved
+// ahead of the rate it takes nothing and returns false.
+func (b *tokenBucket) reserve(limits domain.WebhookLimits, now time.Time) (time.Duration, bool) {
+	perSecond := float64(limits.RatePerMinute) / 60
+	burst := float64(limits.Burst)
+	if b.last.IsZero() {
+		b.tokens = burst
+	} else {
+		b.tokens = min(burst, b.tokens+now.Sub(b.last).Seconds()*perSecond)
+	}
+	b.last = now
+
+	if b.tokens-1 < -burst {
+		return 0, false
+	}
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0, true
+	}
+	return time.Duration(-b.tokens / perSecond * float64(time.Second)), true
+}
+
+// reserve takes a request from the bucket of url and returns how long to
+// wait before sending it, or false when the URL's allowance is a full burst
+// behind. Requests to a URL without a rate limit never wait. Webhooks
+// sharing a URL share its bucket, paced by the limits of the latest request.
+func (d *webhookDispatcher) reserve(url string, limits domain.WebhookLimits) (time.Duration, bool) {
+	if !limits.RateLimited() {
+		return 0, true
+	}
+	d.limitMu.Lock()
+	defer d.limitMu.Unlock()
+	if d.limiters == nil {
+		d.limiters = make(map[string]*tokenBucket)
+	}
+	b := d.limiters[url]
+	if b == nil {
+		b = &tokenBucket{}
+		d.limiters[url] = b
+	}
+	return b.reserve(limits, time.Now())
+}
@@ -0,0 +1,100 @@
+package tracer
+
+import (
+	"OmniView/internal/core/domain"
+	"testing"
+	"time"
+)
+
+func TestTokenBucket_PacesRequestsAfterBurst(t *testing.T) {
+	t.Parallel()
+
+	limits := domain.WebhookLimits{RatePerMinute: 60, Burst: 2}
+	var b tokenBucket
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for i, want := range []time.Duration{0, 0, time.Second, 2 * time.Second} {
+		if got, ok := b.reserve(limits, start); !ok || got != want {
+			t.Fatalf("reserve %d = %s, %t, want %s", i+1, got, ok, want)
+		}
+	}
+	// Five seconds repay the two requests taken ahead and refill the burst
+	for i, want := range []time.Duration{0, 0, time.Second} {
+		if got, ok := b.reserve(limits, start.Add(5*time.Second)); !ok || got != want {
+			t.Fatalf("reserve %d after refill = %s, %t, want %s", i+1, got, ok, want)
+		}
+	}
+}
+
+func TestTokenBucket_RefusesMoreThanABurstAhead(t *testing.T) {
+	t.Parallel()
+
+	limits := domain.WebhookLimits{RatePerMinute: 60, Burst: 2}
+	var b tokenBucket
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for range 4 {
+		if _, ok := b.reserve(limits, start); !ok {
+			t.Fatal("reserve refused a request within the burst of debt")
+		}
+	}
+	// A refused request takes nothing, so the next second repays one
+	for range 2 {
+		if wait, ok := b.reserve(limits, start); ok {
+			t.Fatalf("reserve with a full burst of debt = %s, want it refused", wait)
+		}
+	}
+	if wait, ok := b.reserve(limits, start.Add(time.Second)); !ok || wait != 2*time.Second {
+		t.Fatalf("reserve a second later = %s, %t, want 2s", wait, ok)
+	}
+}
+
+func TestWebhookDispatcher_RateLimitedJobIsSavedOnStop(t *testing.T) {
+	sender := &fakeSender{}
+	store := &memoryDeadLetters{}
+	d := startTestDispatcher(sender, store, fastRetries)
+
+	for range 2 {
+		job := testJob()
+		job.limits = domain.WebhookLimits{RatePerMinute: 1, Burst: 1}
+		if err := d.Enqueue(job); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+	waitFor(t, func() bool { return sender.sendCount() == 1 })
+	d.Stop()
+
+	if sends := sender.sendCount(); sends != 1 {
+		t.Fatalf("sends = %d, want the second request held by the rate limit", sends)
+	}
+	saved, _ := store.ListDeadLetters(t.Context())
+	if len(saved) != 1 || saved[0].Reason != domain.DeadLetterInterrupted || saved[0].Attempts != 0 {
+		t.Fatalf("dead letters = %+v, want the held request saved as interrupted", saved)
+	}
+}
+
+func TestWebhookDispatcher_JobTooFarBehindTheRateIsSavedAsRateLimited(t *testing.T) {
+	sender := &fakeSender{}
+	store := &memoryDeadLetters{}
+	d := startTestDispatcher(sender, store, fastRetries)
+	defer d.Stop()
+
+	job := testJob()
+	job.limits = domain.WebhookLimits{RatePerMinute: 1, Burst: 1}
+	// Use the burst and a burst of debt, so the job would wait over a minute
+	for range 2 {
+		if _, ok := d.reserve(job.url, job.limits); !ok {
+			t.Fatal("reserve refused a request within the burst of debt")
+		}
+	}
+	if err := d.Enqueue(job); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	waitFor(t, func() bool { return len(store.entries()) == 1 })
+
+	if sends := sender.sendCount(); sends != 0 {
+		t.Fatalf("sends = %d, want the job refused by the rate limit", sends)
+	}
+	saved, _ := store.ListDeadLetters(t.Context())
+	if len(saved) != 1 || saved[0].Reason != domain.DeadLetterRateLimited || saved[0].Attempts != 0 {
+		t.Fatalf("dead letters = %+v, want the job saved as rate limited", saved)
+	}
+}
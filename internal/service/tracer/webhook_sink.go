@@ -0,0 +1,119 @@
+package tracer
+
+import (
+	"OmniView/internal/adapter/logger"
+	"OmniView/internal/core/domain"
+	"OmniView/internal/core/ports"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// ==========================================
+// Webhook Sink
+// ==========================================
+// Delivers routed messages to webhooks as the "webhook" output sink, so the
+// routing rules decide which messages reach a webhook. The rule seeded with
+// a new store sends it the messages flagged by Trace_Message_To_Webhook.
+// Webhooks are looked up on the sink's goroutine, off the dequeue path, and
+// messages are handed to the global webhook dispatcher. Each sink keeps the
+// stored webhooks in memory until InvalidateWebhookConfigs is called, so a
+// message costs no BoltDB read or credential decryption.
+
+// webhookConfigsVersion counts the changes to the stored webhooks.
+var webhookConfigsVersion atomic.Uint64
+
+// InvalidateWebhookConfigs makes webhook sinks load the stored webhooks
+// again before their next message. Call it after changing them.
+func InvalidateWebhookConfigs() {
+	webhookConfigsVersion.Add(1)
+}
+
+// webhookSink sends each message to the webhook its target names, or the
+// default webhook, unless the sink's webhook setting names one.
+type webhookSink struct {
+	configs        ports.ConfigRepository
+	webhook        string
+	unknownTargets sync.Map // Webhook targets already warned about, so each is logged once
+
+	// Loaded by Write, which only the sink's goroutine calls
+	loaded         bool
+	version        uint64 // webhookConfigsVersion when the webhooks were loaded
+	webhooks       []domain.WebhookConfig
+	defaultWebhook *domain.WebhookConfig
+}
+
+// NewWebhookSinkFactory returns the ports.SinkFactory for "webhook" sinks,
+// which look webhooks up in configs. Settings: webhook, the ID of the
+// webhook every message is sent to.
+func NewWebhookSinkFactory(configs ports.ConfigRepository) ports.SinkFactory {
+	return func(cfg domain.SinkConfig) (ports.MessageSink, error) {
+		if configs == nil {
+			return nil, fmt.Errorf("%w: webhook sink: %w", domain.ErrInvalidSink, domain.ErrNilConfig)
+		}
+		for key := range cfg.Settings {
+			if key != "webhook" {
+				return nil, fmt.Errorf("%w: unknown webhook sink setting %q", domain.ErrInvalidSink, key)
+			}
+		}
+		return &webhookSink{configs: configs, webhook: strings.TrimSpace(cfg.Setting("webhook"))}, nil
+	}
+}
+
+// Write queues msg for its webhook when the webhook accepts the message's
+// level. Messages naming an unknown webhook are dropped with a warning.
+func (s *webhookSink) Write(_ context.Context, msg *domain.QueueMessage) error {
+	target := s.webhook
+	if target == "" {
+		target = msg.WebhookTarget()
+	}
+	webhookConfig, err := s.resolve(target)
+	if errors.Is(err, domain.ErrWebhookConfigNotFound) {
+		if target != "" {
+			webhookDrops.With(webhookDropUnknownTarget).Inc()
+			if _, warned := s.unknownTargets.LoadOrStore(strings.ToLower(target), true); !warned {
+				logger.Warn("message names an unknown webhook, dropping it", "target", target)
+			}
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !webhookConfig.Accepts(msg.LogLevel()) {
+		return nil
+	}
+	return enqueueWebhook(msg, webhookConfig, true)
+}
+
+// resolve returns the webhook named by target, or the default webhook when
+// target is empty, loading the stored webhooks when they have changed.
+func (s *webhookSink) resolve(target string) (*domain.WebhookConfig, error) {
+	if version := webhookConfigsVersion.Load(); !s.loaded || s.version != version {
+		webhooks, err := s.configs.ListWebhookConfigs()
+		if err != nil {
+			return nil, err
+		}
+		defaultWebhook, err := s.configs.GetWebhookConfig()
+		if err != nil && !errors.Is(err, domain.ErrWebhookConfigNotFound) {
+			return nil, err
+		}
+		s.webhooks, s.defaultWebhook = webhooks, defaultWebhook
+		s.loaded, s.version = true, version
+	}
+
+	if target != "" {
+		return matchWebhook(s.webhooks, target)
+	}
+	if s.defaultWebhook == nil {
+		return nil, domain.ErrWebhookConfigNotFound
+	}
+	webhookConfig := *s.defaultWebhook
+	return &webhookConfig, nil
+}
+
+// Close does nothing; queued deliveries belong to the dispatcher.
+func (s *webhookSink) Close() error { return nil }
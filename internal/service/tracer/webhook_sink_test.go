@@ -0,0 +1,163 @@
+package tracer
+
+import (
+	"OmniView/internal/core/domain"
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Not parallel: swaps the global webhook dispatcher and reads process-wide drop counts.
+func TestWebhookSink_SendsToTargetedWebhook(t *testing.T) {
+	previousDispatcher := globalWebhookDispatcher
+
+	t.Cleanup(func() {
+		globalWebhookDispatcher = previousDispatcher
+		dispatcherOnce = sync.Once{}
+	})
+
+	defaultConfig, err := domain.NewWebhookConfig(domain.DefaultWebhookID, "https://example.com/default", true)
+	if err != nil {
+		t.Fatalf("failed to create webhook config: %v", err)
+	}
+	ops, err := domain.NewWebhookConfig("ops", "https://example.com/ops", true)
+	if err != nil {
+		t.Fatalf("failed to create webhook config: %v", err)
+	}
+	if err := ops.SetMinLevel("WARNING"); err != nil {
+		t.Fatalf("SetMinLevel: %v", err)
+	}
+	paused, err := domain.NewWebhookConfig("paused", "https://example.com/paused", false)
+	if err != nil {
+		t.Fatalf("failed to create webhook config: %v", err)
+	}
+	open := NewWebhookSinkFactory(&webhookConfigRepository{config: defaultConfig, named: []domain.WebhookConfig{*ops, *paused}})
+
+	for _, tt := range []struct {
+		name     string
+		settings map[string]string
+		target   string
+		level    domain.LogLevel
+		wantURL  string // Empty when nothing should be queued
+	}{
+		{name: "untargeted goes to the default", level: domain.LogLevelInfo, wantURL: defaultConfig.URL},
+		{name: "target matched case-insensitively", target: "OPS", level: domain.LogLevelError, wantURL: ops.URL},
+		{name: "sink setting overrides the target", settings: map[string]string{"webhook": "ops"}, level: domain.LogLevelError, wantURL: ops.URL},
+		{name: "below the target's level", target: "ops", level: domain.LogLevelInfo},
+		{name: "disabled target", target: "paused", level: domain.LogLevelCritical},
+		{name: "unknown target", target: "missing", level: domain.LogLevelCritical},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			injectedDispatcher := &webhookDispatcher{queue: make(chan webhookJob, 1)}
+			globalWebhookDispatcher = injectedDispatcher
+			dispatcherOnce = sync.Once{}
+			// Mark sync.Once as used so getWebhookDispatcher keeps the injected dispatcher
+			// instead of replacing it with a real worker-backed dispatcher.
+			dispatcherOnce.Do(func() {})
+
+			sink, err := open(domain.SinkConfig{ID: "webhooks", Type: domain.WebhookSinkType, Settings: tt.settings, Enabled: true})
+			if err != nil {
+				t.Fatalf("open webhook sink: %v", err)
+			}
+			msg, err := domain.NewQueueMessage("message", "TEST_PROCESS", tt.level, "payload", time.Unix(1700000000, 0), true)
+			if err != nil {
+				t.Fatalf("failed to create queue message: %v", err)
+			}
+			msg.SetWebhookTarget(tt.target)
+
+			unknownBefore := webhookDrops.With(webhookDropUnknownTarget).Value()
+			if err := sink.Write(context.Background(), msg); err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+
+			if tt.wantURL == "" {
+				if queued := len(injectedDispatcher.queue); queued != 0 {
+					t.Fatalf("expected nothing to be queued, got %d jobs", queued)
+				}
+			} else if job := <-injectedDispatcher.queue; job.url != tt.wantURL {
+				t.Fatalf("queued for %q, want %q", job.url, tt.wantURL)
+			}
+
+			wantUnknown := uint64(0)
+			if tt.target == "missing" {
+				wantUnknown = 1
+			}
+			if delta := webhookDrops.With(webhookDropUnknownTarget).Value() - unknownBefore; delta != wantUnknown {
+				t.Fatalf("unknown_target drops increased by %d, want %d", delta, wantUnknown)
+			}
+		})
+	}
+}
+
+func TestNewWebhookSinkFactory_RejectsUnknownSettings(t *testing.T) {
+	t.Parallel()
+
+	open := NewWebhookSinkFactory(&webhookConfigRepository{})
+	_, err := open(domain.SinkConfig{ID: "webhooks", Type: domain.WebhookSinkType, Settings: map[string]string{"url": "https://example.com"}})
+	if !errors.Is(err, domain.ErrInvalidSink) {
+		t.Fatalf("open with an unknown setting error = %v, want ErrInvalidSink", err)
+	}
+	if _, err := NewWebhookSinkFactory(nil)(domain.SinkConfig{ID: "webhooks", Type: domain.WebhookSinkType}); !errors.Is(err, domain.ErrInvalidSink) {
+		t.Fatalf("open without a config repository error = %v, want ErrInvalidSink", err)
+	}
+}
+
+// countingConfigRepository counts how often the webhooks are listed.
+type countingConfigRepository struct {
+	webhookConfigRepository
+	lists atomic.Int32
+}
+
+func (r *countingConfigRepository) ListWebhookConfigs() ([]domain.WebhookConfig, error) {
+	r.lists.Add(1)
+	return r.webhookConfigRepository.ListWebhookConfigs()
+}
+
+// Not parallel: swaps the global webhook dispatcher and bumps the process-wide webhook version.
+func TestWebhookSink_CachesWebhooksUntilInvalidated(t *testing.T) {
+	previousDispatcher := globalWebhookDispatcher
+	t.Cleanup(func() {
+		globalWebhookDispatcher = previousDispatcher
+		dispatcherOnce = sync.Once{}
+	})
+	injectedDispatcher := &webhookDispatcher{queue: make(chan webhookJob, 4)}
+	globalWebhookDispatcher = injectedDispatcher
+	dispatcherOnce = sync.Once{}
+	dispatcherOnce.Do(func() {})
+
+	ops, err := domain.NewWebhookConfig("ops", "https://example.com/ops", true)
+	if err != nil {
+		t.Fatalf("failed to create webhook config: %v", err)
+	}
+	repo := &countingConfigRepository{webhookConfigRepository: webhookConfigRepository{named: []domain.WebhookConfig{*ops}}}
+	sink, err := NewWebhookSinkFactory(repo)(domain.SinkConfig{ID: "webhooks", Type: domain.WebhookSinkType, Enabled: true})
+	if err != nil {
+		t.Fatalf("open webhook sink: %v", err)
+	}
+	write := func() {
+		t.Helper()
+		msg, err := domain.NewQueueMessage("message", "TEST_PROCESS", domain.LogLevelInfo, "payload", time.Unix(1700000000, 0), true)
+		if err != nil {
+			t.Fatalf("failed to create queue message: %v", err)
+		}
+		msg.SetWebhookTarget("ops")
+		if err := sink.Write(context.Background(), msg); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+		<-injectedDispatcher.queue
+	}
+
+	write()
+	write()
+	if lists := repo.lists.Load(); lists != 1 {
+		t.Fatalf("webhooks listed %d times for two messages, want 1", lists)
+	}
+	InvalidateWebhookConfigs()
+	write()
+	if lists := repo.lists.Load(); lists != 2 {
+		t.Fatalf("webhooks listed %d times after invalidation, want 2", lists)
+	}
+}
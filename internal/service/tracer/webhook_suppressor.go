@@ -0,0 +1,127 @@
+package tracer
+
+import (
+	"OmniView/internal/adapter/logger"
+	"OmniView/internal/core/domain"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ==========================================
+// Webhook Suppression
+// ==========================================
+// Holds back repeats of a message that was just sent. The first message with
+// a fingerprint goes out and opens a suppress window; repeats within the
+// window are only counted. When the window ends a single follow-up reports
+// how many were held back, so a job retrying in a loop costs its webhook two
+// requests per window instead of hundreds.
+
+// maxWebhookSuppressions bounds the open windows. Messages with new
+// fingerprints are sent without opening one once it is reached.
+const maxWebhookSuppressions = 10000
+
+// suppression counts the repeats of one message for one webhook.
+type suppression struct {
+	window time.Duration
+	config domain.WebhookConfig // Config of the latest repeat; the follow-up is sent with it
+	last   *domain.QueueMessage // Latest repeat
+	count  int
+	timer  *time.Timer
+}
+
+// Suppress reports whether msg repeats a message config's webhook sent
+// within its suppress window. A repeat is counted for the window's
+// follow-up and must not be sent; any other message opens a window.
+func (d *webhookDispatcher) Suppress(msg *domain.QueueMessage, config domain.WebhookConfig) bool {
+	if !config.Limits.Suppresses() {
+		return false
+	}
+	key := config.ID + "\x00" + msg.Fingerprint()
+
+	d.suppressMu.Lock()
+	defer d.suppressMu.Unlock()
+	if d.suppressClosed {
+		return false
+	}
+	if s := d.suppressions[key]; s != nil {
+		s.config, s.last = config, msg
+		s.count++
+		webhookSuppressed.Inc()
+		return true
+	}
+	if len(d.suppressions) >= maxWebhookSuppressions {
+		return false
+	}
+	if d.suppressions == nil {
+		d.suppressions = make(map[string]*suppression)
+	}
+	s := &suppression{window: config.Limits.SuppressWindow}
+	s.timer = time.AfterFunc(s.window, func() { d.endSuppression(key, s) })
+	d.suppressions[key] = s
+	return false
+}
+
+// endSuppression closes s if it is still the open window for key and sends
+// its follow-up.
+func (d *webhookDispatcher) endSuppression(key string, s *suppression) {
+	d.suppressMu.Lock()
+	if d.suppressions[key] != s {
+		d.suppressMu.Unlock()
+		return
+	}
+	delete(d.suppressions, key)
+	d.suppressMu.Unlock()
+
+	d.sendFollowUp(s)
+}
+
+// flushSuppressions closes every window, sending the follow-ups of those
+// that held messages back, and stops suppressing. Stop calls it before
+// flushing batches, so follow-ups of batching webhooks join their digest.
+func (d *webhookDispatcher) flushSuppressions() {
+	d.suppressMu.Lock()
+	suppressions := d.suppressions
+	d.suppressions = nil
+	d.suppressClosed = true
+	d.suppressMu.Unlock()
+
+	for _, s := range suppressions {
+		s.timer.Stop()
+		d.sendFollowUp(s)
+	}
+}
+
+// sendFollowUp delivers the message reporting the repeats s held back. The
+// follow-up is not suppressed itself, but is batched like any message.
+func (d *webhookDispatcher) sendFollowUp(s *suppression) {
+	if s.count == 0 {
+		return
+	}
+	msg, err := s.followUp()
+	if err != nil {
+		logger.Error("failed to build webhook suppression follow-up", "webhook", s.config.ID, "suppressed", s.count, "error", err)
+		return
+	}
+	// Failures are logged, and saved as dead letters, by Submit
+	_ = d.Submit(msg, &s.config, true)
+}
+
+// followUp returns the latest repeat with its payload prefixed by how many
+// messages were held back. The count is also set as the suppressed_count
+// attribute, for templates and filters.
+func (s *suppression) followUp() (*domain.QueueMessage, error) {
+	payload := fmt.Sprintf("Suppressed %d similar messages in the last %s: %s", s.count, s.window, s.last.Payload())
+	msg, err := domain.NewQueueMessage(s.last.MessageID(), s.last.ProcessName(), s.last.LogLevel(), payload, s.last.Timestamp())
+	if err != nil {
+		return nil, err
+	}
+	attrs := s.last.Attributes()
+	if attrs == nil {
+		attrs = make(map[string]string, 1)
+	}
+	attrs["suppressed_count"] = strconv.Itoa(s.count)
+	msg.SetAttributes(attrs)
+	msg.SetWebhookTarget(s.last.WebhookTarget())
+	return msg, nil
+}
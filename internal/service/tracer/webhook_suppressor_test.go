@@ -0,0 +1,118 @@
+package tracer
+
+import (
+	"OmniView/internal/core/domain"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// suppressingWebhook returns the ops webhook with repeats suppressed for
+// window.
+func suppressingWebhook(window time.Duration) domain.WebhookConfig {
+	return domain.WebhookConfig{
+		ID:      "ops",
+		URL:     "https://example.com/hook",
+		Enabled: true,
+		Limits:  domain.WebhookLimits{SuppressWindow: window},
+	}
+}
+
+// offer sends msg through d as the tracer does: counted when it repeats a
+// recent message, submitted otherwise.
+func offer(t *testing.T, d *webhookDispatcher, msg *domain.QueueMessage, config domain.WebhookConfig) bool {
+	t.Helper()
+	if d.Suppress(msg, config) {
+		return true
+	}
+	if err := d.Submit(msg, &config, true); err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	return false
+}
+
+// sentMessage decodes the message carried by a request body in the default
+// format.
+func sentMessage(t *testing.T, body []byte) *domain.QueueMessage {
+	t.Helper()
+	envelope, _ := decodeDigest(t, body)
+	var msg domain.QueueMessage
+	if err := json.Unmarshal([]byte(envelope.Message), &msg); err != nil {
+		t.Fatalf("Unmarshal message: %v", err)
+	}
+	return &msg
+}
+
+func TestWebhookDispatcherSuppress_SendsFollowUpWithCount(t *testing.T) {
+	sender := &fakeSender{}
+	d := startTestDispatcher(sender, &memoryDeadLetters{}, fastRetries)
+	defer d.Stop()
+
+	config := suppressingWebhook(200 * time.Millisecond)
+	suppressed := 0
+	for i := range 10 {
+		_, msg := batchJob(t, domain.LogLevelError, fmt.Sprintf("ORA-00054: resource busy, order %d", 1000+i))
+		if offer(t, d, msg, config) {
+			suppressed++
+		}
+	}
+	// A different error is not a repeat
+	_, other := batchJob(t, domain.LogLevelError, "ORA-00060: deadlock detected")
+	if offer(t, d, other, config) {
+		t.Fatal("expected a different error code to be sent")
+	}
+	if suppressed != 9 {
+		t.Fatalf("suppressed = %d, want every repeat after the first", suppressed)
+	}
+	waitFor(t, func() bool { return sender.sendCount() == 3 })
+
+	followUp := sentMessage(t, sender.sentBodies()[2])
+	if !strings.HasPrefix(followUp.Payload(), "Suppressed 9 similar messages in the last 200ms: ORA-00054: resource busy, order 1009") {
+		t.Fatalf("follow-up payload = %q", followUp.Payload())
+	}
+	if count, _ := followUp.Attribute("suppressed_count"); count != "9" || followUp.LogLevel() != domain.LogLevelError {
+		t.Fatalf("follow-up = %s with suppressed_count %q", followUp, count)
+	}
+
+	// The window has ended, so the next repeat opens a new one
+	_, msg := batchJob(t, domain.LogLevelError, "ORA-00054: resource busy, order 2000")
+	if offer(t, d, msg, config) {
+		t.Fatal("expected the first repeat after the window to be sent")
+	}
+}
+
+func TestWebhookDispatcherSuppress_WindowWithoutRepeatsSendsNothingMore(t *testing.T) {
+	sender := &fakeSender{}
+	d := startTestDispatcher(sender, &memoryDeadLetters{}, fastRetries)
+	defer d.Stop()
+
+	_, msg := batchJob(t, domain.LogLevelError, "disk full")
+	offer(t, d, msg, suppressingWebhook(10*time.Millisecond))
+	waitFor(t, func() bool { return sender.sendCount() == 1 })
+	time.Sleep(50 * time.Millisecond)
+
+	if sends := sender.sendCount(); sends != 1 {
+		t.Fatalf("sends = %d, want no follow-up for a window without repeats", sends)
+	}
+}
+
+func TestWebhookDispatcherStop_SendsSuppressionFollowUps(t *testing.T) {
+	sender := &fakeSender{}
+	d := startTestDispatcher(sender, &memoryDeadLetters{}, fastRetries)
+
+	config := suppressingWebhook(time.Hour)
+	for range 3 {
+		_, msg := batchJob(t, domain.LogLevelError, "disk full")
+		offer(t, d, msg, config)
+	}
+	d.Stop()
+
+	if sends := sender.sendCount(); sends != 2 {
+		t.Fatalf("sends = %d, want the message and its follow-up", sends)
+	}
+	if count, _ := sentMessage(t, sender.sentBodies()[1]).Attribute("suppressed_count"); count != "2" {
+		t.Fatalf("suppressed_count = %q, want 2", count)
+	}
+}
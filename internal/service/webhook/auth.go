@@ -0,0 +1,127 @@
+package webhook
+
+import (
+	"OmniView/internal/core/domain"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// ==========================================
+// Request Authentication
+// ==========================================
+
+// Signature returns the value of the signature header for body sent at
+// timestamp (Unix seconds): "sha256=" and the hex HMAC-SHA256 of
+// "<timestamp>.<body>" keyed with secret. Receivers compute the same value
+// from the timestamp header and the raw body, compare it in constant time,
+// and reject old timestamps to stop replays.
+func Signature(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// setAuthHeaders adds the static headers, bearer token and signature that
+// auth asks for. The static headers go first so they cannot replace the
+// others.
+func setAuthHeaders(header http.Header, auth domain.WebhookAuth, body []byte, now time.Time) {
+	for name, value := range auth.Headers {
+		header.Set(name, value)
+	}
+	if auth.BearerToken != "" {
+		header.Set("Authorization", "Bearer "+auth.BearerToken)
+	}
+	if auth.SigningSecret != "" {
+		timestamp := now.Unix()
+		header.Set(domain.WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+		header.Set(domain.WebhookSignatureHeader, Signature(auth.SigningSecret, timestamp, body))
+	}
+}
+
+// LoadTLSConfig reads the client certificate and CA bundle named by auth. It
+// returns nil when auth uses neither, and errors wrapping
+// domain.ErrInvalidWebhookAuth when a file cannot be used.
+func LoadTLSConfig(auth domain.WebhookAuth) (*tls.Config, error) {
+	if !auth.UsesTLSFiles() {
+		return nil, nil
+	}
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if auth.ClientCert != "" {
+		cert, err := tls.LoadX509KeyPair(auth.ClientCert, auth.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("%w: client certificate: %v", domain.ErrInvalidWebhookAuth, err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	if auth.CABundle != "" {
+		pem, err := os.ReadFile(auth.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("%w: CA bundle: %v", domain.ErrInvalidWebhookAuth, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%w: CA bundle %s has no PEM certificates", domain.ErrInvalidWebhookAuth, auth.CABundle)
+		}
+		config.RootCAs = pool
+	}
+	return config, nil
+}
+
+// tlsClient is a client built from TLS files, with the modification times
+// the files had when they were read.
+type tlsClient struct {
+	client  *http.Client
+	modTime string
+}
+
+// clientFor returns the client for a webhook's requests. Webhooks without
+// TLS files share the default client; the others get one per set of files,
+// rebuilt when a file changes so renewed certificates are picked up.
+func (ws *WebhookService) clientFor(auth domain.WebhookAuth) (*http.Client, error) {
+	if !auth.UsesTLSFiles() {
+		return ws.client, nil
+	}
+
+	key := auth.ClientCert + "\x00" + auth.ClientKey + "\x00" + auth.CABundle
+	modTime := ""
+	for _, path := range []string{auth.ClientCert, auth.ClientKey, auth.CABundle} {
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", domain.ErrInvalidWebhookAuth, err)
+		}
+		modTime += info.ModTime().String() + "\x00"
+	}
+
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if cached, ok := ws.tlsClients[key]; ok && cached.modTime == modTime {
+		return cached.client, nil
+	}
+
+	config, err := LoadTLSConfig(auth)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = config
+	if cached, ok := ws.tlsClients[key]; ok {
+		cached.client.CloseIdleConnections()
+	}
+	client := newHTTPClient(transport)
+	ws.tlsClients[key] = tlsClient{client: client, modTime: modTime}
+	return client, nil
+}
@@ -0,0 +1,168 @@
+package webhook
+
+import (
+	"OmniView/internal/core/domain"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSignature_MatchesReceiverComputation(t *testing.T) {
+	t.Parallel()
+
+	// Expected value from: printf '1700000000.{"message":"hi"}' | openssl dgst -sha256 -hmac s3cret
+	got := Signature("s3cret", 1700000000, []byte(`{"message":"hi"}`))
+	if want := "sha256=a5d9aa2f81c9c4cea581e6454020d3bf169fa853b3bc0251b870b9616edad155"; got != want {
+		t.Fatalf("Signature() = %q, want %q", got, want)
+	}
+	if Signature("s3cret", 1700000001, []byte(`{"message":"hi"}`)) == got {
+		t.Fatal("expected the timestamp to change the signature")
+	}
+	if Signature("other", 1700000000, []byte(`{"message":"hi"}`)) == got {
+		t.Fatal("expected the secret to change the signature")
+	}
+}
+
+func TestSetAuthHeaders(t *testing.T) {
+	t.Parallel()
+
+	auth, err := domain.NewWebhookAuth("s3cret", "tok", "X-Api-Key=abc,X-Team=ops%20team", "", "", "")
+	if err != nil {
+		t.Fatalf("NewWebhookAuth: %v", err)
+	}
+	body := []byte(`{"message":"hi"}`)
+	header := http.Header{}
+	setAuthHeaders(header, auth, body, time.Unix(1700000000, 0))
+
+	for name, want := range map[string]string{
+		"Authorization":               "Bearer tok",
+		"X-Api-Key":                   "abc",
+		"X-Team":                      "ops team",
+		domain.WebhookTimestampHeader: "1700000000",
+		domain.WebhookSignatureHeader: Signature("s3cret", 1700000000, body),
+	} {
+		if got := header.Get(name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+
+	unsigned := http.Header{}
+	setAuthHeaders(unsigned, domain.WebhookAuth{}, body, time.Now())
+	if len(unsigned) != 0 {
+		t.Fatalf("expected no headers without auth, got %v", unsigned)
+	}
+}
+
+func TestLoadTLSConfig(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	certFile, keyFile := writeTestCertificate(t, dir)
+
+	config, err := LoadTLSConfig(domain.WebhookAuth{})
+	if err != nil || config != nil {
+		t.Fatalf("LoadTLSConfig without TLS files = %v, %v; want nil, nil", config, err)
+	}
+
+	config, err = LoadTLSConfig(domain.WebhookAuth{ClientCert: certFile, ClientKey: keyFile, CABundle: certFile})
+	if err != nil {
+		t.Fatalf("LoadTLSConfig: %v", err)
+	}
+	if len(config.Certificates) != 1 || config.RootCAs == nil {
+		t.Fatalf("expected a client certificate and a CA pool, got %+v", config)
+	}
+
+	notPEM := filepath.Join(dir, "empty.pem")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, auth := range []domain.WebhookAuth{
+		{ClientCert: filepath.Join(dir, "missing.pem"), ClientKey: keyFile},
+		{CABundle: filepath.Join(dir, "missing.pem")},
+		{CABundle: notPEM},
+	} {
+		if _, err := LoadTLSConfig(auth); !errors.Is(err, domain.ErrInvalidWebhookAuth) {
+			t.Errorf("LoadTLSConfig(%+v) error = %v, want ErrInvalidWebhookAuth", auth, err)
+		}
+	}
+}
+
+func TestClientFor_ReusesClientUntilFilesChange(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	certFile, keyFile := writeTestCertificate(t, dir)
+	ws := NewWebhookService()
+	auth := domain.WebhookAuth{ClientCert: certFile, ClientKey: keyFile}
+
+	if client, err := ws.clientFor(domain.WebhookAuth{BearerToken: "tok"}); err != nil || client != ws.client {
+		t.Fatalf("expected the shared client without TLS files, got %v, %v", client, err)
+	}
+	first, err := ws.clientFor(auth)
+	if err != nil {
+		t.Fatalf("clientFor: %v", err)
+	}
+	if first == ws.client {
+		t.Fatal("expected a separate client for mTLS")
+	}
+	if again, _ := ws.clientFor(auth); again != first {
+		t.Fatal("expected the mTLS client to be reused")
+	}
+
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(certFile, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if renewed, err := ws.clientFor(auth); err != nil || renewed == first {
+		t.Fatalf("expected a new client after the certificate changed, got %v", err)
+	}
+}
+
+// writeTestCertificate writes a self-signed certificate and its key as PEM
+// files in dir.
+func writeTestCertificate(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "omniview-test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile = filepath.Join(dir, "client.pem")
+	keyFile = filepath.Join(dir, "client-key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
@@ -0,0 +1,457 @@
+package webhook
+
+import (
+	"OmniView/internal/core/domain"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+	"unicode/utf8"
+)
+
+// ==========================================
+// Payload Formats
+// ==========================================
+// Lays a trace message out as the request body a webhook expects. The
+// OmniView JSON envelope is the default; chat services get their own card
+// layouts, and anything else can be produced with a Go text/template.
+
+// TemplateData is what a custom template is executed with.
+type TemplateData struct {
+	MessageID  string
+	Process    string
+	Level      string
+	Payload    string
+	Timestamp  time.Time
+	Mode       string            // "Global" for broadcast messages
+	Target     string            // Webhook named by Trace_Message_To_Webhook; empty for the default webhook
+	Attributes map[string]string // Structured fields; nil when there are none
+	JSON       string            // The whole message, or digest, as JSON
+
+	// Digest is set when the request summarises a batch. Level is then the
+	// most severe level in it, Process the process names, Payload the
+	// summary text and Timestamp the time of the last message.
+	Digest *domain.WebhookDigest
+}
+
+// templateFuncs are available to custom templates besides the text/template
+// built-ins.
+var templateFuncs = template.FuncMap{
+	// json encodes a value, so strings can be placed in JSON bodies safely
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"truncate": func(n int, s string) string { return truncate(s, n) },
+	"upper":    strings.ToUpper,
+	"lower":    strings.ToLower,
+}
+
+// templates caches each webhook's parsed template by webhook ID, so each
+// delivery does not parse it again. An edited template replaces the entry,
+// so the cache holds at most one template per webhook.
+var templates sync.Map // webhook ID -> *parsedTemplate
+
+// parsedTemplate is a cached template and the source it was parsed from.
+type parsedTemplate struct {
+	source string
+	tmpl   *template.Template
+}
+
+// ParseTemplate parses a custom template, reporting syntax errors with
+// domain.ErrInvalidWebhookFormat.
+func ParseTemplate(source string) (*template.Template, error) {
+	tmpl, err := template.New("webhook").Funcs(templateFuncs).Option("missingkey=zero").Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidWebhookFormat, err)
+	}
+	return tmpl, nil
+}
+
+// webhookTemplate returns source parsed, from the cache entry of webhookID
+// when it holds the same source. Without a webhook ID nothing is cached.
+func webhookTemplate(webhookID, source string) (*template.Template, error) {
+	if webhookID == "" {
+		return ParseTemplate(source)
+	}
+	if cached, ok := templates.Load(webhookID); ok && cached.(*parsedTemplate).source == source {
+		return cached.(*parsedTemplate).tmpl, nil
+	}
+	tmpl, err := ParseTemplate(source)
+	if err != nil {
+		return nil, err
+	}
+	templates.Store(webhookID, &parsedTemplate{source: source, tmpl: tmpl})
+	return tmpl, nil
+}
+
+// Render lays out message as the request body for format. message is the
+// JSON of a domain.QueueMessage, or of a domain.WebhookDigest when
+// meta.Digest is set. source is the template for
+// domain.WebhookFormatTemplate and ignored otherwise. Messages and templates
+// that cannot be rendered are reported with domain.ErrInvalidWebhookFormat.
+func Render(format domain.WebhookFormat, source string, message []byte, meta WebhookMetadata) ([]byte, error) {
+	if format == domain.WebhookFormatJSON {
+		return json.Marshal(WebhookPayload{
+			Message:   string(message),
+			LogLevel:  meta.LogLevel,
+			Timestamp: meta.Timestamp,
+			Digest:    meta.Digest,
+		})
+	}
+
+	var c card
+	var data TemplateData
+	if meta.Digest {
+		var digest domain.WebhookDigest
+		if err := json.Unmarshal(message, &digest); err != nil {
+			return nil, fmt.Errorf("%w: unreadable digest: %v", domain.ErrInvalidWebhookFormat, err)
+		}
+		c = digestCard(&digest)
+		data = TemplateData{
+			Process:   strings.Join(digest.Processes, ", "),
+			Level:     string(c.level),
+			Payload:   c.text,
+			Timestamp: digest.Last,
+			Digest:    &digest,
+		}
+	} else {
+		var msg domain.QueueMessage
+		if err := json.Unmarshal(message, &msg); err != nil {
+			return nil, fmt.Errorf("%w: unreadable message: %v", domain.ErrInvalidWebhookFormat, err)
+		}
+		c = messageCard(&msg)
+		data = TemplateData{
+			MessageID:  msg.MessageID(),
+			Process:    msg.ProcessName(),
+			Level:      string(msg.LogLevel()),
+			Payload:    msg.Payload(),
+			Timestamp:  msg.Timestamp(),
+			Mode:       msg.Mode(),
+			Target:     msg.WebhookTarget(),
+			Attributes: msg.Attributes(),
+		}
+	}
+
+	switch format {
+	case domain.WebhookFormatSlack:
+		return json.Marshal(slackBody(c))
+	case domain.WebhookFormatTeams:
+		return json.Marshal(adaptiveCardBody(c))
+	case domain.WebhookFormatMessageCard:
+		return json.Marshal(messageCardBody(c))
+	case domain.WebhookFormatDiscord:
+		return json.Marshal(discordBody(c))
+	case domain.WebhookFormatTemplate:
+		data.JSON = string(message)
+		return renderTemplate(meta.WebhookID, source, data)
+	default:
+		return nil, fmt.Errorf("%w: unknown format %q", domain.ErrInvalidWebhookFormat, format)
+	}
+}
+
+func renderTemplate(webhookID, source string, data TemplateData) ([]byte, error) {
+	tmpl, err := webhookTemplate(webhookID, source)
+	if err != nil {
+		return nil, err
+	}
+	var body bytes.Buffer
+	if err := tmpl.Execute(&body, data); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidWebhookFormat, err)
+	}
+	return body.Bytes(), nil
+}
+
+// contentType returns the Content-Type for a rendered body. Every built-in
+// format is JSON; a custom template may produce plain text.
+func contentType(body []byte) string {
+	if json.Valid(body) {
+		return "application/json"
+	}
+	return "text/plain; charset=utf-8"
+}
+
+// ==========================================
+// Chat Service Layouts
+// ==========================================
+
+// Length limits of the fields the payload is placed in
+const (
+	slackHeaderLimit   = 150
+	slackTextLimit     = 3000
+	discordTitleLimit  = 256
+	discordDescLimit   = 4096
+	discordFieldLimit  = 1024
+	discordFieldsLimit = 25
+)
+
+// card is what the chat layouts show: a title, the payload, and name/value
+// facts below it.
+type card struct {
+	title     string
+	text      string
+	level     domain.LogLevel
+	timestamp time.Time
+	facts     []fact
+}
+
+// fact is one name/value line shown under the payload.
+type fact struct {
+	Name  string
+	Value string
+}
+
+// messageCard shows one message under a title such as "ERROR from BILLING",
+// with its ID, time and attributes as facts. Attributes without a value are
+// left out, as Discord rejects empty fields.
+func messageCard(msg *domain.QueueMessage) card {
+	c := card{
+		title:     fmt.Sprintf("%s from %s", msg.LogLevel(), msg.ProcessName()),
+		text:      msg.Payload(),
+		level:     msg.LogLevel(),
+		timestamp: msg.Timestamp(),
+		facts: []fact{
+			{Name: "Message ID", Value: msg.MessageID()},
+			{Name: "Time", Value: msg.Timestamp().Format(time.RFC3339)},
+		},
+	}
+	for _, key := range msg.AttributeKeys() {
+		if value, _ := msg.Attribute(key); value != "" {
+			c.facts = append(c.facts, fact{Name: key, Value: value})
+		}
+	}
+	return c
+}
+
+// digestPayloadLimit bounds each payload line of a digest, so one long
+// payload cannot push the others out of the chat service's length limit.
+const digestPayloadLimit = 200
+
+// digestCard lists the most frequent payloads of a batch, one per line with
+// their count, and the level counts and time span as facts.
+func digestCard(d *domain.WebhookDigest) card {
+	from := fmt.Sprintf("%d processes", len(d.Processes))
+	if len(d.Processes) == 1 {
+		from = d.Processes[0]
+	}
+	var lines []string
+	for _, entry := range d.Top {
+		lines = append(lines, fmt.Sprintf("%d× [%s] %s: %s", entry.Count, entry.Level, entry.Process, truncate(entry.Payload, digestPayloadLimit)))
+	}
+	if others := d.Others(); others > 0 {
+		lines = append(lines, fmt.Sprintf("… and %d more", others))
+	}
+
+	var levels []string
+	for _, level := range slices.Backward(domain.LogLevels()) {
+		if n := d.Levels[level]; n > 0 {
+			levels = append(levels, fmt.Sprintf("%d %s", n, level))
+		}
+	}
+	c := card{
+		title:     fmt.Sprintf("%d messages from %s", d.Count, from),
+		text:      strings.Join(lines, "\n"),
+		level:     d.Highest(),
+		timestamp: d.Last,
+		facts: []fact{
+			{Name: "Levels", Value: strings.Join(levels, ", ")},
+			{Name: "First", Value: d.First.Format(time.RFC3339)},
+			{Name: "Last", Value: d.Last.Format(time.RFC3339)},
+		},
+	}
+	if len(d.Processes) > 1 {
+		c.facts = append(c.facts, fact{Name: "Processes", Value: strings.Join(d.Processes, ", ")})
+	}
+	return c
+}
+
+// levelColor returns the RGB colour cards use for level.
+func levelColor(level domain.LogLevel) int {
+	switch level {
+	case domain.LogLevelCritical:
+		return 0x8B0000
+	case domain.LogLevelError:
+		return 0xE01E5A
+	case domain.LogLevelWarning:
+		return 0xECB22E
+	case domain.LogLevelInfo:
+		return 0x2EB67D
+	default:
+		return 0x808080
+	}
+}
+
+func slackBody(c card) map[string]any {
+	var line strings.Builder
+	for i, f := range c.facts {
+		if i > 0 {
+			line.WriteString("  |  ")
+		}
+		fmt.Fprintf(&line, "*%s:* %s", f.Name, f.Value)
+	}
+	return map[string]any{
+		"text": truncate(c.title+": "+c.text, slackTextLimit),
+		"blocks": []any{
+			map[string]any{
+				"type": "header",
+				"text": map[string]any{"type": "plain_text", "text": truncate(c.title, slackHeaderLimit)},
+			},
+			map[string]any{
+				"type": "section",
+				"text": map[string]any{"type": "mrkdwn", "text": "```" + truncate(c.text, slackTextLimit-6) + "```"},
+			},
+			map[string]any{
+				"type":     "context",
+				"elements": []any{map[string]any{"type": "mrkdwn", "text": truncate(line.String(), slackTextLimit)}},
+			},
+		},
+	}
+}
+
+// adaptiveCardBody is the message shape accepted by Teams Workflows webhooks.
+func adaptiveCardBody(c card) map[string]any {
+	color := "Default"
+	switch {
+	case c.level.IsError():
+		color = "Attention"
+	case c.level == domain.LogLevelWarning:
+		color = "Warning"
+	}
+	var factSet []any
+	for _, f := range c.facts {
+		factSet = append(factSet, map[string]any{"title": f.Name, "value": f.Value})
+	}
+	return map[string]any{
+		"type": "message",
+		"attachments": []any{map[string]any{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content": map[string]any{
+				"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+				"type":    "AdaptiveCard",
+				"version": "1.4",
+				"body": []any{
+					map[string]any{"type": "TextBlock", "text": c.title, "weight": "Bolder", "size": "Medium", "color": color, "wrap": true},
+					map[string]any{"type": "TextBlock", "text": c.text, "fontType": "Monospace", "wrap": true},
+					map[string]any{"type": "FactSet", "facts": factSet},
+				},
+			},
+		}},
+	}
+}
+
+// messageCardBody is the legacy card accepted by Office 365 connectors.
+func messageCardBody(c card) map[string]any {
+	var factList []any
+	for _, f := range c.facts {
+		factList = append(factList, map[string]any{"name": f.Name, "value": f.Value})
+	}
+	return map[string]any{
+		"@type":      "MessageCard",
+		"@context":   "https://schema.org/extensions",
+		"themeColor": fmt.Sprintf("%06X", levelColor(c.level)),
+		"summary":    c.title,
+		"sections": []any{map[string]any{
+			"activityTitle": c.title,
+			"text":          c.text,
+			"facts":         factList,
+		}},
+	}
+}
+
+func discordBody(c card) map[string]any {
+	var fields []any
+	for _, f := range c.facts {
+		if len(fields) == discordFieldsLimit {
+			break
+		}
+		fields = append(fields, map[string]any{
+			"name":   truncate(f.Name, discordTitleLimit),
+			"value":  truncate(f.Value, discordFieldLimit),
+			"inline": true,
+		})
+	}
+	return map[string]any{
+		"embeds": []any{map[string]any{
+			"title":       truncate(c.title, discordTitleLimit),
+			"description": truncate(c.text, discordDescLimit),
+			"color":       levelColor(c.level),
+			"timestamp":   c.timestamp.Format(time.RFC3339),
+			"fields":      fields,
+			"footer":      map[string]any{"text": "OmniView"},
+		}},
+	}
+}
+
+// truncate shortens s to at most n characters, ending it with an ellipsis
+// when anything was cut.
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	if n <= 1 {
+		return string([]rune(s)[:max(n, 0)])
+	}
+	return string([]rune(s)[:n-1]) + "…"
+}
+
+// ==========================================
+// Preview
+// ==========================================
+
+// Preview renders a sample ERROR message in format, or a digest of a sample
+// batch when digest is set, for showing a webhook's layout before anything
+// is sent and for checking a template on save.
+func Preview(format domain.WebhookFormat, source string, digest bool) ([]byte, error) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	sample := func(id string, level domain.LogLevel, payload string, offset time.Duration) (*domain.QueueMessage, error) {
+		msg, err := domain.NewQueueMessage(id, "BILLING_BATCH", level, payload, at.Add(offset), true)
+		if err != nil {
+			return nil, err
+		}
+		msg.SetAttributes(map[string]string{"order_id": "A-1001", "tenant": "acme"})
+		return msg, nil
+	}
+
+	deadlock := "Invoice run failed: ORA-00060 deadlock detected while waiting for resource"
+	if !digest {
+		msg, err := sample("42", domain.LogLevelError, deadlock, 0)
+		if err != nil {
+			return nil, err
+		}
+		message, err := json.Marshal(msg)
+		if err != nil {
+			return nil, err
+		}
+		return Render(format, source, message, WebhookMetadata{
+			LogLevel:  string(msg.LogLevel()),
+			Timestamp: msg.Timestamp().Format(time.RFC3339),
+		})
+	}
+
+	var batch domain.WebhookDigest
+	for i := range 12 {
+		level, payload := domain.LogLevelError, deadlock
+		if i%3 == 2 {
+			level, payload = domain.LogLevelWarning, "Retrying invoice run in 30s"
+		}
+		msg, err := sample(fmt.Sprint(i+1), level, payload, time.Duration(i)*5*time.Second)
+		if err != nil {
+			return nil, err
+		}
+		batch.Add(msg)
+	}
+	batch.Finish()
+	message, err := json.Marshal(&batch)
+	if err != nil {
+		return nil, err
+	}
+	return Render(format, source, message, WebhookMetadata{
+		LogLevel:  string(batch.Highest()),
+		Timestamp: batch.Last.Format(time.RFC3339),
+		Digest:    true,
+	})
+}
@@ -0,0 +1,148 @@
+package webhook
+
+import (
+	"OmniView/internal/core/domain"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRender_Formats(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		format domain.WebhookFormat
+		want   []string // Fragments the body must contain
+	}{
+		{domain.WebhookFormatJSON, []string{`"message":"{`, `"log_level":"ERROR"`, `"timestamp":"2026-01-02T03:04:05Z"`}},
+		{domain.WebhookFormatSlack, []string{`"type":"header"`, `"text":"ERROR from BILLING_BATCH"`, "ORA-00060", `*order_id:* A-1001`}},
+		{domain.WebhookFormatTeams, []string{`"contentType":"application/vnd.microsoft.card.adaptive"`, `"color":"Attention"`, `"title":"tenant","value":"acme"`}},
+		{domain.WebhookFormatMessageCard, []string{`"@type":"MessageCard"`, `"themeColor":"E01E5A"`, `"name":"Message ID","value":"42"`}},
+		{domain.WebhookFormatDiscord, []string{`"embeds":[`, `"color":14687834`, `"timestamp":"2026-01-02T03:04:05Z"`}},
+	} {
+		t.Run(tt.format.Label(), func(t *testing.T) {
+			t.Parallel()
+
+			body, err := Preview(tt.format, "", false)
+			if err != nil {
+				t.Fatalf("Preview: %v", err)
+			}
+			if !json.Valid(body) {
+				t.Fatalf("body is not JSON: %s", body)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(body), want) {
+					t.Errorf("body does not contain %s:\n%s", want, body)
+				}
+			}
+		})
+	}
+}
+
+func TestRender_Template(t *testing.T) {
+	t.Parallel()
+
+	body, err := Preview(domain.WebhookFormatTemplate,
+		`{"text": {{printf "%s %s: %s" (lower .Level) .Process (truncate 12 .Payload) | json}}, "order": {{json (index .Attributes "order_id")}}, "at": "{{.Timestamp.Format "15:04"}}"}`, false)
+	if err != nil {
+		t.Fatalf("Preview: %v", err)
+	}
+	want := `{"text": "error BILLING_BATCH: Invoice run…", "order": "A-1001", "at": "03:04"}`
+	if string(body) != want {
+		t.Errorf("body = %s, want %s", body, want)
+	}
+	if got := contentType(body); got != "application/json" {
+		t.Errorf("contentType = %q, want application/json", got)
+	}
+
+	text, err := Preview(domain.WebhookFormatTemplate, "{{.Level}} {{.MessageID}}", false)
+	if err != nil {
+		t.Fatalf("Preview: %v", err)
+	}
+	if string(text) != "ERROR 42" || !strings.HasPrefix(contentType(text), "text/plain") {
+		t.Errorf("plain text template gave %q sent as %q", text, contentType(text))
+	}
+}
+
+func TestRender_CachesOneTemplatePerWebhook(t *testing.T) {
+	t.Parallel()
+
+	const webhookID = "cache-test"
+	message := []byte(`{"message_id":"7","process_name":"P","log_level":"INFO","payload":"hi","timestamp":1700000000}`)
+	meta := WebhookMetadata{WebhookID: webhookID}
+	for _, tt := range []struct{ source, want string }{
+		{"{{.Level}}", "INFO"},
+		{"{{.Level}}", "INFO"},
+		{"{{.MessageID}}", "7"}, // Edited template replaces the entry
+	} {
+		body, err := Render(domain.WebhookFormatTemplate, tt.source, message, meta)
+		if err != nil {
+			t.Fatalf("Render %s: %v", tt.source, err)
+		}
+		if string(body) != tt.want {
+			t.Fatalf("Render %s = %q, want %q", tt.source, body, tt.want)
+		}
+		cached, ok := templates.Load(webhookID)
+		if !ok || cached.(*parsedTemplate).source != tt.source {
+			t.Fatalf("cache for %s = %v, want the entry for %s", webhookID, cached, tt.source)
+		}
+	}
+
+	// Renders without a webhook, as the preview does, are not cached
+	before := 0
+	templates.Range(func(any, any) bool { before++; return true })
+	if _, err := Preview(domain.WebhookFormatTemplate, "{{.Payload}} uncached", false); err != nil {
+		t.Fatalf("Preview: %v", err)
+	}
+	after := 0
+	templates.Range(func(any, any) bool { after++; return true })
+	if after != before {
+		t.Fatalf("preview grew the cache from %d to %d entries", before, after)
+	}
+}
+
+func TestRender_Errors(t *testing.T) {
+	t.Parallel()
+
+	for name, render := range map[string]func() ([]byte, error){
+		"template syntax": func() ([]byte, error) { return Preview(domain.WebhookFormatTemplate, "{{.Level", false) },
+		"unknown field":   func() ([]byte, error) { return Preview(domain.WebhookFormatTemplate, "{{.Nope}}", false) },
+		"unknown format":  func() ([]byte, error) { return Preview("pager", "", false) },
+		"unreadable input": func() ([]byte, error) {
+			return Render(domain.WebhookFormatSlack, "", []byte("not json"), WebhookMetadata{})
+		},
+	} {
+		if _, err := render(); !errors.Is(err, domain.ErrInvalidWebhookFormat) || IsRetryable(err) {
+			t.Errorf("%s: error = %v, want a non-retryable ErrInvalidWebhookFormat", name, err)
+		}
+	}
+}
+
+func TestRender_Digest(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		format domain.WebhookFormat
+		want   []string
+	}{
+		{domain.WebhookFormatJSON, []string{`"digest":true`, `\"count\":12`, `"log_level":"ERROR"`}},
+		{domain.WebhookFormatSlack, []string{`"text":"12 messages from BILLING_BATCH"`, `8× [ERROR] BILLING_BATCH: Invoice run failed`, `*Levels:* 8 ERROR, 4 WARNING`}},
+		{domain.WebhookFormatDiscord, []string{`"title":"12 messages from BILLING_BATCH"`, `"timestamp":"2026-01-02T03:05:00Z"`}},
+	} {
+		body, err := Preview(tt.format, "", true)
+		if err != nil {
+			t.Fatalf("%s: Preview: %v", tt.format.Label(), err)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(string(body), want) {
+				t.Errorf("%s: body does not contain %s:\n%s", tt.format.Label(), want, body)
+			}
+		}
+	}
+
+	body, err := Preview(domain.WebhookFormatTemplate, `{{if .Digest}}{{.Digest.Count}} x {{.Level}}{{else}}one{{end}}`, true)
+	if err != nil || string(body) != "12 x ERROR" {
+		t.Fatalf("digest template = %q, %v; want 12 x ERROR", body, err)
+	}
+}
@@ -1,13 +1,17 @@
 package webhook
 
 import (
+	"OmniView/internal/core/domain"
 	"bytes"
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -71,111 +75,199 @@ func isHostnameBlocked(host string) bool {
 	return false
 }
 
-// WebhookPayload represents the JSON envelope for webhook messages
+// WebhookPayload represents the JSON envelope for webhook messages in the
+// default format
 type WebhookPayload struct {
 	Message   string `json:"message"`
 	LogLevel  string `json:"log_level,omitempty"`
 	Timestamp string `json:"timestamp,omitempty"`
+	Digest    bool   `json:"digest,omitempty"` // Message is a domain.WebhookDigest rather than one trace message
 }
 
 // WebhookMetadata holds optional metadata for webhook messages
 type WebhookMetadata struct {
+	WebhookID string // Keys the parsed-template cache; templates are parsed every time without it
 	LogLevel  string
 	Timestamp string
+	Digest    bool // The payload is a domain.WebhookDigest summarising a batch
 }
 
 // WebhookService handles sending webhook notifications
 type WebhookService struct {
 	client *http.Client
+
+	mu         sync.Mutex
+	tlsClients map[string]tlsClient // Clients for webhooks with mTLS or a CA bundle, by file paths
 }
 
 // NewWebhookService creates a new WebhookService
 func NewWebhookService() *WebhookService {
 	return &WebhookService{
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-			// Disable redirects to prevent bypassing SSRF protection
-			// If a redirect occurs, the request will fail with ErrUseLastResponse
-			CheckRedirect: func(req *http.Request, via []*http.Request) error {
-				return http.ErrUseLastResponse
-			},
-		},
+		client:     newHTTPClient(nil),
+		tlsClients: make(map[string]tlsClient),
 	}
 }
 
-// SendToWebhook sends a payload to the specified webhook URL as a JSON envelope
-func (ws *WebhookService) SendToWebhook(payload []byte, webhookURL string, meta ...WebhookMetadata) error {
-	if webhookURL == "" {
-		return fmt.Errorf("webhook URL cannot be empty")
+// newHTTPClient creates the client webhook requests are sent with. A nil
+// transport uses http.DefaultTransport.
+func newHTTPClient(transport http.RoundTripper) *http.Client {
+	return &http.Client{
+		Transport: transport,
+		Timeout:   30 * time.Second,
+		// Disable redirects to prevent bypassing SSRF protection
+		// If a redirect occurs, the request will fail with ErrUseLastResponse
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
 	}
+}
 
-	parsedURL, err := url.Parse(webhookURL)
-	if err != nil {
-		return fmt.Errorf("invalid webhook URL: %w", err)
-	}
+// StatusError reports a webhook response outside the 2xx range.
+type StatusError struct {
+	StatusCode int
+	RetryAfter time.Duration // From the Retry-After header of a 429 or 503 response; zero when absent
+}
 
-	scheme := strings.ToLower(parsedURL.Scheme)
-	if scheme != "http" && scheme != "https" {
-		return fmt.Errorf("webhook URL must use http or https scheme")
-	}
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("webhook returned non-success status: %d", e.StatusCode)
+}
 
-	// Security: validate host is not localhost or reserved IP
-	host := parsedURL.Hostname()
-	if host == "" {
-		return fmt.Errorf("webhook URL must have a valid host")
+// Temporary reports whether the endpoint may accept the same request later:
+// timeouts, rate limiting and server errors are temporary, other statuses are not.
+func (e *StatusError) Temporary() bool {
+	switch {
+	case e.StatusCode == http.StatusRequestTimeout, e.StatusCode == http.StatusTooEarly, e.StatusCode == http.StatusTooManyRequests:
+		return true
+	default:
+		return e.StatusCode >= 500
 	}
+}
 
-	// Block localhost aliases
-	if strings.ToLower(host) == "localhost" || host == "0.0.0.0" || host == "::" {
-		return fmt.Errorf("webhook URL cannot point to localhost")
+// IsRetryable reports whether a failed SendToWebhook may succeed if sent
+// again. Network errors and temporary statuses are retryable; rejected URLs,
+// unreadable certificates, unrenderable formats, other statuses and
+// cancelled sends are not.
+func IsRetryable(err error) bool {
+	var status *StatusError
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, domain.ErrInvalidWebhookURL), errors.Is(err, domain.ErrInvalidWebhookAuth),
+		errors.Is(err, domain.ErrInvalidWebhookFormat), errors.Is(err, context.Canceled):
+		return false
+	case errors.As(err, &status):
+		return status.Temporary()
+	default:
+		return true
 	}
+}
 
-	// Block known metadata endpoints
-	if isHostnameBlocked(host) {
-		return fmt.Errorf("webhook URL cannot point to cloud metadata endpoints")
+// RetryAfter returns the delay the endpoint asked for with Retry-After, or zero.
+func RetryAfter(err error) time.Duration {
+	var status *StatusError
+	if errors.As(err, &status) {
+		return status.RetryAfter
 	}
+	return 0
+}
 
-	// Resolve and validate IP addresses
-	ips, err := net.LookupIP(host)
-	if err != nil {
-		return fmt.Errorf("failed to resolve webhook hostname: %w", err)
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
 	}
-	for _, ip := range ips {
-		if isReservedIP(ip) {
-			return fmt.Errorf("webhook URL cannot point to reserved/private IP address: %s", ip.String())
-		}
-	}
-
-	// Wrap payload in JSON envelope with optional metadata
-	envelope := WebhookPayload{
-		Message: string(payload),
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
 	}
-	if len(meta) > 0 {
-		envelope.LogLevel = meta[0].LogLevel
-		envelope.Timestamp = meta[0].Timestamp
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0)
 	}
+	return 0
+}
 
-	jsonBody, err := json.Marshal(envelope)
+// SendToWebhook posts body, as laid out by Render, to the specified webhook
+// URL, authenticated as auth describes. URLs that fail validation are
+// reported with domain.ErrInvalidWebhookURL, unreadable TLS files with
+// domain.ErrInvalidWebhookAuth and non-2xx responses with a *StatusError.
+func (ws *WebhookService) SendToWebhook(ctx context.Context, body []byte, webhookURL string, auth domain.WebhookAuth) error {
+	parsedURL, err := validateWebhookURL(ctx, webhookURL)
 	if err != nil {
-		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+		return err
+	}
+	client, err := ws.clientFor(auth)
+	if err != nil {
+		return err
 	}
 
-	req, err := http.NewRequest("POST", parsedURL.String(), bytes.NewBuffer(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", parsedURL.String(), bytes.NewReader(body))
 	if err != nil {
 		return fmt.Errorf("failed to create webhook request: %w", err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	setAuthHeaders(req.Header, auth, body, time.Now())
+	req.Header.Set("Content-Type", contentType(body))
 
-	resp, err := ws.client.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to send webhook request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("webhook returned non-success status: %d", resp.StatusCode)
+		statusErr := &StatusError{StatusCode: resp.StatusCode}
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+			statusErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		}
+		return statusErr
 	}
 
 	return nil
 }
+
+// validateWebhookURL parses webhookURL and rejects schemes and hosts that
+// could reach the local machine or internal networks.
+func validateWebhookURL(ctx context.Context, webhookURL string) (*url.URL, error) {
+	if webhookURL == "" {
+		return nil, fmt.Errorf("%w: URL cannot be empty", domain.ErrInvalidWebhookURL)
+	}
+
+	parsedURL, err := url.Parse(webhookURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidWebhookURL, err)
+	}
+
+	scheme := strings.ToLower(parsedURL.Scheme)
+	if scheme != "http" && scheme != "https" {
+		return nil, fmt.Errorf("%w: URL must use http or https scheme", domain.ErrInvalidWebhookURL)
+	}
+
+	// Security: validate host is not localhost or reserved IP
+	host := parsedURL.Hostname()
+	if host == "" {
+		return nil, fmt.Errorf("%w: URL must have a valid host", domain.ErrInvalidWebhookURL)
+	}
+
+	// Block localhost aliases
+	if strings.ToLower(host) == "localhost" || host == "0.0.0.0" || host == "::" {
+		return nil, fmt.Errorf("%w: URL cannot point to localhost", domain.ErrInvalidWebhookURL)
+	}
+
+	// Block known metadata endpoints
+	if isHostnameBlocked(host) {
+		return nil, fmt.Errorf("%w: URL cannot point to cloud metadata endpoints", domain.ErrInvalidWebhookURL)
+	}
+
+	// Resolve and validate IP addresses. A failed lookup may be temporary, so
+	// it is not reported as an invalid URL.
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve webhook hostname: %w", err)
+	}
+	for _, addr := range addrs {
+		if isReservedIP(addr.IP) {
+			return nil, fmt.Errorf("%w: URL cannot point to reserved/private IP address: %s", domain.ErrInvalidWebhookURL, addr.IP.String())
+		}
+	}
+	return parsedURL, nil
+}
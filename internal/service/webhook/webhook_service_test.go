@@ -0,0 +1,65 @@
+package webhook
+
+import (
+	"OmniView/internal/core/domain"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestIsRetryable(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "network error", err: errors.New("connection reset by peer"), want: true},
+		{name: "server error", err: &StatusError{StatusCode: http.StatusBadGateway}, want: true},
+		{name: "rate limited", err: &StatusError{StatusCode: http.StatusTooManyRequests}, want: true},
+		{name: "request timeout", err: fmt.Errorf("send: %w", &StatusError{StatusCode: http.StatusRequestTimeout}), want: true},
+		{name: "not found", err: &StatusError{StatusCode: http.StatusNotFound}, want: false},
+		{name: "unauthorized", err: &StatusError{StatusCode: http.StatusUnauthorized}, want: false},
+		{name: "blocked URL", err: fmt.Errorf("%w: loopback", domain.ErrInvalidWebhookURL), want: false},
+		{name: "unreadable certificate", err: fmt.Errorf("%w: client certificate", domain.ErrInvalidWebhookAuth), want: false},
+		{name: "cancelled", err: context.Canceled, want: false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Fatalf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, tt := range []struct {
+		value string
+		want  time.Duration
+	}{
+		{value: "", want: 0},
+		{value: "120", want: 2 * time.Minute},
+		{value: "-5", want: 0},
+		{value: "soon", want: 0},
+		{value: now.Add(30 * time.Second).Format(http.TimeFormat), want: 30 * time.Second},
+		{value: now.Add(-time.Minute).Format(http.TimeFormat), want: 0},
+	} {
+		if got := parseRetryAfter(tt.value, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestSendToWebhook_RejectsBlockedURLs(t *testing.T) {
+	ws := NewWebhookService()
+	for _, url := range []string{"ftp://example.com/hook", "http://127.0.0.1/hook", "http://169.254.169.254/latest"} {
+		err := ws.SendToWebhook(context.Background(), []byte("{}"), url, domain.WebhookAuth{})
+		if !errors.Is(err, domain.ErrInvalidWebhookURL) || IsRetryable(err) {
+			t.Errorf("SendToWebhook(%q) error = %v, want a non-retryable ErrInvalidWebhookURL", url, err)
+		}
+	}
+}